@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/infrastructure/github"
+)
+
+// repositoryChecker はアクセス確認（--check）に使う GitHub クライアントの機能。
+type repositoryChecker interface {
+	GetRepository(ctx context.Context, repo domain.Repository) (*github.RepositoryAccess, error)
+}
+
+// runCheck は分析せずに、各リポジトリへ1回ずつリクエストしてトークンと到達性を確認する（--check）。
+// 到達できたかを HTTP ステータス付きで1行ずつ表示し、1件でも到達できなければエラーを返す。
+// 1件の失敗で止めず、すべてのリポジトリを確認してから結果をまとめる。
+func runCheck(ctx context.Context, w io.Writer, client repositoryChecker, repos []domain.Repository) error {
+	fmt.Fprintf(w, "Checking access to %d repositories...\n\n", len(repos))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	unreachable := 0
+	for _, repo := range repos {
+		access, err := client.GetRepository(ctx, repo)
+		switch {
+		case err != nil:
+			unreachable++
+			fmt.Fprintf(tw, "  NG\t%s\t%v\n", repo.FullName(), err)
+		case !access.Reachable():
+			unreachable++
+			fmt.Fprintf(tw, "  NG\t%s\t%s\n", repo.FullName(), access.Status)
+		default:
+			visibility := "public"
+			if access.Private {
+				visibility = "private"
+			}
+			fmt.Fprintf(tw, "  OK\t%s\t%s (%s, default branch: %s)\n", repo.FullName(), access.Status, visibility, access.DefaultBranch)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(w)
+
+	if unreachable > 0 {
+		return fmt.Errorf("%d of %d repositories are unreachable", unreachable, len(repos))
+	}
+	fmt.Fprintln(w, "All repositories are reachable.")
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/infrastructure/github"
+)
+
+// fakeChecker はリポジトリ名ごとに決めた結果を返す repositoryChecker。
+type fakeChecker struct {
+	access map[string]*github.RepositoryAccess
+	errs   map[string]error
+	calls  []string
+}
+
+func (f *fakeChecker) GetRepository(_ context.Context, repo domain.Repository) (*github.RepositoryAccess, error) {
+	f.calls = append(f.calls, repo.FullName())
+	if err := f.errs[repo.FullName()]; err != nil {
+		return nil, err
+	}
+	return f.access[repo.FullName()], nil
+}
+
+func TestRunCheck(t *testing.T) {
+	ok := &github.RepositoryAccess{StatusCode: http.StatusOK, Status: "200 OK", DefaultBranch: "main"}
+	repos := []domain.Repository{domain.NewRepository("org", "a"), domain.NewRepository("org", "b"), domain.NewRepository("org", "c")}
+
+	t.Run("all reachable", func(t *testing.T) {
+		client := &fakeChecker{access: map[string]*github.RepositoryAccess{"org/a": ok, "org/b": ok, "org/c": ok}}
+		var out bytes.Buffer
+		if err := runCheck(context.Background(), &out, client, repos); err != nil {
+			t.Fatalf("runCheck() error = %v", err)
+		}
+		if got := strings.Count(out.String(), "  OK  "); got != 3 {
+			t.Errorf("OK lines = %d, want 3\n%s", got, out.String())
+		}
+	})
+
+	t.Run("unreachable repositories are reported and all are checked", func(t *testing.T) {
+		client := &fakeChecker{
+			access: map[string]*github.RepositoryAccess{
+				"org/a": {StatusCode: http.StatusNotFound, Status: "404 Not Found"},
+				"org/c": ok,
+			},
+			errs: map[string]error{"org/b": errors.New("connection refused")},
+		}
+		var out bytes.Buffer
+		err := runCheck(context.Background(), &out, client, repos)
+		if err == nil || !strings.Contains(err.Error(), "2 of 3") {
+			t.Errorf("runCheck() error = %v, want 2 of 3 unreachable", err)
+		}
+		if len(client.calls) != 3 {
+			t.Errorf("checked %v, want all 3 repositories", client.calls)
+		}
+		for _, want := range []string{"404 Not Found", "connection refused", "default branch: main"} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("output does not contain %q:\n%s", want, out.String())
+			}
+		}
+	})
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/features/analyze"
+)
+
+// fileConfig は --config で指定する設定ファイル（JSON）の内容。
+//
+// CLI フラグで指定するには長すぎる・チームで共有したい設定を置く。
+//
+//	{
+//	  "categoryWeights": {"velocity": 2, "quality": 2, "tech_debt": 1, "health": 0.5},
+//	  "severityPenalties": {"high": 20, "low": 0},
+//	  "doraRatingBands": {"deployFrequency": {"elite": 20}, "mttrHours": {"high": 48}},
+//	  "failureLabels": ["type:defect", "sev1"],
+//	  "branchPrefixes": {"feature": ["story/"], "refactor": ["task/", "chore/"]},
+//	  "nonSourceExtensions": [".png", ".lock", "-lock.json", ".min.js", ".generated.go"],
+//	  "coAuthorshipMitigatesOwnership": true,
+//	  "testFilePatterns": ["*_test.go", "*.spec.ts", "tests/"]
+//	}
+type fileConfig struct {
+	// 総合スコアのカテゴリ別の重み（指定のないカテゴリは 1）
+	CategoryWeights map[domain.Category]float64 `json:"categoryWeights"`
+
+	// カテゴリスコアでリスク1件ごとに引く点数（重大度別。省略した重大度はデフォルトのまま）
+	SeverityPenalties *severityPenaltyConfig `json:"severityPenalties"`
+
+	// DORA レーティング（Elite/High/Medium/Low）の境界。省略したメトリクス・レーティングはデフォルトのまま
+	DORARatingBands *doraRatingBandsConfig `json:"doraRatingBands"`
+
+	// 障害とみなす Issue ラベル（変更失敗率・MTTR。省略時は bug / incident / hotfix）
+	FailureLabels []string `json:"failureLabels"`
+
+	// PRの種類（投資比率・バグ修正割合）を判定するブランチ名の接頭辞。
+	// 指定した種類はデフォルトを置き換え、省略した種類はデフォルトのまま
+	BranchPrefixes *branchPrefixConfig `json:"branchPrefixes"`
+
+	// 巨大ファイルリスクの対象外にするファイル名の末尾（拡張子など）。
+	// 指定するとデフォルトを置き換える（デフォルトに足すときはデフォルトも並べる）
+	NonSourceExtensions []string `json:"nonSourceExtensions"`
+
+	// 共同作成（Co-authored-by）のコミットが多い期間は属人化リスクの重大度を下げる（省略時は下げない）
+	CoAuthorshipMitigatesOwnership bool `json:"coAuthorshipMitigatesOwnership"`
+
+	// テストファイルとみなすパスのパターン（ファイル名のグロブか、"/" で終わるディレクトリ）。
+	// 指定するとデフォルトを置き換える（デフォルトに足すときはデフォルトも並べる）
+	TestFilePatterns []string `json:"testFilePatterns"`
+}
+
+// severityPenaltyConfig は重大度ごとの減点。
+type severityPenaltyConfig struct {
+	High   *int `json:"high"`
+	Medium *int `json:"medium"`
+	Low    *int `json:"low"`
+}
+
+// severityPenalties は設定ファイルの減点をデフォルトに重ねて返す（指定がなければ nil）。
+func (fc *fileConfig) severityPenalties() *analyze.SeverityPenalties {
+	if fc.SeverityPenalties == nil {
+		return nil
+	}
+	p := analyze.DefaultSeverityPenalties
+	for _, v := range []struct {
+		src *int
+		dst *int
+	}{
+		{fc.SeverityPenalties.High, &p.High},
+		{fc.SeverityPenalties.Medium, &p.Medium},
+		{fc.SeverityPenalties.Low, &p.Low},
+	} {
+		if v.src != nil {
+			*v.dst = *v.src
+		}
+	}
+	return &p
+}
+
+// doraRatingBandsConfig は DORA メトリクスごとのレーティングの境界。
+type doraRatingBandsConfig struct {
+	DeployFrequency   *doraBandsConfig `json:"deployFrequency"`   // 回/月（境界値以上）
+	ChangeFailureRate *doraBandsConfig `json:"changeFailureRate"` // %（境界値以下）
+	MTTRHours         *doraBandsConfig `json:"mttrHours"`         // 時間（境界値未満）
+}
+
+// doraBandsConfig は1つのメトリクスのレーティングごとの境界値。
+type doraBandsConfig struct {
+	Elite  *float64 `json:"elite"`
+	High   *float64 `json:"high"`
+	Medium *float64 `json:"medium"`
+}
+
+// doraRatingBands は設定ファイルの境界をデフォルトに重ねて返す（指定がなければ nil）。
+func (fc *fileConfig) doraRatingBands() *domain.DORARatingBands {
+	if fc.DORARatingBands == nil {
+		return nil
+	}
+	b := domain.DefaultDORARatingBands
+	for _, m := range []struct {
+		src *doraBandsConfig
+		dst *domain.DORABands
+	}{
+		{fc.DORARatingBands.DeployFrequency, &b.DeployFrequency},
+		{fc.DORARatingBands.ChangeFailureRate, &b.ChangeFailureRate},
+		{fc.DORARatingBands.MTTRHours, &b.MTTR},
+	} {
+		if m.src == nil {
+			continue
+		}
+		for _, v := range []struct {
+			src *float64
+			dst *float64
+		}{
+			{m.src.Elite, &m.dst.Elite},
+			{m.src.High, &m.dst.High},
+			{m.src.Medium, &m.dst.Medium},
+		} {
+			if v.src != nil {
+				*v.dst = *v.src
+			}
+		}
+	}
+	return &b
+}
+
+// branchPrefixConfig は PR の種類ごとのブランチ名の接頭辞。
+type branchPrefixConfig struct {
+	Feature  []string `json:"feature"`
+	BugFix   []string `json:"bugfix"`
+	Refactor []string `json:"refactor"`
+}
+
+// branchPrefixes は設定ファイルの接頭辞を analyze.BranchPrefixes にする（指定がなければゼロ値）。
+func (fc *fileConfig) branchPrefixes() analyze.BranchPrefixes {
+	if fc.BranchPrefixes == nil {
+		return analyze.BranchPrefixes{}
+	}
+	return analyze.BranchPrefixes(*fc.BranchPrefixes)
+}
+
+// loadConfigFile は設定ファイルを読み込んで検証する。
+// 綴りの誤りに気付けるよう、未知のキーはエラーにする。
+func loadConfigFile(path string) (*fileConfig, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer f.Close()
+
+	var fc fileConfig
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&fc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	if fc.CategoryWeights != nil {
+		weights, err := analyze.NormalizeCategoryWeights(fc.CategoryWeights)
+		if err != nil {
+			return nil, fmt.Errorf("invalid categoryWeights in %s: %w", path, err)
+		}
+		fc.CategoryWeights = weights
+	}
+
+	if p := fc.severityPenalties(); p != nil {
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid severityPenalties in %s: %w", path, err)
+		}
+	}
+
+	if b := fc.doraRatingBands(); b != nil {
+		if err := b.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid doraRatingBands in %s: %w", path, err)
+		}
+	}
+
+	if fc.FailureLabels != nil {
+		labels, err := normalizeFailureLabels(fc.FailureLabels)
+		if err != nil {
+			return nil, fmt.Errorf("invalid failureLabels in %s: %w", path, err)
+		}
+		fc.FailureLabels = labels
+	}
+
+	if fc.BranchPrefixes != nil {
+		prefixes, err := analyze.NormalizeBranchPrefixes(fc.branchPrefixes())
+		if err != nil {
+			return nil, fmt.Errorf("invalid branchPrefixes in %s: %w", path, err)
+		}
+		fc.BranchPrefixes = (*branchPrefixConfig)(&prefixes)
+	}
+
+	if fc.NonSourceExtensions != nil {
+		exts, err := normalizeNonSourceExtensions(fc.NonSourceExtensions)
+		if err != nil {
+			return nil, fmt.Errorf("invalid nonSourceExtensions in %s: %w", path, err)
+		}
+		fc.NonSourceExtensions = exts
+	}
+
+	if fc.TestFilePatterns != nil {
+		patterns, err := normalizeTestFilePatterns(fc.TestFilePatterns)
+		if err != nil {
+			return nil, fmt.Errorf("invalid testFilePatterns in %s: %w", path, err)
+		}
+		fc.TestFilePatterns = patterns
+	}
+
+	return &fc, nil
+}
+
+// normalizeFailureLabels は前後の空白を除き、空のラベルや空のリストをエラーにする。
+func normalizeFailureLabels(labels []string) ([]string, error) {
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("at least one label is required")
+	}
+	normalized := make([]string, len(labels))
+	for i, l := range labels {
+		normalized[i] = strings.TrimSpace(l)
+		if normalized[i] == "" {
+			return nil, fmt.Errorf("label at index %d is empty", i)
+		}
+	}
+	return normalized, nil
+}
+
+// normalizeNonSourceExtensions は前後の空白を除いて小文字にそろえ、空の要素や空のリストをエラーにする。
+func normalizeNonSourceExtensions(exts []string) ([]string, error) {
+	if len(exts) == 0 {
+		return nil, fmt.Errorf("at least one extension is required")
+	}
+	normalized := make([]string, len(exts))
+	for i, e := range exts {
+		normalized[i] = strings.ToLower(strings.TrimSpace(e))
+		if normalized[i] == "" {
+			return nil, fmt.Errorf("extension at index %d is empty", i)
+		}
+	}
+	return normalized, nil
+}
+
+// normalizeTestFilePatterns は前後の空白を除いて小文字にそろえ、空の要素・空のリスト・不正なグロブをエラーにする。
+func normalizeTestFilePatterns(patterns []string) ([]string, error) {
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("at least one pattern is required")
+	}
+	normalized := make([]string, len(patterns))
+	for i, p := range patterns {
+		normalized[i] = strings.ToLower(strings.TrimSpace(p))
+		if normalized[i] == "" {
+			return nil, fmt.Errorf("pattern at index %d is empty", i)
+		}
+	}
+	if err := analyze.ValidateTestFilePatterns(normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
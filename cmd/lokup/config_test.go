@@ -0,0 +1,247 @@
+package main
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/features/analyze"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "lokup.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantWeights map[domain.Category]float64
+		wantLabels  []string
+		wantPrefix  *analyze.BranchPrefixes
+		wantExts    []string
+		wantPenalty *analyze.SeverityPenalties
+		wantBands   *domain.DORARatingBands
+		wantCoAuth  bool
+		wantTests   []string
+		wantErr     bool
+	}{
+		{
+			name:        "empty object",
+			content:     `{}`,
+			wantWeights: nil,
+		},
+		{
+			name:    "category weights are normalized",
+			content: `{"categoryWeights": {"velocity": 2, "quality": 2, "tech_debt": 1, "health": 0}}`,
+			wantWeights: map[domain.Category]float64{
+				domain.CategoryVelocity: 0.4,
+				domain.CategoryQuality:  0.4,
+				domain.CategoryTechDebt: 0.2,
+				domain.CategoryHealth:   0,
+			},
+		},
+		{
+			name:    "unknown category",
+			content: `{"categoryWeights": {"speed": 1}}`,
+			wantErr: true,
+		},
+		{
+			name:    "weights sum to zero",
+			content: `{"categoryWeights": {"velocity": 0, "quality": 0, "tech_debt": 0, "health": 0}}`,
+			wantErr: true,
+		},
+		{
+			name:        "severity penalties override only the given severities",
+			content:     `{"severityPenalties": {"high": 20, "low": 0}}`,
+			wantPenalty: &analyze.SeverityPenalties{High: 20, Medium: analyze.DefaultSeverityPenalties.Medium, Low: 0},
+		},
+		{
+			name:    "negative severity penalty",
+			content: `{"severityPenalties": {"medium": -5}}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown severity",
+			content: `{"severityPenalties": {"critical": 30}}`,
+			wantErr: true,
+		},
+		{
+			name:    "DORA rating bands override only the given values",
+			content: `{"doraRatingBands": {"deployFrequency": {"elite": 20}, "mttrHours": {"high": 48, "medium": 336}}}`,
+			wantBands: &domain.DORARatingBands{
+				DeployFrequency:   domain.DORABands{Elite: 20, High: 4, Medium: 1},
+				ChangeFailureRate: domain.DefaultDORARatingBands.ChangeFailureRate,
+				MTTR:              domain.DORABands{Elite: 1, High: 48, Medium: 336},
+			},
+		},
+		{
+			name:    "DORA rating bands out of order",
+			content: `{"doraRatingBands": {"changeFailureRate": {"elite": 40}}}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown DORA rating",
+			content: `{"doraRatingBands": {"mttrHours": {"low": 500}}}`,
+			wantErr: true,
+		},
+		{
+			name:       "failure labels are trimmed",
+			content:    `{"failureLabels": [" type:defect ", "sev1"]}`,
+			wantLabels: []string{"type:defect", "sev1"},
+		},
+		{
+			name:    "empty failure labels",
+			content: `{"failureLabels": []}`,
+			wantErr: true,
+		},
+		{
+			name:    "blank failure label",
+			content: `{"failureLabels": ["bug", "  "]}`,
+			wantErr: true,
+		},
+		{
+			name:    "branch prefixes replace only the given kinds",
+			content: `{"branchPrefixes": {"feature": ["Story/"], "refactor": ["task/", "chore/"]}}`,
+			wantPrefix: &analyze.BranchPrefixes{
+				Feature:  []string{"story/"},
+				BugFix:   analyze.DefaultBranchPrefixes.BugFix,
+				Refactor: []string{"task/", "chore/"},
+			},
+		},
+		{
+			name:    "branch prefix under two kinds",
+			content: `{"branchPrefixes": {"feature": ["task/"], "refactor": ["task/"]}}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown branch prefix kind",
+			content: `{"branchPrefixes": {"story": ["story/"]}}`,
+			wantErr: true,
+		},
+		{
+			name:     "non-source extensions are trimmed and lowercased",
+			content:  `{"nonSourceExtensions": [" .PNG", "-lock.json"]}`,
+			wantExts: []string{".png", "-lock.json"},
+		},
+		{
+			name:    "empty non-source extensions",
+			content: `{"nonSourceExtensions": []}`,
+			wantErr: true,
+		},
+		{
+			name:    "blank non-source extension",
+			content: `{"nonSourceExtensions": [".png", " "]}`,
+			wantErr: true,
+		},
+		{
+			name:       "co-authorship mitigation",
+			content:    `{"coAuthorshipMitigatesOwnership": true}`,
+			wantCoAuth: true,
+		},
+		{
+			name:      "test file patterns are trimmed and lowercased",
+			content:   `{"testFilePatterns": [" *Test.java", "tests/"]}`,
+			wantTests: []string{"*test.java", "tests/"},
+		},
+		{
+			name:    "empty test file patterns",
+			content: `{"testFilePatterns": []}`,
+			wantErr: true,
+		},
+		{
+			name:    "malformed test file pattern",
+			content: `{"testFilePatterns": ["*_test.[go"]}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown key",
+			content: `{"categoryWeight": {"velocity": 1}}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON",
+			content: `{`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadConfigFile(writeConfigFile(t, tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadConfigFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got.CategoryWeights) != len(tt.wantWeights) {
+				t.Fatalf("CategoryWeights = %v, want %v", got.CategoryWeights, tt.wantWeights)
+			}
+			for cat, want := range tt.wantWeights {
+				if math.Abs(got.CategoryWeights[cat]-want) > 1e-9 {
+					t.Errorf("CategoryWeights[%s] = %v, want %v", cat, got.CategoryWeights[cat], want)
+				}
+			}
+			if p := got.severityPenalties(); !reflect.DeepEqual(p, tt.wantPenalty) {
+				t.Errorf("severityPenalties() = %+v, want %+v", p, tt.wantPenalty)
+			}
+			if b := got.doraRatingBands(); !reflect.DeepEqual(b, tt.wantBands) {
+				t.Errorf("doraRatingBands() = %+v, want %+v", b, tt.wantBands)
+			}
+			if !slices.Equal(got.FailureLabels, tt.wantLabels) {
+				t.Errorf("FailureLabels = %v, want %v", got.FailureLabels, tt.wantLabels)
+			}
+			if tt.wantPrefix == nil {
+				if got.BranchPrefixes != nil {
+					t.Errorf("BranchPrefixes = %+v, want nil", got.BranchPrefixes)
+				}
+			} else if p := got.branchPrefixes(); !slices.Equal(p.Feature, tt.wantPrefix.Feature) ||
+				!slices.Equal(p.BugFix, tt.wantPrefix.BugFix) || !slices.Equal(p.Refactor, tt.wantPrefix.Refactor) {
+				t.Errorf("BranchPrefixes = %+v, want %+v", p, *tt.wantPrefix)
+			}
+			if !slices.Equal(got.NonSourceExtensions, tt.wantExts) {
+				t.Errorf("NonSourceExtensions = %v, want %v", got.NonSourceExtensions, tt.wantExts)
+			}
+			if got.CoAuthorshipMitigatesOwnership != tt.wantCoAuth {
+				t.Errorf("CoAuthorshipMitigatesOwnership = %v, want %v", got.CoAuthorshipMitigatesOwnership, tt.wantCoAuth)
+			}
+			if !slices.Equal(got.TestFilePatterns, tt.wantTests) {
+				t.Errorf("TestFilePatterns = %v, want %v", got.TestFilePatterns, tt.wantTests)
+			}
+		})
+	}
+}
+
+func TestParseArgs_Config(t *testing.T) {
+	path := writeConfigFile(t, `{"categoryWeights": {"quality": 3}}`)
+
+	got, err := parseArgs([]string{"facebook/react", "--config", path}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if w := got.CategoryWeights[domain.CategoryQuality]; math.Abs(w-0.5) > 1e-9 {
+		t.Errorf("quality weight = %v, want 0.5", w)
+	}
+
+	got, err = parseArgs([]string{"facebook/react"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.CategoryWeights != nil {
+		t.Errorf("CategoryWeights = %v, want nil without --config", got.CategoryWeights)
+	}
+
+	if _, err := parseArgs([]string{"facebook/react", "--config", filepath.Join(t.TempDir(), "missing.json")}, time.Now); err == nil {
+		t.Error("expected error for missing config file")
+	}
+}
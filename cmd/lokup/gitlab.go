@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// gitLabTokenEnv は --provider gitlab のときにトークンを読む環境変数。
+const gitLabTokenEnv = "GITLAB_TOKEN"
+
+// gitLabAPIPath は GitLab の REST API（v4）のパス。
+const gitLabAPIPath = "/api/v4"
+
+// parseGitLabURL は --gitlab-url（セルフマネージドの GitLab のURL）を API のベース URL にする。
+// "https://gitlab.example.com" のようにインスタンスの URL だけを渡せば /api/v4 を補う。空なら GitLab.com を使うため空を返す。
+func parseGitLabURL(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid --gitlab-url: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("--gitlab-url must be an http or https URL: %s", s)
+	}
+	base := strings.TrimRight(s, "/")
+	if !strings.HasSuffix(base, gitLabAPIPath) {
+		base += gitLabAPIPath
+	}
+	return base, nil
+}
+
+// lookupGitLabToken は GitLab のトークンを探す。優先順位: tokenFile（--token-file）→ GITLAB_TOKEN。
+// gh のようなフォールバックはないため、見つからなければエラーにする。
+func lookupGitLabToken(tokenFile string, getenv func(string) string, stdin io.Reader) (string, error) {
+	if tokenFile != "" {
+		return readTokenFile(tokenFile, stdin)
+	}
+	if token := getenv(gitLabTokenEnv); token != "" {
+		return token, nil
+	}
+	return "", errors.New("GitLab authentication required: set " + gitLabTokenEnv + " or use --token-file")
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup"
+)
+
+func TestParseGitLabURL(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "", want: ""},
+		{in: "https://gitlab.example.com", want: "https://gitlab.example.com/api/v4"},
+		{in: "https://gitlab.example.com/", want: "https://gitlab.example.com/api/v4"},
+		{in: "https://example.com/gitlab/api/v4/", want: "https://example.com/gitlab/api/v4"},
+		{in: "gitlab.example.com", wantErr: true},
+		{in: "ftp://gitlab.example.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseGitLabURL(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseGitLabURL(%q) = %q, %v, want %q (error: %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestLookupGitLabToken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("glpat-from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env := func(token string) func(string) string {
+		return func(key string) string {
+			if key == gitLabTokenEnv {
+				return token
+			}
+			// GitHub のトークンは GitLab では使わない
+			return "ghp-unused"
+		}
+	}
+
+	if got, err := lookupGitLabToken(path, env("glpat-from-env"), strings.NewReader("")); err != nil || got != "glpat-from-file" {
+		t.Errorf("lookupGitLabToken(--token-file) = %q, %v, want the file's token", got, err)
+	}
+	if got, err := lookupGitLabToken("", env("glpat-from-env"), strings.NewReader("")); err != nil || got != "glpat-from-env" {
+		t.Errorf("lookupGitLabToken(GITLAB_TOKEN) = %q, %v, want glpat-from-env", got, err)
+	}
+	if _, err := lookupGitLabToken("", env(""), strings.NewReader("")); err == nil || !strings.Contains(err.Error(), gitLabTokenEnv) {
+		t.Errorf("lookupGitLabToken() without token error = %v, want an error naming %s", err, gitLabTokenEnv)
+	}
+}
+
+func TestParseArgs_Provider(t *testing.T) {
+	tests := []struct {
+		args      []string
+		want      lokup.Provider
+		wantURL   string
+		wantCache bool
+		wantErr   bool
+	}{
+		{args: []string{"facebook/react"}, want: lokup.ProviderGitHub},
+		{args: []string{"gitlab-org/gitlab", "--provider", "gitlab"}, want: lokup.ProviderGitLab},
+		{
+			args: []string{"platform/billing", "--provider", "gitlab", "--gitlab-url", "https://gitlab.example.com"},
+			want: lokup.ProviderGitLab, wantURL: "https://gitlab.example.com/api/v4",
+		},
+		// --history はキャッシュを有効にするが、GitLab ではキャッシュしない
+		{args: []string{"facebook/react", "--history", "3"}, want: lokup.ProviderGitHub, wantCache: true},
+		{args: []string{"gitlab-org/gitlab", "--provider", "gitlab", "--history", "3"}, want: lokup.ProviderGitLab},
+		{args: []string{"facebook/react", "--provider", "bitbucket"}, wantErr: true},
+		{args: []string{"facebook/react", "--gitlab-url", "https://gitlab.example.com"}, wantErr: true},
+		{args: []string{"gitlab-org/gitlab", "--provider", "gitlab", "--check"}, wantErr: true},
+		{args: []string{"gitlab-org/gitlab", "--provider", "gitlab", "--check-vulns"}, wantErr: true},
+		{args: []string{"gitlab-org/gitlab", "--provider", "gitlab", "--cache-ttl", "1h"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			got, err := parseArgs(tt.args, time.Now)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("parseArgs() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseArgs() error = %v", err)
+			}
+			if got.Provider != tt.want || got.GitLabURL != tt.wantURL || (got.CacheTTL > 0) != tt.wantCache {
+				t.Errorf("Provider = %q, GitLabURL = %q, CacheTTL = %s, want %q, %q, cache %v",
+					got.Provider, got.GitLabURL, got.CacheTTL, tt.want, tt.wantURL, tt.wantCache)
+			}
+		})
+	}
+}
@@ -7,230 +7,875 @@
 //
 //	lokup facebook/react
 //	lokup facebook/react --output report.html
+//	lokup facebook/react --output-dir reports --format json
 //	lokup facebook/react --days 30
+//	lokup facebook/react --since 2025-01-01 --until 2025-03-31
+//	lokup facebook/react --format json
+//	lokup facebook/react --fail-under 60
+//	lokup facebook/react --cache-ttl 1h
+//	lokup facebook/react --verbose
+//	lokup facebook/react --no-color
+//	lokup facebook/react --config lokup.json
+//	lokup facebook/react --baseline last-release.json
+//	lokup facebook/react --history 6 --window 30
+//	lokup facebook/react --lang en
+//	lokup org/a org/b org/c --output "reports/{repo}.html"
 package main
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"slices"
 	"strings"
+	"syscall"
 	"time"
+	_ "time/tzdata" // --timezone を tzdata のない環境（Windows 等）でも使えるように埋め込む
 
+	"github.com/ryuka-games/lokup"
 	"github.com/ryuka-games/lokup/domain"
 	"github.com/ryuka-games/lokup/features/analyze"
 	"github.com/ryuka-games/lokup/features/report"
 	"github.com/ryuka-games/lokup/infrastructure/github"
+	"github.com/ryuka-games/lokup/shared/i18n"
 )
 
 // Config は CLI 引数から解析された設定。
 type Config struct {
-	Owner  string // リポジトリオーナー（例: facebook）
-	Repo   string // リポジトリ名（例: react）
-	Output string // 出力ファイルパス
-	Days   int    // 分析期間（日数）
+	Repositories   []domain.Repository  // 分析対象リポジトリ（複数指定可）
+	Output         string               // 出力ファイルパス（{repo} でリポジトリごとに分割。OutputDir 指定時は空）
+	OutputDir      string               // index.html・CSV などをまとめて書き出すディレクトリ（空なら Output に書く）
+	Format         report.Format        // 出力形式（html / json / md / prometheus / sarif）
+	Days           int                  // 分析期間（日数）
+	Period         *domain.DateRange    // --since / --until で指定した分析期間（nil なら現在から Days 日さかのぼる）
+	FailUnder      int                  // 総合スコアがこの値未満なら終了コード 2（0 で無効）
+	CommitLimit    int                  // 変更ファイルを取得するコミット数の上限
+	Location       *time.Location       // コミット時刻を解釈するタイムゾーン（nil ならコミット自身のオフセット）
+	CacheTTL       time.Duration        // API レスポンスのキャッシュ有効期間（0 でキャッシュしない）
+	Verbose        bool                 // 取得ごとの所要時間・件数などを stderr に出す
+	Quiet          bool                 // 進捗表示を出さない
+	NoColor        bool                 // 結果表示を色付けしない
+	StalePRDays    int                  // オープンのままこの日数を超えたPRを滞留とみなす
+	TopFiles       int                  // 巨大ファイル・変更集中ファイルの一覧に残す件数
+	Lang           i18n.Lang            // レポートの出力言語（ja / en）
+	Path           string               // 分析対象をリポジトリ内のこのパス配下に絞る（空ならリポジトリ全体）
+	Branch         string               // デフォルトブランチの代わりに分析するブランチ（空ならデフォルトブランチ）
+	DeploySource   analyze.DeploySource // DORA メトリクスでデプロイとみなすイベントの取得元
+	DeployWorkflow string               // DeploySource が workflows のときのデプロイ用ワークフロー名
+	MTTRSource     analyze.MTTRSource   // MTTR で復旧作業の開始とみなす時点
+	CSVDir         string               // PR・コントリビューター詳細の CSV を書き出すディレクトリ（空なら出さない）
+	Rollup         string               // 全リポジトリを集約したロールアップ HTML の出力パス（空なら出さない）
+	IgnoreFile     *string              // --ignore-file で読み込んだ除外パターン（nil ならリポジトリの .lokupignore を使う）
+	AppAuth        *github.AppAuth      // GitHub App として認証する場合の認証情報（nil なら GITHUB_TOKEN / gh auth token）
+	TokenFile      string               // トークンを読むファイル（"-" なら標準入力。空なら GITHUB_TOKEN_FILE / GITHUB_TOKEN / gh auth token）
+	Concurrency    int                  // 同時に送る HTTP リクエスト数の上限
+	TodoScanFiles  int                  // TODO コメントを数えるために走査するファイル数の上限（0 なら走査しない）
+	CheckVulns     bool                 // 依存の既知の脆弱性を OSV で調べる
+	Quick          bool                 // PR詳細・レビューと依存の取得を省くクイックモード
+	IncludeDrafts  bool                 // ドラフトPRもPR系のメトリクスに含める
+	Check          bool                 // 分析せずにトークンとリポジトリへの到達性だけを確認する
+	Explain        bool                 // カテゴリ別スコアの内訳（リスクごとの減点）を表示する
+	History        int                  // スコアの推移を出す期間の数（0 なら出さない）
+	HistoryWindow  int                  // スコアの推移の各期間の日数（0 なら分析期間と同じ）
+	Clock          func() time.Time     // 現在時刻の取得元（期間の計算と分析で共通に使う）
+
+	CategoryWeights map[domain.Category]float64 // 総合スコアのカテゴリ別の重み（nil なら均等、--config で指定）
+	FailureLabels   []string                    // 障害とみなす Issue ラベル（nil ならデフォルト、--config で指定）
+	Penalties       *analyze.SeverityPenalties  // カテゴリスコアの重大度別の減点（nil ならデフォルト、--config で指定）
+	DORABands       *domain.DORARatingBands     // DORA レーティングの境界（nil ならデフォルト、--config で指定）
+	BranchPrefixes  analyze.BranchPrefixes      // PRの種類を判定するブランチ名の接頭辞（ゼロ値ならデフォルト、--config で指定）
+	NonSourceExts   []string                    // 巨大ファイルリスクの対象外にするファイル名の末尾（nil ならデフォルト、--config で指定）
+	Baseline        *report.Baseline            // 比較の基準にする過去の JSON レポート（nil なら比較しない）
+
+	CoAuthorshipMitigation bool     // 共同作成のコミットが多ければ属人化リスクの重大度を下げる（--config で指定）
+	TestFilePatterns       []string // テストファイルとみなすパスのパターン（nil ならデフォルト、--config で指定）
+
+	OwnershipSource analyze.OwnershipSource // 属人化リスクでコミットの偏りを見るときの集計元
+
+	PostURL     string      // 分析結果の JSON を POST する URL（空なら送らない）
+	PostHeaders http.Header // POST に付ける HTTP ヘッダー（--post-header）
+
+	Provider  lokup.Provider // データの取得元（github / gitlab）
+	GitLabURL string         // GitLab API のベース URL（空なら GitLab.com）
 }
 
+// --history の設定
+const (
+	maxHistory      = 24        // 期間ごとに分析し直すため、API の呼び出しが増えすぎないよう上限を設ける
+	historyCacheTTL = time.Hour // --cache-ttl 未指定時に使うキャッシュ期間（期間をまたいで共通の取得をキャッシュから返す）
+)
+
+// 終了コード
+const (
+	exitOK        = 0
+	exitError     = 1
+	exitFailUnder = 2   // --fail-under の閾値を下回った
+	exitCanceled  = 130 // Ctrl-C（SIGINT）・SIGTERM で中断した（シェルの慣習に合わせる）
+)
+
+// errScoreBelowThreshold は総合スコアが --fail-under を下回ったことを示す。
+var errScoreBelowThreshold = errors.New("overall score below threshold")
+
+// errCanceled は Ctrl-C（SIGINT）・SIGTERM で分析を中断したことを示す。
+var errCanceled = errors.New("cancelled")
+
 func main() {
-	if err := run(); err != nil {
+	// Ctrl-C・SIGTERM で ctx をキャンセルし、実行中の HTTP リクエストを打ち切る。
+	// 1回目のシグナルで通知をやめ、後片付けが終わらなくても2回目でいつも通り終了できるようにする
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	context.AfterFunc(ctx, stop)
+	err := canceledError(ctx, run(ctx, time.Now))
+	stop()
+
+	switch {
+	case errors.Is(err, errCanceled):
+		fmt.Fprintf(os.Stderr, "\n%s\n", err)
+	case err != nil:
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
 	}
+	os.Exit(exitCode(err))
+}
+
+// canceledError は ctx がキャンセルされて run が失敗した場合に、個々のネットワークエラーを並べる代わりに
+// errCanceled を返す（途中までの進み具合は run が errCanceled に添えたものをそのまま使う）。
+func canceledError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil || errors.Is(err, errCanceled) {
+		return err
+	}
+	return errCanceled
 }
 
-func run() error {
-	config, err := parseArgs(os.Args[1:])
+// exitCode はエラーに対応する終了コードを返す。
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, errScoreBelowThreshold):
+		return exitFailUnder
+	case errors.Is(err, errCanceled):
+		return exitCanceled
+	default:
+		return exitError
+	}
+}
+
+// run は CLI を実行する。now は現在時刻の取得元で、--since / --until の解釈と分析で共通に使う。
+func run(ctx context.Context, now func() time.Time) error {
+	config, err := parseArgs(os.Args[1:], now)
 	if err != nil {
 		return err
 	}
 
-	// GitHub トークン取得（GITHUB_TOKEN → gh auth token → エラー）
-	token, err := resolveGitHubToken()
+	// トークン取得（GitHub App → --token-file → GITHUB_TOKEN_FILE → GITHUB_TOKEN → gh auth token → エラー。
+	// --provider gitlab なら --token-file → GITLAB_TOKEN → エラー）
+	token, err := resolveToken(ctx, config)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Lokup - GitHub Repository Health Check\n\n")
-	fmt.Printf("Repository: %s/%s\n", config.Owner, config.Repo)
-	fmt.Printf("Period:     %d days\n", config.Days)
-	fmt.Printf("Output:     %s\n", config.Output)
-	fmt.Println()
+	// --check: 分析せずにアクセスだけ確認して終わる
+	if config.Check {
+		client := github.NewClient(token, github.WithLogger(newLogger(os.Stderr, config.Verbose)))
+		return runCheck(ctx, os.Stdout, client, config.Repositories)
+	}
+
+	// レポートを標準出力に書く場合、進捗や結果表示は stderr に逃がして stdout を汚さない
+	out := os.Stdout
+	toStdout := config.Output == report.StdoutPath
+	if toStdout {
+		out = os.Stderr
+	}
 
-	// 依存関係の組み立て
-	client := github.NewClient(token)
-	service := analyze.NewService(client)
+	if config.Provider == lokup.ProviderGitLab {
+		fmt.Fprintf(out, "Lokup - GitLab Repository Health Check\n\n")
+	} else {
+		fmt.Fprintf(out, "Lokup - GitHub Repository Health Check\n\n")
+	}
+	fmt.Fprintf(out, "Repository: %s\n", joinRepositoryNames(config.Repositories))
+	if config.Period != nil {
+		fmt.Fprintf(out, "Period:     %s ~ %s\n", config.Period.From.Format(time.RFC3339), config.Period.To.Format(time.RFC3339))
+	} else {
+		fmt.Fprintf(out, "Period:     %d days\n", config.Days)
+	}
+	if config.Path != "" {
+		fmt.Fprintf(out, "Path:       %s\n", config.Path)
+	}
+	if config.Branch != "" {
+		fmt.Fprintf(out, "Branch:     %s\n", config.Branch)
+	}
+	if config.History > 0 {
+		if config.HistoryWindow > 0 {
+			fmt.Fprintf(out, "History:    %d windows of %d days\n", config.History, config.HistoryWindow)
+		} else {
+			fmt.Fprintf(out, "History:    %d windows\n", config.History)
+		}
+	}
+	switch config.DeploySource {
+	case analyze.DeploySourceTags:
+		fmt.Fprintf(out, "Deploys:    tags\n")
+	case analyze.DeploySourceWorkflows:
+		fmt.Fprintf(out, "Deploys:    workflow runs (%s)\n", config.DeployWorkflow)
+	}
+	if config.MTTRSource == analyze.MTTRSourceEvents {
+		fmt.Fprintf(out, "MTTR:       from the first failure label or assignment (issue events)\n")
+	}
+	if config.OwnershipSource == analyze.OwnershipSourceWindow {
+		fmt.Fprintf(out, "Ownership:  commits in the period\n")
+	}
+	if config.OutputDir != "" {
+		fmt.Fprintf(out, "Output:     %s (index.html, CSV%s)\n", config.OutputDir, bundleFormatSuffix(config.Format))
+	} else {
+		fmt.Fprintf(out, "Output:     %s (%s)\n", config.Output, config.Format)
+	}
+	fmt.Fprintln(out)
 
-	// 分析期間の計算
-	now := time.Now()
-	from := now.AddDate(0, 0, -config.Days)
-	period := domain.NewDateRange(from, now)
+	// 進捗表示（stderr が端末のときだけ。ログは進捗の行を消してから書く）
+	var progress *progressLine
+	var logOut io.Writer = os.Stderr
+	if !config.Quiet && isTerminal(os.Stderr) {
+		progress = newProgressLine(os.Stderr, spinnerInterval)
+		defer progress.Close()
+		logOut = progress
+	}
 
-	// 分析実行
-	ctx := context.Background()
-	input := analyze.ServiceInput{
-		Repository: domain.NewRepository(config.Owner, config.Repo),
-		Period:     period,
+	// 結果表示（出力先が端末のときだけグレードと重大度で色分けする）
+	summary := summaryRenderer{
+		lang:    config.Lang,
+		explain: config.Explain,
+		color:   useColor(config.NoColor, isTerminal(out), os.Getenv),
 	}
 
-	fmt.Println("Analyzing...")
-	result, err := service.Analyze(ctx, input)
+	// 依存関係の組み立て（期間の計算も含めてライブラリ側で行う）
+	logger := newLogger(logOut, config.Verbose)
+	opts := config.options(token, logger)
+	if progress != nil {
+		opts.Progress = progress.Update
+	}
+	analyzer, err := lokup.NewAnalyzer(opts)
 	if err != nil {
-		return fmt.Errorf("analysis failed: %w", err)
+		return err
 	}
 
-	// 結果表示
-	printResult(result)
+	// 分析実行（1件失敗しても残りは続行し、エラーは最後にまとめて報告する）
+	var results []*domain.AnalysisResult
+	var errs []error
+	for _, repo := range config.Repositories {
+		fmt.Fprintf(out, "Analyzing %s...\n", repo.FullName())
+		result, err := analyzer.Analyze(ctx, repo)
+		progress.Clear()
+		// 中断されたら残りのリポジトリは分析せず、レポートも書かない
+		if ctx.Err() != nil {
+			return fmt.Errorf("%w (%d of %d repositories analyzed; no report was written)",
+				errCanceled, len(results), len(config.Repositories))
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", repo.FullName(), err))
+			continue
+		}
 
-	// HTML レポート生成
-	fmt.Printf("\nGenerating report: %s\n", config.Output)
-	reportService := report.NewService()
-	if err := reportService.Generate(result, config.Output); err != nil {
-		return fmt.Errorf("report generation failed: %w", err)
+		// 結果表示
+		summary.render(out, result)
+		results = append(results, result)
 	}
-	fmt.Println("Report generated successfully!")
 
-	return nil
-}
+	// レポート生成
+	if len(results) > 0 {
+		reportService := report.NewService(report.WithLang(config.Lang))
+		start := time.Now()
+		var paths []string
+		if config.OutputDir != "" {
+			fmt.Fprintf(out, "\nGenerating report: %s\n", config.OutputDir)
+			paths, err = reportService.GenerateBundle(results, config.OutputDir, config.Format)
+		} else {
+			fmt.Fprintf(out, "\nGenerating report: %s\n", config.Output)
+			paths, err = reportService.GenerateAll(results, config.Output, config.Format)
+		}
+		if err != nil {
+			return fmt.Errorf("report generation failed: %w", err)
+		}
+		logger.Debug("report generated", "format", config.Format, "files", len(paths),
+			"duration", time.Since(start).Round(time.Millisecond))
+		if !toStdout {
+			for _, p := range paths {
+				fmt.Fprintf(out, "  %s\n", p)
+			}
+			fmt.Fprintln(out, "Report generated successfully!")
+		}
+
+		if config.CSVDir != "" {
+			paths, err := reportService.GenerateCSV(results, config.CSVDir)
+			if err != nil {
+				return fmt.Errorf("CSV export failed: %w", err)
+			}
+			fmt.Fprintf(out, "\nCSV exported:\n")
+			for _, p := range paths {
+				fmt.Fprintf(out, "  %s\n", p)
+			}
+		}
 
-// printResult は分析結果を表示する。
-func printResult(r *domain.AnalysisResult) {
-	fmt.Println("\n========================================")
-	fmt.Println("           Analysis Result")
-	fmt.Println("========================================")
-
-	fmt.Printf("\nRepository: %s\n", r.Repository.FullName())
-	fmt.Printf("Period:     %s ~ %s (%d days)\n",
-		r.Period.From.Format("2006-01-02"),
-		r.Period.To.Format("2006-01-02"),
-		r.Period.Days())
-
-	fmt.Printf("\nOverall:    %d/100 (%s)\n", r.OverallScore.Value, r.OverallScore.Grade())
-
-	fmt.Println("\n--- Category Scores ---")
-	catNames := map[domain.Category]string{
-		domain.CategoryVelocity: "Velocity",
-		domain.CategoryQuality:  "Quality",
-		domain.CategoryTechDebt: "Tech Debt",
-		domain.CategoryHealth:   "Health",
-	}
-	for _, cat := range []domain.Category{domain.CategoryVelocity, domain.CategoryQuality, domain.CategoryTechDebt, domain.CategoryHealth} {
-		if cs, ok := r.CategoryScores[cat]; ok {
-			fmt.Printf("%-12s %d/100 (%s) - %s\n", catNames[cat]+":", cs.Score.Value, cs.Score.Grade(), cs.Diagnosis)
-		}
-	}
-
-	fmt.Println("\n--- Metrics ---")
-	fmt.Printf("Total Commits:        %d\n", r.Metrics.TotalCommits)
-	fmt.Printf("Feature Addition:     %.2f commits/day\n", r.Metrics.FeatureAdditionRate)
-	fmt.Printf("Contributors:         %d\n", r.Metrics.TotalContributors)
-	fmt.Printf("Late Night Commits:   %.1f%%\n", r.Metrics.LateNightCommitRate)
-
-	fmt.Println("\n--- DORA Metrics ---")
-	fmt.Printf("Deploy Freq:          %.1f/month (%s)\n", r.Metrics.DeployFrequency, r.Metrics.DeployFreqRating)
-	fmt.Printf("Change Failure Rate:  %.1f%% (%s)\n", r.Metrics.ChangeFailureRate, r.Metrics.ChangeFailRating)
-	fmt.Printf("MTTR:                 %.1fh (%s)\n", r.Metrics.MTTR, r.Metrics.MTTRRating)
-
-	fmt.Println("\n--- Investment Ratio ---")
-	fmt.Printf("Feature:   %d PRs (%.1f%%)\n", r.Metrics.FeaturePRCount, r.Metrics.FeatureRatio)
-	fmt.Printf("BugFix:    %d PRs (%.1f%%)\n", r.Metrics.BugFixPRCount, r.Metrics.BugFixRatio)
-	fmt.Printf("Refactor:  %d PRs (%.1f%%)\n", r.Metrics.RefactorPRCount, r.Metrics.RefactorRatio)
-	fmt.Printf("Other:     %d PRs\n", r.Metrics.OtherPRCount)
-	fmt.Printf("Revert:    %d commits (%.1f%%)\n", r.Metrics.RevertCommitCount, r.Metrics.RevertRate)
-
-	if len(r.Trends) > 0 {
-		fmt.Println("\n--- Trends (vs Previous Period) ---")
-		for _, t := range r.Trends {
-			arrow := "→"
-			switch t.Direction {
-			case "up":
-				arrow = "↑"
-			case "down":
-				arrow = "↓"
+		if config.Rollup != "" {
+			if err := reportService.GenerateRollup(results, config.Rollup, config.rollupReportPath()); err != nil {
+				return fmt.Errorf("rollup generation failed: %w", err)
 			}
-			fmt.Printf("%s %-16s %+.1f%%\n", arrow, t.MetricName, t.DeltaPct)
+			fmt.Fprintf(out, "\nRollup generated: %s\n", config.Rollup)
 		}
-	}
 
-	if len(r.Risks) > 0 {
-		fmt.Println("\n--- Risks ---")
-		for _, risk := range r.Risks {
-			severity := "⚪"
-			switch risk.Severity {
-			case domain.SeverityHigh:
-				severity = "🔴"
-			case domain.SeverityMedium:
-				severity = "🟡"
-			case domain.SeverityLow:
-				severity = "🟢"
+		// 分析結果の送信（ダッシュボードなどへの集約用）
+		if config.PostURL != "" {
+			var body bytes.Buffer
+			if err := reportService.RenderJSON(&body, results); err != nil {
+				return fmt.Errorf("failed to build results JSON: %w", err)
 			}
-			fmt.Printf("%s %s: %s\n", severity, risk.Type, risk.Description)
+			fmt.Fprintf(out, "\nPosting results: %s\n", config.PostURL)
+			client := &http.Client{Timeout: postTimeout}
+			if err := postResults(ctx, client, config.PostURL, config.PostHeaders, body.Bytes()); err != nil {
+				return err
+			}
+			fmt.Fprintln(out, "Results posted successfully!")
 		}
-	} else {
-		fmt.Println("\n--- Risks ---")
-		fmt.Println("No significant risks detected.")
 	}
 
-	fmt.Println("\n========================================")
+	if len(errs) > 0 {
+		return fmt.Errorf("analysis failed for %d of %d repositories:\n%w",
+			len(errs), len(config.Repositories), errors.Join(errs...))
+	}
+
+	return checkFailUnder(results, config.FailUnder)
+}
+
+// bundleFormatSuffix は --output-dir の出力内容の表示に添える、HTML 以外の形式のレポートファイル名を返す。
+func bundleFormatSuffix(format report.Format) string {
+	if format == report.FormatHTML {
+		return ""
+	}
+	return ", report" + format.Ext()
+}
+
+// rollupReportPath はロールアップからリンクする HTML レポートのパスを返す。
+// --output-dir なら index.html、HTML 以外の形式で --output に書いた場合はリンクしない（空を返す）。
+func (c *Config) rollupReportPath() string {
+	if c.OutputDir != "" {
+		return filepath.Join(c.OutputDir, "index.html")
+	}
+	if c.Format != report.FormatHTML {
+		return ""
+	}
+	return c.Output
+}
+
+// options は CLI の設定をライブラリの Options に変換する。
+// リポジトリは1件ずつ Analyzer.Analyze に渡すため含めない。
+func (c *Config) options(token string, logger *slog.Logger) lokup.Options {
+	return lokup.Options{
+		Token:            token,
+		Period:           c.Period,
+		Days:             c.Days,
+		StalePRDays:      c.StalePRDays,
+		MaxCommitDetails: c.CommitLimit,
+		TopFiles:         c.TopFiles,
+		CategoryWeights:  c.CategoryWeights,
+		FailureLabels:    c.FailureLabels,
+		Penalties:        c.Penalties,
+		DORABands:        c.DORABands,
+		BranchPrefixes:   c.BranchPrefixes,
+		NonSourceExts:    c.NonSourceExts,
+		TestFilePatterns: c.TestFilePatterns,
+		Location:         c.Location,
+		Path:             c.Path,
+		Branch:           c.Branch,
+		IgnoreFile:       c.IgnoreFile,
+		DeploySource:     c.DeploySource,
+		DeployWorkflow:   c.DeployWorkflow,
+		MTTRSource:       c.MTTRSource,
+		OwnershipSource:  c.OwnershipSource,
+		Concurrency:      c.Concurrency,
+		TodoScanMaxFiles: c.TodoScanFiles,
+		CheckVulns:       c.CheckVulns,
+		Quick:            c.Quick,
+		IncludeDrafts:    c.IncludeDrafts,
+		CacheTTL:         c.CacheTTL,
+		Lang:             c.Lang,
+		Baseline:         c.Baseline,
+		History:          c.History,
+		HistoryWindow:    c.HistoryWindow,
+		Clock:            c.Clock,
+		Logger:           logger,
+
+		CoAuthorshipMitigation: c.CoAuthorshipMitigation,
+
+		Provider:  c.Provider,
+		GitLabURL: c.GitLabURL,
+	}
+}
+
+// newLogger は CLI 用のロガーを生成する。
+// 通常は警告以上だけを出し、--verbose なら取得ごとの所要時間などの Debug ログも出す。
+func newLogger(w io.Writer, verbose bool) *slog.Logger {
+	level := slog.LevelWarn
+	if verbose {
+		level = slog.LevelDebug
+	}
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// checkFailUnder は総合スコアが閾値を下回るリポジトリがあればエラーを返す。
+// レポートは出力済みの前提で、CI を失敗させるためだけに使う。
+func checkFailUnder(results []*domain.AnalysisResult, threshold int) error {
+	if threshold <= 0 {
+		return nil
+	}
+
+	var failed []string
+	for _, r := range results {
+		if r.OverallScore.Value < threshold {
+			failed = append(failed, fmt.Sprintf("%s (%d)", r.Repository.FullName(), r.OverallScore.Value))
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w %d: %s", errScoreBelowThreshold, threshold, strings.Join(failed, ", "))
+}
+
+// joinRepositoryNames はリポジトリ名をカンマ区切りで連結する。
+func joinRepositoryNames(repos []domain.Repository) string {
+	names := make([]string, len(repos))
+	for i, r := range repos {
+		names[i] = r.FullName()
+	}
+	return strings.Join(names, ", ")
 }
 
 // parseArgs は CLI 引数を解析して Config を返す。
-func parseArgs(args []string) (*Config, error) {
+// now は現在時刻の取得元で、--since / --until の解釈に使い、Config.Clock として分析にも渡す。
+func parseArgs(args []string, now func() time.Time) (*Config, error) {
 	fs := flag.NewFlagSet("lokup", flag.ContinueOnError)
 
 	// フラグ定義
-	output := fs.String("output", "report.html", "Output file path")
-	days := fs.Int("days", 30, "Analysis period in days")
+	output := fs.String("output", "", "Output file path (use {repo} for one file per repository, - for stdout) (default \"report.<format>\")")
+	outputDir := fs.String("output-dir", "", "Write index.html, the CSV drill-down data and (with a non-HTML --format) report.<format> into this directory, creating it if needed (cannot be combined with --output)")
+	format := fs.String("format", string(report.FormatHTML), "Output format: html, json, md (Markdown summary for PR comments), prometheus (text exposition format) or sarif (risks as SARIF 2.1.0 for security tooling)")
+	days := fs.Int("days", lokup.DefaultDays, "Analysis period in days")
+	since := fs.String("since", "", "Start of the analysis period (RFC3339 or YYYY-MM-DD); use instead of --days")
+	until := fs.String("until", "", "End of the analysis period (RFC3339 or YYYY-MM-DD, a date includes the whole day; requires --since) (default: now)")
+	failUnder := fs.Int("fail-under", 0, "Exit with status 2 if the overall score is below this value (0 disables)")
+	timezone := fs.String("timezone", "", "IANA timezone for late-night detection and hourly stats, e.g. Asia/Tokyo (default: each commit's own offset)")
+	commitLimit := fs.Int("max-commit-details", analyze.DefaultMaxCommitDetails, "Max number of recent commits to fetch changed files for (used for change concentration)")
+	stalePRDays := fs.Int("stale-pr-days", analyze.DefaultStalePRDays, "Open pull requests older than this many days count as stale")
+	topFiles := fs.Int("top-files", analyze.DefaultTopFiles, "Max number of large files and change hotspots to list in the report (risk counts still cover all files)")
+	concurrency := fs.Int("concurrency", github.DefaultConcurrency, "Max number of HTTP requests in flight at once, across all fetches (raise carefully; GitHub enforces secondary rate limits)")
+	cacheTTL := fs.Duration("cache-ttl", 0, "Cache GitHub/registry API responses on disk for this long, e.g. 1h (0 disables)")
+	noCache := fs.Bool("no-cache", false, "Bypass the on-disk API cache even if --cache-ttl is set")
+	baselinePath := fs.String("baseline", "", "Path to a previously saved JSON report (--format json) to compare scores and key metrics against")
+	history := fs.Int("history", 0, fmt.Sprintf("Also analyze this many consecutive windows back from the end of the period (2-%d) and show the overall score trend (0 disables; enables a 1h cache unless --cache-ttl or --no-cache is given)", maxHistory))
+	window := fs.Int("window", 0, "Length in days of each --history window (default: the length of the analysis period)")
+	configPath := fs.String("config", "", "Path to a JSON config file (e.g. category weights for the overall score)")
+	verbose := fs.Bool("verbose", false, "Log each fetch step with timing, item counts and pages walked to stderr")
+	quiet := fs.Bool("quiet", false, "Do not show the progress indicator (it is shown only when stderr is a terminal)")
+	noColor := fs.Bool("no-color", false, "Do not color the result summary by grade and risk severity (it is colored only when the output is a terminal and NO_COLOR is unset)")
+	lang := fs.String("lang", string(i18n.Default), "Report language: ja (Japanese) or en (English)")
+	deploySource := fs.String("deploy-source", string(analyze.DeploySourceReleases), "What counts as a deploy for DORA metrics: releases (GitHub Releases), tags, or workflows (successful runs of --deploy-workflow)")
+	deployWorkflow := fs.String("deploy-workflow", "", "Name or file name (e.g. deploy.yml) of the GitHub Actions workflow that deploys (with --provider gitlab: the environment or job name of successful deployments); required with --deploy-source workflows")
+	mttrSource := fs.String("mttr-source", string(analyze.MTTRSourceCreated), "When recovery starts for MTTR: created (issue creation) or events (first failure label or assignment, from issue events; falls back to creation when unavailable)")
+	ownershipSource := fs.String("ownership-source", string(analyze.OwnershipSourceLifetime), "What the ownership risk counts: lifetime (all-time contributions from the contributors API) or window (commits in the analysis period, so authors who left long ago do not dominate)")
+	csvDir := fs.String("csv-dir", "", "Also write pull_requests.csv and contributors.csv (drill-down data) to this directory (one subdirectory per repository when several are given)")
+	rollup := fs.String("rollup", "", "Also write an org-level rollup HTML to this file: average score, grade distribution, lowest-scoring repositories, DORA aggregates and the most common risks, linking to each repository's HTML report")
+	appID := fs.Int64("app-id", 0, "GitHub App ID; authenticate as an App installation instead of GITHUB_TOKEN / gh (requires --installation-id and --private-key)")
+	installationID := fs.Int64("installation-id", 0, "GitHub App installation ID to create a short-lived installation token for")
+	tokenFile := fs.String("token-file", "", "Read the GitHub token (the GitLab token with --provider gitlab) from this file (- for stdin), e.g. a mounted secret; takes precedence over GITHUB_TOKEN_FILE and GITHUB_TOKEN (GITLAB_TOKEN)")
+	privateKey := fs.String("private-key", "", "Path to the GitHub App private key (PEM file downloaded from the App settings)")
+	ignoreFile := fs.String("ignore-file", "", "Path to a local .lokupignore-style file of path patterns to exclude from large-file and change-concentration risks (default: the repository's .lokupignore)")
+	scanTodos := fs.Bool("scan-todos", false, "Count TODO/FIXME/HACK comments in source files (fetches file contents, so it is off by default)")
+	todoMaxFiles := fs.Int("todo-max-files", analyze.DefaultTodoScanMaxFiles, "Max number of source files to fetch for --scan-todos (largest first)")
+	checkVulns := fs.Bool("check-vulns", false, "Look up known vulnerabilities (CVE/GHSA) of dependencies in OSV (api.osv.dev; adds network calls, so it is off by default)")
+	explain := fs.Bool("explain", false, "Print the score breakdown under each category (base score and the points each risk deducted)")
+	quick := fs.Bool("quick", false, "Quick mode: skip per-PR details/reviews and dependency lookups (PR size, review and dependency metrics are not computed)")
+	check := fs.Bool("check", false, "Only check that the token works and each repository is reachable (one request per repository), then exit without analyzing")
+	includeDrafts := fs.Bool("include-drafts", false, "Count draft pull requests in lead time, PR breakdown, PR size, review and stale-PR metrics (excluded by default)")
+	path := fs.String("path", "", "Limit commits, files and merged pull requests to this directory, e.g. services/billing (contributors, issues, releases and dependencies stay repository-wide)")
+	reposFromFile := fs.String("repos-from-file", "", "Read repositories from this file, one owner/repo per line (blank lines and # comments are ignored); combined with any repositories given as arguments")
+	postURLFlag := fs.String("post-url", "", "After the analysis, POST the results as JSON (the --format json schema) to this URL, e.g. an internal dashboard; fails if the response is not 2xx")
+	var postHeaders headerFlag
+	fs.Var(&postHeaders, "post-header", "HTTP header for --post-url as Name=Value, e.g. Authorization=Bearer xxx (repeatable)")
+	branch := fs.String("branch", "", "Analyze commits and files of this branch instead of the default branch, e.g. develop (pull requests, issues, releases and dependencies stay repository-wide)")
+	providerFlag := fs.String("provider", string(lokup.ProviderGitHub), "Where the repositories are hosted: github or gitlab (reads GITLAB_TOKEN; merge requests count as pull requests, dependencies, file sizes and force pushes are not analyzed)")
+	gitlabURLFlag := fs.String("gitlab-url", "", "URL of a self-managed GitLab instance for --provider gitlab, e.g. https://gitlab.example.com (default: gitlab.com)")
 
 	// カスタム Usage
 	fs.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: lokup <owner/repo> [options]\n\n")
+		fmt.Fprintf(os.Stderr, "Usage: lokup <owner/repo>... [options]\n\n")
 		fmt.Fprintf(os.Stderr, "Arguments:\n")
-		fmt.Fprintf(os.Stderr, "  owner/repo    GitHub repository (e.g., facebook/react)\n\n")
+		fmt.Fprintf(os.Stderr, "  owner/repo    GitHub repository (e.g., facebook/react), or GitLab project with --provider gitlab. Multiple repositories can be given, or listed in --repos-from-file\n\n")
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		fs.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
 		fmt.Fprintf(os.Stderr, "  lokup facebook/react\n")
 		fmt.Fprintf(os.Stderr, "  lokup facebook/react --output report.html\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --output-dir reports --format json\n")
 		fmt.Fprintf(os.Stderr, "  lokup facebook/react --days 90\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --since 2025-01-01 --until 2025-03-31\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --format json --output report.json\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --format json --output - | jq .overallScore\n")
+		fmt.Fprintf(os.Stderr, "  lokup org/a org/b --output \"reports/{repo}.html\"\n")
+		fmt.Fprintf(os.Stderr, "  lokup org/a org/b --format prometheus --output /var/lib/node_exporter/lokup.prom\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --format sarif --output lokup.sarif\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --fail-under 60\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --timezone Asia/Tokyo\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --cache-ttl 1h\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --concurrency 2\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --quick\n")
+		fmt.Fprintf(os.Stderr, "  lokup org/a org/b --check\n")
+		fmt.Fprintf(os.Stderr, "  lokup --repos-from-file repos.txt --output \"reports/{repo}.html\"\n")
+		fmt.Fprintf(os.Stderr, "  lokup --repos-from-file repos.txt --output \"reports/{repo}.html\" --rollup reports/rollup.html\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --post-url https://dashboard.example.com/api/lokup --post-header \"Authorization=Bearer $DASHBOARD_TOKEN\"\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --explain\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --verbose\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --quiet\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --no-color\n")
+		fmt.Fprintf(os.Stderr, "  lokup org/repo --app-id 12345 --installation-id 678 --private-key app.pem\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --token-file /run/secrets/github_token\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --config lokup.json\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --baseline last-release.json\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --history 6 --window 30\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --lang en\n")
+		fmt.Fprintf(os.Stderr, "  lokup org/monorepo --path services/billing\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --branch develop\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --ignore-file .lokupignore\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --csv-dir exports\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --scan-todos --todo-max-files 100\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --check-vulns\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --deploy-source tags\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --deploy-source workflows --deploy-workflow deploy.yml\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --mttr-source events\n")
+		fmt.Fprintf(os.Stderr, "  lokup facebook/react --ownership-source window\n")
+		fmt.Fprintf(os.Stderr, "  lokup gitlab-org/gitlab-runner --provider gitlab\n")
+		fmt.Fprintf(os.Stderr, "  lokup platform/billing --provider gitlab --gitlab-url https://gitlab.example.com\n")
+		fmt.Fprintf(os.Stderr, "\nExit status:\n")
+		fmt.Fprintf(os.Stderr, "  0  success\n")
+		fmt.Fprintf(os.Stderr, "  1  error (invalid arguments, API failure, unreachable repository with --check, etc.)\n")
+		fmt.Fprintf(os.Stderr, "  2  overall score below --fail-under (the report is still written)\n")
+		fmt.Fprintf(os.Stderr, "  130  cancelled with Ctrl-C (SIGINT) or SIGTERM (no report is written)\n")
 	}
 
 	// Go の flag パッケージは最初の非フラグ引数で解析を止めるため、
 	// 位置引数（owner/repo）とフラグを分離してからパースする。
-	flagArgs, positionalArgs := splitArgs(args)
+	flagArgs, positionalArgs := splitArgs(fs, args)
 
 	if err := fs.Parse(flagArgs); err != nil {
 		return nil, err
 	}
 
-	if len(positionalArgs) < 1 {
-		fs.Usage()
-		return nil, errors.New("repository argument required")
+	if *failUnder < 0 || *failUnder > 100 {
+		return nil, fmt.Errorf("--fail-under must be between 0 and 100: %d", *failUnder)
+	}
+
+	if *commitLimit < 1 {
+		return nil, fmt.Errorf("--max-commit-details must be at least 1: %d", *commitLimit)
+	}
+
+	if *stalePRDays < 1 {
+		return nil, fmt.Errorf("--stale-pr-days must be at least 1: %d", *stalePRDays)
+	}
+
+	if *topFiles < 1 {
+		return nil, fmt.Errorf("--top-files must be at least 1: %d", *topFiles)
+	}
+
+	if *todoMaxFiles < 1 {
+		return nil, fmt.Errorf("--todo-max-files must be at least 1: %d", *todoMaxFiles)
+	}
+	todoScanFiles := 0
+	if *scanTodos {
+		todoScanFiles = *todoMaxFiles
+	}
+
+	if *quick && *checkVulns {
+		return nil, errors.New("--check-vulns cannot be combined with --quick (quick mode does not fetch dependencies)")
+	}
+
+	if *concurrency < 1 {
+		return nil, fmt.Errorf("--concurrency must be at least 1: %d", *concurrency)
+	}
+
+	provider, err := lokup.ParseProvider(*providerFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	if *cacheTTL < 0 {
+		return nil, fmt.Errorf("--cache-ttl must not be negative: %s", *cacheTTL)
+	}
+	if provider == lokup.ProviderGitLab && *cacheTTL > 0 {
+		return nil, errors.New("--cache-ttl is not supported with --provider gitlab")
+	}
+	if *history != 0 && (*history < 2 || *history > maxHistory) {
+		return nil, fmt.Errorf("--history must be between 2 and %d: %d", maxHistory, *history)
+	}
+	if isFlagSet(fs, "window") {
+		if *history == 0 {
+			return nil, errors.New("--window requires --history")
+		}
+		if *window < 1 {
+			return nil, fmt.Errorf("--window must be at least 1: %d", *window)
+		}
+	}
+	// 期間ごとの分析はファイルツリーや依存など期間によらない取得を繰り返すため、キャッシュで使い回す
+	// （GitLab クライアントはキャッシュを持たないため、GitLab では毎回取得する）
+	if *history > 0 && !isFlagSet(fs, "cache-ttl") && provider != lokup.ProviderGitLab {
+		*cacheTTL = historyCacheTTL
+	}
+	if *noCache {
+		*cacheTTL = 0
+	}
+
+	var location *time.Location
+	if *timezone != "" {
+		loc, err := time.LoadLocation(*timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --timezone: %w", err)
+		}
+		location = loc
+	}
+
+	// 日付だけの指定は --timezone（未指定ならローカル）の日付として解釈する
+	var period *domain.DateRange
+	if *since != "" || *until != "" {
+		if isFlagSet(fs, "days") {
+			return nil, errors.New("--days cannot be combined with --since/--until")
+		}
+		dateLoc := location
+		if dateLoc == nil {
+			dateLoc = time.Local
+		}
+		p, err := parsePeriod(*since, *until, dateLoc, now())
+		if err != nil {
+			return nil, err
+		}
+		period = &p
+	}
+
+	scopePath, err := parseScopePath(*path)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := analyze.ParseDeploySource(*deploySource)
+	if err != nil {
+		return nil, err
+	}
+	if source == analyze.DeploySourceWorkflows && *deployWorkflow == "" {
+		return nil, errors.New("--deploy-workflow is required with --deploy-source workflows")
+	}
+	if source != analyze.DeploySourceWorkflows && *deployWorkflow != "" {
+		return nil, errors.New("--deploy-workflow can only be used with --deploy-source workflows")
+	}
+	mttrFrom, err := analyze.ParseMTTRSource(*mttrSource)
+	if err != nil {
+		return nil, err
+	}
+	ownershipFrom, err := analyze.ParseOwnershipSource(*ownershipSource)
+	if err != nil {
+		return nil, err
+	}
+
+	var ignoreContent *string
+	if *ignoreFile != "" {
+		data, err := os.ReadFile(*ignoreFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ignore file: %w", err)
+		}
+		content := string(data)
+		ignoreContent = &content
+	}
+
+	appAuth, err := parseAppAuth(*appID, *installationID, *privateKey)
+	if err != nil {
+		return nil, err
+	}
+	if appAuth != nil && *tokenFile != "" {
+		return nil, errors.New("--token-file cannot be combined with the GitHub App flags")
+	}
+
+	var gitlabURL string
+	if provider == lokup.ProviderGitLab {
+		switch {
+		case appAuth != nil:
+			return nil, errors.New("the GitHub App flags cannot be used with --provider gitlab")
+		case *check:
+			return nil, errors.New("--check is not supported with --provider gitlab")
+		case *checkVulns:
+			return nil, errors.New("--check-vulns is not supported with --provider gitlab (dependencies are not analyzed)")
+		}
+		if gitlabURL, err = parseGitLabURL(*gitlabURLFlag); err != nil {
+			return nil, err
+		}
+	} else if *gitlabURLFlag != "" {
+		return nil, errors.New("--gitlab-url can only be used with --provider gitlab")
+	}
+
+	var fc fileConfig
+	if *configPath != "" {
+		loaded, err := loadConfigFile(*configPath)
+		if err != nil {
+			return nil, err
+		}
+		fc = *loaded
+	}
+
+	var baseline *report.Baseline
+	if *baselinePath != "" {
+		loaded, err := report.LoadBaseline(*baselinePath)
+		if err != nil {
+			return nil, err
+		}
+		baseline = loaded
 	}
 
-	owner, repo, err := parseRepository(positionalArgs[0])
+	reportFormat, err := report.ParseFormat(*format)
 	if err != nil {
 		return nil, err
 	}
 
+	reportLang, err := i18n.Parse(*lang)
+	if err != nil {
+		return nil, err
+	}
+
+	// 出力先未指定なら形式に合わせた拡張子にする（--output-dir のときはディレクトリにまとめる）
+	outputPath := *output
+	if *outputDir != "" {
+		if outputPath != "" {
+			return nil, errors.New("--output cannot be combined with --output-dir")
+		}
+		if *csvDir != "" {
+			return nil, errors.New("--csv-dir cannot be combined with --output-dir (the CSV files are written into the output directory)")
+		}
+	} else if outputPath == "" {
+		outputPath = "report" + reportFormat.Ext()
+	}
+	if *rollup == report.StdoutPath {
+		return nil, errors.New("--rollup must be a file path (the report itself can be written to stdout)")
+	}
+	if *rollup != "" && (*rollup == outputPath || (*outputDir != "" && filepath.Clean(*rollup) == filepath.Join(*outputDir, "index.html"))) {
+		return nil, fmt.Errorf("--rollup %s would overwrite the report", *rollup)
+	}
+
+	repos := make([]domain.Repository, 0, len(positionalArgs))
+	for _, arg := range positionalArgs {
+		owner, repo, err := parseRepository(arg)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, domain.NewRepository(owner, repo))
+	}
+	if *reposFromFile != "" {
+		listed, err := readRepositoryList(*reposFromFile)
+		if err != nil {
+			return nil, err
+		}
+		repos = appendUniqueRepositories(repos, listed)
+	}
+
+	if len(repos) < 1 {
+		fs.Usage()
+		return nil, errors.New("repository argument required")
+	}
+
+	var postURL string
+	if *postURLFlag != "" {
+		if postURL, err = parsePostURL(*postURLFlag); err != nil {
+			return nil, err
+		}
+	} else if len(postHeaders) > 0 {
+		return nil, errors.New("--post-header can only be used with --post-url")
+	}
+
 	return &Config{
-		Owner:  owner,
-		Repo:   repo,
-		Output: *output,
-		Days:   *days,
+		Repositories:   repos,
+		Output:         outputPath,
+		OutputDir:      *outputDir,
+		Format:         reportFormat,
+		Days:           *days,
+		Period:         period,
+		Clock:          now,
+		FailUnder:      *failUnder,
+		CommitLimit:    *commitLimit,
+		Location:       location,
+		CacheTTL:       *cacheTTL,
+		Verbose:        *verbose,
+		Quiet:          *quiet,
+		NoColor:        *noColor,
+		StalePRDays:    *stalePRDays,
+		TopFiles:       *topFiles,
+		Lang:           reportLang,
+		Path:           scopePath,
+		Branch:         strings.TrimSpace(*branch),
+		CSVDir:         *csvDir,
+		Rollup:         *rollup,
+		DeploySource:   source,
+		DeployWorkflow: *deployWorkflow,
+		MTTRSource:     mttrFrom,
+		IgnoreFile:     ignoreContent,
+		AppAuth:        appAuth,
+		TokenFile:      *tokenFile,
+		Concurrency:    *concurrency,
+		TodoScanFiles:  todoScanFiles,
+		CheckVulns:     *checkVulns,
+		Quick:          *quick,
+		IncludeDrafts:  *includeDrafts,
+		Check:          *check,
+		Explain:        *explain,
+		History:        *history,
+		HistoryWindow:  *window,
+
+		CategoryWeights: fc.CategoryWeights,
+		FailureLabels:   fc.FailureLabels,
+		Penalties:       fc.severityPenalties(),
+		DORABands:       fc.doraRatingBands(),
+		BranchPrefixes:  fc.branchPrefixes(),
+		NonSourceExts:   fc.NonSourceExtensions,
+		Baseline:        baseline,
+
+		CoAuthorshipMitigation: fc.CoAuthorshipMitigatesOwnership,
+		TestFilePatterns:       fc.TestFilePatterns,
+
+		OwnershipSource: ownershipFrom,
+
+		PostURL:     postURL,
+		PostHeaders: postHeaders.header(),
+
+		Provider:  provider,
+		GitLabURL: gitlabURL,
 	}, nil
 }
 
+// parseScopePath は --path の値を "services/billing" の形にそろえる。
+// リポジトリの外を指す ".." は受け付けない。
+func parseScopePath(s string) (string, error) {
+	p := strings.Trim(strings.TrimPrefix(strings.TrimSpace(s), "./"), "/")
+	if slices.Contains(strings.Split(p, "/"), "..") {
+		return "", fmt.Errorf("--path must be a directory inside the repository: %s", s)
+	}
+	return p, nil
+}
+
 // splitArgs は引数をフラグ引数と位置引数に分離する。
 // Go の flag パッケージが位置引数の後のフラグを無視する問題を回避する。
-func splitArgs(args []string) (flagArgs, positionalArgs []string) {
+func splitArgs(fs *flag.FlagSet, args []string) (flagArgs, positionalArgs []string) {
 	for i := 0; i < len(args); i++ {
 		if strings.HasPrefix(args[i], "-") {
 			flagArgs = append(flagArgs, args[i])
-			// フラグの値（次の引数）も一緒に取る
-			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			// フラグの値（次の引数）も一緒に取る（bool フラグと --flag=value は値を取らない）
+			if takesValue(fs, args[i]) && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
 				i++
 				flagArgs = append(flagArgs, args[i])
 			}
@@ -241,6 +886,79 @@ func splitArgs(args []string) (flagArgs, positionalArgs []string) {
 	return
 }
 
+// takesValue はフラグが次の引数を値として取るかを返す。
+func takesValue(fs *flag.FlagSet, arg string) bool {
+	name := strings.TrimLeft(arg, "-")
+	if strings.Contains(name, "=") {
+		return false
+	}
+	f := fs.Lookup(name)
+	if f == nil {
+		return true
+	}
+	if bf, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && bf.IsBoolFlag() {
+		return false
+	}
+	return true
+}
+
+// isFlagSet はフラグがコマンドラインで明示的に指定されたかを返す。
+func isFlagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// dateOnlyLayout は --since / --until で受け付ける日付だけの書式。
+const dateOnlyLayout = "2006-01-02"
+
+// parsePeriod は --since / --until の値から分析期間を組み立てる。
+// until が空なら now までとする。日付だけの until はその日の終わり（翌日 0 時）までを含む。
+func parsePeriod(since, until string, loc *time.Location, now time.Time) (domain.DateRange, error) {
+	if since == "" {
+		return domain.DateRange{}, errors.New("--until requires --since")
+	}
+	from, _, err := parsePeriodBound(since, loc)
+	if err != nil {
+		return domain.DateRange{}, fmt.Errorf("invalid --since: %w", err)
+	}
+
+	to := now
+	if until != "" {
+		t, dateOnly, err := parsePeriodBound(until, loc)
+		if err != nil {
+			return domain.DateRange{}, fmt.Errorf("invalid --until: %w", err)
+		}
+		if dateOnly {
+			t = t.AddDate(0, 0, 1)
+		}
+		to = t
+	}
+
+	if !from.Before(to) {
+		return domain.DateRange{}, fmt.Errorf("--since must be before --until: %s >= %s",
+			from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+	return domain.NewDateRange(from, to), nil
+}
+
+// parsePeriodBound は RFC3339 または YYYY-MM-DD の日時を解析する。
+// dateOnly は日付だけの書式だったかを返す。
+func parsePeriodBound(s string, loc *time.Location) (t time.Time, dateOnly bool, err error) {
+	if t, err := time.ParseInLocation(dateOnlyLayout, s, loc); err == nil {
+		return t, true, nil
+	}
+	t, err = time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%q is neither RFC3339 nor YYYY-MM-DD", s)
+	}
+	return t, false, nil
+}
+
 // parseRepository は "owner/repo" 形式の文字列を分解する。
 func parseRepository(s string) (owner, repo string, err error) {
 	parts := strings.Split(s, "/")
@@ -261,16 +979,103 @@ func parseRepository(s string) (owner, repo string, err error) {
 	return owner, repo, nil
 }
 
+// readRepositoryList は --repos-from-file のファイルから "owner/repo" を1行1件で読み込む。
+// 空行と "#" 以降（行全体・行末のコメント）は無視する。不正な行は行番号付きのエラーにする。
+func readRepositoryList(path string) ([]domain.Repository, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read repository list: %w", err)
+	}
+	var repos []domain.Repository
+	for i, line := range strings.Split(string(data), "\n") {
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		owner, repo, err := parseRepository(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, i+1, err)
+		}
+		repos = append(repos, domain.NewRepository(owner, repo))
+	}
+	return repos, nil
+}
+
+// appendUniqueRepositories は repos にまだないリポジトリだけを順に追加する。
+// 引数とファイルの両方に書いたリポジトリを二重に分析しないため（大文字小文字は区別しない）。
+func appendUniqueRepositories(repos, more []domain.Repository) []domain.Repository {
+	seen := make(map[string]bool, len(repos)+len(more))
+	for _, r := range repos {
+		seen[strings.ToLower(r.FullName())] = true
+	}
+	for _, r := range more {
+		key := strings.ToLower(r.FullName())
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		repos = append(repos, r)
+	}
+	return repos
+}
+
+// parseAppAuth は GitHub App 用のフラグを検証し、秘密鍵を読み込む。
+// 3つとも未指定なら nil を返す（通常のトークンで認証する）。
+func parseAppAuth(appID, installationID int64, privateKeyPath string) (*github.AppAuth, error) {
+	if appID == 0 && installationID == 0 && privateKeyPath == "" {
+		return nil, nil
+	}
+	if appID <= 0 || installationID <= 0 || privateKeyPath == "" {
+		return nil, errors.New("--app-id, --installation-id and --private-key must be given together")
+	}
+	data, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+	key, err := github.ParseAppPrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key %s: %w", privateKeyPath, err)
+	}
+	return &github.AppAuth{AppID: appID, InstallationID: installationID, PrivateKey: key}, nil
+}
+
+// resolveToken は分析に使うトークンを決める。
+// --provider gitlab なら lookupGitLabToken に従う。GitHub App の指定があればインストールトークンを発行し、
+// なければ resolveGitHubToken に従う。
+func resolveToken(ctx context.Context, config *Config) (string, error) {
+	if config.Provider == lokup.ProviderGitLab {
+		return lookupGitLabToken(config.TokenFile, os.Getenv, os.Stdin)
+	}
+	if config.AppAuth == nil {
+		return resolveGitHubToken(config.TokenFile)
+	}
+	logger := newLogger(os.Stderr, config.Verbose)
+	client := github.NewClient("", github.WithLogger(logger))
+	token, expiresAt, err := client.CreateInstallationToken(ctx, *config.AppAuth)
+	if err != nil {
+		return "", err
+	}
+	logger.Debug("installation token created", "installation", config.AppAuth.InstallationID, "expires_at", expiresAt)
+	return token, nil
+}
+
 // resolveGitHubToken は GitHub トークンを取得する。
-// 優先順位: GITHUB_TOKEN 環境変数 → gh auth token → 対話的ログイン
-func resolveGitHubToken() (string, error) {
-	// 1. 環境変数
-	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+// 優先順位: --token-file → GITHUB_TOKEN_FILE → GITHUB_TOKEN 環境変数 → gh auth token → 対話的ログイン
+func resolveGitHubToken(tokenFile string) (string, error) {
+	// 1. トークンファイル・環境変数
+	token, err := lookupToken(tokenFile, os.Getenv, os.Stdin)
+	if err != nil {
+		return "", err
+	}
+	if token != "" {
 		return token, nil
 	}
 
 	// 2. gh auth token（既にログイン済みの場合）
-	token, err := ghAuthToken()
+	token, err = ghAuthToken()
 	if err == nil && token != "" {
 		return token, nil
 	}
@@ -280,9 +1085,9 @@ func resolveGitHubToken() (string, error) {
 		return "", fmt.Errorf("GitHub CLI (gh) is required\n\n  Install: winget install GitHub.cli\n  Or set GITHUB_TOKEN environment variable")
 	}
 
-	// 4. 対話的にログインを促す
-	fmt.Println("GitHub authentication is required.")
-	fmt.Print("Launch GitHub login? (Y/n): ")
+	// 4. 対話的にログインを促す（--output - で stdout を使う場合があるためプロンプトは stderr に出す）
+	fmt.Fprintln(os.Stderr, "GitHub authentication is required.")
+	fmt.Fprint(os.Stderr, "Launch GitHub login? (Y/n): ")
 
 	var answer string
 	fmt.Scanln(&answer) //nolint:errcheck // 対話的入力、エラー時はデフォルト動作で問題ない
@@ -294,7 +1099,7 @@ func resolveGitHubToken() (string, error) {
 	// gh auth login を対話的に実行
 	cmd := exec.Command("gh", "auth", "login")
 	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = os.Stderr
 	cmd.Stderr = os.Stderr
 	if err := cmd.Run(); err != nil {
 		return "", fmt.Errorf("GitHub login failed: %w", err)
@@ -306,7 +1111,46 @@ func resolveGitHubToken() (string, error) {
 		return "", errors.New("failed to retrieve token after login")
 	}
 
-	fmt.Println()
+	fmt.Fprintln(os.Stderr)
+	return token, nil
+}
+
+// tokenFileEnv はトークンを書いたファイルのパスを指定する環境変数（シークレットをファイルでマウントする環境向け）。
+const tokenFileEnv = "GITHUB_TOKEN_FILE"
+
+// lookupToken は gh にフォールバックする前に、ファイルと環境変数からトークンを探す。
+// 優先順位: tokenFile（--token-file）→ GITHUB_TOKEN_FILE → GITHUB_TOKEN。見つからなければ空文字を返す。
+// 指定されたファイルが読めない・空の場合は、次の候補に進まずエラーにする。
+func lookupToken(tokenFile string, getenv func(string) string, stdin io.Reader) (string, error) {
+	if tokenFile != "" {
+		return readTokenFile(tokenFile, stdin)
+	}
+	if path := getenv(tokenFileEnv); path != "" {
+		token, err := readTokenFile(path, stdin)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", tokenFileEnv, err)
+		}
+		return token, nil
+	}
+	return getenv("GITHUB_TOKEN"), nil
+}
+
+// readTokenFile はファイル（"-" なら stdin）からトークンを読み、前後の空白・改行を除いて返す。
+func readTokenFile(path string, stdin io.Reader) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("token file is empty: %s", path)
+	}
 	return token, nil
 }
 
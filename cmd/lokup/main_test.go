@@ -1,7 +1,25 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/features/analyze"
+	"github.com/ryuka-games/lokup/features/report"
+	"github.com/ryuka-games/lokup/infrastructure/github"
+	"github.com/ryuka-games/lokup/shared/i18n"
 )
 
 func TestParseArgs(t *testing.T) {
@@ -15,42 +33,167 @@ func TestParseArgs(t *testing.T) {
 			name: "basic repository",
 			args: []string{"facebook/react"},
 			want: &Config{
-				Owner:  "facebook",
-				Repo:   "react",
-				Output: "report.html",
-				Days:   30,
+				Repositories: []domain.Repository{domain.NewRepository("facebook", "react")},
+				Output:       "report.html",
+				Format:       report.FormatHTML,
+				Days:         30,
+				CommitLimit:  analyze.DefaultMaxCommitDetails,
 			},
 		},
 		{
 			name: "with output flag",
 			args: []string{"facebook/react", "--output", "custom.html"},
 			want: &Config{
-				Owner:  "facebook",
-				Repo:   "react",
-				Output: "custom.html",
-				Days:   30,
+				Repositories: []domain.Repository{domain.NewRepository("facebook", "react")},
+				Output:       "custom.html",
+				Format:       report.FormatHTML,
+				Days:         30,
+				CommitLimit:  analyze.DefaultMaxCommitDetails,
 			},
 		},
 		{
 			name: "with days flag",
 			args: []string{"facebook/react", "--days", "90"},
 			want: &Config{
-				Owner:  "facebook",
-				Repo:   "react",
-				Output: "report.html",
-				Days:   90,
+				Repositories: []domain.Repository{domain.NewRepository("facebook", "react")},
+				Output:       "report.html",
+				Format:       report.FormatHTML,
+				Days:         90,
+				CommitLimit:  analyze.DefaultMaxCommitDetails,
 			},
 		},
 		{
 			name: "with all flags",
 			args: []string{"facebook/react", "--output", "out.html", "--days", "7"},
 			want: &Config{
-				Owner:  "facebook",
-				Repo:   "react",
-				Output: "out.html",
-				Days:   7,
+				Repositories: []domain.Repository{domain.NewRepository("facebook", "react")},
+				Output:       "out.html",
+				Format:       report.FormatHTML,
+				Days:         7,
+				CommitLimit:  analyze.DefaultMaxCommitDetails,
+			},
+		},
+		{
+			name: "multiple repositories",
+			args: []string{"org/a", "org/b", "--days", "14", "org/c"},
+			want: &Config{
+				Repositories: []domain.Repository{
+					domain.NewRepository("org", "a"),
+					domain.NewRepository("org", "b"),
+					domain.NewRepository("org", "c"),
+				},
+				Output:      "report.html",
+				Format:      report.FormatHTML,
+				Days:        14,
+				CommitLimit: analyze.DefaultMaxCommitDetails,
+			},
+		},
+		{
+			name: "json format",
+			args: []string{"facebook/react", "--format", "json"},
+			want: &Config{
+				Repositories: []domain.Repository{domain.NewRepository("facebook", "react")},
+				Output:       "report.json",
+				Format:       report.FormatJSON,
+				Days:         30,
+				CommitLimit:  analyze.DefaultMaxCommitDetails,
+			},
+		},
+		{
+			name: "json format with output flag",
+			args: []string{"facebook/react", "--format", "json", "--output", "out/{repo}.json"},
+			want: &Config{
+				Repositories: []domain.Repository{domain.NewRepository("facebook", "react")},
+				Output:       "out/{repo}.json",
+				Format:       report.FormatJSON,
+				Days:         30,
+				CommitLimit:  analyze.DefaultMaxCommitDetails,
 			},
 		},
+		{
+			name: "with fail-under flag",
+			args: []string{"facebook/react", "--fail-under", "60"},
+			want: &Config{
+				Repositories: []domain.Repository{domain.NewRepository("facebook", "react")},
+				Output:       "report.html",
+				Format:       report.FormatHTML,
+				Days:         30,
+				CommitLimit:  analyze.DefaultMaxCommitDetails,
+				FailUnder:    60,
+			},
+		},
+		{
+			name:    "fail-under out of range",
+			args:    []string{"facebook/react", "--fail-under", "101"},
+			wantErr: true,
+		},
+		{
+			name: "with max-commit-details flag",
+			args: []string{"facebook/react", "--max-commit-details", "500"},
+			want: &Config{
+				Repositories: []domain.Repository{domain.NewRepository("facebook", "react")},
+				Output:       "report.html",
+				Format:       report.FormatHTML,
+				Days:         30,
+				CommitLimit:  500,
+			},
+		},
+		{
+			name:    "max-commit-details zero",
+			args:    []string{"facebook/react", "--max-commit-details", "0"},
+			wantErr: true,
+		},
+		{
+			name:    "stale-pr-days zero",
+			args:    []string{"facebook/react", "--stale-pr-days", "0"},
+			wantErr: true,
+		},
+		{
+			name:    "top-files zero",
+			args:    []string{"facebook/react", "--top-files", "0"},
+			wantErr: true,
+		},
+		{
+			name:    "concurrency zero",
+			args:    []string{"facebook/react", "--concurrency", "0"},
+			wantErr: true,
+		},
+		{
+			name:    "todo max files zero",
+			args:    []string{"facebook/react", "--scan-todos", "--todo-max-files", "0"},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported lang",
+			args:    []string{"facebook/react", "--lang", "fr"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid timezone",
+			args:    []string{"facebook/react", "--timezone", "Mars/Olympus"},
+			wantErr: true,
+		},
+		{
+			name: "markdown format",
+			args: []string{"facebook/react", "--format", "md"},
+			want: &Config{
+				Repositories: []domain.Repository{domain.NewRepository("facebook", "react")},
+				Output:       "report.md",
+				Format:       report.FormatMarkdown,
+				Days:         30,
+				CommitLimit:  analyze.DefaultMaxCommitDetails,
+			},
+		},
+		{
+			name:    "unsupported format",
+			args:    []string{"facebook/react", "--format", "xml"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid repository among multiple",
+			args:    []string{"org/a", "invalid"},
+			wantErr: true,
+		},
 		{
 			name:    "missing repository",
 			args:    []string{},
@@ -75,7 +218,7 @@ func TestParseArgs(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := parseArgs(tt.args)
+			got, err := parseArgs(tt.args, time.Now)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("parseArgs() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -83,18 +226,1084 @@ func TestParseArgs(t *testing.T) {
 			if tt.wantErr {
 				return
 			}
-			if got.Owner != tt.want.Owner {
-				t.Errorf("Owner = %q, want %q", got.Owner, tt.want.Owner)
+			if len(got.Repositories) != len(tt.want.Repositories) {
+				t.Fatalf("Repositories len = %d, want %d", len(got.Repositories), len(tt.want.Repositories))
 			}
-			if got.Repo != tt.want.Repo {
-				t.Errorf("Repo = %q, want %q", got.Repo, tt.want.Repo)
+			for i, r := range got.Repositories {
+				if r != tt.want.Repositories[i] {
+					t.Errorf("Repositories[%d] = %q, want %q", i, r.FullName(), tt.want.Repositories[i].FullName())
+				}
 			}
 			if got.Output != tt.want.Output {
 				t.Errorf("Output = %q, want %q", got.Output, tt.want.Output)
 			}
+			if got.Format != tt.want.Format {
+				t.Errorf("Format = %q, want %q", got.Format, tt.want.Format)
+			}
 			if got.Days != tt.want.Days {
 				t.Errorf("Days = %d, want %d", got.Days, tt.want.Days)
 			}
+			if got.CommitLimit != tt.want.CommitLimit {
+				t.Errorf("CommitLimit = %d, want %d", got.CommitLimit, tt.want.CommitLimit)
+			}
+			if got.FailUnder != tt.want.FailUnder {
+				t.Errorf("FailUnder = %d, want %d", got.FailUnder, tt.want.FailUnder)
+			}
+		})
+	}
+}
+
+func TestParseArgs_Timezone(t *testing.T) {
+	got, err := parseArgs([]string{"facebook/react", "--timezone", "Asia/Tokyo"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.Location == nil || got.Location.String() != "Asia/Tokyo" {
+		t.Errorf("Location = %v, want Asia/Tokyo", got.Location)
+	}
+
+	got, err = parseArgs([]string{"facebook/react"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.Location != nil {
+		t.Errorf("Location = %v, want nil (commit's own offset)", got.Location)
+	}
+}
+
+func TestParseArgs_Path(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{[]string{"org/mono"}, "", false},
+		{[]string{"org/mono", "--path", "services/billing"}, "services/billing", false},
+		{[]string{"org/mono", "--path", "./services/billing/"}, "services/billing", false},
+		{[]string{"org/mono", "--path", "../other"}, "", true},
+		{[]string{"org/mono", "--path", "services/../../etc"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			got, err := parseArgs(tt.args, time.Now)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("parseArgs() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseArgs() error = %v", err)
+			}
+			if got.Path != tt.want {
+				t.Errorf("Path = %q, want %q", got.Path, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseArgs_DeploySource(t *testing.T) {
+	tests := []struct {
+		args         []string
+		wantSource   analyze.DeploySource
+		wantWorkflow string
+		wantErr      bool
+	}{
+		{[]string{"facebook/react"}, analyze.DeploySourceReleases, "", false},
+		{[]string{"facebook/react", "--deploy-source", "tags"}, analyze.DeploySourceTags, "", false},
+		{[]string{"facebook/react", "--deploy-source", "workflows", "--deploy-workflow", "deploy.yml"}, analyze.DeploySourceWorkflows, "deploy.yml", false},
+		{[]string{"facebook/react", "--deploy-source", "workflows"}, "", "", true},
+		{[]string{"facebook/react", "--deploy-workflow", "deploy.yml"}, "", "", true},
+		{[]string{"facebook/react", "--deploy-source", "pages"}, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			got, err := parseArgs(tt.args, time.Now)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("parseArgs() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseArgs() error = %v", err)
+			}
+			if got.DeploySource != tt.wantSource || got.DeployWorkflow != tt.wantWorkflow {
+				t.Errorf("DeploySource = %q, DeployWorkflow = %q, want %q, %q", got.DeploySource, got.DeployWorkflow, tt.wantSource, tt.wantWorkflow)
+			}
+		})
+	}
+}
+
+func TestParseArgs_MTTRSource(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    analyze.MTTRSource
+		wantErr bool
+	}{
+		{[]string{"facebook/react"}, analyze.MTTRSourceCreated, false},
+		{[]string{"facebook/react", "--mttr-source", "events"}, analyze.MTTRSourceEvents, false},
+		{[]string{"facebook/react", "--mttr-source", "timeline"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			got, err := parseArgs(tt.args, time.Now)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("parseArgs() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseArgs() error = %v", err)
+			}
+			if got.MTTRSource != tt.want {
+				t.Errorf("MTTRSource = %q, want %q", got.MTTRSource, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseArgs_OwnershipSource(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    analyze.OwnershipSource
+		wantErr bool
+	}{
+		{[]string{"facebook/react"}, analyze.OwnershipSourceLifetime, false},
+		{[]string{"facebook/react", "--ownership-source", "window"}, analyze.OwnershipSourceWindow, false},
+		{[]string{"facebook/react", "--ownership-source", "recent"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			got, err := parseArgs(tt.args, time.Now)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("parseArgs() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseArgs() error = %v", err)
+			}
+			if got.OwnershipSource != tt.want {
+				t.Errorf("OwnershipSource = %q, want %q", got.OwnershipSource, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseArgs_CSVDir(t *testing.T) {
+	got, err := parseArgs([]string{"facebook/react", "--csv-dir", "exports"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.CSVDir != "exports" {
+		t.Errorf("CSVDir = %q, want exports", got.CSVDir)
+	}
+}
+
+func TestParseArgs_Rollup(t *testing.T) {
+	got, err := parseArgs([]string{"facebook/react", "golang/go", "--output", "reports/{repo}.html", "--rollup", "reports/rollup.html"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.Rollup != "reports/rollup.html" {
+		t.Errorf("Rollup = %q, want reports/rollup.html", got.Rollup)
+	}
+
+	for _, args := range [][]string{
+		{"facebook/react", "--rollup", "-"},
+		{"facebook/react", "--output", "all.html", "--rollup", "all.html"},
+		{"facebook/react", "--output-dir", "site", "--rollup", "site/index.html"},
+	} {
+		if _, err := parseArgs(args, time.Now); err == nil {
+			t.Errorf("parseArgs(%v): want error", args)
+		}
+	}
+}
+
+func TestConfig_RollupReportPath(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   string
+	}{
+		{"html", Config{Output: "reports/{repo}.html", Format: report.FormatHTML}, "reports/{repo}.html"},
+		{"output dir", Config{OutputDir: "site", Format: report.FormatJSON}, filepath.Join("site", "index.html")},
+		{"non-HTML output", Config{Output: "report.json", Format: report.FormatJSON}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.rollupReportPath(); got != tt.want {
+				t.Errorf("rollupReportPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseArgs_OutputDir(t *testing.T) {
+	got, err := parseArgs([]string{"facebook/react", "--output-dir", "reports", "--format", "json"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.OutputDir != "reports" {
+		t.Errorf("OutputDir = %q, want reports", got.OutputDir)
+	}
+	// ディレクトリにまとめるときは単一ファイルの既定の出力先を埋めない
+	if got.Output != "" {
+		t.Errorf("Output = %q, want empty", got.Output)
+	}
+
+	for _, args := range [][]string{
+		{"facebook/react", "--output-dir", "reports", "--output", "report.html"},
+		{"facebook/react", "--output-dir", "reports", "--csv-dir", "exports"},
+	} {
+		if _, err := parseArgs(args, time.Now); err == nil {
+			t.Errorf("parseArgs(%v): want error", args)
+		}
+	}
+}
+
+func TestParseArgs_Concurrency(t *testing.T) {
+	got, err := parseArgs([]string{"facebook/react"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.Concurrency != github.DefaultConcurrency {
+		t.Errorf("default Concurrency = %d, want %d", got.Concurrency, github.DefaultConcurrency)
+	}
+
+	got, err = parseArgs([]string{"facebook/react", "--concurrency", "2"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.Concurrency != 2 {
+		t.Errorf("Concurrency = %d, want 2", got.Concurrency)
+	}
+}
+
+func TestParseArgs_ScanTodos(t *testing.T) {
+	got, err := parseArgs([]string{"facebook/react"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.TodoScanFiles != 0 {
+		t.Errorf("default TodoScanFiles = %d, want 0", got.TodoScanFiles)
+	}
+
+	got, err = parseArgs([]string{"facebook/react", "--scan-todos"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.TodoScanFiles != analyze.DefaultTodoScanMaxFiles {
+		t.Errorf("TodoScanFiles = %d, want %d", got.TodoScanFiles, analyze.DefaultTodoScanMaxFiles)
+	}
+
+	got, err = parseArgs([]string{"facebook/react", "--scan-todos", "--todo-max-files", "50"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.TodoScanFiles != 50 {
+		t.Errorf("TodoScanFiles = %d, want 50", got.TodoScanFiles)
+	}
+}
+
+func TestParseArgs_CheckVulns(t *testing.T) {
+	got, err := parseArgs([]string{"facebook/react"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.CheckVulns || got.options("", nil).CheckVulns {
+		t.Error("CheckVulns = true by default, want false")
+	}
+
+	got, err = parseArgs([]string{"facebook/react", "--check-vulns"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if !got.CheckVulns || !got.options("", nil).CheckVulns {
+		t.Error("CheckVulns = false with --check-vulns, want true")
+	}
+}
+
+func TestParseArgs_Explain(t *testing.T) {
+	got, err := parseArgs([]string{"facebook/react"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.Explain {
+		t.Error("Explain = true by default, want false")
+	}
+
+	got, err = parseArgs([]string{"facebook/react", "--explain"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if !got.Explain {
+		t.Error("Explain = false with --explain, want true")
+	}
+}
+
+func TestParseArgs_Quick(t *testing.T) {
+	got, err := parseArgs([]string{"facebook/react"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.Quick || got.options("", nil).Quick {
+		t.Error("Quick = true by default, want false")
+	}
+
+	got, err = parseArgs([]string{"facebook/react", "--quick"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if !got.Quick || !got.options("", nil).Quick {
+		t.Error("Quick = false with --quick, want true")
+	}
+
+	// クイックモードでは依存を取得しないため、脆弱性は調べられない
+	if _, err := parseArgs([]string{"facebook/react", "--quick", "--check-vulns"}, time.Now); err == nil {
+		t.Error("parseArgs() with --quick and --check-vulns: want error")
+	}
+}
+
+func TestParseArgs_IncludeDrafts(t *testing.T) {
+	got, err := parseArgs([]string{"facebook/react"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.IncludeDrafts || got.options("", nil).IncludeDrafts {
+		t.Error("IncludeDrafts = true by default, want false")
+	}
+
+	got, err = parseArgs([]string{"facebook/react", "--include-drafts"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if !got.IncludeDrafts || !got.options("", nil).IncludeDrafts {
+		t.Error("IncludeDrafts = false with --include-drafts, want true")
+	}
+}
+
+func TestParseArgs_Branch(t *testing.T) {
+	got, err := parseArgs([]string{"facebook/react", "--branch", "develop"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.Branch != "develop" || got.options("", nil).Branch != "develop" {
+		t.Errorf("Branch = %q, want develop", got.Branch)
+	}
+}
+
+func TestParseArgs_Check(t *testing.T) {
+	got, err := parseArgs([]string{"org/a", "org/b", "--check"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if !got.Check || len(got.Repositories) != 2 {
+		t.Errorf("Check = %v, Repositories = %v, want true and 2 repositories", got.Check, got.Repositories)
+	}
+}
+
+func TestParseArgs_AppAuth(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "app.pem")
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := os.WriteFile(keyPath, keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := parseArgs([]string{"org/repo", "--app-id", "42", "--installation-id", "678", "--private-key", keyPath}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.AppAuth == nil || got.AppAuth.AppID != 42 || got.AppAuth.InstallationID != 678 || !got.AppAuth.PrivateKey.Equal(key) {
+		t.Errorf("AppAuth = %+v, want app 42 / installation 678 with the key", got.AppAuth)
+	}
+
+	got, err = parseArgs([]string{"org/repo"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.AppAuth != nil {
+		t.Errorf("AppAuth = %+v, want nil without App flags", got.AppAuth)
+	}
+
+	notKey := filepath.Join(t.TempDir(), "not.pem")
+	if err := os.WriteFile(notKey, []byte("hello"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for name, args := range map[string][]string{
+		"missing installation": {"org/repo", "--app-id", "42", "--private-key", keyPath},
+		"missing key":          {"org/repo", "--app-id", "42", "--installation-id", "678"},
+		"missing app id":       {"org/repo", "--installation-id", "678", "--private-key", keyPath},
+		"key file not found":   {"org/repo", "--app-id", "42", "--installation-id", "678", "--private-key", filepath.Join(t.TempDir(), "missing.pem")},
+		"not a PEM key":        {"org/repo", "--app-id", "42", "--installation-id", "678", "--private-key", notKey},
+	} {
+		if _, err := parseArgs(args, time.Now); err == nil {
+			t.Errorf("%s: parseArgs() error = nil, want error", name)
+		}
+	}
+}
+
+func TestParseArgs_TokenFile(t *testing.T) {
+	got, err := parseArgs([]string{"org/repo", "--token-file", "/run/secrets/github_token"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.TokenFile != "/run/secrets/github_token" {
+		t.Errorf("TokenFile = %q, want /run/secrets/github_token", got.TokenFile)
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "app.pem")
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := os.WriteFile(keyPath, keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	args := []string{"org/repo", "--token-file", "token", "--app-id", "42", "--installation-id", "678", "--private-key", keyPath}
+	if _, err := parseArgs(args, time.Now); err == nil {
+		t.Error("--token-file with App flags: want error")
+	}
+}
+
+func TestLookupToken(t *testing.T) {
+	dir := t.TempDir()
+	writeToken := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	flagFile := writeToken("flag", "  from-flag\n")
+	envFile := writeToken("env", "from-env-file\n")
+	emptyFile := writeToken("empty", "\n")
+
+	tests := []struct {
+		name      string
+		tokenFile string
+		env       map[string]string
+		stdin     string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "flag wins over env",
+			tokenFile: flagFile,
+			env:       map[string]string{"GITHUB_TOKEN_FILE": envFile, "GITHUB_TOKEN": "from-env"},
+			want:      "from-flag",
+		},
+		{
+			name: "GITHUB_TOKEN_FILE wins over GITHUB_TOKEN",
+			env:  map[string]string{"GITHUB_TOKEN_FILE": envFile, "GITHUB_TOKEN": "from-env"},
+			want: "from-env-file",
+		},
+		{
+			name: "GITHUB_TOKEN",
+			env:  map[string]string{"GITHUB_TOKEN": "from-env"},
+			want: "from-env",
+		},
+		{
+			// 空なら呼び出し側が gh auth token にフォールバックする
+			name: "nothing set falls back to gh",
+			want: "",
+		},
+		{
+			name:      "stdin",
+			tokenFile: "-",
+			stdin:     "from-stdin\n",
+			want:      "from-stdin",
+		},
+		{
+			name:      "empty file is an error",
+			tokenFile: emptyFile,
+			env:       map[string]string{"GITHUB_TOKEN": "from-env"},
+			wantErr:   true,
+		},
+		{
+			name:    "missing GITHUB_TOKEN_FILE is an error",
+			env:     map[string]string{"GITHUB_TOKEN_FILE": filepath.Join(dir, "missing"), "GITHUB_TOKEN": "from-env"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			got, err := lookupToken(tt.tokenFile, getenv, strings.NewReader(tt.stdin))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("lookupToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("lookupToken() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseArgs_IgnoreFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".lokupignore")
+	if err := os.WriteFile(path, []byte("vendor/\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := parseArgs([]string{"owner/repo", "--ignore-file", path}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.IgnoreFile == nil || *got.IgnoreFile != "vendor/\n" {
+		t.Errorf("IgnoreFile = %v, want %q", got.IgnoreFile, "vendor/\n")
+	}
+
+	got, err = parseArgs([]string{"owner/repo"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.IgnoreFile != nil {
+		t.Errorf("IgnoreFile = %q, want nil", *got.IgnoreFile)
+	}
+
+	if _, err := parseArgs([]string{"owner/repo", "--ignore-file", filepath.Join(t.TempDir(), "missing")}, time.Now); err == nil {
+		t.Error("parseArgs() error = nil, want error for missing file")
+	}
+}
+
+func TestParseArgs_ReposFromFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	list := write("repos.txt", "# team repositories\norg/b\n\n  org/c  # payments\nORG/A\n")
+
+	got, err := parseArgs([]string{"org/a", "--repos-from-file", list}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	var names []string
+	for _, r := range got.Repositories {
+		names = append(names, r.FullName())
+	}
+	if want := []string{"org/a", "org/b", "org/c"}; !slices.Equal(names, want) {
+		t.Errorf("Repositories = %v, want %v", names, want)
+	}
+
+	// 位置引数なしでもファイルだけで指定できる
+	got, err = parseArgs([]string{"--repos-from-file", list, "--format", "json"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() without arguments error = %v", err)
+	}
+	if len(got.Repositories) != 3 {
+		t.Errorf("Repositories = %v, want 3 repositories", got.Repositories)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{"invalid line", write("invalid.txt", "org/a\nnot-a-repo\n"), "invalid.txt:2: invalid repository format"},
+		{"only comments", write("empty.txt", "# nothing yet\n\n"), "repository argument required"},
+		{"missing file", filepath.Join(dir, "missing.txt"), "failed to read repository list"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseArgs([]string{"--repos-from-file", tt.path}, time.Now)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseArgs() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseArgs_Period(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "dates include the whole until day",
+			args:     []string{"facebook/react", "--since", "2025-01-01", "--until", "2025-03-31", "--timezone", "UTC"},
+			wantFrom: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "dates use --timezone",
+			args:     []string{"facebook/react", "--timezone", "Asia/Tokyo", "--since", "2025-01-01", "--until", "2025-01-31"},
+			wantFrom: time.Date(2024, 12, 31, 15, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2025, 1, 31, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "rfc3339",
+			args:     []string{"facebook/react", "--since", "2025-01-01T09:00:00+09:00", "--until=2025-01-02T00:00:00Z"},
+			wantFrom: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{name: "since after until", args: []string{"facebook/react", "--since", "2025-02-01", "--until", "2025-01-01"}, wantErr: true},
+		{name: "until without since", args: []string{"facebook/react", "--until", "2025-01-01"}, wantErr: true},
+		{name: "combined with days", args: []string{"facebook/react", "--days", "30", "--since", "2025-01-01"}, wantErr: true},
+		{name: "invalid date", args: []string{"facebook/react", "--since", "2025/01/01"}, wantErr: true},
+		{name: "since in the future", args: []string{"facebook/react", "--since", "2999-01-01"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseArgs(tt.args, time.Now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Period == nil {
+				t.Fatal("Period = nil, want explicit range")
+			}
+			if !got.Period.From.Equal(tt.wantFrom) || !got.Period.To.Equal(tt.wantTo) {
+				t.Errorf("Period = %s ~ %s, want %s ~ %s", got.Period.From, got.Period.To, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+
+	// since だけなら現在まで
+	got, err := parseArgs([]string{"facebook/react", "--since", "2025-01-01"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.Period == nil || time.Since(got.Period.To) > time.Minute {
+		t.Errorf("Period = %v, want until now", got.Period)
+	}
+
+	// 指定がなければ --days から実行時に算出する
+	got, err = parseArgs([]string{"facebook/react", "--days", "7"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.Period != nil {
+		t.Errorf("Period = %v, want nil without --since/--until", got.Period)
+	}
+}
+
+func TestParseArgs_Clock(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 35, 0, 0, time.UTC)
+	got, err := parseArgs([]string{"facebook/react", "--since", "2025-03-01", "--timezone", "UTC"}, func() time.Time { return now })
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.Period == nil || !got.Period.To.Equal(now) {
+		t.Errorf("Period = %v, want until the injected clock %v", got.Period, now)
+	}
+	if c := got.options("", nil).Clock; c == nil || !c().Equal(now) {
+		t.Error("options() does not pass the injected clock to the analyzer")
+	}
+}
+
+func TestParseArgs_Lang(t *testing.T) {
+	tests := []struct {
+		args []string
+		want i18n.Lang
+	}{
+		{[]string{"facebook/react"}, i18n.Japanese},
+		{[]string{"facebook/react", "--lang", "en"}, i18n.English},
+		{[]string{"facebook/react", "--lang", "EN"}, i18n.English},
+		{[]string{"facebook/react", "--lang=ja"}, i18n.Japanese},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			got, err := parseArgs(tt.args, time.Now)
+			if err != nil {
+				t.Fatalf("parseArgs() error = %v", err)
+			}
+			if got.Lang != tt.want {
+				t.Errorf("Lang = %q, want %q", got.Lang, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseArgs_Cache(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "disabled by default", args: []string{"facebook/react"}, want: 0},
+		{name: "ttl", args: []string{"facebook/react", "--cache-ttl", "1h"}, want: time.Hour},
+		{name: "no-cache wins", args: []string{"facebook/react", "--cache-ttl", "1h", "--no-cache"}, want: 0},
+		// bool フラグの直後の位置引数をフラグ値として食わない
+		{name: "no-cache before repo", args: []string{"--no-cache", "facebook/react"}, want: 0},
+		{name: "verbose before repo", args: []string{"--verbose", "facebook/react", "--cache-ttl", "30m"}, want: 30 * time.Minute},
+		{name: "negative ttl", args: []string{"facebook/react", "--cache-ttl", "-1m"}, wantErr: true},
+		{name: "history enables cache", args: []string{"facebook/react", "--history", "6"}, want: time.Hour},
+		{name: "history keeps explicit ttl", args: []string{"facebook/react", "--history", "6", "--cache-ttl", "10m"}, want: 10 * time.Minute},
+		{name: "history with no-cache", args: []string{"facebook/react", "--history", "6", "--no-cache"}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseArgs(tt.args, time.Now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.CacheTTL != tt.want {
+				t.Errorf("CacheTTL = %s, want %s", got.CacheTTL, tt.want)
+			}
+			if len(got.Repositories) != 1 || got.Repositories[0].FullName() != "facebook/react" {
+				t.Errorf("Repositories = %v", got.Repositories)
+			}
+		})
+	}
+}
+
+func TestNewLogger(t *testing.T) {
+	var quiet strings.Builder
+	l := newLogger(&quiet, false)
+	l.Debug("fetched", "what", "commits")
+	l.Warn("list truncated")
+	if strings.Contains(quiet.String(), "fetched") {
+		t.Errorf("debug log written without --verbose:\n%s", quiet.String())
+	}
+	if !strings.Contains(quiet.String(), "list truncated") {
+		t.Errorf("warning not written:\n%s", quiet.String())
+	}
+
+	var verbose strings.Builder
+	newLogger(&verbose, true).Debug("fetched", "what", "commits")
+	if !strings.Contains(verbose.String(), "what=commits") {
+		t.Errorf("debug log not written with --verbose:\n%s", verbose.String())
+	}
+}
+
+func TestPrintResult(t *testing.T) {
+	newResult := func() *domain.AnalysisResult {
+		return &domain.AnalysisResult{
+			Repository:   domain.NewRepository("facebook", "react"),
+			OverallScore: domain.NewScore(76),
+			CategoryScores: map[domain.Category]domain.CategoryScore{
+				domain.CategoryVelocity: {Category: domain.CategoryVelocity, Score: domain.NewScore(85)},
+				domain.CategoryHealth:   {Category: domain.CategoryHealth, Score: domain.NewScore(35)},
+			},
+		}
+	}
+	insufficient := newResult()
+	insufficient.InsufficientData = true
+	community := newResult()
+	community.CommunityFiles = []domain.CommunityFile{
+		{Name: "CONTRIBUTING.md", Present: true},
+		{Name: "SECURITY.md", Present: false},
+		{Name: ".github/ISSUE_TEMPLATE", Present: false},
+		{Name: ".github/PULL_REQUEST_TEMPLATE.md", Present: true},
+	}
+	community.Metrics.CommunityHealthScore = 50
+	vulns := newResult()
+	vulns.Metrics.VulnCheckedDeps = 40
+	vulns.Metrics.VulnerableDepCount = 1
+	vulns.VulnerableDeps = []domain.VulnerableDep{
+		{Name: "lodash", Version: "4.17.20", Ecosystem: "npm", IDs: []string{"GHSA-29mw-wpgm-hmr9", "GHSA-35jh-r3h4-6jhm"}},
+	}
+	protected := newResult()
+	protected.BranchProtection = &domain.BranchProtection{Branch: "main", Protected: true, RequiredApprovals: 2, RequiredStatusChecks: true}
+	unprotected := newResult()
+	unprotected.BranchProtection = &domain.BranchProtection{Branch: "main"}
+	deps := newResult()
+	deps.Metrics.DependencyCount = 40
+	deps.Metrics.OutdatedDepRate = 12.5
+	deps.DependencyEcosystems = []domain.DependencyEcosystem{
+		{Ecosystem: "npm", Count: 30, Outdated: 5},
+		{Ecosystem: "go", Count: 10},
+	}
+	throughput := newResult()
+	throughput.Metrics.MergedPRCount = 15
+	throughput.Metrics.PRThroughputPerWeek = 3.5
+	reverts := newResult()
+	reverts.Metrics.TotalCommits = 50
+	reverts.Metrics.RevertCommitCount = 3
+	reverts.Metrics.RevertRate = 6
+	reverts.Metrics.FastRevertCount = 2
+	history := newResult()
+	history.History = []domain.HistoryPoint{
+		{Period: domain.NewDateRange(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)), InsufficientData: true},
+		{Period: domain.NewDateRange(time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)), OverallScore: domain.NewScore(68)},
+		{Period: domain.NewDateRange(time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC), time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)), OverallScore: domain.NewScore(76)},
+	}
+	snapshotHistory := newResult()
+	snapshotHistory.History = []domain.HistoryPoint{
+		{Period: domain.NewDateRange(time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)), OverallScore: domain.NewScore(68), CurrentSnapshot: true},
+		{Period: domain.NewDateRange(time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC), time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)), OverallScore: domain.NewScore(76)},
+	}
+	breakdown := newResult()
+	breakdown.CategoryScores[domain.CategoryVelocity] = domain.CategoryScore{
+		Category: domain.CategoryVelocity,
+		Score: domain.NewScoreWithBreakdown(85, []domain.ScoreBreakdownItem{
+			{Label: "Base score", Points: 100},
+			{Label: "Slow lead time", Points: -15, Detail: "10.0 days, target ≤7"},
+		}),
+	}
+
+	tests := []struct {
+		name        string
+		result      *domain.AnalysisResult
+		lang        i18n.Lang
+		explain     bool
+		want        []string
+		notWant     []string
+		wantHeadTop bool // Overall Health が Repository より前にあるか
+	}{
+		{
+			name:   "japanese grade description",
+			result: newResult(),
+			lang:   i18n.Japanese,
+			want: []string{
+				"Overall Health: 76/100 (B - 普通)",
+				"Repository: facebook/react",
+				"Velocity:    85/100 (A)",
+				"Health:      35/100 (D)",
+			},
+			wantHeadTop: true,
+		},
+		{
+			name:        "english grade description",
+			result:      newResult(),
+			lang:        i18n.English,
+			want:        []string{"Overall Health: 76/100 (B - Fair)"},
+			wantHeadTop: true,
+		},
+		{
+			name:    "insufficient data",
+			result:  insufficient,
+			lang:    i18n.Default,
+			want:    []string{"Overall Health: N/A"},
+			notWant: []string{"76/100", "--- Category Scores ---", "Community Health:"},
+		},
+		{
+			name:   "community health lists missing files",
+			result: community,
+			lang:   i18n.Default,
+			want:   []string{"Community Health:     50/100 (missing: SECURITY.md, .github/ISSUE_TEMPLATE)"},
+		},
+		{
+			name:   "dependency summary",
+			result: deps,
+			lang:   i18n.Default,
+			want:   []string{"Dependencies:         40 (12.5% outdated; npm 30, go 10)"},
+		},
+		{
+			name:   "vulnerable dependencies",
+			result: vulns,
+			lang:   i18n.Default,
+			want: []string{
+				"Vulnerable Deps:      1 (of 40 checked in OSV)",
+				"  - lodash@4.17.20: GHSA-29mw-wpgm-hmr9, GHSA-35jh-r3h4-6jhm",
+			},
+		},
+		{
+			name:   "branch protection unknown",
+			result: newResult(),
+			lang:   i18n.Default,
+			want:   []string{"Branch Protection:    unknown"},
+		},
+		{
+			name:   "branch protected",
+			result: protected,
+			lang:   i18n.Default,
+			want:   []string{"Branch Protection:    main protected (2 approvals required, status checks required)"},
+		},
+		{
+			name:   "branch not protected",
+			result: unprotected,
+			lang:   i18n.Default,
+			want:   []string{"Branch Protection:    main not protected"},
+		},
+		{
+			name:   "pr throughput",
+			result: throughput,
+			lang:   i18n.Default,
+			want:   []string{"PR Throughput:        3.5 PRs/week (15 merged)"},
+		},
+		{
+			name:    "explain prints score breakdown",
+			result:  breakdown,
+			lang:    i18n.English,
+			explain: true,
+			want:    []string{"Velocity:    85/100 (A)", "    +100  Base score\n", "     -15  Slow lead time (10.0 days, target ≤7)\n"},
+		},
+		{
+			name:    "breakdown hidden without explain",
+			result:  breakdown,
+			lang:    i18n.English,
+			notWant: []string{"Base score", "Slow lead time"},
+		},
+		{
+			name:   "fast reverts",
+			result: reverts,
+			lang:   i18n.Default,
+			want:   []string{"Revert:    3 commits (6.0%, 2 within 24h)"},
+		},
+		{
+			name:   "score history",
+			result: history,
+			lang:   i18n.Default,
+			want: []string{
+				"--- Score History ---\n2025-01-01 ~ 2025-01-31  N/A\n",
+				"2025-01-31 ~ 2025-03-02   68 (B)\n",
+				"2025-03-02 ~ 2025-04-01   76 (B)\n",
+			},
+		},
+		{
+			name:   "score history from the current snapshot",
+			result: snapshotHistory,
+			lang:   i18n.Default,
+			want: []string{
+				"2025-01-31 ~ 2025-03-02   68 (B) *\n",
+				"2025-03-02 ~ 2025-04-01   76 (B)\n",
+				"* scored with the current file tree",
+			},
+		},
+		{
+			name:    "no history",
+			result:  newResult(),
+			lang:    i18n.Default,
+			notWant: []string{"--- Score History ---"},
+		},
+		{
+			name:    "no dependencies",
+			result:  newResult(),
+			lang:    i18n.Default,
+			notWant: []string{"Dependencies:", "Vulnerable Deps:"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			summaryRenderer{lang: tt.lang, explain: tt.explain}.render(&b, tt.result)
+			out := b.String()
+
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output does not contain %q\n%s", want, out)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(out, notWant) {
+					t.Errorf("output contains %q\n%s", notWant, out)
+				}
+			}
+			if tt.wantHeadTop && strings.Index(out, "Overall Health:") > strings.Index(out, "Repository:") {
+				t.Errorf("Overall Health is not at the top\n%s", out)
+			}
+		})
+	}
+}
+
+func TestCheckFailUnder(t *testing.T) {
+	newResult := func(owner, name string, score int) *domain.AnalysisResult {
+		return &domain.AnalysisResult{
+			Repository:   domain.NewRepository(owner, name),
+			OverallScore: domain.NewScore(score),
+		}
+	}
+
+	tests := []struct {
+		name      string
+		results   []*domain.AnalysisResult
+		threshold int
+		wantCode  int
+	}{
+		{
+			name:      "disabled by default",
+			results:   []*domain.AnalysisResult{newResult("org", "a", 10)},
+			threshold: 0,
+			wantCode:  exitOK,
+		},
+		{
+			name:      "score above threshold",
+			results:   []*domain.AnalysisResult{newResult("org", "a", 75)},
+			threshold: 60,
+			wantCode:  exitOK,
+		},
+		{
+			name:      "score equal to threshold",
+			results:   []*domain.AnalysisResult{newResult("org", "a", 60)},
+			threshold: 60,
+			wantCode:  exitOK,
+		},
+		{
+			name:      "low score fails",
+			results:   []*domain.AnalysisResult{newResult("org", "a", 35)},
+			threshold: 60,
+			wantCode:  exitFailUnder,
+		},
+		{
+			name: "one of multiple repositories fails",
+			results: []*domain.AnalysisResult{
+				newResult("org", "a", 90),
+				newResult("org", "b", 40),
+			},
+			threshold: 60,
+			wantCode:  exitFailUnder,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkFailUnder(tt.results, tt.threshold)
+			if got := exitCode(err); got != tt.wantCode {
+				t.Errorf("exitCode() = %d, want %d (err = %v)", got, tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	if got := exitCode(nil); got != exitOK {
+		t.Errorf("exitCode(nil) = %d, want %d", got, exitOK)
+	}
+	if got := exitCode(errors.New("boom")); got != exitError {
+		t.Errorf("exitCode(error) = %d, want %d", got, exitError)
+	}
+	if got := exitCode(fmt.Errorf("%w (1 of 3 repositories analyzed)", errCanceled)); got != exitCanceled {
+		t.Errorf("exitCode(canceled) = %d, want %d", got, exitCanceled)
+	}
+}
+
+func TestCanceledError(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	networkErr := fmt.Errorf("facebook/react: %w", context.Canceled)
+	withProgress := fmt.Errorf("%w (1 of 3 repositories analyzed)", errCanceled)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want error
+	}{
+		{"success", canceled, nil, nil},
+		{"not canceled", context.Background(), networkErr, networkErr},
+		{"canceled network error", canceled, networkErr, errCanceled},
+		{"keeps progress", canceled, withProgress, withProgress},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canceledError(tt.ctx, tt.err); got != tt.want {
+				t.Errorf("canceledError() = %v, want %v", got, tt.want)
+			}
 		})
 	}
 }
@@ -160,3 +1369,63 @@ func TestParseRepository(t *testing.T) {
 		})
 	}
 }
+
+func TestParseArgs_Baseline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	if err := os.WriteFile(path, []byte(`{"schemaVersion": 1, "repository": "facebook/react"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := parseArgs([]string{"facebook/react", "--baseline", path}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.Baseline == nil {
+		t.Error("Baseline = nil, want loaded baseline")
+	}
+
+	got, err = parseArgs([]string{"facebook/react"}, time.Now)
+	if err != nil {
+		t.Fatalf("parseArgs() error = %v", err)
+	}
+	if got.Baseline != nil {
+		t.Error("Baseline should be nil without --baseline")
+	}
+
+	if _, err := parseArgs([]string{"facebook/react", "--baseline", filepath.Join(t.TempDir(), "missing.json")}, time.Now); err == nil {
+		t.Error("expected error for missing baseline file")
+	}
+}
+
+func TestParseArgs_History(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantN      int
+		wantWindow int
+		wantErr    bool
+	}{
+		{name: "disabled by default", args: []string{"facebook/react"}},
+		{name: "history", args: []string{"facebook/react", "--history", "6"}, wantN: 6},
+		{name: "history with window", args: []string{"facebook/react", "--history", "6", "--window", "30"}, wantN: 6, wantWindow: 30},
+		{name: "single window", args: []string{"facebook/react", "--history", "1"}, wantErr: true},
+		{name: "too many windows", args: []string{"facebook/react", "--history", "25"}, wantErr: true},
+		{name: "negative history", args: []string{"facebook/react", "--history", "-1"}, wantErr: true},
+		{name: "window without history", args: []string{"facebook/react", "--window", "30"}, wantErr: true},
+		{name: "zero window", args: []string{"facebook/react", "--history", "6", "--window", "0"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseArgs(tt.args, time.Now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.History != tt.wantN || got.HistoryWindow != tt.wantWindow {
+				t.Errorf("History = %d, HistoryWindow = %d, want %d, %d", got.History, got.HistoryWindow, tt.wantN, tt.wantWindow)
+			}
+		})
+	}
+}
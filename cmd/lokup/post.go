@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// postTimeout は分析結果の送信（--post-url）1回の待ち時間の上限。
+const postTimeout = 30 * time.Second
+
+// postErrorBodyLimit は送信に失敗したときにエラーに含めるレスポンス本文の長さの上限（バイト）。
+const postErrorBodyLimit = 512
+
+// headerFlag は繰り返し指定できる --post-header の値（"Name=Value"）。
+type headerFlag []string
+
+// String は flag.Value の実装（Usage の表示用）。
+func (h *headerFlag) String() string {
+	return strings.Join(*h, ", ")
+}
+
+// Set は "Name=Value" の形かを検証して追加する。
+func (h *headerFlag) Set(v string) error {
+	name, _, ok := strings.Cut(v, "=")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("header must be Name=Value: %q", v)
+	}
+	*h = append(*h, v)
+	return nil
+}
+
+// header は --post-header の値を HTTP ヘッダーに変換する（同じ名前を繰り返すと値を追加する）。
+func (h headerFlag) header() http.Header {
+	header := make(http.Header, len(h))
+	for _, v := range h {
+		name, value, _ := strings.Cut(v, "=")
+		header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	return header
+}
+
+// parsePostURL は --post-url が送信先として使える http(s) の URL かを検証する。
+func parsePostURL(s string) (string, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid --post-url: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("--post-url must be an http or https URL: %s", s)
+	}
+	return s, nil
+}
+
+// postResults は分析結果の JSON を endpoint に POST する（--post-url）。
+// header は Content-Type より優先する。2xx 以外の応答はステータスと本文の先頭を含むエラーにする。
+func postResults(ctx context.Context, client *http.Client, endpoint string, header http.Header, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "lokup")
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post results: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, postErrorBodyLimit))
+		if msg := strings.TrimSpace(string(snippet)); msg != "" {
+			return fmt.Errorf("post to %s failed: %s: %s", endpoint, resp.Status, msg)
+		}
+		return fmt.Errorf("post to %s failed: %s", endpoint, resp.Status)
+	}
+	return nil
+}
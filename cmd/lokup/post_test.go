@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPostResults(t *testing.T) {
+	var gotMethod, gotBody string
+	var gotHeader http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotMethod, gotBody, gotHeader = r.Method, string(b), r.Header.Clone()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	header := headerFlag{"Authorization=Bearer secret", "X-Source=ci"}.header()
+	if err := postResults(context.Background(), srv.Client(), srv.URL, header, []byte(`{"schemaVersion":1}`)); err != nil {
+		t.Fatalf("postResults() error = %v", err)
+	}
+	if gotMethod != http.MethodPost || gotBody != `{"schemaVersion":1}` {
+		t.Errorf("request = %s %q", gotMethod, gotBody)
+	}
+	for name, want := range map[string]string{
+		"Content-Type":  "application/json",
+		"Authorization": "Bearer secret",
+		"X-Source":      "ci",
+	} {
+		if got := gotHeader.Get(name); got != want {
+			t.Errorf("header %s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestPostResults_Errors(t *testing.T) {
+	t.Run("non-2xx status", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+		}))
+		defer srv.Close()
+
+		err := postResults(context.Background(), srv.Client(), srv.URL, nil, []byte("{}"))
+		if err == nil || !strings.Contains(err.Error(), "401 Unauthorized") || !strings.Contains(err.Error(), "invalid token") {
+			t.Errorf("postResults() error = %v, want status and response body", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		release := make(chan struct{})
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}))
+		defer srv.Close()
+		defer close(release)
+
+		client := srv.Client()
+		client.Timeout = 50 * time.Millisecond
+		if err := postResults(context.Background(), client, srv.URL, nil, []byte("{}")); err == nil {
+			t.Error("postResults() error = nil, want timeout error")
+		}
+	})
+}
+
+func TestHeaderFlag(t *testing.T) {
+	var h headerFlag
+	for _, v := range []string{"Authorization=Bearer a=b", " X-Team = core ", "X-Team=platform"} {
+		if err := h.Set(v); err != nil {
+			t.Fatalf("Set(%q) error = %v", v, err)
+		}
+	}
+	want := http.Header{
+		"Authorization": {"Bearer a=b"},
+		"X-Team":        {"core", "platform"},
+	}
+	if got := h.header(); !reflect.DeepEqual(got, want) {
+		t.Errorf("header() = %v, want %v", got, want)
+	}
+
+	for _, v := range []string{"Authorization", "=value"} {
+		if err := h.Set(v); err == nil {
+			t.Errorf("Set(%q) error = nil, want error", v)
+		}
+	}
+}
+
+func TestParseArgs_PostURL(t *testing.T) {
+	tests := []struct {
+		args       []string
+		wantURL    string
+		wantHeader http.Header
+		wantErr    bool
+	}{
+		{[]string{"facebook/react"}, "", http.Header{}, false},
+		{
+			[]string{"facebook/react", "--post-url", "https://dashboard.example.com/api", "--post-header", "Authorization=Bearer x"},
+			"https://dashboard.example.com/api", http.Header{"Authorization": {"Bearer x"}}, false,
+		},
+		{[]string{"facebook/react", "--post-url", "dashboard.example.com/api"}, "", nil, true},
+		{[]string{"facebook/react", "--post-url", "ftp://dashboard.example.com"}, "", nil, true},
+		{[]string{"facebook/react", "--post-header", "Authorization=Bearer x"}, "", nil, true},
+		{[]string{"facebook/react", "--post-url", "https://dashboard.example.com/api", "--post-header", "Authorization"}, "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			got, err := parseArgs(tt.args, time.Now)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("parseArgs() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseArgs() error = %v", err)
+			}
+			if got.PostURL != tt.wantURL || !reflect.DeepEqual(got.PostHeaders, tt.wantHeader) {
+				t.Errorf("PostURL = %q, PostHeaders = %v, want %q, %v", got.PostURL, got.PostHeaders, tt.wantURL, tt.wantHeader)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ryuka-games/lokup/features/analyze"
+)
+
+// spinnerInterval は通知がなくてもスピナーを回す間隔。
+const spinnerInterval = 100 * time.Millisecond
+
+// spinnerFrames はスピナーの各コマ。
+var spinnerFrames = []string{"|", "/", "-", `\`}
+
+// progressLine は分析の進捗を端末の1行に上書き表示する。
+//
+// ページングの長い取得などで通知が途切れても止まって見えないよう、スピナーは一定間隔で回す。
+// ログもこの行を消してから書き出し（Write）、表示が混ざらないようにする。
+// 端末以外に書くとエスケープシーケンスが残るため、stderr が端末のときだけ使う。
+type progressLine struct {
+	mu    sync.Mutex
+	w     io.Writer
+	text  string // 表示中の内容（空なら何も表示していない）
+	frame int
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newProgressLine は w に進捗を表示する progressLine を作り、スピナーを回し始める。
+// interval が 0 以下ならスピナーは通知のときだけ進む。
+func newProgressLine(w io.Writer, interval time.Duration) *progressLine {
+	p := &progressLine{w: w, stop: make(chan struct{}), done: make(chan struct{})}
+	if interval <= 0 {
+		close(p.done)
+		return p
+	}
+	go func() {
+		defer close(p.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.mu.Lock()
+				if p.text != "" {
+					p.frame++
+					p.draw()
+				}
+				p.mu.Unlock()
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+	return p
+}
+
+// Update は進捗の通知を受けて表示を更新する（analyze.ProgressFunc として渡す）。
+func (p *progressLine) Update(pr analyze.Progress) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.text = formatProgress(pr)
+	p.frame++
+	p.draw()
+}
+
+// Write は進捗の行を消してから b を書き、進捗を描き直す（ログの出力先にする）。
+func (p *progressLine) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.erase()
+	n, err := p.w.Write(b)
+	p.draw()
+	return n, err
+}
+
+// Clear は進捗の行を消す。結果を表示する前に呼ぶ。nil なら何もしない。
+func (p *progressLine) Clear() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.erase()
+	p.text = ""
+}
+
+// Close はスピナーを止めて進捗の行を消す。
+func (p *progressLine) Close() {
+	close(p.stop)
+	<-p.done
+	p.Clear()
+}
+
+// draw は現在の進捗を行頭から描く。呼び出し側で mu を持つ。
+func (p *progressLine) draw() {
+	if p.text == "" {
+		return
+	}
+	fmt.Fprintf(p.w, "\r\033[K%s %s", spinnerFrames[p.frame%len(spinnerFrames)], p.text)
+}
+
+// erase は表示中の進捗の行を消す。呼び出し側で mu を持つ。
+func (p *progressLine) erase() {
+	if p.text == "" {
+		return
+	}
+	fmt.Fprint(p.w, "\r\033[K")
+}
+
+// formatProgress は進捗を「owner/repo: fetching PR details 12/20」の形にする。
+func formatProgress(pr analyze.Progress) string {
+	s := fmt.Sprintf("%s: %s", pr.Repository.FullName(), pr.Phase)
+	if pr.Total > 0 {
+		s += fmt.Sprintf(" %d/%d", pr.Done, pr.Total)
+	}
+	return s
+}
+
+// isTerminal は f が端末（キャラクタデバイス）かを返す。
+// パイプやファイルへのリダイレクト、CI のログでは false になる。
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/features/analyze"
+)
+
+func TestFormatProgress(t *testing.T) {
+	repo := domain.NewRepository("facebook", "react")
+	tests := []struct {
+		name string
+		pr   analyze.Progress
+		want string
+	}{
+		{"with count", analyze.Progress{Repository: repo, Phase: analyze.PhasePRDetails, Done: 12, Total: 20}, "facebook/react: fetching PR details 12/20"},
+		{"without count", analyze.Progress{Repository: repo, Phase: analyze.PhaseAnalyzing}, "facebook/react: analyzing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatProgress(tt.pr); got != tt.want {
+				t.Errorf("formatProgress() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProgressLine(t *testing.T) {
+	var buf bytes.Buffer
+	p := newProgressLine(&buf, 0)
+	repo := domain.NewRepository("o", "r")
+
+	// 何も表示していなければログはそのまま書く
+	p.Write([]byte("before\n"))
+	if got := buf.String(); got != "before\n" {
+		t.Fatalf("output = %q, want plain log line", got)
+	}
+
+	buf.Reset()
+	p.Update(analyze.Progress{Repository: repo, Phase: analyze.PhaseCommitDetails, Done: 1, Total: 3})
+	if got := buf.String(); !strings.HasPrefix(got, "\r\033[K") || !strings.HasSuffix(got, "o/r: fetching commit details 1/3") {
+		t.Errorf("Update output = %q", got)
+	}
+
+	// ログは進捗の行を消してから書き、進捗を描き直す
+	buf.Reset()
+	p.Write([]byte("level=WARN msg=x\n"))
+	if got := buf.String(); !strings.HasPrefix(got, "\r\033[Klevel=WARN msg=x\n\r\033[K") || !strings.HasSuffix(got, "1/3") {
+		t.Errorf("Write output = %q", got)
+	}
+
+	buf.Reset()
+	p.Close()
+	if got := buf.String(); got != "\r\033[K" {
+		t.Errorf("Close output = %q, want line erased", got)
+	}
+	buf.Reset()
+	p.Write([]byte("after\n"))
+	if got := buf.String(); got != "after\n" {
+		t.Errorf("output after Close = %q, want plain log line", got)
+	}
+
+	// nil（進捗を出さない設定）でも Clear は呼べる
+	var none *progressLine
+	none.Clear()
+}
+
+func TestParseArgs_Quiet(t *testing.T) {
+	for _, tt := range []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"facebook/react"}, false},
+		{[]string{"facebook/react", "--quiet"}, true},
+	} {
+		got, err := parseArgs(tt.args, time.Now)
+		if err != nil {
+			t.Fatalf("parseArgs(%v) error = %v", tt.args, err)
+		}
+		if got.Quiet != tt.want {
+			t.Errorf("parseArgs(%v).Quiet = %v, want %v", tt.args, got.Quiet, tt.want)
+		}
+	}
+}
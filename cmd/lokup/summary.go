@@ -0,0 +1,326 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/shared/i18n"
+)
+
+// ANSI エスケープシーケンスの色（端末の結果表示で使う）
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+)
+
+// summaryRenderer は分析結果を端末向けのテキストで表示する。
+// color なら総合・カテゴリのグレード、リスク、ベースライン比較を ANSI カラーで色分けする。
+type summaryRenderer struct {
+	lang    i18n.Lang
+	explain bool
+	color   bool
+}
+
+// useColor は結果表示を色付けするかを返す。
+// --no-color、出力先が端末でない場合、環境変数 NO_COLOR が空でない場合（https://no-color.org/）は色を付けない。
+func useColor(noColor, terminal bool, getenv func(string) string) bool {
+	return !noColor && terminal && getenv("NO_COLOR") == ""
+}
+
+// paint は色付けが有効なら text を code の色で囲む（無効ならそのまま返す）。
+func (s summaryRenderer) paint(code, text string) string {
+	if !s.color {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+// gradeColor はグレードの色を返す（HTML レポートと同じく A・B は緑、C は黄、D は赤）。
+func gradeColor(grade string) string {
+	switch grade {
+	case "A", "B":
+		return ansiGreen
+	case "C":
+		return ansiYellow
+	default:
+		return ansiRed
+	}
+}
+
+// severityColor はリスクの重大度の色を返す。
+func severityColor(severity domain.Severity) string {
+	switch severity {
+	case domain.SeverityHigh:
+		return ansiRed
+	case domain.SeverityMedium:
+		return ansiYellow
+	default:
+		return ansiGreen
+	}
+}
+
+// printScoreBreakdown はスコアの内訳を1項目1行で表示する（HTML レポートのスコア内訳と同じ内容）。
+func printScoreBreakdown(w io.Writer, items []domain.ScoreBreakdownItem) {
+	for _, b := range items {
+		if b.Detail != "" {
+			fmt.Fprintf(w, "    %+4d  %s (%s)\n", b.Points, b.Label, b.Detail)
+		} else {
+			fmt.Fprintf(w, "    %+4d  %s\n", b.Points, b.Label)
+		}
+	}
+}
+
+// render は分析結果を表示する。
+// ベースライン比較の項目名はレポートと同じ言語で出す。
+// explain なら各カテゴリの下にスコアの内訳（基本スコアとリスクごとの減点）も出す。
+func (s summaryRenderer) render(w io.Writer, r *domain.AnalysisResult) {
+	fmt.Fprintln(w, "\n========================================")
+	fmt.Fprintln(w, "           Analysis Result")
+	fmt.Fprintln(w, "========================================")
+
+	// 一目で分かるよう、総合スコアとグレードを先頭に出す
+	if r.InsufficientData {
+		fmt.Fprintln(w, "\nOverall Health: N/A (no commits or merged PRs in the period; widen --days or --since)")
+	} else {
+		grade := r.OverallScore.Grade()
+		fmt.Fprintf(w, "\nOverall Health: %s (%s - %s)\n",
+			s.paint(ansiBold+gradeColor(grade), fmt.Sprintf("%d/100", r.OverallScore.Value)),
+			s.paint(gradeColor(grade), grade), s.lang.T(r.OverallScore.GradeDescription()))
+	}
+
+	fmt.Fprintf(w, "\nRepository: %s\n", r.Repository.FullName())
+	fmt.Fprintf(w, "Period:     %s ~ %s (%d days)\n",
+		r.Period.From.Format("2006-01-02"),
+		r.Period.To.Format("2006-01-02"),
+		r.Period.Days())
+	if r.Path != "" {
+		fmt.Fprintf(w, "Path:       %s (contributors, issues, releases, open PRs and dependencies are repository-wide)\n", r.Path)
+	}
+	if r.Branch != "" {
+		fmt.Fprintf(w, "Branch:     %s (pull requests, issues, releases and dependencies are repository-wide)\n", r.Branch)
+	}
+	if r.QuickMode {
+		fmt.Fprintln(w, "Mode:       quick (PR size, review and dependency metrics were not computed)")
+	}
+
+	if !r.InsufficientData {
+		fmt.Fprintln(w, "\n--- Category Scores ---")
+		catNames := map[domain.Category]string{
+			domain.CategoryVelocity: "Velocity",
+			domain.CategoryQuality:  "Quality",
+			domain.CategoryTechDebt: "Tech Debt",
+			domain.CategoryHealth:   "Health",
+		}
+		for _, cat := range []domain.Category{domain.CategoryVelocity, domain.CategoryQuality, domain.CategoryTechDebt, domain.CategoryHealth} {
+			if cs, ok := r.CategoryScores[cat]; ok {
+				grade := cs.Score.Grade()
+				fmt.Fprintf(w, "%-12s %d/100 (%s) - %s\n", catNames[cat]+":", cs.Score.Value, s.paint(gradeColor(grade), grade), cs.Diagnosis)
+				if s.explain {
+					printScoreBreakdown(w, cs.Score.Breakdown)
+				}
+			}
+		}
+	}
+
+	fmt.Fprintln(w, "\n--- Metrics ---")
+	fmt.Fprintf(w, "Total Commits:        %d\n", r.Metrics.TotalCommits)
+	fmt.Fprintf(w, "Feature Addition:     %.2f commits/day\n", r.Metrics.FeatureAdditionRate)
+	fmt.Fprintf(w, "PR Throughput:        %.1f PRs/week (%d merged)\n", r.Metrics.PRThroughputPerWeek, r.Metrics.MergedPRCount)
+	fmt.Fprintf(w, "Contributors:         %d\n", r.Metrics.TotalContributors)
+	fmt.Fprintf(w, "Late Night Commits:   %.1f%%\n", r.Metrics.LateNightCommitRate)
+	fmt.Fprintf(w, "Weekend Commits:      %.1f%%\n", r.Metrics.WeekendCommitRate)
+	fmt.Fprintf(w, "Active Authors:       %d (+%d new / -%d churned)\n", r.Metrics.ActiveAuthors, r.Metrics.NewAuthors, r.Metrics.ChurnedAuthors)
+	license := r.LicenseFile
+	if license == "" {
+		license = "not found"
+	}
+	fmt.Fprintf(w, "License:              %s\n", license)
+	fmt.Fprintf(w, "Branch Protection:    %s\n", formatBranchProtection(r.BranchProtection))
+	if r.Metrics.TodoScannedFiles > 0 {
+		fmt.Fprintf(w, "TODO Comments:        %d (%.1f per 1k lines, %d files scanned)\n",
+			r.Metrics.TodoCount, r.Metrics.TodoDensity, r.Metrics.TodoScannedFiles)
+	}
+	if r.Metrics.DependencyCount > 0 {
+		ecosystems := make([]string, len(r.DependencyEcosystems))
+		for i, e := range r.DependencyEcosystems {
+			ecosystems[i] = fmt.Sprintf("%s %d", e.Ecosystem, e.Count)
+		}
+		fmt.Fprintf(w, "Dependencies:         %d (%.1f%% outdated; %s)\n",
+			r.Metrics.DependencyCount, r.Metrics.OutdatedDepRate, strings.Join(ecosystems, ", "))
+	}
+	if r.Metrics.VulnCheckedDeps > 0 {
+		fmt.Fprintf(w, "Vulnerable Deps:      %d (of %d checked in OSV)\n", r.Metrics.VulnerableDepCount, r.Metrics.VulnCheckedDeps)
+		for _, v := range r.VulnerableDeps {
+			fmt.Fprintf(w, "  - %s@%s: %s\n", v.Name, v.Version, strings.Join(v.IDs, ", "))
+		}
+	}
+	if len(r.CommunityFiles) > 0 {
+		var missing []string
+		for _, f := range r.CommunityFiles {
+			if !f.Present {
+				missing = append(missing, f.Name)
+			}
+		}
+		if len(missing) > 0 {
+			fmt.Fprintf(w, "Community Health:     %d/100 (missing: %s)\n", r.Metrics.CommunityHealthScore, strings.Join(missing, ", "))
+		} else {
+			fmt.Fprintf(w, "Community Health:     %d/100\n", r.Metrics.CommunityHealthScore)
+		}
+	}
+
+	fmt.Fprintln(w, "\n--- DORA Metrics ---")
+	fmt.Fprintf(w, "Deploy Freq:          %.1f/month (%s)\n", r.Metrics.DeployFrequency, r.Metrics.DeployFreqRating)
+	fmt.Fprintf(w, "Change Failure Rate:  %.1f%% (%s)\n", r.Metrics.ChangeFailureRate, r.Metrics.ChangeFailRating)
+	fmt.Fprintf(w, "MTTR:                 %.1fh (%s)\n", r.Metrics.MTTR, r.Metrics.MTTRRating)
+	if r.Metrics.LastReleaseAt.IsZero() {
+		fmt.Fprintln(w, "Since Last Release:   N/A (no release found)")
+	} else {
+		fmt.Fprintf(w, "Since Last Release:   %d days, %d commits (%s, %s)\n", r.Metrics.DaysSinceLastRelease, r.Metrics.CommitsSinceLastRelease,
+			r.Metrics.LastReleaseName, r.Metrics.LastReleaseAt.Format("2006-01-02"))
+	}
+
+	fmt.Fprintln(w, "\n--- Investment Ratio ---")
+	fmt.Fprintf(w, "Feature:   %d PRs (%.1f%%)\n", r.Metrics.FeaturePRCount, r.Metrics.FeatureRatio)
+	fmt.Fprintf(w, "BugFix:    %d PRs (%.1f%%)\n", r.Metrics.BugFixPRCount, r.Metrics.BugFixRatio)
+	fmt.Fprintf(w, "Refactor:  %d PRs (%.1f%%)\n", r.Metrics.RefactorPRCount, r.Metrics.RefactorRatio)
+	fmt.Fprintf(w, "Other:     %d PRs\n", r.Metrics.OtherPRCount)
+	fmt.Fprintf(w, "Abandoned: %d PRs (%.1f%% of closed)\n", r.Metrics.AbandonedPRCount, r.Metrics.AbandonmentRate)
+	if r.Metrics.FastRevertCount > 0 {
+		fmt.Fprintf(w, "Revert:    %d commits (%.1f%%, %d within 24h)\n", r.Metrics.RevertCommitCount, r.Metrics.RevertRate, r.Metrics.FastRevertCount)
+	} else {
+		fmt.Fprintf(w, "Revert:    %d commits (%.1f%%)\n", r.Metrics.RevertCommitCount, r.Metrics.RevertRate)
+	}
+	fmt.Fprintf(w, "Low-quality messages: %d commits (%.1f%%)\n", r.Metrics.LowQualityCommitCount, r.Metrics.LowQualityCommitRate)
+	fmt.Fprintf(w, "Co-authored commits:  %d (%.1f%%)\n", r.Metrics.CoAuthoredCommitCount, r.Metrics.CoAuthorshipRate)
+	fmt.Fprintf(w, "Files per commit:     %.1f (%d commits with file details)\n", r.Metrics.AvgFilesPerCommit, r.Metrics.SizedCommitCount)
+	fmt.Fprintf(w, "Test files:           %d for %d source files (%.2f per source file, estimated)\n", r.Metrics.TestFileCount, r.Metrics.SourceFileCount, r.Metrics.TestToSourceRatio)
+
+	if b := r.Baseline; b != nil {
+		fmt.Fprintf(w, "\n--- Baseline (%s) ---\n", b.GeneratedAt.Format("2006-01-02"))
+		deltas := append([]domain.BaselineDelta{b.OverallScore}, b.Categories...)
+		for _, d := range append(deltas, b.Metrics...) {
+			arrow := "→"
+			switch d.Status() {
+			case "improved":
+				arrow = s.paint(ansiGreen, "▲")
+			case "regressed":
+				arrow = s.paint(ansiRed, "▼")
+			}
+			fmt.Fprintf(w, "%s %-16s %.1f → %.1f (%+.1f)\n", arrow, s.lang.T(d.Name), d.Baseline, d.Current, d.Delta())
+		}
+	}
+
+	if len(r.Trends) > 0 {
+		fmt.Fprintln(w, "\n--- Trends (vs Previous Period) ---")
+		for _, t := range r.Trends {
+			arrow := "→"
+			switch t.Direction {
+			case "up":
+				arrow = "↑"
+			case "down":
+				arrow = "↓"
+			}
+			fmt.Fprintf(w, "%s %-16s %+.1f%%\n", arrow, t.MetricName, t.DeltaPct)
+		}
+	}
+
+	if len(r.History) > 0 {
+		fmt.Fprintln(w, "\n--- Score History ---")
+		currentSnapshot := false
+		for _, h := range r.History {
+			period := h.Period.From.Format("2006-01-02") + " ~ " + h.Period.To.Format("2006-01-02")
+			mark := ""
+			if h.CurrentSnapshot {
+				mark, currentSnapshot = " *", true
+			}
+			if h.InsufficientData {
+				fmt.Fprintf(w, "%s  N/A%s\n", period, mark)
+				continue
+			}
+			fmt.Fprintf(w, "%s  %3d (%s)%s\n", period, h.OverallScore.Value, h.OverallScore.Grade(), mark)
+		}
+		if currentSnapshot {
+			fmt.Fprintln(w, "* scored with the current file tree, dependencies, branch protection, license and community files")
+		}
+	}
+
+	var incomplete []domain.DataSource
+	for _, src := range r.DataSources {
+		if !src.Complete() {
+			incomplete = append(incomplete, src)
+		}
+	}
+	if len(incomplete) > 0 {
+		fmt.Fprintln(w, "\n--- Incomplete Data ---")
+		for _, src := range incomplete {
+			fmt.Fprintf(w, "%s: %s\n", s.lang.T(src.Name), s.paint(ansiYellow, formatDataSource(src)))
+		}
+	}
+
+	if len(r.Risks) > 0 {
+		fmt.Fprintln(w, "\n--- Risks ---")
+		for _, risk := range r.Risks {
+			severity := "⚪"
+			switch risk.Severity {
+			case domain.SeverityHigh:
+				severity = "🔴"
+			case domain.SeverityMedium:
+				severity = "🟡"
+			case domain.SeverityLow:
+				severity = "🟢"
+			}
+			fmt.Fprintf(w, "%s %s: %s\n", severity, s.paint(severityColor(risk.Severity), string(risk.Type)), risk.Description)
+		}
+	} else {
+		fmt.Fprintln(w, "\n--- Risks ---")
+		fmt.Fprintln(w, "No significant risks detected.")
+	}
+
+	fmt.Fprintln(w, "\n========================================")
+}
+
+// formatDataSource はデータ1種類の取得状況を1行で表す（例: "20/134 (capped), 2 failed"）。
+func formatDataSource(src domain.DataSource) string {
+	switch {
+	case src.Skipped:
+		return "skipped (quick mode)"
+	case src.Error != "":
+		return "failed: " + src.Error
+	}
+	count := strconv.Itoa(src.Fetched)
+	if src.Total > 0 {
+		count += "/" + strconv.Itoa(src.Total)
+	}
+	var notes []string
+	if src.Total > src.Fetched+src.Failed {
+		notes = append(notes, "capped")
+	}
+	if src.Truncated {
+		notes = append(notes, "truncated")
+	}
+	if src.Failed > 0 {
+		notes = append(notes, fmt.Sprintf("%d failed", src.Failed))
+	}
+	return count + " (" + strings.Join(notes, ", ") + ")"
+}
+
+// formatBranchProtection はデフォルトブランチの保護設定を1行で表す（読めなければ unknown）。
+func formatBranchProtection(bp *domain.BranchProtection) string {
+	switch {
+	case bp == nil:
+		return "unknown"
+	case !bp.Protected:
+		return bp.Branch + " not protected"
+	}
+	checks := "no status checks"
+	if bp.RequiredStatusChecks {
+		checks = "status checks required"
+	}
+	return fmt.Sprintf("%s protected (%d approvals required, %s)", bp.Branch, bp.RequiredApprovals, checks)
+}
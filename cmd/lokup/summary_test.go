@@ -0,0 +1,147 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/shared/i18n"
+)
+
+// colorResult は色分けの対象（グレード・リスク・ベースライン比較）をすべて含む分析結果を返す。
+func colorResult() *domain.AnalysisResult {
+	return &domain.AnalysisResult{
+		Repository:   domain.NewRepository("facebook", "react"),
+		OverallScore: domain.NewScore(35),
+		CategoryScores: map[domain.Category]domain.CategoryScore{
+			domain.CategoryVelocity: {Category: domain.CategoryVelocity, Score: domain.NewScore(85)},
+			domain.CategoryHealth:   {Category: domain.CategoryHealth, Score: domain.NewScore(45)},
+		},
+		Risks: []domain.Risk{
+			{Type: domain.RiskTypeOwnership, Severity: domain.SeverityHigh, Description: "owner"},
+			{Type: domain.RiskTypeLateNight, Severity: domain.SeverityMedium, Description: "late"},
+			{Type: domain.RiskTypeWeekendWork, Severity: domain.SeverityLow, Description: "weekend"},
+		},
+		Baseline: &domain.BaselineComparison{
+			OverallScore: domain.BaselineDelta{Name: "総合スコア", Current: 35, Baseline: 50, HigherIsBetter: true},
+			Categories: []domain.BaselineDelta{
+				{Name: "開発速度", Current: 85, Baseline: 70, HigherIsBetter: true},
+			},
+		},
+	}
+}
+
+func TestSummaryRenderer_NoColor(t *testing.T) {
+	for _, explain := range []bool{false, true} {
+		var b strings.Builder
+		summaryRenderer{lang: i18n.English, explain: explain}.render(&b, colorResult())
+		if strings.Contains(b.String(), "\x1b[") {
+			t.Errorf("explain=%v: output contains ANSI escape codes with color off:\n%q", explain, b.String())
+		}
+	}
+}
+
+func TestSummaryRenderer_Color(t *testing.T) {
+	var b strings.Builder
+	summaryRenderer{lang: i18n.English, color: true}.render(&b, colorResult())
+	out := b.String()
+
+	for _, want := range []string{
+		"Overall Health: " + ansiBold + ansiRed + "35/100" + ansiReset + " (" + ansiRed + "D" + ansiReset + " - ",
+		"85/100 (" + ansiGreen + "A" + ansiReset + ")",
+		"45/100 (" + ansiYellow + "C" + ansiReset + ")",
+		"🔴 " + ansiRed + string(domain.RiskTypeOwnership) + ansiReset + ": owner\n",
+		"🟡 " + ansiYellow + string(domain.RiskTypeLateNight) + ansiReset + ": late\n",
+		"🟢 " + ansiGreen + string(domain.RiskTypeWeekendWork) + ansiReset + ": weekend\n",
+		ansiRed + "▼" + ansiReset + " Overall score",
+		ansiGreen + "▲" + ansiReset + " Velocity",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q\n%q", want, out)
+		}
+	}
+}
+
+func TestUseColor(t *testing.T) {
+	env := func(noColor string) func(string) string {
+		return func(key string) string {
+			if key == "NO_COLOR" {
+				return noColor
+			}
+			return ""
+		}
+	}
+	tests := []struct {
+		name     string
+		noColor  bool
+		terminal bool
+		envValue string
+		want     bool
+	}{
+		{name: "terminal", terminal: true, want: true},
+		{name: "not a terminal", terminal: false, want: false},
+		{name: "--no-color", noColor: true, terminal: true, want: false},
+		{name: "NO_COLOR set", terminal: true, envValue: "1", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := useColor(tt.noColor, tt.terminal, env(tt.envValue)); got != tt.want {
+				t.Errorf("useColor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseArgs_NoColor(t *testing.T) {
+	for _, tt := range []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"facebook/react"}, false},
+		{[]string{"facebook/react", "--no-color"}, true},
+	} {
+		got, err := parseArgs(tt.args, time.Now)
+		if err != nil {
+			t.Fatalf("parseArgs(%v) error = %v", tt.args, err)
+		}
+		if got.NoColor != tt.want {
+			t.Errorf("parseArgs(%v).NoColor = %v, want %v", tt.args, got.NoColor, tt.want)
+		}
+	}
+}
+
+func TestFormatDataSource(t *testing.T) {
+	tests := []struct {
+		src  domain.DataSource
+		want string
+	}{
+		{domain.DataSource{Fetched: 20, Total: 134}, "20/134 (capped)"},
+		{domain.DataSource{Fetched: 1000, Truncated: true}, "1000 (truncated)"},
+		{domain.DataSource{Fetched: 97, Total: 500, Failed: 3}, "97/500 (capped, 3 failed)"},
+		{domain.DataSource{Skipped: true}, "skipped (quick mode)"},
+		{domain.DataSource{Error: "403 Forbidden"}, "failed: 403 Forbidden"},
+	}
+	for _, tt := range tests {
+		if got := formatDataSource(tt.src); got != tt.want {
+			t.Errorf("formatDataSource(%+v) = %q, want %q", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestSummaryRenderer_IncompleteData(t *testing.T) {
+	r := colorResult()
+	r.DataSources = []domain.DataSource{
+		{Name: "コミット", Fetched: 42},
+		{Name: "PR詳細（サイズ・レビュー）", Fetched: 20, Total: 134},
+	}
+	var b strings.Builder
+	summaryRenderer{lang: i18n.English}.render(&b, r)
+	out := b.String()
+	if !strings.Contains(out, "--- Incomplete Data ---\nPR details (size, reviews): 20/134 (capped)\n") {
+		t.Errorf("output does not list the incomplete data source:\n%s", out)
+	}
+	if strings.Contains(out, "Commits: 42") {
+		t.Errorf("output lists a complete data source:\n%s", out)
+	}
+}
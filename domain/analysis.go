@@ -18,6 +18,14 @@ func (d DateRange) Days() int {
 	return int(d.To.Sub(d.From).Hours() / 24)
 }
 
+// MinDeployFreqWindowDays はデプロイ頻度の DORA レーティングを出す最短の分析期間（日）。
+// これより短いと、1回のリリースでも月換算で High 相当（7日なら約4.3回/月）になってしまう。
+const MinDeployFreqWindowDays = 14
+
+// DeployFreqRatingWindowTooShort は分析期間が MinDeployFreqWindowDays 日より短く、
+// デプロイ頻度を評価しないときのレーティング。
+const DeployFreqRatingWindowTooShort = "N/A (window too short)"
+
 // CategoryScore はカテゴリごとのスコアと診断。
 type CategoryScore struct {
 	Category  Category // カテゴリ
@@ -27,14 +35,18 @@ type CategoryScore struct {
 
 // PRDetail はPRの詳細情報（ドリルダウン表示用）。
 type PRDetail struct {
-	Number          int     // PR番号
-	Title           string  // タイトル
-	Author          string  // 作成者
-	LeadTimeDays    float64 // リードタイム（日）
-	Size            int     // 変更行数（追加+削除）
-	Additions       int     // 追加行数
-	Deletions       int     // 削除行数
-	ReviewWaitHours float64 // レビュー待ち時間（時間）
+	Number             int     // PR番号
+	Title              string  // タイトル
+	Author             string  // 作成者
+	LeadTimeDays       float64 // リードタイム（日）
+	Size               int     // 変更行数（追加+削除）
+	Additions          int     // 追加行数
+	Deletions          int     // 削除行数
+	ReviewWaitHours    float64 // レビュー待ち時間（時間）
+	FirstResponseHours float64 // 最初の人による反応（作成者以外のレビューかコメント）までの時間（時間）
+	ReviewsFetched     bool    // レビュー一覧を取得できたか（false ならレビュー有無は不明）
+	Reviewed           bool    // APPROVED / CHANGES_REQUESTED のレビューを受けたか
+	SelfMerged         bool    // 作成者以外の承認（APPROVED）がないままマージされたか
 }
 
 // TrendDelta は前期比較のデルタ値を表す。
@@ -46,6 +58,72 @@ type TrendDelta struct {
 	Direction     string  `json:"direction"`     // "up", "down", "same"
 }
 
+// HistoryPoint はスコア推移の1点（期間をずらして分析した1期間分のスコア）。
+//
+// 過去の期間でも、ファイルツリー・依存・ブランチ保護・ライセンス・コミュニティファイルのような
+// 時点を指定して取得できない情報は分析時点のものを使う（CurrentSnapshot）。
+// 期間によって変わるのはコミット・PR・Issue・リリースなどの活動から出す指標だけ。
+type HistoryPoint struct {
+	Period           DateRange          // 分析期間
+	OverallScore     Score              // 総合スコア（内訳は持たない）
+	CategoryScores   map[Category]Score // カテゴリ別スコア（内訳は持たない）
+	InsufficientData bool               // 期間内にコミットもマージ済みPRもなく、スコアが健全さを表さない
+	CurrentSnapshot  bool               // 過去の期間を、期間当時ではなく分析時点のファイルツリー・依存などで採点した
+}
+
+// NewHistoryPoint は分析結果からスコア推移の1点を作る。
+func NewHistoryPoint(r *AnalysisResult) HistoryPoint {
+	categories := make(map[Category]Score, len(r.CategoryScores))
+	for cat, cs := range r.CategoryScores {
+		categories[cat] = NewScore(cs.Score.Value)
+	}
+	return HistoryPoint{
+		Period:           r.Period,
+		OverallScore:     NewScore(r.OverallScore.Value),
+		CategoryScores:   categories,
+		InsufficientData: r.InsufficientData,
+	}
+}
+
+// BaselineComparison は保存済みのベースライン（過去のレポート）との比較結果。
+//
+// TrendDelta が直前の同じ長さの期間との比較なのに対し、
+// こちらはリリース時点などの固定した基準点との比較。
+type BaselineComparison struct {
+	GeneratedAt  time.Time       // ベースラインのレポート生成日時
+	Period       DateRange       // ベースラインの分析期間
+	OverallScore BaselineDelta   // 総合スコア
+	Categories   []BaselineDelta // カテゴリ別スコア
+	Metrics      []BaselineDelta // 主要メトリクス
+}
+
+// BaselineDelta はベースラインと今回の値の比較1件。
+type BaselineDelta struct {
+	Name           string  // 表示名（例: "コード品質", "平均リードタイム"）
+	Unit           string  // 単位（例: "日", "%"。スコアは空）
+	Current        float64 // 今回の値
+	Baseline       float64 // ベースラインの値
+	HigherIsBetter bool    // 値が大きいほど良い指標か
+}
+
+// Delta は今回の値からベースラインの値を引いた差を返す。
+func (d BaselineDelta) Delta() float64 {
+	return d.Current - d.Baseline
+}
+
+// Status は改善・悪化の判定を返す（"improved", "regressed", "same"）。
+// 表示桁（小数1桁）に現れない差は変化なしとみなす。
+func (d BaselineDelta) Status() string {
+	delta := d.Delta()
+	if delta > -0.05 && delta < 0.05 {
+		return "same"
+	}
+	if (delta > 0) == d.HigherIsBetter {
+		return "improved"
+	}
+	return "regressed"
+}
+
 // ContributorDetail はコントリビューターの詳細（ドリルダウン表示用）。
 type ContributorDetail struct {
 	Name    string  // ユーザー名
@@ -56,20 +134,73 @@ type ContributorDetail struct {
 // AnalysisResult は分析結果を表す集約。
 // これが集約ルートであり、診断結果全体を束ねる。
 type AnalysisResult struct {
-	Repository         Repository                 // 対象リポジトリ
-	Period             DateRange                  // 分析期間
-	CategoryScores     map[Category]CategoryScore // カテゴリ別スコア
-	OverallScore       Score                      // 総合スコア（カテゴリ平均）
-	Risks              []Risk                     // 検出されたリスク
-	Metrics            Metrics                    // 各種メトリクス
-	DailyCommits       []DailyCommit              // 日別コミット数
-	LargeFiles         []LargeFile                // 巨大ファイル一覧
-	OutdatedDeps       []OutdatedDep              // 古い依存一覧
-	PRDetails          []PRDetail                 // PR詳細一覧（ドリルダウン用）
-	ContributorDetails []ContributorDetail        // コントリビューター詳細（ドリルダウン用）
-	HourlyCommits      [24]int                    // 時間帯別コミット数（ドリルダウン用）
-	Trends             []TrendDelta               // 前期比較トレンド
-	GeneratedAt        time.Time                  // レポート生成日時
+	Repository           Repository                 // 対象リポジトリ
+	Period               DateRange                  // 分析期間
+	Path                 string                     // 対象パス（モノレポのサブツリーに絞った場合。空ならリポジトリ全体）
+	Branch               string                     // 対象ブランチ（空ならデフォルトブランチ）
+	CategoryScores       map[Category]CategoryScore // カテゴリ別スコア
+	CategoryWeights      map[Category]float64       // 総合スコアに使ったカテゴリ別の重み（nil なら均等）
+	OverallScore         Score                      // 総合スコア（カテゴリ平均）
+	Risks                []Risk                     // 検出されたリスク
+	Metrics              Metrics                    // 各種メトリクス
+	DailyCommits         []DailyCommit              // 日別コミット数
+	LargeFiles           []LargeFile                // 巨大ファイル一覧（サイズの大きい順に上位のみ）
+	LargeNonSourceFiles  []LargeFile                // 巨大ファイルリスクの対象外にした大きいバイナリ・生成物（サイズの大きい順に上位のみ）
+	HotFiles             []HotFile                  // 変更集中ファイル一覧（変更回数の多い順に上位のみ）
+	SiloFiles            []SiloFile                 // 1人の作成者が変更の大半を占めるファイル一覧（変更回数の多い順に上位のみ）
+	OutdatedDeps         []OutdatedDep              // 古い依存一覧
+	DependencyEcosystems []DependencyEcosystem      // エコシステム別の依存数（依存の多い順）
+	VulnerableDeps       []VulnerableDep            // 既知の脆弱性がある依存（--check-vulns 指定時のみ、名前順）
+	LicenseFile          string                     // 検出したライセンスファイル（LICENSE など、なければ空）
+	TodoFiles            []TodoFile                 // TODO コメントの多いファイル（件数の多い順に上位のみ、--scan-todos 指定時のみ）
+	CommunityFiles       []CommunityFile            // コミュニティヘルスファイルの有無（チェック順）
+	BranchProtection     *BranchProtection          // デフォルトブランチの保護設定（権限不足などで読めなければ nil）
+	PRDetails            []PRDetail                 // PR詳細一覧（ドリルダウン用）
+	ContributorDetails   []ContributorDetail        // コントリビューター詳細（ドリルダウン用）
+	HourlyCommits        [24]int                    // 時間帯別コミット数（ドリルダウン用）
+	WeekdayHourCommits   [7][24]int                 // 曜日（time.Weekday、日曜が0）×時間帯別コミット数（ヒートマップ用）
+	Trends               []TrendDelta               // 前期比較トレンド
+	Baseline             *BaselineComparison        // ベースラインとの比較（--baseline 指定時のみ）
+	History              []HistoryPoint             // 期間をずらして分析した過去のスコア（古い順、最後が今回の期間。--history 指定時のみ）
+	Thresholds           []Threshold                // 判定に使った閾値（レポートの判定基準に表示する）
+	DORABands            DORARatingBands            // DORA レーティングの判定に使った境界（ゼロ値ならデフォルト）
+	InsufficientData     bool                       // 期間内にコミットもマージ済みPRもなく、スコアが健全さを表さない
+	LastActivity         time.Time                  // 休眠の判定で求めた最後の活動（コミット・マージ・リリース）の日時（判定しなかった場合はゼロ値）
+	FilesTruncated       bool                       // 大きなリポジトリでファイル一覧の一部しか取得できず、ファイル数・巨大ファイルなどが少なく出ている
+	QuickMode            bool                       // クイックモード（PR詳細・依存を取得せず、PRサイズ・レビュー系・依存のメトリクスは未計算）
+	DataSources          []DataSource               // データの種類ごとの取得状況（件数の上限・取得失敗でデータが欠けていないか）
+	GeneratedAt          time.Time                  // レポート生成日時
+}
+
+// Threshold はリスクの判定・スコアの計算に使った閾値1件を表す。
+type Threshold struct {
+	Name    string  // 表示名（例: "深夜コミット率"）
+	Unit    string  // 単位（例: "%", "日"）
+	Value   float64 // 判定に使った値
+	Default float64 // デフォルト値
+}
+
+// Custom は設定で閾値をデフォルトから変更しているかを返す。
+func (t Threshold) Custom() bool {
+	return t.Value != t.Default
+}
+
+// DataSource はレポートの元になったデータ1種類の取得状況を表す。
+// 件数・ページ数の上限や取得失敗でデータが欠けていれば、それを使うスコアは割り引いて読む必要がある。
+type DataSource struct {
+	Name      string // 表示名（例: "PR詳細"）
+	Fetched   int    // 取得した件数
+	Total     int    // 対象の件数（上限で絞った場合のみ Fetched より大きい。絞っていなければ 0）
+	Failed    int    // 1件ずつの取得に失敗した件数
+	Truncated bool   // 件数・ページ数の上限で打ち切り、一部しか取得していない
+	Skipped   bool   // クイックモードで取得しなかった
+	Error     string // 取得自体に失敗した場合のエラー（失敗していなければ空）
+	Note      string // 補足（例: 依存を読み取れたエコシステム）
+}
+
+// Complete は欠けなく取得できたかを返す。
+func (d DataSource) Complete() bool {
+	return !d.Truncated && !d.Skipped && d.Error == "" && d.Failed == 0 && d.Fetched >= d.Total
 }
 
 // DailyCommit は1日分のコミット数を表す。
@@ -85,6 +216,42 @@ type LargeFile struct {
 	Severity Severity // 重大度
 }
 
+// HotFile は変更が集中しているファイル（ホットスポット）を表す。
+type HotFile struct {
+	Path     string   // ファイルパス
+	Changes  int      // 期間内の変更回数
+	Severity Severity // 重大度
+}
+
+// SiloFile は1人の作成者が変更の大半を占めるファイル（知識のサイロ）を表す。
+type SiloFile struct {
+	Path     string   // ファイルパス
+	Author   string   // 最も多く変更した作成者
+	Changes  int      // 期間内の変更回数（変更ファイルを取得したコミットのみ）
+	Share    int      // そのうち Author の変更が占める割合（%）
+	Severity Severity // 重大度（Author 以外が一度も変更していなければ High）
+}
+
+// TodoFile は TODO / FIXME / HACK コメントのあるファイルを表す。
+type TodoFile struct {
+	Path  string // ファイルパス
+	Count int    // マーカーを含む行数
+}
+
+// CommunityFile はコミュニティヘルスファイル（CONTRIBUTING.md など）1種類の有無を表す。
+type CommunityFile struct {
+	Name    string // 表示名（例: "CONTRIBUTING.md", ".github/ISSUE_TEMPLATE"）
+	Present bool   // リポジトリにあるか
+}
+
+// BranchProtection はデフォルトブランチの保護設定を表す。
+type BranchProtection struct {
+	Branch               string // デフォルトブランチ名
+	Protected            bool   // 保護ルールがあるか
+	RequiredApprovals    int    // マージに必要な承認数（0 ならレビュー必須なし）
+	RequiredStatusChecks bool   // ステータスチェックの成功が必須か
+}
+
 // OutdatedDep は古い依存情報を表す。
 type OutdatedDep struct {
 	Name     string   // パッケージ名
@@ -93,37 +260,73 @@ type OutdatedDep struct {
 	Severity Severity // 重大度
 }
 
+// VulnerableDep は既知の脆弱性がある依存を表す。
+type VulnerableDep struct {
+	Name      string   // パッケージ名
+	Version   string   // 使用中のバージョン
+	Ecosystem string   // エコシステム名（"npm", "go" など）
+	IDs       []string // 影響する脆弱性の ID（GHSA-xxxx など、昇順）
+}
+
+// DependencyEcosystem はエコシステム（npm, go など）ごとの依存の集計を表す。
+type DependencyEcosystem struct {
+	Ecosystem string // エコシステム名（"npm", "go", "python" など）
+	Count     int    // 分析した依存の数
+	Outdated  int    // そのうち古い依存の数
+}
+
 // Metrics は各種メトリクスを表す。
 type Metrics struct {
 	// 開発速度メトリクス
 	TotalCommits        int     // 総コミット数
 	FeatureAdditionRate float64 // 機能追加速度（コミット/日）
+	MergedPRCount       int     // 期間内にマージされたPR数
+	PRThroughputPerWeek float64 // PRスループット（期間内にマージされたPR数/週）
 	AvgLeadTime         float64 // PR作成→マージの平均日数
+	LeadTimeP50         float64 // PR作成→マージの日数の中央値
+	LeadTimeP75         float64 // 同 75パーセンタイル
+	LeadTimeP90         float64 // 同 90パーセンタイル
 	AvgReviewWaitTime   float64 // 最初のレビューまでの平均時間（時間）
+	AvgFirstResponse    float64 // 最初の人による反応（レビューかコメント）までの平均時間（時間）
 	OpenPRCount         int     // オープンPR数
 	OpenIssueCount      int     // オープンIssue数
 
 	// コード品質メトリクス
-	BugFixRatio    float64 // バグ修正の割合（%）
-	ReworkRate     float64 // 手戻り率（%）
-	AvgPRSize      int     // PRあたりの平均変更行数
-	IssueCloseRate float64 // Issueクローズ率（%）
-	IssuesCreated  int     // 期間中に作成されたIssue数
-	IssuesClosed   int     // 期間中にクローズされたIssue数
+	BugFixRatio        float64 // バグ修正の割合（%）
+	ReworkRate         float64 // 手戻り率（%）
+	AvgPRSize          int     // PRあたりの平均変更行数
+	IssueCloseRate     float64 // Issueクローズ率（%）
+	IssuesCreated      int     // 期間中に作成されたIssue数
+	IssuesClosed       int     // 期間中にクローズされたIssue数
+	ReviewCoverageRate float64 // レビューカバレッジ（承認・変更要求のレビューを受けたマージ済みPRの割合、%）
+	ReviewCoveragePRs  int     // レビューカバレッジの集計対象PR数（レビュー一覧を取得できたマージ済みPR）
+	SelfMergeCount     int     // 作成者以外の承認なしでマージされたPR数（集計対象はレビューカバレッジと同じ）
+	SelfMergeRate      float64 // 同 割合（%）
 
 	// PR内訳
 	FeaturePRCount int // feature PRの件数
 	BugFixPRCount  int // bugfix PRの件数
 	OtherPRCount   int // その他PRの件数
 
+	// 放棄PR（マージされずにクローズされたPR）
+	AbandonedPRCount int     // マージされずにクローズされたPR数
+	AbandonmentRate  float64 // クローズ済みPRに占める割合（%）
+
 	// DORA メトリクス
 	DeployFrequency   float64 // デプロイ頻度（リリース/月）
-	DeployFreqRating  string  // DORAレーティング（Elite/High/Medium/Low）
+	DeployFreqRating  string  // DORAレーティング（Elite/High/Medium/Low、期間が短すぎれば DeployFreqRatingWindowTooShort）
 	ChangeFailureRate float64 // 変更失敗率（%）
 	ChangeFailRating  string  // DORAレーティング
 	MTTR              float64 // 平均復旧時間（時間）
 	MTTRRating        string  // DORAレーティング
 
+	// リリース負債（最後のリリース以降に溜まった未リリースの変更。
+	// 期間の終わりまでにリリースが見つからなければ LastReleaseAt はゼロ値で、ほかも 0）
+	LastReleaseAt           time.Time // 最後のリリース（デプロイ）の日時
+	LastReleaseName         string    // 同 タグ名（なければリリース名・ワークフロー名）
+	DaysSinceLastRelease    int       // 最後のリリースから期間の終わりまでの日数
+	CommitsSinceLastRelease int       // 最後のリリースより後のコミット数
+
 	// 投資比率（PR分類拡張）
 	RefactorPRCount int     // リファクタリングPR数
 	FeatureRatio    float64 // 機能追加率（%）
@@ -132,11 +335,57 @@ type Metrics struct {
 	// コードチャーン
 	RevertCommitCount int     // Revertコミット数
 	RevertRate        float64 // Revert率（%）
+	// 元のコミットから Revert までの時間（元のコミットを期間内に特定できた Revert だけ）
+	FastRevertCount      int     // 元のコミットから24時間以内に Revert された数
+	AvgTimeToRevertHours float64 // 元のコミットから Revert までの平均時間（特定できた Revert がなければ 0）
+
+	// コミットメッセージの品質（空・1単語・WIP・本文のないマージなど）
+	LowQualityCommitCount int     // メッセージが変更内容を説明していないコミット数
+	LowQualityCommitRate  float64 // 全コミットに占める割合（%）
+
+	// 共同作成（メッセージの Co-authored-by トレーラー。ペアプログラミング・モブプログラミングの目安）
+	CoAuthoredCommitCount int     // 共同作成者のいるコミット数
+	CoAuthorshipRate      float64 // 全コミットに占める割合（%）
+
+	// コミットの粒度（変更ファイルを取得できた、マージ以外のコミット）
+	SizedCommitCount  int     // 集計したコミット数
+	AvgFilesPerCommit float64 // 1コミットあたりの平均変更ファイル数
+
+	// テストファイルの比率（ファイル名の慣習で判定したテストコードの量の目安。実際のカバレッジではない）
+	TestFileCount     int     // テストファイル数
+	SourceFileCount   int     // テスト以外のソースファイル数
+	TestToSourceRatio float64 // ソースファイル1件あたりのテストファイル数（ソースファイルがなければ 0）
 
 	// チーム健全性メトリクス
 	TotalFiles          int     // 総ファイル数
 	TotalContributors   int     // コントリビューター数
 	LateNightCommitRate float64 // 深夜コミット率（%）
+	WeekendCommitRate   float64 // 週末（土日）コミット率（%）
+
+	// コントリビューターの定着（期間を前半・後半に分けて比較）
+	ActiveAuthors  int // 期間中にコミットした作成者数
+	NewAuthors     int // 後半にだけコミットした作成者数（新しく加わった人）
+	ChurnedAuthors int // 前半にだけコミットした作成者数（離れた人）
+
+	// コミュニティヘルス（CONTRIBUTING.md などの揃っている割合、0-100）
+	CommunityHealthScore int
+
+	// 巨大ファイルリスクの対象外にした、50KB以上のバイナリ・生成物・ベンダリングしたコードの数
+	LargeNonSourceFileCount int
+
+	// TODO コメント（--scan-todos 指定時のみ。走査しなければすべて 0）
+	TodoCount        int     // TODO / FIXME / HACK を含む行数
+	TodoDensity      float64 // 1000行あたりの件数
+	TodoScannedFiles int     // 内容を走査したファイル数
+	TodoScannedLines int     // 走査した行数（空行を除く）
+
+	// 依存（リリース日を取得できたものだけを数える）
+	DependencyCount int     // 分析した依存の数
+	OutdatedDepRate float64 // そのうち古い依存の割合（%）
+
+	// 依存の既知の脆弱性（--check-vulns 指定時のみ。VulnCheckedDeps が 0 なら調べていない）
+	VulnerableDepCount int // 脆弱性がある依存の数
+	VulnCheckedDeps    int // 脆弱性を調べた依存の数
 }
 
 // RiskCount は重大度別のリスク数を返す。
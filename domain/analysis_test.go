@@ -97,3 +97,25 @@ func TestAnalysisResultRiskCount_empty(t *testing.T) {
 		t.Errorf("RiskCount on empty = %d, want 0", got)
 	}
 }
+
+func TestBaselineDeltaStatus(t *testing.T) {
+	tests := []struct {
+		name  string
+		delta BaselineDelta
+		want  string
+	}{
+		{"higher is better, increased", BaselineDelta{Current: 80, Baseline: 70, HigherIsBetter: true}, "improved"},
+		{"higher is better, decreased", BaselineDelta{Current: 60, Baseline: 70, HigherIsBetter: true}, "regressed"},
+		{"lower is better, decreased", BaselineDelta{Current: 2.0, Baseline: 3.5}, "improved"},
+		{"lower is better, increased", BaselineDelta{Current: 4.0, Baseline: 3.5}, "regressed"},
+		{"unchanged", BaselineDelta{Current: 70, Baseline: 70, HigherIsBetter: true}, "same"},
+		{"below display precision", BaselineDelta{Current: 3.52, Baseline: 3.5}, "same"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.delta.Status(); got != tt.want {
+				t.Errorf("Status() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
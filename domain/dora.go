@@ -0,0 +1,107 @@
+package domain
+
+import "fmt"
+
+// DORA レーティング。
+const (
+	DORARatingElite  = "Elite"
+	DORARatingHigh   = "High"
+	DORARatingMedium = "Medium"
+	DORARatingLow    = "Low"
+)
+
+// DORABands は DORA メトリクス1つのレーティングの境界値。
+// Elite・High・Medium の順に境界を満たすかを見て、どれも満たさなければ Low とする。
+type DORABands struct {
+	Elite  float64
+	High   float64
+	Medium float64
+}
+
+// DORARatingBands は DORA メトリクスごとのレーティングの境界。
+type DORARatingBands struct {
+	DeployFrequency   DORABands // デプロイ頻度（回/月）。境界値以上ならそのレーティング
+	ChangeFailureRate DORABands // 変更失敗率（%）。境界値以下ならそのレーティング
+	MTTR              DORABands // 平均復旧時間（時間）。境界値未満ならそのレーティング
+}
+
+// DefaultDORARatingBands は DORA の State of DevOps で使われてきた境界。
+// デプロイ頻度は 毎日（月30回）・週1回（月4回）・月1回、変更失敗率は 15%・30%・45%、
+// MTTR は 1時間・1日・1週間。
+var DefaultDORARatingBands = DORARatingBands{
+	DeployFrequency:   DORABands{Elite: 30, High: 4, Medium: 1},
+	ChangeFailureRate: DORABands{Elite: 15, High: 30, Medium: 45},
+	MTTR:              DORABands{Elite: 1, High: 24, Medium: 168},
+}
+
+// Validate は境界が 0 以上で、Elite から Medium に向かって緩くなる順に並んでいるかを検証する。
+// 変更失敗率は 100% 以下とする。
+func (b DORARatingBands) Validate() error {
+	for _, v := range []struct {
+		name       string
+		bands      DORABands
+		descending bool // 大きいほど良いメトリクス（Elite の境界が最も大きい）
+		max        float64
+	}{
+		{"deployFrequency", b.DeployFrequency, true, 0},
+		{"changeFailureRate", b.ChangeFailureRate, false, 100},
+		{"mttrHours", b.MTTR, false, 0},
+	} {
+		e, h, m := v.bands.Elite, v.bands.High, v.bands.Medium
+		if e < 0 || h < 0 || m < 0 {
+			return fmt.Errorf("%s bands must not be negative: elite %g, high %g, medium %g", v.name, e, h, m)
+		}
+		if v.max > 0 && (e > v.max || h > v.max || m > v.max) {
+			return fmt.Errorf("%s bands must be %g or less: elite %g, high %g, medium %g", v.name, v.max, e, h, m)
+		}
+		if v.descending && (e < h || h < m) {
+			return fmt.Errorf("%s bands must satisfy elite >= high >= medium: elite %g, high %g, medium %g", v.name, e, h, m)
+		}
+		if !v.descending && (e > h || h > m) {
+			return fmt.Errorf("%s bands must satisfy elite <= high <= medium: elite %g, high %g, medium %g", v.name, e, h, m)
+		}
+	}
+	return nil
+}
+
+// DeployFrequencyRating はデプロイ頻度（回/月）の DORA レーティングを返す。
+func (b DORARatingBands) DeployFrequencyRating(freq float64) string {
+	switch {
+	case freq >= b.DeployFrequency.Elite:
+		return DORARatingElite
+	case freq >= b.DeployFrequency.High:
+		return DORARatingHigh
+	case freq >= b.DeployFrequency.Medium:
+		return DORARatingMedium
+	default:
+		return DORARatingLow
+	}
+}
+
+// ChangeFailureRateRating は変更失敗率（%）の DORA レーティングを返す。
+func (b DORARatingBands) ChangeFailureRateRating(cfr float64) string {
+	switch {
+	case cfr <= b.ChangeFailureRate.Elite:
+		return DORARatingElite
+	case cfr <= b.ChangeFailureRate.High:
+		return DORARatingHigh
+	case cfr <= b.ChangeFailureRate.Medium:
+		return DORARatingMedium
+	default:
+		return DORARatingLow
+	}
+}
+
+// MTTRRating は平均復旧時間（時間）の DORA レーティングを返す。
+func (b DORARatingBands) MTTRRating(hours float64) string {
+	switch {
+	case hours < b.MTTR.Elite:
+		return DORARatingElite
+	case hours < b.MTTR.High:
+		return DORARatingHigh
+	case hours < b.MTTR.Medium:
+		return DORARatingMedium
+	default:
+		return DORARatingLow
+	}
+}
@@ -0,0 +1,59 @@
+package domain
+
+import "testing"
+
+func TestDORARatingBands_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(b *DORARatingBands)
+		wantErr bool
+	}{
+		{"default", func(b *DORARatingBands) {}, false},
+		{"equal bands", func(b *DORARatingBands) { b.MTTR = DORABands{Elite: 24, High: 24, Medium: 24} }, false},
+		{"negative", func(b *DORARatingBands) { b.DeployFrequency.Medium = -1 }, true},
+		{"deploy frequency ascending", func(b *DORARatingBands) { b.DeployFrequency.High = 40 }, true},
+		{"change failure rate descending", func(b *DORARatingBands) { b.ChangeFailureRate.Elite = 35 }, true},
+		{"change failure rate over 100", func(b *DORARatingBands) { b.ChangeFailureRate.Medium = 120 }, true},
+		{"mttr descending", func(b *DORARatingBands) { b.MTTR.Medium = 12 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := DefaultDORARatingBands
+			tt.modify(&b)
+			if err := b.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDORARatingBands_Ratings(t *testing.T) {
+	b := DORARatingBands{
+		DeployFrequency:   DORABands{Elite: 20, High: 8, Medium: 2},
+		ChangeFailureRate: DORABands{Elite: 5, High: 10, Medium: 20},
+		MTTR:              DORABands{Elite: 4, High: 48, Medium: 336},
+	}
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"deploy on elite boundary", b.DeployFrequencyRating(20), DORARatingElite},
+		{"deploy high", b.DeployFrequencyRating(8), DORARatingHigh},
+		{"deploy medium", b.DeployFrequencyRating(2), DORARatingMedium},
+		{"deploy low", b.DeployFrequencyRating(1.9), DORARatingLow},
+		{"cfr on elite boundary", b.ChangeFailureRateRating(5), DORARatingElite},
+		{"cfr high", b.ChangeFailureRateRating(10), DORARatingHigh},
+		{"cfr medium", b.ChangeFailureRateRating(15), DORARatingMedium},
+		{"cfr low", b.ChangeFailureRateRating(20.1), DORARatingLow},
+		{"mttr elite", b.MTTRRating(3.9), DORARatingElite},
+		{"mttr on elite boundary", b.MTTRRating(4), DORARatingHigh},
+		{"mttr medium", b.MTTRRating(100), DORARatingMedium},
+		{"mttr low", b.MTTRRating(336), DORARatingLow},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: rating = %s, want %s", tt.name, tt.got, tt.want)
+		}
+	}
+}
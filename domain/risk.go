@@ -27,21 +27,48 @@ const (
 	// RiskTypeOwnership は属人化。
 	RiskTypeOwnership RiskType = "ownership"
 
+	// RiskTypeFileOwnership は1人の作成者が変更の大半を占めるファイル（知識のサイロ）がある。
+	RiskTypeFileOwnership RiskType = "file_ownership"
+
+	// RiskTypeBusFactor は CODEOWNERS 上で副担当のいない領域が大きい。
+	RiskTypeBusFactor RiskType = "bus_factor"
+
 	// RiskTypeOutdatedDeps は依存の古さ。
 	RiskTypeOutdatedDeps RiskType = "outdated_deps"
 
 	// RiskTypeLateNight は深夜労働。
 	RiskTypeLateNight RiskType = "late_night"
 
+	// RiskTypeWeekendWork は週末（土日）の作業が多い。
+	RiskTypeWeekendWork RiskType = "weekend_work"
+
 	// RiskTypeSlowLeadTime はPRリードタイムが長い。
 	RiskTypeSlowLeadTime RiskType = "slow_lead_time"
 
+	// RiskTypeStalePR は長期間オープンのままのPRが多い。
+	RiskTypeStalePR RiskType = "stale_pr"
+
+	// RiskTypeHighPRAbandonment はマージされずにクローズされるPRが多い。
+	RiskTypeHighPRAbandonment RiskType = "high_pr_abandonment"
+
 	// RiskTypeSlowReview はレビュー待ち時間が長い。
 	RiskTypeSlowReview RiskType = "slow_review"
 
 	// RiskTypeLargePR はPRサイズが大きい。
 	RiskTypeLargePR RiskType = "large_pr"
 
+	// RiskTypeDirectPush はPRを経由せずデフォルトブランチに入るコミットが多い。
+	RiskTypeDirectPush RiskType = "direct_push"
+
+	// RiskTypeLowReviewCoverage はレビューを受けずにマージされるPRが多い。
+	RiskTypeLowReviewCoverage RiskType = "low_review_coverage"
+
+	// RiskTypeSelfMerge は作成者以外の承認なしでマージされるPRが多い。
+	RiskTypeSelfMerge RiskType = "self_merge"
+
+	// RiskTypeLowCommitQuality は変更内容を説明していないコミットメッセージが多い。
+	RiskTypeLowCommitQuality RiskType = "low_commit_quality"
+
 	// RiskTypeLowIssueClose はIssueクローズ率が低い。
 	RiskTypeLowIssueClose RiskType = "low_issue_close"
 
@@ -57,27 +84,80 @@ const (
 	// RiskTypeSlowRecovery は復旧時間が長い。
 	RiskTypeSlowRecovery RiskType = "slow_recovery"
 
+	// RiskTypeReleaseDebt は最後のリリース以降、未リリースの変更が長く溜まっている。
+	RiskTypeReleaseDebt RiskType = "release_debt"
+
 	// RiskTypeLowFeatureInvestment は機能投資比率が低い。
 	RiskTypeLowFeatureInvestment RiskType = "low_feature_investment"
+
+	// RiskTypeVulnerableDependency は既知の脆弱性がある依存（--check-vulns 指定時のみ）。
+	RiskTypeVulnerableDependency RiskType = "vulnerable_dependency"
+
+	// RiskTypeMissingLicense はライセンスファイルがない。
+	RiskTypeMissingLicense RiskType = "missing_license"
+
+	// RiskTypeHighTodoDensity は TODO / FIXME / HACK コメントが多い。
+	RiskTypeHighTodoDensity RiskType = "high_todo_density"
+
+	// RiskTypeMissingCommunityFiles は CONTRIBUTING.md などのコミュニティヘルスファイルが揃っていない。
+	RiskTypeMissingCommunityFiles RiskType = "missing_community_files"
+
+	// RiskTypeNoBranchProtection はデフォルトブランチにレビュー必須の保護ルールがない。
+	RiskTypeNoBranchProtection RiskType = "no_branch_protection"
+
+	// RiskTypeHistoryRewrite はデフォルトブランチへの force push（履歴の書き換え）がある。
+	RiskTypeHistoryRewrite RiskType = "history_rewrite"
+
+	// RiskTypeFastRevert は入れてから24時間以内に Revert された変更がある。
+	RiskTypeFastRevert RiskType = "fast_revert"
+
+	// RiskTypeLargeCommits は1コミットあたりの変更ファイル数が多い（レビュー・bisect がしにくい粒度）。
+	RiskTypeLargeCommits RiskType = "large_commits"
+
+	// RiskTypeLowTestCoverage はソースファイルに対してテストファイルが少ない（ファイル数による推定で、実際のカバレッジではない）。
+	RiskTypeLowTestCoverage RiskType = "low_test_coverage"
+
+	// RiskTypeInactiveRepo は以前は活動していたが、期間内にコミット・マージ・リリースが1件もない（休眠）。
+	RiskTypeInactiveRepo RiskType = "inactive_repo"
 )
 
 // DisplayName はリスク種別の表示名を返す。
 func (r RiskType) DisplayName() string {
 	names := map[RiskType]string{
-		RiskTypeChangeConcentration:  "変更集中リスク",
-		RiskTypeLargeFile:            "巨大ファイル",
-		RiskTypeOwnership:            "属人化",
-		RiskTypeOutdatedDeps:         "依存の古さ",
-		RiskTypeLateNight:            "深夜労働",
-		RiskTypeSlowLeadTime:         "PRリードタイム超過",
-		RiskTypeSlowReview:           "レビュー待ち超過",
-		RiskTypeLargePR:              "PRサイズ超過",
-		RiskTypeLowIssueClose:        "Issueクローズ率低下",
-		RiskTypeBugFixHigh:           "バグ修正割合過多",
-		RiskTypeLowDeployFreq:        "デプロイ頻度不足",
-		RiskTypeHighChangeFailure:    "変更失敗率過多",
-		RiskTypeSlowRecovery:         "復旧時間超過",
-		RiskTypeLowFeatureInvestment: "機能投資不足",
+		RiskTypeChangeConcentration:   "変更集中リスク",
+		RiskTypeLargeFile:             "巨大ファイル",
+		RiskTypeOwnership:             "属人化",
+		RiskTypeFileOwnership:         "ファイルの属人化",
+		RiskTypeBusFactor:             "バス係数リスク",
+		RiskTypeOutdatedDeps:          "依存の古さ",
+		RiskTypeVulnerableDependency:  "脆弱性のある依存",
+		RiskTypeLateNight:             "深夜労働",
+		RiskTypeWeekendWork:           "週末労働",
+		RiskTypeSlowLeadTime:          "PRリードタイム超過",
+		RiskTypeStalePR:               "滞留PR",
+		RiskTypeHighPRAbandonment:     "PR放棄率過多",
+		RiskTypeSlowReview:            "レビュー待ち超過",
+		RiskTypeLargePR:               "PRサイズ超過",
+		RiskTypeDirectPush:            "PR未経由の直接プッシュ",
+		RiskTypeLowReviewCoverage:     "レビューカバレッジ不足",
+		RiskTypeSelfMerge:             "セルフマージ",
+		RiskTypeLowCommitQuality:      "コミットメッセージ品質低下",
+		RiskTypeLargeCommits:          "コミットサイズ超過",
+		RiskTypeLowTestCoverage:       "テストファイル不足（推定）",
+		RiskTypeLowIssueClose:         "Issueクローズ率低下",
+		RiskTypeBugFixHigh:            "バグ修正割合過多",
+		RiskTypeLowDeployFreq:         "デプロイ頻度不足",
+		RiskTypeHighChangeFailure:     "変更失敗率過多",
+		RiskTypeSlowRecovery:          "復旧時間超過",
+		RiskTypeReleaseDebt:           "リリース負債",
+		RiskTypeLowFeatureInvestment:  "機能投資不足",
+		RiskTypeMissingLicense:        "ライセンス未設定",
+		RiskTypeHighTodoDensity:       "TODOコメント過多",
+		RiskTypeMissingCommunityFiles: "コミュニティファイル不足",
+		RiskTypeNoBranchProtection:    "ブランチ保護なし",
+		RiskTypeHistoryRewrite:        "履歴の書き換え",
+		RiskTypeFastRevert:            "直後の Revert",
+		RiskTypeInactiveRepo:          "休眠リポジトリ",
 	}
 	if name, ok := names[r]; ok {
 		return name
@@ -88,13 +168,13 @@ func (r RiskType) DisplayName() string {
 // Category はリスクタイプが属するカテゴリを返す。
 func (r RiskType) Category() Category {
 	switch r {
-	case RiskTypeSlowLeadTime, RiskTypeSlowReview, RiskTypeLowDeployFreq, RiskTypeSlowRecovery:
+	case RiskTypeSlowLeadTime, RiskTypeStalePR, RiskTypeHighPRAbandonment, RiskTypeSlowReview, RiskTypeLowDeployFreq, RiskTypeSlowRecovery, RiskTypeReleaseDebt:
 		return CategoryVelocity
-	case RiskTypeChangeConcentration, RiskTypeLargePR, RiskTypeLowIssueClose, RiskTypeBugFixHigh, RiskTypeHighChangeFailure:
+	case RiskTypeChangeConcentration, RiskTypeLargePR, RiskTypeDirectPush, RiskTypeLowReviewCoverage, RiskTypeSelfMerge, RiskTypeNoBranchProtection, RiskTypeHistoryRewrite, RiskTypeFastRevert, RiskTypeLowCommitQuality, RiskTypeLargeCommits, RiskTypeLowTestCoverage, RiskTypeLowIssueClose, RiskTypeBugFixHigh, RiskTypeHighChangeFailure:
 		return CategoryQuality
-	case RiskTypeLargeFile, RiskTypeOutdatedDeps, RiskTypeLowFeatureInvestment:
+	case RiskTypeLargeFile, RiskTypeOutdatedDeps, RiskTypeVulnerableDependency, RiskTypeLowFeatureInvestment, RiskTypeMissingLicense, RiskTypeHighTodoDensity:
 		return CategoryTechDebt
-	case RiskTypeLateNight, RiskTypeOwnership:
+	case RiskTypeLateNight, RiskTypeWeekendWork, RiskTypeOwnership, RiskTypeFileOwnership, RiskTypeBusFactor, RiskTypeMissingCommunityFiles, RiskTypeInactiveRepo:
 		return CategoryHealth
 	default:
 		return CategoryQuality
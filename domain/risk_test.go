@@ -10,11 +10,22 @@ func TestRiskTypeDisplayName(t *testing.T) {
 		{RiskTypeChangeConcentration, "変更集中リスク"},
 		{RiskTypeLargeFile, "巨大ファイル"},
 		{RiskTypeOwnership, "属人化"},
+		{RiskTypeFileOwnership, "ファイルの属人化"},
+		{RiskTypeInactiveRepo, "休眠リポジトリ"},
+		{RiskTypeLowTestCoverage, "テストファイル不足（推定）"},
+		{RiskTypeLargeCommits, "コミットサイズ超過"},
+		{RiskTypeBusFactor, "バス係数リスク"},
 		{RiskTypeOutdatedDeps, "依存の古さ"},
 		{RiskTypeLateNight, "深夜労働"},
+		{RiskTypeWeekendWork, "週末労働"},
 		{RiskTypeSlowLeadTime, "PRリードタイム超過"},
 		{RiskTypeSlowReview, "レビュー待ち超過"},
+		{RiskTypeStalePR, "滞留PR"},
+		{RiskTypeHighPRAbandonment, "PR放棄率過多"},
 		{RiskTypeLargePR, "PRサイズ超過"},
+		{RiskTypeDirectPush, "PR未経由の直接プッシュ"},
+		{RiskTypeLowReviewCoverage, "レビューカバレッジ不足"},
+		{RiskTypeSelfMerge, "セルフマージ"},
 		{RiskTypeLowIssueClose, "Issueクローズ率低下"},
 		{RiskTypeBugFixHigh, "バグ修正割合過多"},
 		{RiskTypeLowDeployFreq, "デプロイ頻度不足"},
@@ -48,11 +59,18 @@ func TestRiskTypeCategory(t *testing.T) {
 		// Velocity
 		{RiskTypeSlowLeadTime, CategoryVelocity},
 		{RiskTypeSlowReview, CategoryVelocity},
+		{RiskTypeStalePR, CategoryVelocity},
+		{RiskTypeHighPRAbandonment, CategoryVelocity},
 		{RiskTypeLowDeployFreq, CategoryVelocity},
 		{RiskTypeSlowRecovery, CategoryVelocity},
 		// Quality
 		{RiskTypeChangeConcentration, CategoryQuality},
 		{RiskTypeLargePR, CategoryQuality},
+		{RiskTypeDirectPush, CategoryQuality},
+		{RiskTypeLowReviewCoverage, CategoryQuality},
+		{RiskTypeSelfMerge, CategoryQuality},
+		{RiskTypeLowTestCoverage, CategoryQuality},
+		{RiskTypeLargeCommits, CategoryQuality},
 		{RiskTypeLowIssueClose, CategoryQuality},
 		{RiskTypeBugFixHigh, CategoryQuality},
 		{RiskTypeHighChangeFailure, CategoryQuality},
@@ -62,7 +80,11 @@ func TestRiskTypeCategory(t *testing.T) {
 		{RiskTypeLowFeatureInvestment, CategoryTechDebt},
 		// Health
 		{RiskTypeLateNight, CategoryHealth},
+		{RiskTypeWeekendWork, CategoryHealth},
 		{RiskTypeOwnership, CategoryHealth},
+		{RiskTypeFileOwnership, CategoryHealth},
+		{RiskTypeBusFactor, CategoryHealth},
+		{RiskTypeInactiveRepo, CategoryHealth},
 	}
 	for _, tt := range tests {
 		t.Run(string(tt.riskType), func(t *testing.T) {
@@ -57,7 +57,12 @@ func (s Score) Grade() string {
 
 // GradeDescription はグレードの説明を返す。
 func (s Score) GradeDescription() string {
-	switch s.Grade() {
+	return GradeDescription(s.Grade())
+}
+
+// GradeDescription はグレード（A/B/C/D）の説明を返す。
+func GradeDescription(grade string) string {
+	switch grade {
 	case "A":
 		return "良好"
 	case "B":
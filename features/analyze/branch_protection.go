@@ -0,0 +1,25 @@
+package analyze
+
+import (
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// detectNoBranchProtection はデフォルトブランチにレビュー必須の保護ルールがなければリスクとして返す。
+// 保護がなければ誰でもレビューなしに直接プッシュできるため High、保護はあるが
+// 承認が必須でなければ Medium とする。保護設定を読めなかった（nil）場合は判定しない。
+func (s *Service) detectNoBranchProtection(bp *domain.BranchProtection) []domain.Risk {
+	if bp == nil || (bp.Protected && bp.RequiredApprovals > 0) {
+		return nil
+	}
+	risk := domain.Risk{
+		Type:        domain.RiskTypeNoBranchProtection,
+		Severity:    domain.SeverityHigh,
+		Target:      bp.Branch,
+		Description: s.lang.T("デフォルトブランチ（%s）にブランチ保護がありません", bp.Branch),
+	}
+	if bp.Protected {
+		risk.Severity = domain.SeverityMedium
+		risk.Description = s.lang.T("デフォルトブランチ（%s）の保護ルールでレビューが必須になっていません", bp.Branch)
+	}
+	return []domain.Risk{risk}
+}
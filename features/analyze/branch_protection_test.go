@@ -0,0 +1,59 @@
+package analyze
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+func TestAnalyze_BranchProtection(t *testing.T) {
+	tests := []struct {
+		name         string
+		protection   *domain.BranchProtection
+		wantSeverity domain.Severity
+		wantRisk     bool
+	}{
+		{"unknown", nil, 0, false},
+		{"not protected", &domain.BranchProtection{Branch: "main"}, domain.SeverityHigh, true},
+		{"protected without reviews", &domain.BranchProtection{Branch: "main", Protected: true, RequiredStatusChecks: true}, domain.SeverityMedium, true},
+		{"reviews required", &domain.BranchProtection{Branch: "main", Protected: true, RequiredApprovals: 1}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRepository{protection: tt.protection}
+			result, err := NewService(repo).Analyze(context.Background(), ServiceInput{
+				Repository: domain.NewRepository("owner", "repo"),
+				Period: domain.NewDateRange(
+					time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+					time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+				),
+			})
+			if err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+			if result.BranchProtection != tt.protection {
+				t.Errorf("BranchProtection = %+v, want %+v", result.BranchProtection, tt.protection)
+			}
+			var gotRisk *domain.Risk
+			for i, r := range result.Risks {
+				if r.Type == domain.RiskTypeNoBranchProtection {
+					gotRisk = &result.Risks[i]
+				}
+			}
+			if (gotRisk != nil) != tt.wantRisk {
+				t.Fatalf("branch protection risk = %v, want %v", gotRisk != nil, tt.wantRisk)
+			}
+			if gotRisk == nil {
+				return
+			}
+			if gotRisk.Severity != tt.wantSeverity || gotRisk.Target != "main" {
+				t.Errorf("risk = %+v, want severity %v on main", gotRisk, tt.wantSeverity)
+			}
+			if gotRisk.Type.Category() != domain.CategoryQuality {
+				t.Errorf("category = %s, want quality", gotRisk.Type.Category())
+			}
+		})
+	}
+}
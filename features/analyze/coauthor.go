@@ -0,0 +1,55 @@
+package analyze
+
+import (
+	"strings"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// coAuthorTrailer はペアプログラミング・モブプログラミングの共同作成者を示すトレーラー（小文字）。
+const coAuthorTrailer = "co-authored-by:"
+
+// coAuthors はコミットメッセージの Co-authored-by トレーラーから共同作成者（"Name <email>"）を返す。
+// GitHub の表記ゆれ（大文字小文字・行頭の空白）は許容し、名前のない行は数えない。
+func coAuthors(message string) []string {
+	var authors []string
+	for _, line := range strings.Split(message, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) < len(coAuthorTrailer) || !strings.EqualFold(line[:len(coAuthorTrailer)], coAuthorTrailer) {
+			continue
+		}
+		if author := strings.TrimSpace(line[len(coAuthorTrailer):]); author != "" {
+			authors = append(authors, author)
+		}
+	}
+	return authors
+}
+
+// countCoAuthoredCommits は共同作成者のいるコミット数をカウントする。
+func countCoAuthoredCommits(commits []Commit) int {
+	count := 0
+	for _, c := range commits {
+		if len(coAuthors(c.Message)) > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// mitigateOwnership は共同作成のコミットが多い期間の属人化リスクの重大度を下げる。
+//
+// ペアプログラミング・モブプログラミングで知識が共有されていれば、コミットの作成者が
+// 1人に偏っていても担当者の離脱による影響は小さいため。コミット数が少ない期間は判定しない。
+func (s *Service) mitigateOwnership(risks []domain.Risk, metrics domain.Metrics) []domain.Risk {
+	if metrics.TotalCommits < coAuthorshipMinCommits || metrics.CoAuthorshipRate < coAuthorshipMitigationPct {
+		return risks
+	}
+	for i, r := range risks {
+		if r.Type != domain.RiskTypeOwnership || r.Severity == domain.SeverityLow {
+			continue
+		}
+		risks[i].Severity = domain.SeverityLow
+		risks[i].Description += s.lang.T("（共同作成のコミットが%.0f%%あるため重大度を下げています）", metrics.CoAuthorshipRate)
+	}
+	return risks
+}
@@ -0,0 +1,116 @@
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+func TestCoAuthors(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []string
+	}{
+		{"no trailer", "Add login form", nil},
+		{"one co-author", "Add login form\n\nCo-authored-by: Bob <bob@example.com>", []string{"Bob <bob@example.com>"}},
+		{"two co-authors", "Pair on parser\n\nCo-authored-by: Bob <bob@example.com>\nCo-authored-by: Carol <carol@example.com>",
+			[]string{"Bob <bob@example.com>", "Carol <carol@example.com>"}},
+		{"case and indentation", "Fix\n\n  co-Authored-By:  Bob <bob@example.com>  ", []string{"Bob <bob@example.com>"}},
+		{"empty trailer", "Fix\n\nCo-authored-by:", nil},
+		{"mentioned in the middle of a line", "Document the Co-authored-by: trailer", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := coAuthors(tt.message); !slices.Equal(got, tt.want) {
+				t.Errorf("coAuthors() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountCoAuthoredCommits(t *testing.T) {
+	commits := []Commit{
+		{Message: "Add login form\n\nCo-authored-by: Bob <bob@example.com>"},
+		{Message: "Fix typo"},
+		{Message: "Pair on parser\n\nCo-authored-by: Bob <bob@example.com>\nCo-authored-by: Carol <carol@example.com>"},
+	}
+	if got := countCoAuthoredCommits(commits); got != 2 {
+		t.Errorf("countCoAuthoredCommits() = %d, want 2", got)
+	}
+}
+
+func TestMitigateOwnership(t *testing.T) {
+	ownership := func() []domain.Risk {
+		return []domain.Risk{
+			{Type: domain.RiskTypeOwnership, Severity: domain.SeverityMedium, Description: "偏り"},
+			{Type: domain.RiskTypeLateNight, Severity: domain.SeverityMedium},
+		}
+	}
+	tests := []struct {
+		name    string
+		metrics domain.Metrics
+		want    domain.Severity
+	}{
+		{"enough co-authored commits", domain.Metrics{TotalCommits: 20, CoAuthorshipRate: 25}, domain.SeverityLow},
+		{"few co-authored commits", domain.Metrics{TotalCommits: 20, CoAuthorshipRate: 10}, domain.SeverityMedium},
+		{"too few commits", domain.Metrics{TotalCommits: 5, CoAuthorshipRate: 100}, domain.SeverityMedium},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			risks := NewService(nil).mitigateOwnership(ownership(), tt.metrics)
+			if risks[0].Severity != tt.want {
+				t.Errorf("ownership Severity = %v, want %v", risks[0].Severity, tt.want)
+			}
+			if (risks[0].Description != "偏り") != (tt.want == domain.SeverityLow) {
+				t.Errorf("Description = %q", risks[0].Description)
+			}
+			// 属人化以外のリスクはそのまま
+			if risks[1].Severity != domain.SeverityMedium {
+				t.Errorf("late night Severity = %v, want Medium", risks[1].Severity)
+			}
+		})
+	}
+}
+
+func TestAnalyze_CoAuthorshipMitigation(t *testing.T) {
+	base := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	var commits []Commit
+	for i := range 10 {
+		msg := "Add feature"
+		if i < 5 {
+			msg += "\n\nCo-authored-by: Bob <bob@example.com>"
+		}
+		commits = append(commits, Commit{SHA: fmt.Sprintf("c%d", i), Author: "alice", Message: msg, Date: base.Add(-time.Duration(i) * time.Hour)})
+	}
+	input := ServiceInput{
+		Repository: domain.NewRepository("owner", "repo"),
+		Period:     domain.NewDateRange(base.AddDate(0, 0, -7), base),
+	}
+
+	for _, enabled := range []bool{false, true} {
+		repo := &mockRepository{
+			commits:      commits,
+			contributors: []Contributor{{Login: "alice", Contributions: 90}, {Login: "bob", Contributions: 10}},
+		}
+		result, err := NewService(repo, WithCoAuthorshipMitigation(enabled)).Analyze(context.Background(), input)
+		if err != nil {
+			t.Fatalf("Analyze() error = %v", err)
+		}
+		if result.Metrics.CoAuthoredCommitCount != 5 || result.Metrics.CoAuthorshipRate != 50 {
+			t.Errorf("co-authored = %d (%.1f%%), want 5 (50%%)", result.Metrics.CoAuthoredCommitCount, result.Metrics.CoAuthorshipRate)
+		}
+		want := domain.SeverityMedium
+		if enabled {
+			want = domain.SeverityLow
+		}
+		i := slices.IndexFunc(result.Risks, func(r domain.Risk) bool { return r.Type == domain.RiskTypeOwnership })
+		if i < 0 || result.Risks[i].Severity != want {
+			t.Errorf("mitigation %v: ownership risks = %+v, want severity %v", enabled, result.Risks, want)
+		}
+	}
+}
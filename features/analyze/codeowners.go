@@ -0,0 +1,119 @@
+package analyze
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// codeownersPaths は CODEOWNERS を探す場所（GitHub と同じ優先順）。
+var codeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// fetchCodeowners は CODEOWNERS を取得してルール一覧を返す。
+// どの場所にも存在しなければ nil を返す（エラーではない）。
+func (s *Service) fetchCodeowners(ctx context.Context, repo domain.Repository) []codeownersRule {
+	for _, p := range codeownersPaths {
+		content, err := s.repo.GetFileContent(ctx, repo, p)
+		if err != nil {
+			continue
+		}
+		return parseCodeowners(string(content))
+	}
+	return nil
+}
+
+// codeownersRule は CODEOWNERS の1行（パターンとオーナー）。
+type codeownersRule struct {
+	pattern string
+	owners  []string
+}
+
+// parseCodeowners は CODEOWNERS の内容をルール一覧に変換する。
+// コメント行・空行は無視する。オーナーなしの行も「所有者なし」として残す
+// （後勝ちで上書きされるため）。
+func parseCodeowners(content string) []codeownersRule {
+	var rules []codeownersRule
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, "#"); i != -1 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		rules = append(rules, codeownersRule{pattern: fields[0], owners: fields[1:]})
+	}
+	return rules
+}
+
+// ownersOf は filePath に適用されるオーナーを返す。
+// GitHub と同様に、最後にマッチしたルールが優先される。
+func ownersOf(rules []codeownersRule, filePath string) []string {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if matchCodeownersPattern(rules[i].pattern, filePath) {
+			return rules[i].owners
+		}
+	}
+	return nil
+}
+
+// matchCodeownersPattern は CODEOWNERS のパターンがファイルパスにマッチするかを返す。
+//
+// gitignore 形式のうち、CODEOWNERS で実際に使われる範囲に対応する:
+//   - "*"          全ファイル
+//   - "*.js"       任意の階層の拡張子
+//   - "/docs/"     ルート直下のディレクトリ以下すべて
+//   - "docs/"      任意の階層のディレクトリ以下すべて
+//   - "src/app"    ルートからのパス（途中に "/" を含むものはルート基準）
+//   - "docs/*"     ディレクトリ直下のファイルのみ
+//   - "**/logs"    任意の階層
+func matchCodeownersPattern(pattern, filePath string) bool {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	// "docs/*" は docs 直下のファイルのみ（サブディレクトリは含まない）
+	filesOnly := strings.HasSuffix(pattern, "/*")
+	pattern = strings.TrimSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/**")
+
+	anchored := strings.HasPrefix(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if strings.HasPrefix(pattern, "**/") {
+		pattern = strings.TrimPrefix(pattern, "**/")
+		anchored = false
+	} else if strings.Contains(pattern, "/") {
+		// 途中に "/" を含むパターンはルート基準
+		anchored = true
+	}
+	if pattern == "" {
+		return false
+	}
+
+	segments := strings.Split(filePath, "/")
+	patternDepth := strings.Count(pattern, "/") + 1
+
+	// 開始位置（ルート基準なら先頭のみ）× パターンと同じ深さの部分パスで照合する。
+	// 部分パスがディレクトリに当たる場合はその配下すべてがマッチする。
+	starts := len(segments)
+	if anchored {
+		starts = 1
+	}
+	for start := 0; start < starts; start++ {
+		end := start + patternDepth
+		if end > len(segments) {
+			break
+		}
+		// ディレクトリ指定はファイル名そのものにはマッチしない
+		if dirOnly && end == len(segments) {
+			continue
+		}
+		if filesOnly && end != len(segments) {
+			continue
+		}
+		candidate := strings.Join(segments[start:end], "/")
+		if ok, _ := path.Match(pattern, candidate); ok {
+			return true
+		}
+	}
+	return false
+}
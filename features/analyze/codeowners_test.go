@@ -0,0 +1,79 @@
+package analyze
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCodeowners(t *testing.T) {
+	content := `# コメント
+*       @org/core
+
+/docs/  @alice @bob # 末尾コメント
+vendor/
+`
+	got := parseCodeowners(content)
+	want := []codeownersRule{
+		{pattern: "*", owners: []string{"@org/core"}},
+		{pattern: "/docs/", owners: []string{"@alice", "@bob"}},
+		{pattern: "vendor/", owners: []string{}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseCodeowners() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMatchCodeownersPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*", "main.go", true},
+		{"*", "src/app/main.go", true},
+		{"*.js", "web/app.js", true},
+		{"*.js", "web/app.ts", false},
+		{"/docs/", "docs/guide.md", true},
+		{"/docs/", "src/docs/guide.md", false},
+		{"docs/", "src/docs/guide.md", true},
+		{"docs/", "docs", false},
+		{"src/app", "src/app/main.go", true},
+		{"src/app", "lib/src/app/main.go", false},
+		{"docs/*", "docs/guide.md", true},
+		{"docs/*", "docs/api/index.md", false},
+		{"/build/**", "build/out/a.o", true},
+		{"**/logs", "deep/nested/logs/app.log", true},
+		{"README.md", "README.md", true},
+		{"README.md", "pkg/README.md", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			if got := matchCodeownersPattern(tt.pattern, tt.path); got != tt.want {
+				t.Errorf("matchCodeownersPattern(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOwnersOf(t *testing.T) {
+	rules := parseCodeowners(`
+*          @org/core
+/src/      @alice
+/src/gen/
+`)
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"README.md", []string{"@org/core"}},
+		{"src/main.go", []string{"@alice"}},
+		// 後のルールが優先される（オーナーなしで上書き）
+		{"src/gen/types.go", []string{}},
+	}
+	for _, tt := range tests {
+		if got := ownersOf(rules, tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ownersOf(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package analyze
+
+import (
+	"strings"
+)
+
+// lowQualityCommitSubjects は単語数があっても中身のない件名（小文字・末尾の句読点を除いた形）。
+// 1単語の件名（"wip" / "fix" / "update" など）はこれとは別に一律で低品質とみなす。
+var lowQualityCommitSubjects = map[string]bool{
+	"work in progress": true,
+	"fix bug":          true,
+	"bug fix":          true,
+	"fix bugs":         true,
+	"minor fix":        true,
+	"minor fixes":      true,
+	"small fix":        true,
+	"minor changes":    true,
+	"some changes":     true,
+	"update code":      true,
+	"update files":     true,
+}
+
+// isLowQualityCommitMessage はコミットメッセージが変更内容を説明していないかを返す。
+// 次のいずれかに当てはまれば低品質とみなす。
+//   - 空（空白だけを含む）
+//   - 件名が1単語（"wip", "fix", "update" など）
+//   - 件名が WIP で始まる（"WIP: ...", "[WIP] ..."）
+//   - 件名が lowQualityCommitSubjects のいずれか
+//   - 本文のない "Merge ..." だけのマージコミット（PR のマージは本文に PR タイトルが入るため除く）
+//
+// git revert が作るコミット（"Revert ..."）は件名に元のコミットが入るため対象にしない。
+func isLowQualityCommitMessage(message string) bool {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return true
+	}
+	subject, body, _ := strings.Cut(message, "\n")
+	subject = strings.TrimSpace(subject)
+	body = strings.TrimSpace(body)
+
+	if strings.HasPrefix(subject, "Revert ") {
+		return false
+	}
+	if strings.HasPrefix(subject, "Merge ") {
+		return body == ""
+	}
+
+	normalized := strings.ToLower(strings.TrimRight(subject, ".!:;,"))
+	if strings.HasPrefix(normalized, "wip") || strings.HasPrefix(normalized, "[wip]") {
+		// "wipe ..." などの単語は除く
+		rest := strings.TrimPrefix(strings.TrimPrefix(normalized, "[wip]"), "wip")
+		if rest == "" || !isASCIILetter(rest[0]) {
+			return true
+		}
+	}
+	if len(strings.Fields(normalized)) <= 1 {
+		return true
+	}
+	return lowQualityCommitSubjects[normalized]
+}
+
+// isASCIILetter は b が英字かを返す。
+func isASCIILetter(b byte) bool {
+	return ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+}
+
+// countLowQualityCommits はメッセージが低品質なコミット数をカウントする。
+func countLowQualityCommits(commits []Commit) int {
+	count := 0
+	for _, c := range commits {
+		if isLowQualityCommitMessage(c.Message) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,47 @@
+package analyze
+
+import "testing"
+
+func TestIsLowQualityCommitMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    bool
+	}{
+		{"empty", "", true},
+		{"whitespace only", "  \n\t\n", true},
+		{"single word", "wip", true},
+		{"single word with punctuation", "Fix.", true},
+		{"single word with body", "update\n\nbump the cache TTL to one hour", true},
+		{"generic phrase", "Minor fixes", true},
+		{"wip prefix", "WIP: add login form", true},
+		{"bracketed wip prefix", "[WIP] add login form", true},
+		{"word starting with wip", "Wipe stale cache entries on startup", false},
+		{"descriptive subject", "Fix off-by-one in pagination", false},
+		{"conventional commit", "feat: add --scan-todos flag", false},
+		{"two words", "Initial commit", false},
+		{"bare merge", "Merge branch 'main' into feature/login", true},
+		{"bare merge with trailing newline", "Merge remote-tracking branch 'origin/main'\n", true},
+		{"pull request merge with title", "Merge pull request #12 from owner/login\n\nAdd login form", false},
+		{"revert", "Revert \"Add login form\"\n\nThis reverts commit abc123.", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isLowQualityCommitMessage(tt.message); got != tt.want {
+				t.Errorf("isLowQualityCommitMessage(%q) = %v, want %v", tt.message, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountLowQualityCommits(t *testing.T) {
+	commits := []Commit{
+		{Message: "fix"},
+		{Message: "Add retry to the HTTP client"},
+		{Message: ""},
+		{Message: "Merge branch 'main'"},
+	}
+	if got := countLowQualityCommits(commits); got != 3 {
+		t.Errorf("countLowQualityCommits() = %d, want 3", got)
+	}
+}
@@ -0,0 +1,28 @@
+package analyze
+
+// commitSize は1コミットあたりの変更ファイル数（コミットの粒度）の集計結果。
+type commitSize struct {
+	Commits  int     // 集計したコミット数
+	AvgFiles float64 // 1コミットあたりの平均変更ファイル数（集計したコミットがなければ 0）
+}
+
+// measureCommitSize は1コミットあたりの平均変更ファイル数を計算する。
+//
+// 変更ファイルは直近 maxCommitDetails 件のコミットしか取得しないため、変更ファイルが空のコミット
+// （未取得・取得失敗・.lokupignore で全ファイルを除外したもの）は集計しない。
+// マージコミットは取り込んだ変更をまとめて含むことがあるため除く。
+func measureCommitSize(commits []Commit) commitSize {
+	var r commitSize
+	files := 0
+	for _, c := range commits {
+		if len(c.Parents) > 1 || len(c.Files) == 0 {
+			continue
+		}
+		r.Commits++
+		files += len(c.Files)
+	}
+	if r.Commits > 0 {
+		r.AvgFiles = float64(files) / float64(r.Commits)
+	}
+	return r
+}
@@ -0,0 +1,37 @@
+package analyze
+
+import "testing"
+
+func TestMeasureCommitSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		commits []Commit
+		want    commitSize
+	}{
+		{"no commits", nil, commitSize{}},
+		{
+			"average over commits with files",
+			[]Commit{
+				{SHA: "a", Files: []string{"a.go"}},
+				{SHA: "b", Files: []string{"a.go", "b.go", "c.go"}},
+			},
+			commitSize{Commits: 2, AvgFiles: 2},
+		},
+		{
+			"skips merges and commits without file details",
+			[]Commit{
+				{SHA: "a", Files: []string{"a.go", "b.go"}},
+				{SHA: "m", Parents: []string{"a", "x"}, Files: []string{"x1.go", "x2.go", "x3.go"}},
+				{SHA: "c"}, // 変更ファイル未取得
+			},
+			commitSize{Commits: 1, AvgFiles: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := measureCommitSize(tt.commits); got != tt.want {
+				t.Errorf("measureCommitSize() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package analyze
+
+import (
+	"strings"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// communityFileRule はコミュニティヘルスファイル1種類の探し方。
+type communityFileRule struct {
+	name  string   // 表示名
+	paths []string // 探すパス（小文字で比較する）
+	dir   bool     // ディレクトリなら true（配下にファイルが1つでもあれば存在とみなす）
+}
+
+// communityFileRules はチェックするコミュニティヘルスファイル（表示順）。
+// GitHub と同じく、ルート・.github・docs のどこに置いても認識する。
+var communityFileRules = []communityFileRule{
+	{name: "CONTRIBUTING.md", paths: []string{"contributing.md", ".github/contributing.md", "docs/contributing.md"}},
+	{name: "SECURITY.md", paths: []string{"security.md", ".github/security.md", "docs/security.md"}},
+	{name: ".github/ISSUE_TEMPLATE", paths: []string{".github/issue_template"}, dir: true},
+	{name: ".github/PULL_REQUEST_TEMPLATE.md", paths: []string{".github/pull_request_template.md", "pull_request_template.md", "docs/pull_request_template.md"}},
+}
+
+// checkCommunityFiles はファイル一覧からコミュニティヘルスファイルの有無を調べる。
+// 中身は見ないため、ファイルごとに内容を取得せず GetFiles の結果だけで判定できる。
+func checkCommunityFiles(files []File) []domain.CommunityFile {
+	paths := make(map[string]bool, len(files))
+	for _, f := range files {
+		paths[strings.ToLower(f.Path)] = true
+	}
+
+	result := make([]domain.CommunityFile, len(communityFileRules))
+	for i, rule := range communityFileRules {
+		result[i] = domain.CommunityFile{Name: rule.name, Present: rule.matches(files, paths)}
+	}
+	return result
+}
+
+// matches はルールに合うファイルがあるかを返す。paths は小文字にしたパスの集合。
+func (r communityFileRule) matches(files []File, paths map[string]bool) bool {
+	for _, p := range r.paths {
+		if !r.dir {
+			if paths[p] {
+				return true
+			}
+			continue
+		}
+		for _, f := range files {
+			if strings.HasPrefix(strings.ToLower(f.Path), p+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// communityHealthScore は揃っているコミュニティヘルスファイルの割合（0-100）を返す。
+func communityHealthScore(files []domain.CommunityFile) int {
+	if len(files) == 0 {
+		return 0
+	}
+	present := 0
+	for _, f := range files {
+		if f.Present {
+			present++
+		}
+	}
+	return present * 100 / len(files)
+}
+
+// detectMissingCommunityFiles は足りないコミュニティヘルスファイルをまとめて1件のリスクとして返す。
+// 半分以上が欠けていれば Medium、それ以外は Low。
+func (s *Service) detectMissingCommunityFiles(files []domain.CommunityFile) []domain.Risk {
+	var missing []string
+	for _, f := range files {
+		if !f.Present {
+			missing = append(missing, f.Name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	severity := domain.SeverityLow
+	if len(missing)*2 >= len(files) {
+		severity = domain.SeverityMedium
+	}
+	return []domain.Risk{{
+		Type:        domain.RiskTypeMissingCommunityFiles,
+		Severity:    severity,
+		Target:      s.lang.T("リポジトリ全体"),
+		Description: s.lang.T("コミュニティヘルスファイルが不足しています: %s", strings.Join(missing, ", ")),
+		Value:       len(missing),
+		Threshold:   len(files),
+	}}
+}
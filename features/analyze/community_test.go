@@ -0,0 +1,98 @@
+package analyze
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+func TestCheckCommunityFiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  []bool // communityFileRules の順
+	}{
+		{"none", []string{"README.md", "main.go"}, []bool{false, false, false, false}},
+		{"all at preferred locations", []string{
+			"CONTRIBUTING.md",
+			"SECURITY.md",
+			".github/ISSUE_TEMPLATE/bug_report.md",
+			".github/PULL_REQUEST_TEMPLATE.md",
+		}, []bool{true, true, true, true}},
+		{"alternate locations and case", []string{
+			".github/contributing.md",
+			"docs/SECURITY.md",
+			".github/issue_template/config.yml",
+			"docs/pull_request_template.md",
+		}, []bool{true, true, true, true}},
+		{"nested file is not recognized", []string{"pkg/CONTRIBUTING.md", "src/SECURITY.md"}, []bool{false, false, false, false}},
+		{"issue template needs a file inside the directory", []string{".github/ISSUE_TEMPLATE.md", ".github/ISSUE_TEMPLATES/bug.md"}, []bool{false, false, false, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := make([]File, len(tt.files))
+			for i, p := range tt.files {
+				files[i] = File{Path: p}
+			}
+			got := checkCommunityFiles(files)
+			present := make([]bool, len(got))
+			for i, f := range got {
+				present[i] = f.Present
+				if f.Name != communityFileRules[i].name {
+					t.Errorf("got[%d].Name = %q, want %q", i, f.Name, communityFileRules[i].name)
+				}
+			}
+			if !reflect.DeepEqual(present, tt.want) {
+				t.Errorf("present = %v, want %v", present, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectMissingCommunityFiles(t *testing.T) {
+	files := func(present ...bool) []domain.CommunityFile {
+		cf := make([]domain.CommunityFile, len(present))
+		for i, p := range present {
+			cf[i] = domain.CommunityFile{Name: communityFileRules[i].name, Present: p}
+		}
+		return cf
+	}
+	tests := []struct {
+		name         string
+		files        []domain.CommunityFile
+		wantScore    int
+		wantRisk     bool
+		wantSeverity domain.Severity
+		wantValue    int
+	}{
+		{"all present", files(true, true, true, true), 100, false, 0, 0},
+		{"one missing", files(true, false, true, true), 75, true, domain.SeverityLow, 1},
+		{"half missing", files(true, false, false, true), 50, true, domain.SeverityMedium, 2},
+		{"all missing", files(false, false, false, false), 0, true, domain.SeverityMedium, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := communityHealthScore(tt.files); got != tt.wantScore {
+				t.Errorf("communityHealthScore() = %d, want %d", got, tt.wantScore)
+			}
+			risks := (&Service{}).detectMissingCommunityFiles(tt.files)
+			if (len(risks) > 0) != tt.wantRisk {
+				t.Fatalf("risks = %v, want risk %v", risks, tt.wantRisk)
+			}
+			if !tt.wantRisk {
+				return
+			}
+			r := risks[0]
+			if r.Type.Category() != domain.CategoryHealth {
+				t.Errorf("category = %s, want health", r.Type.Category())
+			}
+			if r.Severity != tt.wantSeverity {
+				t.Errorf("severity = %v, want %v", r.Severity, tt.wantSeverity)
+			}
+			if r.Value != tt.wantValue || r.Threshold != len(tt.files) {
+				t.Errorf("value/threshold = %d/%d, want %d/%d", r.Value, r.Threshold, tt.wantValue, len(tt.files))
+			}
+		})
+	}
+}
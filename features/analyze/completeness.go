@@ -0,0 +1,154 @@
+package analyze
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// truncationKey は一覧取得の打ち切りを記録するフラグを ctx に入れるキー。
+type truncationKey struct{}
+
+// withTruncationFlag は Repository の一覧取得が上限で打ち切られたことを受け取る ctx と、そのフラグを返す。
+func withTruncationFlag(ctx context.Context) (context.Context, *atomic.Bool) {
+	flag := &atomic.Bool{}
+	return context.WithValue(ctx, truncationKey{}, flag), flag
+}
+
+// MarkTruncated は ctx で行っている一覧取得がページ数などの上限で打ち切られ、
+// 一部しか返せないことを記録する。Repository の実装が打ち切ったときに呼ぶ。
+// 記録先のない ctx（Analyze 以外からの呼び出し）では何もしない。
+func MarkTruncated(ctx context.Context) {
+	if flag, ok := ctx.Value(truncationKey{}).(*atomic.Bool); ok {
+		flag.Store(true)
+	}
+}
+
+// dataSourceKind はレポートに取得状況を出すデータの種類。値の順にレポートに並べる。
+type dataSourceKind int
+
+const (
+	sourceCommits dataSourceKind = iota
+	sourceCommitDetails
+	sourceContributors
+	sourceClosedPRs
+	sourceOpenPRs
+	sourcePRDetails
+	sourceIssues
+	sourceIssueEvents
+	sourceOpenIssues
+	sourceFiles
+	sourceTodos
+	sourceBranchProtection
+	sourceForcePushes
+	sourceDependencies
+	sourceVulnerabilities
+	sourceDeployments
+	sourcePrevCommits
+	sourcePrevIssues
+	numDataSources
+)
+
+// dataSourceNames はデータの種類ごとの表示名。
+var dataSourceNames = [numDataSources]string{
+	sourceCommits:          "コミット",
+	sourceCommitDetails:    "コミットの変更ファイル",
+	sourceContributors:     "コントリビューター",
+	sourceClosedPRs:        "クローズ済みPR",
+	sourceOpenPRs:          "オープンPR",
+	sourcePRDetails:        "PR詳細（サイズ・レビュー）",
+	sourceIssues:           "Issue",
+	sourceIssueEvents:      "障害Issueのイベント",
+	sourceOpenIssues:       "オープンIssue",
+	sourceFiles:            "ファイル一覧",
+	sourceTodos:            "TODO コメントの走査",
+	sourceBranchProtection: "ブランチ保護",
+	sourceForcePushes:      "force push",
+	sourceDependencies:     "依存",
+	sourceVulnerabilities:  "依存の脆弱性",
+	sourceDeployments:      "デプロイ",
+	sourcePrevCommits:      "前期のコミット",
+	sourcePrevIssues:       "前期のIssue",
+}
+
+// sourceLog は並行に取得したデータの取得状況を集める。
+type sourceLog struct {
+	mu      sync.Mutex
+	sources [numDataSources]*domain.DataSource
+}
+
+// record はデータ1種類の取得状況を記録する。Name は kind から埋める。
+func (l *sourceLog) record(kind dataSourceKind, src domain.DataSource) {
+	src.Name = dataSourceNames[kind]
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sources[kind] = &src
+}
+
+// list は記録した取得状況を種類の順に返す（記録がなければ nil）。
+func (l *sourceLog) list() []domain.DataSource {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var sources []domain.DataSource
+	for _, src := range l.sources {
+		if src != nil {
+			sources = append(sources, *src)
+		}
+	}
+	return sources
+}
+
+// fetchedSource は一覧取得1回分の取得状況を返す。
+// truncated は Repository が上限で打ち切ったか（withTruncationFlag のフラグ）。
+func fetchedSource(items int, truncated bool, err error) domain.DataSource {
+	src := domain.DataSource{Fetched: items, Truncated: truncated}
+	if err != nil {
+		src.Error = err.Error()
+	}
+	return src
+}
+
+// cappedSource は対象のうち上限までを1件ずつ取得したデータの取得状況を返す。
+// total は対象の件数、attempted は取得を試みた件数、failed はそのうち失敗した件数。
+func cappedSource(total, attempted, failed int) domain.DataSource {
+	src := domain.DataSource{Fetched: attempted - failed, Failed: failed}
+	if total > attempted {
+		src.Total = total
+	}
+	return src
+}
+
+// prDetailsSource はPR詳細の取得状況を返す。対象はマージ済みPRで、レビューを取得できなかったPRを失敗に数える。
+func prDetailsSource(closedPRs []PullRequest, details []domain.PRDetail) domain.DataSource {
+	merged := 0
+	for _, pr := range closedPRs {
+		if pr.MergedAt != nil {
+			merged++
+		}
+	}
+	failed := 0
+	for _, d := range details {
+		if !d.ReviewsFetched {
+			failed++
+		}
+	}
+	return cappedSource(merged, len(details), failed)
+}
+
+// dependencySource は依存の取得状況を返す。依存を読み取れたエコシステムを補足に入れる。
+func dependencySource(deps []Dependency, err error) domain.DataSource {
+	src := fetchedSource(len(deps), false, err)
+	var ecosystems []string
+	for _, dep := range deps {
+		if !slices.Contains(ecosystems, dep.PackageType) {
+			ecosystems = append(ecosystems, dep.PackageType)
+		}
+	}
+	slices.Sort(ecosystems)
+	src.Note = strings.Join(ecosystems, ", ")
+	return src
+}
@@ -0,0 +1,124 @@
+package analyze
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+func TestMarkTruncated(t *testing.T) {
+	// 記録先のない ctx では何もしない
+	MarkTruncated(context.Background())
+
+	ctx, truncated := withTruncationFlag(context.Background())
+	if truncated.Load() {
+		t.Fatal("truncated before MarkTruncated")
+	}
+	MarkTruncated(ctx)
+	if !truncated.Load() {
+		t.Error("MarkTruncated did not set the flag")
+	}
+}
+
+func TestCappedSource(t *testing.T) {
+	tests := []struct {
+		name                     string
+		total, attempted, failed int
+		want                     domain.DataSource
+		complete                 bool
+	}{
+		{name: "all", total: 12, attempted: 12, want: domain.DataSource{Fetched: 12}, complete: true},
+		{name: "capped", total: 134, attempted: 20, want: domain.DataSource{Fetched: 20, Total: 134}},
+		{name: "failed", total: 5, attempted: 5, failed: 2, want: domain.DataSource{Fetched: 3, Failed: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cappedSource(tt.total, tt.attempted, tt.failed)
+			if got != tt.want {
+				t.Errorf("cappedSource() = %+v, want %+v", got, tt.want)
+			}
+			if got.Complete() != tt.complete {
+				t.Errorf("Complete() = %v, want %v", got.Complete(), tt.complete)
+			}
+		})
+	}
+}
+
+func TestDependencySource(t *testing.T) {
+	deps := []Dependency{
+		{Name: "github.com/a/b", PackageType: "go"},
+		{Name: "react", PackageType: "npm"},
+		{Name: "github.com/c/d", PackageType: "go"},
+	}
+	if got := dependencySource(deps, nil); got.Fetched != 3 || got.Note != "go, npm" || !got.Complete() {
+		t.Errorf("dependencySource() = %+v, want 3 deps from go, npm", got)
+	}
+	if got := dependencySource(nil, errors.New("boom")); got.Error != "boom" || got.Complete() {
+		t.Errorf("dependencySource(error) = %+v, want an incomplete source with the error", got)
+	}
+}
+
+func TestAnalyze_DataSources(t *testing.T) {
+	base := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	input := ServiceInput{
+		Repository: domain.NewRepository("owner", "repo"),
+		Period:     domain.NewDateRange(base.AddDate(0, 0, -30), base.AddDate(0, 0, 1)),
+	}
+	var prs []PullRequest
+	for i := range maxPRDetailsCount + 5 {
+		merged := base.AddDate(0, 0, -i)
+		prs = append(prs, PullRequest{Number: 100 - i, CreatedAt: merged.Add(-time.Hour), MergedAt: &merged})
+	}
+	newRepo := func() *mockRepository {
+		return &mockRepository{
+			commits:         []Commit{{SHA: "a1", Author: "alice", Date: base}},
+			closedPRs:       prs,
+			truncateCommits: true,
+			failOn:          "GetDependencies",
+		}
+	}
+	find := func(t *testing.T, sources []domain.DataSource, name string) domain.DataSource {
+		t.Helper()
+		for _, src := range sources {
+			if src.Name == name {
+				return src
+			}
+		}
+		t.Fatalf("data source %q not found in %+v", name, sources)
+		return domain.DataSource{}
+	}
+
+	result, err := NewService(newRepo()).Analyze(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if len(result.DataSources) == 0 || result.DataSources[0].Name != "コミット" {
+		t.Fatalf("DataSources = %+v, want commits first", result.DataSources)
+	}
+	if got := find(t, result.DataSources, "コミット"); !got.Truncated || got.Fetched != 1 {
+		t.Errorf("commits = %+v, want 1 commit marked truncated", got)
+	}
+	if got := find(t, result.DataSources, "PR詳細（サイズ・レビュー）"); got.Fetched != maxPRDetailsCount || got.Total != len(prs) {
+		t.Errorf("PR details = %+v, want %d/%d", got, maxPRDetailsCount, len(prs))
+	}
+	if got := find(t, result.DataSources, "依存"); got.Error == "" || got.Complete() {
+		t.Errorf("dependencies = %+v, want the fetch error", got)
+	}
+	if got := find(t, result.DataSources, "オープンIssue"); !got.Complete() {
+		t.Errorf("open issues = %+v, want complete", got)
+	}
+
+	// クイックモードでは PR詳細・依存を取得しない
+	result, err = NewService(newRepo(), WithQuickMode(true)).Analyze(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	for _, name := range []string{"PR詳細（サイズ・レビュー）", "依存"} {
+		if got := find(t, result.DataSources, name); !got.Skipped {
+			t.Errorf("%s = %+v, want skipped in quick mode", name, got)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// DeploySource は DORA メトリクスで「デプロイ」とみなすイベントの取得元。
+type DeploySource string
+
+const (
+	DeploySourceReleases  DeploySource = "releases"  // GitHub Releases の公開（デフォルト）
+	DeploySourceTags      DeploySource = "tags"      // タグの作成（タグが指すコミットの日時）
+	DeploySourceWorkflows DeploySource = "workflows" // デプロイ用ワークフローの成功した実行
+)
+
+// ParseDeploySource は文字列をデプロイの取得元に変換する。空ならデフォルト（releases）。
+func ParseDeploySource(s string) (DeploySource, error) {
+	switch src := DeploySource(strings.ToLower(s)); src {
+	case "":
+		return DeploySourceReleases, nil
+	case DeploySourceReleases, DeploySourceTags, DeploySourceWorkflows:
+		return src, nil
+	default:
+		return "", fmt.Errorf("unsupported deploy source: %q (use releases, tags or workflows)", s)
+	}
+}
+
+// fetchDeployments は設定された取得元からデプロイ一覧を取得する。
+// デプロイ頻度・変更失敗率の計算をそのまま使えるよう、どの取得元も Release に変換して返す。
+func (s *Service) fetchDeployments(ctx context.Context, repo domain.Repository, period domain.DateRange) ([]Release, error) {
+	switch s.deploySource {
+	case DeploySourceTags:
+		tags, err := s.repo.GetTags(ctx, repo)
+		if err != nil {
+			return nil, err
+		}
+		return tagsToDeployments(tags), nil
+	case DeploySourceWorkflows:
+		runs, err := s.repo.GetWorkflowRuns(ctx, repo, s.deployWorkflow, period)
+		if err != nil {
+			return nil, err
+		}
+		return workflowRunsToDeployments(runs), nil
+	default:
+		return s.repo.GetReleases(ctx, repo)
+	}
+}
+
+// tagsToDeployments はタグをデプロイ（Release）に変換する。日時はタグが指すコミットの日時。
+func tagsToDeployments(tags []Tag) []Release {
+	deployments := make([]Release, len(tags))
+	for i, t := range tags {
+		deployments[i] = Release{TagName: t.Name, Name: t.Name, PublishedAt: t.Date}
+	}
+	return deployments
+}
+
+// workflowRunsToDeployments はワークフロー実行をデプロイ（Release）に変換する。
+func workflowRunsToDeployments(runs []WorkflowRun) []Release {
+	deployments := make([]Release, len(runs))
+	for i, r := range runs {
+		deployments[i] = Release{ID: r.ID, Name: r.Name, PublishedAt: r.CreatedAt}
+	}
+	return deployments
+}
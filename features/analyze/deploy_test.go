@@ -0,0 +1,86 @@
+package analyze
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+func TestParseDeploySource(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    DeploySource
+		wantErr bool
+	}{
+		{"", DeploySourceReleases, false},
+		{"releases", DeploySourceReleases, false},
+		{"tags", DeploySourceTags, false},
+		{"Workflows", DeploySourceWorkflows, false},
+		{"deployments", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseDeploySource(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDeploySource(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDeploySource(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyze_DeploySource(t *testing.T) {
+	period := domain.NewDateRange(
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+	)
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 12, 0, 0, 0, time.UTC) }
+	repo := &mockRepository{
+		commits: []Commit{{SHA: "a", Author: "alice", Date: day(5), Message: "feat: x"}},
+		releases: []Release{
+			{TagName: "v1.0.0", PublishedAt: day(10)},
+		},
+		tags: []Tag{
+			{Name: "v1.0.2", Date: day(20)},
+			{Name: "v1.0.1", Date: day(15)},
+			{Name: "v1.0.0", Date: day(10)},
+			{Name: "v0.9.0", Date: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)}, // 期間外
+		},
+		workflowRuns: []WorkflowRun{
+			{ID: 1, Name: "Deploy", CreatedAt: day(3)},
+			{ID: 2, Name: "CI", CreatedAt: day(4)},
+			{ID: 3, Name: "Deploy", CreatedAt: day(6)},
+			{ID: 4, Name: "Deploy", CreatedAt: day(9)},
+			{ID: 5, Name: "Deploy", CreatedAt: day(12)},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		opts  []Option
+		wantN int // 期間内のデプロイ数
+	}{
+		{"default releases", nil, 1},
+		{"tags", []Option{WithDeploySource(DeploySourceTags, "")}, 3},
+		{"workflows", []Option{WithDeploySource(DeploySourceWorkflows, "Deploy")}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewService(repo, tt.opts...).Analyze(context.Background(), ServiceInput{
+				Repository: domain.NewRepository("owner", "repo"),
+				Period:     period,
+			})
+			if err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+			want := float64(tt.wantN) / (float64(period.Days()) / 30.0)
+			if result.Metrics.DeployFrequency != want {
+				t.Errorf("DeployFrequency = %v, want %v", result.Metrics.DeployFrequency, want)
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package analyze
 
 import (
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/ryuka-games/lokup/domain"
 )
@@ -9,6 +11,8 @@ import (
 // ── DORA メトリクス計算 ──────────────────────────────────────
 
 // calculateDeployFrequency は期間内のデプロイ頻度（リリース/月）とDORAレーティングを計算する。
+// 期間が domain.MinDeployFreqWindowDays 日より短いときは、頻度は返すがレーティングは
+// domain.DeployFreqRatingWindowTooShort とする。
 func (s *Service) calculateDeployFrequency(releases []Release, period domain.DateRange) (float64, string) {
 	if len(releases) == 0 {
 		return 0, "N/A"
@@ -26,26 +30,28 @@ func (s *Service) calculateDeployFrequency(releases []Release, period domain.Dat
 		days = 1
 	}
 	freq := float64(count) / (float64(days) / 30.0)
+	if days < domain.MinDeployFreqWindowDays {
+		return freq, domain.DeployFreqRatingWindowTooShort
+	}
 
-	rating := doraDeployFreqRating(freq)
+	rating := s.doraDeployFreqRating(freq)
 	return freq, rating
 }
 
 // doraDeployFreqRating はデプロイ頻度からDORAレーティングを返す。
-func doraDeployFreqRating(freq float64) string {
-	switch {
-	case freq >= 30: // daily or more
-		return "Elite"
-	case freq >= 4: // weekly
-		return "High"
-	case freq >= 1: // monthly
-		return "Medium"
-	default:
-		return "Low"
-	}
+// 境界は WithDORARatingBands で設定したもの（未設定なら DORA の公開値）を使う。3つのレーティングで共通。
+func (s *Service) doraDeployFreqRating(freq float64) string {
+	return s.doraRatingBands().DeployFrequencyRating(freq)
 }
 
+// revertIncidentWindow は障害Issueと同じ障害への対応とみなす Revert コミットの時間幅。
+// Issue の作成日時の前後この範囲の Revert は、Issue 側で数えているため二重に数えない。
+const revertIncidentWindow = 48 * time.Hour
+
 // calculateChangeFailureRate は変更失敗率（%）とDORAレーティングを計算する。
+//
+// 障害指標は障害ラベルの Issue と、どの障害Issueにも対応しない Revert コミットの合計。
+// 障害がデプロイ数より多くても 100% を上限とする。
 func (s *Service) calculateChangeFailureRate(issues []Issue, releases []Release, commits []Commit, period domain.DateRange) (float64, string) {
 	// デプロイ数 = 期間内リリース数
 	deployCount := 0
@@ -58,41 +64,27 @@ func (s *Service) calculateChangeFailureRate(issues []Issue, releases []Release,
 		return 0, "N/A"
 	}
 
-	// 障害指標: bug/incident/hotfixラベルのIssue + Revertコミット
-	failureCount := 0
+	// 障害指標: 障害ラベル（デフォルト: bug/incident/hotfix）のIssue + Revertコミット
+	var failureTimes []time.Time
 	for _, issue := range issues {
-		if !issue.CreatedAt.Before(period.From) && !issue.CreatedAt.After(period.To) {
-			for _, label := range issue.Labels {
-				lower := strings.ToLower(label)
-				if lower == "bug" || lower == "incident" || lower == "hotfix" {
-					failureCount++
-					break
-				}
-			}
+		if !issue.CreatedAt.Before(period.From) && !issue.CreatedAt.After(period.To) && s.isFailureIssue(issue) {
+			failureTimes = append(failureTimes, issue.CreatedAt)
 		}
 	}
-	failureCount += countRevertCommits(commits)
+	failureCount := len(failureTimes) + countUncorrelatedReverts(commits, failureTimes)
 
-	cfr := float64(failureCount) / float64(deployCount) * 100
-	rating := doraChangeFailRating(cfr)
+	cfr := min(float64(failureCount)/float64(deployCount)*100, 100)
+	rating := s.doraChangeFailRating(cfr)
 	return cfr, rating
 }
 
 // doraChangeFailRating は変更失敗率からDORAレーティングを返す。
-func doraChangeFailRating(cfr float64) string {
-	switch {
-	case cfr <= 15:
-		return "Elite"
-	case cfr <= 30:
-		return "High"
-	case cfr <= 45:
-		return "Medium"
-	default:
-		return "Low"
-	}
+func (s *Service) doraChangeFailRating(cfr float64) string {
+	return s.doraRatingBands().ChangeFailureRateRating(cfr)
 }
 
 // calculateMTTR は平均復旧時間（時間）とDORAレーティングを計算する。
+// 復旧作業の開始はイベントから求めた日時（RecoveryStartedAt）があればそれを、なければ Issue の作成日時とする。
 func (s *Service) calculateMTTR(issues []Issue, period domain.DateRange) (float64, string) {
 	var totalHours float64
 	var count int
@@ -104,22 +96,17 @@ func (s *Service) calculateMTTR(issues []Issue, period domain.DateRange) (float6
 		if issue.CreatedAt.Before(period.From) || issue.CreatedAt.After(period.To) {
 			continue
 		}
-		// bugラベルのIssueのみ対象
-		isBug := false
-		for _, label := range issue.Labels {
-			lower := strings.ToLower(label)
-			if lower == "bug" || lower == "incident" || lower == "hotfix" {
-				isBug = true
-				break
-			}
-		}
-		if !isBug {
+		// 障害ラベルのIssueのみ対象
+		if !s.isFailureIssue(issue) {
 			continue
 		}
 
-		hours := issue.ClosedAt.Sub(issue.CreatedAt).Hours()
-		if hours >= 0 {
-			totalHours += hours
+		started := issue.CreatedAt
+		if issue.RecoveryStartedAt != nil {
+			started = *issue.RecoveryStartedAt
+		}
+		if d, ok := elapsed(started, *issue.ClosedAt); ok {
+			totalHours += d.Hours()
 			count++
 		}
 	}
@@ -129,31 +116,129 @@ func (s *Service) calculateMTTR(issues []Issue, period domain.DateRange) (float6
 	}
 
 	mttr := totalHours / float64(count)
-	rating := doraMTTRRating(mttr)
+	rating := s.doraMTTRRating(mttr)
 	return mttr, rating
 }
 
 // doraMTTRRating はMTTRからDORAレーティングを返す。
-func doraMTTRRating(mttr float64) string {
-	switch {
-	case mttr < 1:
-		return "Elite"
-	case mttr < 24:
-		return "High"
-	case mttr < 168: // 1 week
-		return "Medium"
-	default:
-		return "Low"
-	}
+func (s *Service) doraMTTRRating(mttr float64) string {
+	return s.doraRatingBands().MTTRRating(mttr)
 }
 
 // countRevertCommits はRevertコミット数をカウントする。
 func countRevertCommits(commits []Commit) int {
 	count := 0
 	for _, c := range commits {
-		if strings.HasPrefix(c.Message, "Revert ") {
+		if isRevertCommit(c) {
 			count++
 		}
 	}
 	return count
 }
+
+// countUncorrelatedReverts は、どの障害Issueの作成日時とも revertIncidentWindow 以内にない
+// Revertコミット数をカウントする。
+func countUncorrelatedReverts(commits []Commit, failureTimes []time.Time) int {
+	count := 0
+	for _, c := range commits {
+		if !isRevertCommit(c) {
+			continue
+		}
+		correlated := false
+		for _, t := range failureTimes {
+			if d := c.Date.Sub(t); d >= -revertIncidentWindow && d <= revertIncidentWindow {
+				correlated = true
+				break
+			}
+		}
+		if !correlated {
+			count++
+		}
+	}
+	return count
+}
+
+// isRevertCommit は git revert で作られたコミット（"Revert " で始まる）かを返す。
+func isRevertCommit(c Commit) bool {
+	return strings.HasPrefix(c.Message, "Revert ")
+}
+
+// fastRevertWindow は元のコミットからこの時間内の Revert を「すぐに取り消された変更」とみなす。
+// 入れた直後に取り消す変更は、DORA の「ロールバックで復旧した失敗デプロイ」に近い。
+const fastRevertWindow = 24 * time.Hour
+
+var (
+	// revertedSHARe は git revert が本文に書く "This reverts commit <sha>." から元のコミットのハッシュを拾う。
+	revertedSHARe = regexp.MustCompile(`This reverts commit ([0-9a-fA-F]{7,40})`)
+	// revertSubjectRe は Revert の件名 `Revert "<元の件名>"` から元の件名を拾う
+	// （GitHub の Revert ボタンで作ったPRをスカッシュマージすると末尾に " (#123)" が付く）。
+	revertSubjectRe = regexp.MustCompile(`^Revert "(.+)"(?: \(#\d+\))?$`)
+)
+
+// revertTiming は Revert コミットと取り消された元のコミットの対応付けの結果。
+type revertTiming struct {
+	Matched  int     // 元のコミットを特定できた Revert の数
+	Fast     int     // 元のコミットから fastRevertWindow 以内の Revert の数
+	AvgHours float64 // 元のコミットから Revert までの平均時間（特定できたものだけ、なければ 0）
+}
+
+// measureTimeToRevert は Revert コミットごとに取り消された元のコミットを commits から探し、
+// 元のコミットから Revert までの時間を集計する。
+// 期間より前のコミットを取り消した Revert は元のコミットが見つからないため集計に含めない。
+func measureTimeToRevert(commits []Commit) revertTiming {
+	var t revertTiming
+	var totalHours float64
+	for _, c := range commits {
+		if !isRevertCommit(c) {
+			continue
+		}
+		original, ok := findRevertedCommit(c, commits)
+		if !ok {
+			continue
+		}
+		d := c.Date.Sub(original.Date)
+		t.Matched++
+		totalHours += d.Hours()
+		if d < fastRevertWindow {
+			t.Fast++
+		}
+	}
+	if t.Matched > 0 {
+		t.AvgHours = totalHours / float64(t.Matched)
+	}
+	return t
+}
+
+// findRevertedCommit は Revert コミット r が取り消した元のコミットを commits から探す。
+//
+// 本文の "This reverts commit <sha>." のハッシュ（短縮形も可）で探し、見つからなければ
+// （スカッシュマージでハッシュが変わった場合など）件名が元の件名と一致するコミットのうち、
+// r より前で最も新しいものを使う。r より後のコミットは元のコミットとみなさない。
+func findRevertedCommit(r Commit, commits []Commit) (Commit, bool) {
+	if m := revertedSHARe.FindStringSubmatch(r.Message); m != nil {
+		sha := strings.ToLower(m[1])
+		for _, c := range commits {
+			if c.SHA != "" && strings.HasPrefix(strings.ToLower(c.SHA), sha) && !c.Date.After(r.Date) {
+				return c, true
+			}
+		}
+	}
+
+	subject, _, _ := strings.Cut(r.Message, "\n")
+	m := revertSubjectRe.FindStringSubmatch(strings.TrimSpace(subject))
+	if m == nil {
+		return Commit{}, false
+	}
+	var original Commit
+	found := false
+	for _, c := range commits {
+		if c.Date.After(r.Date) {
+			continue
+		}
+		s, _, _ := strings.Cut(c.Message, "\n")
+		if strings.TrimSpace(s) == m[1] && (!found || c.Date.After(original.Date)) {
+			original, found = c, true
+		}
+	}
+	return original, found
+}
@@ -49,6 +49,33 @@ func TestCalculateDeployFrequency(t *testing.T) {
 			t.Errorf("freq = %v, want 1.0", freq)
 		}
 	})
+
+	t.Run("short windows", func(t *testing.T) {
+		release := []Release{{PublishedAt: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}}
+		tests := []struct {
+			name       string
+			days       int
+			wantRating string
+		}{
+			{"7 days", 7, domain.DeployFreqRatingWindowTooShort}, // 月換算 ~4.3 回でも High にしない
+			{"1 day", 1, domain.DeployFreqRatingWindowTooShort},
+			{"13 days", 13, domain.DeployFreqRatingWindowTooShort},
+			{"14 days", 14, "Medium"}, // 月換算 ~2.1 回
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+				short := domain.NewDateRange(from, from.AddDate(0, 0, tt.days))
+				freq, rating := s.calculateDeployFrequency(release, short)
+				if want := 30.0 / float64(tt.days); freq != want {
+					t.Errorf("freq = %v, want %v", freq, want)
+				}
+				if rating != tt.wantRating {
+					t.Errorf("rating = %q, want %q", rating, tt.wantRating)
+				}
+			})
+		}
+	})
 }
 
 func TestDoraDeployFreqRating(t *testing.T) {
@@ -65,8 +92,9 @@ func TestDoraDeployFreqRating(t *testing.T) {
 		{0.5, "Low"},
 		{0, "Low"},
 	}
+	s := &Service{}
 	for _, tt := range tests {
-		got := doraDeployFreqRating(tt.freq)
+		got := s.doraDeployFreqRating(tt.freq)
 		if got != tt.want {
 			t.Errorf("doraDeployFreqRating(%v) = %q, want %q", tt.freq, got, tt.want)
 		}
@@ -107,6 +135,103 @@ func TestCalculateChangeFailureRate(t *testing.T) {
 			t.Errorf("cfr = %v, want 50.0", cfr)
 		}
 	})
+
+	t.Run("custom failure labels", func(t *testing.T) {
+		custom := &Service{}
+		WithFailureLabels([]string{"type:defect", "SEV1"})(custom)
+		releases := []Release{
+			{PublishedAt: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)},
+			{PublishedAt: time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)},
+			{PublishedAt: time.Date(2025, 1, 25, 0, 0, 0, 0, time.UTC)},
+			{PublishedAt: time.Date(2025, 1, 28, 0, 0, 0, 0, time.UTC)},
+		}
+		issues := []Issue{
+			{CreatedAt: time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC), Labels: []string{"Type:Defect"}},
+			{CreatedAt: time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC), Labels: []string{"sev1"}},
+			{CreatedAt: time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC), Labels: []string{"bug"}}, // デフォルトのラベルは対象外になる
+		}
+		cfr, _ := custom.calculateChangeFailureRate(issues, releases, nil, period)
+		// 2 failures / 4 deploys = 50%
+		if cfr != 50.0 {
+			t.Errorf("cfr = %v, want 50.0", cfr)
+		}
+	})
+}
+
+func TestCalculateChangeFailureRate_Reverts(t *testing.T) {
+	s := &Service{}
+	period := domain.NewDateRange(
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+	)
+	releases := []Release{
+		{PublishedAt: time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{PublishedAt: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)},
+		{PublishedAt: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{PublishedAt: time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)},
+	}
+	issues := []Issue{
+		{CreatedAt: time.Date(2025, 1, 12, 9, 0, 0, 0, time.UTC), Labels: []string{"incident"}},
+	}
+
+	tests := []struct {
+		name    string
+		commits []Commit
+		want    float64
+	}{
+		{
+			name: "revert within window of an incident is not double counted",
+			commits: []Commit{
+				{Message: `Revert "Add cache layer"`, Date: time.Date(2025, 1, 12, 10, 0, 0, 0, time.UTC)},
+			},
+			want: 25.0, // 1 failure / 4 deploys
+		},
+		{
+			name: "revert before the issue was filed is also correlated",
+			commits: []Commit{
+				{Message: `Revert "Add cache layer"`, Date: time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC)},
+			},
+			want: 25.0,
+		},
+		{
+			name: "revert unrelated to any incident counts",
+			commits: []Commit{
+				{Message: `Revert "Bump deps"`, Date: time.Date(2025, 1, 25, 0, 0, 0, 0, time.UTC)},
+				{Message: "Fix typo", Date: time.Date(2025, 1, 25, 0, 0, 0, 0, time.UTC)},
+			},
+			want: 50.0, // (1 issue + 1 revert) / 4 deploys
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfr, _ := s.calculateChangeFailureRate(issues, releases, tt.commits, period)
+			if cfr != tt.want {
+				t.Errorf("cfr = %v, want %v", cfr, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateChangeFailureRate_CappedAt100(t *testing.T) {
+	s := &Service{}
+	period := domain.NewDateRange(
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+	)
+	releases := []Release{{PublishedAt: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)}}
+	issues := []Issue{
+		{CreatedAt: time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC), Labels: []string{"bug"}},
+		{CreatedAt: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), Labels: []string{"bug"}},
+		{CreatedAt: time.Date(2025, 1, 25, 0, 0, 0, 0, time.UTC), Labels: []string{"hotfix"}},
+	}
+
+	cfr, rating := s.calculateChangeFailureRate(issues, releases, nil, period)
+	if cfr != 100.0 {
+		t.Errorf("cfr = %v, want 100 (3 failures / 1 deploy capped)", cfr)
+	}
+	if rating != "Low" {
+		t.Errorf("rating = %q, want Low", rating)
+	}
 }
 
 func TestDoraChangeFailRating(t *testing.T) {
@@ -122,8 +247,9 @@ func TestDoraChangeFailRating(t *testing.T) {
 		{45, "Medium"},
 		{46, "Low"},
 	}
+	s := &Service{}
 	for _, tt := range tests {
-		got := doraChangeFailRating(tt.cfr)
+		got := s.doraChangeFailRating(tt.cfr)
 		if got != tt.want {
 			t.Errorf("doraChangeFailRating(%v) = %q, want %q", tt.cfr, got, tt.want)
 		}
@@ -179,6 +305,37 @@ func TestCalculateMTTR(t *testing.T) {
 			t.Errorf("rating = %q, want N/A", rating)
 		}
 	})
+
+	t.Run("zero and skewed close times excluded", func(t *testing.T) {
+		var zero time.Time
+		skewed := time.Date(2025, 1, 9, 0, 0, 0, 0, time.UTC) // 作成より前
+		closed6h := time.Date(2025, 1, 10, 6, 0, 0, 0, time.UTC)
+		created := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+		issues := []Issue{
+			{CreatedAt: created, ClosedAt: &zero, Labels: []string{"bug"}},
+			{CreatedAt: created, ClosedAt: &skewed, Labels: []string{"bug"}},
+			{CreatedAt: created, ClosedAt: &closed6h, Labels: []string{"bug"}},
+		}
+		mttr, _ := s.calculateMTTR(issues, period)
+		if mttr != 6.0 {
+			t.Errorf("mttr = %v, want 6.0", mttr)
+		}
+	})
+
+	t.Run("custom failure labels", func(t *testing.T) {
+		custom := &Service{}
+		WithFailureLabels([]string{"type:defect", "sev1"})(custom)
+		closed12h := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+		closed48h := time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC)
+		issues := []Issue{
+			{CreatedAt: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), ClosedAt: &closed12h, Labels: []string{"SEV1"}},
+			{CreatedAt: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), ClosedAt: &closed48h, Labels: []string{"bug"}}, // デフォルトのラベルは対象外になる
+		}
+		mttr, _ := custom.calculateMTTR(issues, period)
+		if mttr != 12.0 {
+			t.Errorf("mttr = %v, want 12.0", mttr)
+		}
+	})
 }
 
 func TestDoraMTTRRating(t *testing.T) {
@@ -194,14 +351,88 @@ func TestDoraMTTRRating(t *testing.T) {
 		{168, "Low"},
 		{500, "Low"},
 	}
+	s := &Service{}
 	for _, tt := range tests {
-		got := doraMTTRRating(tt.mttr)
+		got := s.doraMTTRRating(tt.mttr)
 		if got != tt.want {
 			t.Errorf("doraMTTRRating(%v) = %q, want %q", tt.mttr, got, tt.want)
 		}
 	}
 }
 
+func TestDoraRating_CustomBands(t *testing.T) {
+	// 週1回以上を Elite とする、DORA より緩いデプロイ頻度と、DORA より厳しい変更失敗率・MTTR の独自の境界
+	custom := NewService(nil, WithDORARatingBands(domain.DORARatingBands{
+		DeployFrequency:   domain.DORABands{Elite: 4, High: 2, Medium: 0.5},
+		ChangeFailureRate: domain.DORABands{Elite: 5, High: 10, Medium: 20},
+		MTTR:              domain.DORABands{Elite: 4, High: 48, Medium: 336},
+	}))
+	defaults := NewService(nil)
+
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	period := domain.NewDateRange(day(1), day(31))
+	releases := func(n int) []Release {
+		r := make([]Release, n)
+		for i := range r {
+			r[i] = Release{PublishedAt: day(2 + i*2)}
+		}
+		return r
+	}
+	bugs := func(n int) []Issue {
+		issues := make([]Issue, n)
+		for i := range issues {
+			issues[i] = Issue{CreatedAt: day(3 + i), Labels: []string{"bug"}}
+		}
+		return issues
+	}
+	incident := func(hours int) []Issue {
+		closed := day(10).Add(time.Duration(hours) * time.Hour)
+		return []Issue{{CreatedAt: day(10), ClosedAt: &closed, Labels: []string{"bug"}}}
+	}
+	deploy := func(n int) func(s *Service) string {
+		return func(s *Service) string {
+			_, rating := s.calculateDeployFrequency(releases(n), period)
+			return rating
+		}
+	}
+	cfr := func(failures, deploys int) func(s *Service) string {
+		return func(s *Service) string {
+			_, rating := s.calculateChangeFailureRate(bugs(failures), releases(deploys), nil, period)
+			return rating
+		}
+	}
+	mttr := func(hours int) func(s *Service) string {
+		return func(s *Service) string {
+			_, rating := s.calculateMTTR(incident(hours), period)
+			return rating
+		}
+	}
+
+	tests := []struct {
+		name        string
+		rate        func(s *Service) string
+		wantCustom  string
+		wantDefault string
+	}{
+		{"deploy 4/month", deploy(4), "Elite", "High"},
+		{"deploy 1/month", deploy(1), "Medium", "Medium"},
+		{"cfr 10%", cfr(1, 10), "High", "Elite"},
+		{"cfr 30%", cfr(3, 10), "Low", "High"},
+		{"mttr 3h", mttr(3), "Elite", "High"},
+		{"mttr 200h", mttr(200), "Medium", "Low"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rate(custom); got != tt.wantCustom {
+				t.Errorf("rating with custom bands = %q, want %q", got, tt.wantCustom)
+			}
+			if got := tt.rate(defaults); got != tt.wantDefault {
+				t.Errorf("rating with default bands = %q, want %q", got, tt.wantDefault)
+			}
+		})
+	}
+}
+
 func TestCountRevertCommits(t *testing.T) {
 	commits := []Commit{
 		{Message: "feat: add feature"},
@@ -225,3 +456,71 @@ func TestCountRevertCommits_none(t *testing.T) {
 		t.Errorf("countRevertCommits() = %d, want 0", got)
 	}
 }
+
+func TestMeasureTimeToRevert(t *testing.T) {
+	at := func(day, hour int) time.Time { return time.Date(2025, 1, day, hour, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name    string
+		commits []Commit
+		want    revertTiming
+	}{
+		{
+			name: "matched by sha in the body",
+			commits: []Commit{
+				{SHA: "abc1234def", Message: "feat: add cache layer", Date: at(10, 9)},
+				{SHA: "fff0000aaa", Message: "Revert \"feat: add cache layer\"\n\nThis reverts commit abc1234def.", Date: at(10, 15)},
+			},
+			want: revertTiming{Matched: 1, Fast: 1, AvgHours: 6},
+		},
+		{
+			name: "short sha",
+			commits: []Commit{
+				{SHA: "abc1234def", Message: "feat: add cache layer", Date: at(10, 9)},
+				{SHA: "fff0000aaa", Message: "Revert something\n\nThis reverts commit abc1234.", Date: at(12, 9)},
+			},
+			want: revertTiming{Matched: 1, AvgHours: 48},
+		},
+		{
+			name: "squash merged revert falls back to the subject",
+			commits: []Commit{
+				{SHA: "0001", Message: "Add login form (#12)", Date: at(3, 9)},
+				{SHA: "0002", Message: "Add login form (#12)", Date: at(5, 9)},
+				{SHA: "0003", Message: "Revert \"Add login form (#12)\" (#13)\n\nThis reverts commit 9999999.", Date: at(5, 21)},
+			},
+			// 同じ件名が複数あれば Revert より前の直近のもの
+			want: revertTiming{Matched: 1, Fast: 1, AvgHours: 12},
+		},
+		{
+			name: "revert of a revert",
+			commits: []Commit{
+				{SHA: "0001", Message: "Add login form", Date: at(3, 9)},
+				{SHA: "0002", Message: "Revert \"Add login form\"", Date: at(3, 10)},
+				{SHA: "0003", Message: "Revert \"Revert \"Add login form\"\"", Date: at(6, 10)},
+			},
+			want: revertTiming{Matched: 2, Fast: 1, AvgHours: 36.5},
+		},
+		{
+			name: "original outside the period",
+			commits: []Commit{
+				{SHA: "0002", Message: "Revert \"Bump deps\"\n\nThis reverts commit 1234567.", Date: at(3, 10)},
+			},
+			want: revertTiming{},
+		},
+		{
+			name: "commit after the revert is not the original",
+			commits: []Commit{
+				{SHA: "0002", Message: "Revert \"Bump deps\"", Date: at(3, 10)},
+				{SHA: "0003", Message: "Bump deps", Date: at(4, 10)},
+			},
+			want: revertTiming{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := measureTimeToRevert(tt.commits); got != tt.want {
+				t.Errorf("measureTimeToRevert() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,355 @@
+package analyze
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// fetchGroup は並行に実行する取得処理をまとめる（errgroup.WithContext 相当）。
+// 最初に失敗した処理のエラーを保持し、残りの処理に ctx のキャンセルで中断を伝える。
+type fetchGroup struct {
+	wg      sync.WaitGroup
+	cancel  context.CancelFunc
+	errOnce sync.Once
+	err     error
+
+	// 処理が1つ終わるたびに呼ぶ（nil なら呼ばない）。total は Go で始めた処理の数
+	onDone            func(done, total int)
+	started, finished atomic.Int32
+}
+
+// newFetchGroup は fetchGroup と、いずれかの処理が失敗したらキャンセルされる ctx を返す。
+func newFetchGroup(ctx context.Context) (*fetchGroup, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &fetchGroup{cancel: cancel}, ctx
+}
+
+// Go は f を別 goroutine で実行する。
+func (g *fetchGroup) Go(f func() error) {
+	g.wg.Add(1)
+	g.started.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer func() {
+			if done := g.finished.Add(1); g.onDone != nil {
+				g.onDone(int(done), int(g.started.Load()))
+			}
+		}()
+		if err := f(); err != nil {
+			g.errOnce.Do(func() {
+				g.err = err
+				g.cancel()
+			})
+		}
+	}()
+}
+
+// Wait はすべての処理の終了を待ち、最初のエラーを返す。
+func (g *fetchGroup) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+	return g.err
+}
+
+// fetchedData は Analyze のデータ取得フェーズの結果。
+type fetchedData struct {
+	metrics        metricsInput             // 今期のメトリクス計算用（period 以外の取得結果）
+	codeowners     []codeownersRule         // バス係数リスク検出用（なければ nil）
+	ignoreRules    []ignoreRule             // ファイル系リスクから除外するパターン（なければ nil）
+	licenseFile    string                   // ライセンスファイルのパス（なければ空）
+	communityFiles []domain.CommunityFile   // コミュニティヘルスファイルの有無
+	filesTruncated bool                     // ファイル一覧の一部しか取得できなかった
+	protection     *domain.BranchProtection // デフォルトブランチの保護設定（読めなければ nil）
+	forcePushes    []ForcePush              // 期間内のデフォルトブランチへの force push（取得できなければ nil）
+	todos          todoScan                 // TODO コメントの走査結果（WithTodoScan 指定時のみ）
+	dependencies   []Dependency             // 古い依存検出用
+	vulnerableDeps []domain.VulnerableDep   // 既知の脆弱性がある依存（WithVulnerabilityCheck 指定時のみ）
+	vulnChecked    int                      // 脆弱性を調べた依存の数（調べなかった・失敗したら 0）
+	prevCommits    []Commit                 // トレンド比較用
+	prevIssues     []Issue                  // トレンド比較用
+	prevMergedPRs  []PullRequest            // トレンド比較用（前期にマージされたPR）
+	sources        sourceLog                // データの種類ごとの取得状況
+}
+
+// fetchData は分析に必要なデータを並行に取得する。
+//
+// 各 API 呼び出しは互いに独立しているため、直列に待つとラウンドトリップの合計が
+// 実行時間の大半を占める。分析に必須のデータの取得に失敗したら残りをキャンセルして
+// エラーを返す。依存・リリース・前期データなど補助的なデータの失敗は警告に留める。
+func (s *Service) fetchData(ctx context.Context, input ServiceInput, prevPeriod domain.DateRange) (*fetchedData, error) {
+	repo := input.Repository
+	d := &fetchedData{metrics: metricsInput{period: input.Period}}
+	g, ctx := newFetchGroup(ctx)
+	s.reportProgress(Progress{Repository: repo, Phase: PhaseFetching})
+	g.onDone = func(done, total int) {
+		s.reportProgress(Progress{Repository: repo, Phase: PhaseFetching, Done: done, Total: total})
+	}
+
+	// 必須データ（失敗したら分析全体を中断）
+	g.Go(func() error {
+		start := time.Now()
+		ctx, truncated := withTruncationFlag(ctx)
+		commits, err := s.repo.GetCommits(ctx, repo, input.Period, s.path, s.branch)
+		s.logFetch("commits", start, len(commits), err)
+		if err != nil {
+			return err
+		}
+		d.sources.record(sourceCommits, fetchedSource(len(commits), truncated.Load(), nil))
+		// 直近のコミットの変更ファイルを取得（変更集中リスク検出用）
+		start = time.Now()
+		n := min(len(commits), s.maxCommitDetails())
+		failed := s.fillCommitFiles(ctx, repo, commits)
+		s.logFetch("commit details", start, n, nil)
+		d.sources.record(sourceCommitDetails, cappedSource(len(commits), n, failed))
+		for i := range commits {
+			commits[i].Files = filterPaths(commits[i].Files, s.path)
+		}
+		d.metrics.commits = commits
+		return nil
+	})
+	g.Go(func() (err error) {
+		start := time.Now()
+		ctx, truncated := withTruncationFlag(ctx)
+		d.metrics.contributors, err = s.repo.GetContributors(ctx, repo)
+		s.logFetch("contributors", start, len(d.metrics.contributors), err)
+		d.sources.record(sourceContributors, fetchedSource(len(d.metrics.contributors), truncated.Load(), err))
+		return err
+	})
+	g.Go(func() (err error) {
+		// マージ済みPR（リードタイム計算用）
+		start := time.Now()
+		ctx, truncated := withTruncationFlag(ctx)
+		d.metrics.closedPRs, err = s.repo.GetPullRequests(ctx, repo, "closed")
+		s.logFetch("closed pull requests", start, len(d.metrics.closedPRs), err)
+		d.sources.record(sourceClosedPRs, fetchedSource(len(d.metrics.closedPRs), truncated.Load(), err))
+		return err
+	})
+	g.Go(func() (err error) {
+		start := time.Now()
+		ctx, truncated := withTruncationFlag(ctx)
+		d.metrics.openPRs, err = s.repo.GetPullRequests(ctx, repo, "open")
+		s.logFetch("open pull requests", start, len(d.metrics.openPRs), err)
+		d.sources.record(sourceOpenPRs, fetchedSource(len(d.metrics.openPRs), truncated.Load(), err))
+		return err
+	})
+	g.Go(func() (err error) {
+		// 期間内の作成・クローズを計算
+		start := time.Now()
+		ctx, truncated := withTruncationFlag(ctx)
+		periodStart := input.Period.From
+		d.metrics.allIssues, err = s.repo.GetIssues(ctx, repo, "all", &periodStart)
+		s.logFetch("issues", start, len(d.metrics.allIssues), err)
+		d.sources.record(sourceIssues, fetchedSource(len(d.metrics.allIssues), truncated.Load(), err))
+		if err != nil || s.mttrSource != MTTRSourceEvents {
+			return err
+		}
+		// MTTR の開始をトリアージ後（障害ラベルの付与・アサイン）にする
+		start = time.Now()
+		total, attempted, failed := s.fillRecoveryStarts(ctx, repo, d.metrics.allIssues, input.Period)
+		s.logFetch("issue events", start, attempted, nil)
+		d.sources.record(sourceIssueEvents, cappedSource(total, attempted, failed))
+		return nil
+	})
+	g.Go(func() (err error) {
+		start := time.Now()
+		ctx, truncated := withTruncationFlag(ctx)
+		d.metrics.openIssues, err = s.repo.GetIssues(ctx, repo, "open", nil)
+		s.logFetch("open issues", start, len(d.metrics.openIssues), err)
+		d.sources.record(sourceOpenIssues, fetchedSource(len(d.metrics.openIssues), truncated.Load(), err))
+		return err
+	})
+	g.Go(func() (err error) {
+		// 巨大ファイル・バス係数・コミュニティヘルス検出用
+		start := time.Now()
+		files, truncated, err := s.repo.GetFiles(ctx, repo, s.branch)
+		s.logFetch("files", start, len(files), err)
+		d.sources.record(sourceFiles, fetchedSource(len(files), truncated, err))
+		if err != nil {
+			return err
+		}
+		d.filesTruncated = truncated
+		// コミュニティヘルスファイルはリポジトリ単位のため、--path や .lokupignore で絞る前に見る
+		d.communityFiles = checkCommunityFiles(files)
+		d.metrics.files = filterFiles(files, s.path)
+		return nil
+	})
+
+	// 補助データ（失敗しても分析は続ける）
+	g.Go(func() error {
+		start := time.Now()
+		d.codeowners = s.fetchCodeowners(ctx, repo)
+		s.logFetch("codeowners rules", start, len(d.codeowners), nil)
+		return nil
+	})
+	g.Go(func() error {
+		start := time.Now()
+		d.licenseFile = s.fetchLicenseFile(ctx, repo)
+		found := 0
+		if d.licenseFile != "" {
+			found = 1
+		}
+		s.logFetch("license file", start, found, nil)
+		return nil
+	})
+	g.Go(func() error {
+		start := time.Now()
+		protection, err := s.repo.GetBranchProtection(ctx, repo)
+		found := 0
+		if protection != nil {
+			found = 1
+		}
+		s.logFetch("branch protection", start, found, err)
+		if err != nil {
+			s.warnUnlessCanceled(ctx, "failed to get branch protection", err)
+		}
+		src := fetchedSource(found, false, err)
+		if protection == nil && err == nil {
+			src.Note = "権限不足などで読み取れませんでした"
+		}
+		d.sources.record(sourceBranchProtection, src)
+		d.protection = protection
+		return nil
+	})
+	g.Go(func() error {
+		start := time.Now()
+		ctx, truncated := withTruncationFlag(ctx)
+		pushes, err := s.repo.GetForcePushes(ctx, repo, input.Period)
+		s.logFetch("force pushes", start, len(pushes), err)
+		d.sources.record(sourceForcePushes, fetchedSource(len(pushes), truncated.Load(), err))
+		if err != nil {
+			s.warnUnlessCanceled(ctx, "failed to get force pushes", err)
+		}
+		d.forcePushes = pushes
+		return nil
+	})
+	g.Go(func() error {
+		start := time.Now()
+		d.ignoreRules = s.fetchIgnoreRules(ctx, repo)
+		s.logFetch("ignore rules", start, len(d.ignoreRules), nil)
+		return nil
+	})
+	g.Go(func() error {
+		// クイックモードでは依存のリリース日をレジストリに問い合わせない
+		if s.quick {
+			d.sources.record(sourceDependencies, domain.DataSource{Skipped: true})
+			return nil
+		}
+		start := time.Now()
+		deps, err := s.repo.GetDependencies(ctx, repo)
+		s.logFetch("dependencies", start, len(deps), err)
+		d.sources.record(sourceDependencies, dependencySource(deps, err))
+		if err != nil {
+			s.warnUnlessCanceled(ctx, "failed to get dependencies", err)
+		}
+		d.dependencies = deps
+		if s.vulnChecker != nil && len(deps) > 0 {
+			start = time.Now()
+			ids, err := s.vulnChecker.CheckVulnerabilities(ctx, deps)
+			d.vulnerableDeps = vulnerableDependencies(deps, ids)
+			s.logFetch("vulnerable dependencies", start, len(d.vulnerableDeps), err)
+			if err != nil {
+				s.warnUnlessCanceled(ctx, "failed to check vulnerabilities", err)
+			} else {
+				d.vulnChecked = len(deps)
+			}
+			d.sources.record(sourceVulnerabilities, fetchedSource(d.vulnChecked, false, err))
+		}
+		return nil
+	})
+	g.Go(func() error {
+		// DORA デプロイ頻度用
+		start := time.Now()
+		ctx, truncated := withTruncationFlag(ctx)
+		releases, err := s.fetchDeployments(ctx, repo, input.Period)
+		s.logFetch("deployments", start, len(releases), err)
+		d.sources.record(sourceDeployments, fetchedSource(len(releases), truncated.Load(), err))
+		if err != nil {
+			s.warnUnlessCanceled(ctx, "failed to get deployments", err)
+			releases = nil
+		}
+		d.metrics.releases = releases
+		return nil
+	})
+	g.Go(func() error {
+		start := time.Now()
+		ctx, truncated := withTruncationFlag(ctx)
+		prevCommits, err := s.repo.GetCommits(ctx, repo, prevPeriod, s.path, s.branch)
+		s.logFetch("previous period commits", start, len(prevCommits), err)
+		d.sources.record(sourcePrevCommits, fetchedSource(len(prevCommits), truncated.Load(), err))
+		if err != nil {
+			s.warnUnlessCanceled(ctx, "failed to get previous period commits", err)
+			prevCommits = nil
+		}
+		d.prevCommits = prevCommits
+		return nil
+	})
+	g.Go(func() error {
+		start := time.Now()
+		ctx, truncated := withTruncationFlag(ctx)
+		prevPeriodStart := prevPeriod.From
+		prevIssues, err := s.repo.GetIssues(ctx, repo, "all", &prevPeriodStart)
+		s.logFetch("previous period issues", start, len(prevIssues), err)
+		d.sources.record(sourcePrevIssues, fetchedSource(len(prevIssues), truncated.Load(), err))
+		if err != nil {
+			s.warnUnlessCanceled(ctx, "failed to get previous period issues", err)
+			prevIssues = nil
+		}
+		d.prevIssues = prevIssues
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	// ドラフトは作業中なので、以降のPR系の集計（前期を含む）から外す
+	if !s.includeDrafts {
+		d.metrics.closedPRs = withoutDrafts(d.metrics.closedPRs)
+		d.metrics.openPRs = withoutDrafts(d.metrics.openPRs)
+	}
+
+	// クローズ済みPRは期間によらず取得しているので、前期にマージされたPRもここから選ぶ
+	closedPRs := d.metrics.closedPRs
+	if s.path != "" {
+		d.metrics.closedPRs = mergedPRsInCommits(closedPRs, d.metrics.commits)
+		closedPRs = mergedPRsInCommits(closedPRs, d.prevCommits)
+	}
+	d.prevMergedPRs = mergedPRsInPeriod(closedPRs, prevPeriod)
+
+	// 生成物・vendor などを巨大ファイル・変更集中・バス係数の対象から外す
+	d.metrics.files = filterIgnoredFiles(d.metrics.files, d.ignoreRules)
+	for i := range d.metrics.commits {
+		d.metrics.commits[i].Files = filterIgnoredPaths(d.metrics.commits[i].Files, d.ignoreRules)
+	}
+
+	// TODO コメントは除外後のファイルから選んで走査する（生成物・vendor の TODO は数えない）
+	if s.todoScanMaxFiles > 0 {
+		start := time.Now()
+		d.todos = s.scanTodos(ctx, repo, d.metrics.files)
+		s.logFetch("todo scan files", start, d.todos.scanned, nil)
+		d.sources.record(sourceTodos, domain.DataSource{Fetched: d.todos.scanned, Failed: d.todos.targets - d.todos.scanned})
+	}
+	return d, nil
+}
+
+// logFetch は1種類のデータ取得の所要時間と件数を Debug レベルで出す。
+func (s *Service) logFetch(what string, start time.Time, items int, err error) {
+	duration := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		s.log().Debug("fetch failed", "what", what, "duration", duration, "error", err)
+		return
+	}
+	s.log().Debug("fetched", "what", what, "items", items, "duration", duration)
+}
+
+// warnUnlessCanceled は補助データの取得失敗を警告として出す。
+// 必須データの失敗で中断された場合の巻き添えのエラーは出さない。
+func (s *Service) warnUnlessCanceled(ctx context.Context, msg string, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	s.log().Warn(msg, "error", err)
+}
@@ -0,0 +1,116 @@
+package analyze
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// authorKey はコミットの作成者を識別するキーを返す。
+// メールアドレス（大文字小文字を区別しない）で識別し、空なら名前を使う。
+func authorKey(c Commit) string {
+	if key := strings.ToLower(c.Email); key != "" {
+		return key
+	}
+	return c.Author
+}
+
+// detectFileOwnership はファイルごとの変更の作成者を集計し、1人が大半を変更したファイル（知識のサイロ）を検出する。
+//
+// 変更ファイルを取得したコミット（直近 maxCommitDetails 件）だけが対象。
+// 作成者が1人しかいない期間はすべてのファイルが該当してしまうため判定しない（全体の偏りは属人化リスクで見る）。
+// リスクはサイロ化したファイルの件数で1つにまとめ、一覧は変更回数の多い順に返す。
+func (s *Service) detectFileOwnership(commits []Commit) ([]domain.Risk, []domain.SiloFile) {
+	type fileStats struct {
+		changes  int
+		byAuthor map[string]int
+	}
+	stats := make(map[string]*fileStats)
+	names := make(map[string]string) // 作成者キー → 表示名
+	for _, c := range commits {
+		key := authorKey(c)
+		if key == "" || len(c.Files) == 0 {
+			continue
+		}
+		if _, ok := names[key]; !ok {
+			names[key] = c.Author
+			if names[key] == "" {
+				names[key] = c.Email
+			}
+		}
+		for _, f := range c.Files {
+			st, ok := stats[f]
+			if !ok {
+				st = &fileStats{byAuthor: make(map[string]int)}
+				stats[f] = st
+			}
+			st.changes++
+			st.byAuthor[key]++
+		}
+	}
+	if len(names) < 2 {
+		return nil, nil
+	}
+
+	var siloFiles []domain.SiloFile
+	for path, st := range stats {
+		if st.changes < fileOwnershipMinChanges {
+			continue
+		}
+		// 最多の作成者（同数ならキーの小さい方にして結果を安定させる）
+		var owner string
+		for key, n := range st.byAuthor {
+			if owner == "" || n > st.byAuthor[owner] || (n == st.byAuthor[owner] && key < owner) {
+				owner = key
+			}
+		}
+		share := float64(st.byAuthor[owner]) / float64(st.changes)
+		if share < fileOwnershipShare {
+			continue
+		}
+		// 他の誰も触っていないファイルは引き継ぎの手がかりがないため重く見る
+		severity := domain.SeverityMedium
+		if len(st.byAuthor) == 1 {
+			severity = domain.SeverityHigh
+		}
+		siloFiles = append(siloFiles, domain.SiloFile{
+			Path:     path,
+			Author:   names[owner],
+			Changes:  st.changes,
+			Share:    int(share * 100),
+			Severity: severity,
+		})
+	}
+	if len(siloFiles) == 0 {
+		return nil, nil
+	}
+	sort.Slice(siloFiles, func(i, j int) bool {
+		if siloFiles[i].Changes != siloFiles[j].Changes {
+			return siloFiles[i].Changes > siloFiles[j].Changes
+		}
+		return siloFiles[i].Path < siloFiles[j].Path
+	})
+
+	severity := domain.SeverityMedium
+	if len(siloFiles) >= fileOwnershipCountCritical {
+		severity = domain.SeverityHigh
+	}
+	risk := domain.Risk{
+		Type:        domain.RiskTypeFileOwnership,
+		Severity:    severity,
+		Target:      s.lang.T("%d件", len(siloFiles)),
+		Description: s.lang.T("1人の作成者が変更の%d%%以上を占めるファイルがあります", int(fileOwnershipShare*100)),
+		Value:       len(siloFiles),
+		Threshold:   int(fileOwnershipShare * 100),
+	}
+	return []domain.Risk{risk}, siloFiles
+}
+
+// topSiloFiles は変更回数の多い順に並んだ一覧の先頭 n 件を返す。
+func topSiloFiles(files []domain.SiloFile, n int) []domain.SiloFile {
+	if len(files) > n {
+		return files[:n]
+	}
+	return files
+}
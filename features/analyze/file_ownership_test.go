@@ -0,0 +1,89 @@
+package analyze
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// commitsBy は author が file を n 回変更したコミットを返す。
+func commitsBy(author, file string, n int) []Commit {
+	commits := make([]Commit, n)
+	for i := range commits {
+		commits[i] = Commit{
+			SHA:    fmt.Sprintf("%s-%s-%d", author, file, i),
+			Author: author,
+			Email:  author + "@example.com",
+			Files:  []string{file},
+		}
+	}
+	return commits
+}
+
+func TestDetectFileOwnership(t *testing.T) {
+	var commits []Commit
+	// alice だけが変更（High）
+	commits = append(commits, commitsBy("alice", "billing/invoice.go", 8)...)
+	// bob が9割（Medium）
+	commits = append(commits, commitsBy("bob", "auth/session.go", 9)...)
+	commits = append(commits, commitsBy("alice", "auth/session.go", 1)...)
+	// 変更が分散（対象外）
+	commits = append(commits, commitsBy("alice", "main.go", 5)...)
+	commits = append(commits, commitsBy("bob", "main.go", 5)...)
+	// 変更回数が少ない（対象外）
+	commits = append(commits, commitsBy("bob", "README.md", fileOwnershipMinChanges-1)...)
+
+	risks, files := (&Service{}).detectFileOwnership(commits)
+
+	wantFiles := []domain.SiloFile{
+		{Path: "auth/session.go", Author: "bob", Changes: 10, Share: 90, Severity: domain.SeverityMedium},
+		{Path: "billing/invoice.go", Author: "alice", Changes: 8, Share: 100, Severity: domain.SeverityHigh},
+	}
+	if !reflect.DeepEqual(files, wantFiles) {
+		t.Errorf("files = %+v, want %+v", files, wantFiles)
+	}
+	if len(risks) != 1 {
+		t.Fatalf("risks = %d, want 1", len(risks))
+	}
+	r := risks[0]
+	if r.Type != domain.RiskTypeFileOwnership || r.Severity != domain.SeverityMedium || r.Value != 2 {
+		t.Errorf("risk = %+v, want file_ownership / medium / 2 files", r)
+	}
+}
+
+func TestDetectFileOwnership_Critical(t *testing.T) {
+	var commits []Commit
+	for i := range fileOwnershipCountCritical {
+		commits = append(commits, commitsBy("alice", fmt.Sprintf("pkg/file%d.go", i), fileOwnershipMinChanges)...)
+	}
+	commits = append(commits, commitsBy("bob", "docs/guide.md", 1)...)
+
+	risks, _ := (&Service{}).detectFileOwnership(commits)
+	if len(risks) != 1 || risks[0].Severity != domain.SeverityHigh {
+		t.Errorf("risks = %+v, want one high risk", risks)
+	}
+}
+
+func TestDetectFileOwnership_SingleAuthor(t *testing.T) {
+	// 作成者が1人だけの期間は全ファイルが該当するため判定しない
+	commits := commitsBy("alice", "main.go", 10)
+	risks, files := (&Service{}).detectFileOwnership(commits)
+	if len(risks) != 0 || len(files) != 0 {
+		t.Errorf("detectFileOwnership() = %v, %v, want none", risks, files)
+	}
+}
+
+func TestDetectFileOwnership_SameAuthorDifferentNames(t *testing.T) {
+	// 名前が違ってもメールアドレスが同じなら同じ作成者として数える
+	commits := commitsBy("alice", "main.go", 5)
+	commits[0].Author = "Alice Smith"
+	commits[1].Email = "ALICE@example.com"
+	commits = append(commits, commitsBy("bob", "other.go", 1)...)
+
+	_, files := (&Service{}).detectFileOwnership(commits)
+	if len(files) != 1 || files[0].Share != 100 {
+		t.Errorf("files = %+v, want main.go owned 100%% by one author", files)
+	}
+}
@@ -12,7 +12,6 @@ package analyze
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"github.com/ryuka-games/lokup/domain"
 )
@@ -46,7 +45,7 @@ func (h *Handler) Handle(ctx context.Context, input Input) (*domain.AnalysisResu
 	}
 
 	// 期間の計算
-	to := time.Now()
+	to := h.service.now()
 	from := to.AddDate(0, 0, -input.Days)
 
 	// サービス呼び出し
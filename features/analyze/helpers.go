@@ -1,20 +1,102 @@
 package analyze
 
 import (
+	"cmp"
 	"context"
-	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/shared/i18n"
+	"github.com/ryuka-games/lokup/shared/parallel"
 )
 
 // PR詳細取得の上限
 const maxPRDetailsCount = 20
 
+// DefaultMaxCommitDetails は変更ファイルを取得するコミット数の上限のデフォルト。
+const DefaultMaxCommitDetails = 100
+
+// DefaultTopFiles は巨大ファイル・変更集中ファイルの一覧に残す件数のデフォルト。
+const DefaultTopFiles = 20
+
+// DefaultStalePRDays は滞留PRとみなすオープン日数のデフォルト。
+const DefaultStalePRDays = 30
+
+// DefaultFailureLabels は障害（変更失敗・復旧対象）とみなす Issue ラベルのデフォルト。
+var DefaultFailureLabels = []string{"bug", "incident", "hotfix"}
+
+// コミット詳細（変更ファイル）取得の同時リクエスト数
+const commitDetailsConcurrency = 5
+
+// DefaultPRDetailsConcurrency はPR詳細（サイズ・レビュー）を同時に取得するPR数のデフォルト。
+const DefaultPRDetailsConcurrency = 5
+
+// percentile は values の p パーセンタイル（0〜100）を返す。
+// 隣り合う値の間は線形補間する（偶数個の中央値は中央2値の平均になる）。
+// 空なら 0 を返す。values は変更しない。
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return sorted[lower]
+	}
+	frac := rank - float64(lower)
+	return sorted[lower] + (sorted[upper]-sorted[lower])*frac
+}
+
+// elapsed は from から to までの経過時間を返す。
+// どちらかがゼロ値（API が値を返さなかった）か、時計のずれで to が from より前なら
+// 信頼できない値として ok=false を返す。呼び出し側はその値を集計から外す。
+func elapsed(from, to time.Time) (d time.Duration, ok bool) {
+	if from.IsZero() || to.IsZero() {
+		return 0, false
+	}
+	d = to.Sub(from)
+	if d < 0 {
+		return 0, false
+	}
+	return d, true
+}
+
+// commitLocalTime はコミット日時を集計に使うタイムゾーンの時刻にして返す。
+// loc が nil ならコミット自身のタイムゾーン（オフセット）のまま扱う。
+//
+// 時間帯別グラフ・曜日×時間帯ヒートマップ・深夜労働率・週末労働率は、
+// 同じコミットを同じ時刻として数えないと食い違うため、時と曜日はすべてこれを通して求める。
+func commitLocalTime(c Commit, loc *time.Location) time.Time {
+	if loc == nil {
+		return c.Date
+	}
+	return c.Date.In(loc)
+}
+
+// commitHour はコミットの時（0〜23）を commitLocalTime のタイムゾーンで返す。
+func commitHour(c Commit, loc *time.Location) int {
+	return commitLocalTime(c, loc).Hour()
+}
+
+// commitWeekday はコミットの曜日を commitLocalTime のタイムゾーンで返す。
+func commitWeekday(c Commit, loc *time.Location) time.Weekday {
+	return commitLocalTime(c, loc).Weekday()
+}
+
 // countLateNightCommits は深夜（22時〜5時）のコミット数を返す。
-func countLateNightCommits(commits []Commit) int {
+// 時刻は loc に変換してから判定する（nil ならコミット自身のタイムゾーン）。
+func countLateNightCommits(commits []Commit, loc *time.Location) int {
 	count := 0
 	for _, c := range commits {
-		hour := c.Date.Hour()
+		hour := commitHour(c, loc)
 		if hour >= lateNightStartHour || hour < lateNightEndHour {
 			count++
 		}
@@ -22,67 +104,216 @@ func countLateNightCommits(commits []Commit) int {
 	return count
 }
 
-// buildPRDetails はマージ済みPRからPR詳細一覧を構築する。
-// レビュー情報もここで取得し、PRDetailに含める。
-func (s *Service) buildPRDetails(ctx context.Context, repo domain.Repository, pullRequests []PullRequest) []domain.PRDetail {
-	var details []domain.PRDetail
+// isWeekend は曜日が土日かを返す。
+func isWeekend(d time.Weekday) bool {
+	return d == time.Saturday || d == time.Sunday
+}
 
-	// 最新の20件のマージ済みPRから詳細を構築（APIコール節約）
+// countWeekendCommits は週末（土日）のコミット数を返す。
+// 曜日は深夜判定と同じく loc に変換してから判定する（nil ならコミット自身のタイムゾーン）。
+func countWeekendCommits(commits []Commit, loc *time.Location) int {
 	count := 0
+	for _, c := range commits {
+		if isWeekend(commitWeekday(c, loc)) {
+			count++
+		}
+	}
+	return count
+}
+
+// authorRetention はコントリビューターの定着の集計結果。
+type authorRetention struct {
+	Active  int // 期間中にコミットした作成者数
+	New     int // 後半にだけコミットした作成者数
+	Churned int // 前半にだけコミットした作成者数
+}
+
+// calcAuthorRetention は期間を前半・後半に分け、作成者の増減を集計する。
+// 作成者は authorKey で識別する。
+func calcAuthorRetention(commits []Commit, period domain.DateRange) authorRetention {
+	mid := period.From.Add(period.To.Sub(period.From) / 2)
+	firstHalf := make(map[string]bool)
+	secondHalf := make(map[string]bool)
+	for _, c := range commits {
+		key := authorKey(c)
+		if key == "" {
+			continue
+		}
+		if c.Date.Before(mid) {
+			firstHalf[key] = true
+		} else {
+			secondHalf[key] = true
+		}
+	}
+
+	var r authorRetention
+	for key := range firstHalf {
+		r.Active++
+		if !secondHalf[key] {
+			r.Churned++
+		}
+	}
+	for key := range secondHalf {
+		if !firstHalf[key] {
+			r.Active++
+			r.New++
+		}
+	}
+	return r
+}
+
+// fillCommitFiles は直近のコミットの変更ファイルを取得して commits に書き込む。
+//
+// コミット一覧APIには変更ファイルが含まれないため1件ずつ取得する。
+// APIコール節約のため先頭（最新）から maxCommitDetails 件に限り、
+// 同時リクエスト数を commitDetailsConcurrency に抑えたワーカープールで取得する。
+// 取得に失敗したコミットは Files が空のまま残る。失敗したコミットの数を返す。
+func (s *Service) fillCommitFiles(ctx context.Context, repo domain.Repository, commits []Commit) int {
+	n := min(len(commits), s.maxCommitDetails())
+	if n == 0 {
+		return 0
+	}
+
+	progress := s.startProgress(repo, PhaseCommitDetails, n)
+	var failed atomic.Int32
+	parallel.ForEach(ctx, commitDetailsConcurrency, n, func(i int) {
+		detail, err := s.repo.GetCommitDetail(ctx, repo, commits[i].SHA)
+		progress.step()
+		if err != nil {
+			failed.Add(1)
+			return
+		}
+		commits[i].Files = detail.Files
+		commits[i].Additions = detail.Additions
+		commits[i].Deletions = detail.Deletions
+	})
+	return int(failed.Load())
+}
+
+// buildPRDetails はマージ済みPRからPR詳細一覧を構築する。
+// レビュー・コメントもここで取得し、PRDetailに含める。
+//
+// APIコール節約のため対象は先頭（最新）から maxPRDetailsCount 件に限り、
+// 同時に処理するPR数を prDetailsConcurrency() に抑えたワーカープールで取得する。
+// 結果は取得の完了順によらずPR番号の降順（新しい順）に並べる。
+// サイズ・レビューの取得に失敗したPRも、その項目を空にして一覧には残す。
+// ctx がキャンセルされたら未着手のPRは取得せず、一覧から除く。
+func (s *Service) buildPRDetails(ctx context.Context, repo domain.Repository, pullRequests []PullRequest) []domain.PRDetail {
+	var targets []PullRequest
 	for _, pr := range pullRequests {
 		if pr.MergedAt == nil {
 			continue
 		}
-		if count >= maxPRDetailsCount {
+		if len(targets) >= maxPRDetailsCount {
 			break
 		}
-		count++
+		targets = append(targets, pr)
+	}
+	if len(targets) == 0 {
+		return nil
+	}
 
-		leadTime := pr.LeadTime()
+	results := make([]*domain.PRDetail, len(targets))
+	progress := s.startProgress(repo, PhasePRDetails, len(targets))
+	parallel.ForEach(ctx, s.prDetailsConcurrency(), len(targets), func(i int) {
+		results[i] = s.buildPRDetail(ctx, repo, targets[i])
+		progress.step()
+	})
 
-		// PR詳細を取得（additions/deletions）
-		size := 0
-		prDetail, detailErr := s.repo.GetPRDetail(ctx, repo, pr.Number)
-		if detailErr == nil {
-			size = prDetail.Additions + prDetail.Deletions
+	details := make([]domain.PRDetail, 0, len(targets))
+	for _, d := range results {
+		if d != nil {
+			details = append(details, *d)
 		}
+	}
+	slices.SortFunc(details, func(a, b domain.PRDetail) int {
+		return cmp.Compare(b.Number, a.Number)
+	})
+	return details
+}
 
-		// レビュー待ち時間を計算
-		var reviewWaitHours float64
-		reviews, err := s.repo.GetPRReviews(ctx, repo, pr.Number)
-		if err == nil && len(reviews) > 0 {
-			firstReview := reviews[0]
-			for _, r := range reviews {
-				if r.SubmittedAt.Before(firstReview.SubmittedAt) {
-					firstReview = r
-				}
+// buildPRDetail は1件のPRの詳細（サイズ・レビュー）を取得してPR詳細を構築する。
+func (s *Service) buildPRDetail(ctx context.Context, repo domain.Repository, pr PullRequest) *domain.PRDetail {
+	// PR詳細を取得（additions/deletions）
+	var additions, deletions int
+	if prDetail, err := s.repo.GetPRDetail(ctx, repo, pr.Number); err == nil {
+		additions = prDetail.Additions
+		deletions = prDetail.Deletions
+	}
+
+	// レビュー待ち時間とレビュー有無を計算
+	var reviewWaitHours float64
+	reviews, err := s.repo.GetPRReviews(ctx, repo, pr.Number)
+	if err == nil {
+		// 送信前（pending）のレビューは submitted_at がないため、最初のレビューの候補にしない
+		var firstReview *Review
+		for i, r := range reviews {
+			if r.SubmittedAt.IsZero() {
+				continue
 			}
-			waitTime := firstReview.SubmittedAt.Sub(pr.CreatedAt).Hours()
-			if waitTime >= 0 {
-				reviewWaitHours = waitTime
+			if firstReview == nil || r.SubmittedAt.Before(firstReview.SubmittedAt) {
+				firstReview = &reviews[i]
 			}
 		}
+		if firstReview != nil {
+			if wait, ok := elapsed(pr.CreatedAt, firstReview.SubmittedAt); ok {
+				reviewWaitHours = wait.Hours()
+			}
+		}
+	}
 
-		additions := 0
-		deletions := 0
-		if detailErr == nil {
-			additions = prDetail.Additions
-			deletions = prDetail.Deletions
+	// 最初の人による反応までの時間を計算（レビューとコメントの片方しか取れなくても、取れた方で計算する）
+	var comments []Comment
+	if c, err := s.repo.GetPRComments(ctx, repo, pr.Number); err == nil {
+		comments = c
+	}
+	var firstResponseHours float64
+	if first, ok := firstHumanResponse(pr.Author, reviews, comments); ok {
+		if wait, ok := elapsed(pr.CreatedAt, first); ok {
+			firstResponseHours = wait.Hours()
 		}
+	}
 
-		details = append(details, domain.PRDetail{
-			Number:          pr.Number,
-			Title:           pr.Title,
-			Author:          pr.Author,
-			LeadTimeDays:    leadTime,
-			Size:            size,
-			Additions:       additions,
-			Deletions:       deletions,
-			ReviewWaitHours: reviewWaitHours,
-		})
+	return &domain.PRDetail{
+		Number:             pr.Number,
+		Title:              pr.Title,
+		Author:             pr.Author,
+		LeadTimeDays:       pr.LeadTime(),
+		Size:               additions + deletions,
+		Additions:          additions,
+		Deletions:          deletions,
+		ReviewWaitHours:    reviewWaitHours,
+		FirstResponseHours: firstResponseHours,
+		ReviewsFetched:     err == nil,
+		Reviewed:           err == nil && hasDecisiveReview(reviews),
+		SelfMerged:         err == nil && !hasApprovalFromOthers(reviews, pr.Author),
 	}
+}
 
-	return details
+// firstHumanResponse はPR作成者以外の人による最初のレビューまたはコメントの日時を返す。
+// 作成者自身と Bot（CI の結果やプレビュー URL の自動投稿）の投稿は反応とみなさない。
+func firstHumanResponse(author string, reviews []Review, comments []Comment) (time.Time, bool) {
+	var first time.Time
+	consider := func(login string, bot bool, at time.Time) {
+		if at.IsZero() || bot || isBotLogin(login) || strings.EqualFold(login, author) {
+			return
+		}
+		if first.IsZero() || at.Before(first) {
+			first = at
+		}
+	}
+	for _, r := range reviews {
+		consider(r.Author, false, r.SubmittedAt)
+	}
+	for _, c := range comments {
+		consider(c.Author, c.Bot, c.CreatedAt)
+	}
+	return first, !first.IsZero()
+}
+
+// isBotLogin は GitHub App（"dependabot[bot]" など）のログイン名かを返す。
+func isBotLogin(login string) bool {
+	return strings.HasSuffix(login, "[bot]")
 }
 
 // calcAvgPRSize はPR詳細一覧から平均PRサイズを計算する。
@@ -116,6 +347,82 @@ func calcAvgReviewWait(details []domain.PRDetail) float64 {
 	return total / float64(count)
 }
 
+// calcAvgFirstResponse はPR詳細一覧から最初の人による反応までの平均時間を計算する。
+// 反応のなかったPR（0）は平均に含めない。
+func calcAvgFirstResponse(details []domain.PRDetail) float64 {
+	var total float64
+	var count int
+	for _, d := range details {
+		if d.FirstResponseHours > 0 {
+			total += d.FirstResponseHours
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return total / float64(count)
+}
+
+// hasDecisiveReview は承認または変更要求のレビューが含まれるかを返す。
+// コメントだけのレビューは「レビューを受けた」とはみなさない。
+func hasDecisiveReview(reviews []Review) bool {
+	for _, r := range reviews {
+		if r.State == "APPROVED" || r.State == "CHANGES_REQUESTED" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasApprovalFromOthers は作成者以外からの承認が含まれるかを返す。
+func hasApprovalFromOthers(reviews []Review, author string) bool {
+	for _, r := range reviews {
+		if r.State == "APPROVED" && !strings.EqualFold(r.Author, author) {
+			return true
+		}
+	}
+	return false
+}
+
+// calcReviewCoverage はPR詳細一覧からレビューカバレッジ（%）と集計対象のPR数を計算する。
+// レビュー一覧を取得できなかったPRは有無が分からないため分母から除く。
+func calcReviewCoverage(details []domain.PRDetail) (float64, int) {
+	var reviewed, count int
+	for _, d := range details {
+		if !d.ReviewsFetched {
+			continue
+		}
+		count++
+		if d.Reviewed {
+			reviewed++
+		}
+	}
+	if count == 0 {
+		return 0, 0
+	}
+	return float64(reviewed) / float64(count) * 100, count
+}
+
+// calcSelfMerge はPR詳細一覧からセルフマージの件数と割合（%）を計算する。
+// 分母はレビューカバレッジと同じく、レビュー一覧を取得できたPR。
+func calcSelfMerge(details []domain.PRDetail) (int, float64) {
+	var selfMerged, count int
+	for _, d := range details {
+		if !d.ReviewsFetched {
+			continue
+		}
+		count++
+		if d.SelfMerged {
+			selfMerged++
+		}
+	}
+	if count == 0 {
+		return 0, 0
+	}
+	return selfMerged, float64(selfMerged) / float64(count) * 100
+}
+
 // buildContributorDetails はコントリビューター詳細一覧を構築する。
 func (s *Service) buildContributorDetails(contributors []Contributor) []domain.ContributorDetail {
 	totalCommits := 0
@@ -140,14 +447,25 @@ func (s *Service) buildContributorDetails(contributors []Contributor) []domain.C
 }
 
 // aggregateHourlyCommits はコミットを時間帯別に集計する。
+// 深夜判定と同じタイムゾーンで集計し、グラフと深夜労働率の整合を取る。
 func (s *Service) aggregateHourlyCommits(commits []Commit) [24]int {
 	var hourly [24]int
 	for _, c := range commits {
-		hourly[c.Date.Hour()]++
+		hourly[commitHour(c, s.location)]++
 	}
 	return hourly
 }
 
+// aggregateWeekdayHourCommits はコミットを曜日（time.Weekday の順、日曜が0）×時間帯別に集計する。
+// 時間帯別・週末の集計と同じタイムゾーンで曜日と時を判定する。
+func (s *Service) aggregateWeekdayHourCommits(commits []Commit) [7][24]int {
+	var matrix [7][24]int
+	for _, c := range commits {
+		matrix[commitWeekday(c, s.location)][commitHour(c, s.location)]++
+	}
+	return matrix
+}
+
 // aggregateDailyCommits はコミットを日別に集計する。
 func (s *Service) aggregateDailyCommits(commits []Commit, period domain.DateRange) []domain.DailyCommit {
 	// 日付ごとのコミット数をカウント
@@ -173,15 +491,123 @@ func (s *Service) aggregateDailyCommits(commits []Commit, period domain.DateRang
 }
 
 // formatAge は月数を「X年Yヶ月」形式にフォーマットする。
-func formatAge(months int) string {
+func formatAge(months int, lang i18n.Lang) string {
 	years := months / 12
 	remainingMonths := months % 12
 
 	if years == 0 {
-		return fmt.Sprintf("%dヶ月", remainingMonths)
+		return lang.T("%dヶ月", remainingMonths)
 	}
 	if remainingMonths == 0 {
-		return fmt.Sprintf("%d年", years)
+		return lang.T("%d年", years)
+	}
+	return lang.T("%d年%dヶ月", years, remainingMonths)
+}
+
+// normalizePath は対象パスの表記ゆれ（"./"・前後の "/"・空白）を取り除く。
+func normalizePath(path string) string {
+	path = strings.TrimSpace(path)
+	path = strings.TrimPrefix(path, "./")
+	return strings.Trim(path, "/")
+}
+
+// inPath はファイルが対象パス配下にあるかを返す（path が空なら常に true）。
+// "services/bill" が "services/billing/..." に一致しないよう、ディレクトリ単位で判定する。
+func inPath(file, path string) bool {
+	if path == "" {
+		return true
+	}
+	return file == path || strings.HasPrefix(file, path+"/")
+}
+
+// filterPaths はファイルパスの一覧を対象パス配下のものに絞る。
+func filterPaths(files []string, path string) []string {
+	if path == "" {
+		return files
+	}
+	var filtered []string
+	for _, f := range files {
+		if inPath(f, path) {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
+
+// filterFiles はリポジトリのファイル一覧を対象パス配下のものに絞る。
+func filterFiles(files []File, path string) []File {
+	if path == "" {
+		return files
+	}
+	var filtered []File
+	for _, f := range files {
+		if inPath(f.Path, path) {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
+
+// mergedPRsInCommits はマージ済みPRのうち、マージ結果またはブランチ先頭のコミットが
+// commits に含まれるものを返す。
+// PRの変更ファイルは取得しないため、対象パスのコミット一覧に現れるかで判定する。
+// 未マージのPRはどのパスを変更したか判定できないため含めない。
+func mergedPRsInCommits(prs []PullRequest, commits []Commit) []PullRequest {
+	shas := make(map[string]bool, len(commits))
+	for _, c := range commits {
+		shas[c.SHA] = true
+	}
+	var matched []PullRequest
+	for _, pr := range prs {
+		if pr.MergedAt == nil {
+			continue
+		}
+		if (pr.MergeSHA != "" && shas[pr.MergeSHA]) || (pr.HeadSHA != "" && shas[pr.HeadSHA]) {
+			matched = append(matched, pr)
+		}
+	}
+	return matched
+}
+
+// withoutDrafts はドラフトを除いたPRを返す。
+func withoutDrafts(prs []PullRequest) []PullRequest {
+	var ready []PullRequest
+	for _, pr := range prs {
+		if !pr.Draft {
+			ready = append(ready, pr)
+		}
+	}
+	return ready
+}
+
+// mergedPRsInPeriod は期間内にマージされたPRを返す。
+func mergedPRsInPeriod(prs []PullRequest, period domain.DateRange) []PullRequest {
+	var merged []PullRequest
+	for _, pr := range prs {
+		if pr.MergedAt != nil && !pr.MergedAt.Before(period.From) && !pr.MergedAt.After(period.To) {
+			merged = append(merged, pr)
+		}
+	}
+	return merged
+}
+
+// perWeek は期間中の件数を1週間あたりに換算する（days が 0 なら 1日として扱う）。
+func perWeek(count, days int) float64 {
+	if days == 0 {
+		days = 1
+	}
+	return float64(count) / (float64(days) / 7)
+}
+
+// hasActivity は期間内にコミットかマージ済みPRがあるかを返す。
+func hasActivity(commits []Commit, prs []PullRequest, period domain.DateRange) bool {
+	if len(commits) > 0 {
+		return true
+	}
+	for _, pr := range prs {
+		if pr.MergedAt != nil && !pr.MergedAt.Before(period.From) && !pr.MergedAt.After(period.To) {
+			return true
+		}
 	}
-	return fmt.Sprintf("%d年%dヶ月", years, remainingMonths)
+	return false
 }
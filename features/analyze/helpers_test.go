@@ -1,10 +1,13 @@
 package analyze
 
 import (
+	"context"
+	"math"
 	"testing"
 	"time"
 
 	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/shared/i18n"
 )
 
 func TestCountLateNightCommits(t *testing.T) {
@@ -70,7 +73,7 @@ func TestCountLateNightCommits(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := countLateNightCommits(tt.commits)
+			got := countLateNightCommits(tt.commits, nil)
 			if got != tt.want {
 				t.Errorf("countLateNightCommits() = %d, want %d", got, tt.want)
 			}
@@ -78,6 +81,185 @@ func TestCountLateNightCommits(t *testing.T) {
 	}
 }
 
+func TestCountLateNightCommits_Location(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	pst := time.FixedZone("PST", -8*60*60)
+
+	tests := []struct {
+		name    string
+		commits []Commit
+		loc     *time.Location
+		want    int
+	}{
+		{
+			"nil keeps commit offset",
+			[]Commit{
+				{Date: time.Date(2025, 1, 1, 23, 0, 0, 0, pst)}, // PST 23時
+			},
+			nil,
+			1,
+		},
+		{
+			"daytime in UTC is late night in JST",
+			[]Commit{
+				{Date: time.Date(2025, 1, 1, 14, 0, 0, 0, time.UTC)}, // JST 23時
+			},
+			jst,
+			1,
+		},
+		{
+			"late night in PST is daytime in JST",
+			[]Commit{
+				{Date: time.Date(2025, 1, 1, 23, 0, 0, 0, pst)}, // JST 翌16時
+			},
+			jst,
+			0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := countLateNightCommits(tt.commits, tt.loc)
+			if got != tt.want {
+				t.Errorf("countLateNightCommits() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountWeekendCommits(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	pst := time.FixedZone("PST", -8*60*60)
+
+	tests := []struct {
+		name    string
+		commits []Commit
+		loc     *time.Location
+		want    int
+	}{
+		{"empty", nil, nil, 0},
+		{
+			"saturday and sunday",
+			[]Commit{
+				{Date: time.Date(2025, 1, 4, 10, 0, 0, 0, time.UTC)}, // 土
+				{Date: time.Date(2025, 1, 5, 10, 0, 0, 0, time.UTC)}, // 日
+				{Date: time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)}, // 月
+				{Date: time.Date(2025, 1, 3, 23, 0, 0, 0, time.UTC)}, // 金
+			},
+			nil,
+			2,
+		},
+		{
+			"nil keeps commit offset",
+			[]Commit{
+				{Date: time.Date(2025, 1, 3, 20, 0, 0, 0, pst)}, // PST 金曜20時（UTC では土曜）
+			},
+			nil,
+			0,
+		},
+		{
+			"friday night in UTC is saturday in JST",
+			[]Commit{
+				{Date: time.Date(2025, 1, 3, 16, 0, 0, 0, time.UTC)}, // JST 土曜1時
+			},
+			jst,
+			1,
+		},
+		{
+			"monday morning in JST is sunday in PST",
+			[]Commit{
+				{Date: time.Date(2025, 1, 6, 9, 0, 0, 0, jst)}, // PST 日曜16時
+			},
+			pst,
+			1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := countWeekendCommits(tt.commits, tt.loc)
+			if got != tt.want {
+				t.Errorf("countWeekendCommits() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalcAuthorRetention(t *testing.T) {
+	period := domain.NewDateRange(
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+	)
+	early := time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2025, 1, 25, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		commits []Commit
+		want    authorRetention
+	}{
+		{"empty", nil, authorRetention{}},
+		{
+			"stayed, joined and left",
+			[]Commit{
+				{Email: "alice@example.com", Date: early},
+				{Email: "Alice@Example.com", Date: late}, // 大文字小文字は区別しない
+				{Email: "bob@example.com", Date: early},
+				{Email: "carol@example.com", Date: late},
+				{Email: "carol@example.com", Date: late},
+			},
+			authorRetention{Active: 3, New: 1, Churned: 1},
+		},
+		{
+			"falls back to name without email",
+			[]Commit{
+				{Author: "dave", Date: early},
+				{Author: "dave", Date: late},
+				{Date: late}, // 識別できない作成者は数えない
+			},
+			authorRetention{Active: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calcAuthorRetention(tt.commits, period); got != tt.want {
+				t.Errorf("calcAuthorRetention() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		p      float64
+		want   float64
+	}{
+		{"empty", nil, 50, 0},
+		{"single", []float64{4}, 90, 4},
+		{"odd median", []float64{5, 1, 3}, 50, 3},
+		{"even median", []float64{4, 1, 3, 2}, 50, 2.5},
+		{"p75 interpolated", []float64{1, 2, 3, 4}, 75, 3.25},
+		{"p90 with outlier", []float64{1, 1, 1, 1, 1, 1, 1, 1, 1, 100}, 90, 10.9},
+		{"p0 is min", []float64{3, 1, 2}, 0, 1},
+		{"p100 is max", []float64{3, 1, 2}, 100, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := percentile(tt.values, tt.p)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("percentile(%v, %v) = %v, want %v", tt.values, tt.p, got, tt.want)
+			}
+		})
+	}
+
+	// 入力を並べ替えない
+	values := []float64{3, 1, 2}
+	percentile(values, 50)
+	if values[0] != 3 || values[1] != 1 || values[2] != 2 {
+		t.Errorf("input modified: %v", values)
+	}
+}
+
 func TestCalcAvgPRSize(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -137,6 +319,107 @@ func TestCalcAvgReviewWait(t *testing.T) {
 	}
 }
 
+func TestHasDecisiveReview(t *testing.T) {
+	tests := []struct {
+		name    string
+		reviews []Review
+		want    bool
+	}{
+		{"no reviews", nil, false},
+		{"comment only", []Review{{State: "COMMENTED"}}, false},
+		{"approved", []Review{{State: "COMMENTED"}, {State: "APPROVED"}}, true},
+		{"changes requested", []Review{{State: "CHANGES_REQUESTED"}}, true},
+		{"dismissed", []Review{{State: "DISMISSED"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasDecisiveReview(tt.reviews); got != tt.want {
+				t.Errorf("hasDecisiveReview() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasApprovalFromOthers(t *testing.T) {
+	tests := []struct {
+		name    string
+		reviews []Review
+		want    bool
+	}{
+		{"no reviews", nil, false},
+		{"approved by other", []Review{{Author: "bob", State: "APPROVED"}}, true},
+		{"approved by author only", []Review{{Author: "alice", State: "APPROVED"}}, false},
+		{"author login case differs", []Review{{Author: "Alice", State: "APPROVED"}}, false},
+		{"other only requested changes", []Review{{Author: "bob", State: "CHANGES_REQUESTED"}}, false},
+		{"other commented, author approved", []Review{{Author: "bob", State: "COMMENTED"}, {Author: "alice", State: "APPROVED"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasApprovalFromOthers(tt.reviews, "alice"); got != tt.want {
+				t.Errorf("hasApprovalFromOthers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalcSelfMerge(t *testing.T) {
+	details := []domain.PRDetail{
+		{ReviewsFetched: true, SelfMerged: true},
+		{ReviewsFetched: true},
+		{ReviewsFetched: true},
+		{ReviewsFetched: true, SelfMerged: true},
+		{ReviewsFetched: false, SelfMerged: false}, // 取得失敗は分母から除く
+	}
+	count, rate := calcSelfMerge(details)
+	if count != 2 || rate != 50 {
+		t.Errorf("calcSelfMerge() = (%d, %v), want (2, 50)", count, rate)
+	}
+
+	if count, rate := calcSelfMerge(nil); count != 0 || rate != 0 {
+		t.Errorf("calcSelfMerge(nil) = (%d, %v), want (0, 0)", count, rate)
+	}
+}
+
+func TestCalcReviewCoverage(t *testing.T) {
+	tests := []struct {
+		name      string
+		details   []domain.PRDetail
+		wantRate  float64
+		wantCount int
+	}{
+		{"empty", nil, 0, 0},
+		{
+			"all reviewed",
+			[]domain.PRDetail{{ReviewsFetched: true, Reviewed: true}, {ReviewsFetched: true, Reviewed: true}},
+			100, 2,
+		},
+		{
+			"partial",
+			[]domain.PRDetail{
+				{ReviewsFetched: true, Reviewed: true},
+				{ReviewsFetched: true},
+				{ReviewsFetched: true},
+				{ReviewsFetched: true, Reviewed: true},
+			},
+			50, 4,
+		},
+		{
+			"skip unfetched",
+			[]domain.PRDetail{{ReviewsFetched: true, Reviewed: true}, {ReviewsFetched: false}},
+			100, 1,
+		},
+		{"none fetched", []domain.PRDetail{{}, {}}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, count := calcReviewCoverage(tt.details)
+			if rate != tt.wantRate || count != tt.wantCount {
+				t.Errorf("calcReviewCoverage() = (%v, %d), want (%v, %d)", rate, count, tt.wantRate, tt.wantCount)
+			}
+		})
+	}
+}
+
 func TestFormatAge(t *testing.T) {
 	tests := []struct {
 		months int
@@ -151,7 +434,7 @@ func TestFormatAge(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.want, func(t *testing.T) {
-			got := formatAge(tt.months)
+			got := formatAge(tt.months, i18n.Default)
 			if got != tt.want {
 				t.Errorf("formatAge(%d) = %q, want %q", tt.months, got, tt.want)
 			}
@@ -212,6 +495,101 @@ func TestAggregateHourlyCommits(t *testing.T) {
 	}
 }
 
+func TestAggregateWeekdayHourCommits(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	s := NewService(nil, WithLocation(jst))
+	commits := []Commit{
+		{Date: time.Date(2025, 1, 6, 1, 0, 0, 0, time.UTC)},  // 月曜 JST 10時
+		{Date: time.Date(2025, 1, 13, 1, 0, 0, 0, time.UTC)}, // 月曜 JST 10時
+		{Date: time.Date(2025, 1, 4, 14, 0, 0, 0, time.UTC)}, // 土曜 UTC 14時 → 土曜 JST 23時
+		{Date: time.Date(2025, 1, 4, 16, 0, 0, 0, time.UTC)}, // 土曜 UTC 16時 → 日曜 JST 1時
+	}
+
+	matrix := s.aggregateWeekdayHourCommits(commits)
+
+	if matrix[time.Monday][10] != 2 {
+		t.Errorf("matrix[Mon][10] = %d, want 2", matrix[time.Monday][10])
+	}
+	if matrix[time.Saturday][23] != 1 {
+		t.Errorf("matrix[Sat][23] = %d, want 1", matrix[time.Saturday][23])
+	}
+	if matrix[time.Sunday][1] != 1 {
+		t.Errorf("matrix[Sun][1] = %d, want 1 (day boundary crossed in JST)", matrix[time.Sunday][1])
+	}
+	total := 0
+	for _, row := range matrix {
+		for _, n := range row {
+			total += n
+		}
+	}
+	if total != len(commits) {
+		t.Errorf("total = %d, want %d", total, len(commits))
+	}
+}
+
+func TestAggregateHourlyCommits_Location(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	s := NewService(nil, WithLocation(jst))
+	commits := []Commit{
+		{Date: time.Date(2025, 1, 1, 14, 0, 0, 0, time.UTC)}, // JST 23時
+		{Date: time.Date(2025, 1, 1, 1, 0, 0, 0, time.UTC)},  // JST 10時
+	}
+
+	hourly := s.aggregateHourlyCommits(commits)
+
+	if hourly[23] != 1 {
+		t.Errorf("hourly[23] = %d, want 1", hourly[23])
+	}
+	if hourly[10] != 1 {
+		t.Errorf("hourly[10] = %d, want 1", hourly[10])
+	}
+	if hourly[14] != 0 || hourly[1] != 0 {
+		t.Errorf("hours not converted: hourly[14] = %d, hourly[1] = %d", hourly[14], hourly[1])
+	}
+}
+
+// 時間帯別グラフ・ヒートマップ・深夜労働率・週末労働率が、同じコミットを同じ時刻として数えること
+func TestCommitLocalTime_Agreement(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	// UTC では金曜15時30分、JST では土曜0時30分（深夜かつ週末）
+	commit := Commit{Date: time.Date(2025, 1, 3, 15, 30, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name        string
+		loc         *time.Location
+		wantHour    int
+		wantWeekday time.Weekday
+		wantLate    int
+		wantWeekend int
+	}{
+		{"commit offset", nil, 15, time.Friday, 0, 0},
+		{"JST", jst, 0, time.Saturday, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(nil, WithLocation(tt.loc))
+			commits := []Commit{commit}
+
+			local := commitLocalTime(commit, tt.loc)
+			if local.Hour() != tt.wantHour || local.Weekday() != tt.wantWeekday {
+				t.Fatalf("commitLocalTime() = %v, want %s %d時", local, tt.wantWeekday, tt.wantHour)
+			}
+			if hourly := s.aggregateHourlyCommits(commits); hourly[tt.wantHour] != 1 {
+				t.Errorf("hourly[%d] = %d, want 1 (%v)", tt.wantHour, hourly[tt.wantHour], hourly)
+			}
+			if matrix := s.aggregateWeekdayHourCommits(commits); matrix[tt.wantWeekday][tt.wantHour] != 1 {
+				t.Errorf("matrix[%s][%d] = %d, want 1", tt.wantWeekday, tt.wantHour, matrix[tt.wantWeekday][tt.wantHour])
+			}
+			if got := countLateNightCommits(commits, s.location); got != tt.wantLate {
+				t.Errorf("countLateNightCommits() = %d, want %d", got, tt.wantLate)
+			}
+			if got := countWeekendCommits(commits, s.location); got != tt.wantWeekend {
+				t.Errorf("countWeekendCommits() = %d, want %d", got, tt.wantWeekend)
+			}
+		})
+	}
+}
+
 func TestAggregateDailyCommits(t *testing.T) {
 	s := &Service{}
 	period := domain.NewDateRange(
@@ -239,3 +617,205 @@ func TestAggregateDailyCommits(t *testing.T) {
 		t.Errorf("day 3 count = %d, want 1", daily[2].Count)
 	}
 }
+
+func TestInPath(t *testing.T) {
+	tests := []struct {
+		file, path string
+		want       bool
+	}{
+		{"services/billing/main.go", "", true},
+		{"services/billing/main.go", "services/billing", true},
+		{"services/billing", "services/billing", true},
+		{"services/billing-v2/main.go", "services/billing", false},
+		{"README.md", "services/billing", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file+"@"+tt.path, func(t *testing.T) {
+			if got := inPath(tt.file, tt.path); got != tt.want {
+				t.Errorf("inPath(%q, %q) = %v, want %v", tt.file, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizePath(t *testing.T) {
+	for in, want := range map[string]string{
+		"":                   "",
+		"services/billing":   "services/billing",
+		"./services/billing": "services/billing",
+		"/services/billing/": "services/billing",
+		" services/billing ": "services/billing",
+	} {
+		if got := normalizePath(in); got != want {
+			t.Errorf("normalizePath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestElapsed(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     time.Duration
+		wantOK   bool
+	}{
+		{"forward", base, base.Add(36 * time.Hour), 36 * time.Hour, true},
+		{"same instant", base, base, 0, true},
+		{"different zones", base, base.Add(2 * time.Hour).In(time.FixedZone("JST", 9*60*60)), 2 * time.Hour, true},
+		{"backwards (clock skew)", base, base.Add(-time.Minute), 0, false},
+		{"zero from", time.Time{}, base, 0, false},
+		{"zero to", base, time.Time{}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := elapsed(tt.from, tt.to)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("elapsed() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// reviewRepo は指定したレビューとコメントを返すモック。
+type reviewRepo struct {
+	*mockRepository
+	reviews     []Review
+	comments    []Comment
+	commentsErr error
+}
+
+func (r *reviewRepo) GetPRReviews(context.Context, domain.Repository, int) ([]Review, error) {
+	return r.reviews, nil
+}
+
+func (r *reviewRepo) GetPRComments(context.Context, domain.Repository, int) ([]Comment, error) {
+	return r.comments, r.commentsErr
+}
+
+func TestBuildPRDetail_ReviewWait(t *testing.T) {
+	created := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	merged := created.Add(72 * time.Hour)
+	future := created.AddDate(1, 0, 0)
+	tests := []struct {
+		name    string
+		reviews []Review
+		want    float64
+	}{
+		{
+			"first submitted review",
+			[]Review{
+				{State: "APPROVED", SubmittedAt: created.Add(30 * time.Hour)},
+				{State: "COMMENTED", SubmittedAt: created.Add(6 * time.Hour)},
+			},
+			6,
+		},
+		{
+			"pending review without submitted_at is ignored",
+			[]Review{
+				{State: "PENDING"},
+				{State: "APPROVED", SubmittedAt: created.Add(12 * time.Hour)},
+			},
+			12,
+		},
+		{
+			"review before creation (clock skew) is not counted",
+			[]Review{{State: "APPROVED", SubmittedAt: created.Add(-time.Hour)}},
+			0,
+		},
+		{
+			"future-dated review is kept as is",
+			[]Review{{State: "APPROVED", SubmittedAt: future}},
+			future.Sub(created).Hours(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(&reviewRepo{mockRepository: &mockRepository{}, reviews: tt.reviews})
+			pr := PullRequest{Number: 1, Author: "alice", CreatedAt: created, MergedAt: &merged}
+			d := s.buildPRDetail(context.Background(), domain.NewRepository("o", "r"), pr)
+			if d.ReviewWaitHours != tt.want {
+				t.Errorf("ReviewWaitHours = %v, want %v", d.ReviewWaitHours, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildPRDetail_FirstResponse(t *testing.T) {
+	created := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	merged := created.Add(72 * time.Hour)
+	tests := []struct {
+		name        string
+		reviews     []Review
+		comments    []Comment
+		commentsErr error
+		want        float64
+		wantReview  float64
+	}{
+		{
+			name:       "comment before the first review",
+			reviews:    []Review{{Author: "bob", State: "APPROVED", SubmittedAt: created.Add(30 * time.Hour)}},
+			comments:   []Comment{{Author: "carol", CreatedAt: created.Add(2 * time.Hour)}},
+			want:       2,
+			wantReview: 30,
+		},
+		{
+			name:       "review before the first comment",
+			reviews:    []Review{{Author: "bob", State: "COMMENTED", SubmittedAt: created.Add(5 * time.Hour)}},
+			comments:   []Comment{{Author: "carol", CreatedAt: created.Add(8 * time.Hour)}},
+			want:       5,
+			wantReview: 5,
+		},
+		{
+			name: "author and bot comments are not responses",
+			comments: []Comment{
+				{Author: "alice", CreatedAt: created.Add(time.Hour)},
+				{Author: "github-actions[bot]", CreatedAt: created.Add(2 * time.Hour)},
+				{Author: "ci-app", Bot: true, CreatedAt: created.Add(3 * time.Hour)},
+				{Author: "carol", CreatedAt: created.Add(10 * time.Hour)},
+			},
+			want: 10,
+		},
+		{
+			name:       "self review is not a response",
+			reviews:    []Review{{Author: "alice", State: "COMMENTED", SubmittedAt: created.Add(time.Hour)}},
+			want:       0,
+			wantReview: 1,
+		},
+		{
+			name:        "comments unavailable falls back to reviews",
+			reviews:     []Review{{Author: "bob", State: "APPROVED", SubmittedAt: created.Add(12 * time.Hour)}},
+			commentsErr: errMockFetch,
+			want:        12,
+			wantReview:  12,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &reviewRepo{mockRepository: &mockRepository{}, reviews: tt.reviews, comments: tt.comments, commentsErr: tt.commentsErr}
+			pr := PullRequest{Number: 1, Author: "alice", CreatedAt: created, MergedAt: &merged}
+			d := NewService(repo).buildPRDetail(context.Background(), domain.NewRepository("o", "r"), pr)
+			if d.FirstResponseHours != tt.want {
+				t.Errorf("FirstResponseHours = %v, want %v", d.FirstResponseHours, tt.want)
+			}
+			// レビュー待ち時間はコメントに影響されない
+			if d.ReviewWaitHours != tt.wantReview {
+				t.Errorf("ReviewWaitHours = %v, want %v", d.ReviewWaitHours, tt.wantReview)
+			}
+		})
+	}
+}
+
+func TestCalcAvgFirstResponse(t *testing.T) {
+	details := []domain.PRDetail{
+		{FirstResponseHours: 2},
+		{FirstResponseHours: 0}, // 反応なしは平均に含めない
+		{FirstResponseHours: 6},
+	}
+	if got := calcAvgFirstResponse(details); got != 4 {
+		t.Errorf("calcAvgFirstResponse() = %v, want 4", got)
+	}
+	if got := calcAvgFirstResponse(nil); got != 0 {
+		t.Errorf("calcAvgFirstResponse(nil) = %v, want 0", got)
+	}
+}
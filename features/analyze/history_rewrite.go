@@ -0,0 +1,49 @@
+package analyze
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// historyRewriteCriticalCount は force push を High とする期間内の回数。
+// 1回でも共有ブランチの履歴が変われば手元のクローンとずれるため Medium で検出し、
+// 繰り返されていれば運用として常態化しているとみなす。
+const historyRewriteCriticalCount = 3
+
+// detectHistoryRewrite はデフォルトブランチへの force push をまとめて1件のリスクとして返す。
+// コミット一覧の API には force push が現れないため、リポジトリのアクティビティから判定する。
+func (s *Service) detectHistoryRewrite(pushes []ForcePush) []domain.Risk {
+	if len(pushes) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var actors []string
+	for _, p := range pushes {
+		if p.Actor != "" && !seen[p.Actor] {
+			seen[p.Actor] = true
+			actors = append(actors, p.Actor)
+		}
+	}
+	sort.Strings(actors)
+
+	severity := domain.SeverityMedium
+	if len(pushes) >= historyRewriteCriticalCount {
+		severity = domain.SeverityHigh
+	}
+	branch := pushes[0].Branch
+	description := s.lang.T("デフォルトブランチ（%s）への force push が%d回ありました", branch, len(pushes))
+	if len(actors) > 0 {
+		description += s.lang.T("（実行者: %s）", strings.Join(actors, ", "))
+	}
+
+	return []domain.Risk{{
+		Type:        domain.RiskTypeHistoryRewrite,
+		Severity:    severity,
+		Target:      branch,
+		Description: description,
+		Value:       len(pushes),
+	}}
+}
@@ -0,0 +1,81 @@
+package analyze
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/shared/i18n"
+)
+
+func TestDetectHistoryRewrite(t *testing.T) {
+	at := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	push := func(actor string) ForcePush {
+		return ForcePush{Branch: "main", Actor: actor, Timestamp: at}
+	}
+	tests := []struct {
+		name         string
+		pushes       []ForcePush
+		wantRisk     bool
+		wantSeverity domain.Severity
+		wantDesc     string
+	}{
+		{"none", nil, false, 0, ""},
+		{"once", []ForcePush{push("alice")}, true, domain.SeverityMedium, "デフォルトブランチ（main）への force push が1回ありました（実行者: alice）"},
+		{"repeated", []ForcePush{push("bob"), push("alice"), push("bob")}, true, domain.SeverityHigh, "デフォルトブランチ（main）への force push が3回ありました（実行者: alice, bob）"},
+		{"unknown actor", []ForcePush{push("")}, true, domain.SeverityMedium, "デフォルトブランチ（main）への force push が1回ありました"},
+	}
+	s := &Service{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			risks := s.detectHistoryRewrite(tt.pushes)
+			if (len(risks) == 1) != tt.wantRisk {
+				t.Fatalf("risks = %+v, want risk %v", risks, tt.wantRisk)
+			}
+			if !tt.wantRisk {
+				return
+			}
+			r := risks[0]
+			if r.Type != domain.RiskTypeHistoryRewrite || r.Severity != tt.wantSeverity || r.Target != "main" || r.Value != len(tt.pushes) {
+				t.Errorf("risk = %+v, want %v on main with value %d", r, tt.wantSeverity, len(tt.pushes))
+			}
+			if r.Description != tt.wantDesc {
+				t.Errorf("Description = %q, want %q", r.Description, tt.wantDesc)
+			}
+		})
+	}
+
+	risks := NewService(nil, WithLang(i18n.English)).detectHistoryRewrite([]ForcePush{push("alice")})
+	if got, want := risks[0].Description, "Force pushes to the default branch (main): 1 (by alice)"; got != want {
+		t.Errorf("English Description = %q, want %q", got, want)
+	}
+}
+
+func TestAnalyze_HistoryRewrite(t *testing.T) {
+	repo := &mockRepository{forcePushes: []ForcePush{
+		{Branch: "main", Actor: "alice", Timestamp: time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)},
+	}}
+	result, err := NewService(repo).Analyze(context.Background(), ServiceInput{
+		Repository: domain.NewRepository("owner", "repo"),
+		Period: domain.NewDateRange(
+			time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		),
+	})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	found := false
+	for _, r := range result.Risks {
+		if r.Type == domain.RiskTypeHistoryRewrite {
+			found = true
+			if r.Type.Category() != domain.CategoryQuality {
+				t.Errorf("category = %s, want quality", r.Type.Category())
+			}
+		}
+	}
+	if !found {
+		t.Error("expected RiskTypeHistoryRewrite")
+	}
+}
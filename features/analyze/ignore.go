@@ -0,0 +1,155 @@
+package analyze
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// ignoreFilePath は除外パターンを書くファイルの場所（リポジトリのルート）。
+const ignoreFilePath = ".lokupignore"
+
+// ignoreRule は .lokupignore の1行。
+type ignoreRule struct {
+	pattern string
+	negate  bool // "!" で始まる行（除外を取り消す）
+}
+
+// fetchIgnoreRules は .lokupignore を取得してルール一覧を返す。
+// WithIgnoreFile で指定済みならそれを使い、リポジトリのファイルは見ない。
+// どちらもなければ nil を返す（エラーではない）。
+func (s *Service) fetchIgnoreRules(ctx context.Context, repo domain.Repository) []ignoreRule {
+	if s.ignoreRules != nil {
+		return s.ignoreRules
+	}
+	content, err := s.repo.GetFileContent(ctx, repo, ignoreFilePath)
+	if err != nil {
+		return nil
+	}
+	return parseIgnoreFile(string(content))
+}
+
+// parseIgnoreFile は gitignore 形式の内容をルール一覧に変換する。
+// "#" で始まる行と空行は無視する（gitignore と同じく行の途中の "#" はコメントではない）。
+func parseIgnoreFile(content string) []ignoreRule {
+	rules := []ignoreRule{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule := ignoreRule{pattern: line}
+		if strings.HasPrefix(line, "!") {
+			rule = ignoreRule{pattern: line[1:], negate: true}
+		}
+		if rule.pattern != "" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// isIgnored は filePath が除外対象かを返す。
+// gitignore と同様に、最後にマッチしたルールが優先される。
+func isIgnored(rules []ignoreRule, filePath string) bool {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if matchIgnorePattern(rules[i].pattern, filePath) {
+			return !rules[i].negate
+		}
+	}
+	return false
+}
+
+// matchIgnorePattern は gitignore 形式のパターンがファイルパスにマッチするかを返す。
+//
+// 対応する書き方:
+//   - "*.min.js"       任意の階層のファイル名（"/" を含まないパターン）
+//   - "vendor/"        任意の階層のディレクトリ以下すべて
+//   - "/dist"          ルート直下（先頭の "/" か、途中に "/" を含むパターンはルート基準）
+//   - "**/*.min.js"    "**" は0個以上のディレクトリ（先頭・途中・末尾のどこでも）
+//   - "src/**/gen/*"   "*" "?" "[...]" は1階層の中だけでマッチ
+//
+// ディレクトリにマッチしたパターンは、その配下のファイルすべてにマッチする。
+func matchIgnorePattern(pattern, filePath string) bool {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	anchored := strings.HasPrefix(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if strings.Contains(pattern, "/") {
+		anchored = true
+	}
+	if pattern == "" {
+		return false
+	}
+
+	patternSegs := strings.Split(pattern, "/")
+	if !anchored {
+		patternSegs = append([]string{"**"}, patternSegs...)
+	}
+	segs := strings.Split(filePath, "/")
+
+	// ファイル自身か、親ディレクトリのいずれかにマッチすればよい
+	for end := len(segs); end >= 1; end-- {
+		// ディレクトリ指定はファイル名そのものにはマッチしない
+		if dirOnly && end == len(segs) {
+			continue
+		}
+		if matchSegments(patternSegs, segs[:end]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments はパスの各階層をパターンの各階層と照合する。"**" は0個以上の階層にマッチする。
+func matchSegments(pattern, segs []string) bool {
+	if len(pattern) == 0 {
+		return len(segs) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segs); i++ {
+			if matchSegments(pattern[1:], segs[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segs) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segs[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], segs[1:])
+}
+
+// filterIgnoredPaths はファイルパスの一覧から除外対象を取り除く。
+func filterIgnoredPaths(files []string, rules []ignoreRule) []string {
+	if len(rules) == 0 {
+		return files
+	}
+	var kept []string
+	for _, f := range files {
+		if !isIgnored(rules, f) {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+// filterIgnoredFiles はリポジトリのファイル一覧から除外対象を取り除く。
+func filterIgnoredFiles(files []File, rules []ignoreRule) []File {
+	if len(rules) == 0 {
+		return files
+	}
+	var kept []File
+	for _, f := range files {
+		if !isIgnored(rules, f.Path) {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,87 @@
+package analyze
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestParseIgnoreFile(t *testing.T) {
+	content := `# 生成物
+dist/
+
+  **/*.min.js  
+!vendor/keep.js
+`
+	got := parseIgnoreFile(content)
+	want := []ignoreRule{
+		{pattern: "dist/"},
+		{pattern: "**/*.min.js"},
+		{pattern: "vendor/keep.js", negate: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseIgnoreFile() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMatchIgnorePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"**/*.min.js", "app.min.js", true},
+		{"**/*.min.js", "web/static/app.min.js", true},
+		{"**/*.min.js", "web/app.js", false},
+		{"*.min.js", "web/static/app.min.js", true},
+		{"vendor/", "vendor/github.com/x/y.go", true},
+		{"vendor/", "third_party/vendor/lib.js", true},
+		{"vendor/", "vendor", false},
+		{"vendor", "vendor/lib.js", true},
+		{"/dist", "dist/bundle.js", true},
+		{"/dist", "web/dist/bundle.js", false},
+		{"web/dist/", "web/dist/bundle.js", true},
+		{"web/dist/", "src/web/dist/bundle.js", false},
+		{"src/**/generated/*.go", "src/generated/a.go", true},
+		{"src/**/generated/*.go", "src/api/v1/generated/a.go", true},
+		{"src/**/generated/*.go", "src/api/v1/generated/sub/a.go", false},
+		{"docs/**", "docs/guide/intro.md", true},
+		{"**/testdata", "pkg/parser/testdata/big.json", true},
+		{"*.pb.go", "api/service.pb.go", true},
+		{"assets/*.png", "assets/logo.png", true},
+		{"assets/*.png", "assets/icons/logo.png", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+"|"+tt.path, func(t *testing.T) {
+			if got := matchIgnorePattern(tt.pattern, tt.path); got != tt.want {
+				t.Errorf("matchIgnorePattern(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsIgnored_Negation(t *testing.T) {
+	rules := parseIgnoreFile("vendor/\n!vendor/important.js\n")
+	if !isIgnored(rules, "vendor/lib.js") {
+		t.Error("vendor/lib.js should be ignored")
+	}
+	if isIgnored(rules, "vendor/important.js") {
+		t.Error("vendor/important.js should be re-included by negation")
+	}
+	if isIgnored(rules, "src/main.go") {
+		t.Error("src/main.go should not be ignored")
+	}
+}
+
+func TestFilterIgnoredPaths(t *testing.T) {
+	rules := parseIgnoreFile("**/*.min.js\nvendor/\n")
+	got := filterIgnoredPaths([]string{"src/main.go", "web/app.min.js", "vendor/x/y.go"}, rules)
+	if !slices.Equal(got, []string{"src/main.go"}) {
+		t.Errorf("filterIgnoredPaths() = %v, want [src/main.go]", got)
+	}
+	// ルールがなければそのまま
+	in := []string{"a", "b"}
+	if got := filterIgnoredPaths(in, nil); !slices.Equal(got, in) {
+		t.Errorf("filterIgnoredPaths(nil rules) = %v", got)
+	}
+}
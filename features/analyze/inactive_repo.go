@@ -0,0 +1,71 @@
+package analyze
+
+import (
+	"context"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// lastActivity は期間の開始より前も含めた最後の活動（コミット・マージ・リリース）の日時を返す。
+// 期間内のコミットは取得済みの一覧にないため、期間の終わり以前で最新のコミットを1件だけ取得する。
+// 活動が1つもない（空のリポジトリ）ならゼロ値を返す。
+func (s *Service) lastActivity(ctx context.Context, repo domain.Repository, period domain.DateRange, prs []PullRequest, releases []Release) time.Time {
+	var last time.Time
+	update := func(t time.Time) {
+		if !t.After(period.To) && t.After(last) {
+			last = t
+		}
+	}
+
+	commit, err := s.repo.GetLastCommit(ctx, repo, period.To, s.path, s.branch)
+	if err != nil {
+		s.warnUnlessCanceled(ctx, "failed to get last commit", err)
+	} else if commit != nil {
+		update(commit.Date)
+	}
+
+	for _, pr := range prs {
+		if pr.MergedAt != nil {
+			update(*pr.MergedAt)
+		}
+	}
+	for _, r := range releases {
+		update(r.PublishedAt)
+	}
+	return last
+}
+
+// detectInactiveRepo は、以前は活動していたのに期間内にコミット・マージ・リリースが1件もない
+// 休眠リポジトリを検出する。アーカイブ（整理）の候補として、最後の活動日を添えて返す。
+//
+// 一度も活動のない空のリポジトリ（lastActivity がゼロ値）は対象にしない（データ不足として扱う）。
+func (s *Service) detectInactiveRepo(lastActivity time.Time, period domain.DateRange) []domain.Risk {
+	if lastActivity.IsZero() {
+		return nil
+	}
+	days := int(period.To.Sub(lastActivity).Hours() / 24)
+	severity := domain.SeverityMedium
+	if days >= inactiveRepoCriticalDays {
+		severity = domain.SeverityHigh
+	}
+	date := lastActivity.Format("2006-01-02")
+	return []domain.Risk{{
+		Type:        domain.RiskTypeInactiveRepo,
+		Severity:    severity,
+		Target:      date,
+		Description: s.lang.T("期間内にコミット・マージされたPR・リリースがありません（最終活動: %s、%d日前）", date, days),
+		Value:       days,
+		Threshold:   period.Days(),
+	}}
+}
+
+// releasedInPeriod は期間内に公開されたリリースがあるかを返す。
+func releasedInPeriod(releases []Release, period domain.DateRange) bool {
+	for _, r := range releases {
+		if !r.PublishedAt.Before(period.From) && !r.PublishedAt.After(period.To) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package analyze
+
+import (
+	"context"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// licenseFilePaths はライセンスファイルを探す場所（リポジトリのルート、優先順）。
+var licenseFilePaths = []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "COPYING"}
+
+// fetchLicenseFile はリポジトリのルートにあるライセンスファイルのパスを返す。
+// どれも存在しなければ空文字を返す（エラーではない）。
+func (s *Service) fetchLicenseFile(ctx context.Context, repo domain.Repository) string {
+	for _, p := range licenseFilePaths {
+		if _, err := s.repo.GetFileContent(ctx, repo, p); err == nil {
+			return p
+		}
+	}
+	return ""
+}
+
+// detectMissingLicense はライセンスファイルがなければリスクとして返す。
+// OSS として利用・配布する際の権利関係が不明確になるため、コンプライアンス監査の観点で検出する。
+func (s *Service) detectMissingLicense(licenseFile string) []domain.Risk {
+	if licenseFile != "" {
+		return nil
+	}
+	return []domain.Risk{{
+		Type:        domain.RiskTypeMissingLicense,
+		Severity:    domain.SeverityMedium,
+		Target:      "LICENSE",
+		Description: s.lang.T("ライセンスファイル（LICENSE / COPYING）が見つかりません"),
+	}}
+}
@@ -0,0 +1,54 @@
+package analyze
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+func TestAnalyze_License(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		wantFile string
+		wantRisk bool
+	}{
+		{"no license", nil, "", true},
+		{"LICENSE", map[string]string{"LICENSE": "MIT License"}, "LICENSE", false},
+		{"COPYING", map[string]string{"COPYING": "GNU GPL"}, "COPYING", false},
+		{"LICENSE.md preferred over COPYING", map[string]string{"COPYING": "GNU GPL", "LICENSE.md": "# MIT"}, "LICENSE.md", false},
+		{"lowercase is not recognized", map[string]string{"license": "MIT"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRepository{fileContents: tt.files}
+			result, err := NewService(repo).Analyze(context.Background(), ServiceInput{
+				Repository: domain.NewRepository("owner", "repo"),
+				Period: domain.NewDateRange(
+					time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+					time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+				),
+			})
+			if err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+			if result.LicenseFile != tt.wantFile {
+				t.Errorf("LicenseFile = %q, want %q", result.LicenseFile, tt.wantFile)
+			}
+			var gotRisk *domain.Risk
+			for i, r := range result.Risks {
+				if r.Type == domain.RiskTypeMissingLicense {
+					gotRisk = &result.Risks[i]
+				}
+			}
+			if (gotRisk != nil) != tt.wantRisk {
+				t.Fatalf("missing license risk = %v, want %v", gotRisk != nil, tt.wantRisk)
+			}
+			if gotRisk != nil && gotRisk.Type.Category() != domain.CategoryTechDebt {
+				t.Errorf("category = %s, want tech_debt", gotRisk.Type.Category())
+			}
+		})
+	}
+}
@@ -16,7 +16,12 @@ type metricsInput struct {
 	releases          []Release
 	period            domain.DateRange
 	avgReviewWaitTime float64
+	avgFirstResponse  float64
 	avgPRSize         int
+	reviewCoverage    float64
+	reviewCoveragePRs int
+	selfMergeCount    int
+	selfMergeRate     float64
 }
 
 // calculateMetrics は各種メトリクスを計算する。
@@ -26,18 +31,25 @@ func (s *Service) calculateMetrics(in metricsInput) domain.Metrics {
 		days = 1
 	}
 
-	// 深夜コミット率を計算
+	// 深夜・週末コミット率を計算
 	lateNightRate := 0.0
+	weekendRate := 0.0
 	if len(in.commits) > 0 {
-		lateNightRate = float64(countLateNightCommits(in.commits)) / float64(len(in.commits)) * 100
+		lateNightRate = float64(countLateNightCommits(in.commits, s.location)) / float64(len(in.commits)) * 100
+		weekendRate = float64(countWeekendCommits(in.commits, s.location)) / float64(len(in.commits)) * 100
 	}
 
 	// PRリードタイム（作成からマージまでの平均日数）を計算
 	avgLeadTime := s.calculateAvgLeadTime(in.closedPRs)
+	// 平均は少数の巨大PRに引っ張られるため、パーセンタイルも出す
+	ltp := calculateLeadTimePercentiles(in.closedPRs)
 
 	// PR内訳を計算
 	prb := s.calculatePRBreakdown(in.closedPRs)
 
+	// PRスループット（クローズ済みPRは期間によらず取得しているため、期間内のマージだけ数える）
+	mergedPRs := len(mergedPRsInPeriod(in.closedPRs, in.period))
+
 	// Issue統計を計算
 	is := s.calculateIssueStats(in.allIssues, in.period)
 
@@ -46,35 +58,70 @@ func (s *Service) calculateMetrics(in metricsInput) domain.Metrics {
 	cfr, cfrRating := s.calculateChangeFailureRate(in.allIssues, in.releases, in.commits, in.period)
 	mttr, mttrRating := s.calculateMTTR(in.allIssues, in.period)
 
+	// コントリビューターの定着
+	ar := calcAuthorRetention(in.commits, in.period)
+
 	// コードチャーン
 	revertCount := countRevertCommits(in.commits)
 	revertRate := 0.0
 	if len(in.commits) > 0 {
 		revertRate = float64(revertCount) / float64(len(in.commits)) * 100
 	}
+	ttr := measureTimeToRevert(in.commits)
+
+	// コミットメッセージの品質
+	lowQualityCount := countLowQualityCommits(in.commits)
+	lowQualityRate := 0.0
+	if len(in.commits) > 0 {
+		lowQualityRate = float64(lowQualityCount) / float64(len(in.commits)) * 100
+	}
+
+	// コミットの粒度
+	cs := measureCommitSize(in.commits)
+
+	// 共同作成
+	coAuthoredCount := countCoAuthoredCommits(in.commits)
+	coAuthorshipRate := 0.0
+	if len(in.commits) > 0 {
+		coAuthorshipRate = float64(coAuthoredCount) / float64(len(in.commits)) * 100
+	}
 
 	return domain.Metrics{
 		// 開発速度
 		TotalCommits:        len(in.commits),
 		FeatureAdditionRate: float64(len(in.commits)) / float64(days),
+		MergedPRCount:       mergedPRs,
+		PRThroughputPerWeek: perWeek(mergedPRs, days),
 		AvgLeadTime:         avgLeadTime,
+		LeadTimeP50:         ltp.P50,
+		LeadTimeP75:         ltp.P75,
+		LeadTimeP90:         ltp.P90,
 		AvgReviewWaitTime:   in.avgReviewWaitTime,
+		AvgFirstResponse:    in.avgFirstResponse,
 		OpenPRCount:         len(in.openPRs),
 		OpenIssueCount:      len(in.openIssues),
 
 		// コード品質
-		BugFixRatio:    prb.BugFixRatio,
-		ReworkRate:     revertRate,
-		AvgPRSize:      in.avgPRSize,
-		IssueCloseRate: is.CloseRate,
-		IssuesCreated:  is.Created,
-		IssuesClosed:   is.Closed,
+		BugFixRatio:        prb.BugFixRatio,
+		ReworkRate:         revertRate,
+		AvgPRSize:          in.avgPRSize,
+		IssueCloseRate:     is.CloseRate,
+		IssuesCreated:      is.Created,
+		IssuesClosed:       is.Closed,
+		ReviewCoverageRate: in.reviewCoverage,
+		ReviewCoveragePRs:  in.reviewCoveragePRs,
+		SelfMergeCount:     in.selfMergeCount,
+		SelfMergeRate:      in.selfMergeRate,
 
 		// PR内訳
 		FeaturePRCount: prb.Feature,
 		BugFixPRCount:  prb.BugFix,
 		OtherPRCount:   prb.Other,
 
+		// 放棄PR
+		AbandonedPRCount: prb.Abandoned,
+		AbandonmentRate:  prb.AbandonmentRate,
+
 		// DORA メトリクス
 		DeployFrequency:   deployFreq,
 		DeployFreqRating:  deployRating,
@@ -92,10 +139,29 @@ func (s *Service) calculateMetrics(in metricsInput) domain.Metrics {
 		RevertCommitCount: revertCount,
 		RevertRate:        revertRate,
 
+		FastRevertCount:      ttr.Fast,
+		AvgTimeToRevertHours: ttr.AvgHours,
+
+		// コミットメッセージの品質
+		LowQualityCommitCount: lowQualityCount,
+		LowQualityCommitRate:  lowQualityRate,
+
+		// コミットの粒度
+		SizedCommitCount:  cs.Commits,
+		AvgFilesPerCommit: cs.AvgFiles,
+
+		// 共同作成
+		CoAuthoredCommitCount: coAuthoredCount,
+		CoAuthorshipRate:      coAuthorshipRate,
+
 		// チーム健全性
 		TotalFiles:          len(in.files),
 		TotalContributors:   len(in.contributors),
 		LateNightCommitRate: lateNightRate,
+		WeekendCommitRate:   weekendRate,
+		ActiveAuthors:       ar.Active,
+		NewAuthors:          ar.New,
+		ChurnedAuthors:      ar.Churned,
 	}
 }
 
@@ -108,20 +174,28 @@ type prBreakdown struct {
 	BugFixRatio   float64
 	FeatureRatio  float64
 	RefactorRatio float64
+
+	// マージされずにクローズされたPR（内訳の分母には含めない）
+	Abandoned       int
+	AbandonmentRate float64
 }
 
-// calculatePRBreakdown はマージ済みPRの内訳を計算する。
+// calculatePRBreakdown はマージ済みPRの内訳と、マージされずにクローズされたPRの件数を計算する。
+// pullRequests はクローズ済みPR（マージ済みを含む）。
 func (s *Service) calculatePRBreakdown(pullRequests []PullRequest) prBreakdown {
 	var b prBreakdown
 	for _, pr := range pullRequests {
-		if pr.MergedAt != nil {
-			if pr.IsFeature() {
+		if pr.MergedAt == nil {
+			b.Abandoned++
+		} else {
+			switch s.prKind(pr) {
+			case prKindFeature:
 				b.Feature++
-			} else if pr.IsBugFix() {
+			case prKindBugFix:
 				b.BugFix++
-			} else if pr.IsRefactor() {
+			case prKindRefactor:
 				b.Refactor++
-			} else {
+			default:
 				b.Other++
 			}
 		}
@@ -133,6 +207,9 @@ func (s *Service) calculatePRBreakdown(pullRequests []PullRequest) prBreakdown {
 		b.FeatureRatio = float64(b.Feature) / float64(total) * 100
 		b.RefactorRatio = float64(b.Refactor) / float64(total) * 100
 	}
+	if len(pullRequests) > 0 {
+		b.AbandonmentRate = float64(b.Abandoned) / float64(len(pullRequests)) * 100
+	}
 	return b
 }
 
@@ -156,6 +233,28 @@ func (s *Service) calculateAvgLeadTime(pullRequests []PullRequest) float64 {
 	return totalLeadTime / float64(mergedCount)
 }
 
+// leadTimePercentiles はPRリードタイムのパーセンタイル（日）。
+type leadTimePercentiles struct {
+	P50 float64
+	P75 float64
+	P90 float64
+}
+
+// calculateLeadTimePercentiles はマージ済みPRのリードタイムのパーセンタイルを計算する。
+func calculateLeadTimePercentiles(pullRequests []PullRequest) leadTimePercentiles {
+	var leadTimes []float64
+	for _, pr := range pullRequests {
+		if lt := pr.LeadTime(); lt >= 0 { // マージ済みのみ
+			leadTimes = append(leadTimes, lt)
+		}
+	}
+	return leadTimePercentiles{
+		P50: percentile(leadTimes, 50),
+		P75: percentile(leadTimes, 75),
+		P90: percentile(leadTimes, 90),
+	}
+}
+
 // issueStats はIssue統計の結果。
 type issueStats struct {
 	Created   int
@@ -1,6 +1,7 @@
 package analyze
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -45,6 +46,14 @@ func TestCalculatePRBreakdown(t *testing.T) {
 	if b.RefactorRatio != 20.0 {
 		t.Errorf("RefactorRatio = %v, want 20.0", b.RefactorRatio)
 	}
+
+	// 未マージの1件は内訳に含めず、放棄PRとして数える（6件中1件）
+	if b.Abandoned != 1 {
+		t.Errorf("Abandoned = %d, want 1", b.Abandoned)
+	}
+	if want := 100.0 / 6; math.Abs(b.AbandonmentRate-want) > 1e-9 {
+		t.Errorf("AbandonmentRate = %v, want %v", b.AbandonmentRate, want)
+	}
 }
 
 func TestCalculatePRBreakdown_empty(t *testing.T) {
@@ -56,6 +65,9 @@ func TestCalculatePRBreakdown_empty(t *testing.T) {
 	if b.FeatureRatio != 0 {
 		t.Errorf("FeatureRatio = %v, want 0", b.FeatureRatio)
 	}
+	if b.Abandoned != 0 || b.AbandonmentRate != 0 {
+		t.Errorf("Abandoned = %d (%v%%), want 0", b.Abandoned, b.AbandonmentRate)
+	}
 }
 
 func TestCalculateAvgLeadTime(t *testing.T) {
@@ -93,6 +105,26 @@ func TestCalculateAvgLeadTime(t *testing.T) {
 	})
 }
 
+func TestCalculateLeadTimePercentiles(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	merged := func(days int) PullRequest {
+		m := base.AddDate(0, 0, days)
+		return PullRequest{CreatedAt: base, MergedAt: &m}
+	}
+
+	// 1, 2, 3, 4, 30日 + 未マージ → 平均 8日でも中央値は 3日
+	prs := []PullRequest{merged(1), merged(30), merged(2), merged(4), merged(3), {CreatedAt: base}}
+	got := calculateLeadTimePercentiles(prs)
+	want := leadTimePercentiles{P50: 3, P75: 4, P90: 19.6}
+	if math.Abs(got.P50-want.P50) > 1e-9 || math.Abs(got.P75-want.P75) > 1e-9 || math.Abs(got.P90-want.P90) > 1e-9 {
+		t.Errorf("calculateLeadTimePercentiles() = %+v, want %+v", got, want)
+	}
+
+	if got := calculateLeadTimePercentiles(nil); got != (leadTimePercentiles{}) {
+		t.Errorf("empty = %+v, want zero", got)
+	}
+}
+
 func TestCalculateIssueStats(t *testing.T) {
 	s := &Service{}
 	period := domain.NewDateRange(
@@ -134,3 +166,33 @@ func TestCalculateIssueStats_empty(t *testing.T) {
 		t.Error("expected all zeros")
 	}
 }
+
+func TestCalculateMetrics_PRThroughput(t *testing.T) {
+	period := domain.NewDateRange(
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 29, 0, 0, 0, 0, time.UTC), // 28日 = 4週
+	)
+	merged := func(d int) *time.Time {
+		t := time.Date(2025, 1, d, 12, 0, 0, 0, time.UTC)
+		return &t
+	}
+	before := time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)
+	closedPRs := []PullRequest{
+		{Number: 1, MergedAt: merged(3)},
+		{Number: 2, MergedAt: merged(10)},
+		{Number: 3, MergedAt: merged(10)},
+		{Number: 4, MergedAt: merged(20)},
+		{Number: 5, MergedAt: merged(27)},
+		{Number: 6, MergedAt: merged(28)},
+		{Number: 7},                    // 未マージ
+		{Number: 8, MergedAt: &before}, // 期間より前
+	}
+
+	m := (&Service{}).calculateMetrics(metricsInput{closedPRs: closedPRs, period: period})
+	if m.MergedPRCount != 6 {
+		t.Errorf("MergedPRCount = %d, want 6", m.MergedPRCount)
+	}
+	if m.PRThroughputPerWeek != 1.5 {
+		t.Errorf("PRThroughputPerWeek = %v, want 1.5", m.PRThroughputPerWeek)
+	}
+}
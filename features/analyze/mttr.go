@@ -0,0 +1,91 @@
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/shared/parallel"
+)
+
+// MTTRSource は MTTR（平均復旧時間）で復旧作業の開始とみなす時点。
+type MTTRSource string
+
+const (
+	MTTRSourceCreated MTTRSource = "created" // Issue の作成（デフォルト）
+	MTTRSourceEvents  MTTRSource = "events"  // 障害ラベルの付与・担当者のアサインのうち最初のイベント
+)
+
+// ParseMTTRSource は文字列を MTTR の開始時点に変換する。空ならデフォルト（created）。
+func ParseMTTRSource(s string) (MTTRSource, error) {
+	switch src := MTTRSource(strings.ToLower(s)); src {
+	case "":
+		return MTTRSourceCreated, nil
+	case MTTRSourceCreated, MTTRSourceEvents:
+		return src, nil
+	default:
+		return "", fmt.Errorf("unsupported MTTR source: %q (use created or events)", s)
+	}
+}
+
+// maxIssueEventsCount はイベントを取得する障害Issueの数の上限（新しい順）。
+// Issue ごとに1回のAPIコールが必要なため、障害の多いリポジトリでもコール数を抑える。
+const maxIssueEventsCount = 100
+
+// issueEventsConcurrency は Issue のイベント取得の同時リクエスト数。
+const issueEventsConcurrency = 5
+
+// fillRecoveryStarts は MTTR の対象になる障害Issueのイベントを取得し、復旧作業の開始日時を埋める。
+//
+// 対象は期間内に作成されてクローズ済みの障害Issueのうち、新しい順に maxIssueEventsCount 件。
+// イベントを取得できなかった Issue と、該当するイベントがない Issue は RecoveryStartedAt を
+// nil のまま残し、MTTR は作成からクローズまでで計算する。
+// 上限で絞る前の対象の数、イベントの取得を試みた Issue の数、そのうち取得に失敗した数を返す。
+func (s *Service) fillRecoveryStarts(ctx context.Context, repo domain.Repository, issues []Issue, period domain.DateRange) (total, attempted, failed int) {
+	var targets []int
+	for i, issue := range issues {
+		inPeriod := !issue.CreatedAt.Before(period.From) && !issue.CreatedAt.After(period.To)
+		if issue.ClosedAt != nil && inPeriod && s.isFailureIssue(issue) {
+			targets = append(targets, i)
+		}
+	}
+	sort.SliceStable(targets, func(a, b int) bool {
+		return issues[targets[a]].CreatedAt.After(issues[targets[b]].CreatedAt)
+	})
+	total = len(targets)
+	targets = targets[:min(len(targets), maxIssueEventsCount)]
+	if len(targets) == 0 {
+		return 0, 0, 0
+	}
+
+	var failedCount atomic.Int32
+	parallel.ForEach(ctx, issueEventsConcurrency, len(targets), func(k int) {
+		i := targets[k]
+		events, err := s.repo.GetIssueEvents(ctx, repo, issues[i].Number)
+		if err != nil {
+			s.log().Debug("issue events unavailable", "issue", issues[i].Number, "error", err)
+			failedCount.Add(1)
+			return
+		}
+		issues[i].RecoveryStartedAt = s.recoveryStart(events)
+	})
+	return total, len(targets), int(failedCount.Load())
+}
+
+// recoveryStart は Issue のイベントから復旧作業の開始日時を返す（該当するイベントがなければ nil）。
+// 障害ラベルの付与か担当者のアサインのうち、最も早いものを開始とみなす。
+func (s *Service) recoveryStart(events []IssueEvent) *time.Time {
+	var start *time.Time
+	for _, e := range events {
+		relevant := e.Event == "assigned" || (e.Event == "labeled" && s.isFailureLabel(e.Label))
+		if relevant && (start == nil || e.CreatedAt.Before(*start)) {
+			t := e.CreatedAt
+			start = &t
+		}
+	}
+	return start
+}
@@ -0,0 +1,141 @@
+package analyze
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+func TestParseMTTRSource(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    MTTRSource
+		wantErr bool
+	}{
+		{"", MTTRSourceCreated, false},
+		{"created", MTTRSourceCreated, false},
+		{"Events", MTTRSourceEvents, false},
+		{"timeline", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseMTTRSource(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMTTRSource(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMTTRSource(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecoveryStart(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2025, 1, 10, h, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		name   string
+		labels []string // WithFailureLabels（nil ならデフォルト）
+		events []IssueEvent
+		want   time.Time // ゼロ値なら nil
+	}{
+		{
+			name: "failure label",
+			events: []IssueEvent{
+				{Event: "labeled", Label: "enhancement", CreatedAt: at(1)},
+				{Event: "labeled", Label: "Bug", CreatedAt: at(3)},
+				{Event: "closed", CreatedAt: at(9)},
+			},
+			want: at(3),
+		},
+		{
+			name: "assigned before labeled",
+			events: []IssueEvent{
+				{Event: "assigned", CreatedAt: at(2)},
+				{Event: "labeled", Label: "bug", CreatedAt: at(4)},
+			},
+			want: at(2),
+		},
+		{
+			name:   "configured failure label",
+			labels: []string{"sev1"},
+			events: []IssueEvent{
+				{Event: "labeled", Label: "bug", CreatedAt: at(1)},
+				{Event: "labeled", Label: "sev1", CreatedAt: at(5)},
+			},
+			want: at(5),
+		},
+		{
+			name: "no relevant event",
+			events: []IssueEvent{
+				{Event: "mentioned", CreatedAt: at(1)},
+				{Event: "unlabeled", Label: "bug", CreatedAt: at(2)},
+				{Event: "closed", CreatedAt: at(3)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(nil, WithFailureLabels(tt.labels))
+			got := s.recoveryStart(tt.events)
+			if (got == nil) != tt.want.IsZero() || (got != nil && !got.Equal(tt.want)) {
+				t.Errorf("recoveryStart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyze_MTTRSource(t *testing.T) {
+	period := domain.NewDateRange(
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+	)
+	at := func(d, h int) time.Time { return time.Date(2025, 1, d, h, 0, 0, 0, time.UTC) }
+	closed1, closed2 := at(4, 12), at(11, 0)
+	repo := &mockRepository{
+		commits: []Commit{{SHA: "a", Author: "alice", Date: at(5, 12), Message: "fix: x"}},
+		issues: []Issue{
+			// 作成から1日トリアージを待ち、ラベル付けから36時間で復旧（作成からは60時間）
+			{Number: 1, Labels: []string{"bug"}, CreatedAt: at(2, 0), ClosedAt: &closed1},
+			// イベントを取得できない Issue は作成からの24時間で数える
+			{Number: 2, Labels: []string{"bug"}, CreatedAt: at(10, 0), ClosedAt: &closed2},
+		},
+		issueEvents: map[int][]IssueEvent{
+			1: {
+				{Event: "labeled", Label: "bug", CreatedAt: at(3, 0)},
+				{Event: "assigned", CreatedAt: at(3, 6)},
+				{Event: "closed", CreatedAt: at(4, 12)},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		wantMTTR   float64
+		wantEvents bool // GetIssueEvents を呼ぶか
+	}{
+		{"default created", nil, (60 + 24) / 2.0, false},
+		{"events", []Option{WithMTTRSource(MTTRSourceEvents)}, (36 + 24) / 2.0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo.calls = nil
+			result, err := NewService(repo, tt.opts...).Analyze(context.Background(), ServiceInput{
+				Repository: domain.NewRepository("owner", "repo"),
+				Period:     period,
+			})
+			if err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+			if result.Metrics.MTTR != tt.wantMTTR {
+				t.Errorf("MTTR = %v, want %v", result.Metrics.MTTR, tt.wantMTTR)
+			}
+			if got := slices.Contains(repo.calls, "GetIssueEvents"); got != tt.wantEvents {
+				t.Errorf("GetIssueEvents called = %v, want %v", got, tt.wantEvents)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package analyze
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// DefaultNonSourceExtensions は巨大ファイルリスクの対象外にするファイル名の末尾（拡張子など）のデフォルト。
+// 分割しても保守性が上がらないバイナリ・ロックファイル・生成物を並べる。
+var DefaultNonSourceExtensions = []string{
+	// 画像・メディア
+	".png", ".jpg", ".jpeg", ".gif", ".bmp", ".ico", ".webp", ".svg", ".psd",
+	".mp3", ".mp4", ".mov", ".wav", ".webm",
+	// フォント
+	".woff", ".woff2", ".ttf", ".otf", ".eot",
+	// アーカイブ・実行形式・ドキュメント
+	".zip", ".tar", ".gz", ".tgz", ".7z", ".jar", ".war", ".class",
+	".so", ".dylib", ".dll", ".exe", ".bin", ".wasm", ".pdf",
+	// ロックファイル（yarn.lock / Cargo.lock / package-lock.json / pnpm-lock.yaml / go.sum など）
+	".lock", "-lock.json", "-lock.yaml", "go.sum",
+	// minify 済み・ソースマップ・コード生成の出力
+	".min.js", ".min.css", ".map", ".pb.go", ".snap",
+}
+
+// vendoredDirs はサードパーティのコードをそのまま取り込むディレクトリ名（どの階層でも）。
+var vendoredDirs = []string{"vendor", "node_modules", "third_party", "bower_components"}
+
+// isNonSourceFile はファイルがソースコード以外（バイナリ・生成物・ベンダリングしたコード）かを返す。
+// ツリー API はファイルの中身を返さないため、ファイル名の末尾（WithNonSourceExtensions、
+// 未設定ならデフォルト）とディレクトリ名で判定する。大文字小文字は区別しない。
+func (s *Service) isNonSourceFile(filePath string) bool {
+	exts := s.nonSourceExtensions
+	if exts == nil {
+		exts = DefaultNonSourceExtensions
+	}
+	lower := strings.ToLower(filePath)
+	name := path.Base(lower)
+	for _, ext := range exts {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	dirs := strings.Split(path.Dir(lower), "/")
+	return slices.ContainsFunc(vendoredDirs, func(d string) bool { return slices.Contains(dirs, d) })
+}
+
+// largeFileSeverity はファイルサイズから巨大ファイルの重大度を返す（閾値未満なら false）。
+func largeFileSeverity(size int) (domain.Severity, bool) {
+	switch {
+	case size >= largeFileCriticalBytes:
+		return domain.SeverityHigh, true
+	case size >= largeFileWarningBytes:
+		return domain.SeverityMedium, true
+	default:
+		return 0, false
+	}
+}
+
+// findLargeNonSourceFiles は巨大ファイルリスクの対象外にした、閾値以上のバイナリ・生成物を返す。
+// リスクにはしないが、リポジトリの肥大化の原因としてレポートに別枠で出す。
+func (s *Service) findLargeNonSourceFiles(files []File) []domain.LargeFile {
+	var large []domain.LargeFile
+	for _, f := range files {
+		if !s.isNonSourceFile(f.Path) {
+			continue
+		}
+		if severity, ok := largeFileSeverity(f.Size); ok {
+			large = append(large, domain.LargeFile{Path: f.Path, SizeKB: f.Size / 1024, Severity: severity})
+		}
+	}
+	return large
+}
@@ -0,0 +1,71 @@
+package analyze
+
+import (
+	"testing"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+func TestIsNonSourceFile(t *testing.T) {
+	tests := []struct {
+		name string
+		exts []string
+		path string
+		want bool
+	}{
+		{name: "source file", path: "src/app.ts", want: false},
+		{name: "image", path: "assets/logo.png", want: true},
+		{name: "case-insensitive", path: "assets/Logo.PNG", want: true},
+		{name: "yarn lock", path: "yarn.lock", want: true},
+		{name: "npm lock", path: "web/package-lock.json", want: true},
+		{name: "plain json", path: "web/package.json", want: false},
+		{name: "go.sum", path: "go.sum", want: true},
+		{name: "minified js", path: "public/app.min.js", want: true},
+		{name: "generated protobuf", path: "api/user.pb.go", want: true},
+		{name: "vendor directory", path: "vendor/github.com/x/y/z.go", want: true},
+		{name: "nested node_modules", path: "web/node_modules/react/index.js", want: true},
+		{name: "vendor in file name only", path: "pkg/vendor.go", want: false},
+		{name: "custom list replaces default", exts: []string{".generated.go"}, path: "assets/logo.png", want: false},
+		{name: "custom extension", exts: []string{".GENERATED.go"}, path: "model/user.generated.go", want: true},
+		{name: "vendored even with custom list", exts: []string{".generated.go"}, path: "third_party/lib.c", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewService(nil, WithNonSourceExtensions(tt.exts))
+			if got := s.isNonSourceFile(tt.path); got != tt.want {
+				t.Errorf("isNonSourceFile(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectLargeFiles_SkipsNonSource(t *testing.T) {
+	s := &Service{}
+	files := []File{
+		{Path: "server.go", Size: 120 * 1024},         // 120KB - High
+		{Path: "package-lock.json", Size: 900 * 1024}, // 対象外
+		{Path: "assets/hero.png", Size: 60 * 1024},    // 対象外
+		{Path: "assets/icon.png", Size: 10 * 1024},    // 対象外かつ閾値未満
+		{Path: "vendor/lib/big.go", Size: 200 * 1024}, // 対象外
+	}
+
+	risks, largeFiles := s.detectLargeFiles(files)
+	if len(risks) != 1 || len(largeFiles) != 1 || largeFiles[0].Path != "server.go" {
+		t.Errorf("detectLargeFiles() = %d risks, %+v, want only server.go", len(risks), largeFiles)
+	}
+
+	nonSource := s.findLargeNonSourceFiles(files)
+	want := map[string]domain.Severity{
+		"package-lock.json": domain.SeverityHigh,
+		"assets/hero.png":   domain.SeverityMedium,
+		"vendor/lib/big.go": domain.SeverityHigh,
+	}
+	if len(nonSource) != len(want) {
+		t.Fatalf("findLargeNonSourceFiles() = %+v, want %d files", nonSource, len(want))
+	}
+	for _, f := range nonSource {
+		if sev, ok := want[f.Path]; !ok || sev != f.Severity {
+			t.Errorf("unexpected non-source file %+v", f)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package analyze
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OwnershipSource は属人化リスクでコミットの偏りを見るときの集計元。
+type OwnershipSource string
+
+const (
+	OwnershipSourceLifetime OwnershipSource = "lifetime" // コントリビューター API の累計コミット数（デフォルト）
+	OwnershipSourceWindow   OwnershipSource = "window"   // 分析期間内のコミットの作成者
+)
+
+// ParseOwnershipSource は文字列を属人化の集計元に変換する。空ならデフォルト（lifetime）。
+func ParseOwnershipSource(s string) (OwnershipSource, error) {
+	switch src := OwnershipSource(strings.ToLower(s)); src {
+	case "":
+		return OwnershipSourceLifetime, nil
+	case OwnershipSourceLifetime, OwnershipSourceWindow:
+		return src, nil
+	default:
+		return "", fmt.Errorf("unsupported ownership source: %q (use lifetime or window)", s)
+	}
+}
+
+// windowContributors は期間内のコミットを作成者ごとに集計し、コミット数の多い順のコントリビューター一覧を返す。
+//
+// 作成者は authorKey（メールアドレス、なければ名前）で識別し、表示名はその作成者の最初のコミットの名前を使う。
+// コミット数が同じなら表示名の順に並べる。
+func windowContributors(commits []Commit) []Contributor {
+	counts := make(map[string]int)
+	names := make(map[string]string) // 作成者キー → 表示名
+	for _, c := range commits {
+		key := authorKey(c)
+		if key == "" {
+			continue
+		}
+		if _, ok := names[key]; !ok {
+			names[key] = c.Author
+			if names[key] == "" {
+				names[key] = c.Email
+			}
+		}
+		counts[key]++
+	}
+
+	contributors := make([]Contributor, 0, len(counts))
+	for key, n := range counts {
+		contributors = append(contributors, Contributor{Login: names[key], Contributions: n})
+	}
+	sort.Slice(contributors, func(i, j int) bool {
+		if contributors[i].Contributions != contributors[j].Contributions {
+			return contributors[i].Contributions > contributors[j].Contributions
+		}
+		return contributors[i].Login < contributors[j].Login
+	})
+	return contributors
+}
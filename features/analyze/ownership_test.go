@@ -0,0 +1,108 @@
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+func TestParseOwnershipSource(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    OwnershipSource
+		wantErr bool
+	}{
+		{"", OwnershipSourceLifetime, false},
+		{"lifetime", OwnershipSourceLifetime, false},
+		{"Window", OwnershipSourceWindow, false},
+		{"recent", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseOwnershipSource(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseOwnershipSource(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseOwnershipSource(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindowContributors(t *testing.T) {
+	commits := []Commit{
+		{Author: "bob", Email: "bob@example.com"},
+		{Author: "Alice", Email: "alice@example.com"},
+		{Author: "alice", Email: "ALICE@example.com"}, // メールアドレスが同じなら同じ作成者
+		{Author: "carol", Email: "carol@example.com"},
+		{Author: "bob", Email: "bob@example.com"},
+		{Author: "", Email: "dave@example.com"}, // 名前がなければメールアドレスを表示名にする
+		{},                                      // 作成者が分からないコミットは数えない
+	}
+	want := []Contributor{
+		{Login: "Alice", Contributions: 2},
+		{Login: "bob", Contributions: 2},
+		{Login: "carol", Contributions: 1},
+		{Login: "dave@example.com", Contributions: 1},
+	}
+	if got := windowContributors(commits); !reflect.DeepEqual(got, want) {
+		t.Errorf("windowContributors() = %+v, want %+v", got, want)
+	}
+	if got := windowContributors(nil); len(got) != 0 {
+		t.Errorf("windowContributors(nil) = %+v, want empty", got)
+	}
+}
+
+func TestAnalyze_OwnershipSource(t *testing.T) {
+	period := domain.NewDateRange(
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+	)
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 12, 0, 0, 0, time.UTC) }
+
+	// 累計では何年も前に離れた創業者が大半を占めるが、期間内は bob がほぼ1人でコミットしている
+	repo := &mockRepository{
+		contributors: []Contributor{
+			{Login: "founder", Contributions: 900},
+			{Login: "bob", Contributions: 100},
+		},
+	}
+	for i := range 9 {
+		repo.commits = append(repo.commits, Commit{SHA: fmt.Sprintf("b%d", i), Author: "bob", Email: "bob@example.com", Date: day(i + 1), Message: "feat: x"})
+	}
+	repo.commits = append(repo.commits, Commit{SHA: "c", Author: "carol", Email: "carol@example.com", Date: day(20), Message: "fix: y"})
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		wantTarget string
+	}{
+		{"default lifetime", nil, "founder"},
+		{"window", []Option{WithOwnershipSource(OwnershipSourceWindow)}, "bob"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewService(repo, tt.opts...).Analyze(context.Background(), ServiceInput{
+				Repository: domain.NewRepository("owner", "repo"),
+				Period:     period,
+			})
+			if err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+			var targets []string
+			for _, r := range result.Risks {
+				if r.Type == domain.RiskTypeOwnership {
+					targets = append(targets, r.Target)
+				}
+			}
+			if len(targets) != 1 || targets[0] != tt.wantTarget {
+				t.Errorf("ownership risk targets = %v, want [%s]", targets, tt.wantTarget)
+			}
+		})
+	}
+}
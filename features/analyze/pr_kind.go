@@ -0,0 +1,146 @@
+package analyze
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// prKind はPRの種類（投資比率の分類）。
+type prKind int
+
+const (
+	prKindOther prKind = iota
+	prKindFeature
+	prKindBugFix
+	prKindRefactor
+)
+
+// BranchPrefixes はPRの種類ごとのブランチ名の接頭辞。
+// nil の種類は DefaultBranchPrefixes のまま、空のスライスならその種類はブランチ名では判定しない。
+type BranchPrefixes struct {
+	Feature  []string // 機能追加
+	BugFix   []string // バグ修正
+	Refactor []string // リファクタリング・雑務（chore / ci / docs など）
+}
+
+// DefaultBranchPrefixes はブランチ名の接頭辞のデフォルト。
+var DefaultBranchPrefixes = BranchPrefixes{
+	Feature:  []string{"feature/", "feat/"},
+	BugFix:   []string{"fix/", "bugfix/", "hotfix/"},
+	Refactor: []string{"refactor/", "chore/", "debt/", "ci/", "docs/"},
+}
+
+// NormalizeBranchPrefixes は接頭辞の前後の空白を除いて小文字にそろえ、nil の種類をデフォルトで補う。
+// 空の接頭辞や、同じ接頭辞が複数の種類にある場合はエラーを返す。
+func NormalizeBranchPrefixes(p BranchPrefixes) (BranchPrefixes, error) {
+	seen := make(map[string]string)
+	normalize := func(name string, prefixes, defaults []string) ([]string, error) {
+		if prefixes == nil {
+			prefixes = defaults
+		}
+		normalized := make([]string, len(prefixes))
+		for i, prefix := range prefixes {
+			normalized[i] = strings.ToLower(strings.TrimSpace(prefix))
+			if normalized[i] == "" {
+				return nil, fmt.Errorf("%s prefix at index %d is empty", name, i)
+			}
+			if other, ok := seen[normalized[i]]; ok && other != name {
+				return nil, fmt.Errorf("prefix %q is listed under both %s and %s", normalized[i], other, name)
+			}
+			seen[normalized[i]] = name
+		}
+		return normalized, nil
+	}
+
+	var out BranchPrefixes
+	var err error
+	if out.Feature, err = normalize("feature", p.Feature, DefaultBranchPrefixes.Feature); err != nil {
+		return BranchPrefixes{}, err
+	}
+	if out.BugFix, err = normalize("bugfix", p.BugFix, DefaultBranchPrefixes.BugFix); err != nil {
+		return BranchPrefixes{}, err
+	}
+	if out.Refactor, err = normalize("refactor", p.Refactor, DefaultBranchPrefixes.Refactor); err != nil {
+		return BranchPrefixes{}, err
+	}
+	return out, nil
+}
+
+// branchPrefixKind はブランチ名の接頭辞とPRの種類の組。
+type branchPrefixKind struct {
+	prefix string
+	kind   prKind
+}
+
+// prefixKinds は nil の種類をデフォルトで補い、照合用の一覧（小文字）にする。
+// 接頭辞が1つもなくても nil にはしない。
+// "fix/" と "fix/ui/" のように重なる場合に長いほうを優先するよう、長い順に並べる。
+func (p BranchPrefixes) prefixKinds() []branchPrefixKind {
+	kinds := []branchPrefixKind{}
+	for _, g := range []struct {
+		prefixes []string
+		defaults []string
+		kind     prKind
+	}{
+		{p.Feature, DefaultBranchPrefixes.Feature, prKindFeature},
+		{p.BugFix, DefaultBranchPrefixes.BugFix, prKindBugFix},
+		{p.Refactor, DefaultBranchPrefixes.Refactor, prKindRefactor},
+	} {
+		prefixes := g.prefixes
+		if prefixes == nil {
+			prefixes = g.defaults
+		}
+		for _, prefix := range prefixes {
+			kinds = append(kinds, branchPrefixKind{prefix: strings.ToLower(prefix), kind: g.kind})
+		}
+	}
+	slices.SortStableFunc(kinds, func(a, b branchPrefixKind) int {
+		return cmp.Compare(len(b.prefix), len(a.prefix))
+	})
+	return kinds
+}
+
+// defaultBranchPrefixKinds は DefaultBranchPrefixes の照合用の一覧。
+var defaultBranchPrefixKinds = DefaultBranchPrefixes.prefixKinds()
+
+// Conventional Commits の type → PRの種類
+var conventionalTypeKinds = map[string]prKind{
+	"feat":     prKindFeature,
+	"fix":      prKindBugFix,
+	"refactor": prKindRefactor,
+	"chore":    prKindRefactor,
+	"ci":       prKindRefactor,
+	"docs":     prKindRefactor,
+	"build":    prKindRefactor,
+	"style":    prKindRefactor,
+	"test":     prKindRefactor,
+	"perf":     prKindRefactor,
+}
+
+// conventionalTitlePattern は Conventional Commits 形式のタイトル（"type(scope)!: 説明"）。
+var conventionalTitlePattern = regexp.MustCompile(`^([a-z]+)(\([^)]*\))?!?:\s`)
+
+// prKind はPRの種類を判定する。
+// ブランチ名の接頭辞（WithBranchPrefixes、未設定ならデフォルト）を優先し、
+// 該当しなければタイトルの Conventional Commits 形式で判定する。
+func (s *Service) prKind(pr PullRequest) prKind {
+	kinds := s.branchPrefixKinds
+	if kinds == nil {
+		kinds = defaultBranchPrefixKinds
+	}
+	branch := strings.ToLower(pr.HeadBranch)
+	for _, bp := range kinds {
+		if strings.HasPrefix(branch, bp.prefix) {
+			return bp.kind
+		}
+	}
+
+	m := conventionalTitlePattern.FindStringSubmatch(strings.ToLower(pr.Title))
+	if m == nil {
+		return prKindOther
+	}
+	return conventionalTypeKinds[m[1]] // 未知の type は prKindOther
+}
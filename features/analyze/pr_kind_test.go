@@ -0,0 +1,160 @@
+package analyze
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPRKind_DefaultBranchPrefixes(t *testing.T) {
+	tests := []struct {
+		branch string
+		want   prKind
+	}{
+		{"feature/new-ui", prKindFeature},
+		{"feat/add-login", prKindFeature},
+		{"FEATURE/caps", prKindFeature},
+		{"fix/login-bug", prKindBugFix},
+		{"bugfix/issue-123", prKindBugFix},
+		{"hotfix/urgent", prKindBugFix},
+		{"FIX/uppercase", prKindBugFix},
+		{"refactor/cleanup", prKindRefactor},
+		{"chore/update-deps", prKindRefactor},
+		{"debt/reduce-tech-debt", prKindRefactor},
+		{"ci/fix-pipeline", prKindRefactor},
+		{"docs/update-readme", prKindRefactor},
+		{"story/login", prKindOther},
+		{"login-retry", prKindOther},
+	}
+	s := &Service{}
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			if got := s.prKind(PullRequest{HeadBranch: tt.branch}); got != tt.want {
+				t.Errorf("prKind() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPRKind_ConventionalTitle(t *testing.T) {
+	tests := []struct {
+		name   string
+		branch string
+		title  string
+		want   prKind
+	}{
+		{"feat with scope", "update-login", "feat(auth): add SSO login", prKindFeature},
+		{"fix breaking", "patch-1", "fix!: drop legacy token format", prKindBugFix},
+		{"fix scope breaking", "patch-2", "fix(api)!: reject empty body", prKindBugFix},
+		{"refactor", "cleanup", "refactor: split handler", prKindRefactor},
+		{"chore", "deps", "chore(deps): bump lodash", prKindRefactor},
+		{"uppercase type", "x", "Feat: capitalized", prKindFeature},
+		{"unknown type", "x", "wip: something", prKindOther},
+		{"not conventional", "x", "Fix the login bug", prKindOther},
+		{"missing space", "x", "feat:no-space", prKindOther},
+		// ブランチ名が優先される
+		{"branch wins over title", "fix/login", "feat: add login retry", prKindBugFix},
+		{"branch refactor wins", "chore/ci", "fix: flaky test", prKindRefactor},
+		{"no branch prefix falls back to title", "login-retry", "fix: retry login", prKindBugFix},
+	}
+	s := &Service{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := PullRequest{HeadBranch: tt.branch, Title: tt.title}
+			if got := s.prKind(pr); got != tt.want {
+				t.Errorf("prKind() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPRKind_CustomBranchPrefixes(t *testing.T) {
+	prefixes, err := NormalizeBranchPrefixes(BranchPrefixes{
+		Feature:  []string{"story/"},
+		Refactor: []string{"task/", "Fix/Lint/"},
+		// BugFix は省略 → デフォルトのまま
+	})
+	if err != nil {
+		t.Fatalf("NormalizeBranchPrefixes() error = %v", err)
+	}
+	s := NewService(nil, WithBranchPrefixes(prefixes))
+
+	tests := []struct {
+		branch string
+		title  string
+		want   prKind
+	}{
+		{"story/login", "", prKindFeature},
+		{"Story/caps", "", prKindFeature},
+		{"task/bump-deps", "", prKindRefactor},
+		{"fix/login", "", prKindBugFix},
+		{"hotfix/urgent", "", prKindBugFix},
+		// 長い接頭辞が優先される
+		{"fix/lint/eslint", "", prKindRefactor},
+		// 置き換えた種類のデフォルトは使わない（タイトルにフォールバック）
+		{"feature/new-ui", "", prKindOther},
+		{"chore/deps", "", prKindOther},
+		{"chore/deps", "chore: bump deps", prKindRefactor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.branch+" "+tt.title, func(t *testing.T) {
+			if got := s.prKind(PullRequest{HeadBranch: tt.branch, Title: tt.title}); got != tt.want {
+				t.Errorf("prKind() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// 全種類を空にするとブランチ名では判定しない
+	none := NewService(nil, WithBranchPrefixes(BranchPrefixes{Feature: []string{}, BugFix: []string{}, Refactor: []string{}}))
+	if got := none.prKind(PullRequest{HeadBranch: "fix/login"}); got != prKindOther {
+		t.Errorf("prKind() with no prefixes = %v, want %v", got, prKindOther)
+	}
+}
+
+func TestNormalizeBranchPrefixes(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      BranchPrefixes
+		want    BranchPrefixes
+		wantErr bool
+	}{
+		{
+			name: "zero value is defaults",
+			in:   BranchPrefixes{},
+			want: DefaultBranchPrefixes,
+		},
+		{
+			name: "trimmed and lowercased",
+			in:   BranchPrefixes{Feature: []string{" Story/ "}, Refactor: []string{}},
+			want: BranchPrefixes{Feature: []string{"story/"}, BugFix: DefaultBranchPrefixes.BugFix, Refactor: []string{}},
+		},
+		{
+			name:    "empty prefix",
+			in:      BranchPrefixes{Feature: []string{"story/", "  "}},
+			wantErr: true,
+		},
+		{
+			name:    "prefix under two kinds",
+			in:      BranchPrefixes{Feature: []string{"task/"}, Refactor: []string{"Task/"}},
+			wantErr: true,
+		},
+		{
+			name:    "custom prefix collides with default",
+			in:      BranchPrefixes{Feature: []string{"fix/"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeBranchPrefixes(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeBranchPrefixes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !slices.Equal(got.Feature, tt.want.Feature) || !slices.Equal(got.BugFix, tt.want.BugFix) || !slices.Equal(got.Refactor, tt.want.Refactor) {
+				t.Errorf("NormalizeBranchPrefixes() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
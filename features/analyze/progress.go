@@ -0,0 +1,69 @@
+package analyze
+
+import (
+	"sync/atomic"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// 分析のフェーズ（Progress.Phase の値）
+const (
+	PhaseFetching      = "fetching repository data"
+	PhaseCommitDetails = "fetching commit details"
+	PhasePRDetails     = "fetching PR details"
+	PhaseTodoScan      = "scanning TODO comments"
+	PhaseAnalyzing     = "analyzing"
+)
+
+// Progress は分析の進み具合。
+type Progress struct {
+	Repository domain.Repository
+	Phase      string // 実行中のフェーズ（Phase* のいずれか）
+	Done       int    // 完了した件数
+	Total      int    // 全件数（0 なら件数を数えないフェーズ）
+}
+
+// ProgressFunc は分析の進捗の通知を受け取る。
+// 並行に取得している処理からも呼ばれるが、Service が呼び出しを直列化するため同時には呼ばれない。
+// 通知のたびに呼ばれるので、重い処理はしない。
+type ProgressFunc func(Progress)
+
+// WithProgress は進捗の通知先を設定する。
+// 大きなリポジトリで分析が長引くときに、CLI やアプリケーションが進み具合を表示するために使う。
+func WithProgress(f ProgressFunc) Option {
+	return func(s *Service) {
+		s.progress = f
+	}
+}
+
+// reportProgress は進捗を通知する（通知先がなければ何もしない）。
+func (s *Service) reportProgress(p Progress) {
+	if s.progress == nil {
+		return
+	}
+	s.progressMu.Lock()
+	defer s.progressMu.Unlock()
+	s.progress(p)
+}
+
+// progressCounter は件数の決まったフェーズの進捗を数えて通知する。
+// ワーカープールの各 goroutine から step を呼べる。
+type progressCounter struct {
+	s     *Service
+	repo  domain.Repository
+	phase string
+	total int
+	done  atomic.Int64
+}
+
+// startProgress はフェーズの開始（0件完了）を通知し、件数を数える progressCounter を返す。
+func (s *Service) startProgress(repo domain.Repository, phase string, total int) *progressCounter {
+	s.reportProgress(Progress{Repository: repo, Phase: phase, Total: total})
+	return &progressCounter{s: s, repo: repo, phase: phase, total: total}
+}
+
+// step は1件の完了を通知する。
+func (p *progressCounter) step() {
+	done := p.done.Add(1)
+	p.s.reportProgress(Progress{Repository: p.repo, Phase: p.phase, Done: int(done), Total: p.total})
+}
@@ -0,0 +1,60 @@
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+func TestAnalyze_Progress(t *testing.T) {
+	base := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	repo := &mockRepository{commitFiles: make(map[string][]string)}
+	for i := range 4 {
+		sha := fmt.Sprintf("sha%d", i)
+		repo.commits = append(repo.commits, Commit{SHA: sha, Author: "alice", Date: base.Add(time.Duration(i) * time.Hour)})
+		repo.commitFiles[sha] = []string{"main.go"}
+	}
+	merged := base.Add(24 * time.Hour)
+	for n := 1; n <= 3; n++ {
+		repo.closedPRs = append(repo.closedPRs, PullRequest{Number: n, CreatedAt: base, MergedAt: &merged})
+	}
+
+	// 通知は直列化されるため、ロックなしで記録できる
+	var got []Progress
+	s := NewService(repo, WithProgress(func(p Progress) { got = append(got, p) }))
+	target := domain.NewRepository("owner", "repo")
+	_, err := s.Analyze(context.Background(), ServiceInput{
+		Repository: target,
+		Period:     domain.NewDateRange(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)),
+	})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	// フェーズごとの最後の通知
+	last := make(map[string]Progress)
+	for _, p := range got {
+		if p.Repository != target {
+			t.Errorf("Repository = %v, want %v", p.Repository, target)
+		}
+		last[p.Phase] = p
+	}
+	if p := last[PhaseFetching]; p.Total == 0 || p.Done != p.Total {
+		t.Errorf("last %q = %d/%d, want all fetches done", PhaseFetching, p.Done, p.Total)
+	}
+	if p := last[PhaseCommitDetails]; p.Done != 4 || p.Total != 4 {
+		t.Errorf("last %q = %d/%d, want 4/4", PhaseCommitDetails, p.Done, p.Total)
+	}
+	if p := last[PhasePRDetails]; p.Done != 3 || p.Total != 3 {
+		t.Errorf("last %q = %d/%d, want 3/3", PhasePRDetails, p.Done, p.Total)
+	}
+	if _, ok := last[PhaseTodoScan]; ok {
+		t.Errorf("%q reported without WithTodoScan", PhaseTodoScan)
+	}
+	if len(got) == 0 || got[len(got)-1].Phase != PhaseAnalyzing {
+		t.Errorf("progress = %+v, want to end with %q", got, PhaseAnalyzing)
+	}
+}
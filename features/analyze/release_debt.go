@@ -0,0 +1,81 @@
+package analyze
+
+import (
+	"context"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// releaseDebt は最後のリリース以降に溜まった未リリースの変更。
+type releaseDebt struct {
+	lastReleaseAt   time.Time // ゼロ値なら期間の終わりまでにリリースがない
+	lastReleaseName string
+	days            int
+	commits         int
+}
+
+// apply はリリース負債をメトリクスに書き込む。
+func (d releaseDebt) apply(m *domain.Metrics) {
+	m.LastReleaseAt = d.lastReleaseAt
+	m.LastReleaseName = d.lastReleaseName
+	m.DaysSinceLastRelease = d.days
+	m.CommitsSinceLastRelease = d.commits
+}
+
+// latestRelease は until までに公開された最新のリリースを返す（なければ false）。
+// 日時のないリリース（下書きなど）は数えない。
+func latestRelease(releases []Release, until time.Time) (Release, bool) {
+	var latest Release
+	found := false
+	for _, r := range releases {
+		if r.PublishedAt.IsZero() || r.PublishedAt.After(until) {
+			continue
+		}
+		if !found || r.PublishedAt.After(latest.PublishedAt) {
+			latest, found = r, true
+		}
+	}
+	return latest, found
+}
+
+// calculateReleaseDebt は最後のリリースから期間の終わりまでの日数と、その間のコミット数を求める。
+//
+// リリースはデプロイ頻度と同じ取得元（--deploy-source）から選ぶ。
+// 最後のリリースが分析期間内なら取得済みのコミットから数え、期間より前なら
+// リリースから期間の終わりまでのコミットを取得し直す。取り直しに失敗した場合は
+// 期間内のコミットだけを数える（実際より少なく見積もる）。
+func (s *Service) calculateReleaseDebt(ctx context.Context, repo domain.Repository, period domain.DateRange, releases []Release, commits []Commit) releaseDebt {
+	last, ok := latestRelease(releases, period.To)
+	if !ok {
+		return releaseDebt{}
+	}
+
+	name := last.TagName
+	if name == "" {
+		name = last.Name
+	}
+	debt := releaseDebt{
+		lastReleaseAt:   last.PublishedAt,
+		lastReleaseName: name,
+		days:            int(period.To.Sub(last.PublishedAt).Hours() / 24),
+	}
+
+	if last.PublishedAt.Before(period.From) {
+		start := time.Now()
+		since, err := s.repo.GetCommits(ctx, repo, domain.NewDateRange(last.PublishedAt, period.To), s.path, s.branch)
+		s.logFetch("commits since last release", start, len(since), err)
+		if err != nil {
+			s.warnUnlessCanceled(ctx, "failed to get commits since last release", err)
+		} else {
+			commits = since
+		}
+	}
+
+	for _, c := range commits {
+		if c.Date.After(last.PublishedAt) && !c.Date.After(period.To) {
+			debt.commits++
+		}
+	}
+	return debt
+}
@@ -0,0 +1,168 @@
+package analyze
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+func TestCalculateReleaseDebt(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 12, 0, 0, 0, time.UTC) }
+	period := domain.NewDateRange(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC))
+	commitsOn := func(days ...int) []Commit {
+		commits := make([]Commit, len(days))
+		for i, d := range days {
+			commits[i] = Commit{SHA: string(rune('a' + i)), Date: day(d)}
+		}
+		return commits
+	}
+	before := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	// 期間より前のコミット（取り直したときだけ数える）
+	older := []Commit{
+		{SHA: "old1", Date: time.Date(2024, 11, 20, 0, 0, 0, 0, time.UTC)}, // リリースより前
+		{SHA: "old2", Date: time.Date(2024, 12, 10, 0, 0, 0, 0, time.UTC)},
+		{SHA: "old3", Date: time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)},
+	}
+
+	tests := []struct {
+		name        string
+		releases    []Release
+		commits     []Commit // 分析期間のコミット
+		failOn      string
+		wantName    string
+		wantAt      time.Time
+		wantDays    int
+		wantCommits int
+		wantFetch   bool
+	}{
+		{
+			name:     "no release",
+			releases: nil,
+			commits:  commitsOn(5, 10),
+		},
+		{
+			name:        "release in period counts later commits",
+			releases:    []Release{{TagName: "v1.0.0", PublishedAt: day(8)}, {TagName: "v1.1.0", PublishedAt: day(12)}},
+			commits:     commitsOn(5, 10, 15, 20),
+			wantName:    "v1.1.0",
+			wantAt:      day(12),
+			wantDays:    18,
+			wantCommits: 2,
+		},
+		{
+			name:        "release after period end is ignored",
+			releases:    []Release{{TagName: "v1.0.0", PublishedAt: day(8)}, {TagName: "v2.0.0", PublishedAt: time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)}},
+			commits:     commitsOn(5, 10, 15),
+			wantName:    "v1.0.0",
+			wantAt:      day(8),
+			wantDays:    22,
+			wantCommits: 2,
+		},
+		{
+			name:        "release without tag uses name",
+			releases:    []Release{{Name: "deploy", PublishedAt: day(8)}, {TagName: "draft"}},
+			commits:     commitsOn(10),
+			wantName:    "deploy",
+			wantAt:      day(8),
+			wantDays:    22,
+			wantCommits: 1,
+		},
+		{
+			name:        "release before period refetches commits",
+			releases:    []Release{{TagName: "v0.9.0", PublishedAt: before}},
+			commits:     commitsOn(5, 10),
+			wantName:    "v0.9.0",
+			wantAt:      before,
+			wantDays:    61,
+			wantCommits: 4,
+			wantFetch:   true,
+		},
+		{
+			name:        "refetch failure falls back to period commits",
+			releases:    []Release{{TagName: "v0.9.0", PublishedAt: before}},
+			commits:     commitsOn(5, 10),
+			failOn:      "GetCommits",
+			wantName:    "v0.9.0",
+			wantAt:      before,
+			wantDays:    61,
+			wantCommits: 2,
+			wantFetch:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRepository{commits: append(append([]Commit{}, older...), tt.commits...), failOn: tt.failOn}
+			s := NewService(repo)
+			got := s.calculateReleaseDebt(context.Background(), domain.NewRepository("owner", "repo"), period, tt.releases, tt.commits)
+
+			if got.lastReleaseName != tt.wantName || !got.lastReleaseAt.Equal(tt.wantAt) {
+				t.Errorf("last release = %q at %v, want %q at %v", got.lastReleaseName, got.lastReleaseAt, tt.wantName, tt.wantAt)
+			}
+			if got.days != tt.wantDays || got.commits != tt.wantCommits {
+				t.Errorf("days, commits = %d, %d, want %d, %d", got.days, got.commits, tt.wantDays, tt.wantCommits)
+			}
+			if fetched := len(repo.calls) > 0; fetched != tt.wantFetch {
+				t.Errorf("fetched = %v (calls %v), want %v", fetched, repo.calls, tt.wantFetch)
+			}
+		})
+	}
+}
+
+func TestDetectMetricRisks_ReleaseDebt(t *testing.T) {
+	released := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		metrics      domain.Metrics
+		wantRisk     bool
+		wantSeverity domain.Severity
+	}{
+		{"no release", domain.Metrics{DaysSinceLastRelease: 0, CommitsSinceLastRelease: 0}, false, 0},
+		{"recent", domain.Metrics{LastReleaseAt: released, DaysSinceLastRelease: 10, CommitsSinceLastRelease: 100}, false, 0},
+		{"old but quiet", domain.Metrics{LastReleaseAt: released, DaysSinceLastRelease: 120, CommitsSinceLastRelease: 10}, false, 0},
+		{"warning", domain.Metrics{LastReleaseAt: released, DaysSinceLastRelease: 45, CommitsSinceLastRelease: 80}, true, domain.SeverityMedium},
+		{"days critical only", domain.Metrics{LastReleaseAt: released, DaysSinceLastRelease: 120, CommitsSinceLastRelease: 80}, true, domain.SeverityMedium},
+		{"critical", domain.Metrics{LastReleaseAt: released, DaysSinceLastRelease: 120, CommitsSinceLastRelease: 250}, true, domain.SeverityHigh},
+	}
+	s := &Service{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.metrics.LastReleaseName = "v1.0.0"
+			var found *domain.Risk
+			for _, r := range s.detectMetricRisks(tt.metrics) {
+				if r.Type == domain.RiskTypeReleaseDebt {
+					found = &r
+				}
+			}
+			if (found != nil) != tt.wantRisk {
+				t.Fatalf("release debt risk = %+v, want %v", found, tt.wantRisk)
+			}
+			if found != nil && (found.Severity != tt.wantSeverity || found.Value != tt.metrics.CommitsSinceLastRelease) {
+				t.Errorf("risk = %+v, want severity %v with value %d", *found, tt.wantSeverity, tt.metrics.CommitsSinceLastRelease)
+			}
+		})
+	}
+}
+
+func TestAnalyze_ReleaseDebt(t *testing.T) {
+	repo := &mockRepository{
+		commits:  []Commit{{SHA: "a", Date: time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)}},
+		releases: []Release{{TagName: "v1.0.0", PublishedAt: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)}},
+	}
+	result, err := NewService(repo).Analyze(context.Background(), ServiceInput{
+		Repository: domain.NewRepository("owner", "repo"),
+		Period: domain.NewDateRange(
+			time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		),
+	})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	m := result.Metrics
+	if m.LastReleaseName != "v1.0.0" || m.DaysSinceLastRelease != 21 || m.CommitsSinceLastRelease != 1 {
+		t.Errorf("release debt = %q, %d days, %d commits, want v1.0.0, 21 days, 1 commit",
+			m.LastReleaseName, m.DaysSinceLastRelease, m.CommitsSinceLastRelease)
+	}
+}
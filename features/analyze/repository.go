@@ -2,21 +2,33 @@ package analyze
 
 import (
 	"context"
-	"strings"
 	"time"
 
 	"github.com/ryuka-games/lokup/domain"
 )
 
 // Repository はデータ取得のインターフェース。
-// infrastructure/github パッケージで実装される。
+// infrastructure/github・infrastructure/gitlab パッケージで実装される。
 //
 // なぜ interface か:
 // - テスト時にモックに差し替えるため
 // - GitHub API 以外のデータソースにも対応できるようにするため
 type Repository interface {
 	// GetCommits は指定期間のコミット履歴を取得する。
-	GetCommits(ctx context.Context, repo domain.Repository, period domain.DateRange) ([]Commit, error)
+	// path が空でなければ、そのパス配下を変更したコミットのみを返す。
+	// branch が空でなければそのブランチの履歴を、空ならデフォルトブランチの履歴を対象にする。
+	// 一覧APIの制約により Files / Additions / Deletions は含まれない。
+	GetCommits(ctx context.Context, repo domain.Repository, period domain.DateRange, path, branch string) ([]Commit, error)
+
+	// GetLastCommit は before 以前で最新のコミットを1件だけ取得する（休眠の判定で最終活動日を求める用途）。
+	// path・branch の扱いは GetCommits と同じ。コミットがなければ nil を返す（エラーではない）。
+	GetLastCommit(ctx context.Context, repo domain.Repository, before time.Time, path, branch string) (*Commit, error)
+
+	// BranchExists はブランチが存在するかを返す（--branch の検証用）。
+	BranchExists(ctx context.Context, repo domain.Repository, branch string) (bool, error)
+
+	// GetCommitDetail はコミットの詳細（変更ファイル・行数含む）を取得する。
+	GetCommitDetail(ctx context.Context, repo domain.Repository, sha string) (*Commit, error)
 
 	// GetContributors はコントリビューター一覧を取得する。
 	GetContributors(ctx context.Context, repo domain.Repository) ([]Contributor, error)
@@ -28,7 +40,9 @@ type Repository interface {
 	GetPullRequests(ctx context.Context, repo domain.Repository, state string) ([]PullRequest, error)
 
 	// GetFiles はリポジトリ内のファイル一覧を取得する。
-	GetFiles(ctx context.Context, repo domain.Repository) ([]File, error)
+	// branch が空でなければそのブランチの、空ならデフォルトブランチのファイルを対象にする。
+	// 大きなリポジトリで一覧の一部しか取得できなかった場合は truncated = true を返す（エラーではない）。
+	GetFiles(ctx context.Context, repo domain.Repository, branch string) (files []File, truncated bool, err error)
 
 	// GetDependencies はpackage.json等から依存情報を取得する。
 	// 依存ファイルが存在しない場合は空のスライスを返す（エラーではない）。
@@ -37,14 +51,36 @@ type Repository interface {
 	// GetIssues はIssue一覧を取得する。
 	GetIssues(ctx context.Context, repo domain.Repository, state string, since *time.Time) ([]Issue, error)
 
+	// GetIssueEvents は Issue のイベント（ラベル付け・アサインなど）を古い順に取得する。
+	GetIssueEvents(ctx context.Context, repo domain.Repository, issueNumber int) ([]IssueEvent, error)
+
 	// GetPRReviews はPRのレビュー一覧を取得する。
 	GetPRReviews(ctx context.Context, repo domain.Repository, prNumber int) ([]Review, error)
 
+	// GetPRComments はPRの会話欄のコメント（Issue コメント）を取得する。
+	// 行単位のレビューコメントは含まない（レビューとして GetPRReviews で取得する）。
+	GetPRComments(ctx context.Context, repo domain.Repository, prNumber int) ([]Comment, error)
+
 	// GetPRDetail はPRの詳細（additions/deletions含む）を取得する。
 	GetPRDetail(ctx context.Context, repo domain.Repository, prNumber int) (*PullRequest, error)
 
 	// GetReleases はリリース一覧を取得する。
 	GetReleases(ctx context.Context, repo domain.Repository) ([]Release, error)
+
+	// GetTags はタグ一覧を、タグが指すコミットの日時付きで取得する。
+	GetTags(ctx context.Context, repo domain.Repository) ([]Tag, error)
+
+	// GetWorkflowRuns は指定期間に成功したワークフロー実行のうち、
+	// ワークフロー名（またはワークフローファイル名）が workflow に一致するものを取得する。
+	GetWorkflowRuns(ctx context.Context, repo domain.Repository, workflow string, period domain.DateRange) ([]WorkflowRun, error)
+
+	// GetBranchProtection はデフォルトブランチの保護設定を取得する。
+	// 権限不足などで保護設定を読めない場合は nil を返す（エラーではない）。
+	GetBranchProtection(ctx context.Context, repo domain.Repository) (*domain.BranchProtection, error)
+
+	// GetForcePushes は指定期間にデフォルトブランチへ行われた force push を取得する。
+	// アクティビティを取得できない環境（古い GitHub Enterprise Server や権限不足）では nil を返す（エラーではない）。
+	GetForcePushes(ctx context.Context, repo domain.Repository, period domain.DateRange) ([]ForcePush, error)
 }
 
 // File はファイル情報を表す。
@@ -60,6 +96,7 @@ type Commit struct {
 	Email     string    // メールアドレス
 	Date      time.Time // コミット日時
 	Message   string    // コミットメッセージ
+	Parents   []string  // 親コミットのハッシュ（先頭が first parent。2つ以上ならマージコミット）
 	Files     []string  // 変更されたファイル
 	Additions int       // 追加行数
 	Deletions int       // 削除行数
@@ -77,44 +114,27 @@ type PullRequest struct {
 	Title      string     // タイトル
 	Author     string     // 作成者
 	HeadBranch string     // ブランチ名（例: "fix/login-bug"）
+	HeadSHA    string     // ブランチ先頭のコミットハッシュ
+	MergeSHA   string     // マージで作られたコミットのハッシュ（マージ/スカッシュ/リベース後の先頭。未マージなら空）
 	CreatedAt  time.Time  // 作成日時
 	MergedAt   *time.Time // マージ日時（nilならマージされていない）
 	Additions  int        // 追加行数
 	Deletions  int        // 削除行数
+	Draft      bool       // ドラフトPRか
 }
 
 // LeadTime はPRのリードタイム（作成からマージまでの日数）を返す。
-// マージされていない場合は-1を返す。
+// マージされていない場合と、日時が欠けている・マージが作成より前（時計のずれ）で
+// リードタイムを決められない場合は-1を返す。
 func (pr PullRequest) LeadTime() float64 {
 	if pr.MergedAt == nil {
 		return -1
 	}
-	return pr.MergedAt.Sub(pr.CreatedAt).Hours() / 24
-}
-
-// IsBugFix はブランチ名からバグ修正PRかどうかを判定する。
-func (pr PullRequest) IsBugFix() bool {
-	branch := strings.ToLower(pr.HeadBranch)
-	return strings.HasPrefix(branch, "fix/") ||
-		strings.HasPrefix(branch, "bugfix/") ||
-		strings.HasPrefix(branch, "hotfix/")
-}
-
-// IsFeature はブランチ名から機能追加PRかどうかを判定する。
-func (pr PullRequest) IsFeature() bool {
-	branch := strings.ToLower(pr.HeadBranch)
-	return strings.HasPrefix(branch, "feature/") ||
-		strings.HasPrefix(branch, "feat/")
-}
-
-// IsRefactor はブランチ名からリファクタリング系PRかどうかを判定する。
-func (pr PullRequest) IsRefactor() bool {
-	branch := strings.ToLower(pr.HeadBranch)
-	return strings.HasPrefix(branch, "refactor/") ||
-		strings.HasPrefix(branch, "chore/") ||
-		strings.HasPrefix(branch, "debt/") ||
-		strings.HasPrefix(branch, "ci/") ||
-		strings.HasPrefix(branch, "docs/")
+	d, ok := elapsed(pr.CreatedAt, *pr.MergedAt)
+	if !ok {
+		return -1
+	}
+	return d.Hours() / 24
 }
 
 // Dependency は依存パッケージ情報を表す。
@@ -134,6 +154,16 @@ type Issue struct {
 	Labels    []string   // ラベル名一覧（"bug", "incident" 等）
 	CreatedAt time.Time  // 作成日時
 	ClosedAt  *time.Time // クローズ日時（nilならオープン）
+
+	// 復旧作業の開始日時（MTTRSourceEvents のときイベントから求める。nil なら CreatedAt を使う）
+	RecoveryStartedAt *time.Time
+}
+
+// IssueEvent は Issue のイベント（ラベル付け・アサインなど）を表す。
+type IssueEvent struct {
+	Event     string    // イベントの種類（"labeled", "assigned" 等）
+	Label     string    // 付け外ししたラベル名（labeled / unlabeled のみ）
+	CreatedAt time.Time // 発生日時
 }
 
 // Release はリリース情報を表す。
@@ -144,6 +174,28 @@ type Release struct {
 	PublishedAt time.Time // 公開日時
 }
 
+// Tag はタグ情報を表す。
+type Tag struct {
+	Name string    // タグ名
+	SHA  string    // タグが指すコミットのハッシュ
+	Date time.Time // タグが指すコミットの日時
+}
+
+// WorkflowRun は GitHub Actions のワークフロー実行を表す。
+type WorkflowRun struct {
+	ID        int       // 実行ID
+	Name      string    // ワークフロー名
+	Path      string    // ワークフローファイルのパス（.github/workflows/deploy.yml など）
+	CreatedAt time.Time // 実行開始日時
+}
+
+// ForcePush はブランチへの force push（履歴の書き換え）1回分を表す。
+type ForcePush struct {
+	Branch    string    // 対象のブランチ名
+	Actor     string    // 実行したユーザー
+	Timestamp time.Time // 実行日時
+}
+
 // Review はPRレビュー情報を表す。
 type Review struct {
 	ID          int       // レビューID
@@ -151,3 +203,11 @@ type Review struct {
 	State       string    // "APPROVED", "CHANGES_REQUESTED", "COMMENTED" など
 	SubmittedAt time.Time // 投稿日時
 }
+
+// Comment はPRの会話欄のコメントを表す。
+type Comment struct {
+	ID        int       // コメントID
+	Author    string    // 投稿者
+	Bot       bool      // 投稿者が Bot アカウントか
+	CreatedAt time.Time // 投稿日時
+}
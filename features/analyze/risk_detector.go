@@ -1,9 +1,16 @@
 package analyze
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/shared/i18n"
 )
 
 // ── リスク検出の閾値 ─────────────────────────────────────────
@@ -16,11 +23,28 @@ const (
 	// 属人化リスク
 	ownershipThreshold = 0.8 // コミット割合（80%以上で属人化）
 
+	// 共同作成（Co-authored-by）による属人化リスクの緩和（WithCoAuthorshipMitigation 指定時のみ）
+	coAuthorshipMinCommits    = 10   // 判定に必要な最小コミット数
+	coAuthorshipMitigationPct = 20.0 // 共同作成のコミットの割合（これ以上で属人化リスクを Low に下げる）
+
+	// ファイル単位の属人化（1人の作成者が変更の大半を占めるファイル）
+	fileOwnershipMinChanges    = 5   // 判定に必要な最小変更回数
+	fileOwnershipShare         = 0.9 // 最多の作成者が占める変更の割合（これ以上でサイロ化）
+	fileOwnershipCountCritical = 10  // サイロ化したファイル数（critical）
+
+	// バス係数リスク（CODEOWNERS）
+	busFactorOwnerShare       = 0.8 // ディレクトリ内で単独オーナーが持つファイル割合
+	busFactorTreeShareWarning = 0.1 // リポジトリ全体に占めるディレクトリのファイル割合（warning）
+	busFactorTreeShareHigh    = 0.3 // 同上（critical）
+
 	// 深夜労働リスク
 	lateNightStartHour     = 22  // 深夜開始（22時）
 	lateNightEndHour       = 5   // 深夜終了（5時）
 	lateNightRateThreshold = 0.3 // 深夜コミット割合（30%以上で警告）
 
+	// 週末労働リスク
+	weekendRateThreshold = 0.2 // 土日のコミット割合（20%以上で警告）
+
 	// 巨大ファイル
 	largeFileWarningBytes  = 50 * 1024  // 50KB
 	largeFileCriticalBytes = 100 * 1024 // 100KB
@@ -29,6 +53,25 @@ const (
 	outdatedDepWarningMonths  = 24 // 2年
 	outdatedDepCriticalMonths = 36 // 3年
 
+	// TODO コメント（--scan-todos 指定時のみ）
+	todoDensityMinLines        = 1000 // 判定に必要な最小行数
+	todoDensityWarningPerKLOC  = 5.0  // 1000行あたりの件数（warning、これを超えたら検出）
+	todoDensityCriticalPerKLOC = 10.0 // 同上（critical）
+
+	// PR未経由の直接プッシュ（デフォルトブランチの first-parent 履歴に占める割合）
+	directPushMinCommits   = 10  // 判定に必要な最小コミット数
+	directPushRateWarning  = 0.2 // 割合（warning、これを超えたら検出）
+	directPushRateCritical = 0.5 // 割合（critical）
+
+	// 滞留PR（オープンのまま DefaultStalePRDays 日以上経過したPRの件数）
+	stalePRCountWarning  = 5  // 件数（warning、これを超えたら検出）
+	stalePRCountCritical = 15 // 件数（critical）
+
+	// 放棄PR（クローズ済みPRのうちマージされなかったものの割合）
+	prAbandonmentMinPRs      = 10   // 判定に必要な最小クローズ済みPR数
+	prAbandonmentWarningPct  = 30.0 // 割合（warning、これを超えたら検出）
+	prAbandonmentCriticalPct = 50.0 // 割合（critical）
+
 	// メトリクスベースのリスク閾値
 	leadTimeThresholdDays      = 7.0  // PRリードタイム（日）
 	reviewWaitThresholdHours   = 48.0 // レビュー待ち（時間）
@@ -36,17 +79,51 @@ const (
 	issueCloseRateThresholdPct = 50.0 // Issueクローズ率（%）
 	bugFixRatioThresholdPct    = 50.0 // バグ修正割合（%）
 
+	// レビューカバレッジ（承認・変更要求のレビューを受けたマージ済みPRの割合）
+	reviewCoverageMinPRs      = 5    // 判定に必要な最小PR数
+	reviewCoverageWarningPct  = 80.0 // カバレッジ（warning、これを下回ったら検出）
+	reviewCoverageCriticalPct = 50.0 // カバレッジ（critical）
+
+	// セルフマージ（作成者以外の承認なしでマージされたPRの割合。最小PR数はレビューカバレッジと共通）
+	selfMergeRateWarningPct  = 20.0 // 割合（warning、これを超えたら検出）
+	selfMergeRateCriticalPct = 50.0 // 割合（critical）
+
+	// コミットメッセージの品質（空・1単語・WIP などのメッセージの割合）
+	lowCommitQualityMinCommits  = 20   // 判定に必要な最小コミット数
+	lowCommitQualityWarningPct  = 30.0 // 割合（warning、これを超えたら検出）
+	lowCommitQualityCriticalPct = 50.0 // 割合（critical）
+
+	// コミットの粒度（変更ファイルを取得できたコミットの、1コミットあたりの平均変更ファイル数）
+	largeCommitMinCommits    = 20   // 判定に必要な最小コミット数
+	largeCommitWarningFiles  = 10.0 // 平均ファイル数（warning、これを超えたら検出）
+	largeCommitCriticalFiles = 25.0 // 平均ファイル数（critical）
+
+	// テストファイルの不足（ソースファイル1件あたりのテストファイル数。ファイル名による推定）
+	lowTestRatioMinSourceFiles = 20   // 判定に必要な最小ソースファイル数
+	lowTestRatioWarning        = 0.1  // 比率（warning、これを下回ったら検出）
+	lowTestRatioCritical       = 0.02 // 比率（critical）
+
+	// すぐに取り消された変更（元のコミットから fastRevertWindow 以内の Revert の件数）
+	fastRevertCountWarning  = 1 // 件数（warning、これ以上で検出）
+	fastRevertCountCritical = 3 // 件数（critical）
+
 	// DORA メトリクス閾値
 	deployFreqThresholdPerMonth   = 1.0  // 月1回未満でリスク
 	changeFailureThresholdPct     = 30.0 // 30%超でリスク
 	mttrThresholdHours            = 24.0 // 24時間超でリスク
 	featureInvestmentThresholdPct = 30.0 // 機能追加30%未満でリスク
 
+	// リリース負債（最後のリリースからの日数とコミット数が両方とも超えたら検出）
+	releaseDebtWarningDays     = 30  // 日数（warning）
+	releaseDebtWarningCommits  = 50  // コミット数（warning）
+	releaseDebtCriticalDays    = 90  // 日数（critical）
+	releaseDebtCriticalCommits = 200 // コミット数（critical）
+
+	// 休眠リポジトリ（期間内にコミット・マージ・リリースがすべてない）
+	inactiveRepoCriticalDays = 365 // 最終活動からの日数（critical）
+
 	// スコア計算
-	baseScore     = 100 // カテゴリスコアの初期値
-	penaltyHigh   = -15 // SeverityHigh の減点
-	penaltyMedium = -10 // SeverityMedium の減点
-	penaltyLow    = -5  // SeverityLow の減点
+	baseScore = 100 // カテゴリスコアの初期値
 )
 
 // ── データソースに基づくリスク検出 ──────────────────────────────
@@ -59,12 +136,19 @@ func (s *Service) detectRisks(commits []Commit, contributors []Contributor, file
 	// 変更集中リスクの検出
 	risks = append(risks, s.detectChangeConcentration(commits)...)
 
-	// 属人化リスクの検出
-	risks = append(risks, s.detectOwnershipRisk(contributors)...)
+	// 属人化リスクの検出（期間内のコミットで見る設定なら、累計ではなく期間内の作成者で集計する）
+	if s.ownershipSource == OwnershipSourceWindow {
+		risks = append(risks, s.detectOwnershipRisk(windowContributors(commits))...)
+	} else {
+		risks = append(risks, s.detectOwnershipRisk(contributors)...)
+	}
 
 	// 深夜労働リスクの検出
 	risks = append(risks, s.detectLateNightRisk(commits)...)
 
+	// 週末労働リスクの検出
+	risks = append(risks, s.detectWeekendRisk(commits)...)
+
 	// 巨大ファイルリスクの検出
 	largeFileRisks, largeFiles := s.detectLargeFiles(files)
 	risks = append(risks, largeFileRisks...)
@@ -109,6 +193,7 @@ func (s *Service) detectChangeConcentration(commits []Commit) []domain.Risk {
 }
 
 // detectOwnershipRisk は属人化リスクを検出する。
+// contributors はコミット数の多い順に並んでいること（先頭をトップコントリビューターとみなす）。
 func (s *Service) detectOwnershipRisk(contributors []Contributor) []domain.Risk {
 	var risks []domain.Risk
 
@@ -131,11 +216,15 @@ func (s *Service) detectOwnershipRisk(contributors []Contributor) []domain.Risk
 	ratio := float64(topContributor.Contributions) / float64(totalCommits)
 
 	if ratio >= ownershipThreshold {
+		description := s.lang.T("1人のコントリビューターがコミットの大部分を占めています")
+		if s.ownershipSource == OwnershipSourceWindow {
+			description = s.lang.T("1人のコントリビューターが期間内のコミットの大部分を占めています")
+		}
 		risks = append(risks, domain.Risk{
 			Type:        domain.RiskTypeOwnership,
 			Severity:    domain.SeverityMedium,
 			Target:      topContributor.Login,
-			Description: "1人のコントリビューターがコミットの大部分を占めています",
+			Description: description,
 			Value:       int(ratio * 100),
 			Threshold:   int(ownershipThreshold * 100),
 		})
@@ -144,6 +233,85 @@ func (s *Service) detectOwnershipRisk(contributors []Contributor) []domain.Risk
 	return risks
 }
 
+// detectBusFactor は CODEOWNERS からバス係数リスクを検出する。
+//
+// トップレベルのディレクトリごとに CODEOWNERS の実効オーナーを集計し、
+// 1人（1チーム）だけがオーナーで副担当がいないディレクトリのうち、
+// リポジトリ全体に占める割合が大きいものを報告する。
+// CODEOWNERS がない場合は何も検出しない。
+func (s *Service) detectBusFactor(rules []codeownersRule, files []File) []domain.Risk {
+	var risks []domain.Risk
+	if len(rules) == 0 || len(files) == 0 {
+		return risks
+	}
+
+	type dirStats struct {
+		files      int
+		soleOwners map[string]int  // 単独オーナー → そのオーナーだけが持つファイル数
+		allOwners  map[string]bool // ディレクトリ内に登場する全オーナー
+	}
+	dirs := make(map[string]*dirStats)
+	var dirOrder []string
+
+	for _, f := range files {
+		dir, _, ok := strings.Cut(f.Path, "/")
+		if !ok {
+			continue // ルート直下のファイルはディレクトリとして扱わない
+		}
+		st, exists := dirs[dir]
+		if !exists {
+			st = &dirStats{soleOwners: make(map[string]int), allOwners: make(map[string]bool)}
+			dirs[dir] = st
+			dirOrder = append(dirOrder, dir)
+		}
+		st.files++
+
+		owners := ownersOf(rules, f.Path)
+		for _, o := range owners {
+			st.allOwners[o] = true
+		}
+		if len(owners) == 1 {
+			st.soleOwners[owners[0]]++
+		}
+	}
+
+	sort.Strings(dirOrder)
+	for _, dir := range dirOrder {
+		st := dirs[dir]
+		// 副担当がいる（オーナーが2人以上登場する）ディレクトリは対象外
+		if len(st.allOwners) != 1 {
+			continue
+		}
+		var owner string
+		for o := range st.allOwners {
+			owner = o
+		}
+		if float64(st.soleOwners[owner])/float64(st.files) < busFactorOwnerShare {
+			continue
+		}
+
+		treeShare := float64(st.files) / float64(len(files))
+		severity := domain.SeverityMedium
+		switch {
+		case treeShare >= busFactorTreeShareHigh:
+			severity = domain.SeverityHigh
+		case treeShare < busFactorTreeShareWarning:
+			continue
+		}
+
+		risks = append(risks, domain.Risk{
+			Type:        domain.RiskTypeBusFactor,
+			Severity:    severity,
+			Target:      fmt.Sprintf("%s/ (%s)", dir, owner),
+			Description: s.lang.T("%s だけがオーナーのディレクトリがリポジトリの%d%%を占めています", owner, int(treeShare*100)),
+			Value:       int(treeShare * 100),
+			Threshold:   int(busFactorTreeShareWarning * 100),
+		})
+	}
+
+	return risks
+}
+
 // detectLateNightRisk は深夜労働リスクを検出する。
 func (s *Service) detectLateNightRisk(commits []Commit) []domain.Risk {
 	var risks []domain.Risk
@@ -152,15 +320,15 @@ func (s *Service) detectLateNightRisk(commits []Commit) []domain.Risk {
 		return risks
 	}
 
-	lateNightCount := countLateNightCommits(commits)
+	lateNightCount := countLateNightCommits(commits, s.location)
 	ratio := float64(lateNightCount) / float64(len(commits))
 
 	if ratio >= lateNightRateThreshold {
 		risks = append(risks, domain.Risk{
 			Type:        domain.RiskTypeLateNight,
 			Severity:    domain.SeverityMedium,
-			Target:      "リポジトリ全体",
-			Description: "深夜のコミットが多いです",
+			Target:      s.lang.T("リポジトリ全体"),
+			Description: s.lang.T("深夜のコミットが多いです"),
 			Value:       int(ratio * 100),
 			Threshold:   int(lateNightRateThreshold * 100),
 		})
@@ -169,7 +337,31 @@ func (s *Service) detectLateNightRisk(commits []Commit) []domain.Risk {
 	return risks
 }
 
-// detectLargeFiles は巨大ファイルリスクを検出する。
+// detectWeekendRisk は週末労働リスクを検出する。
+func (s *Service) detectWeekendRisk(commits []Commit) []domain.Risk {
+	var risks []domain.Risk
+
+	if len(commits) == 0 {
+		return risks
+	}
+
+	ratio := float64(countWeekendCommits(commits, s.location)) / float64(len(commits))
+
+	if ratio >= weekendRateThreshold {
+		risks = append(risks, domain.Risk{
+			Type:        domain.RiskTypeWeekendWork,
+			Severity:    domain.SeverityMedium,
+			Target:      s.lang.T("リポジトリ全体"),
+			Description: s.lang.T("週末のコミットが多いです"),
+			Value:       int(ratio * 100),
+			Threshold:   int(weekendRateThreshold * 100),
+		})
+	}
+
+	return risks
+}
+
+// detectLargeFiles は巨大ファイルリスクを検出する（ソースコード以外のファイルは対象外）。
 // 集計されたリスク（重大度ごとに1件）と、詳細なファイル一覧を返す。
 func (s *Service) detectLargeFiles(files []File) ([]domain.Risk, []domain.LargeFile) {
 	var risks []domain.Risk
@@ -178,21 +370,24 @@ func (s *Service) detectLargeFiles(files []File) ([]domain.Risk, []domain.LargeF
 	var highCount, mediumCount int
 
 	for _, f := range files {
-		if f.Size >= largeFileCriticalBytes {
+		// 画像・ロックファイル・minify 済みのファイルなどは分割しても保守性が上がらないため数えない
+		if s.isNonSourceFile(f.Path) {
+			continue
+		}
+		severity, ok := largeFileSeverity(f.Size)
+		if !ok {
+			continue
+		}
+		if severity == domain.SeverityHigh {
 			highCount++
-			largeFiles = append(largeFiles, domain.LargeFile{
-				Path:     f.Path,
-				SizeKB:   f.Size / 1024,
-				Severity: domain.SeverityHigh,
-			})
-		} else if f.Size >= largeFileWarningBytes {
+		} else {
 			mediumCount++
-			largeFiles = append(largeFiles, domain.LargeFile{
-				Path:     f.Path,
-				SizeKB:   f.Size / 1024,
-				Severity: domain.SeverityMedium,
-			})
 		}
+		largeFiles = append(largeFiles, domain.LargeFile{
+			Path:     f.Path,
+			SizeKB:   f.Size / 1024,
+			Severity: severity,
+		})
 	}
 
 	// 集計されたリスクを作成
@@ -200,8 +395,8 @@ func (s *Service) detectLargeFiles(files []File) ([]domain.Risk, []domain.LargeF
 		risks = append(risks, domain.Risk{
 			Type:        domain.RiskTypeLargeFile,
 			Severity:    domain.SeverityHigh,
-			Target:      fmt.Sprintf("%d件", highCount),
-			Description: fmt.Sprintf("%dKB以上の巨大ファイルがあります", largeFileCriticalBytes/1024),
+			Target:      s.lang.T("%d件", highCount),
+			Description: s.lang.T("%dKB以上の巨大ファイルがあります", largeFileCriticalBytes/1024),
 			Value:       highCount,
 			Threshold:   largeFileCriticalBytes / 1024,
 		})
@@ -210,8 +405,8 @@ func (s *Service) detectLargeFiles(files []File) ([]domain.Risk, []domain.LargeF
 		risks = append(risks, domain.Risk{
 			Type:        domain.RiskTypeLargeFile,
 			Severity:    domain.SeverityMedium,
-			Target:      fmt.Sprintf("%d件", mediumCount),
-			Description: fmt.Sprintf("%dKB以上の大きいファイルがあります", largeFileWarningBytes/1024),
+			Target:      s.lang.T("%d件", mediumCount),
+			Description: s.lang.T("%dKB以上の大きいファイルがあります", largeFileWarningBytes/1024),
 			Value:       mediumCount,
 			Threshold:   largeFileWarningBytes / 1024,
 		})
@@ -234,7 +429,7 @@ func (s *Service) detectOutdatedDeps(dependencies []Dependency) ([]domain.Risk,
 			outdatedDeps = append(outdatedDeps, domain.OutdatedDep{
 				Name:     dep.Name,
 				Version:  dep.Version,
-				Age:      formatAge(dep.AgeMonths),
+				Age:      formatAge(dep.AgeMonths, s.lang),
 				Severity: domain.SeverityHigh,
 			})
 		} else if dep.AgeMonths >= outdatedDepWarningMonths {
@@ -242,7 +437,7 @@ func (s *Service) detectOutdatedDeps(dependencies []Dependency) ([]domain.Risk,
 			outdatedDeps = append(outdatedDeps, domain.OutdatedDep{
 				Name:     dep.Name,
 				Version:  dep.Version,
-				Age:      formatAge(dep.AgeMonths),
+				Age:      formatAge(dep.AgeMonths, s.lang),
 				Severity: domain.SeverityMedium,
 			})
 		}
@@ -253,8 +448,8 @@ func (s *Service) detectOutdatedDeps(dependencies []Dependency) ([]domain.Risk,
 		risks = append(risks, domain.Risk{
 			Type:        domain.RiskTypeOutdatedDeps,
 			Severity:    domain.SeverityHigh,
-			Target:      fmt.Sprintf("%d件", highCount),
-			Description: fmt.Sprintf("%d年以上前の古い依存があります", outdatedDepCriticalMonths/12),
+			Target:      s.lang.T("%d件", highCount),
+			Description: s.lang.T("%d年以上前の古い依存があります", outdatedDepCriticalMonths/12),
 			Value:       highCount,
 			Threshold:   outdatedDepCriticalMonths,
 		})
@@ -263,8 +458,8 @@ func (s *Service) detectOutdatedDeps(dependencies []Dependency) ([]domain.Risk,
 		risks = append(risks, domain.Risk{
 			Type:        domain.RiskTypeOutdatedDeps,
 			Severity:    domain.SeverityMedium,
-			Target:      fmt.Sprintf("%d件", mediumCount),
-			Description: fmt.Sprintf("%d年以上前の古い依存があります", outdatedDepWarningMonths/12),
+			Target:      s.lang.T("%d件", mediumCount),
+			Description: s.lang.T("%d年以上前の古い依存があります", outdatedDepWarningMonths/12),
 			Value:       mediumCount,
 			Threshold:   outdatedDepWarningMonths,
 		})
@@ -273,6 +468,179 @@ func (s *Service) detectOutdatedDeps(dependencies []Dependency) ([]domain.Risk,
 	return risks, outdatedDeps
 }
 
+// summarizeDependencies は依存をエコシステムごとに数え、古い依存（detectOutdatedDeps と同じ基準）の割合を返す。
+// 「古い依存5件」が全体の何件中なのかを示すために使う。エコシステムは依存の多い順（同数なら名前順）に並べる。
+func summarizeDependencies(dependencies []Dependency) (ecosystems []domain.DependencyEcosystem, outdatedRate float64) {
+	index := make(map[string]int)
+	outdated := 0
+	for _, dep := range dependencies {
+		i, ok := index[dep.PackageType]
+		if !ok {
+			i = len(ecosystems)
+			index[dep.PackageType] = i
+			ecosystems = append(ecosystems, domain.DependencyEcosystem{Ecosystem: dep.PackageType})
+		}
+		ecosystems[i].Count++
+		if dep.AgeMonths >= outdatedDepWarningMonths {
+			ecosystems[i].Outdated++
+			outdated++
+		}
+	}
+	sort.Slice(ecosystems, func(i, j int) bool {
+		if ecosystems[i].Count != ecosystems[j].Count {
+			return ecosystems[i].Count > ecosystems[j].Count
+		}
+		return ecosystems[i].Ecosystem < ecosystems[j].Ecosystem
+	})
+
+	if len(dependencies) > 0 {
+		outdatedRate = float64(outdated) / float64(len(dependencies)) * 100
+	}
+	return ecosystems, outdatedRate
+}
+
+// detectStalePRs は長期間オープンのままのPRが多すぎるリスクを検出する。
+// 経過日数は asOf（分析期間の終わり）時点の CreatedAt からの日数で判定する。
+func (s *Service) detectStalePRs(openPRs []PullRequest, asOf time.Time) []domain.Risk {
+	var risks []domain.Risk
+
+	days := s.stalePRAge()
+	cutoff := asOf.AddDate(0, 0, -days)
+	stale := 0
+	for _, pr := range openPRs {
+		if pr.CreatedAt.Before(cutoff) {
+			stale++
+		}
+	}
+	if stale <= stalePRCountWarning {
+		return risks
+	}
+
+	severity := domain.SeverityMedium
+	if stale > stalePRCountCritical {
+		severity = domain.SeverityHigh
+	}
+	risks = append(risks, domain.Risk{
+		Type:        domain.RiskTypeStalePR,
+		Severity:    severity,
+		Target:      s.lang.T("リポジトリ全体"),
+		Description: s.lang.T("%d日以上オープンのままのPRが%d件あります", days, stale),
+		Value:       stale,
+		Threshold:   stalePRCountWarning,
+	})
+
+	return risks
+}
+
+// mergePRMessagePattern はPR経由とみなすコミットメッセージ（1行目）。
+// GitHub のマージコミット（"Merge pull request #123 from ..."）と、
+// スカッシュマージの既定のタイトル（"Fix login (#123)"）に一致する。
+var mergePRMessagePattern = regexp.MustCompile(`^Merge pull request #\d+|\(#\d+\)$`)
+
+// detectDirectPushes はPRを経由せずデフォルトブランチに入ったコミットが多いリスクを検出する。
+//
+// 判定対象はデフォルトブランチの first-parent 履歴上のコミットのみ。
+// マージコミット方式のPRに含まれる個々のコミットは first-parent 履歴に乗らないため、
+// PRのマージコミット側で判定される。
+// マージ済みPRの MergeSHA / HeadSHA に一致するか、メッセージがPRのマージ・
+// スカッシュマージの形式ならPR経由とみなす（MergeSHA が取れない場合の代替）。
+func (s *Service) detectDirectPushes(commits []Commit, closedPRs []PullRequest) []domain.Risk {
+	var risks []domain.Risk
+
+	prSHAs := make(map[string]bool)
+	for _, pr := range closedPRs {
+		if pr.MergedAt == nil {
+			continue
+		}
+		if pr.MergeSHA != "" {
+			prSHAs[pr.MergeSHA] = true
+		}
+		if pr.HeadSHA != "" {
+			prSHAs[pr.HeadSHA] = true
+		}
+	}
+
+	mainline := firstParentHistory(commits)
+	if len(mainline) < directPushMinCommits {
+		return risks
+	}
+
+	direct := 0
+	for _, c := range mainline {
+		subject, _, _ := strings.Cut(c.Message, "\n")
+		if prSHAs[c.SHA] || mergePRMessagePattern.MatchString(strings.TrimSpace(subject)) {
+			continue
+		}
+		direct++
+	}
+
+	rate := float64(direct) / float64(len(mainline))
+	if rate <= directPushRateWarning {
+		return risks
+	}
+
+	severity := domain.SeverityMedium
+	if rate > directPushRateCritical {
+		severity = domain.SeverityHigh
+	}
+	risks = append(risks, domain.Risk{
+		Type:        domain.RiskTypeDirectPush,
+		Severity:    severity,
+		Target:      s.lang.T("デフォルトブランチ"),
+		Description: s.lang.T("PRを経由しないコミットが%d件中%d件あります", len(mainline), direct),
+		Value:       int(rate * 100),
+		Threshold:   int(directPushRateWarning * 100),
+	})
+
+	return risks
+}
+
+// firstParentHistory はコミット一覧からデフォルトブランチの first-parent 履歴を取り出す。
+//
+// 他のコミットの親になっていない最新のコミットを先頭とし、first parent を期間内で辿る。
+// 親の情報がない（取得元が対応していない）場合は全コミットを返す。
+func firstParentHistory(commits []Commit) []Commit {
+	bySHA := make(map[string]Commit, len(commits))
+	isParent := make(map[string]bool)
+	hasParents := false
+	for _, c := range commits {
+		bySHA[c.SHA] = c
+		for _, p := range c.Parents {
+			isParent[p] = true
+			hasParents = true
+		}
+	}
+	if !hasParents {
+		return commits
+	}
+
+	var head *Commit
+	for i, c := range commits {
+		if isParent[c.SHA] {
+			continue
+		}
+		if head == nil || c.Date.After(head.Date) {
+			head = &commits[i]
+		}
+	}
+	if head == nil {
+		return commits
+	}
+
+	var history []Commit
+	seen := make(map[string]bool)
+	c, ok := *head, true
+	for ok && !seen[c.SHA] {
+		seen[c.SHA] = true
+		history = append(history, c)
+		if len(c.Parents) == 0 {
+			break
+		}
+		c, ok = bySHA[c.Parents[0]]
+	}
+	return history
+}
+
 // ── メトリクスベースのリスク検出 ─────────────────────────────────
 
 // detectMetricRisks はメトリクス値に基づいてリスクを検出する。
@@ -284,8 +652,8 @@ func (s *Service) detectMetricRisks(metrics domain.Metrics) []domain.Risk {
 		risks = append(risks, domain.Risk{
 			Type:        domain.RiskTypeSlowLeadTime,
 			Severity:    domain.SeverityMedium,
-			Target:      "リポジトリ全体",
-			Description: fmt.Sprintf("PRリードタイムが平均%.1f日です", metrics.AvgLeadTime),
+			Target:      s.lang.T("リポジトリ全体"),
+			Description: s.lang.T("PRリードタイムが平均%.1f日です", metrics.AvgLeadTime),
 			Value:       int(metrics.AvgLeadTime * 10),
 			Threshold:   int(leadTimeThresholdDays),
 		})
@@ -296,8 +664,8 @@ func (s *Service) detectMetricRisks(metrics domain.Metrics) []domain.Risk {
 		risks = append(risks, domain.Risk{
 			Type:        domain.RiskTypeSlowReview,
 			Severity:    domain.SeverityMedium,
-			Target:      "リポジトリ全体",
-			Description: fmt.Sprintf("レビュー待ち時間が平均%.1f時間です", metrics.AvgReviewWaitTime),
+			Target:      s.lang.T("リポジトリ全体"),
+			Description: s.lang.T("レビュー待ち時間が平均%.1f時間です", metrics.AvgReviewWaitTime),
 			Value:       int(metrics.AvgReviewWaitTime * 10),
 			Threshold:   int(reviewWaitThresholdHours),
 		})
@@ -308,20 +676,102 @@ func (s *Service) detectMetricRisks(metrics domain.Metrics) []domain.Risk {
 		risks = append(risks, domain.Risk{
 			Type:        domain.RiskTypeLargePR,
 			Severity:    domain.SeverityMedium,
-			Target:      "リポジトリ全体",
-			Description: fmt.Sprintf("PRの平均サイズが%d行です", metrics.AvgPRSize),
+			Target:      s.lang.T("リポジトリ全体"),
+			Description: s.lang.T("PRの平均サイズが%d行です", metrics.AvgPRSize),
 			Value:       metrics.AvgPRSize,
 			Threshold:   prSizeThresholdLines,
 		})
 	}
 
+	// コミットの粒度（集計したコミットが少ないと平均がぶれるため最小件数を設ける）
+	if metrics.SizedCommitCount >= largeCommitMinCommits && metrics.AvgFilesPerCommit > largeCommitWarningFiles {
+		severity := domain.SeverityMedium
+		if metrics.AvgFilesPerCommit > largeCommitCriticalFiles {
+			severity = domain.SeverityHigh
+		}
+		risks = append(risks, domain.Risk{
+			Type:        domain.RiskTypeLargeCommits,
+			Severity:    severity,
+			Target:      s.lang.T("リポジトリ全体"),
+			Description: s.lang.T("1コミットあたり平均%.1fファイルを変更しています", metrics.AvgFilesPerCommit),
+			Value:       int(metrics.AvgFilesPerCommit * 10),
+			Threshold:   int(largeCommitWarningFiles * 10),
+		})
+	}
+
+	// レビューカバレッジ（集計対象のPRが少ないと割合がぶれるため最小件数を設ける）
+	if metrics.ReviewCoveragePRs >= reviewCoverageMinPRs && metrics.ReviewCoverageRate < reviewCoverageWarningPct {
+		severity := domain.SeverityMedium
+		if metrics.ReviewCoverageRate < reviewCoverageCriticalPct {
+			severity = domain.SeverityHigh
+		}
+		risks = append(risks, domain.Risk{
+			Type:        domain.RiskTypeLowReviewCoverage,
+			Severity:    severity,
+			Target:      s.lang.T("リポジトリ全体"),
+			Description: s.lang.T("レビュー済みでマージされたPRが%.1f%%です", metrics.ReviewCoverageRate),
+			Value:       int(metrics.ReviewCoverageRate),
+			Threshold:   int(reviewCoverageWarningPct),
+		})
+	}
+
+	// セルフマージ
+	if metrics.ReviewCoveragePRs >= reviewCoverageMinPRs && metrics.SelfMergeRate > selfMergeRateWarningPct {
+		severity := domain.SeverityMedium
+		if metrics.SelfMergeRate > selfMergeRateCriticalPct {
+			severity = domain.SeverityHigh
+		}
+		risks = append(risks, domain.Risk{
+			Type:     domain.RiskTypeSelfMerge,
+			Severity: severity,
+			Target:   s.lang.T("リポジトリ全体"),
+			Description: s.lang.T("作成者以外の承認なしでマージされたPRが%d件（%.1f%%）です",
+				metrics.SelfMergeCount, metrics.SelfMergeRate),
+			Value:     int(metrics.SelfMergeRate),
+			Threshold: int(selfMergeRateWarningPct),
+		})
+	}
+
+	// コミットメッセージの品質（コミットが少ないと割合がぶれるため最小件数を設ける）
+	if metrics.TotalCommits >= lowCommitQualityMinCommits && metrics.LowQualityCommitRate > lowCommitQualityWarningPct {
+		severity := domain.SeverityMedium
+		if metrics.LowQualityCommitRate > lowCommitQualityCriticalPct {
+			severity = domain.SeverityHigh
+		}
+		risks = append(risks, domain.Risk{
+			Type:     domain.RiskTypeLowCommitQuality,
+			Severity: severity,
+			Target:   s.lang.T("リポジトリ全体"),
+			Description: s.lang.T("変更内容を説明していないコミットメッセージが%d件（%.1f%%）あります",
+				metrics.LowQualityCommitCount, metrics.LowQualityCommitRate),
+			Value:     int(metrics.LowQualityCommitRate),
+			Threshold: int(lowCommitQualityWarningPct),
+		})
+	}
+
+	// 直後の Revert（入れた直後に取り消す変更は、レビューやテストをすり抜けた失敗に近い）
+	if metrics.FastRevertCount >= fastRevertCountWarning {
+		severity := domain.SeverityMedium
+		if metrics.FastRevertCount >= fastRevertCountCritical {
+			severity = domain.SeverityHigh
+		}
+		risks = append(risks, domain.Risk{
+			Type:        domain.RiskTypeFastRevert,
+			Severity:    severity,
+			Target:      s.lang.T("リポジトリ全体"),
+			Description: s.lang.T("入れてから24時間以内に Revert された変更が%d件あります", metrics.FastRevertCount),
+			Value:       metrics.FastRevertCount,
+			Threshold:   fastRevertCountWarning,
+		})
+	}
+
 	// Issueクローズ率（Issue作成がある場合のみ）
 	if metrics.IssuesCreated > 0 && metrics.IssueCloseRate < issueCloseRateThresholdPct {
 		risks = append(risks, domain.Risk{
 			Type:        domain.RiskTypeLowIssueClose,
 			Severity:    domain.SeverityMedium,
-			Target:      "リポジトリ全体",
-			Description: fmt.Sprintf("Issueクローズ率が%.1f%%です", metrics.IssueCloseRate),
+			Target:      s.lang.T("リポジトリ全体"),
+			Description: s.lang.T("Issueクローズ率が%.1f%%です", metrics.IssueCloseRate),
 			Value:       int(metrics.IssueCloseRate),
 			Threshold:   int(issueCloseRateThresholdPct),
 		})
@@ -332,20 +782,21 @@ func (s *Service) detectMetricRisks(metrics domain.Metrics) []domain.Risk {
 		risks = append(risks, domain.Risk{
 			Type:        domain.RiskTypeBugFixHigh,
 			Severity:    domain.SeverityMedium,
-			Target:      "リポジトリ全体",
-			Description: fmt.Sprintf("バグ修正PRの割合が%.1f%%です", metrics.BugFixRatio),
+			Target:      s.lang.T("リポジトリ全体"),
+			Description: s.lang.T("バグ修正PRの割合が%.1f%%です", metrics.BugFixRatio),
 			Value:       int(metrics.BugFixRatio),
 			Threshold:   int(bugFixRatioThresholdPct),
 		})
 	}
 
-	// DORA: デプロイ頻度
-	if metrics.DeployFrequency > 0 && metrics.DeployFrequency < deployFreqThresholdPerMonth {
+	// DORA: デプロイ頻度（期間が短すぎて評価しないときは検出しない）
+	if metrics.DeployFrequency > 0 && metrics.DeployFrequency < deployFreqThresholdPerMonth &&
+		metrics.DeployFreqRating != domain.DeployFreqRatingWindowTooShort {
 		risks = append(risks, domain.Risk{
 			Type:        domain.RiskTypeLowDeployFreq,
 			Severity:    domain.SeverityMedium,
-			Target:      "リポジトリ全体",
-			Description: fmt.Sprintf("デプロイ頻度が月%.1f回です", metrics.DeployFrequency),
+			Target:      s.lang.T("リポジトリ全体"),
+			Description: s.lang.T("デプロイ頻度が月%.1f回です", metrics.DeployFrequency),
 			Value:       int(metrics.DeployFrequency * 10),
 			Threshold:   int(deployFreqThresholdPerMonth * 10),
 		})
@@ -356,8 +807,8 @@ func (s *Service) detectMetricRisks(metrics domain.Metrics) []domain.Risk {
 		risks = append(risks, domain.Risk{
 			Type:        domain.RiskTypeHighChangeFailure,
 			Severity:    domain.SeverityHigh,
-			Target:      "リポジトリ全体",
-			Description: fmt.Sprintf("変更失敗率が%.1f%%です", metrics.ChangeFailureRate),
+			Target:      s.lang.T("リポジトリ全体"),
+			Description: s.lang.T("変更失敗率が%.1f%%です", metrics.ChangeFailureRate),
 			Value:       int(metrics.ChangeFailureRate),
 			Threshold:   int(changeFailureThresholdPct),
 		})
@@ -368,21 +819,59 @@ func (s *Service) detectMetricRisks(metrics domain.Metrics) []domain.Risk {
 		risks = append(risks, domain.Risk{
 			Type:        domain.RiskTypeSlowRecovery,
 			Severity:    domain.SeverityMedium,
-			Target:      "リポジトリ全体",
-			Description: fmt.Sprintf("平均復旧時間が%.1f時間です", metrics.MTTR),
+			Target:      s.lang.T("リポジトリ全体"),
+			Description: s.lang.T("平均復旧時間が%.1f時間です", metrics.MTTR),
 			Value:       int(metrics.MTTR * 10),
 			Threshold:   int(mttrThresholdHours * 10),
 		})
 	}
 
+	// リリース負債
+	// 日数だけでは変更の少ない安定したリポジトリを、コミット数だけではリリース直後の
+	// 活発な開発を拾ってしまうため、両方が閾値を超えたときだけ検出する
+	if !metrics.LastReleaseAt.IsZero() &&
+		metrics.DaysSinceLastRelease > releaseDebtWarningDays && metrics.CommitsSinceLastRelease > releaseDebtWarningCommits {
+		severity := domain.SeverityMedium
+		if metrics.DaysSinceLastRelease > releaseDebtCriticalDays && metrics.CommitsSinceLastRelease > releaseDebtCriticalCommits {
+			severity = domain.SeverityHigh
+		}
+		risks = append(risks, domain.Risk{
+			Type:     domain.RiskTypeReleaseDebt,
+			Severity: severity,
+			Target:   s.lang.T("リポジトリ全体"),
+			Description: s.lang.T("最後のリリース（%s）から%d日間、%dコミットが未リリースです",
+				metrics.LastReleaseName, metrics.DaysSinceLastRelease, metrics.CommitsSinceLastRelease),
+			Value:     metrics.CommitsSinceLastRelease,
+			Threshold: releaseDebtWarningCommits,
+		})
+	}
+
+	// 放棄PR
+	closedPRs := metrics.FeaturePRCount + metrics.BugFixPRCount + metrics.RefactorPRCount + metrics.OtherPRCount + metrics.AbandonedPRCount
+	if closedPRs >= prAbandonmentMinPRs && metrics.AbandonmentRate > prAbandonmentWarningPct {
+		severity := domain.SeverityMedium
+		if metrics.AbandonmentRate > prAbandonmentCriticalPct {
+			severity = domain.SeverityHigh
+		}
+		risks = append(risks, domain.Risk{
+			Type:     domain.RiskTypeHighPRAbandonment,
+			Severity: severity,
+			Target:   s.lang.T("リポジトリ全体"),
+			Description: s.lang.T("マージされずにクローズされたPRが%d件（%.1f%%）です",
+				metrics.AbandonedPRCount, metrics.AbandonmentRate),
+			Value:     int(metrics.AbandonmentRate),
+			Threshold: int(prAbandonmentWarningPct),
+		})
+	}
+
 	// 機能投資比率
 	totalPRs := metrics.FeaturePRCount + metrics.BugFixPRCount + metrics.RefactorPRCount + metrics.OtherPRCount
 	if totalPRs > 0 && metrics.FeatureRatio < featureInvestmentThresholdPct {
 		risks = append(risks, domain.Risk{
 			Type:        domain.RiskTypeLowFeatureInvestment,
 			Severity:    domain.SeverityMedium,
-			Target:      "リポジトリ全体",
-			Description: fmt.Sprintf("機能追加PRの割合が%.1f%%です", metrics.FeatureRatio),
+			Target:      s.lang.T("リポジトリ全体"),
+			Description: s.lang.T("機能追加PRの割合が%.1f%%です", metrics.FeatureRatio),
 			Value:       int(metrics.FeatureRatio),
 			Threshold:   int(featureInvestmentThresholdPct),
 		})
@@ -393,21 +882,22 @@ func (s *Service) detectMetricRisks(metrics domain.Metrics) []domain.Risk {
 
 // ── スコア計算・診断テキスト ─────────────────────────────────────
 
+// scoreCategories はスコアを算出するカテゴリ。
+var scoreCategories = []domain.Category{
+	domain.CategoryVelocity,
+	domain.CategoryQuality,
+	domain.CategoryTechDebt,
+	domain.CategoryHealth,
+}
+
 // calculateCategoryScores はカテゴリ別スコアを計算する。
 func (s *Service) calculateCategoryScores(risks []domain.Risk) map[domain.Category]domain.CategoryScore {
-	categories := []domain.Category{
-		domain.CategoryVelocity,
-		domain.CategoryQuality,
-		domain.CategoryTechDebt,
-		domain.CategoryHealth,
-	}
-
-	scores := make(map[domain.Category]domain.CategoryScore, len(categories))
+	scores := make(map[domain.Category]domain.CategoryScore, len(scoreCategories))
 
-	for _, cat := range categories {
+	for _, cat := range scoreCategories {
 		score := baseScore
 		breakdown := []domain.ScoreBreakdownItem{
-			{Label: "基本スコア", Points: baseScore},
+			{Label: s.lang.T("基本スコア"), Points: baseScore},
 		}
 
 		// カテゴリに属するリスクのみで減点
@@ -417,20 +907,12 @@ func (s *Service) calculateCategoryScores(risks []domain.Risk) map[domain.Catego
 			if r.Type.Category() != cat {
 				continue
 			}
-			var points int
-			switch r.Severity {
-			case domain.SeverityHigh:
-				points = penaltyHigh
-			case domain.SeverityMedium:
-				points = penaltyMedium
-			case domain.SeverityLow:
-				points = penaltyLow
-			}
+			points := s.severityPenalties().points(r.Severity)
 			score += points
 			breakdown = append(breakdown, domain.ScoreBreakdownItem{
-				Label:  r.Type.DisplayName(),
+				Label:  s.lang.T(r.Type.DisplayName()),
 				Points: points,
-				Detail: formatRiskDetail(r),
+				Detail: formatRiskDetail(r, s.lang),
 			})
 			if points < worstPoints {
 				worstPoints = points
@@ -439,7 +921,7 @@ func (s *Service) calculateCategoryScores(risks []domain.Risk) map[domain.Catego
 			}
 		}
 
-		diagnosis := generateDiagnosis(cat, domain.NewScore(score), worstRisk)
+		diagnosis := s.lang.T(generateDiagnosis(cat, domain.NewScore(score), worstRisk))
 
 		scores[cat] = domain.CategoryScore{
 			Category:  cat,
@@ -451,16 +933,95 @@ func (s *Service) calculateCategoryScores(risks []domain.Risk) map[domain.Catego
 	return scores
 }
 
-// calculateOverallScore はカテゴリ別スコアの平均から総合スコアを計算する。
-func calculateOverallScore(categoryScores map[domain.Category]domain.CategoryScore) domain.Score {
-	if len(categoryScores) == 0 {
+// SeverityPenalties はカテゴリスコアでリスク1件ごとに引く点数（重大度別、0 以上）。
+type SeverityPenalties struct {
+	High   int
+	Medium int
+	Low    int
+}
+
+// DefaultSeverityPenalties は重大度別の減点のデフォルト。
+var DefaultSeverityPenalties = SeverityPenalties{High: 15, Medium: 10, Low: 5}
+
+// Validate は減点が 0 以上カテゴリスコアの初期値以下かを検証する。
+func (p SeverityPenalties) Validate() error {
+	for _, v := range []struct {
+		name   string
+		points int
+	}{{"high", p.High}, {"medium", p.Medium}, {"low", p.Low}} {
+		if v.points < 0 || v.points > baseScore {
+			return fmt.Errorf("penalty for %s must be between 0 and %d: %d", v.name, baseScore, v.points)
+		}
+	}
+	return nil
+}
+
+// points は重大度に応じた内訳の点数（減点なので 0 以下）を返す。
+func (p SeverityPenalties) points(severity domain.Severity) int {
+	switch severity {
+	case domain.SeverityHigh:
+		return -p.High
+	case domain.SeverityMedium:
+		return -p.Medium
+	case domain.SeverityLow:
+		return -p.Low
+	}
+	return 0
+}
+
+// calculateOverallScore はカテゴリ別スコアの加重平均から総合スコアを計算する。
+// weights が nil なら均等（単純平均）。重みの合計が 1 でなくても比率として扱う。
+func calculateOverallScore(categoryScores map[domain.Category]domain.CategoryScore, weights map[domain.Category]float64) domain.Score {
+	var total, weightSum float64
+	for cat, cs := range categoryScores {
+		w := 1.0
+		if weights != nil {
+			w = weights[cat]
+		}
+		total += w * float64(cs.Score.Value)
+		weightSum += w
+	}
+	if weightSum <= 0 {
 		return domain.NewScore(0)
 	}
-	total := 0
-	for _, cs := range categoryScores {
-		total += cs.Score.Value
+	// 均等な重みで従来の整数除算（切り捨て）と同じ値になるよう、誤差分を足してから切り捨てる
+	return domain.NewScore(int(total/weightSum + 1e-9))
+}
+
+// NormalizeCategoryWeights は総合スコアのカテゴリ別の重みを検証し、合計が 1 になるよう正規化する。
+// 指定のないカテゴリの重みは 1（他と均等）とする。
+// 未知のカテゴリ・負の重み・合計が 0 の場合はエラーを返す。
+func NormalizeCategoryWeights(weights map[domain.Category]float64) (map[domain.Category]float64, error) {
+	known := make(map[domain.Category]bool, len(scoreCategories))
+	for _, cat := range scoreCategories {
+		known[cat] = true
+	}
+	for cat, w := range weights {
+		if !known[cat] {
+			return nil, fmt.Errorf("unknown category %q", cat)
+		}
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, fmt.Errorf("weight for %q must be a non-negative number: %v", cat, w)
+		}
+	}
+
+	normalized := make(map[domain.Category]float64, len(scoreCategories))
+	var sum float64
+	for _, cat := range scoreCategories {
+		w, ok := weights[cat]
+		if !ok {
+			w = 1
+		}
+		normalized[cat] = w
+		sum += w
+	}
+	if sum <= 0 {
+		return nil, errors.New("category weights must sum to a positive number")
 	}
-	return domain.NewScore(total / len(categoryScores))
+	for cat := range normalized {
+		normalized[cat] /= sum
+	}
+	return normalized, nil
 }
 
 // generateDiagnosis はカテゴリスコアに応じた一行診断テキストを生成する。
@@ -478,10 +1039,26 @@ func generateDiagnosis(cat domain.Category, score domain.Score, worstRisk *domai
 		return "PRリードタイムが長く、開発速度が低下しています"
 	case domain.RiskTypeSlowReview:
 		return "レビュー待ち時間が長く、フィードバックが遅延しています"
+	case domain.RiskTypeStalePR:
+		return "長期間オープンのままのPRが滞留し、作業が止まっています"
+	case domain.RiskTypeHighPRAbandonment:
+		return "マージされずに閉じられるPRが多く、作業が無駄になっています"
 	case domain.RiskTypeChangeConcentration:
 		return "特定ファイルへの変更が集中しており、品質リスクがあります"
 	case domain.RiskTypeLargePR:
 		return "PRサイズが大きく、レビューの質が低下する可能性があります"
+	case domain.RiskTypeDirectPush:
+		return "PRを経由しない変更が多く、レビューされずに取り込まれています"
+	case domain.RiskTypeLowReviewCoverage:
+		return "レビューを受けずにマージされるPRが多く、品質チェックが抜けています"
+	case domain.RiskTypeSelfMerge:
+		return "作成者以外の承認なしにマージされるPRが多く、統制が効いていません"
+	case domain.RiskTypeLargeCommits:
+		return "1つのコミットで多くのファイルを変更しており、レビューや不具合の原因の切り分けが難しくなっています"
+	case domain.RiskTypeLowTestCoverage:
+		return "ソースコードに対してテストが少なく、変更による不具合を検出しにくい状態です"
+	case domain.RiskTypeLowCommitQuality:
+		return "コミットメッセージから変更の意図が読み取れず、履歴を追いにくくなっています"
 	case domain.RiskTypeLowIssueClose:
 		return "Issueの消化が追いつかず、負債が蓄積しています"
 	case domain.RiskTypeBugFixHigh:
@@ -490,60 +1067,151 @@ func generateDiagnosis(cat domain.Category, score domain.Score, worstRisk *domai
 		return "巨大ファイルが多数あり、保守性に課題があります"
 	case domain.RiskTypeOutdatedDeps:
 		return "古い依存パッケージがあり、セキュリティリスクがあります"
+	case domain.RiskTypeVulnerableDependency:
+		return "既知の脆弱性がある依存パッケージを使っています"
 	case domain.RiskTypeLateNight:
 		return "深夜作業が多く、チームの持続可能性に懸念があります"
+	case domain.RiskTypeWeekendWork:
+		return "週末の作業が常態化しており、十分に休めていない可能性があります"
 	case domain.RiskTypeOwnership:
 		return "知識が特定の人に偏っており、属人化リスクがあります"
+	case domain.RiskTypeBusFactor:
+		return "副担当のいないディレクトリが大きく、担当者の不在に弱い状態です"
+	case domain.RiskTypeFileOwnership:
+		return "1人しか変更していないファイルがあり、知識がサイロ化しています"
 	case domain.RiskTypeLowDeployFreq:
 		return "デプロイ頻度が低く、価値提供のスピードが遅れています"
 	case domain.RiskTypeHighChangeFailure:
 		return "変更失敗率が高く、リリース品質に課題があります"
 	case domain.RiskTypeSlowRecovery:
 		return "障害からの復旧時間が長く、運用に課題があります"
+	case domain.RiskTypeReleaseDebt:
+		return "マージした変更がリリースされないまま溜まっています"
 	case domain.RiskTypeLowFeatureInvestment:
 		return "機能追加への投資比率が低く、負債対応に追われています"
+	case domain.RiskTypeMissingLicense:
+		return "ライセンスが明示されておらず、利用・配布の条件が不明確です"
+	case domain.RiskTypeHighTodoDensity:
+		return "TODO コメントが多く、先送りした作業が溜まっています"
+	case domain.RiskTypeMissingCommunityFiles:
+		return "貢献方法や脆弱性の報告窓口が示されておらず、外部から参加しにくい状態です"
+	case domain.RiskTypeNoBranchProtection:
+		return "デフォルトブランチにレビューを経ない変更が入り得る状態です"
+	case domain.RiskTypeHistoryRewrite:
+		return "共有ブランチの履歴が書き換えられ、各自の作業と食い違いやすい状態です"
+	case domain.RiskTypeFastRevert:
+		return "入れた直後に取り消される変更があり、レビューやテストで問題を防げていません"
+	case domain.RiskTypeInactiveRepo:
+		return "活動が止まっており、メンテナンスされていない可能性があります"
 	default:
 		return "改善の余地があります"
 	}
 }
 
 // formatRiskDetail はリスクの詳細を文字列にフォーマットする。
-func formatRiskDetail(r domain.Risk) string {
+func formatRiskDetail(r domain.Risk, lang i18n.Lang) string {
 	if r.Value == 0 && r.Threshold == 0 {
 		return ""
 	}
 
 	switch r.Type {
 	case domain.RiskTypeLateNight:
-		return fmt.Sprintf("22-5時のコミットが%d%%、基準%d%%以下", r.Value, r.Threshold)
+		return lang.T("22-5時のコミットが%d%%、基準%d%%以下", r.Value, r.Threshold)
+	case domain.RiskTypeWeekendWork:
+		return lang.T("土日のコミットが%d%%、基準%d%%未満", r.Value, r.Threshold)
 	case domain.RiskTypeOwnership:
-		return fmt.Sprintf("1人で%d%%のコミット、基準%d%%以下", r.Value, r.Threshold)
+		return lang.T("1人で%d%%のコミット、基準%d%%以下", r.Value, r.Threshold)
+	case domain.RiskTypeBusFactor:
+		return lang.T("単独オーナーの範囲がリポジトリの%d%%、基準%d%%未満", r.Value, r.Threshold)
+	case domain.RiskTypeFileOwnership:
+		return lang.T("%d件、1人で変更の%d%%以上", r.Value, r.Threshold)
+	case domain.RiskTypeMissingCommunityFiles:
+		return lang.T("%d種類が不足、全%d種類", r.Value, r.Threshold)
 	case domain.RiskTypeChangeConcentration:
-		return fmt.Sprintf("%d回変更、基準%d回以下", r.Value, r.Threshold)
+		return lang.T("%d回変更、基準%d回以下", r.Value, r.Threshold)
 	case domain.RiskTypeLargeFile:
-		return fmt.Sprintf("%d件、%dKB以上", r.Value, r.Threshold)
+		return lang.T("%d件、%dKB以上", r.Value, r.Threshold)
 	case domain.RiskTypeOutdatedDeps:
 		years := r.Threshold / 12
-		return fmt.Sprintf("%d件、%d年以上前", r.Value, years)
+		return lang.T("%d件、%d年以上前", r.Value, years)
+	case domain.RiskTypeVulnerableDependency:
+		return lang.T("%d件、既知の脆弱性あり", r.Value)
+	case domain.RiskTypeHistoryRewrite:
+		return lang.T("force push %d回", r.Value)
+	case domain.RiskTypeFastRevert:
+		return lang.T("24時間以内のRevert %d件", r.Value)
+	case domain.RiskTypeInactiveRepo:
+		return lang.T("最終活動から%d日、期間%d日", r.Value, r.Threshold)
+	case domain.RiskTypeHighTodoDensity:
+		return lang.T("1000行あたり%.1f件、基準%d件以下", float64(r.Value)/10, r.Threshold)
 	case domain.RiskTypeSlowLeadTime:
-		return fmt.Sprintf("平均%.1f日、基準%d日以下", float64(r.Value)/10, r.Threshold)
+		return lang.T("平均%.1f日、基準%d日以下", float64(r.Value)/10, r.Threshold)
 	case domain.RiskTypeSlowReview:
-		return fmt.Sprintf("平均%.1f時間、基準%d時間以下", float64(r.Value)/10, r.Threshold)
+		return lang.T("平均%.1f時間、基準%d時間以下", float64(r.Value)/10, r.Threshold)
+	case domain.RiskTypeStalePR:
+		return lang.T("滞留PR%d件、基準%d件以下", r.Value, r.Threshold)
+	case domain.RiskTypeHighPRAbandonment:
+		return lang.T("放棄%d%%、基準%d%%以下", r.Value, r.Threshold)
 	case domain.RiskTypeLargePR:
-		return fmt.Sprintf("平均%d行、基準%d行以下", r.Value, r.Threshold)
+		return lang.T("平均%d行、基準%d行以下", r.Value, r.Threshold)
+	case domain.RiskTypeDirectPush:
+		return lang.T("PR未経由%d%%、基準%d%%以下", r.Value, r.Threshold)
+	case domain.RiskTypeLowReviewCoverage:
+		return lang.T("レビュー済み%d%%、基準%d%%以上", r.Value, r.Threshold)
+	case domain.RiskTypeSelfMerge:
+		return lang.T("セルフマージ%d%%、基準%d%%以下", r.Value, r.Threshold)
+	case domain.RiskTypeLargeCommits:
+		return lang.T("平均%.1fファイル/コミット、基準%.1fファイル以下", float64(r.Value)/10, float64(r.Threshold)/10)
+	case domain.RiskTypeLowTestCoverage:
+		return lang.T("ソース1件あたりテスト%.2f件、基準%.2f件以上", float64(r.Value)/100, float64(r.Threshold)/100)
+	case domain.RiskTypeLowCommitQuality:
+		return lang.T("低品質メッセージ%d%%、基準%d%%以下", r.Value, r.Threshold)
 	case domain.RiskTypeLowIssueClose:
-		return fmt.Sprintf("クローズ率%d%%、基準%d%%以上", r.Value, r.Threshold)
+		return lang.T("クローズ率%d%%、基準%d%%以上", r.Value, r.Threshold)
 	case domain.RiskTypeBugFixHigh:
-		return fmt.Sprintf("バグ修正%d%%、基準%d%%以下", r.Value, r.Threshold)
+		return lang.T("バグ修正%d%%、基準%d%%以下", r.Value, r.Threshold)
 	case domain.RiskTypeLowDeployFreq:
-		return fmt.Sprintf("月%.1f回、基準月%.1f回以上", float64(r.Value)/10, float64(r.Threshold)/10)
+		return lang.T("月%.1f回、基準月%.1f回以上", float64(r.Value)/10, float64(r.Threshold)/10)
 	case domain.RiskTypeHighChangeFailure:
-		return fmt.Sprintf("失敗率%d%%、基準%d%%以下", r.Value, r.Threshold)
+		return lang.T("失敗率%d%%、基準%d%%以下", r.Value, r.Threshold)
 	case domain.RiskTypeSlowRecovery:
-		return fmt.Sprintf("平均%.1f時間、基準%.1f時間以下", float64(r.Value)/10, float64(r.Threshold)/10)
+		return lang.T("平均%.1f時間、基準%.1f時間以下", float64(r.Value)/10, float64(r.Threshold)/10)
 	case domain.RiskTypeLowFeatureInvestment:
-		return fmt.Sprintf("機能追加%d%%、基準%d%%以上", r.Value, r.Threshold)
+		return lang.T("機能追加%d%%、基準%d%%以上", r.Value, r.Threshold)
+	case domain.RiskTypeReleaseDebt:
+		return lang.T("未リリース%dコミット、基準%dコミット以下", r.Value, r.Threshold)
 	default:
-		return fmt.Sprintf("%d / 基準%d", r.Value, r.Threshold)
+		return lang.T("%d / 基準%d", r.Value, r.Threshold)
+	}
+}
+
+// topLargeFiles は巨大ファイルをサイズの大きい順に並べ、上位 n 件を返す。
+func topLargeFiles(files []domain.LargeFile, n int) []domain.LargeFile {
+	sorted := make([]domain.LargeFile, len(files))
+	copy(sorted, files)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].SizeKB != sorted[j].SizeKB {
+			return sorted[i].SizeKB > sorted[j].SizeKB
+		}
+		return sorted[i].Path < sorted[j].Path
+	})
+	return sorted[:min(len(sorted), n)]
+}
+
+// topHotFiles は変更集中リスクからファイル一覧を作り、変更回数の多い順に上位 n 件を返す。
+func topHotFiles(risks []domain.Risk, n int) []domain.HotFile {
+	var hotFiles []domain.HotFile
+	for _, r := range risks {
+		if r.Type != domain.RiskTypeChangeConcentration {
+			continue
+		}
+		hotFiles = append(hotFiles, domain.HotFile{Path: r.Target, Changes: r.Value, Severity: r.Severity})
 	}
+	sort.Slice(hotFiles, func(i, j int) bool {
+		if hotFiles[i].Changes != hotFiles[j].Changes {
+			return hotFiles[i].Changes > hotFiles[j].Changes
+		}
+		return hotFiles[i].Path < hotFiles[j].Path
+	})
+	return hotFiles[:min(len(hotFiles), n)]
 }
@@ -1,10 +1,14 @@
 package analyze
 
 import (
+	"fmt"
+	"math"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/shared/i18n"
 )
 
 func TestDetectChangeConcentration(t *testing.T) {
@@ -100,6 +104,237 @@ func TestDetectOwnershipRisk(t *testing.T) {
 	}
 }
 
+func TestDetectBusFactor(t *testing.T) {
+	s := &Service{}
+	rules := parseCodeowners(`
+*        @org/core
+/core/   @alice
+/api/    @bob @carol
+/web/    @dave
+`)
+
+	var files []File
+	addFiles := func(dir string, n int) {
+		for i := range n {
+			files = append(files, File{Path: fmt.Sprintf("%s/f%d.go", dir, i)})
+		}
+	}
+	addFiles("core", 40) // 40% を @alice のみ → High
+	addFiles("api", 30)  // 副担当あり → 対象外
+	addFiles("web", 15)  // 15% を @dave のみ → Medium
+	addFiles("docs", 5)  // 5% → 基準未満
+	addFiles("misc", 10) // @org/core のみ 10% → Medium
+
+	risks := s.detectBusFactor(rules, files)
+
+	want := map[string]domain.Severity{
+		"core/ (@alice)":    domain.SeverityHigh,
+		"misc/ (@org/core)": domain.SeverityMedium,
+		"web/ (@dave)":      domain.SeverityMedium,
+	}
+	if len(risks) != len(want) {
+		t.Fatalf("risks = %+v, want %d", risks, len(want))
+	}
+	for _, r := range risks {
+		if r.Type != domain.RiskTypeBusFactor {
+			t.Errorf("unexpected risk type: %v", r.Type)
+		}
+		sev, ok := want[r.Target]
+		if !ok {
+			t.Errorf("unexpected target: %q", r.Target)
+			continue
+		}
+		if r.Severity != sev {
+			t.Errorf("%s: Severity = %v, want %v", r.Target, r.Severity, sev)
+		}
+	}
+}
+
+func TestDetectBusFactor_noCodeowners(t *testing.T) {
+	s := &Service{}
+	files := []File{{Path: "src/a.go"}, {Path: "src/b.go"}}
+	if risks := s.detectBusFactor(nil, files); len(risks) != 0 {
+		t.Errorf("expected no risks, got %d", len(risks))
+	}
+}
+
+func TestFirstParentHistory(t *testing.T) {
+	base := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	// main: m1 ← m2 ← merge(m2, f2)、PRブランチ: m1 ← f1 ← f2
+	commits := []Commit{
+		{SHA: "merge", Parents: []string{"m2", "f2"}, Date: base.Add(5 * time.Hour)},
+		{SHA: "f2", Parents: []string{"f1"}, Date: base.Add(4 * time.Hour)},
+		{SHA: "m2", Parents: []string{"m1"}, Date: base.Add(3 * time.Hour)},
+		{SHA: "f1", Parents: []string{"m1"}, Date: base.Add(2 * time.Hour)},
+		{SHA: "m1", Parents: []string{"m0"}, Date: base.Add(1 * time.Hour)},
+	}
+
+	var got []string
+	for _, c := range firstParentHistory(commits) {
+		got = append(got, c.SHA)
+	}
+	want := []string{"merge", "m2", "m1"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("firstParentHistory() = %v, want %v", got, want)
+	}
+
+	// 親の情報がなければ全コミット
+	noParents := []Commit{{SHA: "a"}, {SHA: "b"}}
+	if got := firstParentHistory(noParents); len(got) != 2 {
+		t.Errorf("without parents = %d commits, want 2", len(got))
+	}
+}
+
+func TestDetectDirectPushes(t *testing.T) {
+	base := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	merged := base.Add(time.Hour)
+
+	// linear は direct 件の直接プッシュと viaPR 件のPR経由コミットを一直線に並べる（新しい順）。
+	// PR経由のコミットは半分をマージSHA、半分をメッセージで判定させる。
+	linear := func(direct, viaPR int) ([]Commit, []PullRequest) {
+		var commits []Commit
+		var prs []PullRequest
+		n := direct + viaPR
+		for i := range n {
+			c := Commit{
+				SHA:     fmt.Sprintf("c%d", i),
+				Parents: []string{fmt.Sprintf("c%d", i+1)},
+				Date:    base.Add(-time.Duration(i) * time.Hour),
+				Message: "fix typo",
+			}
+			if i < viaPR {
+				if i%2 == 0 {
+					prs = append(prs, PullRequest{Number: i, MergedAt: &merged, MergeSHA: c.SHA})
+				} else {
+					c.Message = fmt.Sprintf("Merge pull request #%d from org/branch\n\nbody", i)
+				}
+			}
+			commits = append(commits, c)
+		}
+		return commits, prs
+	}
+
+	tests := []struct {
+		name         string
+		direct       int
+		viaPR        int
+		wantRisk     bool
+		wantSeverity domain.Severity
+	}{
+		{"all via PR", 0, 20, false, 0},
+		{"at warning threshold", 4, 16, false, 0},
+		{"medium", 6, 14, true, domain.SeverityMedium},
+		{"high", 15, 5, true, domain.SeverityHigh},
+		{"too few commits", 5, 0, false, 0},
+	}
+
+	s := &Service{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commits, prs := linear(tt.direct, tt.viaPR)
+			risks := s.detectDirectPushes(commits, prs)
+			if !tt.wantRisk {
+				if len(risks) != 0 {
+					t.Errorf("expected no risks, got %+v", risks)
+				}
+				return
+			}
+			if len(risks) != 1 {
+				t.Fatalf("risks = %d, want 1", len(risks))
+			}
+			if risks[0].Type != domain.RiskTypeDirectPush {
+				t.Errorf("Type = %v, want %v", risks[0].Type, domain.RiskTypeDirectPush)
+			}
+			if risks[0].Severity != tt.wantSeverity {
+				t.Errorf("Severity = %v, want %v", risks[0].Severity, tt.wantSeverity)
+			}
+		})
+	}
+
+	t.Run("squash merge title", func(t *testing.T) {
+		commits, _ := linear(20, 0)
+		for i := range commits {
+			commits[i].Message = fmt.Sprintf("Fix login (#%d)", i)
+		}
+		if risks := s.detectDirectPushes(commits, nil); len(risks) != 0 {
+			t.Errorf("squash merges should count as via PR, got %+v", risks)
+		}
+	})
+
+	t.Run("commits inside merged PR branch are not judged", func(t *testing.T) {
+		// main はすべてPRのマージコミット。PRブランチ側の個々のコミットは対象外
+		var commits []Commit
+		for i := range 10 {
+			commits = append(commits,
+				Commit{
+					SHA:     fmt.Sprintf("m%d", i),
+					Parents: []string{fmt.Sprintf("m%d", i+1), fmt.Sprintf("f%d", i)},
+					Date:    base.Add(-time.Duration(2*i) * time.Hour),
+					Message: fmt.Sprintf("Merge pull request #%d from org/feature", i),
+				},
+				Commit{
+					SHA:     fmt.Sprintf("f%d", i),
+					Parents: []string{fmt.Sprintf("m%d", i+1)},
+					Date:    base.Add(-time.Duration(2*i+1) * time.Hour),
+					Message: "wip",
+				},
+			)
+		}
+		if risks := s.detectDirectPushes(commits, nil); len(risks) != 0 {
+			t.Errorf("expected no risks, got %+v", risks)
+		}
+	})
+}
+
+func TestDetectStalePRs(t *testing.T) {
+	asOf := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	openPRs := func(stale, fresh int) []PullRequest {
+		var prs []PullRequest
+		for range stale {
+			prs = append(prs, PullRequest{CreatedAt: asOf.AddDate(0, 0, -45)})
+		}
+		for range fresh {
+			prs = append(prs, PullRequest{CreatedAt: asOf.AddDate(0, 0, -3)})
+		}
+		return prs
+	}
+
+	tests := []struct {
+		name         string
+		service      *Service
+		prs          []PullRequest
+		wantRisk     bool
+		wantSeverity domain.Severity
+	}{
+		{"below threshold", &Service{}, openPRs(stalePRCountWarning, 20), false, 0},
+		{"medium", &Service{}, openPRs(stalePRCountWarning+1, 0), true, domain.SeverityMedium},
+		{"high", &Service{}, openPRs(stalePRCountCritical+1, 0), true, domain.SeverityHigh},
+		// 60日に広げると45日前のPRは滞留扱いにならない
+		{"custom days", NewService(nil, WithStalePRDays(60)), openPRs(stalePRCountCritical+1, 0), false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			risks := tt.service.detectStalePRs(tt.prs, asOf)
+			if !tt.wantRisk {
+				if len(risks) != 0 {
+					t.Errorf("expected no risks, got %+v", risks)
+				}
+				return
+			}
+			if len(risks) != 1 {
+				t.Fatalf("risks = %d, want 1", len(risks))
+			}
+			if risks[0].Type != domain.RiskTypeStalePR {
+				t.Errorf("Type = %v, want %v", risks[0].Type, domain.RiskTypeStalePR)
+			}
+			if risks[0].Severity != tt.wantSeverity {
+				t.Errorf("Severity = %v, want %v", risks[0].Severity, tt.wantSeverity)
+			}
+		})
+	}
+}
+
 func TestDetectLateNightRisk(t *testing.T) {
 	s := &Service{}
 
@@ -141,6 +376,58 @@ func TestDetectLateNightRisk(t *testing.T) {
 	}
 }
 
+func TestDetectWeekendRisk(t *testing.T) {
+	s := &Service{}
+
+	tests := []struct {
+		name      string
+		commits   []Commit
+		wantRisks int
+	}{
+		{"empty", nil, 0},
+		{
+			"below threshold (10%)",
+			[]Commit{
+				{Date: time.Date(2025, 1, 4, 10, 0, 0, 0, time.UTC)}, // 土
+				{Date: time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)},
+				{Date: time.Date(2025, 1, 7, 10, 0, 0, 0, time.UTC)},
+				{Date: time.Date(2025, 1, 7, 11, 0, 0, 0, time.UTC)},
+				{Date: time.Date(2025, 1, 7, 12, 0, 0, 0, time.UTC)},
+				{Date: time.Date(2025, 1, 8, 10, 0, 0, 0, time.UTC)},
+				{Date: time.Date(2025, 1, 8, 11, 0, 0, 0, time.UTC)},
+				{Date: time.Date(2025, 1, 9, 10, 0, 0, 0, time.UTC)},
+				{Date: time.Date(2025, 1, 9, 11, 0, 0, 0, time.UTC)},
+				{Date: time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC)},
+			},
+			0,
+		},
+		{
+			"at threshold (20%)",
+			[]Commit{
+				{Date: time.Date(2025, 1, 5, 10, 0, 0, 0, time.UTC)}, // 日
+				{Date: time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)},
+				{Date: time.Date(2025, 1, 7, 10, 0, 0, 0, time.UTC)},
+				{Date: time.Date(2025, 1, 8, 10, 0, 0, 0, time.UTC)},
+				{Date: time.Date(2025, 1, 9, 10, 0, 0, 0, time.UTC)},
+			},
+			1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			risks := s.detectWeekendRisk(tt.commits)
+			if len(risks) != tt.wantRisks {
+				t.Fatalf("got %d risks, want %d", len(risks), tt.wantRisks)
+			}
+			for _, r := range risks {
+				if r.Type != domain.RiskTypeWeekendWork || r.Type.Category() != domain.CategoryHealth {
+					t.Errorf("risk = %+v, want weekend work in health", r)
+				}
+			}
+		})
+	}
+}
+
 func TestDetectLargeFiles(t *testing.T) {
 	s := &Service{}
 	files := []File{
@@ -181,6 +468,35 @@ func TestDetectOutdatedDeps(t *testing.T) {
 	}
 }
 
+func TestSummarizeDependencies(t *testing.T) {
+	deps := []Dependency{
+		{Name: "react", AgeMonths: 6, PackageType: "npm"},
+		{Name: "lodash", AgeMonths: 30, PackageType: "npm"},
+		{Name: "left-pad", AgeMonths: 40, PackageType: "npm"},
+		{Name: "golang.org/x/net", AgeMonths: 2, PackageType: "go"},
+		{Name: "requests", AgeMonths: 24, PackageType: "python"}, // ちょうど2年 → 古い
+	}
+
+	ecosystems, rate := summarizeDependencies(deps)
+
+	want := []domain.DependencyEcosystem{
+		{Ecosystem: "npm", Count: 3, Outdated: 2},
+		{Ecosystem: "go", Count: 1, Outdated: 0},
+		{Ecosystem: "python", Count: 1, Outdated: 1},
+	}
+	if !reflect.DeepEqual(ecosystems, want) {
+		t.Errorf("ecosystems = %+v, want %+v", ecosystems, want)
+	}
+	if rate != 60 {
+		t.Errorf("outdatedRate = %v, want 60", rate)
+	}
+
+	ecosystems, rate = summarizeDependencies(nil)
+	if len(ecosystems) != 0 || rate != 0 {
+		t.Errorf("summarizeDependencies(nil) = %+v, %v, want empty and 0", ecosystems, rate)
+	}
+}
+
 func TestDetectMetricRisks(t *testing.T) {
 	s := &Service{}
 
@@ -240,6 +556,278 @@ func TestDetectMetricRisks(t *testing.T) {
 		}
 	})
 
+	t.Run("low deploy frequency", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			rating string
+			want   bool
+		}{
+			{"rated", "Low", true},
+			{"window too short", domain.DeployFreqRatingWindowTooShort, false},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				m := domain.Metrics{DeployFrequency: 0.5, DeployFreqRating: tt.rating}
+				found := false
+				for _, r := range s.detectMetricRisks(m) {
+					if r.Type == domain.RiskTypeLowDeployFreq {
+						found = true
+					}
+				}
+				if found != tt.want {
+					t.Errorf("RiskTypeLowDeployFreq detected = %v, want %v", found, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("low review coverage", func(t *testing.T) {
+		tests := []struct {
+			name         string
+			rate         float64
+			prs          int
+			wantRisk     bool
+			wantSeverity domain.Severity
+		}{
+			{"covered", 90.0, 20, false, 0},
+			{"below warning", 70.0, 20, true, domain.SeverityMedium},
+			{"below critical", 30.0, 20, true, domain.SeverityHigh},
+			{"too few PRs", 0.0, 4, false, 0},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				m := domain.Metrics{ReviewCoverageRate: tt.rate, ReviewCoveragePRs: tt.prs}
+				var got *domain.Risk
+				for _, r := range s.detectMetricRisks(m) {
+					if r.Type == domain.RiskTypeLowReviewCoverage {
+						got = &r
+					}
+				}
+				if !tt.wantRisk {
+					if got != nil {
+						t.Errorf("unexpected risk: %+v", *got)
+					}
+					return
+				}
+				if got == nil {
+					t.Fatal("expected RiskTypeLowReviewCoverage")
+				}
+				if got.Severity != tt.wantSeverity {
+					t.Errorf("Severity = %v, want %v", got.Severity, tt.wantSeverity)
+				}
+			})
+		}
+	})
+
+	t.Run("self merge", func(t *testing.T) {
+		tests := []struct {
+			name         string
+			rate         float64
+			prs          int
+			wantRisk     bool
+			wantSeverity domain.Severity
+		}{
+			{"few self merges", 10.0, 20, false, 0},
+			{"above warning", 30.0, 20, true, domain.SeverityMedium},
+			{"above critical", 80.0, 20, true, domain.SeverityHigh},
+			{"too few PRs", 100.0, 4, false, 0},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// カバレッジは良好にしておき、セルフマージ単独の判定を見る
+				m := domain.Metrics{ReviewCoverageRate: 100, ReviewCoveragePRs: tt.prs, SelfMergeRate: tt.rate}
+				var got *domain.Risk
+				for _, r := range s.detectMetricRisks(m) {
+					if r.Type == domain.RiskTypeSelfMerge {
+						got = &r
+					}
+				}
+				if !tt.wantRisk {
+					if got != nil {
+						t.Errorf("unexpected risk: %+v", *got)
+					}
+					return
+				}
+				if got == nil {
+					t.Fatal("expected RiskTypeSelfMerge")
+				}
+				if got.Severity != tt.wantSeverity {
+					t.Errorf("Severity = %v, want %v", got.Severity, tt.wantSeverity)
+				}
+			})
+		}
+	})
+
+	t.Run("low commit quality", func(t *testing.T) {
+		tests := []struct {
+			name         string
+			rate         float64
+			commits      int
+			wantRisk     bool
+			wantSeverity domain.Severity
+		}{
+			{"few low-quality messages", 10.0, 50, false, 0},
+			{"at warning", 30.0, 50, false, 0},
+			{"above warning", 40.0, 50, true, domain.SeverityMedium},
+			{"above critical", 60.0, 50, true, domain.SeverityHigh},
+			{"too few commits", 100.0, 19, false, 0},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				m := domain.Metrics{
+					TotalCommits:          tt.commits,
+					LowQualityCommitCount: int(float64(tt.commits) * tt.rate / 100),
+					LowQualityCommitRate:  tt.rate,
+				}
+				var got *domain.Risk
+				for _, r := range s.detectMetricRisks(m) {
+					if r.Type == domain.RiskTypeLowCommitQuality {
+						got = &r
+					}
+				}
+				if !tt.wantRisk {
+					if got != nil {
+						t.Errorf("unexpected risk: %+v", *got)
+					}
+					return
+				}
+				if got == nil {
+					t.Fatal("expected RiskTypeLowCommitQuality")
+				}
+				if got.Severity != tt.wantSeverity {
+					t.Errorf("Severity = %v, want %v", got.Severity, tt.wantSeverity)
+				}
+				if got.Type.Category() != domain.CategoryQuality {
+					t.Errorf("Category = %v, want %v", got.Type.Category(), domain.CategoryQuality)
+				}
+			})
+		}
+	})
+
+	t.Run("fast revert", func(t *testing.T) {
+		tests := []struct {
+			name         string
+			fast         int
+			wantRisk     bool
+			wantSeverity domain.Severity
+		}{
+			{"none", 0, false, 0},
+			{"one", 1, true, domain.SeverityMedium},
+			{"at critical", 3, true, domain.SeverityHigh},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				m := domain.Metrics{FeatureRatio: 100, RevertCommitCount: tt.fast + 1, FastRevertCount: tt.fast}
+				var got *domain.Risk
+				for _, r := range s.detectMetricRisks(m) {
+					if r.Type == domain.RiskTypeFastRevert {
+						got = &r
+					}
+				}
+				if !tt.wantRisk {
+					if got != nil {
+						t.Errorf("unexpected risk: %+v", *got)
+					}
+					return
+				}
+				if got == nil {
+					t.Fatal("expected RiskTypeFastRevert")
+				}
+				if got.Severity != tt.wantSeverity || got.Value != tt.fast {
+					t.Errorf("Severity = %v, Value = %d, want %v, %d", got.Severity, got.Value, tt.wantSeverity, tt.fast)
+				}
+				if got.Type.Category() != domain.CategoryQuality {
+					t.Errorf("Category = %v, want %v", got.Type.Category(), domain.CategoryQuality)
+				}
+			})
+		}
+	})
+
+	t.Run("large commits", func(t *testing.T) {
+		tests := []struct {
+			name         string
+			commits      int
+			avgFiles     float64
+			wantRisk     bool
+			wantSeverity domain.Severity
+		}{
+			{"small commits", 50, 3.5, false, 0},
+			{"at warning", 50, 10, false, 0},
+			{"above warning", 50, 12.5, true, domain.SeverityMedium},
+			{"above critical", 50, 30, true, domain.SeverityHigh},
+			{"too few commits", 19, 30, false, 0},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				m := domain.Metrics{FeatureRatio: 100, SizedCommitCount: tt.commits, AvgFilesPerCommit: tt.avgFiles}
+				var got *domain.Risk
+				for _, r := range s.detectMetricRisks(m) {
+					if r.Type == domain.RiskTypeLargeCommits {
+						got = &r
+					}
+				}
+				if !tt.wantRisk {
+					if got != nil {
+						t.Errorf("unexpected risk: %+v", *got)
+					}
+					return
+				}
+				if got == nil {
+					t.Fatal("expected RiskTypeLargeCommits")
+				}
+				if got.Severity != tt.wantSeverity {
+					t.Errorf("Severity = %v, want %v", got.Severity, tt.wantSeverity)
+				}
+				if detail := formatRiskDetail(*got, i18n.English); detail != fmt.Sprintf("%.1f files per commit on average, target 10.0 or fewer", tt.avgFiles) {
+					t.Errorf("detail = %q", detail)
+				}
+			})
+		}
+	})
+
+	t.Run("pr abandonment", func(t *testing.T) {
+		tests := []struct {
+			name         string
+			merged       int
+			abandoned    int
+			wantRisk     bool
+			wantSeverity domain.Severity
+		}{
+			{"few abandoned", 18, 2, false, 0},
+			{"above warning", 12, 8, true, domain.SeverityMedium},
+			{"above critical", 8, 12, true, domain.SeverityHigh},
+			{"too few PRs", 1, 8, false, 0},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				m := domain.Metrics{
+					FeaturePRCount:   tt.merged,
+					FeatureRatio:     100,
+					AbandonedPRCount: tt.abandoned,
+					AbandonmentRate:  float64(tt.abandoned) / float64(tt.merged+tt.abandoned) * 100,
+				}
+				var got *domain.Risk
+				for _, r := range s.detectMetricRisks(m) {
+					if r.Type == domain.RiskTypeHighPRAbandonment {
+						got = &r
+					}
+				}
+				if !tt.wantRisk {
+					if got != nil {
+						t.Errorf("unexpected risk: %+v", *got)
+					}
+					return
+				}
+				if got == nil {
+					t.Fatal("expected RiskTypeHighPRAbandonment")
+				}
+				if got.Severity != tt.wantSeverity {
+					t.Errorf("Severity = %v, want %v", got.Severity, tt.wantSeverity)
+				}
+			})
+		}
+	})
+
 	t.Run("no risks when metrics are good", func(t *testing.T) {
 		m := domain.Metrics{
 			AvgLeadTime:       3.0,
@@ -264,6 +852,31 @@ func TestDetectMetricRisks(t *testing.T) {
 	})
 }
 
+func TestDetectMetricRisks_English(t *testing.T) {
+	s := NewService(nil, WithLang(i18n.English))
+	risks := s.detectMetricRisks(domain.Metrics{AvgLeadTime: 10.0})
+	if len(risks) != 1 {
+		t.Fatalf("risks = %d, want 1", len(risks))
+	}
+	if got, want := risks[0].Description, "Average PR lead time is 10.0 days"; got != want {
+		t.Errorf("Description = %q, want %q", got, want)
+	}
+	if got, want := risks[0].Target, "Entire repository"; got != want {
+		t.Errorf("Target = %q, want %q", got, want)
+	}
+
+	// グレード A では個別の診断が出ないので、重大度を上げて B 以下にする
+	risks[0].Severity = domain.SeverityHigh
+	scores := s.calculateCategoryScores(append(risks, risks[0]))
+	velocity := scores[domain.CategoryVelocity]
+	if got, want := velocity.Diagnosis, "Long PR lead times are slowing development"; got != want {
+		t.Errorf("Diagnosis = %q, want %q", got, want)
+	}
+	if got, want := velocity.Score.Breakdown[0].Label, "Base score"; got != want {
+		t.Errorf("Breakdown[0].Label = %q, want %q", got, want)
+	}
+}
+
 func TestCalculateCategoryScores(t *testing.T) {
 	s := &Service{}
 
@@ -327,6 +940,61 @@ func TestCalculateCategoryScores(t *testing.T) {
 	})
 }
 
+func TestCalculateCategoryScores_CustomPenalties(t *testing.T) {
+	risks := []domain.Risk{
+		{Type: domain.RiskTypeHighChangeFailure, Severity: domain.SeverityHigh}, // Quality
+		{Type: domain.RiskTypeLateNight, Severity: domain.SeverityMedium},       // Health
+		{Type: domain.RiskTypeOwnership, Severity: domain.SeverityLow},          // Health
+	}
+	s := NewService(nil, WithSeverityPenalties(SeverityPenalties{High: 30, Medium: 4, Low: 0}))
+	scores := s.calculateCategoryScores(risks)
+
+	if got := scores[domain.CategoryQuality].Score.Value; got != 70 {
+		t.Errorf("quality score = %d, want 70", got)
+	}
+	if got := scores[domain.CategoryHealth].Score.Value; got != 96 {
+		t.Errorf("health score = %d, want 96", got)
+	}
+	// 内訳の点数も設定した減点になる
+	var points []int
+	for _, item := range scores[domain.CategoryHealth].Score.Breakdown {
+		points = append(points, item.Points)
+	}
+	if want := []int{baseScore, -4, 0}; !reflect.DeepEqual(points, want) {
+		t.Errorf("health breakdown points = %v, want %v", points, want)
+	}
+
+	// 未設定ならデフォルトの減点と同じ
+	defaults := NewService(nil).calculateCategoryScores(risks)
+	explicit := NewService(nil, WithSeverityPenalties(DefaultSeverityPenalties)).calculateCategoryScores(risks)
+	for _, cat := range scoreCategories {
+		if defaults[cat].Score.Value != explicit[cat].Score.Value {
+			t.Errorf("%s score = %d with defaults, %d with DefaultSeverityPenalties", cat, defaults[cat].Score.Value, explicit[cat].Score.Value)
+		}
+	}
+}
+
+func TestSeverityPenalties_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       SeverityPenalties
+		wantErr bool
+	}{
+		{"default", DefaultSeverityPenalties, false},
+		{"zero", SeverityPenalties{}, false},
+		{"max", SeverityPenalties{High: 100, Medium: 100, Low: 100}, false},
+		{"negative", SeverityPenalties{High: 15, Medium: -1, Low: 5}, true},
+		{"over base score", SeverityPenalties{High: 101, Medium: 10, Low: 5}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.p.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestCalculateOverallScore(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -357,7 +1025,69 @@ func TestCalculateOverallScore(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := calculateOverallScore(tt.scores)
+			got := calculateOverallScore(tt.scores, nil)
+			if got.Value != tt.want {
+				t.Errorf("calculateOverallScore() = %d, want %d", got.Value, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateOverallScore_Weighted(t *testing.T) {
+	scores := map[domain.Category]domain.CategoryScore{
+		domain.CategoryVelocity: {Score: domain.NewScore(80)},
+		domain.CategoryQuality:  {Score: domain.NewScore(60)},
+		domain.CategoryTechDebt: {Score: domain.NewScore(100)},
+		domain.CategoryHealth:   {Score: domain.NewScore(40)},
+	}
+
+	tests := []struct {
+		name    string
+		weights map[domain.Category]float64
+		want    int
+	}{
+		{"nil weights → simple average", nil, 70},
+		{
+			"equal weights → same as default",
+			map[domain.Category]float64{
+				domain.CategoryVelocity: 0.25,
+				domain.CategoryQuality:  0.25,
+				domain.CategoryTechDebt: 0.25,
+				domain.CategoryHealth:   0.25,
+			},
+			70,
+		},
+		{
+			// (80*2 + 60*2 + 100*1 + 40*1) / 6 = 70
+			"velocity and quality doubled",
+			map[domain.Category]float64{
+				domain.CategoryVelocity: 2,
+				domain.CategoryQuality:  2,
+				domain.CategoryTechDebt: 1,
+				domain.CategoryHealth:   1,
+			},
+			70,
+		},
+		{
+			// (80*0.4 + 60*0.4 + 100*0.2) = 76
+			"health ignored",
+			map[domain.Category]float64{
+				domain.CategoryVelocity: 0.4,
+				domain.CategoryQuality:  0.4,
+				domain.CategoryTechDebt: 0.2,
+				domain.CategoryHealth:   0,
+			},
+			76,
+		},
+		{
+			"quality only",
+			map[domain.Category]float64{domain.CategoryQuality: 1},
+			60,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateOverallScore(scores, tt.weights)
 			if got.Value != tt.want {
 				t.Errorf("calculateOverallScore() = %d, want %d", got.Value, tt.want)
 			}
@@ -365,6 +1095,75 @@ func TestCalculateOverallScore(t *testing.T) {
 	}
 }
 
+func TestNormalizeCategoryWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights map[domain.Category]float64
+		want    map[domain.Category]float64
+		wantErr bool
+	}{
+		{
+			name:    "nil → equal",
+			weights: nil,
+			want: map[domain.Category]float64{
+				domain.CategoryVelocity: 0.25,
+				domain.CategoryQuality:  0.25,
+				domain.CategoryTechDebt: 0.25,
+				domain.CategoryHealth:   0.25,
+			},
+		},
+		{
+			name: "partial → unspecified default to 1",
+			weights: map[domain.Category]float64{
+				domain.CategoryQuality: 3,
+				domain.CategoryHealth:  0,
+			},
+			want: map[domain.Category]float64{
+				domain.CategoryVelocity: 0.2,
+				domain.CategoryQuality:  0.6,
+				domain.CategoryTechDebt: 0.2,
+				domain.CategoryHealth:   0,
+			},
+		},
+		{
+			name:    "unknown category",
+			weights: map[domain.Category]float64{"speed": 1},
+			wantErr: true,
+		},
+		{
+			name:    "negative weight",
+			weights: map[domain.Category]float64{domain.CategoryHealth: -1},
+			wantErr: true,
+		},
+		{
+			name: "all zero",
+			weights: map[domain.Category]float64{
+				domain.CategoryVelocity: 0,
+				domain.CategoryQuality:  0,
+				domain.CategoryTechDebt: 0,
+				domain.CategoryHealth:   0,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeCategoryWeights(tt.weights)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			for cat, want := range tt.want {
+				if math.Abs(got[cat]-want) > 1e-9 {
+					t.Errorf("weight[%s] = %v, want %v", cat, got[cat], want)
+				}
+			}
+		})
+	}
+}
+
 func TestGenerateDiagnosis(t *testing.T) {
 	t.Run("grade A → good", func(t *testing.T) {
 		got := generateDiagnosis(domain.CategoryHealth, domain.NewScore(90), nil)
@@ -388,3 +1187,52 @@ func TestGenerateDiagnosis(t *testing.T) {
 		}
 	})
 }
+
+func TestTopLargeFiles(t *testing.T) {
+	files := []domain.LargeFile{
+		{Path: "b.js", SizeKB: 60},
+		{Path: "a.js", SizeKB: 200},
+		{Path: "c.js", SizeKB: 60},
+		{Path: "d.js", SizeKB: 120},
+	}
+
+	got := topLargeFiles(files, 3)
+	want := []string{"a.js", "d.js", "b.js"}
+	if len(got) != len(want) {
+		t.Fatalf("len = %d, want %d", len(got), len(want))
+	}
+	for i, path := range want {
+		if got[i].Path != path {
+			t.Errorf("got[%d] = %q, want %q", i, got[i].Path, path)
+		}
+	}
+	// 元の一覧は並べ替えない
+	if files[0].Path != "b.js" {
+		t.Errorf("input modified: %+v", files)
+	}
+
+	if got := topLargeFiles(files, 10); len(got) != 4 {
+		t.Errorf("len = %d, want 4 (n larger than input)", len(got))
+	}
+}
+
+func TestTopHotFiles(t *testing.T) {
+	risks := []domain.Risk{
+		{Type: domain.RiskTypeChangeConcentration, Target: "b.go", Value: 12, Severity: domain.SeverityMedium},
+		{Type: domain.RiskTypeLateNight, Target: "リポジトリ全体", Value: 40},
+		{Type: domain.RiskTypeChangeConcentration, Target: "a.go", Value: 25, Severity: domain.SeverityHigh},
+		{Type: domain.RiskTypeChangeConcentration, Target: "c.go", Value: 12, Severity: domain.SeverityMedium},
+	}
+
+	got := topHotFiles(risks, 2)
+	if len(got) != 2 {
+		t.Fatalf("len = %d, want 2", len(got))
+	}
+	if got[0].Path != "a.go" || got[0].Changes != 25 || got[0].Severity != domain.SeverityHigh {
+		t.Errorf("got[0] = %+v", got[0])
+	}
+	// 同じ変更回数はパス順
+	if got[1].Path != "b.go" {
+		t.Errorf("got[1] = %+v, want b.go", got[1])
+	}
+}
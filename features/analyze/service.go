@@ -2,144 +2,612 @@ package analyze
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/shared/i18n"
 )
 
 // Service は分析のビジネスロジックを担当する。
 type Service struct {
 	repo Repository
+
+	// 変更ファイルを取得するコミット数の上限（0 ならデフォルト）
+	commitDetailsLimit int
+
+	// 深夜判定・時間帯別集計に使うタイムゾーン（nil ならコミット自身のオフセット）
+	location *time.Location
+
+	// 取得ごとの所要時間・件数などを出すロガー（nil なら slog.Default）
+	logger *slog.Logger
+
+	// 滞留PRとみなすオープン日数（0 ならデフォルト）
+	stalePRDays int
+
+	// 総合スコアのカテゴリ別の重み（nil なら均等）
+	categoryWeights map[domain.Category]float64
+
+	// カテゴリスコアの重大度別の減点（nil ならデフォルト）
+	penalties *SeverityPenalties
+
+	// DORA レーティングの境界（nil ならデフォルト）
+	doraBands *domain.DORARatingBands
+
+	// 障害とみなす Issue ラベル（小文字、nil ならデフォルト）
+	failureLabels []string
+
+	// 巨大ファイルリスクの対象外にするファイル名の末尾（小文字、nil ならデフォルト）
+	nonSourceExtensions []string
+
+	// テストファイルとみなすパスのパターン（小文字、nil ならデフォルト）
+	testFilePatterns []string
+
+	// PRの種類を判定するブランチ名の接頭辞（長い順、nil ならデフォルト）
+	branchPrefixKinds []branchPrefixKind
+
+	// リスクの説明・診断などを書く言語（ゼロ値なら日本語）
+	lang i18n.Lang
+
+	// 現在時刻を返す関数（nil なら time.Now）。所要時間の計測には使わない
+	clock func() time.Time
+
+	// 巨大ファイル・変更集中ファイルの一覧に残す件数（0 ならデフォルト）
+	topFilesLimit int
+
+	// PR詳細を同時に取得するPR数（0 ならデフォルト）
+	prDetailsWorkers int
+
+	// TODO コメントを数えるために内容を取得するファイル数の上限（0 なら走査しない）
+	todoScanMaxFiles int
+
+	// ファイル系リスクから除外するパターン（nil ならリポジトリの .lokupignore を使う）
+	ignoreRules []ignoreRule
+
+	// 分析対象のパス（空ならリポジトリ全体）。前後の "/" は除いた形で持つ
+	path string
+
+	// 分析対象のブランチ（空ならデフォルトブランチ）
+	branch string
+
+	// DORA メトリクスのデプロイの取得元（空なら releases）と、workflows のときのワークフロー名
+	deploySource   DeploySource
+	deployWorkflow string
+
+	// 依存の既知の脆弱性を調べる先（nil なら調べない）
+	vulnChecker VulnerabilityChecker
+
+	// クイックモード（PR詳細と依存のリリース日を取得しない）
+	quick bool
+
+	// ドラフトPRもPR系のメトリクスに含めるか（false なら除外する）
+	includeDrafts bool
+
+	// 共同作成のコミットが多ければ属人化リスクの重大度を下げるか
+	coAuthorshipMitigation bool
+
+	// MTTR で復旧作業の開始とみなす時点（空なら Issue の作成）
+	mttrSource MTTRSource
+
+	// 属人化リスクでコミットの偏りを見るときの集計元（空なら累計のコントリビューター）
+	ownershipSource OwnershipSource
+
+	// 進捗の通知先（nil なら通知しない）と、並行する通知を直列化するロック
+	progress   ProgressFunc
+	progressMu sync.Mutex
 }
 
-// NewService は Service を生成する。
-func NewService(repo Repository) *Service {
-	return &Service{repo: repo}
+// Option は Service の設定を変更する。
+type Option func(*Service)
+
+// WithMaxCommitDetails は変更ファイルを取得するコミット数の上限を設定する。
+// 小規模リポジトリで全コミットを対象にしたい場合に大きくする。
+func WithMaxCommitDetails(n int) Option {
+	return func(s *Service) {
+		if n > 0 {
+			s.commitDetailsLimit = n
+		}
+	}
 }
 
-// ServiceInput は Service.Analyze の入力。
-type ServiceInput struct {
-	Repository domain.Repository
-	Period     domain.DateRange
+// WithLocation はコミット時刻を解釈するタイムゾーンを設定する。
+// 分散チームで「深夜」をチームの拠点時刻で判定したい場合に使う。
+func WithLocation(loc *time.Location) Option {
+	return func(s *Service) {
+		s.location = loc
+	}
 }
 
-// Analyze はリポジトリを分析し、結果を返す。
-func (s *Service) Analyze(ctx context.Context, input ServiceInput) (*domain.AnalysisResult, error) {
-	// 1. データ取得
-	commits, err := s.repo.GetCommits(ctx, input.Repository, input.Period)
-	if err != nil {
-		return nil, err
+// WithStalePRDays は滞留PRとみなすオープン日数を設定する。
+func WithStalePRDays(days int) Option {
+	return func(s *Service) {
+		if days > 0 {
+			s.stalePRDays = days
+		}
 	}
+}
 
-	contributors, err := s.repo.GetContributors(ctx, input.Repository)
-	if err != nil {
-		return nil, err
+// WithCategoryWeights は総合スコアを算出するときのカテゴリ別の重みを設定する。
+// NormalizeCategoryWeights で検証した値を渡す。nil なら均等。
+func WithCategoryWeights(weights map[domain.Category]float64) Option {
+	return func(s *Service) {
+		s.categoryWeights = weights
 	}
+}
 
-	// マージ済みPRを取得（リードタイム計算用）
-	closedPRs, err := s.repo.GetPullRequests(ctx, input.Repository, "closed")
-	if err != nil {
-		return nil, err
+// WithSeverityPenalties はカテゴリスコアでリスク1件ごとに引く点数を重大度別に設定する。
+// 設定ファイルなど外部の値は SeverityPenalties.Validate で検証してから渡す。
+func WithSeverityPenalties(p SeverityPenalties) Option {
+	return func(s *Service) {
+		s.penalties = &p
 	}
+}
 
-	// オープンPRを取得
-	openPRs, err := s.repo.GetPullRequests(ctx, input.Repository, "open")
-	if err != nil {
-		return nil, err
+// WithDORARatingBands は DORA メトリクス（デプロイ頻度・変更失敗率・MTTR）のレーティングの境界を設定する。
+// 設定ファイルなど外部の値は domain.DORARatingBands.Validate で検証してから渡す。
+func WithDORARatingBands(b domain.DORARatingBands) Option {
+	return func(s *Service) {
+		s.doraBands = &b
 	}
+}
 
-	// Issue一覧を取得（期間内の作成・クローズを計算）
-	periodStart := input.Period.From
-	allIssues, err := s.repo.GetIssues(ctx, input.Repository, "all", &periodStart)
-	if err != nil {
-		return nil, err
+// WithFailureLabels は障害とみなす Issue ラベルを設定する。
+// 変更失敗率と MTTR の両方に使う。大文字小文字は区別しない。空ならデフォルトのまま。
+func WithFailureLabels(labels []string) Option {
+	return func(s *Service) {
+		if len(labels) == 0 {
+			return
+		}
+		s.failureLabels = make([]string, len(labels))
+		for i, l := range labels {
+			s.failureLabels[i] = strings.ToLower(l)
+		}
 	}
+}
 
-	// オープンIssue数を取得
-	openIssues, err := s.repo.GetIssues(ctx, input.Repository, "open", nil)
-	if err != nil {
-		return nil, err
+// WithNonSourceExtensions は巨大ファイルリスクの対象外にするファイル名の末尾（".png"、"-lock.json" など）を設定する。
+// デフォルト（DefaultNonSourceExtensions）を置き換える。大文字小文字は区別しない。空ならデフォルトのまま。
+func WithNonSourceExtensions(exts []string) Option {
+	return func(s *Service) {
+		if len(exts) == 0 {
+			return
+		}
+		s.nonSourceExtensions = make([]string, len(exts))
+		for i, ext := range exts {
+			s.nonSourceExtensions[i] = strings.ToLower(ext)
+		}
+	}
+}
+
+// WithTestFilePatterns はテストファイルとみなすパスのパターンを設定する。
+// デフォルト（DefaultTestFilePatterns）を置き換える。大文字小文字は区別しない。空ならデフォルトのまま。
+// 書式は testFilePatternMatch を参照。設定ファイルなど外部の値は ValidateTestFilePatterns で検証してから渡す。
+func WithTestFilePatterns(patterns []string) Option {
+	return func(s *Service) {
+		if len(patterns) == 0 {
+			return
+		}
+		s.testFilePatterns = make([]string, len(patterns))
+		for i, p := range patterns {
+			s.testFilePatterns[i] = strings.ToLower(p)
+		}
 	}
+}
 
-	// ファイル一覧を取得（巨大ファイル検出用）
-	files, err := s.repo.GetFiles(ctx, input.Repository)
-	if err != nil {
-		return nil, err
+// WithBranchPrefixes はPRの種類（投資比率・バグ修正割合）を判定するブランチ名の接頭辞を設定する。
+// nil の種類はデフォルトのまま（ゼロ値ならすべてデフォルト）。大文字小文字は区別しない。
+// 設定ファイルなど外部の値は NormalizeBranchPrefixes で検証してから渡す。
+func WithBranchPrefixes(p BranchPrefixes) Option {
+	return func(s *Service) {
+		s.branchPrefixKinds = p.prefixKinds()
 	}
+}
 
-	// 依存情報を取得（古い依存検出用）
-	dependencies, err := s.repo.GetDependencies(ctx, input.Repository)
-	if err != nil {
-		return nil, err
+// WithLang はリスクの説明・診断などの出力言語を設定する。
+func WithLang(lang i18n.Lang) Option {
+	return func(s *Service) {
+		s.lang = lang
 	}
+}
 
-	// リリース一覧を取得（DORA デプロイ頻度用）
-	releases, err := s.repo.GetReleases(ctx, input.Repository)
-	if err != nil {
-		log.Printf("Warning: failed to get releases: %v", err)
-		releases = nil
+// WithDeploySource は DORA メトリクスでデプロイとみなすイベントの取得元を設定する。
+// Release を作らずタグだけ打つチームや、GitHub Actions でデプロイするチーム向け。
+// DeploySourceWorkflows の場合は workflow にデプロイ用ワークフローの名前（またはファイル名）を渡す。
+func WithDeploySource(source DeploySource, workflow string) Option {
+	return func(s *Service) {
+		s.deploySource = source
+		s.deployWorkflow = workflow
+	}
+}
+
+// WithMTTRSource は MTTR で復旧作業の開始とみなす時点を設定する。
+// MTTRSourceEvents なら障害Issueごとにイベントを取得し、トリアージで放置された時間を MTTR から除く。
+func WithMTTRSource(source MTTRSource) Option {
+	return func(s *Service) {
+		s.mttrSource = source
+	}
+}
+
+// WithClock は現在時刻の取得元を設定する。
+// テストやレポートの再現のために時刻を固定したい場合に使う。nil ならデフォルトのまま。
+func WithClock(now func() time.Time) Option {
+	return func(s *Service) {
+		if now != nil {
+			s.clock = now
+		}
+	}
+}
+
+// WithTopFiles は巨大ファイル・変更集中ファイルの一覧に残す件数を設定する。
+// 大規模リポジトリでレポートが埋め尽くされないよう、検出後に上位だけを残す。
+// リスクの件数・スコアは全件から算出する。
+func WithTopFiles(n int) Option {
+	return func(s *Service) {
+		if n > 0 {
+			s.topFilesLimit = n
+		}
+	}
+}
+
+// WithPRDetailsConcurrency はPR詳細（サイズ・レビュー）を同時に取得するPR数を設定する。
+// PRごとに2回のAPIコールが直列に並ぶと待ち時間の大半を占めるため、PR単位で並列化する。
+func WithPRDetailsConcurrency(n int) Option {
+	return func(s *Service) {
+		if n > 0 {
+			s.prDetailsWorkers = n
+		}
+	}
+}
+
+// WithTodoScan はソースファイルの TODO / FIXME / HACK コメントを数える。
+// ファイルごとに内容を取得するため API コールが多く、既定では行わない。
+// maxFiles は内容を取得するファイル数の上限（大きいファイルから選ぶ）。0 以下なら走査しない。
+func WithTodoScan(maxFiles int) Option {
+	return func(s *Service) {
+		s.todoScanMaxFiles = max(maxFiles, 0)
+	}
+}
+
+// WithIgnoreFile は除外パターン（.lokupignore 形式）の内容を設定する。
+// 指定するとリポジトリの .lokupignore は読まない。
+func WithIgnoreFile(content string) Option {
+	return func(s *Service) {
+		s.ignoreRules = parseIgnoreFile(content)
+	}
+}
+
+// WithPath は分析対象をリポジトリ内のパス（ディレクトリ）配下に絞る。
+// モノレポの1サービスだけを診断したい場合に使う。
+// コミット・ファイル・マージ済みPRが対象になり、コントリビューター・Issue・リリース・依存は
+// リポジトリ全体のまま。
+func WithPath(path string) Option {
+	return func(s *Service) {
+		s.path = normalizePath(path)
+	}
+}
+
+// WithBranch はデフォルトブランチの代わりに指定したブランチを分析する。
+// 長く続く develop ブランチなどの状態を見たい場合に使う。
+// コミットとファイル一覧がそのブランチのものになり、PR・Issue・リリース・コントリビューター・
+// ファイルの内容（依存・ライセンス・CODEOWNERS・TODO）・ブランチ保護はリポジトリ（デフォルトブランチ）のまま。
+func WithBranch(branch string) Option {
+	return func(s *Service) {
+		s.branch = strings.TrimSpace(branch)
+	}
+}
+
+// WithCoAuthorshipMitigation は、共同作成（Co-authored-by）のコミットが多い期間の
+// 属人化リスクの重大度を下げる。ペアプログラミング・モブプログラミングで知識を共有している
+// チームが、コミットの作成者の偏りだけで減点されないようにするため。
+func WithCoAuthorshipMitigation(enabled bool) Option {
+	return func(s *Service) {
+		s.coAuthorshipMitigation = enabled
+	}
+}
+
+// WithOwnershipSource は属人化リスクでコミットの偏りを見るときの集計元を設定する。
+// OwnershipSourceWindow なら、何年も前に離れた創業者などの累計コミットに引きずられず、
+// 分析期間内に実際にコミットしている人の偏りで判定する。--path 指定時はパス配下のコミットだけを数える。
+func WithOwnershipSource(source OwnershipSource) Option {
+	return func(s *Service) {
+		s.ownershipSource = source
+	}
+}
+
+// WithQuickMode は時間のかかる取得を省いたクイックモードで分析する。
+// PR詳細・レビュー・コメント（PRごとに3回の API コール）と依存（パッケージレジストリへの問い合わせ）を
+// 取得しないため、PRサイズ・レビュー待ち時間・最初の反応までの時間・レビューカバレッジ・セルフマージ・依存系の
+// メトリクスとリスクは計算されない。初回の当たりをつける対話的な利用向け。
+func WithQuickMode(quick bool) Option {
+	return func(s *Service) {
+		s.quick = quick
+	}
+}
+
+// WithIncludeDrafts はドラフトPRもPR系のメトリクス・リスクの対象にするかを設定する。
+// デフォルトではドラフトは作業中とみなし、リードタイム・PR内訳・PRサイズ・レビュー系・滞留PRから除外する。
+func WithIncludeDrafts(include bool) Option {
+	return func(s *Service) {
+		s.includeDrafts = include
+	}
+}
+
+// WithLogger はロガーを設定する。
+// データ取得の所要時間・件数は Debug レベルで出す。
+func WithLogger(l *slog.Logger) Option {
+	return func(s *Service) {
+		s.logger = l
+	}
+}
+
+// NewService は Service を生成する。
+func NewService(repo Repository, opts ...Option) *Service {
+	s := &Service{repo: repo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// severityPenalties は重大度別の減点を返す。
+// 未設定（ゼロ値の Service を含む）ならデフォルト値を使う。
+func (s *Service) severityPenalties() SeverityPenalties {
+	if s.penalties != nil {
+		return *s.penalties
+	}
+	return DefaultSeverityPenalties
+}
+
+// doraRatingBands は DORA レーティングの境界を返す。
+// 未設定（ゼロ値の Service を含む）ならデフォルト値を使う。
+func (s *Service) doraRatingBands() domain.DORARatingBands {
+	if s.doraBands != nil {
+		return *s.doraBands
+	}
+	return domain.DefaultDORARatingBands
+}
+
+// now は現在時刻を返す。
+// 未設定（ゼロ値の Service を含む）なら time.Now を使う。
+func (s *Service) now() time.Time {
+	if s.clock != nil {
+		return s.clock()
+	}
+	return time.Now()
+}
+
+// maxCommitDetails は変更ファイルを取得するコミット数の上限を返す。
+// 未設定（ゼロ値の Service を含む）ならデフォルト値を使う。
+func (s *Service) maxCommitDetails() int {
+	if s.commitDetailsLimit > 0 {
+		return s.commitDetailsLimit
+	}
+	return DefaultMaxCommitDetails
+}
+
+// topFiles は巨大ファイル・変更集中ファイルの一覧に残す件数を返す。
+// 未設定（ゼロ値の Service を含む）ならデフォルト値を使う。
+func (s *Service) topFiles() int {
+	if s.topFilesLimit > 0 {
+		return s.topFilesLimit
+	}
+	return DefaultTopFiles
+}
+
+// prDetailsConcurrency はPR詳細を同時に取得するPR数を返す。
+// 未設定（ゼロ値の Service を含む）ならデフォルト値を使う。
+func (s *Service) prDetailsConcurrency() int {
+	if s.prDetailsWorkers > 0 {
+		return s.prDetailsWorkers
+	}
+	return DefaultPRDetailsConcurrency
+}
+
+// stalePRAge は滞留PRとみなすオープン日数を返す。
+// 未設定（ゼロ値の Service を含む）ならデフォルト値を使う。
+func (s *Service) stalePRAge() int {
+	if s.stalePRDays > 0 {
+		return s.stalePRDays
+	}
+	return DefaultStalePRDays
+}
+
+// isFailureIssue は Issue が障害ラベルを持つかを返す（大文字小文字は区別しない）。
+// 未設定（ゼロ値の Service を含む）なら DefaultFailureLabels を使う。
+func (s *Service) isFailureIssue(issue Issue) bool {
+	return slices.ContainsFunc(issue.Labels, s.isFailureLabel)
+}
+
+// isFailureLabel はラベルが障害ラベル（WithFailureLabels、未設定ならデフォルト）かを返す。
+func (s *Service) isFailureLabel(label string) bool {
+	labels := s.failureLabels
+	if labels == nil {
+		labels = DefaultFailureLabels
+	}
+	return slices.Contains(labels, strings.ToLower(label))
+}
+
+// log はロガーを返す。
+// 未設定（ゼロ値の Service を含む）なら slog.Default を使う。
+func (s *Service) log() *slog.Logger {
+	if s.logger != nil {
+		return s.logger
 	}
+	return slog.Default()
+}
+
+// ServiceInput は Service.Analyze の入力。
+type ServiceInput struct {
+	Repository domain.Repository
+	Period     domain.DateRange
+}
+
+// Analyze はリポジトリを分析し、結果を返す。
+// ctx がキャンセルされたら実行中の取得を打ち切り、途中までのデータで結果を作らずに ctx.Err() を返す。
+func (s *Service) Analyze(ctx context.Context, input ServiceInput) (*domain.AnalysisResult, error) {
+	start := time.Now()
 
-	// 前期データを取得（トレンド比較用）
+	// 前期（トレンド比較用）: 今期と同じ日数の直前期間
 	prevPeriodDays := input.Period.Days()
 	prevTo := input.Period.From.AddDate(0, 0, -1)
 	prevFrom := prevTo.AddDate(0, 0, -prevPeriodDays)
 	prevPeriod := domain.NewDateRange(prevFrom, prevTo)
 
-	prevCommits, err := s.repo.GetCommits(ctx, input.Repository, prevPeriod)
-	if err != nil {
-		log.Printf("Warning: failed to get previous period commits: %v", err)
-		prevCommits = nil
+	// 指定されたブランチがなければ、空の結果を返す前にエラーにする
+	if s.branch != "" {
+		exists, err := s.repo.BranchExists(ctx, input.Repository, s.branch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check branch %s: %w", s.branch, err)
+		}
+		if !exists {
+			return nil, fmt.Errorf("branch %q not found in %s", s.branch, input.Repository.FullName())
+		}
 	}
 
-	prevPeriodStart := prevPeriod.From
-	prevIssues, err := s.repo.GetIssues(ctx, input.Repository, "all", &prevPeriodStart)
+	// 1. データ取得（独立した API 呼び出しを並行に行う）
+	data, err := s.fetchData(ctx, input, prevPeriod)
 	if err != nil {
-		log.Printf("Warning: failed to get previous period issues: %v", err)
-		prevIssues = nil
+		return nil, err
 	}
+	// 補助データの取得中にキャンセルされても fetchData は成功を返すため、欠けたデータで先に進まない
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	commits := data.metrics.commits
+	contributors := data.metrics.contributors
+	closedPRs := data.metrics.closedPRs
+	files := data.metrics.files
+
+	s.log().Debug("data fetched", "repo", input.Repository.FullName(),
+		"duration", time.Since(start).Round(time.Millisecond))
 
 	// レビュー情報を取得しPR詳細を構築（APIコール共有）
-	prDetails := s.buildPRDetails(ctx, input.Repository, closedPRs)
+	// クイックモードでは取得しない（PRサイズ・レビュー系のメトリクスは未計算になる）
+	var prDetails []domain.PRDetail
+	if !s.quick {
+		prDetailsStart := time.Now()
+		prDetails = s.buildPRDetails(ctx, input.Repository, closedPRs)
+		s.log().Debug("fetched", "what", "pr details", "items", len(prDetails),
+			"duration", time.Since(prDetailsStart).Round(time.Millisecond))
+		data.sources.record(sourcePRDetails, prDetailsSource(closedPRs, prDetails))
+	} else {
+		data.sources.record(sourcePRDetails, domain.DataSource{Skipped: true})
+	}
+
+	s.reportProgress(Progress{Repository: input.Repository, Phase: PhaseAnalyzing})
 
 	// レビュー待ち時間の平均を計算
 	avgReviewWaitTime := calcAvgReviewWait(prDetails)
+	avgFirstResponse := calcAvgFirstResponse(prDetails)
 
 	// PRサイズの平均をPR詳細から計算
 	avgPRSize := calcAvgPRSize(prDetails)
 
+	// レビューカバレッジをPR詳細から計算
+	reviewCoverage, reviewCoveragePRs := calcReviewCoverage(prDetails)
+	selfMergeCount, selfMergeRate := calcSelfMerge(prDetails)
+
 	// 2. リスク検出
 	risks, largeFiles := s.detectRisks(commits, contributors, files)
 
+	// ドリルダウン用の一覧は上位だけ残す（リスクは全件のまま）
+	largeFiles = topLargeFiles(largeFiles, s.topFiles())
+	largeNonSource := s.findLargeNonSourceFiles(files)
+	hotFiles := topHotFiles(risks, s.topFiles())
+
+	// ファイル単位の属人化（1人だけが変更しているファイル）
+	fileOwnershipRisks, siloFiles := s.detectFileOwnership(commits)
+	risks = append(risks, fileOwnershipRisks...)
+
 	// 古い依存の検出
-	outdatedRisks, outdatedDeps := s.detectOutdatedDeps(dependencies)
+	outdatedRisks, outdatedDeps := s.detectOutdatedDeps(data.dependencies)
 	risks = append(risks, outdatedRisks...)
+	risks = append(risks, s.detectVulnerableDeps(data.vulnerableDeps)...)
+
+	// CODEOWNERS によるバス係数の検出
+	risks = append(risks, s.detectBusFactor(data.codeowners, files)...)
+
+	// ライセンスファイルの有無
+	risks = append(risks, s.detectMissingLicense(data.licenseFile)...)
+
+	// コミュニティヘルスファイル（CONTRIBUTING.md など）の有無
+	risks = append(risks, s.detectMissingCommunityFiles(data.communityFiles)...)
+
+	// デフォルトブランチの保護設定
+	risks = append(risks, s.detectNoBranchProtection(data.protection)...)
+
+	// デフォルトブランチへの force push（履歴の書き換え）
+	risks = append(risks, s.detectHistoryRewrite(data.forcePushes)...)
+
+	// TODO コメントの多さ（--scan-todos 指定時のみ）
+	risks = append(risks, s.detectHighTodoDensity(data.todos)...)
+
+	// テストファイルの不足（ファイル名による推定）
+	testFiles, sourceFiles := s.countTestFiles(files)
+	risks = append(risks, s.detectLowTestCoverage(testFiles, sourceFiles, data.filesTruncated)...)
+
+	// 滞留PRの検出
+	risks = append(risks, s.detectStalePRs(data.metrics.openPRs, input.Period.To)...)
+
+	// PR未経由の直接プッシュの検出
+	// パス指定時は first-parent 履歴を辿れないため行わない
+	if s.path == "" {
+		risks = append(risks, s.detectDirectPushes(commits, closedPRs)...)
+	}
+
+	// 期間内の活動がなければリスクが検出されず全カテゴリ満点になるため、
+	// 健全と誤読されないよう「データ不足」として扱う（前期比も出さない）
+	insufficientData := !hasActivity(commits, closedPRs, input.Period)
+
+	// 休眠リポジトリの検出（リリースもなければ、期間より前の最後の活動日を調べる）
+	var lastActivity time.Time
+	if insufficientData && !releasedInPeriod(data.metrics.releases, input.Period) {
+		lastActivity = s.lastActivity(ctx, input.Repository, input.Period, closedPRs, data.metrics.releases)
+		risks = append(risks, s.detectInactiveRepo(lastActivity, input.Period)...)
+	}
 
 	// 3. メトリクス計算
-	metrics := s.calculateMetrics(metricsInput{
-		commits:           commits,
-		contributors:      contributors,
-		closedPRs:         closedPRs,
-		openPRs:           openPRs,
-		allIssues:         allIssues,
-		openIssues:        openIssues,
-		files:             files,
-		releases:          releases,
-		period:            input.Period,
-		avgReviewWaitTime: avgReviewWaitTime,
-		avgPRSize:         avgPRSize,
-	})
+	metricsIn := data.metrics
+	metricsIn.avgReviewWaitTime = avgReviewWaitTime
+	metricsIn.avgFirstResponse = avgFirstResponse
+	metricsIn.avgPRSize = avgPRSize
+	metricsIn.reviewCoverage = reviewCoverage
+	metricsIn.reviewCoveragePRs = reviewCoveragePRs
+	metricsIn.selfMergeCount = selfMergeCount
+	metricsIn.selfMergeRate = selfMergeRate
+	metrics := s.calculateMetrics(metricsIn)
+	metrics.CommunityHealthScore = communityHealthScore(data.communityFiles)
+	metrics.TodoCount = data.todos.count
+	metrics.TodoDensity = data.todos.density()
+	metrics.TodoScannedFiles = data.todos.scanned
+	metrics.TodoScannedLines = data.todos.lines
+	dependencyEcosystems, outdatedDepRate := summarizeDependencies(data.dependencies)
+	metrics.DependencyCount = len(data.dependencies)
+	metrics.OutdatedDepRate = outdatedDepRate
+	metrics.VulnerableDepCount = len(data.vulnerableDeps)
+	metrics.VulnCheckedDeps = data.vulnChecked
+	s.calculateReleaseDebt(ctx, input.Repository, input.Period, data.metrics.releases, commits).apply(&metrics)
+	metrics.LargeNonSourceFileCount = len(largeNonSource)
+	metrics.TestFileCount = testFiles
+	metrics.SourceFileCount = sourceFiles
+	metrics.TestToSourceRatio = testToSourceRatio(testFiles, sourceFiles)
 
 	// 4. メトリクスベースのリスク検出
 	metricRisks := s.detectMetricRisks(metrics)
 	risks = append(risks, metricRisks...)
+	if s.coAuthorshipMitigation {
+		risks = s.mitigateOwnership(risks, metrics)
+	}
 
 	// 5. カテゴリ別スコア計算
 	categoryScores := s.calculateCategoryScores(risks)
 
 	// 5b. 総合スコア計算
-	overallScore := calculateOverallScore(categoryScores)
+	overallScore := calculateOverallScore(categoryScores, s.categoryWeights)
 
 	// 6. 日別コミット数を集計
 	dailyCommits := s.aggregateDailyCommits(commits, input.Period)
@@ -147,25 +615,57 @@ func (s *Service) Analyze(ctx context.Context, input ServiceInput) (*domain.Anal
 	// 7. ドリルダウンデータ構築
 	contributorDetails := s.buildContributorDetails(contributors)
 	hourlyCommits := s.aggregateHourlyCommits(commits)
+	weekdayHourCommits := s.aggregateWeekdayHourCommits(commits)
+
+	// 8. トレンド比較（データ不足なら出さない）
+	var trends []domain.TrendDelta
+	if !insufficientData {
+		trends = s.calculateTrends(metrics, data.prevCommits, data.prevIssues, data.prevMergedPRs, prevPeriod)
+	}
+
+	// PR詳細・最終活動日・リリース負債の取得はキャンセルされると途中で打ち切るため、結果を返さない
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
-	// 8. トレンド比較
-	trends := s.calculateTrends(metrics, prevCommits, prevIssues, prevPeriod)
+	s.log().Debug("analysis completed", "repo", input.Repository.FullName(),
+		"duration", time.Since(start).Round(time.Millisecond))
 
 	// 9. 結果を組み立て
 	return &domain.AnalysisResult{
-		Repository:         input.Repository,
-		Period:             input.Period,
-		CategoryScores:     categoryScores,
-		OverallScore:       overallScore,
-		Risks:              risks,
-		Metrics:            metrics,
-		DailyCommits:       dailyCommits,
-		LargeFiles:         largeFiles,
-		OutdatedDeps:       outdatedDeps,
-		PRDetails:          prDetails,
-		ContributorDetails: contributorDetails,
-		HourlyCommits:      hourlyCommits,
-		Trends:             trends,
-		GeneratedAt:        time.Now(),
+		Repository:           input.Repository,
+		Period:               input.Period,
+		Path:                 s.path,
+		Branch:               s.branch,
+		CategoryScores:       categoryScores,
+		CategoryWeights:      s.categoryWeights,
+		OverallScore:         overallScore,
+		Risks:                risks,
+		Metrics:              metrics,
+		DailyCommits:         dailyCommits,
+		LargeFiles:           largeFiles,
+		LargeNonSourceFiles:  topLargeFiles(largeNonSource, s.topFiles()),
+		HotFiles:             hotFiles,
+		SiloFiles:            topSiloFiles(siloFiles, s.topFiles()),
+		OutdatedDeps:         outdatedDeps,
+		DependencyEcosystems: dependencyEcosystems,
+		VulnerableDeps:       data.vulnerableDeps,
+		LicenseFile:          data.licenseFile,
+		TodoFiles:            topTodoFiles(data.todos.files, s.topFiles()),
+		CommunityFiles:       data.communityFiles,
+		BranchProtection:     data.protection,
+		PRDetails:            prDetails,
+		ContributorDetails:   contributorDetails,
+		HourlyCommits:        hourlyCommits,
+		WeekdayHourCommits:   weekdayHourCommits,
+		Trends:               trends,
+		Thresholds:           s.thresholds(),
+		DORABands:            s.doraRatingBands(),
+		InsufficientData:     insufficientData,
+		LastActivity:         lastActivity,
+		FilesTruncated:       data.filesTruncated,
+		QuickMode:            s.quick,
+		DataSources:          data.sources.list(),
+		GeneratedAt:          s.now(),
 	}, nil
 }
@@ -0,0 +1,1069 @@
+package analyze
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// mockRepository はテスト用の Repository 実装。
+// フィールドに設定したデータをそのまま返す。
+type mockRepository struct {
+	commits         []Commit
+	lastCommit      *Commit             // GetLastCommit が返すコミット（期間より前の最後のコミット）
+	commitFiles     map[string][]string // SHA → 変更ファイル
+	contributors    []Contributor
+	closedPRs       []PullRequest
+	openPRs         []PullRequest
+	issues          []Issue
+	issueEvents     map[int][]IssueEvent // Issue番号 → イベント（なければ not found）
+	files           []File
+	truncated       bool // GetFiles が一覧の切り詰めを返す
+	truncateCommits bool // GetCommits が MarkTruncated で一覧の打ち切りを記録する
+	dependencies    []Dependency
+	releases        []Release
+	tags            []Tag
+	workflowRuns    []WorkflowRun
+	protection      *domain.BranchProtection
+	forcePushes     []ForcePush
+	fileContents    map[string]string // パス → 内容（GetFileContent 用、なければ not found）
+	branches        []string          // 存在するブランチ（BranchExists 用）
+
+	// 並行実行の検証用
+	delay  time.Duration // 各データ取得で待つ時間（ctx のキャンセルで打ち切る）
+	failOn string        // このメソッド名の呼び出しで errMockFetch を返す
+
+	mu                sync.Mutex
+	commitDetailCalls int
+	calls             []string // 呼び出されたメソッド名（開始順）
+	inFlight          int
+	maxInFlight       int      // 同時に実行中だった呼び出し数の最大値
+	requestedBranches []string // GetCommits / GetFiles に渡されたブランチ
+}
+
+var errMockFetch = errors.New("mock fetch failed")
+
+// call は呼び出しを記録し、delay だけ待つ。
+// failOn に一致すれば即座に errMockFetch を、待機中にキャンセルされれば ctx.Err() を返す。
+func (m *mockRepository) call(ctx context.Context, name string) error {
+	m.mu.Lock()
+	m.calls = append(m.calls, name)
+	m.inFlight++
+	m.maxInFlight = max(m.maxInFlight, m.inFlight)
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		m.inFlight--
+		m.mu.Unlock()
+	}()
+
+	if name == m.failOn {
+		return errMockFetch
+	}
+	if m.delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(m.delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (m *mockRepository) GetCommits(ctx context.Context, _ domain.Repository, period domain.DateRange, path, branch string) ([]Commit, error) {
+	if err := m.call(ctx, "GetCommits"); err != nil {
+		return nil, err
+	}
+	m.recordBranch(branch)
+	if m.truncateCommits {
+		MarkTruncated(ctx)
+	}
+	var commits []Commit
+	for _, c := range m.commits {
+		if c.Date.Before(period.From) || c.Date.After(period.To) {
+			continue
+		}
+		if path != "" && !slices.ContainsFunc(m.commitFiles[c.SHA], func(f string) bool { return inPath(f, path) }) {
+			continue
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+func (m *mockRepository) GetLastCommit(ctx context.Context, _ domain.Repository, before time.Time, _, _ string) (*Commit, error) {
+	if err := m.call(ctx, "GetLastCommit"); err != nil {
+		return nil, err
+	}
+	if m.lastCommit == nil || m.lastCommit.Date.After(before) {
+		return nil, nil
+	}
+	return m.lastCommit, nil
+}
+
+func (m *mockRepository) BranchExists(ctx context.Context, _ domain.Repository, branch string) (bool, error) {
+	if err := m.call(ctx, "BranchExists"); err != nil {
+		return false, err
+	}
+	return slices.Contains(m.branches, branch), nil
+}
+
+// recordBranch は GetCommits / GetFiles に渡されたブランチを記録する。
+func (m *mockRepository) recordBranch(branch string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requestedBranches = append(m.requestedBranches, branch)
+}
+
+func (m *mockRepository) GetCommitDetail(_ context.Context, _ domain.Repository, sha string) (*Commit, error) {
+	m.mu.Lock()
+	m.commitDetailCalls++
+	m.mu.Unlock()
+
+	files, ok := m.commitFiles[sha]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &Commit{SHA: sha, Files: files}, nil
+}
+
+func (m *mockRepository) GetContributors(ctx context.Context, _ domain.Repository) ([]Contributor, error) {
+	if err := m.call(ctx, "GetContributors"); err != nil {
+		return nil, err
+	}
+	return m.contributors, nil
+}
+
+func (m *mockRepository) GetFileContent(ctx context.Context, _ domain.Repository, path string) ([]byte, error) {
+	if err := m.call(ctx, "GetFileContent"); err != nil {
+		return nil, err
+	}
+	if content, ok := m.fileContents[path]; ok {
+		return []byte(content), nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockRepository) GetPullRequests(ctx context.Context, _ domain.Repository, state string) ([]PullRequest, error) {
+	if err := m.call(ctx, "GetPullRequests"); err != nil {
+		return nil, err
+	}
+	if state == "open" {
+		return m.openPRs, nil
+	}
+	return m.closedPRs, nil
+}
+
+func (m *mockRepository) GetFiles(ctx context.Context, _ domain.Repository, branch string) ([]File, bool, error) {
+	if err := m.call(ctx, "GetFiles"); err != nil {
+		return nil, false, err
+	}
+	m.recordBranch(branch)
+	return m.files, m.truncated, nil
+}
+
+func (m *mockRepository) GetDependencies(ctx context.Context, _ domain.Repository) ([]Dependency, error) {
+	if err := m.call(ctx, "GetDependencies"); err != nil {
+		return nil, err
+	}
+	return m.dependencies, nil
+}
+
+func (m *mockRepository) GetIssues(ctx context.Context, _ domain.Repository, _ string, _ *time.Time) ([]Issue, error) {
+	if err := m.call(ctx, "GetIssues"); err != nil {
+		return nil, err
+	}
+	// 取得した Issue は分析側で書き換える（RecoveryStartedAt）ため、呼び出しごとに別のスライスを返す
+	return slices.Clone(m.issues), nil
+}
+
+func (m *mockRepository) GetIssueEvents(ctx context.Context, _ domain.Repository, issueNumber int) ([]IssueEvent, error) {
+	if err := m.call(ctx, "GetIssueEvents"); err != nil {
+		return nil, err
+	}
+	events, ok := m.issueEvents[issueNumber]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return events, nil
+}
+
+func (m *mockRepository) GetPRReviews(ctx context.Context, _ domain.Repository, _ int) ([]Review, error) {
+	if err := m.call(ctx, "GetPRReviews"); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) GetPRComments(ctx context.Context, _ domain.Repository, _ int) ([]Comment, error) {
+	if err := m.call(ctx, "GetPRComments"); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) GetPRDetail(ctx context.Context, _ domain.Repository, prNumber int) (*PullRequest, error) {
+	if err := m.call(ctx, "GetPRDetail"); err != nil {
+		return nil, err
+	}
+	for _, pr := range m.closedPRs {
+		if pr.Number == prNumber {
+			return &pr, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockRepository) GetReleases(ctx context.Context, _ domain.Repository) ([]Release, error) {
+	if err := m.call(ctx, "GetReleases"); err != nil {
+		return nil, err
+	}
+	return m.releases, nil
+}
+
+func (m *mockRepository) GetTags(ctx context.Context, _ domain.Repository) ([]Tag, error) {
+	if err := m.call(ctx, "GetTags"); err != nil {
+		return nil, err
+	}
+	return m.tags, nil
+}
+
+func (m *mockRepository) GetWorkflowRuns(ctx context.Context, _ domain.Repository, workflow string, _ domain.DateRange) ([]WorkflowRun, error) {
+	if err := m.call(ctx, "GetWorkflowRuns"); err != nil {
+		return nil, err
+	}
+	var runs []WorkflowRun
+	for _, r := range m.workflowRuns {
+		if r.Name == workflow {
+			runs = append(runs, r)
+		}
+	}
+	return runs, nil
+}
+
+func (m *mockRepository) GetBranchProtection(ctx context.Context, _ domain.Repository) (*domain.BranchProtection, error) {
+	if err := m.call(ctx, "GetBranchProtection"); err != nil {
+		return nil, err
+	}
+	return m.protection, nil
+}
+
+func (m *mockRepository) GetForcePushes(ctx context.Context, _ domain.Repository, _ domain.DateRange) ([]ForcePush, error) {
+	if err := m.call(ctx, "GetForcePushes"); err != nil {
+		return nil, err
+	}
+	return m.forcePushes, nil
+}
+
+func TestAnalyze_ChangeConcentrationFromCommitDetails(t *testing.T) {
+	base := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	// 一覧APIと同様に Files を持たないコミットを返し、詳細APIで変更ファイルを返す
+	repo := &mockRepository{commitFiles: make(map[string][]string)}
+	for i := range changeConcentrationCritical {
+		sha := fmt.Sprintf("sha%d", i)
+		repo.commits = append(repo.commits, Commit{
+			SHA:    sha,
+			Author: "alice",
+			Date:   base.Add(time.Duration(i) * time.Hour),
+		})
+		repo.commitFiles[sha] = []string{"src/hot.go"}
+	}
+
+	s := NewService(repo)
+	result, err := s.Analyze(context.Background(), ServiceInput{
+		Repository: domain.NewRepository("owner", "repo"),
+		Period: domain.NewDateRange(
+			time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		),
+	})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	var found bool
+	for _, r := range result.Risks {
+		if r.Type == domain.RiskTypeChangeConcentration && r.Target == "src/hot.go" {
+			found = true
+			if r.Severity != domain.SeverityHigh {
+				t.Errorf("Severity = %v, want High", r.Severity)
+			}
+		}
+	}
+	if !found {
+		t.Error("change concentration risk not detected from commit details")
+	}
+}
+
+func TestWithClock(t *testing.T) {
+	fixed := time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)
+	s := NewService(&mockRepository{}, WithClock(func() time.Time { return fixed }))
+
+	result, err := s.Analyze(context.Background(), ServiceInput{
+		Repository: domain.NewRepository("owner", "repo"),
+		Period:     domain.NewDateRange(fixed.AddDate(0, 0, -30), fixed),
+	})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if !result.GeneratedAt.Equal(fixed) {
+		t.Errorf("GeneratedAt = %s, want %s", result.GeneratedAt, fixed)
+	}
+
+	// Handler も同じ時刻を基準に期間を決める
+	result, err = NewHandler(s).Handle(context.Background(), Input{Owner: "owner", Repo: "repo", Days: 7})
+	if err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+	if want := domain.NewDateRange(fixed.AddDate(0, 0, -7), fixed); result.Period != want {
+		t.Errorf("Period = %+v, want %+v", result.Period, want)
+	}
+
+	// 未設定なら現在時刻
+	if got := (&Service{}).now(); time.Since(got) > time.Minute {
+		t.Errorf("now() = %s, want current time", got)
+	}
+}
+
+func TestAnalyze_WithPath(t *testing.T) {
+	base := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	repo := &mockRepository{
+		commits: []Commit{
+			{SHA: "a1", Author: "alice", Date: base},
+			{SHA: "a2", Author: "alice", Date: base.Add(time.Hour)},
+			{SHA: "b1", Author: "bob", Date: base.Add(2 * time.Hour)},
+		},
+		commitFiles: map[string][]string{
+			"a1": {"services/billing/main.go", "go.mod"},
+			"a2": {"services/billing/api.go"},
+			"b1": {"services/billing-v2/main.go"},
+		},
+		files: []File{
+			{Path: "services/billing/main.go", Size: 100},
+			{Path: "services/billing-v2/main.go", Size: 100},
+			{Path: "README.md", Size: 10},
+		},
+		closedPRs: []PullRequest{
+			{Number: 1, MergeSHA: "a2", CreatedAt: base, MergedAt: &base},
+			{Number: 2, MergeSHA: "b1", CreatedAt: base, MergedAt: &base},
+			{Number: 3, CreatedAt: base}, // 未マージ
+		},
+	}
+
+	s := NewService(repo, WithPath("./services/billing/"))
+	result, err := s.Analyze(context.Background(), ServiceInput{
+		Repository: domain.NewRepository("owner", "repo"),
+		Period:     domain.NewDateRange(base.AddDate(0, 0, -1), base.AddDate(0, 0, 1)),
+	})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	if result.Path != "services/billing" {
+		t.Errorf("Path = %q, want %q", result.Path, "services/billing")
+	}
+	if result.Metrics.TotalCommits != 2 {
+		t.Errorf("TotalCommits = %d, want 2", result.Metrics.TotalCommits)
+	}
+	if result.Metrics.TotalFiles != 1 {
+		t.Errorf("TotalFiles = %d, want 1", result.Metrics.TotalFiles)
+	}
+	if len(result.PRDetails) != 1 || result.PRDetails[0].Number != 1 {
+		t.Errorf("PRDetails = %+v, want only #1", result.PRDetails)
+	}
+}
+
+func TestAnalyze_QuickMode(t *testing.T) {
+	base := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	newRepo := func() *mockRepository {
+		return &mockRepository{
+			commits:      []Commit{{SHA: "a1", Author: "alice", Date: base}},
+			closedPRs:    []PullRequest{{Number: 1, Author: "alice", CreatedAt: base.AddDate(0, 0, -1), MergedAt: &base}},
+			dependencies: []Dependency{{Name: "left-pad", Version: "1.0.0", AgeMonths: 36, PackageType: "npm"}},
+		}
+	}
+	input := ServiceInput{
+		Repository: domain.NewRepository("owner", "repo"),
+		Period:     domain.NewDateRange(base.AddDate(0, 0, -7), base.AddDate(0, 0, 1)),
+	}
+	skipped := []string{"GetPRDetail", "GetPRReviews", "GetPRComments", "GetDependencies"}
+
+	t.Run("quick", func(t *testing.T) {
+		repo := newRepo()
+		result, err := NewService(repo, WithQuickMode(true)).Analyze(context.Background(), input)
+		if err != nil {
+			t.Fatalf("Analyze() error = %v", err)
+		}
+		for _, name := range skipped {
+			if slices.Contains(repo.calls, name) {
+				t.Errorf("%s was called in quick mode", name)
+			}
+		}
+		if !result.QuickMode {
+			t.Error("QuickMode = false, want true")
+		}
+		if len(result.PRDetails) != 0 || result.Metrics.DependencyCount != 0 || len(result.OutdatedDeps) != 0 {
+			t.Errorf("PRDetails = %v, DependencyCount = %d, OutdatedDeps = %v, want none",
+				result.PRDetails, result.Metrics.DependencyCount, result.OutdatedDeps)
+		}
+		// 安いメトリクスは通常どおり計算する
+		if result.Metrics.TotalCommits != 1 {
+			t.Errorf("TotalCommits = %d, want 1", result.Metrics.TotalCommits)
+		}
+	})
+
+	t.Run("default", func(t *testing.T) {
+		repo := newRepo()
+		result, err := NewService(repo).Analyze(context.Background(), input)
+		if err != nil {
+			t.Fatalf("Analyze() error = %v", err)
+		}
+		for _, name := range skipped {
+			if !slices.Contains(repo.calls, name) {
+				t.Errorf("%s was not called", name)
+			}
+		}
+		if result.QuickMode || len(result.PRDetails) != 1 || result.Metrics.DependencyCount != 1 {
+			t.Errorf("QuickMode = %v, PRDetails = %d, DependencyCount = %d, want false, 1, 1",
+				result.QuickMode, len(result.PRDetails), result.Metrics.DependencyCount)
+		}
+	})
+}
+
+func TestAnalyze_Drafts(t *testing.T) {
+	base := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	merged := func(days int) *time.Time {
+		t := base.AddDate(0, 0, days)
+		return &t
+	}
+	input := ServiceInput{
+		Repository: domain.NewRepository("owner", "repo"),
+		Period:     domain.NewDateRange(base.AddDate(0, 0, -7), base.AddDate(0, 0, 7)),
+	}
+	newRepo := func() *mockRepository {
+		return &mockRepository{
+			commits: []Commit{{SHA: "a1", Author: "alice", Date: base}},
+			closedPRs: []PullRequest{
+				{Number: 1, Author: "alice", HeadBranch: "feature/a", CreatedAt: base, MergedAt: merged(2)},
+				// マージ済みとして返るドラフト（API の状態が遅れている場合など）と、閉じたドラフト
+				{Number: 2, Author: "bob", HeadBranch: "fix/b", CreatedAt: base, MergedAt: merged(6), Draft: true},
+				{Number: 3, Author: "bob", CreatedAt: base, Draft: true},
+			},
+			openPRs: []PullRequest{
+				{Number: 4, Author: "alice", CreatedAt: base},
+				{Number: 5, Author: "bob", CreatedAt: base, Draft: true},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		opts          []Option
+		wantLeadTime  float64
+		wantFeature   int
+		wantBugFix    int
+		wantAbandoned int
+		wantOpen      int
+	}{
+		{name: "excluded by default", wantLeadTime: 2, wantFeature: 1, wantBugFix: 0, wantAbandoned: 0, wantOpen: 1},
+		{name: "included", opts: []Option{WithIncludeDrafts(true)}, wantLeadTime: 4, wantFeature: 1, wantBugFix: 1, wantAbandoned: 1, wantOpen: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewService(newRepo(), tt.opts...).Analyze(context.Background(), input)
+			if err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+			m := result.Metrics
+			if m.AvgLeadTime != tt.wantLeadTime {
+				t.Errorf("AvgLeadTime = %v, want %v", m.AvgLeadTime, tt.wantLeadTime)
+			}
+			if m.FeaturePRCount != tt.wantFeature || m.BugFixPRCount != tt.wantBugFix || m.AbandonedPRCount != tt.wantAbandoned {
+				t.Errorf("Feature/BugFix/Abandoned = %d/%d/%d, want %d/%d/%d",
+					m.FeaturePRCount, m.BugFixPRCount, m.AbandonedPRCount, tt.wantFeature, tt.wantBugFix, tt.wantAbandoned)
+			}
+			if m.OpenPRCount != tt.wantOpen {
+				t.Errorf("OpenPRCount = %d, want %d", m.OpenPRCount, tt.wantOpen)
+			}
+		})
+	}
+}
+
+func TestAnalyze_FilesTruncated(t *testing.T) {
+	base := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	input := ServiceInput{
+		Repository: domain.NewRepository("owner", "repo"),
+		Period:     domain.NewDateRange(base.AddDate(0, 0, -7), base.AddDate(0, 0, 1)),
+	}
+
+	for _, truncated := range []bool{false, true} {
+		repo := &mockRepository{
+			commits:   []Commit{{SHA: "a1", Author: "alice", Date: base}},
+			files:     []File{{Path: "main.go", Size: 100}},
+			truncated: truncated,
+		}
+		result, err := NewService(repo).Analyze(context.Background(), input)
+		if err != nil {
+			t.Fatalf("Analyze() error = %v", err)
+		}
+		if result.FilesTruncated != truncated {
+			t.Errorf("FilesTruncated = %v, want %v", result.FilesTruncated, truncated)
+		}
+	}
+}
+
+func TestAnalyze_InsufficientData(t *testing.T) {
+	base := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	period := domain.NewDateRange(base.AddDate(0, 0, -7), base)
+	mergedBefore := base.AddDate(0, 0, -30)
+	mergedIn := base.AddDate(0, 0, -1)
+
+	tests := []struct {
+		name string
+		repo *mockRepository
+		want bool
+	}{
+		{"no activity", &mockRepository{}, true},
+		{"only old merged PR", &mockRepository{closedPRs: []PullRequest{{Number: 1, CreatedAt: mergedBefore, MergedAt: &mergedBefore}}}, true},
+		{"commit in period", &mockRepository{commits: []Commit{{SHA: "a", Date: base.AddDate(0, 0, -2)}}}, false},
+		{"merged PR in period", &mockRepository{closedPRs: []PullRequest{{Number: 1, CreatedAt: mergedBefore, MergedAt: &mergedIn}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewService(tt.repo).Analyze(context.Background(), ServiceInput{
+				Repository: domain.NewRepository("owner", "repo"),
+				Period:     period,
+			})
+			if err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+			if result.InsufficientData != tt.want {
+				t.Errorf("InsufficientData = %v, want %v", result.InsufficientData, tt.want)
+			}
+			// 活動がなければ前期比は意味を持たないため出さない
+			if tt.want && len(result.Trends) != 0 {
+				t.Errorf("Trends = %v, want none", result.Trends)
+			}
+		})
+	}
+}
+
+func TestAnalyze_InactiveRepo(t *testing.T) {
+	base := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	period := domain.NewDateRange(base.AddDate(0, 0, -7), base)
+	oldCommit := base.AddDate(0, 0, -400)
+	oldMerge := base.AddDate(0, 0, -30)
+
+	tests := []struct {
+		name         string
+		repo         *mockRepository
+		wantLast     time.Time // ゼロ値なら休眠リスクなし
+		wantSeverity domain.Severity
+		wantLookup   bool // GetLastCommit を呼ぶか
+	}{
+		{"empty repository", &mockRepository{}, time.Time{}, 0, true},
+		{"old commit only", &mockRepository{lastCommit: &Commit{SHA: "a", Date: oldCommit}}, oldCommit, domain.SeverityHigh, true},
+		{"merged PR after last commit", &mockRepository{
+			lastCommit: &Commit{SHA: "a", Date: oldCommit},
+			closedPRs:  []PullRequest{{Number: 1, CreatedAt: oldMerge, MergedAt: &oldMerge}},
+		}, oldMerge, domain.SeverityMedium, true},
+		{"release in period", &mockRepository{
+			lastCommit: &Commit{SHA: "a", Date: oldCommit},
+			releases:   []Release{{TagName: "v1.0.0", PublishedAt: base.AddDate(0, 0, -1)}},
+		}, time.Time{}, 0, false},
+		{"commit in period", &mockRepository{commits: []Commit{{SHA: "b", Date: base.AddDate(0, 0, -2)}}}, time.Time{}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewService(tt.repo).Analyze(context.Background(), ServiceInput{
+				Repository: domain.NewRepository("owner", "repo"),
+				Period:     period,
+			})
+			if err != nil {
+				t.Fatalf("Analyze() error = %v", err)
+			}
+			if !result.LastActivity.Equal(tt.wantLast) {
+				t.Errorf("LastActivity = %v, want %v", result.LastActivity, tt.wantLast)
+			}
+			if got := slices.Contains(tt.repo.calls, "GetLastCommit"); got != tt.wantLookup {
+				t.Errorf("GetLastCommit called = %v, want %v", got, tt.wantLookup)
+			}
+
+			var inactive []domain.Risk
+			for _, r := range result.Risks {
+				if r.Type == domain.RiskTypeInactiveRepo {
+					inactive = append(inactive, r)
+				}
+			}
+			if tt.wantLast.IsZero() {
+				if len(inactive) != 0 {
+					t.Errorf("inactive repo risks = %v, want none", inactive)
+				}
+				return
+			}
+			if len(inactive) != 1 {
+				t.Fatalf("inactive repo risks = %d, want 1", len(inactive))
+			}
+			if inactive[0].Severity != tt.wantSeverity {
+				t.Errorf("Severity = %v, want %v", inactive[0].Severity, tt.wantSeverity)
+			}
+			if want := tt.wantLast.Format("2006-01-02"); inactive[0].Target != want {
+				t.Errorf("Target = %q, want %q", inactive[0].Target, want)
+			}
+		})
+	}
+}
+
+func TestAnalyze_Branch(t *testing.T) {
+	base := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	input := ServiceInput{
+		Repository: domain.NewRepository("owner", "repo"),
+		Period:     domain.NewDateRange(base.AddDate(0, 0, -7), base),
+	}
+
+	t.Run("exists", func(t *testing.T) {
+		repo := &mockRepository{
+			branches: []string{"develop"},
+			commits:  []Commit{{SHA: "a1", Author: "alice", Date: base.AddDate(0, 0, -1)}},
+		}
+		result, err := NewService(repo, WithBranch("develop")).Analyze(context.Background(), input)
+		if err != nil {
+			t.Fatalf("Analyze() error = %v", err)
+		}
+		if result.Branch != "develop" {
+			t.Errorf("Branch = %q, want develop", result.Branch)
+		}
+		// 今期・前期のコミットとファイル一覧はすべてブランチを指定して取得する
+		if len(repo.requestedBranches) != 3 {
+			t.Errorf("requested branches = %v, want 3 calls", repo.requestedBranches)
+		}
+		for _, b := range repo.requestedBranches {
+			if b != "develop" {
+				t.Errorf("requested branch = %q, want develop", b)
+			}
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repo := &mockRepository{branches: []string{"main"}}
+		_, err := NewService(repo, WithBranch("develop")).Analyze(context.Background(), input)
+		if err == nil || !strings.Contains(err.Error(), `branch "develop" not found in owner/repo`) {
+			t.Fatalf("Analyze() error = %v, want branch not found", err)
+		}
+		// 存在しないブランチでは何も取得しない
+		if slices.Contains(repo.calls, "GetCommits") {
+			t.Error("GetCommits was called for a missing branch")
+		}
+	})
+
+	t.Run("default branch", func(t *testing.T) {
+		repo := &mockRepository{}
+		if _, err := NewService(repo).Analyze(context.Background(), input); err != nil {
+			t.Fatalf("Analyze() error = %v", err)
+		}
+		if slices.Contains(repo.calls, "BranchExists") {
+			t.Error("BranchExists was called without --branch")
+		}
+	})
+}
+
+func TestAnalyze_WithTopFiles(t *testing.T) {
+	repo := &mockRepository{
+		files: []File{
+			{Path: "small.go", Size: 60 * 1024},
+			{Path: "huge.go", Size: 300 * 1024},
+			{Path: "big.go", Size: 120 * 1024},
+			{Path: "yarn.lock", Size: 800 * 1024},
+			{Path: "logo.png", Size: 90 * 1024},
+		},
+	}
+	s := NewService(repo, WithTopFiles(1))
+	result, err := s.Analyze(context.Background(), ServiceInput{
+		Repository: domain.NewRepository("owner", "repo"),
+		Period: domain.NewDateRange(
+			time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		),
+	})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	if len(result.LargeFiles) != 1 || result.LargeFiles[0].Path != "huge.go" {
+		t.Errorf("LargeFiles = %+v, want only huge.go", result.LargeFiles)
+	}
+	// バイナリ・生成物はリスクに数えず別枠（件数は全件、一覧は上位のみ）
+	if len(result.LargeNonSourceFiles) != 1 || result.LargeNonSourceFiles[0].Path != "yarn.lock" {
+		t.Errorf("LargeNonSourceFiles = %+v, want only yarn.lock", result.LargeNonSourceFiles)
+	}
+	if result.Metrics.LargeNonSourceFileCount != 2 {
+		t.Errorf("LargeNonSourceFileCount = %d, want 2", result.Metrics.LargeNonSourceFileCount)
+	}
+	// リスクの件数は一覧を絞る前の全件
+	total := 0
+	for _, r := range result.Risks {
+		if r.Type == domain.RiskTypeLargeFile {
+			total += r.Value
+		}
+	}
+	if total != 3 {
+		t.Errorf("large file risk count = %d, want 3", total)
+	}
+}
+
+func TestAnalyze_IgnoreFile(t *testing.T) {
+	newRepo := func() *mockRepository {
+		return &mockRepository{
+			files: []File{
+				{Path: "dist/bundle.js", Size: 500 * 1024},
+				{Path: "web/app.gen.js", Size: 200 * 1024},
+				{Path: "src/huge.go", Size: 150 * 1024},
+			},
+			fileContents: map[string]string{
+				".lokupignore": "# 生成物\ndist/\n**/*.gen.js\n",
+			},
+		}
+	}
+	period := domain.NewDateRange(
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+	)
+	largeFilePaths := func(t *testing.T, s *Service) []string {
+		t.Helper()
+		result, err := s.Analyze(context.Background(), ServiceInput{
+			Repository: domain.NewRepository("owner", "repo"),
+			Period:     period,
+		})
+		if err != nil {
+			t.Fatalf("Analyze() error = %v", err)
+		}
+		var paths []string
+		for _, lf := range result.LargeFiles {
+			paths = append(paths, lf.Path)
+		}
+		return paths
+	}
+
+	t.Run("repository .lokupignore", func(t *testing.T) {
+		got := largeFilePaths(t, NewService(newRepo()))
+		if !slices.Equal(got, []string{"src/huge.go"}) {
+			t.Errorf("LargeFiles = %v, want [src/huge.go]", got)
+		}
+	})
+
+	t.Run("local file overrides repository", func(t *testing.T) {
+		got := largeFilePaths(t, NewService(newRepo(), WithIgnoreFile("src/\n")))
+		if !slices.Equal(got, []string{"dist/bundle.js", "web/app.gen.js"}) {
+			t.Errorf("LargeFiles = %v, want [dist/bundle.js web/app.gen.js]", got)
+		}
+	})
+}
+
+// prDetailRepo は PR詳細・レビュー取得の同時実行数を記録するモック。
+// 完了順がばらつくよう、PR番号ごとに異なる遅延を入れる。
+type prDetailRepo struct {
+	*mockRepository
+	failDetail map[int]bool // GetPRDetail を失敗させるPR番号
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (r *prDetailRepo) track(ctx context.Context, prNumber int) error {
+	r.mu.Lock()
+	r.inFlight++
+	r.maxInFlight = max(r.maxInFlight, r.inFlight)
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		r.inFlight--
+		r.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(time.Duration(prNumber%4) * 5 * time.Millisecond):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *prDetailRepo) GetPRDetail(ctx context.Context, _ domain.Repository, prNumber int) (*PullRequest, error) {
+	if err := r.track(ctx, prNumber); err != nil {
+		return nil, err
+	}
+	if r.failDetail[prNumber] {
+		return nil, errMockFetch
+	}
+	return &PullRequest{Number: prNumber, Additions: prNumber * 10, Deletions: prNumber}, nil
+}
+
+func (r *prDetailRepo) GetPRReviews(ctx context.Context, _ domain.Repository, prNumber int) ([]Review, error) {
+	if err := r.track(ctx, prNumber); err != nil {
+		return nil, err
+	}
+	return []Review{{Author: "reviewer", State: "APPROVED"}}, nil
+}
+
+func TestBuildPRDetails_Concurrent(t *testing.T) {
+	merged := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	var prs []PullRequest
+	for n := 1; n <= 30; n++ {
+		prs = append(prs, PullRequest{Number: n, Author: "alice", MergedAt: &merged})
+	}
+	prs = append(prs, PullRequest{Number: 99}) // 未マージは対象外
+	repo := &prDetailRepo{mockRepository: &mockRepository{}, failDetail: map[int]bool{3: true}}
+	s := NewService(repo, WithPRDetailsConcurrency(3))
+
+	details := s.buildPRDetails(context.Background(), domain.NewRepository("o", "r"), prs)
+
+	// 先頭から maxPRDetailsCount 件（#1〜#20）を、PR番号の降順で返す
+	if len(details) != maxPRDetailsCount {
+		t.Fatalf("len = %d, want %d", len(details), maxPRDetailsCount)
+	}
+	for i, d := range details {
+		if want := maxPRDetailsCount - i; d.Number != want {
+			t.Errorf("details[%d].Number = %d, want %d", i, d.Number, want)
+		}
+	}
+	if repo.maxInFlight > 3 {
+		t.Errorf("maxInFlight = %d, want at most 3", repo.maxInFlight)
+	}
+	if repo.maxInFlight < 2 {
+		t.Errorf("maxInFlight = %d, want concurrent fetches", repo.maxInFlight)
+	}
+
+	// サイズの取得に失敗したPRも、レビュー情報付きで残す
+	failed := details[maxPRDetailsCount-3]
+	if failed.Number != 3 || failed.Size != 0 || !failed.ReviewsFetched || !failed.Reviewed {
+		t.Errorf("partially failed PR = %+v, want size 0 with reviews", failed)
+	}
+	if ok := details[0]; ok.Size != 220 || ok.Additions != 200 || ok.Deletions != 20 {
+		t.Errorf("details[0] = %+v, want size 220", ok)
+	}
+}
+
+func TestBuildPRDetails_Canceled(t *testing.T) {
+	merged := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	var prs []PullRequest
+	for n := 1; n <= 10; n++ {
+		prs = append(prs, PullRequest{Number: n, MergedAt: &merged})
+	}
+	repo := &prDetailRepo{mockRepository: &mockRepository{}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	details := NewService(repo).buildPRDetails(ctx, domain.NewRepository("o", "r"), prs)
+	if len(details) != 0 {
+		t.Errorf("len = %d, want 0 after cancellation", len(details))
+	}
+}
+
+func TestFillCommitFiles(t *testing.T) {
+	newRepo := func(n int) (*mockRepository, []Commit) {
+		repo := &mockRepository{commitFiles: make(map[string][]string)}
+		commits := make([]Commit, n)
+		for i := range commits {
+			sha := fmt.Sprintf("sha%d", i)
+			commits[i] = Commit{SHA: sha}
+			repo.commitFiles[sha] = []string{fmt.Sprintf("file%d.go", i)}
+		}
+		return repo, commits
+	}
+
+	t.Run("default limit", func(t *testing.T) {
+		repo, commits := newRepo(DefaultMaxCommitDetails + 10)
+		s := &Service{repo: repo}
+		s.fillCommitFiles(context.Background(), domain.NewRepository("o", "r"), commits)
+
+		if repo.commitDetailCalls != DefaultMaxCommitDetails {
+			t.Errorf("calls = %d, want %d", repo.commitDetailCalls, DefaultMaxCommitDetails)
+		}
+		if len(commits[0].Files) != 1 || commits[0].Files[0] != "file0.go" {
+			t.Errorf("commits[0].Files = %v", commits[0].Files)
+		}
+		// 上限を超えた古いコミットは取得しない
+		if len(commits[DefaultMaxCommitDetails].Files) != 0 {
+			t.Errorf("commit beyond limit has files: %v", commits[DefaultMaxCommitDetails].Files)
+		}
+	})
+
+	t.Run("custom limit covers all commits", func(t *testing.T) {
+		repo, commits := newRepo(150)
+		s := NewService(repo, WithMaxCommitDetails(1000))
+		s.fillCommitFiles(context.Background(), domain.NewRepository("o", "r"), commits)
+
+		for i, c := range commits {
+			if len(c.Files) != 1 {
+				t.Fatalf("commits[%d].Files = %v, want 1 file", i, c.Files)
+			}
+		}
+	})
+
+	t.Run("missing detail is skipped", func(t *testing.T) {
+		repo, commits := newRepo(3)
+		delete(repo.commitFiles, "sha1")
+		s := &Service{repo: repo}
+		s.fillCommitFiles(context.Background(), domain.NewRepository("o", "r"), commits)
+
+		if len(commits[1].Files) != 0 {
+			t.Errorf("commits[1].Files = %v, want empty", commits[1].Files)
+		}
+		if len(commits[2].Files) != 1 {
+			t.Errorf("commits[2].Files = %v, want 1 file", commits[2].Files)
+		}
+	})
+}
+
+func TestAnalyze_FetchesConcurrently(t *testing.T) {
+	repo := &mockRepository{delay: 50 * time.Millisecond}
+	s := NewService(repo)
+
+	start := time.Now()
+	_, err := s.Analyze(context.Background(), ServiceInput{
+		Repository: domain.NewRepository("owner", "repo"),
+		Period: domain.NewDateRange(
+			time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		),
+	})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	elapsed := time.Since(start)
+
+	// 全データ取得が呼ばれている
+	called := make(map[string]int)
+	for _, name := range repo.calls {
+		called[name]++
+	}
+	for name, want := range map[string]int{
+		"GetCommits":      2, // 今期 + 前期
+		"GetContributors": 1,
+		"GetPullRequests": 2, // closed + open
+		"GetIssues":       3, // 今期 all + open + 前期 all
+		"GetFiles":        1,
+		"GetDependencies": 1,
+		"GetReleases":     1,
+	} {
+		if called[name] != want {
+			t.Errorf("%s called %d times, want %d", name, called[name], want)
+		}
+	}
+
+	if repo.maxInFlight < 2 {
+		t.Errorf("maxInFlight = %d, want concurrent fetches", repo.maxInFlight)
+	}
+	// 直列なら 50ms × 呼び出し数（10回以上）かかる
+	if serial := time.Duration(len(repo.calls)) * repo.delay; elapsed >= serial {
+		t.Errorf("elapsed %s, want less than serial %s", elapsed, serial)
+	}
+}
+
+func TestAnalyze_FetchFailureCancelsSiblings(t *testing.T) {
+	repo := &mockRepository{delay: 5 * time.Second, failOn: "GetContributors"}
+	s := NewService(repo)
+
+	start := time.Now()
+	_, err := s.Analyze(context.Background(), ServiceInput{
+		Repository: domain.NewRepository("owner", "repo"),
+		Period: domain.NewDateRange(
+			time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		),
+	})
+	if !errors.Is(err, errMockFetch) {
+		t.Fatalf("Analyze() error = %v, want %v", err, errMockFetch)
+	}
+	// 他の取得は delay を待たずにキャンセルされる
+	if elapsed := time.Since(start); elapsed >= repo.delay {
+		t.Errorf("elapsed %s, siblings were not canceled", elapsed)
+	}
+}
+
+func TestAnalyze_Canceled(t *testing.T) {
+	repo := &mockRepository{delay: 5 * time.Second}
+	s := NewService(repo)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	result, err := s.Analyze(ctx, ServiceInput{
+		Repository: domain.NewRepository("owner", "repo"),
+		Period: domain.NewDateRange(
+			time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		),
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Analyze() error = %v, want %v", err, context.Canceled)
+	}
+	if result != nil {
+		t.Errorf("Analyze() result = %+v, want nil", result)
+	}
+	// 実行中の取得は delay を待たずに打ち切られる
+	if elapsed := time.Since(start); elapsed >= repo.delay {
+		t.Errorf("elapsed %s, fetches were not canceled", elapsed)
+	}
+}
+
+// cancelingRepository は依存関係の取得中に ctx をキャンセルする（任意の取得の途中で中断された状況）。
+type cancelingRepository struct {
+	*mockRepository
+	cancel context.CancelFunc
+}
+
+func (r *cancelingRepository) GetDependencies(ctx context.Context, repo domain.Repository) ([]Dependency, error) {
+	r.cancel()
+	return nil, ctx.Err()
+}
+
+func TestAnalyze_CanceledDuringOptionalFetch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := NewService(&cancelingRepository{mockRepository: &mockRepository{}, cancel: cancel})
+
+	// 任意の取得の失敗は致命的ではないが、キャンセルなら欠けたデータで結果を作らない
+	result, err := s.Analyze(ctx, ServiceInput{
+		Repository: domain.NewRepository("owner", "repo"),
+		Period: domain.NewDateRange(
+			time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		),
+	})
+	if !errors.Is(err, context.Canceled) || result != nil {
+		t.Errorf("Analyze() = %v, %v, want nil, %v", result, err, context.Canceled)
+	}
+}
+
+func TestAnalyze_OptionalFetchFailureIsNotFatal(t *testing.T) {
+	for _, name := range []string{"GetDependencies", "GetReleases", "GetFileContent"} {
+		t.Run(name, func(t *testing.T) {
+			repo := &mockRepository{failOn: name}
+			s := NewService(repo)
+			_, err := s.Analyze(context.Background(), ServiceInput{
+				Repository: domain.NewRepository("owner", "repo"),
+				Period: domain.NewDateRange(
+					time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+					time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+				),
+			})
+			if err != nil {
+				t.Errorf("Analyze() error = %v, want nil", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package analyze
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// DefaultTestFilePatterns はテストファイルとみなすパスのパターンのデフォルト（言語ごとの慣習）。
+var DefaultTestFilePatterns = []string{
+	// Go
+	"*_test.go",
+	// JavaScript / TypeScript（Jest・Vitest・Mocha など）
+	"*.test.js", "*.test.jsx", "*.test.ts", "*.test.tsx",
+	"*.spec.js", "*.spec.jsx", "*.spec.ts", "*.spec.tsx",
+	"__tests__/",
+	// Python（pytest）
+	"test_*.py", "*_test.py",
+	// Ruby（RSpec・Minitest）
+	"*_spec.rb", "*_test.rb",
+	// Java / Kotlin（Maven・Gradle の標準レイアウト）
+	"src/test/",
+}
+
+// testFilePatternMatch はパス（小文字）がテストファイルのパターン（小文字）に一致するかを返す。
+//
+// "/" で終わるパターンはディレクトリで、どの階層にあってもその配下のファイルに一致する（"src/test/" など）。
+// それ以外はファイル名に対するグロブ（path.Match の書式）として扱う。
+func testFilePatternMatch(pattern, filePath string) bool {
+	if strings.HasSuffix(pattern, "/") {
+		return strings.Contains("/"+path.Dir(filePath)+"/", "/"+pattern)
+	}
+	ok, _ := path.Match(pattern, path.Base(filePath))
+	return ok
+}
+
+// ValidateTestFilePatterns はテストファイルのパターンがグロブとして正しいかを検証する。
+func ValidateTestFilePatterns(patterns []string) error {
+	for i, p := range patterns {
+		if strings.HasSuffix(p, "/") {
+			continue
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("pattern at index %d (%q): %w", i, p, err)
+		}
+	}
+	return nil
+}
+
+// isTestFile はファイルがテストファイルかを返す。
+// パターン（WithTestFilePatterns、未設定ならデフォルト）で判定する。大文字小文字は区別しない。
+func (s *Service) isTestFile(filePath string) bool {
+	patterns := s.testFilePatterns
+	if patterns == nil {
+		patterns = DefaultTestFilePatterns
+	}
+	lower := strings.ToLower(filePath)
+	for _, p := range patterns {
+		if testFilePatternMatch(p, lower) {
+			return true
+		}
+	}
+	return false
+}
+
+// countTestFiles はソースコードのファイルをテストファイルとそれ以外に分けて数える。
+// ソースコードの拡張子でないファイル（ドキュメント・設定・テストのフィクスチャなど）と、
+// ソースコード以外（生成物・ベンダリングしたコード）は数えない。
+func (s *Service) countTestFiles(files []File) (tests, sources int) {
+	for _, f := range files {
+		if !sourceExtensions[strings.ToLower(path.Ext(f.Path))] || s.isNonSourceFile(f.Path) {
+			continue
+		}
+		if s.isTestFile(f.Path) {
+			tests++
+		} else {
+			sources++
+		}
+	}
+	return tests, sources
+}
+
+// testToSourceRatio はソースファイル1件あたりのテストファイル数を返す（ソースファイルがなければ 0）。
+func testToSourceRatio(tests, sources int) float64 {
+	if sources == 0 {
+		return 0
+	}
+	return float64(tests) / float64(sources)
+}
+
+// detectLowTestCoverage はソースファイルに対してテストファイルが少ないリスクを検出する。
+//
+// ファイル名の慣習による推定で、実際のカバレッジではない（テストが別リポジトリにある場合や、
+// 慣習に合わない名前のテストは数えられない）。ソースファイルが少ないリポジトリと、
+// ファイル一覧の一部しか取得できなかった場合は比率がぶれるため判定しない。
+func (s *Service) detectLowTestCoverage(tests, sources int, filesTruncated bool) []domain.Risk {
+	if sources < lowTestRatioMinSourceFiles || filesTruncated {
+		return nil
+	}
+	ratio := testToSourceRatio(tests, sources)
+	if ratio >= lowTestRatioWarning {
+		return nil
+	}
+	severity := domain.SeverityMedium
+	if ratio < lowTestRatioCritical {
+		severity = domain.SeverityHigh
+	}
+	return []domain.Risk{{
+		Type:     domain.RiskTypeLowTestCoverage,
+		Severity: severity,
+		Target:   s.lang.T("リポジトリ全体"),
+		Description: s.lang.T("ソースファイル%d件に対してテストファイルが%d件です（ファイル名からの推定）",
+			sources, tests),
+		Value:     int(ratio * 100),
+		Threshold: int(lowTestRatioWarning * 100),
+	}}
+}
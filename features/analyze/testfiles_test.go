@@ -0,0 +1,142 @@
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+func TestIsTestFile(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"pkg/server_test.go", true},
+		{"pkg/server.go", false},
+		{"web/App.test.tsx", true},
+		{"web/api.spec.ts", true},
+		{"web/__tests__/api.js", true},
+		{"web/tests.js", false},
+		{"tests/test_models.py", true},
+		{"app/models_test.py", true},
+		{"app/testing.py", false},
+		{"spec/user_spec.rb", true},
+		{"src/test/java/com/example/FooTest.java", true},
+		{"lib/src/test/Helper.kt", true},
+		{"src/main/java/com/example/Foo.java", false},
+		{"src/testdata/Foo.java", false},
+		{"PKG/SERVER_TEST.GO", true},
+	}
+	s := NewService(nil)
+	for _, tt := range tests {
+		if got := s.isTestFile(tt.path); got != tt.want {
+			t.Errorf("isTestFile(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	custom := NewService(nil, WithTestFilePatterns([]string{"*Test.java", "tests/"}))
+	for path, want := range map[string]bool{
+		"src/main/java/FooTest.java": true,
+		"tests/helpers.py":           true,
+		"pkg/server_test.go":         false, // デフォルトは置き換えられる
+	} {
+		if got := custom.isTestFile(path); got != want {
+			t.Errorf("custom isTestFile(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestValidateTestFilePatterns(t *testing.T) {
+	if err := ValidateTestFilePatterns(DefaultTestFilePatterns); err != nil {
+		t.Errorf("ValidateTestFilePatterns(defaults) error = %v", err)
+	}
+	if err := ValidateTestFilePatterns([]string{"*_test.go", "[a-"}); err == nil {
+		t.Error("ValidateTestFilePatterns() error = nil, want error for a malformed glob")
+	}
+}
+
+func TestCountTestFiles(t *testing.T) {
+	files := []File{
+		{Path: "main.go"},
+		{Path: "main_test.go"},
+		{Path: "web/app.ts"},
+		{Path: "web/app.spec.ts"},
+		{Path: "web/app.min.js"},               // 生成物
+		{Path: "vendor/lib/lib.go"},            // ベンダリング
+		{Path: "README.md"},                    // ソースコードではない
+		{Path: "src/test/resources/data.json"}, // テストのフィクスチャ
+	}
+	tests, sources := NewService(nil).countTestFiles(files)
+	if tests != 2 || sources != 2 {
+		t.Errorf("countTestFiles() = %d, %d, want 2, 2", tests, sources)
+	}
+}
+
+func TestDetectLowTestCoverage(t *testing.T) {
+	tests := []struct {
+		name      string
+		tests     int
+		sources   int
+		truncated bool
+		want      []domain.Severity
+	}{
+		{"enough tests", 10, 100, false, nil},
+		{"few tests", 5, 100, false, []domain.Severity{domain.SeverityMedium}},
+		{"almost no tests", 1, 100, false, []domain.Severity{domain.SeverityHigh}},
+		{"no tests", 0, 20, false, []domain.Severity{domain.SeverityHigh}},
+		{"too few source files", 0, 19, false, nil},
+		{"file list truncated", 0, 100, true, nil},
+	}
+	s := NewService(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			risks := s.detectLowTestCoverage(tt.tests, tt.sources, tt.truncated)
+			if len(risks) != len(tt.want) {
+				t.Fatalf("got %d risks, want %d", len(risks), len(tt.want))
+			}
+			for i, r := range risks {
+				if r.Type != domain.RiskTypeLowTestCoverage || r.Severity != tt.want[i] {
+					t.Errorf("risk = %s/%v, want %s/%v", r.Type, r.Severity, domain.RiskTypeLowTestCoverage, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyze_TestFileRatio(t *testing.T) {
+	var files []File
+	for i := range 40 {
+		files = append(files, File{Path: fmt.Sprintf("pkg/file%d.go", i), Size: 100})
+	}
+	files = append(files, File{Path: "pkg/file0_test.go", Size: 100})
+	repo := &mockRepository{files: files}
+
+	result, err := NewService(repo).Analyze(context.Background(), ServiceInput{
+		Repository: domain.NewRepository("owner", "repo"),
+		Period:     domain.NewDateRange(time.Now().AddDate(0, 0, -30), time.Now()),
+	})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if result.Metrics.TestFileCount != 1 || result.Metrics.SourceFileCount != 40 {
+		t.Errorf("test/source files = %d/%d, want 1/40", result.Metrics.TestFileCount, result.Metrics.SourceFileCount)
+	}
+	if got := result.Metrics.TestToSourceRatio; got != 0.025 {
+		t.Errorf("TestToSourceRatio = %v, want 0.025", got)
+	}
+	found := false
+	for _, r := range result.Risks {
+		if r.Type == domain.RiskTypeLowTestCoverage {
+			found = true
+			if r.Severity != domain.SeverityMedium {
+				t.Errorf("severity = %v, want Medium", r.Severity)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected RiskTypeLowTestCoverage")
+	}
+}
@@ -0,0 +1,77 @@
+package analyze
+
+import "github.com/ryuka-games/lokup/domain"
+
+// fixedThreshold は設定で変更できない閾値（値とデフォルトが同じ）を返す。
+func fixedThreshold(name, unit string, value float64) domain.Threshold {
+	return domain.Threshold{Name: name, Unit: unit, Value: value, Default: value}
+}
+
+// thresholds は判定に使った閾値の一覧を返す（レポートの判定基準に表示する）。
+//
+// 割合の閾値はパーセントに換算する。設定で変更できる閾値（滞留PRの日数・DORA レーティングの境界・重大度別の減点）は
+// 実際に使った値とデフォルト値を並べ、デフォルトのままかを読み手が判別できるようにする。
+// 共同作成による属人化の緩和の閾値は、緩和を有効にしたときだけ含める。
+func (s *Service) thresholds() []domain.Threshold {
+	penalties := s.severityPenalties()
+	bands := s.doraRatingBands()
+	thresholds := []domain.Threshold{
+		fixedThreshold("変更集中（Medium）", "回", changeConcentrationWarning),
+		fixedThreshold("変更集中（High）", "回", changeConcentrationCritical),
+		fixedThreshold("属人化（1人のコミット割合）", "%", ownershipThreshold*100),
+		fixedThreshold("ファイル単位の属人化（1人の変更割合）", "%", fileOwnershipShare*100),
+		fixedThreshold("深夜コミット率", "%", lateNightRateThreshold*100),
+		fixedThreshold("週末コミット率", "%", weekendRateThreshold*100),
+		fixedThreshold("巨大ファイル（Medium）", "KB", largeFileWarningBytes/1024),
+		fixedThreshold("巨大ファイル（High）", "KB", largeFileCriticalBytes/1024),
+		fixedThreshold("古い依存（Medium）", "か月", outdatedDepWarningMonths),
+		fixedThreshold("古い依存（High）", "か月", outdatedDepCriticalMonths),
+		fixedThreshold("TODO コメントの密度（Medium）", "件/1000行", todoDensityWarningPerKLOC),
+		fixedThreshold("TODO コメントの密度（High）", "件/1000行", todoDensityCriticalPerKLOC),
+		fixedThreshold("直接プッシュの割合（Medium）", "%", directPushRateWarning*100),
+		fixedThreshold("直接プッシュの割合（High）", "%", directPushRateCritical*100),
+		{Name: "滞留PRとみなす経過日数", Unit: "日", Value: float64(s.stalePRAge()), Default: DefaultStalePRDays},
+		fixedThreshold("滞留PRの件数（Medium）", "件", stalePRCountWarning),
+		fixedThreshold("滞留PRの件数（High）", "件", stalePRCountCritical),
+		fixedThreshold("放棄PRの割合（Medium）", "%", prAbandonmentWarningPct),
+		fixedThreshold("放棄PRの割合（High）", "%", prAbandonmentCriticalPct),
+		fixedThreshold("PRリードタイム", "日", leadTimeThresholdDays),
+		fixedThreshold("レビュー待ち時間", "時間", reviewWaitThresholdHours),
+		fixedThreshold("PRサイズ", "行", prSizeThresholdLines),
+		fixedThreshold("Issueクローズ率", "%", issueCloseRateThresholdPct),
+		fixedThreshold("バグ修正割合", "%", bugFixRatioThresholdPct),
+		fixedThreshold("レビューカバレッジ（Medium）", "%", reviewCoverageWarningPct),
+		fixedThreshold("レビューカバレッジ（High）", "%", reviewCoverageCriticalPct),
+		fixedThreshold("セルフマージの割合（Medium）", "%", selfMergeRateWarningPct),
+		fixedThreshold("セルフマージの割合（High）", "%", selfMergeRateCriticalPct),
+		fixedThreshold("低品質なコミットメッセージの割合（Medium）", "%", lowCommitQualityWarningPct),
+		fixedThreshold("低品質なコミットメッセージの割合（High）", "%", lowCommitQualityCriticalPct),
+		fixedThreshold("コミットあたりの平均変更ファイル数（Medium）", "ファイル/コミット", largeCommitWarningFiles),
+		fixedThreshold("コミットあたりの平均変更ファイル数（High）", "ファイル/コミット", largeCommitCriticalFiles),
+		fixedThreshold("ソースファイルに対するテストファイルの比率（Medium）", "%", lowTestRatioWarning*100),
+		fixedThreshold("ソースファイルに対するテストファイルの比率（High）", "%", lowTestRatioCritical*100),
+		fixedThreshold("デプロイ頻度", "回/月", deployFreqThresholdPerMonth),
+		fixedThreshold("変更失敗率", "%", changeFailureThresholdPct),
+		fixedThreshold("平均復旧時間（MTTR）", "時間", mttrThresholdHours),
+		{Name: "デプロイ頻度のDORAレーティング（Elite）", Unit: "回/月", Value: bands.DeployFrequency.Elite, Default: domain.DefaultDORARatingBands.DeployFrequency.Elite},
+		{Name: "デプロイ頻度のDORAレーティング（High）", Unit: "回/月", Value: bands.DeployFrequency.High, Default: domain.DefaultDORARatingBands.DeployFrequency.High},
+		{Name: "デプロイ頻度のDORAレーティング（Medium）", Unit: "回/月", Value: bands.DeployFrequency.Medium, Default: domain.DefaultDORARatingBands.DeployFrequency.Medium},
+		{Name: "変更失敗率のDORAレーティング（Elite）", Unit: "%", Value: bands.ChangeFailureRate.Elite, Default: domain.DefaultDORARatingBands.ChangeFailureRate.Elite},
+		{Name: "変更失敗率のDORAレーティング（High）", Unit: "%", Value: bands.ChangeFailureRate.High, Default: domain.DefaultDORARatingBands.ChangeFailureRate.High},
+		{Name: "変更失敗率のDORAレーティング（Medium）", Unit: "%", Value: bands.ChangeFailureRate.Medium, Default: domain.DefaultDORARatingBands.ChangeFailureRate.Medium},
+		{Name: "MTTRのDORAレーティング（Elite）", Unit: "時間", Value: bands.MTTR.Elite, Default: domain.DefaultDORARatingBands.MTTR.Elite},
+		{Name: "MTTRのDORAレーティング（High）", Unit: "時間", Value: bands.MTTR.High, Default: domain.DefaultDORARatingBands.MTTR.High},
+		{Name: "MTTRのDORAレーティング（Medium）", Unit: "時間", Value: bands.MTTR.Medium, Default: domain.DefaultDORARatingBands.MTTR.Medium},
+		fixedThreshold("機能追加の割合", "%", featureInvestmentThresholdPct),
+		fixedThreshold("リリース負債の経過日数（Medium）", "日", releaseDebtWarningDays),
+		fixedThreshold("リリース負債の経過日数（High）", "日", releaseDebtCriticalDays),
+		fixedThreshold("休眠リポジトリ（High）", "日", inactiveRepoCriticalDays),
+		{Name: "リスク1件の減点（High）", Unit: "点", Value: float64(penalties.High), Default: float64(DefaultSeverityPenalties.High)},
+		{Name: "リスク1件の減点（Medium）", Unit: "点", Value: float64(penalties.Medium), Default: float64(DefaultSeverityPenalties.Medium)},
+		{Name: "リスク1件の減点（Low）", Unit: "点", Value: float64(penalties.Low), Default: float64(DefaultSeverityPenalties.Low)},
+	}
+	if s.coAuthorshipMitigation {
+		thresholds = append(thresholds, fixedThreshold("属人化を緩和する共同作成のコミット率", "%", coAuthorshipMitigationPct))
+	}
+	return thresholds
+}
@@ -0,0 +1,74 @@
+package analyze
+
+import (
+	"testing"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// findThreshold は名前で閾値を探す（なければ ok = false）。
+func findThreshold(thresholds []domain.Threshold, name string) (domain.Threshold, bool) {
+	for _, th := range thresholds {
+		if th.Name == name {
+			return th, true
+		}
+	}
+	return domain.Threshold{}, false
+}
+
+func TestThresholds(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		thresholds := NewService(nil).thresholds()
+		for _, th := range thresholds {
+			if th.Custom() {
+				t.Errorf("%s: Value %v differs from Default %v", th.Name, th.Value, th.Default)
+			}
+		}
+		if th, _ := findThreshold(thresholds, "深夜コミット率"); th.Value != 30 || th.Unit != "%" {
+			t.Errorf("late-night threshold = %+v, want 30%%", th)
+		}
+		if th, _ := findThreshold(thresholds, "巨大ファイル（High）"); th.Value != 100 || th.Unit != "KB" {
+			t.Errorf("large file threshold = %+v, want 100KB", th)
+		}
+		if _, ok := findThreshold(thresholds, "属人化を緩和する共同作成のコミット率"); ok {
+			t.Error("co-authorship threshold listed while mitigation is disabled")
+		}
+	})
+
+	t.Run("custom config", func(t *testing.T) {
+		s := NewService(nil,
+			WithStalePRDays(14),
+			WithSeverityPenalties(SeverityPenalties{High: 20, Medium: 10, Low: 5}),
+			WithCoAuthorshipMitigation(true),
+			WithDORARatingBands(domain.DORARatingBands{
+				DeployFrequency:   domain.DORABands{Elite: 20, High: 4, Medium: 1},
+				ChangeFailureRate: domain.DefaultDORARatingBands.ChangeFailureRate,
+				MTTR:              domain.DefaultDORARatingBands.MTTR,
+			}),
+		)
+		thresholds := s.thresholds()
+
+		custom := map[string]float64{}
+		for _, th := range thresholds {
+			if th.Custom() {
+				custom[th.Name] = th.Value
+			}
+		}
+		want := map[string]float64{
+			"滞留PRとみなす経過日数":             14,
+			"リスク1件の減点（High）":           20,
+			"デプロイ頻度のDORAレーティング（Elite）": 20,
+		}
+		if len(custom) != len(want) {
+			t.Errorf("custom thresholds = %v, want %v", custom, want)
+		}
+		for name, v := range want {
+			if custom[name] != v {
+				t.Errorf("%s = %v, want %v", name, custom[name], v)
+			}
+		}
+		if _, ok := findThreshold(thresholds, "属人化を緩和する共同作成のコミット率"); !ok {
+			t.Error("co-authorship threshold missing while mitigation is enabled")
+		}
+	})
+}
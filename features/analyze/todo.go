@@ -0,0 +1,178 @@
+package analyze
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/shared/parallel"
+)
+
+// TODO コメントの走査の上限
+const (
+	// DefaultTodoScanMaxFiles は TODO コメントを数えるために内容を取得するファイル数のデフォルト。
+	DefaultTodoScanMaxFiles = 200
+	// これより大きいファイルは生成物や圧縮済みのことが多いため読まない
+	todoScanMaxFileBytes = 512 * 1024
+	// ファイル内容を同時に取得する数
+	todoScanConcurrency = 5
+)
+
+// todoMarkerPattern は技術的負債の目印になるコメントのマーカー。
+// 変数名などへの誤検出を避けるため、大文字の単語としてだけ数える。
+var todoMarkerPattern = regexp.MustCompile(`\b(TODO|FIXME|HACK)\b`)
+
+// sourceExtensions はソースコード・スクリプトの拡張子（TODO コメントの走査とテストファイルの比率の対象）。
+var sourceExtensions = map[string]bool{
+	".go": true, ".js": true, ".jsx": true, ".ts": true, ".tsx": true, ".mjs": true, ".cjs": true,
+	".vue": true, ".svelte": true, ".py": true, ".rb": true, ".php": true, ".java": true, ".kt": true,
+	".kts": true, ".scala": true, ".groovy": true, ".cs": true, ".fs": true, ".vb": true, ".rs": true,
+	".c": true, ".h": true, ".cc": true, ".cpp": true, ".hpp": true, ".m": true, ".mm": true,
+	".swift": true, ".dart": true, ".ex": true, ".exs": true, ".erl": true, ".hs": true, ".clj": true,
+	".lua": true, ".pl": true, ".r": true, ".sh": true, ".bash": true, ".ps1": true, ".sql": true,
+}
+
+// todoScan は TODO コメントの走査結果。
+type todoScan struct {
+	files   []domain.TodoFile // マーカーのあるファイル（件数の多い順）
+	count   int               // マーカーのある行数の合計
+	lines   int               // 走査した行数（空行を除く）
+	scanned int               // 内容を取得できたファイル数
+	targets int               // 走査の対象にしたファイル数
+}
+
+// density は1000行あたりのマーカー数を返す（走査した行がなければ 0）。
+func (t todoScan) density() float64 {
+	if t.lines == 0 {
+		return 0
+	}
+	return float64(t.count) / float64(t.lines) * 1000
+}
+
+// todoScanTargets は内容を取得するファイルを選ぶ。
+// ソースコードの拡張子で、大きすぎないものを大きい順に maxFiles 件まで選ぶ
+// （同じ取得回数でより多くの行を見られるため）。同じサイズはパス順。
+func todoScanTargets(files []File, maxFiles int) []File {
+	var targets []File
+	for _, f := range files {
+		if f.Size <= 0 || f.Size > todoScanMaxFileBytes {
+			continue
+		}
+		if !sourceExtensions[strings.ToLower(path.Ext(f.Path))] {
+			continue
+		}
+		targets = append(targets, f)
+	}
+	slices.SortFunc(targets, func(a, b File) int {
+		if c := cmp.Compare(b.Size, a.Size); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Path, b.Path)
+	})
+	if len(targets) > maxFiles {
+		targets = targets[:maxFiles]
+	}
+	return targets
+}
+
+// countTodoMarkers はマーカーを含む行数と、空行を除いた行数を返す。
+func countTodoMarkers(content []byte) (markers, lines int) {
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	sc.Buffer(make([]byte, 0, 64*1024), todoScanMaxFileBytes)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		lines++
+		if todoMarkerPattern.Match(line) {
+			markers++
+		}
+	}
+	return markers, lines
+}
+
+// scanTodos はソースファイルの内容を取得して TODO / FIXME / HACK コメントを数える。
+// 取得に失敗したファイルは数えない。ctx がキャンセルされたら未着手のファイルは取得しない。
+func (s *Service) scanTodos(ctx context.Context, repo domain.Repository, files []File) todoScan {
+	targets := todoScanTargets(files, s.todoScanMaxFiles)
+	if len(targets) == 0 {
+		return todoScan{}
+	}
+
+	type fileResult struct {
+		ok             bool
+		markers, lines int
+	}
+	results := make([]fileResult, len(targets))
+	progress := s.startProgress(repo, PhaseTodoScan, len(targets))
+
+	parallel.ForEach(ctx, todoScanConcurrency, len(targets), func(i int) {
+		content, err := s.repo.GetFileContent(ctx, repo, targets[i].Path)
+		progress.step()
+		if err != nil {
+			return
+		}
+		markers, lines := countTodoMarkers(content)
+		results[i] = fileResult{ok: true, markers: markers, lines: lines}
+	})
+
+	scan := todoScan{targets: len(targets)}
+	for i, r := range results {
+		if !r.ok {
+			continue
+		}
+		scan.scanned++
+		scan.lines += r.lines
+		scan.count += r.markers
+		if r.markers > 0 {
+			scan.files = append(scan.files, domain.TodoFile{Path: targets[i].Path, Count: r.markers})
+		}
+	}
+	slices.SortFunc(scan.files, func(a, b domain.TodoFile) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Path, b.Path)
+	})
+	return scan
+}
+
+// topTodoFiles は件数の多い順に並んだ一覧の先頭 n 件を返す。
+func topTodoFiles(files []domain.TodoFile, n int) []domain.TodoFile {
+	if len(files) > n {
+		return files[:n]
+	}
+	return files
+}
+
+// detectHighTodoDensity は1000行あたりの TODO コメントが多ければリスクとして返す。
+// 走査した行が少ないと数件で密度が跳ねるため、最小行数に満たなければ判定しない。
+func (s *Service) detectHighTodoDensity(scan todoScan) []domain.Risk {
+	if scan.lines < todoDensityMinLines {
+		return nil
+	}
+	density := scan.density()
+	if density <= todoDensityWarningPerKLOC {
+		return nil
+	}
+	severity := domain.SeverityMedium
+	if density > todoDensityCriticalPerKLOC {
+		severity = domain.SeverityHigh
+	}
+	return []domain.Risk{{
+		Type:     domain.RiskTypeHighTodoDensity,
+		Severity: severity,
+		Target:   s.lang.T("リポジトリ全体"),
+		Description: s.lang.T("TODO / FIXME / HACK コメントが%d件（1000行あたり%.1f件）あります",
+			scan.count, density),
+		Value:     int(density * 10),
+		Threshold: int(todoDensityWarningPerKLOC),
+	}}
+}
@@ -0,0 +1,112 @@
+package analyze
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/shared/i18n"
+)
+
+func TestCountTodoMarkers(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantMarkers int
+		wantLines   int
+	}{
+		{"empty", "", 0, 0},
+		{"markers", "// TODO: fix\nx := 1\n# FIXME later\n/* HACK */\n", 3, 4},
+		{"blank lines are not counted", "a\n\n   \nb\n", 0, 2},
+		{"one line with two markers counts once", "// TODO FIXME\n", 1, 1},
+		{"lowercase and identifiers are ignored", "// todo: later\ntodoList := nil\nTODOS := 1\nHACKER := 2\n", 0, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			markers, lines := countTodoMarkers([]byte(tt.content))
+			if markers != tt.wantMarkers || lines != tt.wantLines {
+				t.Errorf("countTodoMarkers() = %d, %d, want %d, %d", markers, lines, tt.wantMarkers, tt.wantLines)
+			}
+		})
+	}
+}
+
+func TestTodoScanTargets(t *testing.T) {
+	files := []File{
+		{Path: "main.go", Size: 2000},
+		{Path: "README.md", Size: 5000},
+		{Path: "web/app.TS", Size: 3000},
+		{Path: "dist/bundle.js", Size: todoScanMaxFileBytes + 1},
+		{Path: "empty.py", Size: 0},
+		{Path: "b.rb", Size: 1000},
+		{Path: "a.rb", Size: 1000},
+	}
+	got := todoScanTargets(files, 3)
+	var paths []string
+	for _, f := range got {
+		paths = append(paths, f.Path)
+	}
+	want := []string{"web/app.TS", "main.go", "a.rb"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("todoScanTargets() = %v, want %v", paths, want)
+	}
+}
+
+func TestScanTodos(t *testing.T) {
+	repo := &mockRepository{fileContents: map[string]string{
+		"a.go": "// TODO one\n// TODO two\ncode\n",
+		"b.go": "code\n// FIXME\n",
+		"c.go": "clean\n",
+		// d.go は取得に失敗する（fileContents にない）
+	}}
+	files := []File{{Path: "a.go", Size: 30}, {Path: "b.go", Size: 20}, {Path: "c.go", Size: 10}, {Path: "d.go", Size: 5}}
+
+	s := NewService(repo, WithTodoScan(10))
+	got := s.scanTodos(context.Background(), domain.NewRepository("o", "r"), files)
+
+	if got.scanned != 3 || got.count != 3 || got.lines != 6 {
+		t.Errorf("scanned/count/lines = %d/%d/%d, want 3/3/6", got.scanned, got.count, got.lines)
+	}
+	wantFiles := []domain.TodoFile{{Path: "a.go", Count: 2}, {Path: "b.go", Count: 1}}
+	if !reflect.DeepEqual(got.files, wantFiles) {
+		t.Errorf("files = %+v, want %+v", got.files, wantFiles)
+	}
+}
+
+func TestDetectHighTodoDensity(t *testing.T) {
+	tests := []struct {
+		name         string
+		scan         todoScan
+		wantRisk     bool
+		wantSeverity domain.Severity
+	}{
+		{"not scanned", todoScan{}, false, 0},
+		{"too few lines", todoScan{count: 50, lines: 500}, false, 0},
+		{"at warning threshold", todoScan{count: 10, lines: 2000}, false, 0},
+		{"above warning", todoScan{count: 12, lines: 2000}, true, domain.SeverityMedium},
+		{"above critical", todoScan{count: 25, lines: 2000}, true, domain.SeverityHigh},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			risks := (&Service{}).detectHighTodoDensity(tt.scan)
+			if (len(risks) > 0) != tt.wantRisk {
+				t.Fatalf("risks = %v, want risk %v", risks, tt.wantRisk)
+			}
+			if !tt.wantRisk {
+				return
+			}
+			r := risks[0]
+			if r.Type != domain.RiskTypeHighTodoDensity || r.Type.Category() != domain.CategoryTechDebt {
+				t.Errorf("type = %s (%s), want high_todo_density in tech_debt", r.Type, r.Type.Category())
+			}
+			if r.Severity != tt.wantSeverity {
+				t.Errorf("severity = %v, want %v", r.Severity, tt.wantSeverity)
+			}
+			if detail := formatRiskDetail(r, i18n.Japanese); !strings.Contains(detail, "1000行あたり") {
+				t.Errorf("detail = %q", detail)
+			}
+		})
+	}
+}
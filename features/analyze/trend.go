@@ -9,12 +9,13 @@ import (
 // ── トレンド比較 ─────────────────────────────────────────────
 
 // calculateTrends は今期と前期のメトリクスを比較してトレンドを算出する。
-func (s *Service) calculateTrends(current domain.Metrics, prevCommits []Commit, prevIssues []Issue, prevPeriod domain.DateRange) []domain.TrendDelta {
+// prevMergedPRs は前期にマージされたPR。
+func (s *Service) calculateTrends(current domain.Metrics, prevCommits []Commit, prevIssues []Issue, prevMergedPRs []PullRequest, prevPeriod domain.DateRange) []domain.TrendDelta {
 	var trends []domain.TrendDelta
 
 	// コミット数トレンド
 	prevCommitCount := len(prevCommits)
-	trends = append(trends, buildTrendDelta("コミット数", float64(current.TotalCommits), float64(prevCommitCount)))
+	trends = append(trends, buildTrendDelta(s.lang.T("コミット数"), float64(current.TotalCommits), float64(prevCommitCount)))
 
 	// コミット頻度トレンド
 	prevDays := prevPeriod.Days()
@@ -22,11 +23,14 @@ func (s *Service) calculateTrends(current domain.Metrics, prevCommits []Commit,
 		prevDays = 1
 	}
 	prevRate := float64(prevCommitCount) / float64(prevDays)
-	trends = append(trends, buildTrendDelta("コミット頻度", current.FeatureAdditionRate, prevRate))
+	trends = append(trends, buildTrendDelta(s.lang.T("コミット頻度"), current.FeatureAdditionRate, prevRate))
+
+	// PRスループットトレンド
+	trends = append(trends, buildTrendDelta(s.lang.T("PRスループット"), current.PRThroughputPerWeek, perWeek(len(prevMergedPRs), prevPeriod.Days())))
 
 	// Issueクローズ率トレンド
 	prevIS := (&Service{}).calculateIssueStats(prevIssues, prevPeriod)
-	trends = append(trends, buildTrendDelta("Issueクローズ率", current.IssueCloseRate, prevIS.CloseRate))
+	trends = append(trends, buildTrendDelta(s.lang.T("Issueクローズ率"), current.IssueCloseRate, prevIS.CloseRate))
 
 	return trends
 }
@@ -1,7 +1,11 @@
 package analyze
 
 import (
+	"context"
 	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
 )
 
 func TestBuildTrendDelta(t *testing.T) {
@@ -51,3 +55,45 @@ func TestBuildTrendDelta(t *testing.T) {
 		})
 	}
 }
+
+func TestAnalyze_PRThroughputTrend(t *testing.T) {
+	at := func(year int, month time.Month, day int) *time.Time {
+		t := time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+		return &t
+	}
+	repo := &mockRepository{
+		commits: []Commit{{SHA: "a", Date: *at(2025, 1, 20)}},
+		closedPRs: []PullRequest{
+			// 今期（1/1〜1/29）4件、前期（12/3〜12/31）2件
+			{Number: 1, MergedAt: at(2025, 1, 5)},
+			{Number: 2, MergedAt: at(2025, 1, 12)},
+			{Number: 3, MergedAt: at(2025, 1, 19)},
+			{Number: 4, MergedAt: at(2025, 1, 26)},
+			{Number: 5, MergedAt: at(2024, 12, 10)},
+			{Number: 6, MergedAt: at(2024, 12, 20)},
+		},
+	}
+	result, err := NewService(repo).Analyze(context.Background(), ServiceInput{
+		Repository: domain.NewRepository("owner", "repo"),
+		Period: domain.NewDateRange(
+			time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2025, 1, 29, 0, 0, 0, 0, time.UTC),
+		),
+	})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	var trend *domain.TrendDelta
+	for i := range result.Trends {
+		if result.Trends[i].MetricName == "PRスループット" {
+			trend = &result.Trends[i]
+		}
+	}
+	if trend == nil {
+		t.Fatalf("no PR throughput trend in %+v", result.Trends)
+	}
+	if trend.CurrentValue != 1 || trend.PreviousValue != 0.5 || trend.Direction != "up" {
+		t.Errorf("trend = %+v, want 1/week vs 0.5/week (up)", *trend)
+	}
+}
@@ -0,0 +1,77 @@
+package analyze
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// vulnerableDepsInDescription はリスクの説明に名前を挙げる依存の数。残りは「他N件」にまとめる。
+const vulnerableDepsInDescription = 5
+
+// VulnerabilityChecker は依存の既知の脆弱性を調べる。
+// infrastructure/github パッケージ（OSV API）で実装される。
+type VulnerabilityChecker interface {
+	// CheckVulnerabilities は deps と同じ順で、各依存に影響する脆弱性の ID を返す。
+	// 脆弱性がない・調べられない依存は空にする。
+	CheckVulnerabilities(ctx context.Context, deps []Dependency) ([][]string, error)
+}
+
+// WithVulnerabilityCheck は依存の既知の脆弱性を checker で調べる（nil なら調べない）。
+// リリースからの経過月数だけでは安全性を判断できないため、脆弱性データベースで裏付ける。
+// 依存の数に応じた外部 API の呼び出しが増えるため、明示的に指定したときだけ有効にする。
+func WithVulnerabilityCheck(checker VulnerabilityChecker) Option {
+	return func(s *Service) {
+		s.vulnChecker = checker
+	}
+}
+
+// vulnerableDependencies は CheckVulnerabilities の結果から、脆弱性のある依存を名前順に返す。
+func vulnerableDependencies(deps []Dependency, ids [][]string) []domain.VulnerableDep {
+	var vulnerable []domain.VulnerableDep
+	for i, dep := range deps {
+		if i >= len(ids) || len(ids[i]) == 0 {
+			continue
+		}
+		vulnerable = append(vulnerable, domain.VulnerableDep{
+			Name:      dep.Name,
+			Version:   dep.Version,
+			Ecosystem: dep.PackageType,
+			IDs:       ids[i],
+		})
+	}
+	sort.SliceStable(vulnerable, func(i, j int) bool {
+		if vulnerable[i].Name != vulnerable[j].Name {
+			return vulnerable[i].Name < vulnerable[j].Name
+		}
+		return vulnerable[i].Version < vulnerable[j].Version
+	})
+	return vulnerable
+}
+
+// detectVulnerableDeps は既知の脆弱性がある依存を1つのリスク（High）にまとめる。
+// 説明には影響する依存を先頭から数件挙げ、脆弱性の ID はドリルダウンの一覧に出す。
+func (s *Service) detectVulnerableDeps(vulnerable []domain.VulnerableDep) []domain.Risk {
+	if len(vulnerable) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, vulnerableDepsInDescription)
+	for _, v := range vulnerable[:min(len(vulnerable), vulnerableDepsInDescription)] {
+		names = append(names, v.Name+"@"+v.Version)
+	}
+	list := strings.Join(names, ", ")
+	if rest := len(vulnerable) - len(names); rest > 0 {
+		list += s.lang.T(" 他%d件", rest)
+	}
+
+	return []domain.Risk{{
+		Type:        domain.RiskTypeVulnerableDependency,
+		Severity:    domain.SeverityHigh,
+		Target:      s.lang.T("%d件", len(vulnerable)),
+		Description: s.lang.T("既知の脆弱性がある依存があります: %s", list),
+		Value:       len(vulnerable),
+	}}
+}
@@ -0,0 +1,142 @@
+package analyze
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/shared/i18n"
+)
+
+// fakeVulnChecker は依存名 → 脆弱性 ID の表で答える VulnerabilityChecker。
+type fakeVulnChecker struct {
+	vulns map[string][]string
+	err   error
+	calls int
+}
+
+func (f *fakeVulnChecker) CheckVulnerabilities(_ context.Context, deps []Dependency) ([][]string, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	ids := make([][]string, len(deps))
+	for i, d := range deps {
+		ids[i] = f.vulns[d.Name]
+	}
+	return ids, nil
+}
+
+func TestVulnerableDependencies(t *testing.T) {
+	deps := []Dependency{
+		{Name: "react", Version: "18.2.0", PackageType: "npm"},
+		{Name: "lodash", Version: "4.17.20", PackageType: "npm"},
+		{Name: "golang.org/x/net", Version: "0.1.0", PackageType: "go"},
+	}
+	ids := [][]string{nil, {"GHSA-35jh-r3h4-6jhm"}, {"GO-2023-1571", "GHSA-vvpx-j8f3-3w6h"}}
+
+	got := vulnerableDependencies(deps, ids)
+	want := []domain.VulnerableDep{
+		{Name: "golang.org/x/net", Version: "0.1.0", Ecosystem: "go", IDs: []string{"GO-2023-1571", "GHSA-vvpx-j8f3-3w6h"}},
+		{Name: "lodash", Version: "4.17.20", Ecosystem: "npm", IDs: []string{"GHSA-35jh-r3h4-6jhm"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("vulnerableDependencies() = %+v, want %+v", got, want)
+	}
+
+	if got := vulnerableDependencies(deps, nil); got != nil {
+		t.Errorf("vulnerableDependencies(nil ids) = %+v, want nil", got)
+	}
+}
+
+func TestDetectVulnerableDeps(t *testing.T) {
+	s := &Service{}
+
+	if risks := s.detectVulnerableDeps(nil); len(risks) != 0 {
+		t.Errorf("risks = %+v, want none", risks)
+	}
+
+	var vulnerable []domain.VulnerableDep
+	for i := range 7 {
+		vulnerable = append(vulnerable, domain.VulnerableDep{Name: fmt.Sprintf("pkg%d", i), Version: "1.0.0", IDs: []string{"GHSA-x"}})
+	}
+	risks := s.detectVulnerableDeps(vulnerable)
+	if len(risks) != 1 {
+		t.Fatalf("risks = %d, want 1", len(risks))
+	}
+	r := risks[0]
+	if r.Type != domain.RiskTypeVulnerableDependency || r.Severity != domain.SeverityHigh || r.Value != 7 {
+		t.Errorf("risk = %+v, want high vulnerable_dependency with value 7", r)
+	}
+	// 説明には先頭の5件だけを挙げる
+	if !strings.Contains(r.Description, "pkg0@1.0.0, pkg1@1.0.0, pkg2@1.0.0, pkg3@1.0.0, pkg4@1.0.0 他2件") {
+		t.Errorf("Description = %q", r.Description)
+	}
+	if got := formatRiskDetail(r, i18n.Japanese); got != "7件、既知の脆弱性あり" {
+		t.Errorf("formatRiskDetail() = %q", got)
+	}
+}
+
+func TestAnalyze_VulnerabilityCheck(t *testing.T) {
+	period := domain.NewDateRange(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC))
+	newRepo := func() *mockRepository {
+		return &mockRepository{dependencies: []Dependency{
+			{Name: "lodash", Version: "4.17.20", PackageType: "npm"},
+			{Name: "react", Version: "18.2.0", PackageType: "npm"},
+		}}
+	}
+	hasRisk := func(result *domain.AnalysisResult) bool {
+		for _, r := range result.Risks {
+			if r.Type == domain.RiskTypeVulnerableDependency {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("vulnerable dependency raises risk", func(t *testing.T) {
+		checker := &fakeVulnChecker{vulns: map[string][]string{"lodash": {"GHSA-35jh-r3h4-6jhm"}}}
+		s := NewService(newRepo(), WithVulnerabilityCheck(checker))
+		result, err := s.Analyze(context.Background(), ServiceInput{Repository: domain.NewRepository("o", "r"), Period: period})
+		if err != nil {
+			t.Fatalf("Analyze() error = %v", err)
+		}
+		if len(result.VulnerableDeps) != 1 || result.VulnerableDeps[0].Name != "lodash" {
+			t.Errorf("VulnerableDeps = %+v, want lodash", result.VulnerableDeps)
+		}
+		if result.Metrics.VulnerableDepCount != 1 || result.Metrics.VulnCheckedDeps != 2 {
+			t.Errorf("VulnerableDepCount = %d, VulnCheckedDeps = %d, want 1, 2", result.Metrics.VulnerableDepCount, result.Metrics.VulnCheckedDeps)
+		}
+		if !hasRisk(result) {
+			t.Error("expected RiskTypeVulnerableDependency")
+		}
+	})
+
+	t.Run("not checked without option", func(t *testing.T) {
+		s := NewService(newRepo())
+		result, err := s.Analyze(context.Background(), ServiceInput{Repository: domain.NewRepository("o", "r"), Period: period})
+		if err != nil {
+			t.Fatalf("Analyze() error = %v", err)
+		}
+		if result.Metrics.VulnCheckedDeps != 0 || len(result.VulnerableDeps) != 0 || hasRisk(result) {
+			t.Errorf("checked without WithVulnerabilityCheck: %+v", result.VulnerableDeps)
+		}
+	})
+
+	t.Run("check failure does not fail analysis", func(t *testing.T) {
+		checker := &fakeVulnChecker{err: errors.New("osv down")}
+		s := NewService(newRepo(), WithVulnerabilityCheck(checker))
+		result, err := s.Analyze(context.Background(), ServiceInput{Repository: domain.NewRepository("o", "r"), Period: period})
+		if err != nil {
+			t.Fatalf("Analyze() error = %v", err)
+		}
+		if checker.calls != 1 || result.Metrics.VulnCheckedDeps != 0 || hasRisk(result) {
+			t.Errorf("calls = %d, VulnCheckedDeps = %d, want 1 call and 0 checked", checker.calls, result.Metrics.VulnCheckedDeps)
+		}
+	})
+}
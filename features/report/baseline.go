@@ -0,0 +1,221 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/shared/i18n"
+)
+
+// Baseline は --baseline で読み込んだ過去の JSON レポート（リポジトリ名 → レポート）。
+//
+// --format json で保存した単一・複数リポジトリどちらのファイルも読める。
+type Baseline struct {
+	reports map[string]JSONReport
+}
+
+// LoadBaseline は保存済みの JSON レポートをベースラインとして読み込む。
+func LoadBaseline(path string) (*Baseline, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	return parseBaseline(b)
+}
+
+// parseBaseline は JSONReport / JSONMultiReport のどちらかをパースする。
+func parseBaseline(b []byte) (*Baseline, error) {
+	// 単一と複数は repositories キーの有無で見分ける
+	var probe struct {
+		SchemaVersion int             `json:"schemaVersion"`
+		Repositories  json.RawMessage `json:"repositories"`
+	}
+	if err := json.Unmarshal(b, &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline: %w", err)
+	}
+	if probe.SchemaVersion != jsonSchemaVersion {
+		return nil, fmt.Errorf("unsupported baseline schema version: %d (want %d)", probe.SchemaVersion, jsonSchemaVersion)
+	}
+
+	var reports []JSONReport
+	if probe.Repositories != nil {
+		var multi JSONMultiReport
+		if err := json.Unmarshal(b, &multi); err != nil {
+			return nil, fmt.Errorf("failed to parse baseline: %w", err)
+		}
+		reports = multi.Repositories
+	} else {
+		var single JSONReport
+		if err := json.Unmarshal(b, &single); err != nil {
+			return nil, fmt.Errorf("failed to parse baseline: %w", err)
+		}
+		reports = []JSONReport{single}
+	}
+
+	baseline := &Baseline{reports: make(map[string]JSONReport, len(reports))}
+	for _, r := range reports {
+		baseline.reports[r.Repository] = r
+	}
+	return baseline, nil
+}
+
+// baselineMetric はベースライン比較に出す主要メトリクスの定義。
+type baselineMetric struct {
+	name           string
+	unit           string
+	higherIsBetter bool
+	current        func(m domain.Metrics) float64
+	baseline       func(m JSONMetrics) float64
+}
+
+// baselineMetrics はベースライン比較に出す主要メトリクス（表示順）。
+var baselineMetrics = []baselineMetric{
+	{"コミット数", "", true,
+		func(m domain.Metrics) float64 { return float64(m.TotalCommits) },
+		func(m JSONMetrics) float64 { return float64(m.TotalCommits) }},
+	{"平均リードタイム", "日", false,
+		func(m domain.Metrics) float64 { return m.AvgLeadTime },
+		func(m JSONMetrics) float64 { return m.AvgLeadTimeDays }},
+	{"平均レビュー待ち時間", "時間", false,
+		func(m domain.Metrics) float64 { return m.AvgReviewWaitTime },
+		func(m JSONMetrics) float64 { return m.AvgReviewWaitHours }},
+	{"平均PRサイズ", "行", false,
+		func(m domain.Metrics) float64 { return float64(m.AvgPRSize) },
+		func(m JSONMetrics) float64 { return float64(m.AvgPRSize) }},
+	{"バグ修正率", "%", false,
+		func(m domain.Metrics) float64 { return m.BugFixRatio },
+		func(m JSONMetrics) float64 { return m.BugFixRatio }},
+	{"レビューカバレッジ", "%", true,
+		func(m domain.Metrics) float64 { return m.ReviewCoverageRate },
+		func(m JSONMetrics) float64 { return m.ReviewCoverageRate }},
+	{"Issueクローズ率", "%", true,
+		func(m domain.Metrics) float64 { return m.IssueCloseRate },
+		func(m JSONMetrics) float64 { return m.IssueCloseRate }},
+	{"デプロイ頻度", "回/月", true,
+		func(m domain.Metrics) float64 { return m.DeployFrequency },
+		func(m JSONMetrics) float64 { return m.DeployFrequency }},
+	{"変更失敗率", "%", false,
+		func(m domain.Metrics) float64 { return m.ChangeFailureRate },
+		func(m JSONMetrics) float64 { return m.ChangeFailureRate }},
+	{"平均復旧時間", "時間", false,
+		func(m domain.Metrics) float64 { return m.MTTR },
+		func(m JSONMetrics) float64 { return m.MTTRHours }},
+	{"深夜コミット率", "%", false,
+		func(m domain.Metrics) float64 { return m.LateNightCommitRate },
+		func(m JSONMetrics) float64 { return m.LateNightCommitRate }},
+}
+
+// Compare は分析結果をベースラインと比較する。
+// ベースラインに同じリポジトリが含まれていなければ nil を返す。
+func (b *Baseline) Compare(r *domain.AnalysisResult) *domain.BaselineComparison {
+	base, ok := b.reports[r.Repository.FullName()]
+	if !ok {
+		return nil
+	}
+
+	cmp := &domain.BaselineComparison{
+		GeneratedAt: base.GeneratedAt,
+		Period:      domain.NewDateRange(base.Period.From, base.Period.To),
+		OverallScore: domain.BaselineDelta{
+			Name:           "総合スコア",
+			Current:        float64(r.OverallScore.Value),
+			Baseline:       float64(base.OverallScore.Value),
+			HigherIsBetter: true,
+		},
+	}
+
+	for _, ci := range categoryOrder {
+		cs, ok := r.CategoryScores[ci.cat]
+		baseCS, baseOK := base.Categories[string(ci.cat)]
+		if !ok || !baseOK {
+			continue
+		}
+		cmp.Categories = append(cmp.Categories, domain.BaselineDelta{
+			Name:           ci.name,
+			Current:        float64(cs.Score.Value),
+			Baseline:       float64(baseCS.Value),
+			HigherIsBetter: true,
+		})
+	}
+
+	for _, bm := range baselineMetrics {
+		cmp.Metrics = append(cmp.Metrics, domain.BaselineDelta{
+			Name:           bm.name,
+			Unit:           bm.unit,
+			Current:        bm.current(r.Metrics),
+			Baseline:       bm.baseline(base.Metrics),
+			HigherIsBetter: bm.higherIsBetter,
+		})
+	}
+
+	return cmp
+}
+
+// BaselineData はベースライン比較のテンプレートデータ。
+type BaselineData struct {
+	GeneratedAt string // ベースラインの生成日時
+	PeriodFrom  string
+	PeriodTo    string
+	Overall     BaselineRowData
+	Categories  []BaselineRowData
+	Metrics     []BaselineRowData
+}
+
+// BaselineRowData はベースライン比較の1行。値は表示用に整形済み。
+type BaselineRowData struct {
+	Name        string
+	Baseline    string
+	Current     string
+	Delta       string // 符号付きの差（例: "+5", "-1.2日"）
+	Status      string // "improved", "regressed", "same"（CSS クラスにも使う）
+	StatusLabel string // "▲ 改善" など
+}
+
+// buildBaselineData はベースライン比較をテンプレートデータに変換する（nil なら nil）。
+// 名前・単位・判定は lang で翻訳する。
+func buildBaselineData(cmp *domain.BaselineComparison, lang i18n.Lang) *BaselineData {
+	if cmp == nil {
+		return nil
+	}
+	data := &BaselineData{
+		GeneratedAt: cmp.GeneratedAt.Format("2006-01-02 15:04"),
+		PeriodFrom:  cmp.Period.From.Format("2006-01-02"),
+		PeriodTo:    cmp.Period.To.Format("2006-01-02"),
+		Overall:     toBaselineRow(cmp.OverallScore, "%.0f", lang),
+	}
+	for _, d := range cmp.Categories {
+		data.Categories = append(data.Categories, toBaselineRow(d, "%.0f", lang))
+	}
+	for _, d := range cmp.Metrics {
+		data.Metrics = append(data.Metrics, toBaselineRow(d, "%.1f", lang))
+	}
+	return data
+}
+
+// toBaselineRow は比較1件を表示用に整形する。verb は値の書式（スコアは整数、メトリクスは小数1桁）。
+func toBaselineRow(d domain.BaselineDelta, verb string, lang i18n.Lang) BaselineRowData {
+	status := d.Status()
+	unit := lang.T(d.Unit)
+	return BaselineRowData{
+		Name:        lang.T(d.Name),
+		Baseline:    fmt.Sprintf(verb, d.Baseline) + unit,
+		Current:     fmt.Sprintf(verb, d.Current) + unit,
+		Delta:       fmt.Sprintf("%+"+verb[1:], d.Delta()) + unit,
+		Status:      status,
+		StatusLabel: lang.T(baselineStatusLabel(status)),
+	}
+}
+
+// baselineStatusLabel は改善・悪化の判定を表示用ラベルにする。
+func baselineStatusLabel(status string) string {
+	switch status {
+	case "improved":
+		return "▲ 改善"
+	case "regressed":
+		return "▼ 悪化"
+	default:
+		return "→ 変化なし"
+	}
+}
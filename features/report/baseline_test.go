@@ -0,0 +1,156 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// renderBaseline は結果を JSON レポートとして書き出し、ベースラインとして読み直す。
+func renderBaseline(t *testing.T, results ...*domain.AnalysisResult) *Baseline {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := NewService().renderAll(&buf, results, FormatJSON); err != nil {
+		t.Fatalf("renderAll() error = %v", err)
+	}
+	baseline, err := parseBaseline(buf.Bytes())
+	if err != nil {
+		t.Fatalf("parseBaseline() error = %v", err)
+	}
+	return baseline
+}
+
+func TestBaselineCompare(t *testing.T) {
+	baseline := renderBaseline(t, newTestResult())
+
+	current := newTestResult()
+	current.OverallScore = domain.NewScore(80)
+	current.CategoryScores[domain.CategoryQuality] = domain.CategoryScore{
+		Category: domain.CategoryQuality,
+		Score:    domain.NewScore(55),
+	}
+	current.Metrics.AvgLeadTime = 2.0 // 3.5 → 2.0: 改善（小さいほど良い）
+	current.Metrics.IssueCloseRate = 60.0
+
+	cmp := baseline.Compare(current)
+	if cmp == nil {
+		t.Fatal("Compare() = nil, want comparison")
+	}
+	if !cmp.GeneratedAt.Equal(newTestResult().GeneratedAt) {
+		t.Errorf("GeneratedAt = %v", cmp.GeneratedAt)
+	}
+
+	if cmp.OverallScore.Baseline != 76 || cmp.OverallScore.Current != 80 || cmp.OverallScore.Status() != "improved" {
+		t.Errorf("OverallScore = %+v (%s)", cmp.OverallScore, cmp.OverallScore.Status())
+	}
+
+	if len(cmp.Categories) != 4 {
+		t.Fatalf("Categories = %d, want 4", len(cmp.Categories))
+	}
+	wantCategoryStatus := map[string]string{
+		"開発速度":   "same",
+		"コード品質":  "regressed",
+		"技術的負債":  "same",
+		"チーム健全性": "same",
+	}
+	for _, d := range cmp.Categories {
+		if got := d.Status(); got != wantCategoryStatus[d.Name] {
+			t.Errorf("%s status = %s, want %s", d.Name, got, wantCategoryStatus[d.Name])
+		}
+	}
+
+	metrics := make(map[string]domain.BaselineDelta)
+	for _, d := range cmp.Metrics {
+		metrics[d.Name] = d
+	}
+	if got := metrics["平均リードタイム"].Status(); got != "improved" {
+		t.Errorf("lead time status = %s, want improved", got)
+	}
+	if got := metrics["Issueクローズ率"].Status(); got != "regressed" {
+		t.Errorf("issue close rate status = %s, want regressed", got)
+	}
+	if got := metrics["コミット数"].Status(); got != "same" {
+		t.Errorf("commits status = %s, want same", got)
+	}
+}
+
+func TestBaselineCompare_MultiReport(t *testing.T) {
+	other := newTestResult()
+	other.Repository = domain.NewRepository("org", "api")
+	other.OverallScore = domain.NewScore(50)
+	baseline := renderBaseline(t, newTestResult(), other)
+
+	current := newTestResult()
+	current.Repository = domain.NewRepository("org", "api")
+	cmp := baseline.Compare(current)
+	if cmp == nil {
+		t.Fatal("Compare() = nil, want comparison")
+	}
+	if cmp.OverallScore.Baseline != 50 {
+		t.Errorf("baseline overall = %v, want 50 (org/api entry)", cmp.OverallScore.Baseline)
+	}
+
+	missing := newTestResult()
+	missing.Repository = domain.NewRepository("org", "web")
+	if got := baseline.Compare(missing); got != nil {
+		t.Errorf("Compare() for unknown repo = %+v, want nil", got)
+	}
+}
+
+func TestParseBaseline_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"not JSON", `{`},
+		{"unsupported schema version", `{"schemaVersion": 99, "repository": "facebook/react"}`},
+		{"missing schema version", `{"repository": "facebook/react"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseBaseline([]byte(tt.input)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestRender_Baseline(t *testing.T) {
+	baseline := renderBaseline(t, newTestResult())
+	result := newTestResult()
+	result.OverallScore = domain.NewScore(70)
+	result.Baseline = baseline.Compare(result)
+
+	tests := []struct {
+		format Format
+		want   []string
+	}{
+		{FormatHTML, []string{"ベースライン比較", "baseline-table", "▼ 悪化"}},
+		{FormatMarkdown, []string{"### ベースライン比較", "| 総合スコア | 76 | 70 | -6 | ▼ 悪化 |"}},
+		{FormatJSON, []string{`"baseline": {`, `"status": "regressed"`}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := NewService().Render(&buf, result, tt.format); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output does not contain %q", want)
+				}
+			}
+		})
+	}
+
+	// ベースライン未指定なら出さない
+	var buf bytes.Buffer
+	if err := NewService().Render(&buf, newTestResult(), FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), `"baseline"`) {
+		t.Error("JSON should omit baseline when not compared")
+	}
+}
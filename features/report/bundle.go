@@ -0,0 +1,47 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// bundleIndexFileName は GenerateBundle が書き出す HTML レポートのファイル名。
+// ディレクトリをそのまま静的サイトとして公開しても開けるよう index.html にする。
+const bundleIndexFileName = "index.html"
+
+// GenerateBundle は HTML レポートと関連ファイルを dir にまとめて書き出し、出力したファイルパスを返す。
+//
+// dir には index.html（複数リポジトリなら統合レポート）と PR・コントリビューター詳細の CSV を書き、
+// format が HTML 以外なら、その形式のレポートも report.<拡張子>（report.json など）として書く。
+// ディレクトリがなければ作成する。
+func (s *Service) GenerateBundle(results []*domain.AnalysisResult, dir string, format Format) ([]string, error) {
+	if len(results) == 0 {
+		return nil, errors.New("no analysis results to report")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	paths, err := s.GenerateAll(results, filepath.Join(dir, bundleIndexFileName), FormatHTML)
+	if err != nil {
+		return paths, err
+	}
+	if format != FormatHTML {
+		formatPaths, err := s.GenerateAll(results, filepath.Join(dir, "report"+format.Ext()), format)
+		paths = append(paths, formatPaths...)
+		if err != nil {
+			return paths, err
+		}
+	}
+
+	csvPaths, err := s.GenerateCSV(results, dir)
+	paths = append(paths, csvPaths...)
+	if err != nil {
+		return paths, fmt.Errorf("CSV export failed: %w", err)
+	}
+	return paths, nil
+}
@@ -0,0 +1,77 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+func TestGenerateBundle(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    Format
+		wantFiles []string
+	}{
+		{"html only", FormatHTML, []string{"index.html", "pull_requests.csv", "contributors.csv"}},
+		{"with json", FormatJSON, []string{"index.html", "report.json", "pull_requests.csv", "contributors.csv"}},
+		{"with prometheus", FormatPrometheus, []string{"index.html", "report.prom", "pull_requests.csv", "contributors.csv"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 存在しない入れ子のディレクトリも作る
+			dir := filepath.Join(t.TempDir(), "out", "latest")
+			paths, err := NewService().GenerateBundle([]*domain.AnalysisResult{newTestResult()}, dir, tt.format)
+			if err != nil {
+				t.Fatalf("GenerateBundle() error = %v", err)
+			}
+
+			var want []string
+			for _, f := range tt.wantFiles {
+				want = append(want, filepath.Join(dir, f))
+			}
+			if !reflect.DeepEqual(paths, want) {
+				t.Errorf("paths = %v, want %v", paths, want)
+			}
+			for _, p := range paths {
+				if info, err := os.Stat(p); err != nil || info.Size() == 0 {
+					t.Errorf("%s: not written (err = %v)", p, err)
+				}
+			}
+
+			index, err := os.ReadFile(filepath.Join(dir, "index.html"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(string(index), "<!DOCTYPE html>") {
+				t.Errorf("index.html is not an HTML report: %.40q", index)
+			}
+		})
+	}
+}
+
+func TestGenerateBundle_MultipleRepositories(t *testing.T) {
+	a := newTestResult()
+	b := newTestResult()
+	b.Repository = domain.NewRepository("golang", "go")
+
+	dir := t.TempDir()
+	paths, err := NewService().GenerateBundle([]*domain.AnalysisResult{a, b}, dir, FormatHTML)
+	if err != nil {
+		t.Fatalf("GenerateBundle() error = %v", err)
+	}
+	// HTML は統合レポート1つ、CSV はリポジトリごとのサブディレクトリ
+	want := []string{
+		filepath.Join(dir, "index.html"),
+		filepath.Join(dir, "facebook-react", "pull_requests.csv"),
+		filepath.Join(dir, "facebook-react", "contributors.csv"),
+		filepath.Join(dir, "golang-go", "pull_requests.csv"),
+		filepath.Join(dir, "golang-go", "contributors.csv"),
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+}
@@ -0,0 +1,55 @@
+package report
+
+import (
+	"strconv"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/shared/i18n"
+)
+
+// DataSourceData はデータの取得状況（完全性）の1行。値は表示用に整形済み。
+type DataSourceData struct {
+	Name     string
+	Count    string   // 取得した件数（上限で絞った場合は "20/134"、取得していなければ "-"）
+	Notes    []string // 欠けている理由（欠けなく取得できていれば空）
+	Complete bool     // 欠けなく取得できたか
+}
+
+// buildDataSourceData はデータの取得状況をテンプレートデータに変換し、欠けているデータの種類の数を返す。
+// 名前・理由は lang で翻訳する。
+func buildDataSourceData(sources []domain.DataSource, lang i18n.Lang) ([]DataSourceData, int) {
+	rows := make([]DataSourceData, 0, len(sources))
+	incomplete := 0
+	for _, src := range sources {
+		if !src.Complete() {
+			incomplete++
+		}
+		row := DataSourceData{Name: lang.T(src.Name), Complete: src.Complete()}
+		switch {
+		case src.Skipped:
+			row.Count = "-"
+			row.Notes = append(row.Notes, lang.T("クイックモードのため取得していません"))
+		case src.Error != "":
+			row.Count = "-"
+			row.Notes = append(row.Notes, lang.T("取得に失敗しました: %v", src.Error))
+		case src.Total > 0:
+			row.Count = strconv.Itoa(src.Fetched) + "/" + strconv.Itoa(src.Total)
+		default:
+			row.Count = strconv.Itoa(src.Fetched)
+		}
+		if src.Total > src.Fetched+src.Failed {
+			row.Notes = append(row.Notes, lang.T("件数の上限のため新しいものから一部だけ取得しています"))
+		}
+		if src.Truncated {
+			row.Notes = append(row.Notes, lang.T("一覧がページ数の上限で打ち切られ、一部しか取得できていません"))
+		}
+		if src.Failed > 0 {
+			row.Notes = append(row.Notes, lang.T("%d件は取得に失敗しました", src.Failed))
+		}
+		if src.Note != "" {
+			row.Notes = append(row.Notes, lang.T(src.Note))
+		}
+		rows = append(rows, row)
+	}
+	return rows, incomplete
+}
@@ -0,0 +1,117 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/shared/i18n"
+)
+
+// testDataSources は欠けたデータを含む取得状況。
+var testDataSources = []domain.DataSource{
+	{Name: "コミット", Fetched: 1000, Truncated: true},
+	{Name: "PR詳細（サイズ・レビュー）", Fetched: 18, Total: 134, Failed: 2},
+	{Name: "オープンIssue", Fetched: 12},
+	{Name: "依存", Fetched: 40, Note: "go"},
+	{Name: "force push", Error: "403 Forbidden"},
+}
+
+func TestBuildDataSourceData(t *testing.T) {
+	rows, incomplete := buildDataSourceData(testDataSources, i18n.English)
+	if incomplete != 3 {
+		t.Errorf("incomplete = %d, want 3", incomplete)
+	}
+	want := []DataSourceData{
+		{Name: "Commits", Count: "1000", Notes: []string{"The list hit the page limit, so only part of it was fetched"}},
+		{Name: "PR details (size, reviews)", Count: "18/134", Notes: []string{
+			"Only the most recent items were fetched because of the item limit",
+			"2 failed to fetch",
+		}},
+		{Name: "Open issues", Count: "12", Complete: true},
+		{Name: "Dependencies", Count: "40", Notes: []string{"go"}, Complete: true},
+		{Name: "force push", Count: "-", Notes: []string{"Fetch failed: 403 Forbidden"}},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("len(rows) = %d, want %d", len(rows), len(want))
+	}
+	for i := range want {
+		got := rows[i]
+		if got.Name != want[i].Name || got.Count != want[i].Count || got.Complete != want[i].Complete || !slices.Equal(got.Notes, want[i].Notes) {
+			t.Errorf("rows[%d] = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestRender_DataSources(t *testing.T) {
+	render := func(sources []domain.DataSource, format Format) string {
+		t.Helper()
+		result := newTestResult()
+		result.DataSources = sources
+		var b strings.Builder
+		if err := NewService().Render(&b, result, format); err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		return b.String()
+	}
+
+	if out := render(nil, FormatHTML); strings.Contains(out, "データの取得状況") {
+		t.Error("data completeness panel rendered without data sources")
+	}
+	if out := render(testDataSources[2:4], FormatHTML); !strings.Contains(out, "すべて取得済み") {
+		t.Error("complete data sources are not reported as complete")
+	}
+
+	out := render(testDataSources, FormatHTML)
+	for _, want := range []string{
+		"データの取得状況",
+		"欠けているデータ: 3種類",
+		"<td>PR詳細（サイズ・レビュー）</td>",
+		`<td class="num">18/134</td>`,
+		"件数の上限のため新しいものから一部だけ取得しています<br>2件は取得に失敗しました",
+		"取得に失敗しました: 403 Forbidden",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("HTML does not contain %q", want)
+		}
+	}
+
+	md := render(testDataSources, FormatMarkdown)
+	for _, want := range []string{
+		"**欠けているデータ（3種類）**",
+		"- PR詳細（サイズ・レビュー） 18/134: 件数の上限のため新しいものから一部だけ取得しています / 2件は取得に失敗しました",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown does not contain %q", want)
+		}
+	}
+	if strings.Contains(md, "オープンIssue") {
+		t.Error("Markdown lists a complete data source")
+	}
+}
+
+func TestGenerateJSON_DataSources(t *testing.T) {
+	result := newTestResult()
+	result.DataSources = testDataSources
+	var buf bytes.Buffer
+	if err := NewService(WithLang(i18n.English)).Render(&buf, result, FormatJSON); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	var got JSONReport
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(got.DataSources) != len(testDataSources) {
+		t.Fatalf("dataSources = %d, want %d", len(got.DataSources), len(testDataSources))
+	}
+	want := JSONDataSource{Name: "PR details (size, reviews)", Fetched: 18, Total: 134, Failed: 2}
+	if got.DataSources[1] != want {
+		t.Errorf("dataSources[1] = %+v, want %+v", got.DataSources[1], want)
+	}
+	if !got.DataSources[2].Complete {
+		t.Errorf("dataSources[2] = %+v, want complete", got.DataSources[2])
+	}
+}
@@ -0,0 +1,118 @@
+package report
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// CSV のファイル名。スプレッドシートで集計する前提で、列名は英語の snake_case にする。
+const (
+	prCSVFileName          = "pull_requests.csv"
+	contributorCSVFileName = "contributors.csv"
+)
+
+var (
+	prCSVHeader          = []string{"number", "title", "author", "lead_time_days", "size", "additions", "deletions", "review_wait_hours", "first_response_hours"}
+	contributorCSVHeader = []string{"name", "commits", "ratio"}
+)
+
+// GenerateCSV はドリルダウン用の PR 詳細とコントリビューター詳細を CSV として dir に書き出し、
+// 出力したファイルパスを返す。
+//
+// 結果が1件なら dir 直下に、複数なら dir/<owner>-<repo>/ に分けて出力する（列はどちらも同じ）。
+// ディレクトリがなければ作成する。
+func (s *Service) GenerateCSV(results []*domain.AnalysisResult, dir string) ([]string, error) {
+	if len(results) == 0 {
+		return nil, errors.New("no analysis results to export")
+	}
+
+	var paths []string
+	for _, r := range results {
+		repoDir := dir
+		if len(results) > 1 {
+			repoDir = filepath.Join(dir, ExpandOutputPath(RepoPlaceholder, r.Repository))
+		}
+		if err := os.MkdirAll(repoDir, 0o755); err != nil {
+			return paths, fmt.Errorf("failed to create CSV directory: %w", err)
+		}
+
+		prPath := filepath.Join(repoDir, prCSVFileName)
+		if err := writeFile(prPath, func(w io.Writer) error { return writePRCSV(w, r.PRDetails) }); err != nil {
+			return paths, fmt.Errorf("%s: %w", r.Repository.FullName(), err)
+		}
+		paths = append(paths, prPath)
+
+		contributorPath := filepath.Join(repoDir, contributorCSVFileName)
+		if err := writeFile(contributorPath, func(w io.Writer) error { return writeContributorCSV(w, r.ContributorDetails) }); err != nil {
+			return paths, fmt.Errorf("%s: %w", r.Repository.FullName(), err)
+		}
+		paths = append(paths, contributorPath)
+	}
+	return paths, nil
+}
+
+// writePRCSV は PR 詳細を CSV で書き出す。
+func writePRCSV(w io.Writer, details []domain.PRDetail) error {
+	records := make([][]string, 0, len(details)+1)
+	records = append(records, prCSVHeader)
+	for _, d := range details {
+		records = append(records, []string{
+			strconv.Itoa(d.Number),
+			csvText(d.Title),
+			csvText(d.Author),
+			formatCSVFloat(d.LeadTimeDays),
+			strconv.Itoa(d.Size),
+			strconv.Itoa(d.Additions),
+			strconv.Itoa(d.Deletions),
+			formatCSVFloat(d.ReviewWaitHours),
+			formatCSVFloat(d.FirstResponseHours),
+		})
+	}
+	return writeCSV(w, records)
+}
+
+// writeContributorCSV はコントリビューター詳細を CSV で書き出す。
+func writeContributorCSV(w io.Writer, details []domain.ContributorDetail) error {
+	records := make([][]string, 0, len(details)+1)
+	records = append(records, contributorCSVHeader)
+	for _, d := range details {
+		records = append(records, []string{
+			csvText(d.Name),
+			strconv.Itoa(d.Commits),
+			formatCSVFloat(d.Ratio),
+		})
+	}
+	return writeCSV(w, records)
+}
+
+// writeCSV はレコードを CSV で書き出す。カンマ・改行・引用符のクォートは encoding/csv に任せる。
+func writeCSV(w io.Writer, records [][]string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(records); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// csvText は自由入力の文字列をセル用に整える。
+// "=" "+" "-" "@" などで始まるとスプレッドシートが数式として評価するため、
+// 先頭に "'" を付けて文字列として扱わせる（CSV インジェクション対策）。
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// formatCSVFloat は小数を小数第2位までの文字列にする。
+func formatCSVFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
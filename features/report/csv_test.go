@@ -0,0 +1,109 @@
+package report
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+func readCSV(t *testing.T, path string) [][]string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV %s: %v", path, err)
+	}
+	return records
+}
+
+func TestGenerateCSV(t *testing.T) {
+	result := newTestResult()
+	result.PRDetails = []domain.PRDetail{
+		{Number: 12, Title: `Fix "quoted", comma`, Author: "alice", LeadTimeDays: 1.5, Size: 120, Additions: 100, Deletions: 20, ReviewWaitHours: 3.25, FirstResponseHours: 0.5},
+		{Number: 13, Title: "=HYPERLINK(\"x\")\nsecond line", Author: "bob", LeadTimeDays: 0.333, Size: 5, Additions: 5},
+	}
+	result.ContributorDetails = []domain.ContributorDetail{
+		{Name: "alice", Commits: 30, Ratio: 75},
+		{Name: "bob", Commits: 10, Ratio: 25},
+	}
+
+	dir := filepath.Join(t.TempDir(), "csv")
+	paths, err := NewService().GenerateCSV([]*domain.AnalysisResult{result}, dir)
+	if err != nil {
+		t.Fatalf("GenerateCSV() error = %v", err)
+	}
+	wantPaths := []string{filepath.Join(dir, "pull_requests.csv"), filepath.Join(dir, "contributors.csv")}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Errorf("paths = %v, want %v", paths, wantPaths)
+	}
+
+	wantPRs := [][]string{
+		{"number", "title", "author", "lead_time_days", "size", "additions", "deletions", "review_wait_hours", "first_response_hours"},
+		{"12", `Fix "quoted", comma`, "alice", "1.50", "120", "100", "20", "3.25", "0.50"},
+		{"13", "'=HYPERLINK(\"x\")\nsecond line", "bob", "0.33", "5", "5", "0", "0.00", "0.00"},
+	}
+	if got := readCSV(t, wantPaths[0]); !reflect.DeepEqual(got, wantPRs) {
+		t.Errorf("pull_requests.csv = %q, want %q", got, wantPRs)
+	}
+
+	wantContributors := [][]string{
+		{"name", "commits", "ratio"},
+		{"alice", "30", "75.00"},
+		{"bob", "10", "25.00"},
+	}
+	if got := readCSV(t, wantPaths[1]); !reflect.DeepEqual(got, wantContributors) {
+		t.Errorf("contributors.csv = %q, want %q", got, wantContributors)
+	}
+}
+
+func TestGenerateCSV_MultipleRepositories(t *testing.T) {
+	a := newTestResult()
+	b := newTestResult()
+	b.Repository = domain.NewRepository("golang", "go")
+
+	dir := t.TempDir()
+	paths, err := NewService().GenerateCSV([]*domain.AnalysisResult{a, b}, dir)
+	if err != nil {
+		t.Fatalf("GenerateCSV() error = %v", err)
+	}
+	want := []string{
+		filepath.Join(dir, "facebook-react", "pull_requests.csv"),
+		filepath.Join(dir, "facebook-react", "contributors.csv"),
+		filepath.Join(dir, "golang-go", "pull_requests.csv"),
+		filepath.Join(dir, "golang-go", "contributors.csv"),
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+	// PR がなくてもヘッダー行は出す
+	if got := readCSV(t, want[2]); len(got) < 1 || got[0][0] != "number" {
+		t.Errorf("pull_requests.csv = %q, want header row", got)
+	}
+}
+
+func TestCSVText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Add feature", "Add feature"},
+		{"=1+1", "'=1+1"},
+		{"+cmd", "'+cmd"},
+		{"-2", "'-2"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"a=b", "a=b"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := csvText(tt.in); got != tt.want {
+			t.Errorf("csvText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package report
+
+import "fmt"
+
+// Format はレポートの出力形式。
+type Format string
+
+const (
+	// FormatHTML は HTML レポート（デフォルト）。
+	FormatHTML Format = "html"
+	// FormatJSON は機械可読な JSON レポート。
+	FormatJSON Format = "json"
+	// FormatMarkdown は PR コメント向けの Markdown サマリー。
+	FormatMarkdown Format = "md"
+	// FormatPrometheus は監視向けの Prometheus テキスト形式。
+	FormatPrometheus Format = "prometheus"
+	// FormatSARIF はセキュリティツール向けの SARIF 2.1.0（リスクの一覧）。
+	FormatSARIF Format = "sarif"
+)
+
+// ParseFormat は文字列から出力形式を返す。
+func ParseFormat(s string) (Format, error) {
+	switch s {
+	case "html":
+		return FormatHTML, nil
+	case "json":
+		return FormatJSON, nil
+	case "md", "markdown":
+		return FormatMarkdown, nil
+	case "prometheus", "prom":
+		return FormatPrometheus, nil
+	case "sarif":
+		return FormatSARIF, nil
+	default:
+		return "", fmt.Errorf("unsupported format: %q (expected html, json, md, prometheus or sarif)", s)
+	}
+}
+
+// Ext は出力形式に対応するファイル拡張子を返す。
+func (f Format) Ext() string {
+	// node_exporter の textfile collector が読む拡張子に合わせる
+	if f == FormatPrometheus {
+		return ".prom"
+	}
+	return "." + string(f)
+}
@@ -0,0 +1,50 @@
+package report
+
+import "testing"
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Format
+		wantErr bool
+	}{
+		{"html", FormatHTML, false},
+		{"json", FormatJSON, false},
+		{"md", FormatMarkdown, false},
+		{"markdown", FormatMarkdown, false},
+		{"prometheus", FormatPrometheus, false},
+		{"prom", FormatPrometheus, false},
+		{"sarif", FormatSARIF, false},
+		{"xml", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseFormat(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatExt(t *testing.T) {
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{FormatHTML, ".html"},
+		{FormatJSON, ".json"},
+		{FormatMarkdown, ".md"},
+		{FormatPrometheus, ".prom"},
+		{FormatSARIF, ".sarif"},
+	}
+	for _, tt := range tests {
+		if got := tt.format.Ext(); got != tt.want {
+			t.Errorf("%s.Ext() = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+}
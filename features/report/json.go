@@ -0,0 +1,736 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/shared/i18n"
+)
+
+// jsonSchemaVersion は JSON スキーマのバージョン。
+// フィールドの削除・意味の変更など互換性のない変更をしたら上げる。
+const jsonSchemaVersion = 1
+
+// JSONReport は1リポジトリ分の JSON レポート。
+//
+// なぜ domain の型をそのまま出さないか:
+// - ドメインモデルの内部変更で JSON スキーマが壊れないようにするため
+// - 重大度などを数値ではなく安定した文字列で出すため
+type JSONReport struct {
+	SchemaVersion    int                          `json:"schemaVersion"`
+	Repository       string                       `json:"repository"`
+	Period           JSONPeriod                   `json:"period"`
+	Path             string                       `json:"path,omitempty"`         // --path 指定時のみ
+	Branch           string                       `json:"branch,omitempty"`       // --branch 指定時のみ
+	InsufficientData bool                         `json:"insufficientData"`       // 期間内にコミットもマージ済みPRもない（スコアは健全さを表さない）
+	LastActivity     *time.Time                   `json:"lastActivity,omitempty"` // 休眠の判定で求めた最後の活動日時（リリースもなく、判定したときのみ）
+	QuickMode        bool                         `json:"quickMode"`              // --quick: PRサイズ・レビュー系・依存のメトリクスは未計算（0 は「なし」ではない）
+	FilesTruncated   bool                         `json:"filesTruncated"`         // ファイル一覧の一部しか取得できなかった（ファイル数・巨大ファイルは下限値）
+	DataSources      []JSONDataSource             `json:"dataSources"`            // データの種類ごとの取得状況（完全性）
+	GeneratedAt      time.Time                    `json:"generatedAt"`
+	OverallScore     JSONScore                    `json:"overallScore"`
+	Categories       map[string]JSONCategoryScore `json:"categories"`
+	Metrics          JSONMetrics                  `json:"metrics"`
+	Risks            []JSONRisk                   `json:"risks"`
+	Trends           []domain.TrendDelta          `json:"trends"`
+	Baseline         *JSONBaseline                `json:"baseline,omitempty"` // --baseline 指定時のみ
+	History          []JSONHistoryPoint           `json:"history,omitempty"`  // --history 指定時のみ（古い順、最後が今回の期間）
+	LargeFiles       []JSONLargeFile              `json:"largeFiles"`
+	NonSourceFiles   []JSONLargeFile              `json:"largeNonSourceFiles"` // 巨大ファイルの対象外にしたバイナリ・生成物
+	HotFiles         []JSONHotFile                `json:"hotFiles"`            // 変更回数の多い順
+	SiloFiles        []JSONSiloFile               `json:"siloFiles"`           // 1人の作成者が変更の大半を占めるファイル（変更回数の多い順）
+	OutdatedDeps     []JSONOutdatedDep            `json:"outdatedDeps"`
+	Dependencies     []JSONDependencyEcosystem    `json:"dependencyEcosystems"` // 依存の多い順
+	VulnerableDeps   []JSONVulnerableDep          `json:"vulnerableDeps"`       // --check-vulns 指定時のみ中身がある（名前順）
+	LicenseFile      string                       `json:"licenseFile"`          // なければ空文字
+	CommunityFiles   []JSONCommunityFile          `json:"communityFiles"`
+	BranchProtection *JSONBranchProtection        `json:"branchProtection"` // 権限不足などで読めなければ null
+	TodoFiles        []JSONTodoFile               `json:"todoFiles"`        // --scan-todos 指定時のみ中身がある（件数の多い順）
+	PRDetails        []PRDetailData               `json:"prDetails"`
+	Contributors     []ContributorDetailData      `json:"contributors"`
+	HourlyCommits    [24]int                      `json:"hourlyCommits"`
+	CommitHeatmap    [7][24]int                   `json:"commitHeatmap"` // 曜日（日曜が0）×時間帯
+}
+
+// JSONMultiReport は複数リポジトリ分の JSON レポート。
+type JSONMultiReport struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	Repositories  []JSONReport `json:"repositories"`
+}
+
+// JSONPeriod は分析期間。
+type JSONPeriod struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	Days int       `json:"days"`
+}
+
+// JSONScore はスコアとグレード。
+type JSONScore struct {
+	Value int    `json:"value"`
+	Grade string `json:"grade"`
+}
+
+// JSONCategoryScore はカテゴリ別スコア。
+type JSONCategoryScore struct {
+	Value     int                  `json:"value"`
+	Grade     string               `json:"grade"`
+	Diagnosis string               `json:"diagnosis"`
+	Breakdown []JSONScoreBreakdown `json:"breakdown"` // 基本スコアとリスクごとの減点（合計は 0〜100 に丸める前の値）
+}
+
+// JSONScoreBreakdown はスコア内訳の1項目。
+type JSONScoreBreakdown struct {
+	Label  string `json:"label"`
+	Points int    `json:"points"` // 正: 加点、負: 減点
+	Detail string `json:"detail,omitempty"`
+}
+
+// JSONRisk は検出されたリスク。
+type JSONRisk struct {
+	Type        string `json:"type"`
+	Category    string `json:"category"`
+	Severity    string `json:"severity"` // "high", "medium", "low"
+	Target      string `json:"target"`
+	Description string `json:"description"`
+	Value       int    `json:"value"`
+	Threshold   int    `json:"threshold"`
+}
+
+// JSONBaseline はベースラインとの比較。
+type JSONBaseline struct {
+	GeneratedAt  time.Time           `json:"generatedAt"`
+	Period       JSONPeriod          `json:"period"`
+	OverallScore JSONBaselineDelta   `json:"overallScore"`
+	Categories   []JSONBaselineDelta `json:"categories"`
+	Metrics      []JSONBaselineDelta `json:"metrics"`
+}
+
+// JSONBaselineDelta はベースラインとの比較1件。
+type JSONBaselineDelta struct {
+	Name     string  `json:"name"`
+	Unit     string  `json:"unit,omitempty"`
+	Baseline float64 `json:"baseline"`
+	Current  float64 `json:"current"`
+	Delta    float64 `json:"delta"`
+	Status   string  `json:"status"` // "improved", "regressed", "same"
+}
+
+// JSONDataSource はデータ1種類の取得状況。
+type JSONDataSource struct {
+	Name      string `json:"name"`
+	Complete  bool   `json:"complete"`        // 欠けなく取得できた
+	Fetched   int    `json:"fetched"`         // 取得した件数
+	Total     int    `json:"total,omitempty"` // 対象の件数（上限で絞った場合のみ）
+	Failed    int    `json:"failed"`          // 1件ずつの取得に失敗した件数
+	Truncated bool   `json:"truncated"`       // 一覧をページ数などの上限で打ち切った
+	Skipped   bool   `json:"skipped"`         // クイックモードで取得しなかった
+	Error     string `json:"error,omitempty"` // 取得自体に失敗した場合のエラー
+	Note      string `json:"note,omitempty"`  // 補足（依存なら読み取れたエコシステム）
+}
+
+// JSONHistoryPoint はスコア推移の1点。
+type JSONHistoryPoint struct {
+	Period           JSONPeriod           `json:"period"`
+	InsufficientData bool                 `json:"insufficientData"`
+	CurrentSnapshot  bool                 `json:"currentSnapshot"` // ファイルツリー・依存・ブランチ保護などは期間当時でなく分析時点の状態
+	OverallScore     JSONScore            `json:"overallScore"`
+	Categories       map[string]JSONScore `json:"categories"`
+}
+
+// JSONLargeFile は巨大ファイル。
+type JSONLargeFile struct {
+	Path     string `json:"path"`
+	SizeKB   int    `json:"sizeKB"`
+	Severity string `json:"severity"`
+}
+
+// JSONHotFile は変更集中ファイル。
+type JSONHotFile struct {
+	Path     string `json:"path"`
+	Changes  int    `json:"changes"`
+	Severity string `json:"severity"`
+}
+
+// JSONSiloFile は1人の作成者が変更の大半を占めるファイル。
+type JSONSiloFile struct {
+	Path     string `json:"path"`
+	Author   string `json:"author"`
+	Changes  int    `json:"changes"`
+	Share    int    `json:"share"` // Author の変更が占める割合（%）
+	Severity string `json:"severity"`
+}
+
+// JSONOutdatedDep は古い依存。
+type JSONOutdatedDep struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Age      string `json:"age"`
+	Severity string `json:"severity"`
+}
+
+// JSONVulnerableDep は既知の脆弱性がある依存。
+type JSONVulnerableDep struct {
+	Name      string   `json:"name"`
+	Version   string   `json:"version"`
+	Ecosystem string   `json:"ecosystem"`
+	IDs       []string `json:"ids"` // GHSA-xxxx など（昇順）
+}
+
+// JSONDependencyEcosystem はエコシステムごとの依存の集計。
+type JSONDependencyEcosystem struct {
+	Ecosystem string `json:"ecosystem"`
+	Count     int    `json:"count"`
+	Outdated  int    `json:"outdated"`
+}
+
+// JSONTodoFile は TODO コメントのあるファイル。
+type JSONTodoFile struct {
+	Path  string `json:"path"`
+	Count int    `json:"count"`
+}
+
+// JSONCommunityFile はコミュニティヘルスファイル1種類の有無。
+type JSONCommunityFile struct {
+	Name    string `json:"name"`
+	Present bool   `json:"present"`
+}
+
+// JSONBranchProtection はデフォルトブランチの保護設定。
+type JSONBranchProtection struct {
+	Branch               string `json:"branch"`
+	Protected            bool   `json:"protected"`
+	RequiredApprovals    int    `json:"requiredApprovals"` // 0 ならレビュー必須なし
+	RequiredStatusChecks bool   `json:"requiredStatusChecks"`
+}
+
+// JSONMetrics は各種メトリクス。
+type JSONMetrics struct {
+	// 開発速度
+	TotalCommits          int     `json:"totalCommits"`
+	FeatureAdditionRate   float64 `json:"featureAdditionRate"`
+	MergedPRCount         int     `json:"mergedPRCount"`
+	PRThroughputPerWeek   float64 `json:"prThroughputPerWeek"`
+	AvgLeadTimeDays       float64 `json:"avgLeadTimeDays"`
+	LeadTimeP50Days       float64 `json:"leadTimeP50Days"`
+	LeadTimeP75Days       float64 `json:"leadTimeP75Days"`
+	LeadTimeP90Days       float64 `json:"leadTimeP90Days"`
+	AvgReviewWaitHours    float64 `json:"avgReviewWaitHours"`
+	AvgFirstResponseHours float64 `json:"avgFirstResponseHours"`
+	OpenPRCount           int     `json:"openPRCount"`
+	OpenIssueCount        int     `json:"openIssueCount"`
+
+	// コード品質
+	BugFixRatio        float64 `json:"bugFixRatio"`
+	ReworkRate         float64 `json:"reworkRate"`
+	AvgPRSize          int     `json:"avgPRSize"`
+	IssueCloseRate     float64 `json:"issueCloseRate"`
+	IssuesCreated      int     `json:"issuesCreated"`
+	IssuesClosed       int     `json:"issuesClosed"`
+	ReviewCoverageRate float64 `json:"reviewCoverageRate"`
+	ReviewCoveragePRs  int     `json:"reviewCoveragePRs"`
+	SelfMergeCount     int     `json:"selfMergeCount"`
+	SelfMergeRate      float64 `json:"selfMergeRate"`
+
+	// PR内訳・投資比率
+	FeaturePRCount  int     `json:"featurePRCount"`
+	BugFixPRCount   int     `json:"bugFixPRCount"`
+	RefactorPRCount int     `json:"refactorPRCount"`
+	OtherPRCount    int     `json:"otherPRCount"`
+	FeatureRatio    float64 `json:"featureRatio"`
+	RefactorRatio   float64 `json:"refactorRatio"`
+
+	// 放棄PR
+	AbandonedPRCount int     `json:"abandonedPRCount"`
+	AbandonmentRate  float64 `json:"abandonmentRate"`
+
+	// DORA メトリクス
+	DeployFrequency   float64 `json:"deployFrequency"`
+	DeployFreqRating  string  `json:"deployFreqRating"`
+	ChangeFailureRate float64 `json:"changeFailureRate"`
+	ChangeFailRating  string  `json:"changeFailRating"`
+	MTTRHours         float64 `json:"mttrHours"`
+	MTTRRating        string  `json:"mttrRating"`
+
+	// リリース負債
+	LastReleaseAt           *time.Time `json:"lastReleaseAt"` // 期間の終わりまでにリリースがなければ null
+	LastReleaseName         string     `json:"lastReleaseName,omitempty"`
+	DaysSinceLastRelease    int        `json:"daysSinceLastRelease"`
+	CommitsSinceLastRelease int        `json:"commitsSinceLastRelease"`
+
+	// コードチャーン
+	RevertCommitCount int     `json:"revertCommitCount"`
+	RevertRate        float64 `json:"revertRate"`
+
+	FastRevertCount      int     `json:"fastRevertCount"`      // 元のコミットから24時間以内の Revert 数
+	AvgTimeToRevertHours float64 `json:"avgTimeToRevertHours"` // 元のコミットを特定できた Revert がなければ 0
+
+	// コミットメッセージの品質
+	LowQualityCommitCount int     `json:"lowQualityCommitCount"`
+	LowQualityCommitRate  float64 `json:"lowQualityCommitRate"`
+
+	// 共同作成（Co-authored-by）
+	CoAuthoredCommitCount int     `json:"coAuthoredCommitCount"`
+	CoAuthorshipRate      float64 `json:"coAuthorshipRate"`
+
+	// コミットの粒度（変更ファイルを取得できた、マージ以外のコミット）
+	SizedCommitCount  int     `json:"sizedCommitCount"`
+	AvgFilesPerCommit float64 `json:"avgFilesPerCommit"`
+
+	// テストファイルの比率（ファイル名による推定で、実際のカバレッジではない）
+	TestFileCount     int     `json:"testFileCount"`
+	SourceFileCount   int     `json:"sourceFileCount"`
+	TestToSourceRatio float64 `json:"testToSourceRatio"`
+
+	// チーム健全性
+	TotalFiles          int     `json:"totalFiles"`
+	TotalContributors   int     `json:"totalContributors"`
+	LateNightCommitRate float64 `json:"lateNightCommitRate"`
+	WeekendCommitRate   float64 `json:"weekendCommitRate"`
+	ActiveAuthors       int     `json:"activeAuthors"`
+	NewAuthors          int     `json:"newAuthors"`
+	ChurnedAuthors      int     `json:"churnedAuthors"`
+
+	// 巨大ファイルの対象外にしたバイナリ・生成物（50KB以上のもの）
+	LargeNonSourceFileCount int `json:"largeNonSourceFileCount"`
+
+	// コミュニティヘルス（0-100）
+	CommunityHealthScore int `json:"communityHealthScore"`
+
+	// TODO コメント（--scan-todos 指定時のみ、走査しなければ 0）
+	TodoCount        int     `json:"todoCount"`
+	TodoDensity      float64 `json:"todoDensity"` // 1000行あたり
+	TodoScannedFiles int     `json:"todoScannedFiles"`
+	TodoScannedLines int     `json:"todoScannedLines"`
+
+	// 依存（リリース日を取得できたものだけを数える）
+	DependencyCount int     `json:"dependencyCount"`
+	OutdatedDepRate float64 `json:"outdatedDepRate"` // 古い依存の割合（%）
+
+	// 依存の既知の脆弱性（--check-vulns 指定時のみ、調べなければ 0）
+	VulnerableDepCount int `json:"vulnerableDepCount"`
+	VulnCheckedDeps    int `json:"vulnCheckedDeps"`
+}
+
+// GenerateJSON は分析結果から JSON レポートを生成する。
+func (s *Service) GenerateJSON(result *domain.AnalysisResult, outputPath string) error {
+	return s.generateOne(result, outputPath, FormatJSON)
+}
+
+// RenderJSON は分析結果を JSON レポートで w に書き出す（1件なら JSONReport、複数なら JSONMultiReport）。
+// 出力形式（--format）によらず JSON を別の送り先に渡す用途に使う。
+func (s *Service) RenderJSON(w io.Writer, results []*domain.AnalysisResult) error {
+	if len(results) == 0 {
+		return errors.New("no analysis results to report")
+	}
+	return s.renderAll(w, results, FormatJSON)
+}
+
+// writeJSON はインデント付きで JSON を書き出す。
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return nil
+}
+
+// buildJSONReport は分析結果を JSON スキーマに変換する。
+// 実行ごとの差分が意味を持つよう、順序が不定になりうる一覧はソートする。
+func (s *Service) buildJSONReport(r *domain.AnalysisResult) JSONReport {
+	categories := make(map[string]JSONCategoryScore, len(r.CategoryScores))
+	for cat, cs := range r.CategoryScores {
+		categories[string(cat)] = JSONCategoryScore{
+			Value:     cs.Score.Value,
+			Grade:     cs.Score.Grade(),
+			Diagnosis: cs.Diagnosis,
+			Breakdown: toJSONScoreBreakdown(cs.Score.Breakdown),
+		}
+	}
+
+	risks := make([]JSONRisk, len(r.Risks))
+	for i, risk := range sortedRisks(r.Risks) {
+		risks[i] = JSONRisk{
+			Type:        string(risk.Type),
+			Category:    string(risk.Type.Category()),
+			Severity:    severityKey(risk.Severity),
+			Target:      risk.Target,
+			Description: risk.Description,
+			Value:       risk.Value,
+			Threshold:   risk.Threshold,
+		}
+	}
+
+	hotFiles := make([]JSONHotFile, len(r.HotFiles))
+	for i, hf := range r.HotFiles {
+		hotFiles[i] = JSONHotFile{
+			Path:     hf.Path,
+			Changes:  hf.Changes,
+			Severity: severityKey(hf.Severity),
+		}
+	}
+
+	siloFiles := make([]JSONSiloFile, len(r.SiloFiles))
+	for i, sf := range r.SiloFiles {
+		siloFiles[i] = JSONSiloFile{
+			Path:     sf.Path,
+			Author:   sf.Author,
+			Changes:  sf.Changes,
+			Share:    sf.Share,
+			Severity: severityKey(sf.Severity),
+		}
+	}
+
+	outdatedDeps := make([]JSONOutdatedDep, len(r.OutdatedDeps))
+	for i, od := range r.OutdatedDeps {
+		outdatedDeps[i] = JSONOutdatedDep{
+			Name:     od.Name,
+			Version:  od.Version,
+			Age:      od.Age,
+			Severity: severityKey(od.Severity),
+		}
+	}
+	sort.SliceStable(outdatedDeps, func(i, j int) bool {
+		if outdatedDeps[i].Name != outdatedDeps[j].Name {
+			return outdatedDeps[i].Name < outdatedDeps[j].Name
+		}
+		return outdatedDeps[i].Version < outdatedDeps[j].Version
+	})
+
+	trends := r.Trends
+	if trends == nil {
+		trends = []domain.TrendDelta{}
+	}
+
+	m := r.Metrics
+	return JSONReport{
+		SchemaVersion: jsonSchemaVersion,
+		Repository:    r.Repository.FullName(),
+		Period: JSONPeriod{
+			From: r.Period.From,
+			To:   r.Period.To,
+			Days: r.Period.Days(),
+		},
+		Path:             r.Path,
+		Branch:           r.Branch,
+		InsufficientData: r.InsufficientData,
+		LastActivity:     timeOrNil(r.LastActivity),
+		QuickMode:        r.QuickMode,
+		FilesTruncated:   r.FilesTruncated,
+		DataSources:      toJSONDataSources(r.DataSources, s.lang),
+		GeneratedAt:      r.GeneratedAt,
+		OverallScore: JSONScore{
+			Value: r.OverallScore.Value,
+			Grade: r.OverallScore.Grade(),
+		},
+		Categories: categories,
+		Metrics: JSONMetrics{
+			TotalCommits:          m.TotalCommits,
+			FeatureAdditionRate:   m.FeatureAdditionRate,
+			MergedPRCount:         m.MergedPRCount,
+			PRThroughputPerWeek:   m.PRThroughputPerWeek,
+			AvgLeadTimeDays:       m.AvgLeadTime,
+			LeadTimeP50Days:       m.LeadTimeP50,
+			LeadTimeP75Days:       m.LeadTimeP75,
+			LeadTimeP90Days:       m.LeadTimeP90,
+			AvgReviewWaitHours:    m.AvgReviewWaitTime,
+			AvgFirstResponseHours: m.AvgFirstResponse,
+			OpenPRCount:           m.OpenPRCount,
+			OpenIssueCount:        m.OpenIssueCount,
+
+			BugFixRatio:        m.BugFixRatio,
+			ReworkRate:         m.ReworkRate,
+			AvgPRSize:          m.AvgPRSize,
+			IssueCloseRate:     m.IssueCloseRate,
+			IssuesCreated:      m.IssuesCreated,
+			IssuesClosed:       m.IssuesClosed,
+			ReviewCoverageRate: m.ReviewCoverageRate,
+			ReviewCoveragePRs:  m.ReviewCoveragePRs,
+			SelfMergeCount:     m.SelfMergeCount,
+			SelfMergeRate:      m.SelfMergeRate,
+
+			FeaturePRCount:  m.FeaturePRCount,
+			BugFixPRCount:   m.BugFixPRCount,
+			RefactorPRCount: m.RefactorPRCount,
+			OtherPRCount:    m.OtherPRCount,
+			FeatureRatio:    m.FeatureRatio,
+			RefactorRatio:   m.RefactorRatio,
+
+			AbandonedPRCount: m.AbandonedPRCount,
+			AbandonmentRate:  m.AbandonmentRate,
+
+			DeployFrequency:   m.DeployFrequency,
+			DeployFreqRating:  m.DeployFreqRating,
+			ChangeFailureRate: m.ChangeFailureRate,
+			ChangeFailRating:  m.ChangeFailRating,
+			MTTRHours:         m.MTTR,
+			MTTRRating:        m.MTTRRating,
+
+			LastReleaseAt:           timeOrNil(m.LastReleaseAt),
+			LastReleaseName:         m.LastReleaseName,
+			DaysSinceLastRelease:    m.DaysSinceLastRelease,
+			CommitsSinceLastRelease: m.CommitsSinceLastRelease,
+
+			RevertCommitCount: m.RevertCommitCount,
+			RevertRate:        m.RevertRate,
+
+			FastRevertCount:      m.FastRevertCount,
+			AvgTimeToRevertHours: m.AvgTimeToRevertHours,
+
+			LowQualityCommitCount: m.LowQualityCommitCount,
+			LowQualityCommitRate:  m.LowQualityCommitRate,
+
+			CoAuthoredCommitCount: m.CoAuthoredCommitCount,
+			CoAuthorshipRate:      m.CoAuthorshipRate,
+
+			SizedCommitCount:  m.SizedCommitCount,
+			AvgFilesPerCommit: m.AvgFilesPerCommit,
+
+			TestFileCount:     m.TestFileCount,
+			SourceFileCount:   m.SourceFileCount,
+			TestToSourceRatio: m.TestToSourceRatio,
+
+			TotalFiles:          m.TotalFiles,
+			TotalContributors:   m.TotalContributors,
+			LateNightCommitRate: m.LateNightCommitRate,
+			WeekendCommitRate:   m.WeekendCommitRate,
+			ActiveAuthors:       m.ActiveAuthors,
+			NewAuthors:          m.NewAuthors,
+			ChurnedAuthors:      m.ChurnedAuthors,
+
+			LargeNonSourceFileCount: m.LargeNonSourceFileCount,
+
+			CommunityHealthScore: m.CommunityHealthScore,
+
+			TodoCount:        m.TodoCount,
+			TodoDensity:      m.TodoDensity,
+			TodoScannedFiles: m.TodoScannedFiles,
+			TodoScannedLines: m.TodoScannedLines,
+
+			DependencyCount: m.DependencyCount,
+			OutdatedDepRate: m.OutdatedDepRate,
+
+			VulnerableDepCount: m.VulnerableDepCount,
+			VulnCheckedDeps:    m.VulnCheckedDeps,
+		},
+		Risks:            risks,
+		Trends:           trends,
+		Baseline:         toJSONBaseline(r.Baseline, s.lang),
+		History:          toJSONHistory(r.History),
+		LargeFiles:       toJSONLargeFiles(r.LargeFiles),
+		NonSourceFiles:   toJSONLargeFiles(r.LargeNonSourceFiles),
+		HotFiles:         hotFiles,
+		SiloFiles:        siloFiles,
+		OutdatedDeps:     outdatedDeps,
+		Dependencies:     toJSONDependencyEcosystems(r.DependencyEcosystems),
+		VulnerableDeps:   toJSONVulnerableDeps(r.VulnerableDeps),
+		LicenseFile:      r.LicenseFile,
+		CommunityFiles:   toJSONCommunityFiles(r.CommunityFiles),
+		BranchProtection: toJSONBranchProtection(r.BranchProtection),
+		TodoFiles:        toJSONTodoFiles(r.TodoFiles),
+		PRDetails:        toPRDetailData(r.PRDetails),
+		Contributors:     toContributorDetailData(r.ContributorDetails),
+		HourlyCommits:    r.HourlyCommits,
+		CommitHeatmap:    r.WeekdayHourCommits,
+	}
+}
+
+// toJSONScoreBreakdown はスコア内訳を JSON スキーマに変換する（基本スコア → 減点の順のまま）。
+func toJSONScoreBreakdown(items []domain.ScoreBreakdownItem) []JSONScoreBreakdown {
+	data := make([]JSONScoreBreakdown, len(items))
+	for i, b := range items {
+		data[i] = JSONScoreBreakdown{Label: b.Label, Points: b.Points, Detail: b.Detail}
+	}
+	return data
+}
+
+// toJSONLargeFiles は巨大ファイルを JSON スキーマに変換する（差分を取りやすいようパス順）。
+func toJSONLargeFiles(files []domain.LargeFile) []JSONLargeFile {
+	data := make([]JSONLargeFile, len(files))
+	for i, lf := range files {
+		data[i] = JSONLargeFile{
+			Path:     lf.Path,
+			SizeKB:   lf.SizeKB,
+			Severity: severityKey(lf.Severity),
+		}
+	}
+	sort.SliceStable(data, func(i, j int) bool {
+		return data[i].Path < data[j].Path
+	})
+	return data
+}
+
+// timeOrNil はゼロ値の日時を nil（JSON の null）にする。
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// toJSONVulnerableDeps は脆弱性のある依存を JSON スキーマに変換する（名前順のまま）。
+func toJSONVulnerableDeps(deps []domain.VulnerableDep) []JSONVulnerableDep {
+	data := make([]JSONVulnerableDep, len(deps))
+	for i, d := range deps {
+		data[i] = JSONVulnerableDep{Name: d.Name, Version: d.Version, Ecosystem: d.Ecosystem, IDs: d.IDs}
+	}
+	return data
+}
+
+// toJSONDependencyEcosystems はエコシステム別の依存数を JSON スキーマに変換する（依存の多い順のまま）。
+func toJSONDependencyEcosystems(ecosystems []domain.DependencyEcosystem) []JSONDependencyEcosystem {
+	data := make([]JSONDependencyEcosystem, len(ecosystems))
+	for i, e := range ecosystems {
+		data[i] = JSONDependencyEcosystem{Ecosystem: e.Ecosystem, Count: e.Count, Outdated: e.Outdated}
+	}
+	return data
+}
+
+// toJSONTodoFiles は TODO コメントのあるファイルを JSON スキーマに変換する（件数の多い順のまま）。
+func toJSONTodoFiles(files []domain.TodoFile) []JSONTodoFile {
+	data := make([]JSONTodoFile, len(files))
+	for i, f := range files {
+		data[i] = JSONTodoFile{Path: f.Path, Count: f.Count}
+	}
+	return data
+}
+
+// toJSONCommunityFiles はコミュニティヘルスファイルの有無を JSON スキーマに変換する（チェック順のまま）。
+func toJSONCommunityFiles(files []domain.CommunityFile) []JSONCommunityFile {
+	data := make([]JSONCommunityFile, len(files))
+	for i, f := range files {
+		data[i] = JSONCommunityFile{Name: f.Name, Present: f.Present}
+	}
+	return data
+}
+
+// toJSONBranchProtection はデフォルトブランチの保護設定を JSON スキーマに変換する（nil なら nil）。
+func toJSONBranchProtection(bp *domain.BranchProtection) *JSONBranchProtection {
+	if bp == nil {
+		return nil
+	}
+	return &JSONBranchProtection{
+		Branch:               bp.Branch,
+		Protected:            bp.Protected,
+		RequiredApprovals:    bp.RequiredApprovals,
+		RequiredStatusChecks: bp.RequiredStatusChecks,
+	}
+}
+
+// toJSONDataSources はデータの取得状況を JSON スキーマに変換する。名前・補足は lang で翻訳する。
+func toJSONDataSources(sources []domain.DataSource, lang i18n.Lang) []JSONDataSource {
+	out := make([]JSONDataSource, len(sources))
+	for i, src := range sources {
+		out[i] = JSONDataSource{
+			Name:      lang.T(src.Name),
+			Complete:  src.Complete(),
+			Fetched:   src.Fetched,
+			Total:     src.Total,
+			Failed:    src.Failed,
+			Truncated: src.Truncated,
+			Skipped:   src.Skipped,
+			Error:     src.Error,
+			Note:      lang.T(src.Note),
+		}
+	}
+	return out
+}
+
+// toJSONBaseline はベースライン比較を JSON スキーマに変換する（nil なら nil）。
+// 名前・単位は lang で翻訳する。
+func toJSONBaseline(cmp *domain.BaselineComparison, lang i18n.Lang) *JSONBaseline {
+	if cmp == nil {
+		return nil
+	}
+	toDelta := func(d domain.BaselineDelta) JSONBaselineDelta {
+		return JSONBaselineDelta{
+			Name:     lang.T(d.Name),
+			Unit:     lang.T(d.Unit),
+			Baseline: d.Baseline,
+			Current:  d.Current,
+			Delta:    d.Delta(),
+			Status:   d.Status(),
+		}
+	}
+
+	jb := &JSONBaseline{
+		GeneratedAt: cmp.GeneratedAt,
+		Period: JSONPeriod{
+			From: cmp.Period.From,
+			To:   cmp.Period.To,
+			Days: cmp.Period.Days(),
+		},
+		OverallScore: toDelta(cmp.OverallScore),
+		Categories:   make([]JSONBaselineDelta, len(cmp.Categories)),
+		Metrics:      make([]JSONBaselineDelta, len(cmp.Metrics)),
+	}
+	for i, d := range cmp.Categories {
+		jb.Categories[i] = toDelta(d)
+	}
+	for i, d := range cmp.Metrics {
+		jb.Metrics[i] = toDelta(d)
+	}
+	return jb
+}
+
+// toJSONHistory はスコアの推移を JSON スキーマに変換する（推移がなければ nil）。
+func toJSONHistory(history []domain.HistoryPoint) []JSONHistoryPoint {
+	if len(history) == 0 {
+		return nil
+	}
+	points := make([]JSONHistoryPoint, len(history))
+	for i, h := range history {
+		categories := make(map[string]JSONScore, len(h.CategoryScores))
+		for cat, score := range h.CategoryScores {
+			categories[string(cat)] = JSONScore{Value: score.Value, Grade: score.Grade()}
+		}
+		points[i] = JSONHistoryPoint{
+			Period: JSONPeriod{
+				From: h.Period.From,
+				To:   h.Period.To,
+				Days: h.Period.Days(),
+			},
+			InsufficientData: h.InsufficientData,
+			CurrentSnapshot:  h.CurrentSnapshot,
+			OverallScore:     JSONScore{Value: h.OverallScore.Value, Grade: h.OverallScore.Grade()},
+			Categories:       categories,
+		}
+	}
+	return points
+}
+
+// sortedRisks はリスクを重大度（高い順）→ 種類 → 対象の順に並べたコピーを返す。
+func sortedRisks(risks []domain.Risk) []domain.Risk {
+	sorted := make([]domain.Risk, len(risks))
+	copy(sorted, risks)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Severity != b.Severity {
+			return a.Severity > b.Severity
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Target < b.Target
+	})
+	return sorted
+}
+
+// severityKey は重大度を JSON/CSS 用の安定したキーに変換する。
+func severityKey(sev domain.Severity) string {
+	switch sev {
+	case domain.SeverityHigh:
+		return "high"
+	case domain.SeverityMedium:
+		return "medium"
+	default:
+		return "low"
+	}
+}
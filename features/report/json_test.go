@@ -0,0 +1,213 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+func TestGenerateJSON(t *testing.T) {
+	s := NewService()
+	result := newTestResult()
+	result.OutdatedDeps = []domain.OutdatedDep{
+		{Name: "lodash", Version: "4.0.0", Age: "5年", Severity: domain.SeverityHigh},
+		{Name: "express", Version: "3.0.0", Age: "3年", Severity: domain.SeverityMedium},
+	}
+	result.CommunityFiles = []domain.CommunityFile{
+		{Name: "CONTRIBUTING.md", Present: true},
+		{Name: "SECURITY.md", Present: false},
+	}
+	result.Metrics.CommunityHealthScore = 50
+	result.DependencyEcosystems = []domain.DependencyEcosystem{
+		{Ecosystem: "npm", Count: 30, Outdated: 2},
+		{Ecosystem: "go", Count: 10},
+	}
+	result.Metrics.DependencyCount = 40
+	result.Metrics.OutdatedDepRate = 5
+	result.VulnerableDeps = []domain.VulnerableDep{
+		{Name: "lodash", Version: "4.0.0", Ecosystem: "npm", IDs: []string{"GHSA-35jh-r3h4-6jhm"}},
+	}
+	result.Metrics.VulnerableDepCount = 1
+	result.Metrics.VulnCheckedDeps = 40
+	result.BranchProtection = &domain.BranchProtection{Branch: "main", Protected: true, RequiredApprovals: 1}
+	result.CategoryScores[domain.CategoryVelocity] = domain.CategoryScore{
+		Category: domain.CategoryVelocity,
+		Score: domain.NewScoreWithBreakdown(85, []domain.ScoreBreakdownItem{
+			{Label: "基本スコア", Points: 100},
+			{Label: "PRリードタイム遅延", Points: -15, Detail: "10.0日、基準7日以下"},
+		}),
+	}
+
+	path := t.TempDir() + "/report.json"
+	if err := s.GenerateJSON(result, path); err != nil {
+		t.Fatalf("GenerateJSON() error = %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got JSONReport
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if got.SchemaVersion != jsonSchemaVersion {
+		t.Errorf("SchemaVersion = %d, want %d", got.SchemaVersion, jsonSchemaVersion)
+	}
+	if got.Repository != "facebook/react" {
+		t.Errorf("Repository = %q", got.Repository)
+	}
+	if got.OverallScore.Value != 76 || got.OverallScore.Grade != "B" {
+		t.Errorf("OverallScore = %+v", got.OverallScore)
+	}
+	if len(got.Categories) != 4 {
+		t.Errorf("Categories = %d, want 4", len(got.Categories))
+	}
+	if got.Categories[string(domain.CategoryHealth)].Value != 60 {
+		t.Errorf("health score = %d, want 60", got.Categories[string(domain.CategoryHealth)].Value)
+	}
+	// スコア内訳は基本スコア → リスクごとの減点の順
+	wantBreakdown := []JSONScoreBreakdown{
+		{Label: "基本スコア", Points: 100},
+		{Label: "PRリードタイム遅延", Points: -15, Detail: "10.0日、基準7日以下"},
+	}
+	if bd := got.Categories[string(domain.CategoryVelocity)].Breakdown; !reflect.DeepEqual(bd, wantBreakdown) {
+		t.Errorf("velocity breakdown = %+v, want %+v", bd, wantBreakdown)
+	}
+	if bd := got.Categories[string(domain.CategoryHealth)].Breakdown; bd == nil || len(bd) != 0 {
+		t.Errorf("health breakdown = %#v, want empty array", bd)
+	}
+	if got.Metrics.TotalCommits != 150 {
+		t.Errorf("TotalCommits = %d, want 150", got.Metrics.TotalCommits)
+	}
+
+	// リスクは重大度の高い順
+	if len(got.Risks) != 2 {
+		t.Fatalf("Risks = %d, want 2", len(got.Risks))
+	}
+	if got.Risks[0].Severity != "high" || got.Risks[1].Severity != "medium" {
+		t.Errorf("risks not sorted by severity: %+v", got.Risks)
+	}
+
+	// 古い依存は名前順
+	if len(got.OutdatedDeps) != 2 || got.OutdatedDeps[0].Name != "express" {
+		t.Errorf("outdated deps not sorted by name: %+v", got.OutdatedDeps)
+	}
+
+	// コミュニティヘルスファイルはチェック順のまま
+	wantCommunity := []JSONCommunityFile{{Name: "CONTRIBUTING.md", Present: true}, {Name: "SECURITY.md", Present: false}}
+	if !reflect.DeepEqual(got.CommunityFiles, wantCommunity) || got.Metrics.CommunityHealthScore != 50 {
+		t.Errorf("community = %+v (score %d), want %+v (score 50)", got.CommunityFiles, got.Metrics.CommunityHealthScore, wantCommunity)
+	}
+
+	// エコシステム別の依存数は依存の多い順のまま
+	wantDeps := []JSONDependencyEcosystem{{Ecosystem: "npm", Count: 30, Outdated: 2}, {Ecosystem: "go", Count: 10}}
+	if !reflect.DeepEqual(got.Dependencies, wantDeps) || got.Metrics.DependencyCount != 40 || got.Metrics.OutdatedDepRate != 5 {
+		t.Errorf("dependencies = %+v (count %d, rate %v), want %+v (count 40, rate 5)",
+			got.Dependencies, got.Metrics.DependencyCount, got.Metrics.OutdatedDepRate, wantDeps)
+	}
+
+	wantVulns := []JSONVulnerableDep{{Name: "lodash", Version: "4.0.0", Ecosystem: "npm", IDs: []string{"GHSA-35jh-r3h4-6jhm"}}}
+	if !reflect.DeepEqual(got.VulnerableDeps, wantVulns) || got.Metrics.VulnerableDepCount != 1 || got.Metrics.VulnCheckedDeps != 40 {
+		t.Errorf("vulnerable deps = %+v (count %d, checked %d), want %+v (count 1, checked 40)",
+			got.VulnerableDeps, got.Metrics.VulnerableDepCount, got.Metrics.VulnCheckedDeps, wantVulns)
+	}
+
+	wantProtection := &JSONBranchProtection{Branch: "main", Protected: true, RequiredApprovals: 1}
+	if !reflect.DeepEqual(got.BranchProtection, wantProtection) {
+		t.Errorf("branchProtection = %+v, want %+v", got.BranchProtection, wantProtection)
+	}
+}
+
+func TestBuildJSONReportDeterministic(t *testing.T) {
+	s := NewService()
+	result := newTestResult()
+
+	var first bytes.Buffer
+	if err := writeJSON(&first, s.buildJSONReport(result)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 入力の並び順が変わっても出力は変わらない
+	result.Risks[0], result.Risks[1] = result.Risks[1], result.Risks[0]
+	var second bytes.Buffer
+	if err := writeJSON(&second, s.buildJSONReport(result)); err != nil {
+		t.Fatal(err)
+	}
+
+	if first.String() != second.String() {
+		t.Errorf("JSON output is not deterministic:\n%s\n---\n%s", first.String(), second.String())
+	}
+}
+
+func TestGenerateAllJSON(t *testing.T) {
+	s := NewService()
+	first := newTestResult()
+	second := newTestResult()
+	second.Repository = domain.NewRepository("golang", "go")
+
+	path := t.TempDir() + "/all.json"
+	paths, err := s.GenerateAll([]*domain.AnalysisResult{first, second}, path, FormatJSON)
+	if err != nil {
+		t.Fatalf("GenerateAll() error = %v", err)
+	}
+	if len(paths) != 1 || paths[0] != path {
+		t.Fatalf("paths = %v, want [%s]", paths, path)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got JSONMultiReport
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(got.Repositories) != 2 {
+		t.Fatalf("Repositories = %d, want 2", len(got.Repositories))
+	}
+	if got.Repositories[0].Repository != "facebook/react" || got.Repositories[1].Repository != "golang/go" {
+		t.Errorf("unexpected repositories: %s, %s", got.Repositories[0].Repository, got.Repositories[1].Repository)
+	}
+}
+
+func TestRenderJSON(t *testing.T) {
+	s := NewService()
+
+	// 1件なら単独のレポート
+	var single bytes.Buffer
+	if err := s.RenderJSON(&single, []*domain.AnalysisResult{newTestResult()}); err != nil {
+		t.Fatalf("RenderJSON() error = %v", err)
+	}
+	var report JSONReport
+	if err := json.Unmarshal(single.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if report.SchemaVersion != jsonSchemaVersion || report.Repository != "facebook/react" {
+		t.Errorf("report = schemaVersion %d, repository %q", report.SchemaVersion, report.Repository)
+	}
+
+	// 複数なら repositories にまとめる
+	second := newTestResult()
+	second.Repository = domain.NewRepository("golang", "go")
+	var multi bytes.Buffer
+	if err := s.RenderJSON(&multi, []*domain.AnalysisResult{newTestResult(), second}); err != nil {
+		t.Fatalf("RenderJSON() error = %v", err)
+	}
+	var multiReport JSONMultiReport
+	if err := json.Unmarshal(multi.Bytes(), &multiReport); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(multiReport.Repositories) != 2 {
+		t.Errorf("Repositories = %d, want 2", len(multiReport.Repositories))
+	}
+
+	if err := s.RenderJSON(&bytes.Buffer{}, nil); err == nil {
+		t.Error("RenderJSON(nil) error = nil, want error")
+	}
+}
@@ -0,0 +1,158 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// Markdown に載せるリスクの上限。
+// PR コメントが長くなりすぎないよう、超えた分は件数だけ表示する。
+const markdownMaxRisks = 10
+
+// GenerateMarkdown は分析結果から Markdown のサマリーを w に書き出す。
+//
+// GitHub の PR コメントに貼ることを想定し、生の HTML は使わない。
+// 内容は総合グレード・カテゴリ別スコア・DORA 評価・ベースライン比較（指定時）・
+// リスク一覧（改善提案付き）に絞る。
+func (s *Service) GenerateMarkdown(result *domain.AnalysisResult, w io.Writer) error {
+	var b strings.Builder
+	s.writeMarkdown(&b, result)
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write markdown: %w", err)
+	}
+	return nil
+}
+
+// writeMarkdown は1リポジトリ分の Markdown を組み立てる。
+func (s *Service) writeMarkdown(b *strings.Builder, r *domain.AnalysisResult) {
+	// ヘッダ
+	fmt.Fprintf(b, "## Lokup: %s\n\n", r.Repository.FullName())
+	if r.InsufficientData {
+		b.WriteString(s.lang.T("**データ不足**: 分析期間内にコミットもマージされたPRもないため、スコアは算出していません。\n\n"))
+	} else {
+		b.WriteString(s.lang.T("**総合グレード: %s**（%d / 100・%s）\n\n",
+			r.OverallScore.Grade(), r.OverallScore.Value, s.lang.T(r.OverallScore.GradeDescription())))
+	}
+	b.WriteString(s.lang.T("分析期間: %s 〜 %s（%d日間）\n\n",
+		r.Period.From.Format("2006-01-02"), r.Period.To.Format("2006-01-02"), r.Period.Days()))
+	if r.Path != "" {
+		b.WriteString(s.lang.T("対象パス: `%s`（コントリビューター・Issue・リリース・オープンPR・依存はリポジトリ全体）\n\n", r.Path))
+	}
+	if r.Branch != "" {
+		b.WriteString(s.lang.T("対象ブランチ: `%s`（PR・Issue・リリース・依存はリポジトリ全体）\n\n", r.Branch))
+	}
+	if r.QuickMode {
+		b.WriteString(s.lang.T("クイックモード: PRサイズ・レビュー・依存は計算していません\n\n"))
+	}
+	if r.FilesTruncated {
+		b.WriteString(s.lang.T("ファイル一覧の一部しか取得できなかったため、ファイル数・巨大ファイルなどは実際より少ない可能性があります\n\n"))
+	}
+	if rows, incomplete := buildDataSourceData(r.DataSources, s.lang); incomplete > 0 {
+		b.WriteString(s.lang.T("**欠けているデータ（%d種類）**: これらを使うスコアは割り引いて読んでください\n\n", incomplete))
+		for _, row := range rows {
+			if !row.Complete {
+				fmt.Fprintf(b, "- %s %s: %s\n", row.Name, row.Count, strings.Join(row.Notes, " / "))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	// カテゴリ別スコア（データ不足なら満点が並ぶだけなので出さない）
+	if !r.InsufficientData {
+		b.WriteString(s.lang.T("| カテゴリ | スコア | グレード | 診断 |\n"))
+		b.WriteString("|---|---:|:---:|---|\n")
+		for _, c := range s.buildCategoryScoreData(r.CategoryScores) {
+			fmt.Fprintf(b, "| %s %s | %d | %s | %s |\n",
+				c.Icon, c.Name, c.Score, c.Grade, escapeMarkdownCell(c.Diagnosis))
+		}
+		b.WriteString("\n")
+	}
+
+	// DORA
+	m := r.Metrics
+	b.WriteString(s.lang.T("### DORA メトリクス\n\n"))
+	b.WriteString(s.lang.T("| 指標 | 値 | 評価 |\n"))
+	b.WriteString("|---|---:|---|\n")
+	b.WriteString(s.lang.T("| デプロイ頻度 | %.1f 回/月 | %s |\n", m.DeployFrequency, ratingOrNA(m.DeployFreqRating)))
+	b.WriteString(s.lang.T("| 変更失敗率 | %.1f%% | %s |\n", m.ChangeFailureRate, ratingOrNA(m.ChangeFailRating)))
+	b.WriteString(s.lang.T("| 平均復旧時間 | %.1f 時間 | %s |\n", m.MTTR, ratingOrNA(m.MTTRRating)))
+	b.WriteString("\n")
+	if !m.LastReleaseAt.IsZero() {
+		b.WriteString(s.lang.T("最後のリリース: %s（%s）から %d 日・%d コミット未リリース\n\n",
+			m.LastReleaseName, m.LastReleaseAt.Format("2006-01-02"), m.DaysSinceLastRelease, m.CommitsSinceLastRelease))
+	}
+
+	// ベースライン比較
+	if bd := buildBaselineData(r.Baseline, s.lang); bd != nil {
+		b.WriteString(s.lang.T("### ベースライン比較（%s 生成）\n\n", bd.GeneratedAt))
+		b.WriteString(s.lang.T("| 項目 | ベースライン | 今回 | 差 | 判定 |\n"))
+		b.WriteString("|---|---:|---:|---:|---|\n")
+		rows := append([]BaselineRowData{bd.Overall}, bd.Categories...)
+		rows = append(rows, bd.Metrics...)
+		for _, row := range rows {
+			fmt.Fprintf(b, "| %s | %s | %s | %s | %s |\n",
+				row.Name, row.Baseline, row.Current, row.Delta, row.StatusLabel)
+		}
+		b.WriteString("\n")
+	}
+
+	// スコアの推移
+	if len(r.History) > 0 {
+		b.WriteString(s.lang.T("### スコアの推移\n\n"))
+		b.WriteString(s.lang.T("| 期間 | 総合スコア | グレード |\n"))
+		b.WriteString("|---|---:|:---:|\n")
+		history := toHistoryPointData(r.History)
+		for _, h := range history {
+			period := s.lang.T("%v 〜 %v", h.From, h.To)
+			if h.CurrentSnapshot {
+				period += " ※"
+			}
+			if h.InsufficientData {
+				fmt.Fprintf(b, "| %s | N/A | - |\n", period)
+				continue
+			}
+			fmt.Fprintf(b, "| %s | %d | %s |\n", period, h.Score, h.Grade)
+		}
+		b.WriteString("\n")
+		if historyUsesCurrentSnapshot(history) {
+			b.WriteString(s.lang.T("※ の期間は、ファイルツリー・依存・ブランチ保護・ライセンス・コミュニティファイルを期間当時ではなく現在の状態で採点しています（期間ごとに変わるのはコミット・PR・Issue などの活動の指標だけです）。\n\n"))
+		}
+	}
+
+	// リスク
+	risks := sortedRisks(r.Risks)
+	b.WriteString(s.lang.T("### 検出されたリスク（%d件）\n\n", len(risks)))
+	if len(risks) == 0 {
+		b.WriteString(s.lang.T("重大なリスクは検出されませんでした。\n"))
+		return
+	}
+	for i, risk := range risks {
+		if i >= markdownMaxRisks {
+			b.WriteString(s.lang.T("- ほか %d 件\n", len(risks)-markdownMaxRisks))
+			break
+		}
+		fmt.Fprintf(b, "- %s **%s**", risk.Severity.Emoji(), s.lang.T(risk.Type.DisplayName()))
+		if risk.Target != "" {
+			fmt.Fprintf(b, " `%s`", strings.ReplaceAll(risk.Target, "`", "'"))
+		}
+		fmt.Fprintf(b, ": %s\n", risk.Description)
+		fmt.Fprintf(b, "  - 💡 %s\n", s.lang.T(riskTypeToAction(risk.Type)))
+	}
+}
+
+// escapeMarkdownCell はテーブルのセルを壊す文字をエスケープする。
+func escapeMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// ratingOrNA は DORA 評価が空なら N/A を返す。
+func ratingOrNA(rating string) string {
+	if rating == "" {
+		return "N/A"
+	}
+	return rating
+}
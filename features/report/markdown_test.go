@@ -0,0 +1,94 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+func TestGenerateMarkdown(t *testing.T) {
+	s := NewService()
+	var b strings.Builder
+	if err := s.GenerateMarkdown(newTestResult(), &b); err != nil {
+		t.Fatalf("GenerateMarkdown() error = %v", err)
+	}
+	md := b.String()
+
+	wants := []string{
+		"## Lokup: facebook/react",
+		"**総合グレード: B**",
+		"| カテゴリ | スコア | グレード | 診断 |",
+		"### DORA メトリクス",
+		"| デプロイ頻度 | 4.0 回/月 | High |",
+		"### 検出されたリスク（2件）",
+		"`src/main.go`",
+		riskTypeToAction(domain.RiskTypeChangeConcentration),
+	}
+	for _, want := range wants {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown does not contain %q\n%s", want, md)
+		}
+	}
+
+	// 生の HTML は含めない
+	if strings.Contains(md, "<") {
+		t.Errorf("markdown contains raw HTML:\n%s", md)
+	}
+
+	// 重大度の高いリスクが先に来る
+	if strings.Index(md, "🔴") > strings.Index(md, "🟡") {
+		t.Errorf("risks are not sorted by severity:\n%s", md)
+	}
+}
+
+func TestGenerateMarkdown_TruncatesRisks(t *testing.T) {
+	s := NewService()
+	result := newTestResult()
+	result.Risks = nil
+	for i := range markdownMaxRisks + 3 {
+		result.Risks = append(result.Risks, domain.Risk{
+			Type:        domain.RiskTypeLargeFile,
+			Severity:    domain.SeverityMedium,
+			Target:      fmt.Sprintf("file%02d.bin", i),
+			Description: "巨大ファイル",
+		})
+	}
+
+	var b strings.Builder
+	if err := s.GenerateMarkdown(result, &b); err != nil {
+		t.Fatal(err)
+	}
+	md := b.String()
+
+	if got := strings.Count(md, "- 🟡"); got != markdownMaxRisks {
+		t.Errorf("risk lines = %d, want %d", got, markdownMaxRisks)
+	}
+	if !strings.Contains(md, "ほか 3 件") {
+		t.Errorf("missing truncation note:\n%s", md)
+	}
+}
+
+func TestGenerateAllMarkdown(t *testing.T) {
+	s := NewService()
+	first := newTestResult()
+	second := newTestResult()
+	second.Repository = domain.NewRepository("golang", "go")
+
+	path := t.TempDir() + "/summary.md"
+	if _, err := s.GenerateAll([]*domain.AnalysisResult{first, second}, path, FormatMarkdown); err != nil {
+		t.Fatalf("GenerateAll() error = %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	md := string(b)
+	for _, name := range []string{"## Lokup: facebook/react", "## Lokup: golang/go", "\n---\n"} {
+		if !strings.Contains(md, name) {
+			t.Errorf("summary does not contain %q", name)
+		}
+	}
+}
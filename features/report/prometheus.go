@@ -0,0 +1,195 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// prometheusMetricPrefix は出力するメトリクス名の接頭辞。
+const prometheusMetricPrefix = "lokup_"
+
+// prometheusCategories はカテゴリ別スコアの出力順。
+var prometheusCategories = []domain.Category{
+	domain.CategoryVelocity,
+	domain.CategoryQuality,
+	domain.CategoryTechDebt,
+	domain.CategoryHealth,
+}
+
+// prometheusMetric は Prometheus に出す1メトリクスの定義。
+//
+// メトリクス名はダッシュボードやアラートから参照されるため、
+// 一度出した名前は変えない（追加のみ）。docs/metrics.md にも一覧を載せている。
+type prometheusMetric struct {
+	name  string // 接頭辞なしの名前
+	help  string
+	value func(m domain.Metrics) float64
+}
+
+// prometheusMetrics は domain.Metrics から出力する数値メトリクスの一覧（出力順）。
+var prometheusMetrics = []prometheusMetric{
+	// 開発速度
+	{"total_commits", "Number of commits in the analysis period.", func(m domain.Metrics) float64 { return float64(m.TotalCommits) }},
+	{"feature_addition_rate", "Commits per day in the analysis period.", func(m domain.Metrics) float64 { return m.FeatureAdditionRate }},
+	{"merged_pull_requests", "Number of pull requests merged in the analysis period.", func(m domain.Metrics) float64 { return float64(m.MergedPRCount) }},
+	{"pr_throughput_per_week", "Pull requests merged per week in the analysis period.", func(m domain.Metrics) float64 { return m.PRThroughputPerWeek }},
+	{"avg_lead_time_days", "Average days from PR creation to merge.", func(m domain.Metrics) float64 { return m.AvgLeadTime }},
+	{"lead_time_p50_days", "Median days from PR creation to merge.", func(m domain.Metrics) float64 { return m.LeadTimeP50 }},
+	{"lead_time_p75_days", "75th percentile days from PR creation to merge.", func(m domain.Metrics) float64 { return m.LeadTimeP75 }},
+	{"lead_time_p90_days", "90th percentile days from PR creation to merge.", func(m domain.Metrics) float64 { return m.LeadTimeP90 }},
+	{"avg_review_wait_hours", "Average hours until the first review.", func(m domain.Metrics) float64 { return m.AvgReviewWaitTime }},
+	{"avg_first_response_hours", "Average hours until the first review or comment by someone other than the author.", func(m domain.Metrics) float64 { return m.AvgFirstResponse }},
+	{"open_pull_requests", "Number of open pull requests.", func(m domain.Metrics) float64 { return float64(m.OpenPRCount) }},
+	{"open_issues", "Number of open issues.", func(m domain.Metrics) float64 { return float64(m.OpenIssueCount) }},
+
+	// コード品質
+	{"bug_fix_ratio_percent", "Share of bug fix PRs (%).", func(m domain.Metrics) float64 { return m.BugFixRatio }},
+	{"rework_rate_percent", "Rework rate (%).", func(m domain.Metrics) float64 { return m.ReworkRate }},
+	{"avg_pr_size_lines", "Average changed lines per PR.", func(m domain.Metrics) float64 { return float64(m.AvgPRSize) }},
+	{"issue_close_rate_percent", "Issues closed / created in the analysis period (%).", func(m domain.Metrics) float64 { return m.IssueCloseRate }},
+	{"issues_created", "Issues created in the analysis period.", func(m domain.Metrics) float64 { return float64(m.IssuesCreated) }},
+	{"issues_closed", "Issues closed in the analysis period.", func(m domain.Metrics) float64 { return float64(m.IssuesClosed) }},
+	{"review_coverage_percent", "Share of merged PRs with an approving or change-requesting review (%).", func(m domain.Metrics) float64 { return m.ReviewCoverageRate }},
+	{"self_merged_pull_requests", "Merged PRs without an approval from someone other than the author.", func(m domain.Metrics) float64 { return float64(m.SelfMergeCount) }},
+	{"self_merge_rate_percent", "Share of merged PRs without an approval from someone other than the author (%).", func(m domain.Metrics) float64 { return m.SelfMergeRate }},
+
+	// PR内訳・投資比率
+	{"feature_pull_requests", "Number of feature PRs.", func(m domain.Metrics) float64 { return float64(m.FeaturePRCount) }},
+	{"bug_fix_pull_requests", "Number of bug fix PRs.", func(m domain.Metrics) float64 { return float64(m.BugFixPRCount) }},
+	{"refactor_pull_requests", "Number of refactoring PRs.", func(m domain.Metrics) float64 { return float64(m.RefactorPRCount) }},
+	{"other_pull_requests", "Number of other PRs.", func(m domain.Metrics) float64 { return float64(m.OtherPRCount) }},
+	{"feature_ratio_percent", "Share of feature PRs (%).", func(m domain.Metrics) float64 { return m.FeatureRatio }},
+	{"refactor_ratio_percent", "Share of refactoring PRs (%).", func(m domain.Metrics) float64 { return m.RefactorRatio }},
+	{"abandoned_pull_requests", "Closed PRs that were not merged.", func(m domain.Metrics) float64 { return float64(m.AbandonedPRCount) }},
+	{"abandonment_rate_percent", "Share of closed PRs that were not merged (%).", func(m domain.Metrics) float64 { return m.AbandonmentRate }},
+
+	// DORA
+	{"deploy_frequency_per_month", "Releases per month (DORA deployment frequency).", func(m domain.Metrics) float64 { return m.DeployFrequency }},
+	{"change_failure_rate_percent", "DORA change failure rate (%).", func(m domain.Metrics) float64 { return m.ChangeFailureRate }},
+	{"mttr_hours", "DORA mean time to restore (hours).", func(m domain.Metrics) float64 { return m.MTTR }},
+
+	// コードチャーン
+	{"revert_commits", "Number of revert commits.", func(m domain.Metrics) float64 { return float64(m.RevertCommitCount) }},
+	{"revert_rate_percent", "Share of revert commits (%).", func(m domain.Metrics) float64 { return m.RevertRate }},
+	{"fast_reverts", "Number of commits reverted within 24 hours of the original commit.", func(m domain.Metrics) float64 { return float64(m.FastRevertCount) }},
+	{"time_to_revert_hours", "Average hours from a commit to its revert (reverts whose original commit is in the period).", func(m domain.Metrics) float64 { return m.AvgTimeToRevertHours }},
+
+	// コミットメッセージの品質
+	{"low_quality_commits", "Commits whose message does not describe the change (empty, one word, WIP, bare merge).", func(m domain.Metrics) float64 { return float64(m.LowQualityCommitCount) }},
+	{"low_quality_commit_rate_percent", "Share of commits with a low-quality message (%).", func(m domain.Metrics) float64 { return m.LowQualityCommitRate }},
+	{"co_authorship_rate_percent", "Share of commits with a Co-authored-by trailer (%).", func(m domain.Metrics) float64 { return m.CoAuthorshipRate }},
+
+	// コミットの粒度
+	{"avg_files_per_commit", "Average files changed per non-merge commit whose changed files were fetched.", func(m domain.Metrics) float64 { return m.AvgFilesPerCommit }},
+
+	// テストファイルの比率（ファイル名による推定）
+	{"test_files", "Number of test files, classified by file name conventions.", func(m domain.Metrics) float64 { return float64(m.TestFileCount) }},
+	{"source_files", "Number of non-test source files.", func(m domain.Metrics) float64 { return float64(m.SourceFileCount) }},
+	{"test_to_source_ratio", "Test files per non-test source file (a proxy, not code coverage).", func(m domain.Metrics) float64 { return m.TestToSourceRatio }},
+
+	// 依存
+	{"dependencies", "Number of dependencies analyzed (those whose release date could be resolved).", func(m domain.Metrics) float64 { return float64(m.DependencyCount) }},
+	{"outdated_dependency_rate_percent", "Share of analyzed dependencies released 2 or more years ago (%).", func(m domain.Metrics) float64 { return m.OutdatedDepRate }},
+	{"vulnerable_dependencies", "Dependencies with known vulnerabilities in OSV (only with --check-vulns).", func(m domain.Metrics) float64 { return float64(m.VulnerableDepCount) }},
+
+	// チーム健全性
+	{"files", "Number of files in the repository.", func(m domain.Metrics) float64 { return float64(m.TotalFiles) }},
+	{"contributors", "Number of contributors.", func(m domain.Metrics) float64 { return float64(m.TotalContributors) }},
+	{"late_night_commit_rate_percent", "Share of commits made between 22:00 and 05:00 (%).", func(m domain.Metrics) float64 { return m.LateNightCommitRate }},
+	{"weekend_commit_rate_percent", "Share of commits made on Saturday or Sunday (%).", func(m domain.Metrics) float64 { return m.WeekendCommitRate }},
+	{"active_authors", "Distinct commit authors in the analysis period.", func(m domain.Metrics) float64 { return float64(m.ActiveAuthors) }},
+	{"new_authors", "Authors who committed only in the second half of the analysis period.", func(m domain.Metrics) float64 { return float64(m.NewAuthors) }},
+	{"churned_authors", "Authors who committed only in the first half of the analysis period.", func(m domain.Metrics) float64 { return float64(m.ChurnedAuthors) }},
+}
+
+// writePrometheus は分析結果を Prometheus のテキスト形式（exposition format）で書き出す。
+//
+// 同じメトリクスの HELP / TYPE は1度しか書けないため、複数リポジトリの場合も
+// メトリクスごとにまとめ、repo ラベルでリポジトリを区別する。
+// node_exporter の textfile collector などでそのまま読み込める。
+func writePrometheus(w io.Writer, results []*domain.AnalysisResult) error {
+	bw := bufio.NewWriter(w)
+
+	// 総合スコア
+	writePrometheusHeader(bw, "overall_score", "Overall health score (0-100).")
+	for _, r := range results {
+		writePrometheusSample(bw, "overall_score", float64(r.OverallScore.Value), "repo", r.Repository.FullName())
+	}
+
+	// データ不足（1 ならスコアは健全さを表さないため、アラートから除外する）
+	writePrometheusHeader(bw, "insufficient_data", "1 if there were no commits or merged PRs in the analysis period.")
+	for _, r := range results {
+		v := 0.0
+		if r.InsufficientData {
+			v = 1
+		}
+		writePrometheusSample(bw, "insufficient_data", v, "repo", r.Repository.FullName())
+	}
+
+	// カテゴリ別スコア
+	writePrometheusHeader(bw, "category_score", "Health score per category (0-100).")
+	for _, r := range results {
+		for _, cat := range prometheusCategories {
+			cs, ok := r.CategoryScores[cat]
+			if !ok {
+				continue
+			}
+			writePrometheusSample(bw, "category_score", float64(cs.Score.Value),
+				"repo", r.Repository.FullName(), "category", string(cat))
+		}
+	}
+
+	// 重大度別のリスク数
+	writePrometheusHeader(bw, "risks", "Number of detected risks per severity.")
+	for _, r := range results {
+		for _, sev := range []domain.Severity{domain.SeverityHigh, domain.SeverityMedium, domain.SeverityLow} {
+			writePrometheusSample(bw, "risks", float64(r.RiskCount(sev)),
+				"repo", r.Repository.FullName(), "severity", severityKey(sev))
+		}
+	}
+
+	// 各種メトリクス
+	for _, pm := range prometheusMetrics {
+		writePrometheusHeader(bw, pm.name, pm.help)
+		for _, r := range results {
+			writePrometheusSample(bw, pm.name, pm.value(r.Metrics), "repo", r.Repository.FullName())
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write prometheus metrics: %w", err)
+	}
+	return nil
+}
+
+// writePrometheusHeader はメトリクスの HELP / TYPE 行を書く（すべて gauge）。
+func writePrometheusHeader(w *bufio.Writer, name, help string) {
+	fmt.Fprintf(w, "# HELP %s%s %s\n", prometheusMetricPrefix, name, help)
+	fmt.Fprintf(w, "# TYPE %s%s gauge\n", prometheusMetricPrefix, name)
+}
+
+// writePrometheusSample はサンプル1行を書く。labels はキーと値を交互に並べる。
+func writePrometheusSample(w *bufio.Writer, name string, value float64, labels ...string) {
+	w.WriteString(prometheusMetricPrefix + name)
+	if len(labels) > 0 {
+		w.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, "%s=\"%s\"", labels[i], escapePrometheusLabel(labels[i+1]))
+		}
+		w.WriteByte('}')
+	}
+	fmt.Fprintf(w, " %s\n", strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// escapePrometheusLabel はラベル値のバックスラッシュ・ダブルクォート・改行をエスケープする。
+func escapePrometheusLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,64 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	first := newTestResult()
+	second := newTestResult()
+	second.Repository = domain.NewRepository("golang", "go")
+	second.Metrics.TotalCommits = 42
+
+	var b strings.Builder
+	if err := writePrometheus(&b, []*domain.AnalysisResult{first, second}); err != nil {
+		t.Fatalf("writePrometheus() error = %v", err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		"# HELP lokup_overall_score ",
+		"# TYPE lokup_overall_score gauge\n",
+		`lokup_overall_score{repo="facebook/react"} 76` + "\n",
+		`lokup_category_score{repo="facebook/react",category="health"} 60` + "\n",
+		`lokup_risks{repo="facebook/react",severity="high"} 1` + "\n",
+		`lokup_total_commits{repo="facebook/react"} 150` + "\n",
+		`lokup_total_commits{repo="golang/go"} 42` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q\n%s", want, out)
+		}
+	}
+
+	// 複数リポジトリでも HELP / TYPE はメトリクスごとに1回だけ
+	if n := strings.Count(out, "# TYPE lokup_total_commits "); n != 1 {
+		t.Errorf("TYPE lokup_total_commits written %d times, want 1", n)
+	}
+
+	// 全メトリクスが出力される
+	for _, pm := range prometheusMetrics {
+		if !strings.Contains(out, "\n"+prometheusMetricPrefix+pm.name+"{") {
+			t.Errorf("metric %s not written", pm.name)
+		}
+	}
+}
+
+func TestEscapePrometheusLabel(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"owner/repo", "owner/repo"},
+		{`a"b`, `a\"b`},
+		{`a\b`, `a\\b`},
+		{"a\nb", `a\nb`},
+	}
+	for _, tt := range tests {
+		if got := escapePrometheusLabel(tt.in); got != tt.want {
+			t.Errorf("escapePrometheusLabel(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
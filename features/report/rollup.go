@@ -0,0 +1,339 @@
+package report
+
+import (
+	"errors"
+	"io"
+	"math"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// rollupWorstLimit はロールアップに出す「スコアの低いリポジトリ」の件数。
+const rollupWorstLimit = 5
+
+// rollupRiskTypesLimit はロールアップに出す「多いリスク」の種類数。
+const rollupRiskTypesLimit = 10
+
+// rollupGrades はロールアップのグレード分布に並べるグレード（良い順）。
+var rollupGrades = []string{"A", "B", "C", "D"}
+
+// rollupRatings はロールアップの DORA レーティング分布に並べるレーティング（良い順）。
+var rollupRatings = []string{domain.DORARatingElite, domain.DORARatingHigh, domain.DORARatingMedium, domain.DORARatingLow}
+
+// RollupTemplateData はロールアップ（複数リポジトリを集約した組織全体の概要）のテンプレートに渡すデータ。
+type RollupTemplateData struct {
+	Lang       string // <html lang> に使う言語コード
+	PeriodFrom string
+	PeriodTo   string
+	PeriodDays int
+
+	RepoCount         int // 分析したリポジトリ数
+	ScoredCount       int // スコアを算出できたリポジトリ数（データ不足を除く）
+	InsufficientCount int // データ不足でスコアを算出していないリポジトリ数
+
+	// スコアを算出できたリポジトリの総合スコアの平均（ScoredCount が 0 なら 0）
+	AverageScore      int
+	AverageGrade      string
+	AverageGradeClass string
+
+	Grades    []RollupGradeData    // グレード分布（A から D の順）
+	Worst     []RollupRepoData     // 総合スコアの低いリポジトリ（低い順に上位のみ）
+	DORA      []RollupDORAData     // DORA メトリクスの組織全体の集計
+	RiskTypes []RollupRiskTypeData // 多くのリポジトリで検出されたリスクの種類（上位のみ）
+
+	// 全リポジトリ（入力順）
+	Repositories []RollupRepoData
+
+	GeneratedAt string
+}
+
+// RollupRepoData はロールアップに出すリポジトリ1件。
+type RollupRepoData struct {
+	Name             string
+	Link             string // 詳細レポートへのリンク（空ならリンクしない）
+	InsufficientData bool
+	Score            int
+	Grade            string
+	GradeClass       string
+	Risks            int // 検出されたリスクの件数
+	HighRisks        int // うち重大度 High の件数
+}
+
+// RollupGradeData はグレード分布の1行。
+type RollupGradeData struct {
+	Grade       string
+	GradeClass  string
+	Description string
+	Count       int
+	Percent     float64 // スコアを算出できたリポジトリに占める割合（%）
+}
+
+// RollupDORAData は DORA メトリクス1つの組織全体の集計。
+type RollupDORAData struct {
+	Name    string
+	Unit    string
+	Average float64 // レーティングの付いたリポジトリの平均
+	Rating  string  // 平均に対するレーティング（集計対象がなければ "N/A"）
+	Rated   int     // 集計対象のリポジトリ数
+	Ratings []RollupCountData
+	NoData  int // データがない・期間が短いなどでレーティングの付かなかったリポジトリ数
+}
+
+// RollupCountData はレーティングごとのリポジトリ数。
+type RollupCountData struct {
+	Label string
+	Count int
+}
+
+// RollupRiskTypeData はリスクの種類1つの集計。
+type RollupRiskTypeData struct {
+	Type   string
+	Repos  int // 検出されたリポジトリ数
+	Count  int // 全リポジトリでの検出件数
+	High   int // うち重大度 High の件数
+	Action string
+}
+
+// GenerateRollup は複数リポジトリの分析結果を集約したロールアップの HTML を outputPath に書き出す。
+//
+// 平均スコア・グレード分布・スコアの低いリポジトリ・DORA メトリクスの集計・多いリスクを1ページにまとめ、
+// 各リポジトリから詳細レポートへリンクする。reportPath は GenerateAll / GenerateBundle で書き出した
+// HTML レポートのパスで、RepoPlaceholder を含めばリポジトリごとのファイルへ、含まなければ統合レポート内の
+// 各リポジトリの節へリンクする。リンクは outputPath のディレクトリからの相対パスになる。
+// reportPath が空か StdoutPath ならリンクしない。
+func (s *Service) GenerateRollup(results []*domain.AnalysisResult, outputPath, reportPath string) error {
+	if len(results) == 0 {
+		return errors.New("no analysis results to report")
+	}
+	links := make([]string, len(results))
+	if reportPath != "" && reportPath != StdoutPath {
+		for i, r := range results {
+			links[i] = rollupLink(outputPath, reportPath, r.Repository, i, len(results))
+		}
+	}
+	return writeFile(outputPath, func(w io.Writer) error {
+		return s.renderRollup(w, results, links)
+	})
+}
+
+// rollupLink はロールアップから1リポジトリの詳細レポートへのリンクを返す。
+// 統合レポート（2件以上をまとめた1ファイル）なら、そのリポジトリの節（#repo-<入力順>）を指す。
+func rollupLink(outputPath, reportPath string, repo domain.Repository, index, total int) string {
+	target := reportPath
+	anchor := ""
+	if strings.Contains(reportPath, RepoPlaceholder) {
+		target = ExpandOutputPath(reportPath, repo)
+	} else if total > 1 {
+		anchor = "#repo-" + strconv.Itoa(index)
+	}
+
+	base := "."
+	if outputPath != StdoutPath {
+		base = filepath.Dir(outputPath)
+	}
+	absBase, errBase := filepath.Abs(base)
+	absTarget, errTarget := filepath.Abs(target)
+	if errBase == nil && errTarget == nil {
+		if rel, err := filepath.Rel(absBase, absTarget); err == nil {
+			target = rel
+		}
+	}
+	return filepath.ToSlash(target) + anchor
+}
+
+// renderRollup はロールアップの HTML を w に書き出す。links は results と同じ順の詳細レポートへのリンク。
+func (s *Service) renderRollup(w io.Writer, results []*domain.AnalysisResult, links []string) error {
+	return s.executeTemplate(w, "rollup", rollupHTMLTemplate, s.prepareRollupTemplateData(results, links))
+}
+
+// prepareRollupTemplateData は複数の分析結果を集約してロールアップのデータを準備する。
+// 分析期間と生成日時は先頭の結果のものを使う（同一実行内では共通のため）。
+func (s *Service) prepareRollupTemplateData(results []*domain.AnalysisResult, links []string) RollupTemplateData {
+	first := results[0]
+	data := RollupTemplateData{
+		Lang:         s.langCode(),
+		PeriodFrom:   first.Period.From.Format("2006-01-02"),
+		PeriodTo:     first.Period.To.Format("2006-01-02"),
+		PeriodDays:   first.Period.Days(),
+		RepoCount:    len(results),
+		Repositories: make([]RollupRepoData, len(results)),
+		GeneratedAt:  first.GeneratedAt.Format("2006-01-02 15:04:05"),
+	}
+
+	gradeCounts := make(map[string]int)
+	var scored []RollupRepoData
+	total := 0
+	for i, r := range results {
+		repo := RollupRepoData{
+			Name:             r.Repository.FullName(),
+			InsufficientData: r.InsufficientData,
+			Score:            r.OverallScore.Value,
+			Grade:            r.OverallScore.Grade(),
+			GradeClass:       "grade-" + strings.ToLower(r.OverallScore.Grade()),
+			Risks:            len(r.Risks),
+		}
+		if i < len(links) {
+			repo.Link = links[i]
+		}
+		for _, risk := range r.Risks {
+			if risk.Severity == domain.SeverityHigh {
+				repo.HighRisks++
+			}
+		}
+		data.Repositories[i] = repo
+
+		if r.InsufficientData {
+			data.InsufficientCount++
+			continue
+		}
+		scored = append(scored, repo)
+		total += repo.Score
+		gradeCounts[repo.Grade]++
+	}
+	data.ScoredCount = len(scored)
+
+	if len(scored) > 0 {
+		avg := domain.NewScore(int(math.Round(float64(total) / float64(len(scored)))))
+		data.AverageScore = avg.Value
+		data.AverageGrade = avg.Grade()
+		data.AverageGradeClass = "grade-" + strings.ToLower(avg.Grade())
+	}
+	for _, g := range rollupGrades {
+		row := RollupGradeData{
+			Grade:       g,
+			GradeClass:  "grade-" + strings.ToLower(g),
+			Description: s.lang.T(domain.GradeDescription(g)),
+			Count:       gradeCounts[g],
+		}
+		if len(scored) > 0 {
+			row.Percent = float64(row.Count) / float64(len(scored)) * 100
+		}
+		data.Grades = append(data.Grades, row)
+	}
+
+	// スコアの低い順（同点はリポジトリ名順）
+	sort.SliceStable(scored, func(i, j int) bool {
+		if scored[i].Score != scored[j].Score {
+			return scored[i].Score < scored[j].Score
+		}
+		return scored[i].Name < scored[j].Name
+	})
+	data.Worst = scored[:min(len(scored), rollupWorstLimit)]
+
+	data.DORA = s.buildRollupDORA(results)
+	data.RiskTypes = s.buildRollupRiskTypes(results)
+	return data
+}
+
+// buildRollupDORA は DORA メトリクスごとに、レーティングの付いたリポジトリの平均とレーティングの分布を集計する。
+// 平均のレーティングは先頭の結果の境界で判定する（同一実行内では共通のため）。
+func (s *Service) buildRollupDORA(results []*domain.AnalysisResult) []RollupDORAData {
+	bands := results[0].DORABands
+	if bands == (domain.DORARatingBands{}) {
+		bands = domain.DefaultDORARatingBands
+	}
+	metrics := []struct {
+		name, unit string
+		value      func(m domain.Metrics) float64
+		rating     func(m domain.Metrics) string
+		rate       func(v float64) string
+	}{
+		{"デプロイ頻度", "回/月",
+			func(m domain.Metrics) float64 { return m.DeployFrequency },
+			func(m domain.Metrics) string { return m.DeployFreqRating },
+			bands.DeployFrequencyRating},
+		{"変更失敗率", "%",
+			func(m domain.Metrics) float64 { return m.ChangeFailureRate },
+			func(m domain.Metrics) string { return m.ChangeFailRating },
+			bands.ChangeFailureRateRating},
+		{"平均復旧時間", "時間",
+			func(m domain.Metrics) float64 { return m.MTTR },
+			func(m domain.Metrics) string { return m.MTTRRating },
+			bands.MTTRRating},
+	}
+
+	rows := make([]RollupDORAData, 0, len(metrics))
+	for _, m := range metrics {
+		row := RollupDORAData{Name: s.lang.T(m.name), Unit: s.lang.T(m.unit), Rating: "N/A"}
+		// N/A（データなし）や期間が短すぎる場合は平均・分布に含めない
+		counts := make(map[string]int)
+		sum := 0.0
+		for _, r := range results {
+			rating := m.rating(r.Metrics)
+			if !slices.Contains(rollupRatings, rating) {
+				row.NoData++
+				continue
+			}
+			counts[rating]++
+			sum += m.value(r.Metrics)
+			row.Rated++
+		}
+		if row.Rated > 0 {
+			row.Average = sum / float64(row.Rated)
+			row.Rating = m.rate(row.Average)
+		}
+		for _, rating := range rollupRatings {
+			row.Ratings = append(row.Ratings, RollupCountData{Label: rating, Count: counts[rating]})
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// buildRollupRiskTypes はリスクの種類ごとに、検出されたリポジトリ数と件数を集計する。
+// 検出されたリポジトリの多い順（同数なら件数、High の件数の多い順）に上位だけ返す。
+func (s *Service) buildRollupRiskTypes(results []*domain.AnalysisResult) []RollupRiskTypeData {
+	type tally struct {
+		repos, count, high int
+	}
+	tallies := make(map[domain.RiskType]*tally)
+	var order []domain.RiskType
+	for _, r := range results {
+		seen := make(map[domain.RiskType]bool)
+		for _, risk := range r.Risks {
+			t, ok := tallies[risk.Type]
+			if !ok {
+				t = &tally{}
+				tallies[risk.Type] = t
+				order = append(order, risk.Type)
+			}
+			t.count++
+			if risk.Severity == domain.SeverityHigh {
+				t.high++
+			}
+			if !seen[risk.Type] {
+				seen[risk.Type] = true
+				t.repos++
+			}
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := tallies[order[i]], tallies[order[j]]
+		if a.repos != b.repos {
+			return a.repos > b.repos
+		}
+		if a.count != b.count {
+			return a.count > b.count
+		}
+		return a.high > b.high
+	})
+
+	rows := make([]RollupRiskTypeData, 0, min(len(order), rollupRiskTypesLimit))
+	for _, rt := range order[:min(len(order), rollupRiskTypesLimit)] {
+		t := tallies[rt]
+		rows = append(rows, RollupRiskTypeData{
+			Type:   s.lang.T(rt.DisplayName()),
+			Repos:  t.repos,
+			Count:  t.count,
+			High:   t.high,
+			Action: s.lang.T(riskTypeToAction(rt)),
+		})
+	}
+	return rows
+}
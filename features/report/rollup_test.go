@@ -0,0 +1,181 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/shared/i18n"
+)
+
+// newRollupResults はスコア・DORA・リスクの異なる分析結果を返す。
+func newRollupResults() []*domain.AnalysisResult {
+	repo := func(name string, score int, deploy float64, deployRating string, risks ...domain.RiskType) *domain.AnalysisResult {
+		r := newTestResult()
+		r.Repository = domain.NewRepository("acme", name)
+		r.OverallScore = domain.NewScore(score)
+		r.Metrics.DeployFrequency = deploy
+		r.Metrics.DeployFreqRating = deployRating
+		r.Metrics.ChangeFailRating = "N/A"
+		r.Metrics.MTTRRating = "N/A"
+		r.Risks = nil
+		for _, rt := range risks {
+			r.Risks = append(r.Risks, domain.Risk{Type: rt, Severity: domain.SeverityHigh})
+		}
+		return r
+	}
+	idle := repo("idle", 0, 0, "N/A")
+	idle.InsufficientData = true
+	return []*domain.AnalysisResult{
+		repo("api", 85, 40, domain.DORARatingElite, domain.RiskTypeLateNight),
+		repo("web", 35, 2, domain.DORARatingMedium, domain.RiskTypeLateNight, domain.RiskTypeLateNight, domain.RiskTypeLargeFile),
+		repo("cli", 62, 6, domain.DORARatingHigh),
+		repo("docs", 45, 0.5, domain.DORARatingLow, domain.RiskTypeLargeFile),
+		repo("infra", 35, 1, domain.DORARatingMedium),
+		repo("sdk", 90, 30, domain.DORARatingElite, domain.RiskTypeLateNight),
+		idle,
+	}
+}
+
+func TestPrepareRollupTemplateData(t *testing.T) {
+	results := newRollupResults()
+	data := NewService().prepareRollupTemplateData(results, nil)
+
+	if data.RepoCount != 7 || data.ScoredCount != 6 || data.InsufficientCount != 1 {
+		t.Errorf("counts = %d/%d/%d, want 7 repos, 6 scored, 1 insufficient", data.RepoCount, data.ScoredCount, data.InsufficientCount)
+	}
+	// (85+35+62+45+35+90)/6 = 58.67
+	if data.AverageScore != 59 || data.AverageGrade != "C" {
+		t.Errorf("average = %d (%s), want 59 (C)", data.AverageScore, data.AverageGrade)
+	}
+
+	wantGrades := map[string]int{"A": 2, "B": 1, "C": 1, "D": 2}
+	for _, g := range data.Grades {
+		if g.Count != wantGrades[g.Grade] {
+			t.Errorf("grade %s count = %d, want %d", g.Grade, g.Count, wantGrades[g.Grade])
+		}
+	}
+
+	var worst []string
+	for _, r := range data.Worst {
+		worst = append(worst, r.Name)
+	}
+	if got, want := strings.Join(worst, ","), "acme/infra,acme/web,acme/docs,acme/cli,acme/api"; got != want {
+		t.Errorf("worst = %s, want %s", got, want)
+	}
+
+	deploy := data.DORA[0]
+	if deploy.Rated != 6 || deploy.NoData != 1 {
+		t.Errorf("deploy frequency rated = %d, no data = %d, want 6 and 1", deploy.Rated, deploy.NoData)
+	}
+	// (40+2+6+0.5+1+30)/6 = 13.25 回/月
+	if deploy.Average != 13.25 || deploy.Rating != domain.DORARatingHigh {
+		t.Errorf("deploy frequency = %v (%s), want 13.25 (High)", deploy.Average, deploy.Rating)
+	}
+	if deploy.Ratings[0].Count != 2 || deploy.Ratings[2].Count != 2 {
+		t.Errorf("deploy frequency ratings = %+v, want 2 Elite and 2 Medium", deploy.Ratings)
+	}
+	if cfr := data.DORA[1]; cfr.Rated != 0 || cfr.Rating != "N/A" {
+		t.Errorf("change failure rate = %+v, want N/A without rated repositories", cfr)
+	}
+
+	if len(data.RiskTypes) != 2 {
+		t.Fatalf("risk types = %+v, want 2", data.RiskTypes)
+	}
+	if got := data.RiskTypes[0]; got.Type != "深夜労働" || got.Repos != 3 || got.Count != 4 || got.High != 4 {
+		t.Errorf("risk types[0] = %+v, want late-night work in 3 repos, 4 times", got)
+	}
+	if got := data.RiskTypes[1]; got.Type != "巨大ファイル" || got.Repos != 2 {
+		t.Errorf("risk types[1] = %+v, want large files in 2 repos", got)
+	}
+}
+
+func TestPrepareRollupTemplateData_CustomDORABands(t *testing.T) {
+	results := newRollupResults()
+	for _, r := range results {
+		r.DORABands = domain.DORARatingBands{
+			DeployFrequency:   domain.DORABands{Elite: 10, High: 5, Medium: 1},
+			ChangeFailureRate: domain.DefaultDORARatingBands.ChangeFailureRate,
+			MTTR:              domain.DefaultDORARatingBands.MTTR,
+		}
+	}
+	data := NewService().prepareRollupTemplateData(results, nil)
+	if got := data.DORA[0].Rating; got != domain.DORARatingElite {
+		t.Errorf("deploy frequency rating = %s, want Elite with the configured bands", got)
+	}
+}
+
+func TestRollupLink(t *testing.T) {
+	repo := domain.NewRepository("acme", "api")
+	tests := []struct {
+		name       string
+		outputPath string
+		reportPath string
+		want       string
+	}{
+		{"one file per repository", "reports/rollup.html", "reports/{repo}.html", "acme-api.html"},
+		{"reports in a subdirectory", "rollup.html", "reports/{repo}.html", "reports/acme-api.html"},
+		{"combined report", "out/rollup.html", "out/all.html", "all.html#repo-2"},
+		{"bundle", "site/rollup.html", "site/index.html", "index.html#repo-2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rollupLink(tt.outputPath, tt.reportPath, repo, 2, 3); got != tt.want {
+				t.Errorf("rollupLink() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if got := rollupLink("rollup.html", "report.html", repo, 0, 1); got != "report.html" {
+		t.Errorf("rollupLink() for a single report = %q, want report.html", got)
+	}
+}
+
+func TestGenerateRollup(t *testing.T) {
+	dir := t.TempDir()
+	results := newRollupResults()
+	path := filepath.Join(dir, "rollup.html")
+	if err := NewService().GenerateRollup(results, path, filepath.Join(dir, "{repo}.html")); err != nil {
+		t.Fatalf("GenerateRollup() error = %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(b)
+	for _, want := range []string{
+		"組織全体の概要（7リポジトリ）",
+		`<div class="score">59 / 100</div>`,
+		`<a href="acme-web.html">acme/web</a>`,
+		"スコアの低いリポジトリ",
+		"データ不足でスコアを算出していない1リポジトリは",
+		"13.2回/月",
+		`<div class="dist-fill grade-a" style="width: 33%">`,
+		"<td>深夜労働</td>",
+		`<td class="num">3 / 7</td>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("rollup does not contain %q", want)
+		}
+	}
+
+	t.Run("without links", func(t *testing.T) {
+		var b strings.Builder
+		if err := NewService(WithLang(i18n.English)).renderRollup(&b, results, nil); err != nil {
+			t.Fatalf("renderRollup() error = %v", err)
+		}
+		if strings.Contains(b.String(), "<a href") {
+			t.Error("rollup links to reports without report paths")
+		}
+		if !strings.Contains(b.String(), "Organization overview (7 repositories)") {
+			t.Error("rollup is not translated")
+		}
+	})
+
+	t.Run("no results", func(t *testing.T) {
+		if err := NewService().GenerateRollup(nil, path, ""); err == nil {
+			t.Error("expected error for empty results")
+		}
+	})
+}
@@ -0,0 +1,203 @@
+package report
+
+import (
+	"io"
+	"sort"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+// SARIF のバージョンとスキーマ。
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// sarifToolName / sarifToolURI は SARIF の tool.driver に書くツール情報。
+const (
+	sarifToolName = "lokup"
+	sarifToolURI  = "https://github.com/ryuka-games/lokup"
+)
+
+// sarifLog は SARIF 2.1.0 のログ（ルート）。
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+// sarifRun は1リポジトリ分の実行結果。
+type sarifRun struct {
+	Tool                     sarifTool                    `json:"tool"`
+	AutomationDetails        sarifAutomationDetails       `json:"automationDetails"`
+	VersionControlProvenance []sarifVersionControlDetails `json:"versionControlProvenance"`
+	Results                  []sarifResult                `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+// sarifRule はリスクの種類1つ分のルール。
+type sarifRule struct {
+	ID               string              `json:"id"`
+	ShortDescription sarifMessage        `json:"shortDescription"`
+	Help             sarifMessage        `json:"help"`
+	Properties       sarifRuleProperties `json:"properties"`
+}
+
+type sarifRuleProperties struct {
+	Category string `json:"category"`
+}
+
+// sarifAutomationDetails は同じリポジトリの実行同士を突き合わせるための ID。
+type sarifAutomationDetails struct {
+	ID string `json:"id"`
+}
+
+type sarifVersionControlDetails struct {
+	RepositoryURI string `json:"repositoryUri"`
+}
+
+// sarifResult は検出したリスク1件。
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"` // リポジトリ全体のリスクでは省く
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"` // リポジトリルートからの相対パス
+}
+
+// sarifLevel は重大度を SARIF の level に変換する。
+func sarifLevel(s domain.Severity) string {
+	switch s {
+	case domain.SeverityHigh:
+		return "error"
+	case domain.SeverityMedium:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// writeSARIF は分析結果のリスクを SARIF 2.1.0 で書き出す。
+// リポジトリごとに1つの run にする。
+func (s *Service) writeSARIF(w io.Writer, results []*domain.AnalysisResult) error {
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    make([]sarifRun, len(results)),
+	}
+	for i, r := range results {
+		log.Runs[i] = s.buildSARIFRun(r)
+	}
+	return writeJSON(w, log)
+}
+
+// buildSARIFRun は1リポジトリ分のリスクを SARIF の run に変換する。
+//
+// ファイルを指すリスクは physicalLocation 付きの結果にする。変更集中はリスクごと、
+// 巨大ファイルとファイル単位の属人化はリスクが件数でまとめられているため、
+// ドリルダウンの一覧（上位のみ）のファイルごとに結果を作る。一覧がなければまとめたリスクをそのまま出す。
+func (s *Service) buildSARIFRun(r *domain.AnalysisResult) sarifRun {
+	risks := sortedRisks(r.Risks)
+
+	// 検出したリスクの種類だけをルールにする（ID 順）
+	var types []domain.RiskType
+	ruleIndex := make(map[domain.RiskType]int)
+	for _, risk := range risks {
+		if _, ok := ruleIndex[risk.Type]; !ok {
+			ruleIndex[risk.Type] = 0
+			types = append(types, risk.Type)
+		}
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	rules := make([]sarifRule, len(types))
+	for i, rt := range types {
+		ruleIndex[rt] = i
+		rules[i] = sarifRule{
+			ID:               string(rt),
+			ShortDescription: sarifMessage{Text: s.lang.T(rt.DisplayName())},
+			Help:             sarifMessage{Text: s.lang.T(riskTypeToAction(rt))},
+			Properties:       sarifRuleProperties{Category: string(rt.Category())},
+		}
+	}
+
+	results := []sarifResult{} // 検出なしも空配列で出す（null は SARIF として不正）
+	add := func(rt domain.RiskType, severity domain.Severity, message, path string) {
+		res := sarifResult{
+			RuleID:    string(rt),
+			RuleIndex: ruleIndex[rt],
+			Level:     sarifLevel(severity),
+			Message:   sarifMessage{Text: message + "\n" + s.lang.T(riskTypeToAction(rt))},
+		}
+		if path != "" {
+			res.Locations = []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: path},
+			}}}
+		}
+		results = append(results, res)
+	}
+
+	largeFilesListed := false
+	for _, risk := range risks {
+		switch {
+		case risk.Type == domain.RiskTypeChangeConcentration:
+			add(risk.Type, risk.Severity, s.lang.T("期間内に%d回変更されています", risk.Value), risk.Target)
+		case risk.Type == domain.RiskTypeLargeFile && len(r.LargeFiles) > 0:
+			// 重大度ごとに2つのリスクがあるが、ファイルの一覧は1つなので一度だけ出す
+			if largeFilesListed {
+				continue
+			}
+			largeFilesListed = true
+			for _, f := range r.LargeFiles {
+				add(risk.Type, f.Severity, s.lang.T("%dKBの巨大ファイルです", f.SizeKB), f.Path)
+			}
+		case risk.Type == domain.RiskTypeFileOwnership && len(r.SiloFiles) > 0:
+			for _, f := range r.SiloFiles {
+				add(risk.Type, f.Severity, s.lang.T("%s が変更の%d%%を占めています（%d回中）", f.Author, f.Share, f.Changes), f.Path)
+			}
+		default:
+			message := risk.Description
+			if message == "" {
+				message = s.lang.T(risk.Type.DisplayName()) + ": " + risk.Target
+			}
+			add(risk.Type, risk.Severity, message, "")
+		}
+	}
+
+	return sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           sarifToolName,
+			InformationURI: sarifToolURI,
+			Rules:          rules,
+		}},
+		AutomationDetails: sarifAutomationDetails{ID: sarifToolName + "/" + r.Repository.FullName() + "/"},
+		VersionControlProvenance: []sarifVersionControlDetails{
+			{RepositoryURI: "https://github.com/" + r.Repository.FullName()},
+		},
+		Results: results,
+	}
+}
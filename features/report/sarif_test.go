@@ -0,0 +1,104 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	r := newTestResult()
+	r.Risks = append(r.Risks,
+		domain.Risk{Type: domain.RiskTypeLargeFile, Severity: domain.SeverityHigh, Target: "1件", Value: 1, Threshold: 100},
+		domain.Risk{Type: domain.RiskTypeLargeFile, Severity: domain.SeverityMedium, Target: "1件", Value: 1, Threshold: 50},
+		domain.Risk{Type: domain.RiskTypeFileOwnership, Severity: domain.SeverityMedium, Target: "1件", Value: 1, Threshold: 90},
+	)
+	r.LargeFiles = []domain.LargeFile{
+		{Path: "dist/app.js", SizeKB: 120, Severity: domain.SeverityHigh},
+		{Path: "src/big.go", SizeKB: 60, Severity: domain.SeverityMedium},
+	}
+	r.SiloFiles = []domain.SiloFile{
+		{Path: "billing/invoice.go", Author: "alice", Changes: 8, Share: 100, Severity: domain.SeverityHigh},
+	}
+
+	var b strings.Builder
+	if err := NewService().writeSARIF(&b, []*domain.AnalysisResult{r}); err != nil {
+		t.Fatalf("writeSARIF() error = %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal([]byte(b.String()), &log); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || log.Schema == "" || len(log.Runs) != 1 {
+		t.Fatalf("log = version %q, schema %q, %d runs", log.Version, log.Schema, len(log.Runs))
+	}
+	run := log.Runs[0]
+
+	// ルールは検出した種類だけ、ID 順
+	var ruleIDs []string
+	for _, rule := range run.Tool.Driver.Rules {
+		ruleIDs = append(ruleIDs, rule.ID)
+	}
+	wantRules := "change_concentration,file_ownership,large_file,late_night"
+	if got := strings.Join(ruleIDs, ","); got != wantRules {
+		t.Errorf("rules = %s, want %s", got, wantRules)
+	}
+
+	type want struct {
+		level string
+		path  string
+	}
+	got := make(map[string][]want)
+	for _, res := range run.Results {
+		if run.Tool.Driver.Rules[res.RuleIndex].ID != res.RuleID {
+			t.Errorf("%s: ruleIndex %d points at %s", res.RuleID, res.RuleIndex, run.Tool.Driver.Rules[res.RuleIndex].ID)
+		}
+		if res.Message.Text == "" {
+			t.Errorf("%s: empty message", res.RuleID)
+		}
+		path := ""
+		if len(res.Locations) > 0 {
+			path = res.Locations[0].PhysicalLocation.ArtifactLocation.URI
+		}
+		got[res.RuleID] = append(got[res.RuleID], want{res.Level, path})
+	}
+
+	tests := map[string][]want{
+		"change_concentration": {{"error", "src/main.go"}},
+		// 重大度ごとの2つのリスクではなく、一覧のファイルごとに1件
+		"large_file":     {{"error", "dist/app.js"}, {"warning", "src/big.go"}},
+		"file_ownership": {{"error", "billing/invoice.go"}},
+		// リポジトリ全体のリスクは位置なし
+		"late_night": {{"warning", ""}},
+	}
+	for rule, w := range tests {
+		if len(got[rule]) != len(w) {
+			t.Errorf("%s: results = %v, want %v", rule, got[rule], w)
+			continue
+		}
+		for i := range w {
+			if got[rule][i] != w[i] {
+				t.Errorf("%s[%d] = %v, want %v", rule, i, got[rule][i], w[i])
+			}
+		}
+	}
+}
+
+func TestWriteSARIF_NoRisks(t *testing.T) {
+	r := newTestResult()
+	r.Risks = nil
+
+	var b strings.Builder
+	if err := NewService().writeSARIF(&b, []*domain.AnalysisResult{r}); err != nil {
+		t.Fatalf("writeSARIF() error = %v", err)
+	}
+	// results・rules は null ではなく空配列
+	for _, want := range []string{`"results": []`, `"rules": []`} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("output does not contain %s\n%s", want, b.String())
+		}
+	}
+}
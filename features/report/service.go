@@ -1,15 +1,19 @@
-// Package report は HTML レポート生成機能を提供する。
+// Package report は HTML / JSON / Markdown レポート生成機能を提供する。
 package report
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
+	"io"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/shared/i18n"
 )
 
 // templateFuncs はテンプレートで使用する関数。
@@ -24,6 +28,9 @@ var templateFuncs = template.FuncMap{
 	"gt": func(a, b int) bool {
 		return a > b
 	},
+	"gtFloat": func(a, b float64) bool {
+		return a > b
+	},
 	"lt": func(a, b int) bool {
 		return a < b
 	},
@@ -36,23 +43,192 @@ var templateFuncs = template.FuncMap{
 }
 
 // Service はレポート生成のビジネスロジックを担当する。
-type Service struct{}
+type Service struct {
+	// レポートの出力言語（ゼロ値なら日本語）
+	lang i18n.Lang
+}
+
+// Option は Service の設定を変更する。
+type Option func(*Service)
+
+// WithLang はレポートの出力言語を設定する。
+func WithLang(lang i18n.Lang) Option {
+	return func(s *Service) {
+		s.lang = lang
+	}
+}
 
 // NewService は Service を生成する。
-func NewService() *Service {
-	return &Service{}
+func NewService(opts ...Option) *Service {
+	s := &Service{}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
+// RepoPlaceholder は出力パス中でリポジトリ名に置換されるプレースホルダ。
+// 例: "reports/{repo}.html" → "reports/facebook-react.html"
+const RepoPlaceholder = "{repo}"
+
+// StdoutPath は標準出力を表す出力パス。
+// パイプで他のツールに渡すときに --output - として使う。
+const StdoutPath = "-"
+
+// topActionsLimit はレポートの先頭に出す「最優先の改善」の件数。
+const topActionsLimit = 3
+
 // Generate は分析結果から HTML レポートを生成する。
-func (s *Service) Generate(result *domain.AnalysisResult, outputPath string) (err error) {
-	// テンプレートデータの準備
-	data := s.prepareTemplateData(result)
+func (s *Service) Generate(result *domain.AnalysisResult, outputPath string) error {
+	return s.generateOne(result, outputPath, FormatHTML)
+}
 
-	// テンプレート解析
-	tmpl, err := template.New("report").Funcs(templateFuncs).Parse(htmlTemplate)
+// Render は分析結果のレポートを指定形式で w に書き出す。
+func (s *Service) Render(w io.Writer, result *domain.AnalysisResult, format Format) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, s.buildJSONReport(result))
+	case FormatMarkdown:
+		return s.GenerateMarkdown(result, w)
+	case FormatPrometheus:
+		return writePrometheus(w, []*domain.AnalysisResult{result})
+	case FormatSARIF:
+		return s.writeSARIF(w, []*domain.AnalysisResult{result})
+	default:
+		data, err := s.prepareTemplateData(result)
+		if err != nil {
+			return err
+		}
+		return s.executeTemplate(w, "report", htmlTemplate, data)
+	}
+}
+
+// GenerateAll は複数リポジトリの分析結果からレポートを生成し、出力したファイルパスを返す。
+//
+// outputPath に RepoPlaceholder が含まれる場合はリポジトリごとに個別ファイルを出力し、
+// 含まれない場合は全リポジトリをまとめた1つの統合レポートを出力する。
+// 結果が1件だけなら統合せず、通常の詳細レポートを出力する。
+// format が FormatJSON の場合、統合レポートは JSONMultiReport になり、
+// FormatMarkdown の場合は各リポジトリのサマリーを区切り線でつなぎ、
+// FormatPrometheus の場合は repo ラベルで区別した1つのメトリクス一覧になり、
+// FormatSARIF の場合はリポジトリごとの run を並べた1つの SARIF ログになる。
+// outputPath が StdoutPath なら標準出力に書き出す。
+func (s *Service) GenerateAll(results []*domain.AnalysisResult, outputPath string, format Format) ([]string, error) {
+	if len(results) == 0 {
+		return nil, errors.New("no analysis results to report")
+	}
+
+	// リポジトリごとに個別ファイル
+	if strings.Contains(outputPath, RepoPlaceholder) {
+		paths := make([]string, 0, len(results))
+		for _, r := range results {
+			path := ExpandOutputPath(outputPath, r.Repository)
+			if err := s.generateOne(r, path, format); err != nil {
+				return paths, fmt.Errorf("%s: %w", r.Repository.FullName(), err)
+			}
+			paths = append(paths, path)
+		}
+		return paths, nil
+	}
+
+	err := writeFile(outputPath, func(w io.Writer) error {
+		return s.renderAll(w, results, format)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []string{outputPath}, nil
+}
+
+// renderAll は複数リポジトリの統合レポートを w に書き出す。
+// 結果が1件だけなら通常の詳細レポートになる。
+func (s *Service) renderAll(w io.Writer, results []*domain.AnalysisResult, format Format) error {
+	if len(results) == 1 {
+		return s.Render(w, results[0], format)
+	}
+
+	switch format {
+	case FormatJSON:
+		multi := JSONMultiReport{
+			SchemaVersion: jsonSchemaVersion,
+			Repositories:  make([]JSONReport, len(results)),
+		}
+		for i, r := range results {
+			multi.Repositories[i] = s.buildJSONReport(r)
+		}
+		return writeJSON(w, multi)
+	case FormatMarkdown:
+		for i, r := range results {
+			if i > 0 {
+				if _, err := io.WriteString(w, "\n---\n\n"); err != nil {
+					return fmt.Errorf("failed to write markdown: %w", err)
+				}
+			}
+			if err := s.GenerateMarkdown(r, w); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatPrometheus:
+		return writePrometheus(w, results)
+	case FormatSARIF:
+		return s.writeSARIF(w, results)
+	default:
+		data, err := s.prepareMultiTemplateData(results)
+		if err != nil {
+			return err
+		}
+		return s.executeTemplate(w, "multi", multiHTMLTemplate, data)
+	}
+}
+
+// generateOne は1リポジトリ分のレポートを指定形式で出力する。
+func (s *Service) generateOne(result *domain.AnalysisResult, outputPath string, format Format) error {
+	return writeFile(outputPath, func(w io.Writer) error {
+		return s.Render(w, result, format)
+	})
+}
+
+// ExpandOutputPath は出力パス中の RepoPlaceholder をリポジトリ名に置換する。
+// ファイル名に使えない "/" は "-" に置き換える。
+func ExpandOutputPath(outputPath string, repo domain.Repository) string {
+	name := repo.Owner + "-" + repo.Name
+	return strings.ReplaceAll(outputPath, RepoPlaceholder, name)
+}
+
+// executeTemplate はテンプレートを解析・実行して w に書き出す。
+//
+// 共通の templateFuncs に加え、出力言語の翻訳関数を渡す。
+//   - t: 文言を翻訳する（通常どおりエスケープされる）
+//   - th: <strong> などのタグを含む文言を翻訳する。埋め込む引数はエスケープする
+func (s *Service) executeTemplate(w io.Writer, name, text string, data any) error {
+	funcs := template.FuncMap{
+		"t": s.lang.T,
+		"th": func(msg string, args ...any) template.HTML {
+			for i, a := range args {
+				if str, ok := a.(string); ok {
+					args[i] = template.HTMLEscapeString(str)
+				}
+			}
+			return template.HTML(s.lang.T(msg, args...))
+		},
+	}
+	tmpl, err := template.New(name).Funcs(templateFuncs).Funcs(funcs).Parse(text)
 	if err != nil {
 		return fmt.Errorf("failed to parse template: %w", err)
 	}
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	return nil
+}
+
+// writeFile はファイルを作成し、write で内容を書き出す。
+// outputPath が StdoutPath なら標準出力に書き出す。
+func writeFile(outputPath string, write func(w io.Writer) error) (err error) {
+	if outputPath == StdoutPath {
+		return write(os.Stdout)
+	}
 
 	// ファイル作成
 	file, err := os.Create(outputPath)
@@ -65,20 +241,67 @@ func (s *Service) Generate(result *domain.AnalysisResult, outputPath string) (er
 		}
 	}()
 
-	// テンプレート実行
-	if err := tmpl.Execute(file, data); err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
+	return write(file)
+}
+
+// MultiTemplateData は統合レポートのテンプレートに渡すデータ。
+type MultiTemplateData struct {
+	Lang       string // <html lang> に使う言語コード
+	PeriodFrom string
+	PeriodTo   string
+	PeriodDays int
+	Path       string // 対象パス（空ならリポジトリ全体）
+	Branch     string // 対象ブランチ（空ならデフォルトブランチ）
+
+	// リポジトリごとのデータ（入力順）
+	Repositories []TemplateData
+
+	GeneratedAt string
+}
+
+// prepareMultiTemplateData は複数の分析結果から統合レポートのデータを準備する。
+// 分析期間と生成日時は先頭の結果のものを使う（同一実行内では共通のため）。
+func (s *Service) prepareMultiTemplateData(results []*domain.AnalysisResult) (MultiTemplateData, error) {
+	repos := make([]TemplateData, len(results))
+	for i, r := range results {
+		data, err := s.prepareTemplateData(r)
+		if err != nil {
+			return MultiTemplateData{}, fmt.Errorf("%s: %w", r.Repository.FullName(), err)
+		}
+		repos[i] = data
 	}
 
-	return nil
+	first := results[0]
+	return MultiTemplateData{
+		Lang:         s.langCode(),
+		PeriodFrom:   first.Period.From.Format("2006-01-02"),
+		PeriodTo:     first.Period.To.Format("2006-01-02"),
+		PeriodDays:   first.Period.Days(),
+		Path:         first.Path,
+		Branch:       first.Branch,
+		Repositories: repos,
+		GeneratedAt:  first.GeneratedAt.Format("2006-01-02 15:04:05"),
+	}, nil
 }
 
 // TemplateData はテンプレートに渡すデータ。
 type TemplateData struct {
+	Lang       string // <html lang> に使う言語コード
 	Repository string
 	PeriodFrom string
 	PeriodTo   string
 	PeriodDays int
+	Path       string // 対象パス（空ならリポジトリ全体）
+	Branch     string // 対象ブランチ（空ならデフォルトブランチ）
+
+	// 期間内の活動がなく、スコアの代わりにデータ不足の案内を出す
+	InsufficientData bool
+
+	// クイックモード（PRサイズ・レビュー系・依存のメトリクスは未計算で、プレースホルダーを出す）
+	QuickMode bool
+
+	// ファイル一覧の一部しか取得できず、ファイル数・巨大ファイルなどが少なく出ている
+	FilesTruncated bool
 
 	// 総合スコア
 	OverallScore      int
@@ -92,10 +315,20 @@ type TemplateData struct {
 	// メトリクス値
 	TotalCommits      int
 	FeatureAddition   float64
+	MergedPRCount     int
+	PRThroughput      float64 // マージされたPR数/週
 	Contributors      int
 	LateNightRate     float64
+	WeekendRate       float64
+	ActiveAuthors     int
+	NewAuthors        int
+	ChurnedAuthors    int
 	AvgLeadTime       float64
+	LeadTimeP50       float64
+	LeadTimeP75       float64
+	LeadTimeP90       float64
 	AvgReviewWaitTime float64
+	AvgFirstResponse  float64
 	OpenPRCount       int
 	OpenIssueCount    int
 	BugFixRatio       float64
@@ -103,17 +336,35 @@ type TemplateData struct {
 	IssueCloseRate    float64
 	IssuesCreated     int
 	IssuesClosed      int
+	ReviewCoverage    float64
+	ReviewCoveragePRs int
+	SelfMergeCount    int
+	SelfMergeRate     float64
 	FeaturePRCount    int
 	BugFixPRCount     int
 	OtherPRCount      int
+	AbandonedPRCount  int
+	AbandonmentRate   float64
 
 	// DORA メトリクス
-	DeployFrequency   float64
-	DeployFreqRating  string
-	ChangeFailureRate float64
-	ChangeFailRating  string
-	MTTR              float64
-	MTTRRating        string
+	DeployFrequency  float64
+	DeployFreqRating string
+	// 分析期間が短すぎてデプロイ頻度を評価していないか
+	DeployFreqWindowTooShort bool
+	MinDeployFreqWindowDays  int
+	ChangeFailureRate        float64
+	ChangeFailRating         string
+	MTTR                     float64
+	MTTRRating               string
+	// DORA レーティングの境界（CustomDORABands ならデフォルトから変更していて、診断に境界値を出す）
+	DORABands       domain.DORARatingBands
+	CustomDORABands bool
+
+	// リリース負債（LastReleaseDate が空なら期間の終わりまでにリリースがない）
+	LastReleaseDate         string
+	LastReleaseName         string
+	DaysSinceLastRelease    int
+	CommitsSinceLastRelease int
 
 	// 投資比率
 	RefactorPRCount int
@@ -123,38 +374,118 @@ type TemplateData struct {
 	// コードチャーン
 	RevertCommitCount int
 	RevertRate        float64
+	// 元のコミットから Revert までの時間（元のコミットを特定できた Revert だけ）
+	FastRevertCount      int
+	AvgTimeToRevertHours float64
+
+	// コミットメッセージの品質
+	LowQualityCommitCount int
+	LowQualityCommitRate  float64
+
+	// 共同作成（Co-authored-by）
+	CoAuthoredCommitCount int
+	CoAuthorshipRate      float64
+
+	// コミットの粒度
+	SizedCommitCount  int
+	AvgFilesPerCommit float64
+
+	// テストファイルの比率（ファイル名による推定）
+	TestFileCount     int
+	SourceFileCount   int
+	TestToSourceRatio float64
 
 	// チーム
 	TotalFiles int
 
+	// コミュニティヘルス（CONTRIBUTING.md などの揃っている割合と、ファイルごとの有無）
+	CommunityHealthScore int
+	CommunityFiles       []CommunityFileData
+
 	// トレンド
 	TrendsJSON template.JS
 
-	// 技術的負債
+	// ベースライン比較（--baseline 指定時のみ）
+	Baseline *BaselineData
+
+	// スコアの推移（--history 指定時のみ、古い順）
+	History                []HistoryPointData
+	HistoryJSON            template.JS
+	HistoryCurrentSnapshot bool // 現在の状態で採点した過去の期間を含む（表の下に注記を出す）
+
+	// 判定基準（判定に使った閾値。フッターに表示する）
+	Thresholds       []ThresholdData
+	CustomThresholds int // デフォルトから変更した閾値の数
+
+	// データの取得状況（上限・取得失敗でデータが欠けていないか）
+	DataSources           []DataSourceData
+	IncompleteDataSources int // 欠けているデータの種類の数
+
+	// 技術的負債（巨大ファイルの件数は全件、一覧はサイズの大きい順に上位のみ）
 	LargeFileCount   int
 	LargeFiles       []LargeFileData
 	OutdatedDepCount int
 	OutdatedDeps     []OutdatedDepData
+	// 依存の全体像（「古い依存5件」が全体の何件中かを示す）
+	DependencyCount      int
+	OutdatedDepRate      float64
+	DependencyEcosystems []DependencyEcosystemData
+	// 既知の脆弱性（--check-vulns 指定時のみ。VulnCheckedDeps が 0 なら調べていない）
+	VulnCheckedDeps int
+	VulnerableDeps  []VulnerableDepData
+	LicenseFile     string // 検出したライセンスファイル（なければ空）
+
+	// 巨大ファイルの対象外にしたバイナリ・生成物（件数は全件、一覧はサイズの大きい順に上位のみ）
+	LargeNonSourceCount int
+	LargeNonSource      []LargeFileData
+
+	// デフォルトブランチの保護設定（権限不足などで読めなければ nil）
+	BranchProtection *BranchProtectionData
+
+	// TODO コメント（--scan-todos 指定時のみ。TodoScannedFiles が 0 なら走査していない）
+	TodoCount        int
+	TodoDensity      float64
+	TodoScannedFiles int
+	TodoScannedLines int
+	TodoFiles        []TodoFileData
 
 	// リスク
 	Risks    []RiskData
 	HasRisks bool
+	// 最優先で取り組むべき改善（重大度 → カテゴリの重みの順に並べ、改善提案の重複を除いた上位のみ）
+	TopActions []RiskData
+
+	// 変更集中（件数は全件、一覧は変更回数の多い順に上位のみ）
+	HotFileCount int
+	HotFiles     []HotFileData
 
-	// 変更集中リスク一覧（ドリルダウンテーブル用）
-	ChangeConcentrationRisks []RiskData
+	// ファイル単位の属人化（件数は全件、一覧は変更回数の多い順に上位のみ）
+	SiloFileCount int
+	SiloFiles     []SiloFileData
 
 	// グラフ用データ
 	CommitsByDay    []int
 	CommitDayLabels []string
+	PRSizeBuckets   []int // PRサイズ分布（prSizeBucketBounds の区間ごとのPR数）
 
 	// ドリルダウン用JSON（template.JS で安全にスクリプトに埋め込み）
 	PRDetailsJSON          template.JS
 	ContributorDetailsJSON template.JS
 	HourlyCommitsJSON      template.JS
+	CommitHeatmapJSON      template.JS // 曜日（日曜が0）×時間帯別コミット数 [7][24]
+	WeekdayLabelsJSON      template.JS // ヒートマップの曜日ラベル（日曜始まり）
 
 	GeneratedAt string
 }
 
+// BranchProtectionData はデフォルトブランチの保護設定のテンプレートデータ。
+type BranchProtectionData struct {
+	Branch               string
+	Protected            bool
+	RequiredApprovals    int
+	RequiredStatusChecks bool
+}
+
 // CategoryScoreData はカテゴリスコアのテンプレートデータ。
 type CategoryScoreData struct {
 	Icon       string // 📈, ✅, ⚠️, 💚
@@ -194,6 +525,10 @@ type PRDetailData struct {
 	Additions       int     `json:"additions"`
 	Deletions       int     `json:"deletions"`
 	ReviewWaitHours float64 `json:"reviewWaitHours"`
+	// 最初の人による反応（作成者以外のレビューかコメント）までの時間
+	FirstResponseHours float64 `json:"firstResponseHours"`
+	Reviewed           bool    `json:"reviewed"`
+	SelfMerged         bool    `json:"selfMerged"`
 }
 
 // ContributorDetailData はコントリビューター詳細のJSON用データ。
@@ -203,6 +538,16 @@ type ContributorDetailData struct {
 	Ratio   float64 `json:"ratio"`
 }
 
+// HistoryPointData はスコア推移の1点（グラフ用に JSON にもする）。
+type HistoryPointData struct {
+	From             string `json:"from"` // "2006-01-02"
+	To               string `json:"to"`
+	Score            int    `json:"score"`
+	Grade            string `json:"grade"`
+	InsufficientData bool   `json:"insufficientData"` // データ不足でスコアが健全さを表さない（グラフでは点を打たない）
+	CurrentSnapshot  bool   `json:"currentSnapshot"`  // ファイルツリー・依存などは期間当時でなく分析時点の状態で採点した
+}
+
 // LargeFileData は巨大ファイル情報。
 type LargeFileData struct {
 	Path        string
@@ -210,6 +555,22 @@ type LargeFileData struct {
 	SeverityStr string
 }
 
+// HotFileData は変更集中ファイル情報。
+type HotFileData struct {
+	Path         string
+	Changes      int
+	SeverityIcon string
+}
+
+// SiloFileData は1人の作成者が変更の大半を占めるファイル情報。
+type SiloFileData struct {
+	Path         string
+	Author       string
+	Changes      int
+	Share        int
+	SeverityIcon string
+}
+
 // OutdatedDepData は古い依存情報。
 type OutdatedDepData struct {
 	Name        string
@@ -218,11 +579,42 @@ type OutdatedDepData struct {
 	SeverityStr string
 }
 
+// VulnerableDepData は既知の脆弱性がある依存。
+type VulnerableDepData struct {
+	Name      string
+	Version   string
+	Ecosystem string
+	IDs       []string
+}
+
+// DependencyEcosystemData はエコシステムごとの依存数。
+type DependencyEcosystemData struct {
+	Ecosystem    string
+	Count        int
+	Outdated     int
+	OutdatedRate float64 // そのエコシステムで古い依存の割合（%）
+}
+
+// TodoFileData は TODO コメントのあるファイル情報。
+type TodoFileData struct {
+	Path  string
+	Count int
+}
+
+// CommunityFileData はコミュニティヘルスファイル1種類の有無。
+type CommunityFileData struct {
+	Name    string
+	Present bool
+	Action  string // 不足しているときの改善提案
+}
+
 // prepareTemplateData は分析結果からテンプレートデータを準備する。
-func (s *Service) prepareTemplateData(r *domain.AnalysisResult) TemplateData {
+// スクリプトに埋め込む JSON を作れなければエラーを返す（壊れたレポートを出さないため）。
+func (s *Service) prepareTemplateData(r *domain.AnalysisResult) (TemplateData, error) {
 	// リスクデータを変換
+	// 一覧は上位のみのため、件数はリスク（全件から集計）から数える
 	risks := make([]RiskData, len(r.Risks))
-	var changeConcentrationRisks []RiskData
+	var hotFileCount, largeFileCount, siloFileCount int
 	for i, risk := range r.Risks {
 		severity := "low"
 		icon := "🟢"
@@ -237,40 +629,70 @@ func (s *Service) prepareTemplateData(r *domain.AnalysisResult) TemplateData {
 		rd := RiskData{
 			Severity:     severity,
 			SeverityIcon: icon,
-			Type:         risk.Type.DisplayName(),
+			Type:         s.lang.T(risk.Type.DisplayName()),
 			Description:  risk.Description,
 			Target:       risk.Target,
-			Action:       riskTypeToAction(risk.Type),
+			Action:       s.lang.T(riskTypeToAction(risk.Type)),
 		}
 		risks[i] = rd
 
-		if risk.Type == domain.RiskTypeChangeConcentration {
-			changeConcentrationRisks = append(changeConcentrationRisks, rd)
+		switch risk.Type {
+		case domain.RiskTypeChangeConcentration:
+			hotFileCount++
+		case domain.RiskTypeLargeFile:
+			largeFileCount += risk.Value
+		case domain.RiskTypeFileOwnership:
+			siloFileCount += risk.Value
 		}
 	}
 
 	// カテゴリスコアを変換
 	categories := s.buildCategoryScoreData(r.CategoryScores)
+	topActions := buildTopActions(r, risks, topActionsLimit)
 
 	// 日別コミットデータをグラフ用に変換
 	commitsByDay := make([]int, len(r.DailyCommits))
 	commitDayLabels := make([]string, len(r.DailyCommits))
 	for i, dc := range r.DailyCommits {
 		commitsByDay[i] = dc.Count
-		commitDayLabels[i] = formatDateWithWeekday(dc.Date)
+		commitDayLabels[i] = formatDateWithWeekday(dc.Date, s.lang)
 	}
 
 	// 巨大ファイルデータを変換
-	largeFiles := make([]LargeFileData, len(r.LargeFiles))
-	for i, lf := range r.LargeFiles {
-		severityStr := "medium"
-		if lf.Severity == domain.SeverityHigh {
-			severityStr = "high"
+	largeFiles := toLargeFileData(r.LargeFiles)
+
+	// リスクを伴わない結果（テスト用の組み立てなど）でも一覧の件数は下回らない
+	largeFileCount = max(largeFileCount, len(r.LargeFiles))
+	hotFileCount = max(hotFileCount, len(r.HotFiles))
+	siloFileCount = max(siloFileCount, len(r.SiloFiles))
+
+	// 変更集中ファイルデータを変換
+	hotFiles := make([]HotFileData, len(r.HotFiles))
+	for i, hf := range r.HotFiles {
+		icon := "🟡"
+		if hf.Severity == domain.SeverityHigh {
+			icon = "🔴"
 		}
-		largeFiles[i] = LargeFileData{
-			Path:        lf.Path,
-			SizeKB:      lf.SizeKB,
-			SeverityStr: severityStr,
+		hotFiles[i] = HotFileData{
+			Path:         hf.Path,
+			Changes:      hf.Changes,
+			SeverityIcon: icon,
+		}
+	}
+
+	// ファイル単位の属人化データを変換
+	siloFiles := make([]SiloFileData, len(r.SiloFiles))
+	for i, sf := range r.SiloFiles {
+		icon := "🟡"
+		if sf.Severity == domain.SeverityHigh {
+			icon = "🔴"
+		}
+		siloFiles[i] = SiloFileData{
+			Path:         sf.Path,
+			Author:       sf.Author,
+			Changes:      sf.Changes,
+			Share:        sf.Share,
+			SeverityIcon: icon,
 		}
 	}
 
@@ -289,33 +711,108 @@ func (s *Service) prepareTemplateData(r *domain.AnalysisResult) TemplateData {
 		}
 	}
 
-	// ドリルダウン用JSONデータ
-	prDetailsJSON := s.marshalPRDetails(r.PRDetails)
-	contributorDetailsJSON := s.marshalContributorDetails(r.ContributorDetails)
-	hourlyCommitsJSON := s.marshalHourlyCommits(r.HourlyCommits)
-	trendsJSON := s.marshalTrends(r.Trends)
+	// エコシステム別の依存数を変換
+	dependencyEcosystems := make([]DependencyEcosystemData, len(r.DependencyEcosystems))
+	for i, e := range r.DependencyEcosystems {
+		dependencyEcosystems[i] = DependencyEcosystemData{
+			Ecosystem:    e.Ecosystem,
+			Count:        e.Count,
+			Outdated:     e.Outdated,
+			OutdatedRate: float64(e.Outdated) / float64(e.Count) * 100,
+		}
+	}
+
+	// 脆弱性のある依存を変換
+	vulnerableDeps := make([]VulnerableDepData, len(r.VulnerableDeps))
+	for i, v := range r.VulnerableDeps {
+		vulnerableDeps[i] = VulnerableDepData{Name: v.Name, Version: v.Version, Ecosystem: v.Ecosystem, IDs: v.IDs}
+	}
+
+	// TODO コメントのあるファイルを変換
+	todoFiles := make([]TodoFileData, len(r.TodoFiles))
+	for i, tf := range r.TodoFiles {
+		todoFiles[i] = TodoFileData{Path: tf.Path, Count: tf.Count}
+	}
+
+	// コミュニティヘルスファイルを変換
+	communityFiles := make([]CommunityFileData, len(r.CommunityFiles))
+	for i, cf := range r.CommunityFiles {
+		communityFiles[i] = CommunityFileData{
+			Name:    cf.Name,
+			Present: cf.Present,
+			Action:  s.lang.T(communityFileAction(cf.Name)),
+		}
+	}
+
+	var branchProtection *BranchProtectionData
+	if bp := r.BranchProtection; bp != nil {
+		branchProtection = &BranchProtectionData{
+			Branch:               bp.Branch,
+			Protected:            bp.Protected,
+			RequiredApprovals:    bp.RequiredApprovals,
+			RequiredStatusChecks: bp.RequiredStatusChecks,
+		}
+	}
+
+	// ドリルダウン・グラフ用JSONデータ
+	var js scriptJSON
+	prDetailsJSON := js.marshal("pr details", toPRDetailData(r.PRDetails))
+	contributorDetailsJSON := js.marshal("contributor details", toContributorDetailData(r.ContributorDetails))
+	hourlyCommitsJSON := js.marshal("hourly commits", r.HourlyCommits[:])
+	commitHeatmapJSON := js.marshal("commit heatmap", r.WeekdayHourCommits)
+	weekdayLabelsJSON := js.marshal("weekday labels", s.weekdayLabels())
+	trendsJSON := js.marshal("trends", r.Trends)
+	history := toHistoryPointData(r.History)
+	historyJSON := js.marshal("history", history)
+	if js.err != nil {
+		return TemplateData{}, js.err
+	}
 
 	overallGrade := r.OverallScore.Grade()
+	thresholds, customThresholds := buildThresholdData(r.Thresholds, s.lang)
+	dataSources, incompleteDataSources := buildDataSourceData(r.DataSources, s.lang)
+	// 境界を持たない結果（古いベースラインから組み立てたものなど）はデフォルトで判定したものとみなす
+	doraBands := r.DORABands
+	if doraBands == (domain.DORARatingBands{}) {
+		doraBands = domain.DefaultDORARatingBands
+	}
 
 	return TemplateData{
+		Lang:       s.langCode(),
 		Repository: r.Repository.FullName(),
 		PeriodFrom: r.Period.From.Format("2006-01-02"),
 		PeriodTo:   r.Period.To.Format("2006-01-02"),
 		PeriodDays: r.Period.Days(),
+		Path:       r.Path,
+		Branch:     r.Branch,
+
+		InsufficientData: r.InsufficientData,
+		QuickMode:        r.QuickMode,
+		FilesTruncated:   r.FilesTruncated,
 
 		OverallScore:      r.OverallScore.Value,
 		OverallGrade:      overallGrade,
 		OverallGradeClass: "grade-" + strings.ToLower(overallGrade),
-		OverallDiagnosis:  generateOverallDiagnosis(overallGrade, categories),
+		OverallDiagnosis:  generateOverallDiagnosis(overallGrade, categories, s.lang),
 
 		Categories: categories,
 
 		TotalCommits:      r.Metrics.TotalCommits,
 		FeatureAddition:   r.Metrics.FeatureAdditionRate,
+		MergedPRCount:     r.Metrics.MergedPRCount,
+		PRThroughput:      r.Metrics.PRThroughputPerWeek,
 		Contributors:      r.Metrics.TotalContributors,
 		LateNightRate:     r.Metrics.LateNightCommitRate,
+		WeekendRate:       r.Metrics.WeekendCommitRate,
+		ActiveAuthors:     r.Metrics.ActiveAuthors,
+		NewAuthors:        r.Metrics.NewAuthors,
+		ChurnedAuthors:    r.Metrics.ChurnedAuthors,
 		AvgLeadTime:       r.Metrics.AvgLeadTime,
+		LeadTimeP50:       r.Metrics.LeadTimeP50,
+		LeadTimeP75:       r.Metrics.LeadTimeP75,
+		LeadTimeP90:       r.Metrics.LeadTimeP90,
 		AvgReviewWaitTime: r.Metrics.AvgReviewWaitTime,
+		AvgFirstResponse:  r.Metrics.AvgFirstResponse,
 		OpenPRCount:       r.Metrics.OpenPRCount,
 		OpenIssueCount:    r.Metrics.OpenIssueCount,
 		BugFixRatio:       r.Metrics.BugFixRatio,
@@ -323,16 +820,31 @@ func (s *Service) prepareTemplateData(r *domain.AnalysisResult) TemplateData {
 		IssueCloseRate:    r.Metrics.IssueCloseRate,
 		IssuesCreated:     r.Metrics.IssuesCreated,
 		IssuesClosed:      r.Metrics.IssuesClosed,
+		ReviewCoverage:    r.Metrics.ReviewCoverageRate,
+		ReviewCoveragePRs: r.Metrics.ReviewCoveragePRs,
+		SelfMergeCount:    r.Metrics.SelfMergeCount,
+		SelfMergeRate:     r.Metrics.SelfMergeRate,
 		FeaturePRCount:    r.Metrics.FeaturePRCount,
 		BugFixPRCount:     r.Metrics.BugFixPRCount,
 		OtherPRCount:      r.Metrics.OtherPRCount,
-
-		DeployFrequency:   r.Metrics.DeployFrequency,
-		DeployFreqRating:  r.Metrics.DeployFreqRating,
-		ChangeFailureRate: r.Metrics.ChangeFailureRate,
-		ChangeFailRating:  r.Metrics.ChangeFailRating,
-		MTTR:              r.Metrics.MTTR,
-		MTTRRating:        r.Metrics.MTTRRating,
+		AbandonedPRCount:  r.Metrics.AbandonedPRCount,
+		AbandonmentRate:   r.Metrics.AbandonmentRate,
+
+		DeployFrequency:          r.Metrics.DeployFrequency,
+		DeployFreqRating:         r.Metrics.DeployFreqRating,
+		DeployFreqWindowTooShort: r.Metrics.DeployFreqRating == domain.DeployFreqRatingWindowTooShort,
+		MinDeployFreqWindowDays:  domain.MinDeployFreqWindowDays,
+		ChangeFailureRate:        r.Metrics.ChangeFailureRate,
+		ChangeFailRating:         r.Metrics.ChangeFailRating,
+		MTTR:                     r.Metrics.MTTR,
+		MTTRRating:               r.Metrics.MTTRRating,
+		DORABands:                doraBands,
+		CustomDORABands:          doraBands != domain.DefaultDORARatingBands,
+
+		LastReleaseDate:         formatReleaseDate(r.Metrics.LastReleaseAt),
+		LastReleaseName:         r.Metrics.LastReleaseName,
+		DaysSinceLastRelease:    r.Metrics.DaysSinceLastRelease,
+		CommitsSinceLastRelease: r.Metrics.CommitsSinceLastRelease,
 
 		RefactorPRCount: r.Metrics.RefactorPRCount,
 		FeatureRatio:    r.Metrics.FeatureRatio,
@@ -341,53 +853,176 @@ func (s *Service) prepareTemplateData(r *domain.AnalysisResult) TemplateData {
 		RevertCommitCount: r.Metrics.RevertCommitCount,
 		RevertRate:        r.Metrics.RevertRate,
 
+		FastRevertCount:      r.Metrics.FastRevertCount,
+		AvgTimeToRevertHours: r.Metrics.AvgTimeToRevertHours,
+
+		LowQualityCommitCount: r.Metrics.LowQualityCommitCount,
+		LowQualityCommitRate:  r.Metrics.LowQualityCommitRate,
+
+		CoAuthoredCommitCount: r.Metrics.CoAuthoredCommitCount,
+		CoAuthorshipRate:      r.Metrics.CoAuthorshipRate,
+
+		SizedCommitCount:  r.Metrics.SizedCommitCount,
+		AvgFilesPerCommit: r.Metrics.AvgFilesPerCommit,
+
+		TestFileCount:     r.Metrics.TestFileCount,
+		SourceFileCount:   r.Metrics.SourceFileCount,
+		TestToSourceRatio: r.Metrics.TestToSourceRatio,
+
 		TotalFiles: r.Metrics.TotalFiles,
 
+		CommunityHealthScore: r.Metrics.CommunityHealthScore,
+		CommunityFiles:       communityFiles,
+
 		TrendsJSON: trendsJSON,
 
-		LargeFileCount:   len(r.LargeFiles),
+		Baseline: buildBaselineData(r.Baseline, s.lang),
+
+		History:                history,
+		HistoryJSON:            historyJSON,
+		HistoryCurrentSnapshot: historyUsesCurrentSnapshot(history),
+
+		Thresholds:       thresholds,
+		CustomThresholds: customThresholds,
+
+		DataSources:           dataSources,
+		IncompleteDataSources: incompleteDataSources,
+
+		LargeFileCount:   largeFileCount,
 		LargeFiles:       largeFiles,
 		OutdatedDepCount: len(r.OutdatedDeps),
+		LicenseFile:      r.LicenseFile,
+		BranchProtection: branchProtection,
+		TodoCount:        r.Metrics.TodoCount,
+		TodoDensity:      r.Metrics.TodoDensity,
+		TodoScannedFiles: r.Metrics.TodoScannedFiles,
+		TodoScannedLines: r.Metrics.TodoScannedLines,
+		TodoFiles:        todoFiles,
 		OutdatedDeps:     outdatedDeps,
 
-		Risks:                    risks,
-		HasRisks:                 len(risks) > 0,
-		ChangeConcentrationRisks: changeConcentrationRisks,
+		DependencyCount:      r.Metrics.DependencyCount,
+		OutdatedDepRate:      r.Metrics.OutdatedDepRate,
+		DependencyEcosystems: dependencyEcosystems,
+		VulnCheckedDeps:      r.Metrics.VulnCheckedDeps,
+		VulnerableDeps:       vulnerableDeps,
+
+		LargeNonSourceCount: max(r.Metrics.LargeNonSourceFileCount, len(r.LargeNonSourceFiles)),
+		LargeNonSource:      toLargeFileData(r.LargeNonSourceFiles),
+
+		Risks:        risks,
+		HasRisks:     len(risks) > 0,
+		TopActions:   topActions,
+		HotFileCount: hotFileCount,
+		HotFiles:     hotFiles,
+
+		SiloFileCount: siloFileCount,
+		SiloFiles:     siloFiles,
 
 		CommitsByDay:    commitsByDay,
 		CommitDayLabels: commitDayLabels,
+		PRSizeBuckets:   buildPRSizeBuckets(r.PRDetails),
 
 		PRDetailsJSON:          prDetailsJSON,
 		ContributorDetailsJSON: contributorDetailsJSON,
 		HourlyCommitsJSON:      hourlyCommitsJSON,
+		CommitHeatmapJSON:      commitHeatmapJSON,
+		WeekdayLabelsJSON:      weekdayLabelsJSON,
 
 		GeneratedAt: r.GeneratedAt.Format("2006-01-02 15:04:05"),
-	}
+	}, nil
 }
 
-// buildCategoryScoreData はカテゴリスコアをテンプレートデータに変換する。
-func (s *Service) buildCategoryScoreData(scores map[domain.Category]domain.CategoryScore) []CategoryScoreData {
-	type catInfo struct {
-		cat  domain.Category
-		icon string
-		name string
+// prSizeBucketBounds はPRサイズ分布の区切り（変更行数）。
+// <50 / 50-199 / 200-499 / 500-999 / 1000+ の5区間になる（ラベルはテンプレート側）。
+var prSizeBucketBounds = []int{50, 200, 500, 1000}
+
+// buildPRSizeBuckets はPRの変更行数を区間ごとに数える。
+// 変更行数を取得できなかったPR（Size が 0）は数えない。
+func buildPRSizeBuckets(details []domain.PRDetail) []int {
+	buckets := make([]int, len(prSizeBucketBounds)+1)
+	for _, d := range details {
+		if d.Size <= 0 {
+			continue
+		}
+		i := 0
+		for i < len(prSizeBucketBounds) && d.Size >= prSizeBucketBounds[i] {
+			i++
+		}
+		buckets[i]++
 	}
+	return buckets
+}
+
+// categoryInfo はカテゴリの表示情報。
+type categoryInfo struct {
+	cat  domain.Category
+	icon string
+	name string
+}
+
+// categoryOrder はレポートでのカテゴリの表示順。
+var categoryOrder = []categoryInfo{
+	{domain.CategoryVelocity, "📈", "開発速度"},
+	{domain.CategoryQuality, "✅", "コード品質"},
+	{domain.CategoryTechDebt, "⚠️", "技術的負債"},
+	{domain.CategoryHealth, "💚", "チーム健全性"},
+}
 
-	order := []catInfo{
-		{domain.CategoryVelocity, "📈", "開発速度"},
-		{domain.CategoryQuality, "✅", "コード品質"},
-		{domain.CategoryTechDebt, "⚠️", "技術的負債"},
-		{domain.CategoryHealth, "💚", "チーム健全性"},
+// buildTopActions はリスクを優先度順に並べ、改善提案の重複を除いた上位 n 件を返す。
+// risks は r.Risks と同じ順に変換したもの。
+//
+// 優先度は重大度の高い順、同じなら総合スコアでの重みが大きいカテゴリ、
+// それも同じなら（重みが均等なら）スコアの低いカテゴリを先にする。
+// 同じ改善提案（変更集中の複数ファイルなど）は最初の1件だけ残す。
+func buildTopActions(r *domain.AnalysisResult, risks []RiskData, n int) []RiskData {
+	// 総合スコアの計算（calculateOverallScore）と同じく、重みが nil なら均等とみなす
+	weight := func(cat domain.Category) float64 {
+		if r.CategoryWeights == nil {
+			return 1
+		}
+		return r.CategoryWeights[cat]
+	}
+	order := make([]int, len(r.Risks))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ra, rb := r.Risks[order[a]], r.Risks[order[b]]
+		if ra.Severity != rb.Severity {
+			return ra.Severity > rb.Severity
+		}
+		ca, cb := ra.Type.Category(), rb.Type.Category()
+		if wa, wb := weight(ca), weight(cb); wa != wb {
+			return wa > wb
+		}
+		return r.CategoryScores[ca].Score.Value < r.CategoryScores[cb].Score.Value
+	})
+
+	var top []RiskData
+	seen := make(map[string]bool)
+	for _, i := range order {
+		if len(top) == n {
+			break
+		}
+		if seen[risks[i].Action] {
+			continue
+		}
+		seen[risks[i].Action] = true
+		top = append(top, risks[i])
 	}
+	return top
+}
 
+// buildCategoryScoreData はカテゴリスコアをテンプレートデータに変換する。
+func (s *Service) buildCategoryScoreData(scores map[domain.Category]domain.CategoryScore) []CategoryScoreData {
 	var result []CategoryScoreData
-	for _, ci := range order {
+	for _, ci := range categoryOrder {
 		cs, ok := scores[ci.cat]
 		if !ok {
 			cs = domain.CategoryScore{
 				Category:  ci.cat,
 				Score:     domain.NewScore(100),
-				Diagnosis: "良好な状態です",
+				Diagnosis: s.lang.T("良好な状態です"),
 			}
 		}
 
@@ -398,7 +1033,7 @@ func (s *Service) buildCategoryScoreData(scores map[domain.Category]domain.Categ
 
 		result = append(result, CategoryScoreData{
 			Icon:       ci.icon,
-			Name:       ci.name,
+			Name:       s.lang.T(ci.name),
 			CategoryID: string(ci.cat),
 			Score:      cs.Score.Value,
 			Grade:      cs.Score.Grade(),
@@ -411,27 +1046,48 @@ func (s *Service) buildCategoryScoreData(scores map[domain.Category]domain.Categ
 	return result
 }
 
-// marshalPRDetails はPR詳細をJSON文字列に変換する。
-func (s *Service) marshalPRDetails(details []domain.PRDetail) template.JS {
+// scriptJSON はテンプレートのスクリプトに埋め込む JSON を順に作り、最初のエラーを覚えておく。
+// NaN・Inf などマーシャルできない値が混ざっていても、壊れた JS を埋め込まずにエラーにするため。
+type scriptJSON struct {
+	err error
+}
+
+// marshal は v を JSON にする。すでにエラーがあるか失敗した場合は空を返す（エラーは err に残る）。
+func (j *scriptJSON) marshal(name string, v any) template.JS {
+	if j.err != nil {
+		return ""
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		j.err = fmt.Errorf("failed to marshal %s: %w", name, err)
+		return ""
+	}
+	return template.JS(b)
+}
+
+// toPRDetailData はPR詳細をJSON用データに変換する。
+func toPRDetailData(details []domain.PRDetail) []PRDetailData {
 	data := make([]PRDetailData, len(details))
 	for i, d := range details {
 		data[i] = PRDetailData{
-			Number:          d.Number,
-			Title:           d.Title,
-			Author:          d.Author,
-			LeadTimeDays:    d.LeadTimeDays,
-			Size:            d.Size,
-			Additions:       d.Additions,
-			Deletions:       d.Deletions,
-			ReviewWaitHours: d.ReviewWaitHours,
+			Number:             d.Number,
+			Title:              d.Title,
+			Author:             d.Author,
+			LeadTimeDays:       d.LeadTimeDays,
+			Size:               d.Size,
+			Additions:          d.Additions,
+			Deletions:          d.Deletions,
+			ReviewWaitHours:    d.ReviewWaitHours,
+			FirstResponseHours: d.FirstResponseHours,
+			Reviewed:           d.Reviewed,
+			SelfMerged:         d.SelfMerged,
 		}
 	}
-	b, _ := json.Marshal(data)
-	return template.JS(b)
+	return data
 }
 
-// marshalContributorDetails はコントリビューター詳細をJSON文字列に変換する。
-func (s *Service) marshalContributorDetails(details []domain.ContributorDetail) template.JS {
+// toContributorDetailData はコントリビューター詳細をJSON用データに変換する。
+func toContributorDetailData(details []domain.ContributorDetail) []ContributorDetailData {
 	data := make([]ContributorDetailData, len(details))
 	for i, d := range details {
 		data[i] = ContributorDetailData{
@@ -440,39 +1096,92 @@ func (s *Service) marshalContributorDetails(details []domain.ContributorDetail)
 			Ratio:   d.Ratio,
 		}
 	}
-	b, _ := json.Marshal(data)
-	return template.JS(b)
+	return data
 }
 
-// marshalHourlyCommits は時間帯別コミット数をJSON文字列に変換する。
-func (s *Service) marshalHourlyCommits(hourly [24]int) template.JS {
-	b, _ := json.Marshal(hourly[:])
-	return template.JS(b)
+// weekdayLabels は出力言語の曜日の短縮名（日曜始まり）を返す。
+func (s *Service) weekdayLabels() [7]string {
+	var labels [7]string
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		labels[d] = s.lang.Weekday(d)
+	}
+	return labels
 }
 
-// marshalTrends はトレンドデータをJSON文字列に変換する。
-func (s *Service) marshalTrends(trends []domain.TrendDelta) template.JS {
-	b, _ := json.Marshal(trends)
-	return template.JS(b)
+// toHistoryPointData はスコアの推移をテンプレート用に変換する（古い順のまま）。
+func toHistoryPointData(history []domain.HistoryPoint) []HistoryPointData {
+	data := make([]HistoryPointData, len(history))
+	for i, h := range history {
+		data[i] = HistoryPointData{
+			From:             h.Period.From.Format("2006-01-02"),
+			To:               h.Period.To.Format("2006-01-02"),
+			Score:            h.OverallScore.Value,
+			Grade:            h.OverallScore.Grade(),
+			InsufficientData: h.InsufficientData,
+			CurrentSnapshot:  h.CurrentSnapshot,
+		}
+	}
+	return data
+}
+
+// historyUsesCurrentSnapshot は分析時点の状態で採点した過去の期間が推移に含まれるかを返す。
+func historyUsesCurrentSnapshot(history []HistoryPointData) bool {
+	for _, h := range history {
+		if h.CurrentSnapshot {
+			return true
+		}
+	}
+	return false
+}
+
+// communityFileAction はコミュニティヘルスファイルが不足しているときの改善提案を返す。
+func communityFileAction(name string) string {
+	actions := map[string]string{
+		"CONTRIBUTING.md":                  "開発環境の準備・ブランチ運用・PRの出し方をまとめた CONTRIBUTING.md を追加してください。",
+		"SECURITY.md":                      "脆弱性の非公開の報告先と対応方針を SECURITY.md に書いてください。",
+		".github/ISSUE_TEMPLATE":           "バグ報告・機能要望の Issue テンプレートを .github/ISSUE_TEMPLATE/ に追加してください。",
+		".github/PULL_REQUEST_TEMPLATE.md": "変更内容・動作確認・関連 Issue を書く欄のある PR テンプレートを追加してください。",
+	}
+	return actions[name]
 }
 
 // riskTypeToAction はリスクタイプに対する改善提案を返す。
 func riskTypeToAction(rt domain.RiskType) string {
 	actions := map[domain.RiskType]string{
-		domain.RiskTypeChangeConcentration:  "このファイルの責務を分割することを検討してください。頻繁な変更はバグの温床になります。",
-		domain.RiskTypeLargeFile:            "ファイルを機能ごとに分割してください。大きなファイルは可読性と保守性を下げます。",
-		domain.RiskTypeOwnership:            "コードレビューやペアプログラミングで知識を共有してください。担当者が離脱するとリスクになります。",
-		domain.RiskTypeOutdatedDeps:         "依存パッケージを更新してください。古いバージョンにはセキュリティ脆弱性がある可能性があります。",
-		domain.RiskTypeLateNight:            "深夜作業が多い原因を調査してください。締め切り圧力やリソース不足の兆候かもしれません。",
-		domain.RiskTypeSlowLeadTime:         "PRを小さく分割し、レビュー担当をローテーションで明確化してください。",
-		domain.RiskTypeSlowReview:           "レビュー時間をカレンダーで確保し、Slackへの通知など見逃さない仕組みを導入してください。",
-		domain.RiskTypeLargePR:              "1つのPRで1つの機能/修正に絞り、リファクタリングと機能追加を分けてください。",
-		domain.RiskTypeLowIssueClose:        "定期的なトリアージミーティングで優先度を整理し、対応しないものは wontfix でクローズしてください。",
-		domain.RiskTypeBugFixHigh:           "テストを充実させてバグを事前に防ぎ、コードレビューの品質を上げてください。",
-		domain.RiskTypeLowDeployFreq:        "CI/CDパイプラインを整備し、小さなリリースを頻繁に行う文化を構築してください。",
-		domain.RiskTypeHighChangeFailure:    "リリース前のテスト自動化とステージング環境での検証を強化してください。",
-		domain.RiskTypeSlowRecovery:         "インシデント対応プロセスを整備し、ロールバック手順を自動化してください。",
-		domain.RiskTypeLowFeatureInvestment: "技術的負債の計画的な返済とともに、機能開発への投資バランスを見直してください。",
+		domain.RiskTypeChangeConcentration:   "このファイルの責務を分割することを検討してください。頻繁な変更はバグの温床になります。",
+		domain.RiskTypeLargeFile:             "ファイルを機能ごとに分割してください。大きなファイルは可読性と保守性を下げます。",
+		domain.RiskTypeOwnership:             "コードレビューやペアプログラミングで知識を共有してください。担当者が離脱するとリスクになります。",
+		domain.RiskTypeFileOwnership:         "1人しか変更していないファイルは別のメンバーにも変更やレビューを担当してもらい、知識を共有してください。",
+		domain.RiskTypeBusFactor:             "CODEOWNERS に副担当を追加し、レビューを通じて担当範囲の知識を共有してください。",
+		domain.RiskTypeMissingLicense:        "リポジトリのルートに LICENSE を追加し、利用条件を明示してください。",
+		domain.RiskTypeHighTodoDensity:       "TODO コメントを棚卸しし、対応するものは Issue に起こして期限を決め、不要なものは削除してください。",
+		domain.RiskTypeMissingCommunityFiles: "CONTRIBUTING.md や SECURITY.md などを整備し、貢献の手順と脆弱性の報告窓口を明示してください。",
+		domain.RiskTypeOutdatedDeps:          "依存パッケージを更新してください。古いバージョンにはセキュリティ脆弱性がある可能性があります。",
+		domain.RiskTypeVulnerableDependency:  "脆弱性の修正済みバージョンに更新してください。更新できない場合は、各アドバイザリで影響範囲と回避策を確認してください。",
+		domain.RiskTypeLateNight:             "深夜作業が多い原因を調査してください。締め切り圧力やリソース不足の兆候かもしれません。",
+		domain.RiskTypeWeekendWork:           "週末作業が続く原因を調査し、リリース日程や障害対応の当番体制を見直してください。",
+		domain.RiskTypeSlowLeadTime:          "PRを小さく分割し、レビュー担当をローテーションで明確化してください。",
+		domain.RiskTypeStalePR:               "長期間動きのないPRを棚卸しし、マージ・クローズ・担当の再割り当てを決めてください。",
+		domain.RiskTypeHighPRAbandonment:     "着手前にIssueで方針を合意し、不要になったPRは早めに閉じて理由を残してください。",
+		domain.RiskTypeSlowReview:            "レビュー時間をカレンダーで確保し、Slackへの通知など見逃さない仕組みを導入してください。",
+		domain.RiskTypeLargePR:               "1つのPRで1つの機能/修正に絞り、リファクタリングと機能追加を分けてください。",
+		domain.RiskTypeDirectPush:            "ブランチ保護ルールでデフォルトブランチへの直接プッシュを禁止し、PRとレビューを必須にしてください。",
+		domain.RiskTypeLowReviewCoverage:     "ブランチ保護ルールで承認レビューを必須にし、セルフマージの運用を見直してください。",
+		domain.RiskTypeSelfMerge:             "ブランチ保護ルールで作成者以外の承認を1件以上必須にし、管理者によるバイパスも制限してください。",
+		domain.RiskTypeNoBranchProtection:    "デフォルトブランチに保護ルール（または Ruleset）を設定し、マージ前の承認レビューとステータスチェックを必須にしてください。",
+		domain.RiskTypeHistoryRewrite:        "デフォルトブランチの保護ルールで force push を禁止し、取り消しは revert コミットで行ってください。",
+		domain.RiskTypeFastRevert:            "Revert された変更の原因を振り返り、同じ問題をレビューや CI のテストで止められるようにしてください。",
+		domain.RiskTypeLargeCommits:          "1つのコミットを1つの論理的な変更に絞り、フォーマットやリネームなどの機械的な一括変更は別のコミットに分けてください。",
+		domain.RiskTypeLowTestCoverage:       "変更の多い箇所からテストを書き足し、新しいコードにはテストを付けるルールを決めてください。実際のカバレッジは CI のカバレッジ計測で確認してください。",
+		domain.RiskTypeLowCommitQuality:      "件名に「何を・なぜ」変えたかを書くルールを決め、commitlint などでメッセージを検査してください。",
+		domain.RiskTypeLowIssueClose:         "定期的なトリアージミーティングで優先度を整理し、対応しないものは wontfix でクローズしてください。",
+		domain.RiskTypeBugFixHigh:            "テストを充実させてバグを事前に防ぎ、コードレビューの品質を上げてください。",
+		domain.RiskTypeLowDeployFreq:         "CI/CDパイプラインを整備し、小さなリリースを頻繁に行う文化を構築してください。",
+		domain.RiskTypeHighChangeFailure:     "リリース前のテスト自動化とステージング環境での検証を強化してください。",
+		domain.RiskTypeSlowRecovery:          "インシデント対応プロセスを整備し、ロールバック手順を自動化してください。",
+		domain.RiskTypeReleaseDebt:           "リリースの単位を小さくして定期的にリリースし、リリース作業を CI で自動化してください。",
+		domain.RiskTypeLowFeatureInvestment:  "技術的負債の計画的な返済とともに、機能開発への投資バランスを見直してください。",
+		domain.RiskTypeInactiveRepo:          "今後使う予定がなければアーカイブし、使い続けるならメンテナンスの担当者を決めてください。",
 	}
 	if action, ok := actions[rt]; ok {
 		return action
@@ -481,7 +1190,7 @@ func riskTypeToAction(rt domain.RiskType) string {
 }
 
 // generateOverallDiagnosis は総合グレードに基づく一行診断を返す。
-func generateOverallDiagnosis(grade string, categories []CategoryScoreData) string {
+func generateOverallDiagnosis(grade string, categories []CategoryScoreData, lang i18n.Lang) string {
 	// 最低スコアのカテゴリを特定
 	worstName := ""
 	worstScore := 101
@@ -494,20 +1203,52 @@ func generateOverallDiagnosis(grade string, categories []CategoryScoreData) stri
 
 	switch grade {
 	case "A":
-		return "全体的に良好な状態です。"
+		return lang.T("全体的に良好な状態です。")
 	case "B":
-		return fmt.Sprintf("概ね良好ですが、%sに改善の余地があります。", worstName)
+		return lang.T("概ね良好ですが、%sに改善の余地があります。", worstName)
 	case "C":
-		return fmt.Sprintf("%sを中心に改善が必要です。", worstName)
+		return lang.T("%sを中心に改善が必要です。", worstName)
 	case "D":
-		return fmt.Sprintf("%sに重大な課題があります。早急な対応を推奨します。", worstName)
+		return lang.T("%sに重大な課題があります。早急な対応を推奨します。", worstName)
 	default:
-		return "診断データがありません。"
+		return lang.T("診断データがありません。")
+	}
+}
+
+// toLargeFileData は巨大ファイルをテンプレート用に変換する（サイズの大きい順のまま）。
+func toLargeFileData(files []domain.LargeFile) []LargeFileData {
+	data := make([]LargeFileData, len(files))
+	for i, lf := range files {
+		severityStr := "medium"
+		if lf.Severity == domain.SeverityHigh {
+			severityStr = "high"
+		}
+		data[i] = LargeFileData{
+			Path:        lf.Path,
+			SizeKB:      lf.SizeKB,
+			SeverityStr: severityStr,
+		}
 	}
+	return data
+}
+
+// formatReleaseDate は最後のリリース日を "2006-01-02" 形式にする。リリースがなければ空文字。
+func formatReleaseDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
 }
 
 // formatDateWithWeekday は日付を "1/25(土)" 形式でフォーマットする。
-func formatDateWithWeekday(t time.Time) string {
-	weekdays := []string{"日", "月", "火", "水", "木", "金", "土"}
-	return fmt.Sprintf("%d/%d(%s)", t.Month(), t.Day(), weekdays[t.Weekday()])
+func formatDateWithWeekday(t time.Time, lang i18n.Lang) string {
+	return fmt.Sprintf("%d/%d(%s)", t.Month(), t.Day(), lang.Weekday(t.Weekday()))
+}
+
+// langCode は <html lang> に使う言語コードを返す（ゼロ値なら既定の言語）。
+func (s *Service) langCode() string {
+	if s.lang == "" {
+		return string(i18n.Default)
+	}
+	return string(s.lang)
 }
@@ -1,10 +1,17 @@
 package report
 
 import (
+	"encoding/json"
+	"html/template"
+	"math"
+	"os"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/shared/i18n"
 )
 
 func newTestResult() *domain.AnalysisResult {
@@ -69,6 +76,10 @@ func newTestResult() *domain.AnalysisResult {
 			IssueCloseRate:      75.0,
 			IssuesCreated:       20,
 			IssuesClosed:        15,
+			ReviewCoverageRate:  85.0,
+			ReviewCoveragePRs:   20,
+			SelfMergeCount:      2,
+			SelfMergeRate:       10.0,
 			FeaturePRCount:      10,
 			BugFixPRCount:       5,
 			OtherPRCount:        3,
@@ -84,13 +95,21 @@ func newTestResult() *domain.AnalysisResult {
 			RevertCommitCount:   2,
 			RevertRate:          1.3,
 			TotalFiles:          500,
+			DependencyCount:     4,
+			OutdatedDepRate:     25,
 		},
 		LargeFiles: []domain.LargeFile{
 			{Path: "bundle.js", SizeKB: 150, Severity: domain.SeverityHigh},
 		},
+		HotFiles: []domain.HotFile{
+			{Path: "src/main.go", Changes: 25, Severity: domain.SeverityHigh},
+		},
 		OutdatedDeps: []domain.OutdatedDep{
 			{Name: "lodash", Version: "3.0.0", Age: "3年", Severity: domain.SeverityHigh},
 		},
+		DependencyEcosystems: []domain.DependencyEcosystem{
+			{Ecosystem: "npm", Count: 4, Outdated: 1},
+		},
 		PRDetails: []domain.PRDetail{
 			{Number: 1, Title: "feat: login", Author: "alice", LeadTimeDays: 2.0, Size: 100},
 		},
@@ -105,10 +124,20 @@ func newTestResult() *domain.AnalysisResult {
 	}
 }
 
+// mustPrepareTemplateData はテンプレートデータを準備する（エラーならテストを止める）。
+func mustPrepareTemplateData(t *testing.T, s *Service, r *domain.AnalysisResult) TemplateData {
+	t.Helper()
+	data, err := s.prepareTemplateData(r)
+	if err != nil {
+		t.Fatalf("prepareTemplateData() error = %v", err)
+	}
+	return data
+}
+
 func TestPrepareTemplateData(t *testing.T) {
 	s := NewService()
 	result := newTestResult()
-	data := s.prepareTemplateData(result)
+	data := mustPrepareTemplateData(t, s, result)
 
 	t.Run("basic fields", func(t *testing.T) {
 		if data.Repository != "facebook/react" {
@@ -154,11 +183,27 @@ func TestPrepareTemplateData(t *testing.T) {
 		if len(data.Risks) != 2 {
 			t.Errorf("Risks len = %d, want 2", len(data.Risks))
 		}
+		if len(data.TopActions) == 0 || data.TopActions[0].Action == "" {
+			t.Errorf("TopActions = %+v, want actions for the risks", data.TopActions)
+		}
+	})
+
+	t.Run("hot files", func(t *testing.T) {
+		if data.HotFileCount != 1 {
+			t.Errorf("HotFileCount = %d, want 1", data.HotFileCount)
+		}
+		if len(data.HotFiles) != 1 || data.HotFiles[0].Path != "src/main.go" || data.HotFiles[0].Changes != 25 {
+			t.Errorf("HotFiles = %+v", data.HotFiles)
+		}
 	})
 
-	t.Run("change concentration risks extracted", func(t *testing.T) {
-		if len(data.ChangeConcentrationRisks) != 1 {
-			t.Errorf("ChangeConcentrationRisks len = %d, want 1", len(data.ChangeConcentrationRisks))
+	t.Run("counts reflect risks when lists are truncated", func(t *testing.T) {
+		r := newTestResult()
+		// 巨大ファイルは30件あるが、一覧は上位1件のみ
+		r.Risks = append(r.Risks, domain.Risk{Type: domain.RiskTypeLargeFile, Severity: domain.SeverityHigh, Value: 30})
+		d := mustPrepareTemplateData(t, NewService(), r)
+		if d.LargeFileCount != 30 || len(d.LargeFiles) != 1 {
+			t.Errorf("LargeFileCount = %d, LargeFiles len = %d, want 30, 1", d.LargeFileCount, len(d.LargeFiles))
 		}
 	})
 
@@ -184,6 +229,13 @@ func TestPrepareTemplateData(t *testing.T) {
 		if data.OutdatedDepCount != 1 {
 			t.Errorf("OutdatedDepCount = %d, want 1", data.OutdatedDepCount)
 		}
+		if data.DependencyCount != 4 || data.OutdatedDepRate != 25 {
+			t.Errorf("DependencyCount = %d, OutdatedDepRate = %v, want 4, 25", data.DependencyCount, data.OutdatedDepRate)
+		}
+		want := []DependencyEcosystemData{{Ecosystem: "npm", Count: 4, Outdated: 1, OutdatedRate: 25}}
+		if !slices.Equal(data.DependencyEcosystems, want) {
+			t.Errorf("DependencyEcosystems = %+v, want %+v", data.DependencyEcosystems, want)
+		}
 	})
 
 	t.Run("generated at", func(t *testing.T) {
@@ -199,17 +251,33 @@ func TestRiskTypeToAction(t *testing.T) {
 		domain.RiskTypeChangeConcentration,
 		domain.RiskTypeLargeFile,
 		domain.RiskTypeOwnership,
+		domain.RiskTypeBusFactor,
+		domain.RiskTypeFileOwnership,
 		domain.RiskTypeOutdatedDeps,
+		domain.RiskTypeVulnerableDependency,
 		domain.RiskTypeLateNight,
+		domain.RiskTypeWeekendWork,
 		domain.RiskTypeSlowLeadTime,
 		domain.RiskTypeSlowReview,
+		domain.RiskTypeStalePR,
+		domain.RiskTypeHighPRAbandonment,
 		domain.RiskTypeLargePR,
+		domain.RiskTypeDirectPush,
+		domain.RiskTypeLowReviewCoverage,
+		domain.RiskTypeSelfMerge,
+		domain.RiskTypeNoBranchProtection,
+		domain.RiskTypeHistoryRewrite,
+		domain.RiskTypeFastRevert,
+		domain.RiskTypeLowTestCoverage,
+		domain.RiskTypeLargeCommits,
 		domain.RiskTypeLowIssueClose,
 		domain.RiskTypeBugFixHigh,
 		domain.RiskTypeLowDeployFreq,
 		domain.RiskTypeHighChangeFailure,
 		domain.RiskTypeSlowRecovery,
+		domain.RiskTypeReleaseDebt,
 		domain.RiskTypeLowFeatureInvestment,
+		domain.RiskTypeInactiveRepo,
 	}
 	for _, rt := range riskTypes {
 		action := riskTypeToAction(rt)
@@ -239,16 +307,18 @@ func TestGenerateOverallDiagnosis(t *testing.T) {
 
 	tests := []struct {
 		grade string
+		lang  i18n.Lang
 		want  string
 	}{
-		{"A", "全体的に良好な状態です。"},
-		{"B", "概ね良好ですが、コード品質に改善の余地があります。"},
-		{"C", "コード品質を中心に改善が必要です。"},
-		{"D", "コード品質に重大な課題があります。早急な対応を推奨します。"},
+		{"A", i18n.Japanese, "全体的に良好な状態です。"},
+		{"B", i18n.Japanese, "概ね良好ですが、コード品質に改善の余地があります。"},
+		{"C", i18n.Japanese, "コード品質を中心に改善が必要です。"},
+		{"D", i18n.Japanese, "コード品質に重大な課題があります。早急な対応を推奨します。"},
+		{"B", i18n.English, "Mostly good, but コード品質 has room for improvement."},
 	}
 	for _, tt := range tests {
-		t.Run(tt.grade, func(t *testing.T) {
-			got := generateOverallDiagnosis(tt.grade, categories)
+		t.Run(tt.grade+"/"+string(tt.lang), func(t *testing.T) {
+			got := generateOverallDiagnosis(tt.grade, categories, tt.lang)
 			if got != tt.want {
 				t.Errorf("got %q, want %q", got, tt.want)
 			}
@@ -259,15 +329,17 @@ func TestGenerateOverallDiagnosis(t *testing.T) {
 func TestFormatDateWithWeekday(t *testing.T) {
 	tests := []struct {
 		date time.Time
+		lang i18n.Lang
 		want string
 	}{
-		{time.Date(2025, 1, 25, 0, 0, 0, 0, time.UTC), "1/25(土)"},
-		{time.Date(2025, 1, 26, 0, 0, 0, 0, time.UTC), "1/26(日)"},
-		{time.Date(2025, 1, 27, 0, 0, 0, 0, time.UTC), "1/27(月)"},
+		{time.Date(2025, 1, 25, 0, 0, 0, 0, time.UTC), i18n.Japanese, "1/25(土)"},
+		{time.Date(2025, 1, 26, 0, 0, 0, 0, time.UTC), i18n.Japanese, "1/26(日)"},
+		{time.Date(2025, 1, 27, 0, 0, 0, 0, time.UTC), i18n.Japanese, "1/27(月)"},
+		{time.Date(2025, 1, 25, 0, 0, 0, 0, time.UTC), i18n.English, "1/25(Sat)"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.want, func(t *testing.T) {
-			got := formatDateWithWeekday(tt.date)
+			got := formatDateWithWeekday(tt.date, tt.lang)
 			if got != tt.want {
 				t.Errorf("got %q, want %q", got, tt.want)
 			}
@@ -275,6 +347,134 @@ func TestFormatDateWithWeekday(t *testing.T) {
 	}
 }
 
+func TestBuildPRSizeBuckets(t *testing.T) {
+	tests := []struct {
+		name  string
+		sizes []int
+		want  []int
+	}{
+		{"empty", nil, []int{0, 0, 0, 0, 0}},
+		{"boundaries", []int{49, 50, 199, 200, 499, 500, 999, 1000}, []int{1, 2, 2, 2, 1}},
+		{"skips unknown size", []int{0, 10, 5000}, []int{1, 0, 0, 0, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var details []domain.PRDetail
+			for _, size := range tt.sizes {
+				details = append(details, domain.PRDetail{Size: size})
+			}
+			got := buildPRSizeBuckets(details)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildTopActions(t *testing.T) {
+	scores := map[domain.Category]domain.CategoryScore{
+		domain.CategoryVelocity: {Score: domain.NewScore(80)},
+		domain.CategoryQuality:  {Score: domain.NewScore(40)},
+		domain.CategoryTechDebt: {Score: domain.NewScore(60)},
+		domain.CategoryHealth:   {Score: domain.NewScore(90)},
+	}
+	risk := func(typ domain.RiskType, sev domain.Severity) domain.Risk {
+		return domain.Risk{Type: typ, Severity: sev}
+	}
+	tests := []struct {
+		name    string
+		risks   []domain.Risk
+		weights map[domain.Category]float64
+		want    []domain.RiskType
+	}{
+		{
+			name:  "no risks",
+			risks: nil,
+			want:  nil,
+		},
+		{
+			name: "severity first",
+			risks: []domain.Risk{
+				risk(domain.RiskTypeLateNight, domain.SeverityLow),
+				risk(domain.RiskTypeLargeFile, domain.SeverityHigh),
+				risk(domain.RiskTypeSlowReview, domain.SeverityMedium),
+			},
+			want: []domain.RiskType{domain.RiskTypeLargeFile, domain.RiskTypeSlowReview, domain.RiskTypeLateNight},
+		},
+		{
+			name: "equal weights: lower category score first",
+			risks: []domain.Risk{
+				risk(domain.RiskTypeBusFactor, domain.SeverityHigh),    // 健全性 90
+				risk(domain.RiskTypeSlowLeadTime, domain.SeverityHigh), // 開発速度 80
+				risk(domain.RiskTypeDirectPush, domain.SeverityHigh),   // 品質 40
+			},
+			want: []domain.RiskType{domain.RiskTypeDirectPush, domain.RiskTypeSlowLeadTime, domain.RiskTypeBusFactor},
+		},
+		{
+			name: "heavier category first",
+			risks: []domain.Risk{
+				risk(domain.RiskTypeDirectPush, domain.SeverityHigh),
+				risk(domain.RiskTypeBusFactor, domain.SeverityHigh),
+			},
+			weights: map[domain.Category]float64{
+				domain.CategoryVelocity: 1, domain.CategoryQuality: 1, domain.CategoryTechDebt: 1, domain.CategoryHealth: 3,
+			},
+			want: []domain.RiskType{domain.RiskTypeBusFactor, domain.RiskTypeDirectPush},
+		},
+		{
+			name: "same action once, limited to 3",
+			risks: []domain.Risk{
+				risk(domain.RiskTypeChangeConcentration, domain.SeverityHigh),
+				risk(domain.RiskTypeChangeConcentration, domain.SeverityHigh),
+				risk(domain.RiskTypeLargeFile, domain.SeverityMedium),
+				risk(domain.RiskTypeSlowReview, domain.SeverityMedium),
+				risk(domain.RiskTypeLateNight, domain.SeverityLow),
+			},
+			want: []domain.RiskType{domain.RiskTypeChangeConcentration, domain.RiskTypeLargeFile, domain.RiskTypeSlowReview},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &domain.AnalysisResult{Risks: tt.risks, CategoryScores: scores, CategoryWeights: tt.weights}
+			risks := make([]RiskData, len(tt.risks))
+			for i, rk := range tt.risks {
+				risks[i] = RiskData{Type: rk.Type.DisplayName(), Action: riskTypeToAction(rk.Type)}
+			}
+			got := buildTopActions(r, risks, topActionsLimit)
+
+			var gotTypes, wantTypes []string
+			for _, rd := range got {
+				gotTypes = append(gotTypes, rd.Type)
+			}
+			for _, typ := range tt.want {
+				wantTypes = append(wantTypes, typ.DisplayName())
+			}
+			if !slices.Equal(gotTypes, wantTypes) {
+				t.Errorf("got %v, want %v", gotTypes, wantTypes)
+			}
+		})
+	}
+}
+
+func TestPrepareTemplateData_CommitHeatmap(t *testing.T) {
+	result := newTestResult()
+	result.WeekdayHourCommits[time.Monday][10] = 3
+	result.WeekdayHourCommits[time.Sunday][23] = 1
+
+	data := mustPrepareTemplateData(t, NewService(WithLang(i18n.English)), result)
+
+	var matrix [7][24]int
+	if err := json.Unmarshal([]byte(data.CommitHeatmapJSON), &matrix); err != nil {
+		t.Fatalf("CommitHeatmapJSON is not valid JSON: %v", err)
+	}
+	if matrix != result.WeekdayHourCommits {
+		t.Errorf("CommitHeatmapJSON = %s, want the weekday x hour matrix", data.CommitHeatmapJSON)
+	}
+	if want := `["Sun","Mon","Tue","Wed","Thu","Fri","Sat"]`; string(data.WeekdayLabelsJSON) != want {
+		t.Errorf("WeekdayLabelsJSON = %s, want %s", data.WeekdayLabelsJSON, want)
+	}
+}
+
 func TestGenerate_createsFile(t *testing.T) {
 	s := NewService()
 	result := newTestResult()
@@ -285,3 +485,491 @@ func TestGenerate_createsFile(t *testing.T) {
 		t.Fatalf("Generate() error = %v", err)
 	}
 }
+
+func TestExpandOutputPath(t *testing.T) {
+	repo := domain.NewRepository("facebook", "react")
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"report.html", "report.html"},
+		{"{repo}.html", "facebook-react.html"},
+		{"reports/{repo}/index.html", "reports/facebook-react/index.html"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got := ExpandOutputPath(tt.path, repo)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	s := NewService()
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{FormatHTML, "<!DOCTYPE html>"},
+		{FormatJSON, `"repository": "facebook/react"`},
+		{FormatMarkdown, "## Lokup: facebook/react"},
+		{FormatPrometheus, `lokup_overall_score{repo="facebook/react"} 76`},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var b strings.Builder
+			if err := s.Render(&b, newTestResult(), tt.format); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if !strings.Contains(b.String(), tt.want) {
+				t.Errorf("output does not contain %q", tt.want)
+			}
+		})
+	}
+}
+
+func TestPrepareTemplateData_ScriptJSON(t *testing.T) {
+	result := newTestResult()
+	result.HourlyCommits[9] = 5
+	result.History = []domain.HistoryPoint{
+		{Period: domain.NewDateRange(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)), OverallScore: domain.NewScore(72)},
+	}
+	data := mustPrepareTemplateData(t, NewService(), result)
+
+	// スクリプトに埋め込む JSON はすべて正しい JSON で、期待するフィールドを含む
+	tests := []struct {
+		name string
+		js   template.JS
+		want []string
+	}{
+		{"PRDetailsJSON", data.PRDetailsJSON, []string{`"number":1`, `"title":"feat: login"`, `"author":"alice"`, `"leadTimeDays":2`, `"firstResponseHours":`}},
+		{"ContributorDetailsJSON", data.ContributorDetailsJSON, []string{`"name":"alice"`, `"commits":80`, `"ratio":53.3`}},
+		{"HourlyCommitsJSON", data.HourlyCommitsJSON, []string{`[0,0,0,0,0,0,0,0,0,5,`}},
+		{"CommitHeatmapJSON", data.CommitHeatmapJSON, []string{`[[0,`}},
+		{"WeekdayLabelsJSON", data.WeekdayLabelsJSON, []string{`"日"`}},
+		{"TrendsJSON", data.TrendsJSON, []string{`"metricName":"コミット数"`, `"currentValue":150`, `"deltaPct":25`, `"direction":"up"`}},
+		{"HistoryJSON", data.HistoryJSON, []string{`"from":"2025-01-01"`, `"score":72`, `"insufficientData":false`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !json.Valid([]byte(tt.js)) {
+				t.Fatalf("%s is not valid JSON: %s", tt.name, tt.js)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(string(tt.js), w) {
+					t.Errorf("%s = %s, want to contain %s", tt.name, tt.js, w)
+				}
+			}
+		})
+	}
+}
+
+func TestRender_ScriptJSONError(t *testing.T) {
+	// NaN は JSON にできないため、壊れた JS を埋め込まずにエラーにする
+	result := newTestResult()
+	result.PRDetails[0].LeadTimeDays = math.NaN()
+
+	var b strings.Builder
+	err := NewService().Render(&b, result, FormatHTML)
+	if err == nil || !strings.Contains(err.Error(), "failed to marshal pr details") {
+		t.Fatalf("Render() error = %v, want marshal error", err)
+	}
+	if b.Len() != 0 {
+		t.Errorf("Render() wrote %d bytes on error, want none", b.Len())
+	}
+
+	// 統合レポートではどのリポジトリで失敗したかを添える
+	other := newTestResult()
+	other.Repository = domain.NewRepository("facebook", "jest")
+	err = NewService().renderAll(&b, []*domain.AnalysisResult{other, result}, FormatHTML)
+	if err == nil || !strings.Contains(err.Error(), "facebook/react: failed to marshal pr details") {
+		t.Errorf("renderAll() error = %v, want marshal error for facebook/react", err)
+	}
+}
+
+func TestRender_InsufficientData(t *testing.T) {
+	s := NewService()
+	result := newTestResult()
+	result.InsufficientData = true
+	tests := []struct {
+		format Format
+		want   string
+		reject string
+	}{
+		{FormatHTML, "分析できるだけの活動がありません", "総合スコア:"},
+		{FormatJSON, `"insufficientData": true`, ""},
+		{FormatMarkdown, "**データ不足**", "| カテゴリ |"},
+		{FormatPrometheus, `lokup_insufficient_data{repo="facebook/react"} 1`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var b strings.Builder
+			if err := s.Render(&b, result, tt.format); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if !strings.Contains(b.String(), tt.want) {
+				t.Errorf("output does not contain %q", tt.want)
+			}
+			if tt.reject != "" && strings.Contains(b.String(), tt.reject) {
+				t.Errorf("output contains %q", tt.reject)
+			}
+		})
+	}
+}
+
+func TestRender_DeployFreqWindowTooShort(t *testing.T) {
+	s := NewService()
+	result := newTestResult()
+	result.Metrics.DeployFreqRating = domain.DeployFreqRatingWindowTooShort
+
+	var b strings.Builder
+	if err := s.Render(&b, result, FormatHTML); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	out := b.String()
+	if !strings.Contains(out, `dora-badge dora-n/a">N/A (window too short)`) {
+		t.Error("short window is not shown as N/A")
+	}
+	if !strings.Contains(out, "分析期間が14日未満") {
+		t.Error("short window note is missing")
+	}
+}
+
+func TestRender_QuickMode(t *testing.T) {
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{FormatHTML, "クイックモード（--quick）では計算していません"},
+		{FormatMarkdown, "クイックモード: PRサイズ・レビュー・依存は計算していません"},
+		{FormatJSON, `"quickMode": true`},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			result := newTestResult()
+			result.QuickMode = true
+
+			var b strings.Builder
+			if err := NewService().Render(&b, result, tt.format); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if !strings.Contains(b.String(), tt.want) {
+				t.Errorf("output does not contain %q", tt.want)
+			}
+		})
+	}
+
+	// 通常のレポートには出さない
+	var b strings.Builder
+	if err := NewService().Render(&b, newTestResult(), FormatHTML); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if strings.Contains(b.String(), "クイックモード") {
+		t.Error("quick mode note is shown without --quick")
+	}
+}
+
+func TestRender_BranchProtection(t *testing.T) {
+	tests := []struct {
+		name       string
+		protection *domain.BranchProtection
+		want       string
+	}{
+		{"unknown", nil, "ブランチ保護の設定を読めませんでした"},
+		{"not protected", &domain.BranchProtection{Branch: "main"}, "デフォルトブランチ <strong>main</strong> に保護ルールがありません"},
+		{"protected", &domain.BranchProtection{Branch: "main", Protected: true, RequiredApprovals: 2}, "承認2件必須"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newTestResult()
+			result.BranchProtection = tt.protection
+
+			var b strings.Builder
+			if err := NewService().Render(&b, result, FormatHTML); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if !strings.Contains(b.String(), tt.want) {
+				t.Errorf("output does not contain %q", tt.want)
+			}
+		})
+	}
+}
+
+func TestRender_PRThroughput(t *testing.T) {
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{FormatHTML, "1週間あたり平均 <strong>3.5件</strong> のPRがマージされています。期間中にマージされたPR: 15件。"},
+		{FormatJSON, `"prThroughputPerWeek": 3.5`},
+		{FormatPrometheus, `lokup_pr_throughput_per_week{repo="facebook/react"} 3.5`},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			result := newTestResult()
+			result.Metrics.MergedPRCount = 15
+			result.Metrics.PRThroughputPerWeek = 3.5
+
+			var b strings.Builder
+			if err := NewService().Render(&b, result, tt.format); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if !strings.Contains(b.String(), tt.want) {
+				t.Errorf("output does not contain %q", tt.want)
+			}
+		})
+	}
+}
+
+func TestRender_ReleaseDebt(t *testing.T) {
+	released := func(r *domain.AnalysisResult) {
+		r.Metrics.LastReleaseAt = time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+		r.Metrics.LastReleaseName = "v1.2.0"
+		r.Metrics.DaysSinceLastRelease = 61
+		r.Metrics.CommitsSinceLastRelease = 120
+	}
+	tests := []struct {
+		name   string
+		setup  func(*domain.AnalysisResult)
+		format Format
+		want   string
+	}{
+		{"html", released, FormatHTML, "最後のリリースは <strong>v1.2.0</strong>（2024-12-01）で、それから <strong>61日</strong>・<strong>120コミット</strong> が未リリースです"},
+		{"markdown", released, FormatMarkdown, "最後のリリース: v1.2.0（2024-12-01）から 61 日・120 コミット未リリース"},
+		{"json", released, FormatJSON, `"lastReleaseAt": "2024-12-01T00:00:00Z"`},
+		{"html no release", func(*domain.AnalysisResult) {}, FormatHTML, "分析期間の終わりまでにリリース（デプロイ）が見つかりませんでした"},
+		{"json no release", func(*domain.AnalysisResult) {}, FormatJSON, `"lastReleaseAt": null`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newTestResult()
+			tt.setup(result)
+
+			var b strings.Builder
+			if err := NewService().Render(&b, result, tt.format); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if !strings.Contains(b.String(), tt.want) {
+				t.Errorf("output does not contain %q", tt.want)
+			}
+		})
+	}
+}
+
+func TestRender_LargeNonSourceFiles(t *testing.T) {
+	withNonSource := func(r *domain.AnalysisResult) {
+		r.Metrics.LargeNonSourceFileCount = 2
+		r.LargeNonSourceFiles = []domain.LargeFile{
+			{Path: "package-lock.json", SizeKB: 900, Severity: domain.SeverityHigh},
+			{Path: "assets/hero.png", SizeKB: 60, Severity: domain.SeverityMedium},
+		}
+	}
+	tests := []struct {
+		name   string
+		setup  func(*domain.AnalysisResult)
+		format Format
+		want   string
+		absent bool
+	}{
+		{"html", withNonSource, FormatHTML, "画像・ロックファイル・minify 済みのファイルなど 2件 はソースコードではないため", false},
+		{"json", withNonSource, FormatJSON, `"largeNonSourceFileCount": 2`, false},
+		{"json list", withNonSource, FormatJSON, `"path": "assets/hero.png"`, false},
+		{"html none", func(*domain.AnalysisResult) {}, FormatHTML, "バイナリ・生成物", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newTestResult()
+			tt.setup(result)
+
+			var b strings.Builder
+			if err := NewService().Render(&b, result, tt.format); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if got := strings.Contains(b.String(), tt.want); got == tt.absent {
+				t.Errorf("output contains %q = %v, want %v", tt.want, got, !tt.absent)
+			}
+		})
+	}
+}
+
+func TestRender_History(t *testing.T) {
+	withHistory := func(r *domain.AnalysisResult) {
+		day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC) }
+		r.History = []domain.HistoryPoint{
+			{Period: domain.NewDateRange(day(1, 1), day(1, 31)), InsufficientData: true},
+			{
+				Period:         domain.NewDateRange(day(1, 31), day(3, 2)),
+				OverallScore:   domain.NewScore(58),
+				CategoryScores: map[domain.Category]domain.Score{domain.CategoryVelocity: domain.NewScore(70)},
+			},
+		}
+	}
+	withSnapshotHistory := func(r *domain.AnalysisResult) {
+		day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC) }
+		r.History = []domain.HistoryPoint{
+			{Period: domain.NewDateRange(day(1, 1), day(1, 31)), OverallScore: domain.NewScore(64), CurrentSnapshot: true},
+			{Period: domain.NewDateRange(day(1, 31), day(3, 2)), OverallScore: domain.NewScore(58)},
+		}
+	}
+	tests := []struct {
+		name   string
+		setup  func(*domain.AnalysisResult)
+		format Format
+		want   string
+		absent bool
+	}{
+		{"html chart", withHistory, FormatHTML, `id="chart-score-history"`, false},
+		{"html row", withHistory, FormatHTML, "<td>2025-01-31 〜 2025-03-02</td>", false},
+		{"html data", withHistory, FormatHTML, `"to":"2025-03-02","score":58,"grade":"C"`, false},
+		{"json", withHistory, FormatJSON, `"velocity": {
+          "value": 70,
+          "grade": "B"
+        }`, false},
+		{"json insufficient", withHistory, FormatJSON, `"insufficientData": true,`, false},
+		{"markdown", withHistory, FormatMarkdown, "| 2025-01-31 〜 2025-03-02 | 58 | C |\n", false},
+		{"markdown insufficient", withHistory, FormatMarkdown, "| 2025-01-01 〜 2025-01-31 | N/A | - |\n", false},
+		{"html snapshot row", withSnapshotHistory, FormatHTML, "<td>2025-01-01 〜 2025-01-31 ※</td>", false},
+		{"html snapshot note", withSnapshotHistory, FormatHTML, `<p class="table-note">※ の期間は`, false},
+		{"html no snapshot note", withHistory, FormatHTML, "※ の期間は", true},
+		{"json snapshot", withSnapshotHistory, FormatJSON, `"currentSnapshot": true,`, false},
+		{"markdown snapshot row", withSnapshotHistory, FormatMarkdown, "| 2025-01-01 〜 2025-01-31 ※ | 64 | B |\n", false},
+		{"markdown snapshot note", withSnapshotHistory, FormatMarkdown, "\n※ の期間は", false},
+		{"markdown no snapshot note", withHistory, FormatMarkdown, "※ の期間は", true},
+		{"html none", func(*domain.AnalysisResult) {}, FormatHTML, "スコアの推移", true},
+		{"json none", func(*domain.AnalysisResult) {}, FormatJSON, `"history"`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newTestResult()
+			tt.setup(result)
+
+			var b strings.Builder
+			if err := NewService().Render(&b, result, tt.format); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if got := strings.Contains(b.String(), tt.want); got == tt.absent {
+				t.Errorf("output contains %q = %v, want %v\n%s", tt.want, got, !tt.absent, b.String())
+			}
+		})
+	}
+}
+
+func TestRender_VulnerableDeps(t *testing.T) {
+	s := NewService()
+
+	var b strings.Builder
+	if err := s.Render(&b, newTestResult(), FormatHTML); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if strings.Contains(b.String(), "脆弱性のある依存") {
+		t.Error("vulnerable dependencies shown without --check-vulns")
+	}
+
+	result := newTestResult()
+	result.Metrics.VulnCheckedDeps = 4
+	result.Metrics.VulnerableDepCount = 1
+	result.VulnerableDeps = []domain.VulnerableDep{
+		{Name: "lodash", Version: "4.17.20", Ecosystem: "npm", IDs: []string{"GHSA-29mw-wpgm-hmr9", "GHSA-35jh-r3h4-6jhm"}},
+	}
+	b.Reset()
+	if err := s.Render(&b, result, FormatHTML); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for _, want := range []string{
+		"脆弱性のある依存",
+		`<a href="https://osv.dev/vulnerability/GHSA-29mw-wpgm-hmr9"`,
+		"GHSA-35jh-r3h4-6jhm",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("output does not contain %q", want)
+		}
+	}
+}
+
+func TestRender_English(t *testing.T) {
+	s := NewService(WithLang(i18n.English))
+	result := newTestResult()
+	result.Risks = []domain.Risk{{Type: domain.RiskTypeLateNight, Severity: domain.SeverityMedium}}
+	tests := []struct {
+		format Format
+		want   []string
+		reject []string
+	}{
+		{FormatHTML, []string{`<html lang="en">`, "Velocity", "Late-night work", "Suggestions"}, []string{"開発速度", "改善提案"}},
+		{FormatMarkdown, []string{"**Overall grade: B**", "| 📈 Velocity |", "**Late-night work**", "### Detected risks (1)"}, []string{"総合グレード", "開発速度"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var b strings.Builder
+			if err := s.Render(&b, result, tt.format); err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(b.String(), want) {
+					t.Errorf("output does not contain %q", want)
+				}
+			}
+			for _, reject := range tt.reject {
+				if strings.Contains(b.String(), reject) {
+					t.Errorf("output should not contain %q", reject)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateAll(t *testing.T) {
+	s := NewService()
+	first := newTestResult()
+	second := newTestResult()
+	second.Repository = domain.NewRepository("golang", "go")
+	results := []*domain.AnalysisResult{first, second}
+
+	t.Run("one file per repository", func(t *testing.T) {
+		dir := t.TempDir()
+		paths, err := s.GenerateAll(results, dir+"/{repo}.html", FormatHTML)
+		if err != nil {
+			t.Fatalf("GenerateAll() error = %v", err)
+		}
+		want := []string{dir + "/facebook-react.html", dir + "/golang-go.html"}
+		if len(paths) != len(want) {
+			t.Fatalf("paths = %v, want %v", paths, want)
+		}
+		for i, p := range paths {
+			if p != want[i] {
+				t.Errorf("paths[%d] = %q, want %q", i, p, want[i])
+			}
+			if _, err := os.Stat(p); err != nil {
+				t.Errorf("file not created: %v", err)
+			}
+		}
+	})
+
+	t.Run("aggregated report", func(t *testing.T) {
+		path := t.TempDir() + "/all.html"
+		paths, err := s.GenerateAll(results, path, FormatHTML)
+		if err != nil {
+			t.Fatalf("GenerateAll() error = %v", err)
+		}
+		if len(paths) != 1 || paths[0] != path {
+			t.Fatalf("paths = %v, want [%s]", paths, path)
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range []string{"facebook/react", "golang/go"} {
+			if !strings.Contains(string(b), name) {
+				t.Errorf("aggregated report does not contain %q", name)
+			}
+		}
+	})
+
+	t.Run("no results", func(t *testing.T) {
+		if _, err := s.GenerateAll(nil, "report.html", FormatHTML); err == nil {
+			t.Error("expected error for empty results")
+		}
+	})
+}
@@ -4,3 +4,9 @@ import _ "embed"
 
 //go:embed template.html
 var htmlTemplate string
+
+//go:embed multi_template.html
+var multiHTMLTemplate string
+
+//go:embed rollup_template.html
+var rollupHTMLTemplate string
@@ -0,0 +1,36 @@
+package report
+
+import (
+	"strconv"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/shared/i18n"
+)
+
+// ThresholdData は判定基準（判定に使った閾値）の1行。値は表示用に整形済み。
+type ThresholdData struct {
+	Name    string
+	Value   string // 判定に使った値（例: "30%", "50KB"）
+	Default string // デフォルト値
+	Custom  bool   // 設定でデフォルトから変更しているか
+}
+
+// buildThresholdData は判定に使った閾値をテンプレートデータに変換し、デフォルトから変更した数を返す。
+// 名前・単位は lang で翻訳する。
+func buildThresholdData(thresholds []domain.Threshold, lang i18n.Lang) ([]ThresholdData, int) {
+	rows := make([]ThresholdData, 0, len(thresholds))
+	custom := 0
+	for _, t := range thresholds {
+		if t.Custom() {
+			custom++
+		}
+		unit := lang.T(t.Unit)
+		rows = append(rows, ThresholdData{
+			Name:    lang.T(t.Name),
+			Value:   strconv.FormatFloat(t.Value, 'f', -1, 64) + unit,
+			Default: strconv.FormatFloat(t.Default, 'f', -1, 64) + unit,
+			Custom:  t.Custom(),
+		})
+	}
+	return rows, custom
+}
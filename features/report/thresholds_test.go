@@ -0,0 +1,70 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/shared/i18n"
+)
+
+func TestBuildThresholdData(t *testing.T) {
+	thresholds := []domain.Threshold{
+		{Name: "深夜コミット率", Unit: "%", Value: 30, Default: 30},
+		{Name: "滞留PRとみなす経過日数", Unit: "日", Value: 14, Default: 30},
+		{Name: "PRリードタイム", Unit: "日", Value: 7.5, Default: 7.5},
+	}
+
+	rows, custom := buildThresholdData(thresholds, i18n.English)
+	if custom != 1 {
+		t.Errorf("custom = %d, want 1", custom)
+	}
+	want := []ThresholdData{
+		{Name: "Late-night commit rate", Value: "30%", Default: "30%"},
+		{Name: "Days open before a PR counts as stale", Value: "14d", Default: "30d", Custom: true},
+		{Name: "PR lead time", Value: "7.5d", Default: "7.5d"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("len(rows) = %d, want %d", len(rows), len(want))
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("rows[%d] = %+v, want %+v", i, rows[i], want[i])
+		}
+	}
+}
+
+func TestRender_Thresholds(t *testing.T) {
+	render := func(thresholds []domain.Threshold) string {
+		t.Helper()
+		result := newTestResult()
+		result.Thresholds = thresholds
+		var b strings.Builder
+		if err := NewService().Render(&b, result, FormatHTML); err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		return b.String()
+	}
+
+	if out := render(nil); strings.Contains(out, "判定基準（使用した閾値）") {
+		t.Error("methodology section rendered without thresholds")
+	}
+
+	out := render([]domain.Threshold{
+		{Name: "巨大ファイル（Medium）", Unit: "KB", Value: 50, Default: 50},
+		{Name: "リスク1件の減点（High）", Unit: "点", Value: 20, Default: 15},
+	})
+	for _, want := range []string{
+		"判定基準（使用した閾値）",
+		"設定で変更: 1件",
+		"<td>巨大ファイル（Medium）</td>",
+		`<td class="num">50KB</td>`,
+		`<tr class="custom">`,
+		`<td class="num">20点</td>`,
+		`<td class="num">15点</td>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q", want)
+		}
+	}
+}
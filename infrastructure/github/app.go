@@ -0,0 +1,129 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// App の JWT の有効期間。GitHub の上限は10分だが、時計のずれで弾かれないよう余裕を持たせる。
+const (
+	appJWTLifetime = 9 * time.Minute
+	// 発行時刻を過去にずらす幅（GitHub の推奨は60秒）
+	appJWTClockSkew = 60 * time.Second
+)
+
+// AppAuth は GitHub App のインストールとして API を呼ぶための認証情報。
+// 組織全体の自動化で、個人のトークンを使わずに分析したい場合に使う。
+type AppAuth struct {
+	AppID          int64           // App の ID
+	InstallationID int64           // 対象の組織（またはユーザー）へのインストールの ID
+	PrivateKey     *rsa.PrivateKey // App の秘密鍵（ParseAppPrivateKey で読む）
+}
+
+// ParseAppPrivateKey は GitHub App の秘密鍵（PEM）を読む。
+// GitHub がダウンロードさせる PKCS#1（RSA PRIVATE KEY）と、変換後の PKCS#8（PRIVATE KEY）に対応する。
+func ParseAppPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T (GitHub Apps use RSA)", key)
+		}
+		return rsaKey, nil
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+}
+
+// appJWT は App 自身として API を呼ぶための JWT（RS256）を作る。
+func (a AppAuth) appJWT(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-appJWTClockSkew).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": strconv.FormatInt(a.AppID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	signingInput := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, a.PrivateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign app JWT: %w", err)
+	}
+	return signingInput + "." + enc.EncodeToString(sig), nil
+}
+
+// installationTokenResponse は POST /app/installations/{id}/access_tokens のレスポンス。
+type installationTokenResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CreateInstallationToken は App の JWT でインストールトークンを発行する。
+// 返すトークンは1時間で失効するため、分析の開始直前に発行する。
+// このリクエストにはクライアントのトークンは使わない（トークンなしの Client から呼べる）。
+func (c *Client) CreateInstallationToken(ctx context.Context, auth AppAuth) (string, time.Time, error) {
+	if auth.PrivateKey == nil {
+		return "", time.Time{}, errors.New("app private key is required")
+	}
+	jwt, err := auth.appJWT(c.now())
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	url := fmt.Sprintf("%s/app/installations/%d/access_tokens", c.baseURL, auth.InstallationID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "lokup")
+	req.Header.Set("Authorization", "Bearer "+jwt)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to create installation token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", time.Time{}, fmt.Errorf("failed to create installation token: HTTP %s", resp.Status)
+	}
+	var body installationTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to decode installation token: %w", err)
+	}
+	if body.Token == "" {
+		return "", time.Time{}, errors.New("failed to create installation token: empty token in response")
+	}
+	return body.Token, body.ExpiresAt, nil
+}
@@ -0,0 +1,169 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testAppKey はテスト用の RSA 鍵を生成する（2048 ビットは遅いため 1024 ビット）。
+func testAppKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// verifyAppJWT は JWT の署名を pub で検証し、クレームを返す。
+func verifyAppJWT(t *testing.T, token string, pub *rsa.PublicKey) map[string]any {
+	t.Helper()
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("JWT has %d parts, want 3", len(parts))
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig); err != nil {
+		t.Fatalf("JWT signature: %v", err)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var claims map[string]any
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatal(err)
+	}
+	return claims
+}
+
+func TestParseAppPrivateKey(t *testing.T) {
+	key := testAppKey(t)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{"PKCS#1", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), false},
+		{"PKCS#8", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}), false},
+		{"not PEM", []byte("not a key"), true},
+		{"unsupported block", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte{1}}), true},
+		{"broken key", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: []byte{1, 2, 3}}), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAppPrivateKey(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAppPrivateKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(key) {
+				t.Error("parsed key does not match")
+			}
+		})
+	}
+}
+
+func TestAppAuth_AppJWT(t *testing.T) {
+	key := testAppKey(t)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	token, err := AppAuth{AppID: 12345, PrivateKey: key}.appJWT(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := verifyAppJWT(t, token, &key.PublicKey)
+	if claims["iss"] != "12345" {
+		t.Errorf("iss = %v, want \"12345\"", claims["iss"])
+	}
+	if iat := int64(claims["iat"].(float64)); iat != now.Add(-appJWTClockSkew).Unix() {
+		t.Errorf("iat = %d, want %d", iat, now.Add(-appJWTClockSkew).Unix())
+	}
+	exp := int64(claims["exp"].(float64))
+	if exp != now.Add(appJWTLifetime).Unix() || exp-now.Unix() > 10*60 {
+		t.Errorf("exp = %d, want %d (GitHub rejects more than 10 minutes)", exp, now.Add(appJWTLifetime).Unix())
+	}
+}
+
+func TestCreateInstallationToken(t *testing.T) {
+	key := testAppKey(t)
+	now := time.Now()
+	expires := now.Add(time.Hour).UTC().Truncate(time.Second)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/app/installations/678/access_tokens" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		jwt, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			t.Errorf("Authorization = %q, want Bearer JWT", r.Header.Get("Authorization"))
+		}
+		if claims := verifyAppJWT(t, jwt, &key.PublicKey); claims["iss"] != "42" {
+			t.Errorf("iss = %v, want \"42\"", claims["iss"])
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]any{"token": "ghs_installation", "expires_at": expires})
+	}))
+	defer srv.Close()
+
+	c := NewClient("ghp_ignored", WithClock(func() time.Time { return now }), WithBaseURL(srv.URL))
+	token, expiresAt, err := c.CreateInstallationToken(context.Background(), AppAuth{AppID: 42, InstallationID: 678, PrivateKey: key})
+	if err != nil {
+		t.Fatalf("CreateInstallationToken() error = %v", err)
+	}
+	if token != "ghs_installation" || !expiresAt.Equal(expires) {
+		t.Errorf("CreateInstallationToken() = %q, %v, want ghs_installation, %v", token, expiresAt, expires)
+	}
+}
+
+func TestCreateInstallationToken_Errors(t *testing.T) {
+	key := testAppKey(t)
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"unauthorized", http.StatusUnauthorized, `{"message":"A JSON web token could not be decoded"}`},
+		{"installation not found", http.StatusNotFound, `{"message":"Not Found"}`},
+		{"empty token", http.StatusCreated, `{}`},
+		{"broken body", http.StatusCreated, `{`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			c := NewClient("", WithBaseURL(srv.URL))
+			if _, _, err := c.CreateInstallationToken(context.Background(), AppAuth{AppID: 1, InstallationID: 2, PrivateKey: key}); err == nil {
+				t.Error("CreateInstallationToken() error = nil, want error")
+			}
+		})
+	}
+
+	if _, _, err := NewClient("").CreateInstallationToken(context.Background(), AppAuth{AppID: 1, InstallationID: 2}); err == nil {
+		t.Error("CreateInstallationToken() without key: want error")
+	}
+}
@@ -0,0 +1,172 @@
+package github
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cacheDirName はユーザーキャッシュディレクトリ配下のサブディレクトリ名。
+const cacheDirName = "lokup"
+
+// DefaultCacheDir はキャッシュの既定の保存先を返す（例: ~/.cache/lokup）。
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve cache directory: %w", err)
+	}
+	return filepath.Join(dir, cacheDirName), nil
+}
+
+// WithCache は GET レスポンスをディスクにキャッシュする。
+// ttl 以内に同じ URL を取得した場合はネットワークに出ない（0 以下なら無効）。
+func WithCache(dir string, ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		if dir != "" && ttl > 0 {
+			c.cache = &responseCache{dir: dir, ttl: ttl}
+		}
+	}
+}
+
+// responseCache は URL をキーにしたレスポンスのディスクキャッシュ。
+//
+// リポジトリと期間は URL のパス・クエリ（since / until）に含まれるため、
+// URL をキーにすれば別リポジトリ・別期間の結果が混ざることはない。
+// トークンによって見える内容が違うため、キーにはトークンのハッシュも含める（Client.cacheKey 参照）。
+// 中身はプライベートリポジトリの内容を含みうるため、ディレクトリは所有者だけが読める権限で作る。
+type responseCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// cacheEntry はキャッシュファイルの中身。
+type cacheEntry struct {
+	Key        string      `json:"key"`
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	StoredAt   time.Time   `json:"storedAt"`
+}
+
+// cacheable はキャッシュしてよいステータスか。
+// 存在しないファイルの確認（依存マニフェスト探索など）が多いため 404 もキャッシュする。
+func cacheable(status int) bool {
+	return status == http.StatusOK || status == http.StatusNotFound
+}
+
+// path はキーに対応するキャッシュファイルのパスを返す。
+func (rc *responseCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(rc.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// get は有効期限内のキャッシュがあればレスポンスとして返す。
+func (rc *responseCache) get(key string, now time.Time) (*http.Response, bool) {
+	b, err := os.ReadFile(rc.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var e cacheEntry
+	if err := json.Unmarshal(b, &e); err != nil || e.Key != key {
+		return nil, false
+	}
+	if now.Sub(e.StoredAt) > rc.ttl {
+		return nil, false
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Header:        e.Header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+	}, true
+}
+
+// put はレスポンスを保存し、読み直せるボディに差し替えたレスポンスを返す。
+// 保存の失敗は writeErr で返す（リクエスト自体は成功しているため呼び出し側で続行する）。
+func (rc *responseCache) put(key string, resp *http.Response, now time.Time) (_ *http.Response, writeErr, err error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	e := cacheEntry{
+		Key:        key,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+		StoredAt:   now,
+	}
+	return resp, rc.write(key, e), nil
+}
+
+// write はキャッシュファイルを書き込む。
+// 途中で中断されても壊れたファイルが残らないよう、一時ファイルから rename する。
+func (rc *responseCache) write(key string, e cacheEntry) error {
+	if err := os.MkdirAll(rc.dir, 0o700); err != nil {
+		return err
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(rc.dir, "*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), rc.path(key))
+}
+
+// cacheKey は url のキャッシュキーを返す。
+//
+// 同じ URL でもトークンによって見える内容（プライベートリポジトリなど）が違うため、
+// トークンがあればそのハッシュを前に付け、別のトークンで保存したキャッシュを返さないようにする。
+// キャッシュファイルにトークンそのものは残さない。
+func (c *Client) cacheKey(url string) string {
+	if c.token == "" {
+		return url
+	}
+	sum := sha256.Sum256([]byte(c.token))
+	return "token:" + hex.EncodeToString(sum[:]) + " " + url
+}
+
+// cachedGet はキャッシュがあればそれを返し、なければ fetch を呼んで結果を保存する。
+// キャッシュが無効な場合は fetch をそのまま呼ぶ。
+func (c *Client) cachedGet(url string, fetch func() (*http.Response, error)) (*http.Response, error) {
+	if c.cache == nil {
+		return fetch()
+	}
+	key := c.cacheKey(url)
+	if resp, ok := c.cache.get(key, c.now()); ok {
+		c.logger.Debug("cache hit", "url", url)
+		return resp, nil
+	}
+
+	resp, err := fetch()
+	if err != nil || !cacheable(resp.StatusCode) {
+		return resp, err
+	}
+	resp, writeErr, err := c.cache.put(key, resp, c.now())
+	if writeErr != nil {
+		c.logger.Debug("failed to write cache", "url", url, "error", writeErr)
+	}
+	return resp, err
+}
@@ -0,0 +1,143 @@
+package github
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// cacheTestURL はキャッシュのテストで取得する URL。
+const cacheTestURL = "https://api.github.com/repos/o/r"
+
+// cacheFetcher は呼ばれた回数を数え、status と body を返す fetch 関数を作る。
+type cacheFetcher struct {
+	status int
+	body   string
+	calls  int
+}
+
+func (f *cacheFetcher) fetch() (*http.Response, error) {
+	f.calls++
+	rec := httptest.NewRecorder()
+	rec.WriteHeader(f.status)
+	io.WriteString(rec, f.body)
+	return rec.Result(), nil
+}
+
+// readCachedBody は cachedGet の結果のボディを読む。
+func readCachedBody(t *testing.T, c *Client, f *cacheFetcher) string {
+	t.Helper()
+	resp, err := c.cachedGet(cacheTestURL, f.fetch)
+	if err != nil {
+		t.Fatalf("cachedGet() error = %v", err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+	return string(b)
+}
+
+func TestCachedGet(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		elapsed   time.Duration // 1回目から2回目の取得までの経過時間
+		wantCalls int           // 2回取得したときに fetch が呼ばれる回数
+	}{
+		{"hit", http.StatusOK, 30 * time.Minute, 1},
+		{"not found is cached", http.StatusNotFound, 30 * time.Minute, 1},
+		{"expired", http.StatusOK, time.Hour + time.Second, 2},
+		{"server error is not cached", http.StatusInternalServerError, time.Minute, 2},
+		{"forbidden is not cached", http.StatusForbidden, time.Minute, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+			c := NewClient("token", WithCache(t.TempDir(), time.Hour), WithClock(func() time.Time { return now }))
+			f := &cacheFetcher{status: tt.status, body: `{"name":"r"}`}
+
+			for range 2 {
+				if got := readCachedBody(t, c, f); got != f.body {
+					t.Errorf("body = %q, want %q", got, f.body)
+				}
+				now = now.Add(tt.elapsed)
+			}
+			if f.calls != tt.wantCalls {
+				t.Errorf("fetch called %d times, want %d", f.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCachedGet_SeparatesTokens(t *testing.T) {
+	dir := t.TempDir()
+	f := &cacheFetcher{status: http.StatusOK, body: `{"private":true}`}
+
+	readCachedBody(t, NewClient("token-a", WithCache(dir, time.Hour)), f)
+	readCachedBody(t, NewClient("token-a", WithCache(dir, time.Hour)), f)
+	if f.calls != 1 {
+		t.Fatalf("fetch called %d times with the same token, want 1", f.calls)
+	}
+
+	for _, token := range []string{"token-b", ""} {
+		readCachedBody(t, NewClient(token, WithCache(dir, time.Hour)), f)
+	}
+	if f.calls != 3 {
+		t.Errorf("fetch called %d times, want other tokens not to reuse the cache", f.calls)
+	}
+
+	// キャッシュファイルにトークンそのものは残さない
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(b), "token-a") || strings.Contains(string(b), "token-b") {
+			t.Errorf("cache file %s contains the token", e.Name())
+		}
+	}
+}
+
+func TestCachedGet_CorruptEntry(t *testing.T) {
+	dir := t.TempDir()
+	c := NewClient("token", WithCache(dir, time.Hour))
+	f := &cacheFetcher{status: http.StatusOK, body: `{"name":"r"}`}
+
+	if err := os.WriteFile(c.cache.path(c.cacheKey(cacheTestURL)), []byte(`{"key":`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := readCachedBody(t, c, f); got != f.body || f.calls != 1 {
+		t.Fatalf("body = %q after %d fetches, want the fetched body", got, f.calls)
+	}
+
+	// 取得し直した結果で上書きされ、次からはキャッシュを返す
+	readCachedBody(t, c, f)
+	if f.calls != 1 {
+		t.Errorf("fetch called %d times, want the corrupt entry to be replaced", f.calls)
+	}
+}
+
+func TestCachedGet_DirPermission(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "lokup")
+	c := NewClient("token", WithCache(dir, time.Hour))
+	readCachedBody(t, c, &cacheFetcher{status: http.StatusOK, body: `{}`})
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatalf("cache dir not created: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o700 {
+		t.Errorf("cache dir permission = %o, want 700", perm)
+	}
+}
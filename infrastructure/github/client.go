@@ -8,34 +8,268 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"maps"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ryuka-games/lokup/domain"
 	"github.com/ryuka-games/lokup/features/analyze"
+	"github.com/ryuka-games/lokup/infrastructure/internal/httpapi"
 )
 
+// デフォルトのリトライ設定
+const (
+	defaultMaxRetries     = 3
+	defaultRetryBaseDelay = 1 * time.Second
+	// レート制限のリセットをこの時間まで待つ（それ以上ならエラーにする）
+	defaultRateLimitMaxWait = 60 * time.Second
+	// 一覧取得で辿るページ数の上限（per_page=100 なので 1000 件）
+	defaultMaxPages = 10
+)
+
+// ErrRateLimited は GitHub API のレート制限に達したことを示す。
+// リセットまで待てない場合に doRequest が返す（errors.Is で判定できる）。
+var ErrRateLimited = errors.New("GitHub API rate limit exceeded")
+
 // Client は GitHub API クライアント。
+//
+// 複数の goroutine から同時に使ってよい。設定は NewClient の時点で固定され、
+// 実行中に書き換わる状態（レート制限の解除時刻）は rateLimit が排他制御する。
+// ディスクキャッシュはファイル単位で rename するため、同じ URL を同時に書いても壊れない。
 type Client struct {
 	baseURL    string
 	token      string
 	httpClient *http.Client
+
+	// 一時的なエラー（502/503/504・タイムアウト）時のリトライ回数
+	maxRetries int
+	// リトライ間隔の初期値（指数バックオフで倍々に伸ばす）
+	retryBaseDelay time.Duration
+	// レート制限のリセットを待つ上限
+	rateLimitMaxWait time.Duration
+	// 一覧取得で辿るページ数の上限（巨大リポジトリでの際限ない取得を防ぐ）
+	maxPages int
+	// 同時に送る HTTP リクエスト数の上限（全エンドポイント共通）
+	limiter httpapi.Limiter
+	// GET レスポンスのディスクキャッシュ（nil なら無効）
+	cache *responseCache
+	// リクエスト・ページ送りなどの経過を出すロガー
+	logger *slog.Logger
+	// 現在時刻を返す関数（依存の経過月数・キャッシュの有効期限・レート制限の待ち時間に使う）
+	clock func() time.Time
+	// 依存の脆弱性を問い合わせる OSV API のベース URL
+	osvBaseURL string
+	// 並行するリクエストの間で共有するレート制限の解除時刻
+	rateLimit rateLimitState
+}
+
+// ClientOption は Client の設定を変更する。
+type ClientOption func(*Client)
+
+// WithMaxRetries は一時的なエラー時のリトライ回数を設定する（0 でリトライしない）。
+func WithMaxRetries(n int) ClientOption {
+	return func(c *Client) {
+		if n >= 0 {
+			c.maxRetries = n
+		}
+	}
+}
+
+// WithRateLimitMaxWait はレート制限のリセットを待つ上限を設定する（0 で待たない）。
+func WithRateLimitMaxWait(d time.Duration) ClientOption {
+	return func(c *Client) {
+		if d >= 0 {
+			c.rateLimitMaxWait = d
+		}
+	}
+}
+
+// WithBaseURL は GitHub API のベース URL を設定する。
+// GitHub Enterprise Server（https://ghe.example.com/api/v3 など）やテスト用のサーバーを指すときに使う。
+func WithBaseURL(u string) ClientOption {
+	return func(c *Client) {
+		if u != "" {
+			c.baseURL = strings.TrimRight(u, "/")
+		}
+	}
+}
+
+// WithMaxPages は一覧取得で辿るページ数の上限を設定する。
+func WithMaxPages(n int) ClientOption {
+	return func(c *Client) {
+		if n > 0 {
+			c.maxPages = n
+		}
+	}
+}
+
+// WithLogger はロガーを設定する。
+// リクエストごとの所要時間やページ送りは Debug レベルで出す。
+func WithLogger(l *slog.Logger) ClientOption {
+	return func(c *Client) {
+		if l != nil {
+			c.logger = l
+		}
+	}
+}
+
+// WithClock は現在時刻の取得元を設定する。
+// テストやレポートの再現のために時刻を固定したい場合に使う。所要時間の計測には使わない。
+func WithClock(now func() time.Time) ClientOption {
+	return func(c *Client) {
+		if now != nil {
+			c.clock = now
+		}
+	}
 }
 
 // NewClient は Client を生成する。
-func NewClient(token string) *Client {
-	return &Client{
-		baseURL:    "https://api.github.com",
-		token:      token,
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+func NewClient(token string, opts ...ClientOption) *Client {
+	c := &Client{
+		baseURL:          "https://api.github.com",
+		token:            token,
+		httpClient:       &http.Client{Timeout: 30 * time.Second},
+		maxRetries:       defaultMaxRetries,
+		retryBaseDelay:   defaultRetryBaseDelay,
+		rateLimitMaxWait: defaultRateLimitMaxWait,
+		maxPages:         defaultMaxPages,
+		limiter:          httpapi.NewLimiter(DefaultConcurrency),
+		logger:           slog.Default(),
+		clock:            time.Now,
+		osvBaseURL:       "https://api.osv.dev",
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// now は現在時刻を返す。
+func (c *Client) now() time.Time {
+	if c.clock != nil {
+		return c.clock()
 	}
+	return time.Now()
 }
 
 // doRequest は HTTP リクエストを実行する。
+//
+// キャッシュが有効なら GET は有効期限内のキャッシュを返し、ネットワークに出ない。
+// レート制限に達した場合、リセットが rateLimitMaxWait 以内なら待って1度だけ再送し、
+// それ以上かかるなら ErrRateLimited を返す。
 func (c *Client) doRequest(ctx context.Context, method, url string) (*http.Response, error) {
+	if method == http.MethodGet {
+		return c.cachedGet(url, func() (*http.Response, error) {
+			return c.doRequestUncached(ctx, method, url)
+		})
+	}
+	return c.doRequestUncached(ctx, method, url)
+}
+
+// doRequestUncached はキャッシュを介さずに HTTP リクエストを実行する（doRequest 参照）。
+func (c *Client) doRequestUncached(ctx context.Context, method, url string) (*http.Response, error) {
+	for waited := false; ; waited = true {
+		// 他のリクエストがレート制限に達していれば、送る前に解除を待つ
+		if err := c.waitRateLimitReset(ctx); err != nil {
+			return nil, err
+		}
+
+		resp, err := c.sendWithRetry(ctx, method, url)
+		if err != nil {
+			return nil, err
+		}
+
+		now := c.now()
+		reset, limited := rateLimitReset(resp, now)
+		if !limited {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		if c.rateLimit.record(reset) {
+			c.logger.Warn("GitHub API rate limited, waiting until reset", "wait", reset.Sub(now).Round(time.Second))
+		}
+		if waited {
+			return nil, rateLimitedError(reset)
+		}
+	}
+}
+
+// waitRateLimitReset は記録済みのレート制限が解除されるまで待つ。
+// 解除まで rateLimitMaxWait より長くかかる場合は待たずに ErrRateLimited を返す。
+func (c *Client) waitRateLimitReset(ctx context.Context) error {
+	reset := c.rateLimit.resetAt()
+	wait := reset.Sub(c.now())
+	if wait <= 0 {
+		return nil
+	}
+	if wait > c.rateLimitMaxWait {
+		return rateLimitedError(reset)
+	}
+	return httpapi.SleepContext(ctx, wait)
+}
+
+// rateLimitedError はリセット時刻を添えた ErrRateLimited を返す。
+func rateLimitedError(reset time.Time) error {
+	return fmt.Errorf("%w (resets at %s)", ErrRateLimited, reset.Local().Format("15:04:05"))
+}
+
+// rateLimitState はレート制限の解除時刻を並行するリクエストの間で共有する。
+//
+// 1件がレート制限に達したら、他のリクエストも解除まで送らずに待つ
+// （同時に走る全リクエストが 403 を受け取ってから待ち始めるのを防ぐ）。
+type rateLimitState struct {
+	mu    sync.Mutex
+	reset time.Time
+}
+
+// record は解除時刻を記録する。既に記録済みの時刻より後なら更新して true を返す。
+func (s *rateLimitState) record(reset time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !reset.After(s.reset) {
+		return false
+	}
+	s.reset = reset
+	return true
+}
+
+// resetAt は記録済みの解除時刻を返す（未記録ならゼロ値）。
+func (s *rateLimitState) resetAt() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reset
+}
+
+// sendWithRetry は GitHub API に HTTP リクエストを送る（リトライは retry 参照）。
+func (c *Client) sendWithRetry(ctx context.Context, method, url string) (*http.Response, error) {
+	return c.retry(ctx, method, func() (*http.Response, error) {
+		return c.send(ctx, method, url)
+	})
+}
+
+// retry は send でリクエストを送る。
+//
+// 冪等なリクエスト（GET / HEAD）は一時的なエラー時に指数バックオフでリトライする（httpapi.Retry 参照）。
+func (c *Client) retry(ctx context.Context, method string, send func() (*http.Response, error)) (*http.Response, error) {
+	retries := 0
+	if isIdempotent(method) {
+		retries = c.maxRetries
+	}
+	return httpapi.Retry(ctx, retries, c.retryBaseDelay, send)
+}
+
+// send は HTTP リクエストを1回だけ送る。
+func (c *Client) send(ctx context.Context, method, url string) (*http.Response, error) {
 	req, err := http.NewRequestWithContext(ctx, method, url, nil)
 	if err != nil {
 		return nil, err
@@ -47,37 +281,91 @@ func (c *Client) doRequest(ctx context.Context, method, url string) (*http.Respo
 		req.Header.Set("Authorization", "Bearer "+c.token)
 	}
 
-	return c.httpClient.Do(req)
+	return c.do(req)
+}
+
+// fetchAllPages は Link ヘッダの rel="next" を辿って全ページを取得する。
+// maxPages に達したら打ち切る（httpapi.FetchAllPages 参照）。
+func fetchAllPages[T any](ctx context.Context, c *Client, url, what string) ([]T, error) {
+	all, _, err := httpapi.FetchAllPages[T](ctx, c.pager(), url, what, c.maxPages)
+	return all, err
+}
+
+// fetchPage は1ページ分を取得し、次ページの URL（なければ空）を返す。
+func fetchPage[T any](ctx context.Context, c *Client, url, what string) ([]T, string, error) {
+	return httpapi.FetchPage[T](ctx, c.pager(), url, what)
+}
+
+// pager は GitHub API の一覧を doRequest（キャッシュ・リトライ・レート制限の待ちを含む）で取得する設定を返す。
+func (c *Client) pager() httpapi.Pager {
+	return httpapi.Pager{
+		Get: func(ctx context.Context, url string) (*http.Response, error) {
+			return c.doRequest(ctx, http.MethodGet, url)
+		},
+		StatusError: func(resp *http.Response) error {
+			return fmt.Errorf("GitHub API error: %s", resp.Status)
+		},
+		Logger: c.logger,
+	}
+}
+
+// rateLimitReset はレスポンスがレート制限によるものなら、制限が解除される時刻を返す。
+//
+// GitHub は以下の2種類の制限を返す:
+//   - プライマリ: 403/429 + X-RateLimit-Remaining: 0（解除時刻は X-RateLimit-Reset の UNIX 秒）
+//   - セカンダリ: 403/429 + Retry-After（待つべき秒数）
+func rateLimitReset(resp *http.Response, now time.Time) (time.Time, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return time.Time{}, false
+	}
+
+	if after := resp.Header.Get("Retry-After"); after != "" {
+		if sec, err := strconv.Atoi(after); err == nil {
+			return now.Add(time.Duration(sec) * time.Second), true
+		}
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return time.Time{}, false
+	}
+	sec, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		// 解除時刻が不明なら待てないので即エラーにする
+		return now.Add(time.Hour), true
+	}
+	return time.Unix(sec, 0), true
+}
+
+// isIdempotent はリトライしても副作用のないメソッドかを返す。
+func isIdempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
 }
 
 // GetCommits は指定期間のコミット履歴を取得する。
-func (c *Client) GetCommits(ctx context.Context, repo domain.Repository, period domain.DateRange) ([]analyze.Commit, error) {
-	url := fmt.Sprintf("%s/repos/%s/%s/commits?since=%s&until=%s&per_page=100",
+func (c *Client) GetCommits(ctx context.Context, repo domain.Repository, period domain.DateRange, path, branch string) ([]analyze.Commit, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/commits?since=%s&until=%s&per_page=100",
 		c.baseURL,
 		repo.Owner,
 		repo.Name,
 		period.From.Format(time.RFC3339),
 		period.To.Format(time.RFC3339),
 	)
-
-	resp, err := c.doRequest(ctx, "GET", url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch commits: %w", err)
+	// path を渡すと、そのパス配下を変更したコミットだけを API 側で絞り込める
+	if path != "" {
+		endpoint += "&path=" + url.QueryEscape(path)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GitHub API error: %s", resp.Status)
+	// sha にブランチ名を渡すと、デフォルトブランチの代わりにそのブランチの履歴を辿る
+	if branch != "" {
+		endpoint += "&sha=" + url.QueryEscape(branch)
 	}
 
-	var apiCommits []apiCommit
-	if err := json.NewDecoder(resp.Body).Decode(&apiCommits); err != nil {
-		return nil, fmt.Errorf("failed to decode commits: %w", err)
+	apiCommits, err := fetchAllPages[apiCommit](ctx, c, endpoint, "commits")
+	if err != nil {
+		return nil, err
 	}
 
-	// TODO: 各コミットの詳細（変更ファイル）を取得する
-	// レート制限を考慮して、必要に応じて実装
-
+	// 変更ファイルは一覧APIに含まれないため、
+	// 必要なコミットのみ GetCommitDetail で個別取得する（fillCommitFiles参照）
 	commits := make([]analyze.Commit, len(apiCommits))
 	for i, ac := range apiCommits {
 		commits[i] = analyze.Commit{
@@ -86,12 +374,102 @@ func (c *Client) GetCommits(ctx context.Context, repo domain.Repository, period
 			Email:   ac.Commit.Author.Email,
 			Date:    ac.Commit.Author.Date,
 			Message: ac.Commit.Message,
+			Parents: ac.parentSHAs(),
 		}
 	}
 
 	return commits, nil
 }
 
+// GetLastCommit は before 以前で最新のコミットを1件だけ取得する。
+// コミットのない（空の）リポジトリでは GitHub が 409 を返すため、nil として扱う。
+func (c *Client) GetLastCommit(ctx context.Context, repo domain.Repository, before time.Time, path, branch string) (*analyze.Commit, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/commits?until=%s&per_page=1",
+		c.baseURL,
+		repo.Owner,
+		repo.Name,
+		before.Format(time.RFC3339),
+	)
+	if path != "" {
+		endpoint += "&path=" + url.QueryEscape(path)
+	}
+	if branch != "" {
+		endpoint += "&sha=" + url.QueryEscape(branch)
+	}
+
+	resp, err := c.doRequest(ctx, "GET", endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch last commit: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusConflict {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API error: %s", resp.Status)
+	}
+
+	var apiCommits []apiCommit
+	if err := json.NewDecoder(resp.Body).Decode(&apiCommits); err != nil {
+		return nil, fmt.Errorf("failed to decode commits: %w", err)
+	}
+	if len(apiCommits) == 0 {
+		return nil, nil
+	}
+	ac := apiCommits[0]
+	return &analyze.Commit{
+		SHA:     ac.SHA,
+		Author:  ac.Commit.Author.Name,
+		Email:   ac.Commit.Author.Email,
+		Date:    ac.Commit.Author.Date,
+		Message: ac.Commit.Message,
+		Parents: ac.parentSHAs(),
+	}, nil
+}
+
+// GetCommitDetail はコミットの詳細（変更ファイル・行数含む）を取得する。
+func (c *Client) GetCommitDetail(ctx context.Context, repo domain.Repository, sha string) (*analyze.Commit, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/commits/%s",
+		c.baseURL,
+		repo.Owner,
+		repo.Name,
+		sha,
+	)
+
+	resp, err := c.doRequest(ctx, "GET", url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch commit detail: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API error: %s", resp.Status)
+	}
+
+	var ac apiCommit
+	if err := json.NewDecoder(resp.Body).Decode(&ac); err != nil {
+		return nil, fmt.Errorf("failed to decode commit detail: %w", err)
+	}
+
+	files := make([]string, len(ac.Files))
+	for i, f := range ac.Files {
+		files[i] = f.Filename
+	}
+
+	return &analyze.Commit{
+		SHA:       ac.SHA,
+		Author:    ac.Commit.Author.Name,
+		Email:     ac.Commit.Author.Email,
+		Date:      ac.Commit.Author.Date,
+		Message:   ac.Commit.Message,
+		Parents:   ac.parentSHAs(),
+		Files:     files,
+		Additions: ac.Stats.Additions,
+		Deletions: ac.Stats.Deletions,
+	}, nil
+}
+
 // GetContributors はコントリビューター一覧を取得する。
 func (c *Client) GetContributors(ctx context.Context, repo domain.Repository) ([]analyze.Contributor, error) {
 	url := fmt.Sprintf("%s/repos/%s/%s/contributors?per_page=100",
@@ -167,19 +545,9 @@ func (c *Client) GetPullRequests(ctx context.Context, repo domain.Repository, st
 		state,
 	)
 
-	resp, err := c.doRequest(ctx, "GET", url)
+	apiPRs, err := fetchAllPages[apiPullRequest](ctx, c, url, "pull requests")
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch pull requests: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GitHub API error: %s", resp.Status)
-	}
-
-	var apiPRs []apiPullRequest
-	if err := json.NewDecoder(resp.Body).Decode(&apiPRs); err != nil {
-		return nil, fmt.Errorf("failed to decode pull requests: %w", err)
+		return nil, err
 	}
 
 	prs := make([]analyze.PullRequest, len(apiPRs))
@@ -189,8 +557,11 @@ func (c *Client) GetPullRequests(ctx context.Context, repo domain.Repository, st
 			Title:      ap.Title,
 			Author:     ap.User.Login,
 			HeadBranch: ap.Head.Ref,
+			HeadSHA:    ap.Head.SHA,
+			MergeSHA:   ap.mergeSHA(),
 			CreatedAt:  ap.CreatedAt,
 			MergedAt:   ap.MergedAt,
+			Draft:      ap.Draft,
 		}
 		// Note: additions/deletions は一覧APIに含まれないため、
 		// 必要なPRのみ getPRDetail で個別取得する（buildPRDetails参照）
@@ -228,21 +599,70 @@ func (c *Client) GetPRDetail(ctx context.Context, repo domain.Repository, prNumb
 		Title:      ap.Title,
 		Author:     ap.User.Login,
 		HeadBranch: ap.Head.Ref,
+		HeadSHA:    ap.Head.SHA,
+		MergeSHA:   ap.mergeSHA(),
 		CreatedAt:  ap.CreatedAt,
 		MergedAt:   ap.MergedAt,
 		Additions:  ap.Additions,
 		Deletions:  ap.Deletions,
+		Draft:      ap.Draft,
 	}, nil
 }
 
+// maxTreeFetches は切り詰められたツリーをディレクトリごとに辿り直すときに取得するツリーの上限。
+const maxTreeFetches = 200
+
 // GetFiles はリポジトリ内のファイル一覧を取得する。
-func (c *Client) GetFiles(ctx context.Context, repo domain.Repository) ([]analyze.File, error) {
-	// デフォルトブランチのツリーを取得（recursive=1で全階層）
-	url := fmt.Sprintf("%s/repos/%s/%s/git/trees/HEAD?recursive=1",
+//
+// ブランチ（空ならデフォルトブランチ）のツリーを recursive で取得し、GitHub が大きなツリーを切り詰めた場合（truncated）は
+// 警告を出してディレクトリごとに辿り直す。辿り直しても全件を取れなければ、取れた分と truncated = true を返す。
+func (c *Client) GetFiles(ctx context.Context, repo domain.Repository, branch string) ([]analyze.File, bool, error) {
+	// "feature/x" のような "/" を含むブランチ名はツリーの URL に書けないため、先頭コミットの SHA に解決する
+	ref := "HEAD"
+	if branch != "" {
+		sha, err := c.getBranchHead(ctx, repo, branch)
+		if err != nil {
+			return nil, false, err
+		}
+		if sha == "" {
+			return nil, false, fmt.Errorf("branch %q not found in %s", branch, repo.FullName())
+		}
+		ref = sha
+	}
+
+	// ツリーを取得（recursive=1で全階層）
+	tree, err := c.getTree(ctx, repo, ref, true)
+	if err != nil {
+		return nil, false, err
+	}
+	files := treeFiles(tree.Tree, "")
+	if !tree.Truncated {
+		return files, false, nil
+	}
+
+	c.logger.Warn("file tree truncated by GitHub, walking directories", "repo", repo.FullName(), "files", len(files))
+	walked, complete := c.walkTree(ctx, repo, tree.SHA)
+	if complete {
+		return walked, false, nil
+	}
+	c.logger.Warn("file list incomplete", "repo", repo.FullName(), "files", max(len(files), len(walked)))
+	if len(walked) > len(files) {
+		return walked, true, nil
+	}
+	return files, true, nil
+}
+
+// getTree はツリーを1つ取得する。sha にはコミットやブランチ（"HEAD" など）も指定できる。
+func (c *Client) getTree(ctx context.Context, repo domain.Repository, sha string, recursive bool) (*apiTree, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/git/trees/%s",
 		c.baseURL,
 		repo.Owner,
 		repo.Name,
+		sha,
 	)
+	if recursive {
+		url += "?recursive=1"
+	}
 
 	resp, err := c.doRequest(ctx, "GET", url)
 	if err != nil {
@@ -258,19 +678,68 @@ func (c *Client) GetFiles(ctx context.Context, repo domain.Repository) ([]analyz
 	if err := json.NewDecoder(resp.Body).Decode(&tree); err != nil {
 		return nil, fmt.Errorf("failed to decode tree: %w", err)
 	}
+	return &tree, nil
+}
+
+// walkTree は切り詰められたツリーをディレクトリごとに取得し直し、ファイル一覧を返す。
+//
+// ルートは直下だけを取得し、子ディレクトリは recursive で取得する。それも切り詰められていれば
+// そのディレクトリの直下だけを取得して、さらに子ディレクトリに降りる。
+// 取得が maxTreeFetches 回に達するか失敗したら、そこまでのファイルと false を返す。
+func (c *Client) walkTree(ctx context.Context, repo domain.Repository, rootSHA string) ([]analyze.File, bool) {
+	type dir struct {
+		sha       string
+		prefix    string // ルートからのパス（末尾に "/"、ルートは空）
+		recursive bool
+	}
+	queue := []dir{{sha: rootSHA}}
+	var files []analyze.File
+	for fetches := 0; len(queue) > 0; fetches++ {
+		if fetches >= maxTreeFetches {
+			return files, false
+		}
+		d := queue[0]
+		queue = queue[1:]
+
+		tree, err := c.getTree(ctx, repo, d.sha, d.recursive)
+		if err != nil {
+			c.logger.Debug("tree walk failed", "repo", repo.FullName(), "dir", d.prefix, "error", err)
+			return files, false
+		}
+		if d.recursive && tree.Truncated {
+			// 部分木も大きすぎる: 直下だけを取り直して1階層ずつ降りる
+			queue = append(queue, dir{sha: d.sha, prefix: d.prefix})
+			continue
+		}
+		if d.recursive {
+			files = append(files, treeFiles(tree.Tree, d.prefix)...)
+			continue
+		}
+		for _, item := range tree.Tree {
+			switch item.Type {
+			case "blob":
+				files = append(files, analyze.File{Path: d.prefix + item.Path, Size: item.Size})
+			case "tree":
+				queue = append(queue, dir{sha: item.SHA, prefix: d.prefix + item.Path + "/", recursive: true})
+			}
+		}
+	}
+	return files, true
+}
 
-	// blob（ファイル）のみを抽出
+// treeFiles はツリーの項目から blob（ファイル）だけを抽出し、パスの先頭に prefix を付ける。
+// ディレクトリ（tree）とサブモジュール（commit）は含めない。
+func treeFiles(items []apiTreeItem, prefix string) []analyze.File {
 	var files []analyze.File
-	for _, item := range tree.Tree {
+	for _, item := range items {
 		if item.Type == "blob" {
 			files = append(files, analyze.File{
-				Path: item.Path,
+				Path: prefix + item.Path,
 				Size: item.Size,
 			})
 		}
 	}
-
-	return files, nil
+	return files
 }
 
 // GetDependencies は各種依存ファイルから依存情報を取得する。
@@ -280,31 +749,66 @@ func (c *Client) GetDependencies(ctx context.Context, repo domain.Repository) ([
 	// npm (package.json)
 	npmDeps, err := c.getNpmDependencies(ctx, repo)
 	if err != nil {
-		log.Printf("[debug] npm dependencies not found: %v", err)
+		c.logger.Debug("dependencies not found", "ecosystem", "npm", "error", err)
 	}
 	allDependencies = append(allDependencies, npmDeps...)
 
 	// Go (go.mod)
 	goDeps, err := c.getGoDependencies(ctx, repo)
 	if err != nil {
-		log.Printf("[debug] go dependencies not found: %v", err)
+		c.logger.Debug("dependencies not found", "ecosystem", "go", "error", err)
 	}
 	allDependencies = append(allDependencies, goDeps...)
 
 	// Python (requirements.txt)
 	pyDeps, err := c.getPythonDependencies(ctx, repo)
 	if err != nil {
-		log.Printf("[debug] python dependencies not found: %v", err)
+		c.logger.Debug("dependencies not found", "ecosystem", "python", "error", err)
 	}
 	allDependencies = append(allDependencies, pyDeps...)
 
 	// .NET (*.csproj)
 	dotnetDeps, err := c.getDotNetDependencies(ctx, repo)
 	if err != nil {
-		log.Printf("[debug] dotnet dependencies not found: %v", err)
+		c.logger.Debug("dependencies not found", "ecosystem", "dotnet", "error", err)
 	}
 	allDependencies = append(allDependencies, dotnetDeps...)
 
+	// Rust (Cargo.toml)
+	cargoDeps, err := c.getCargoDependencies(ctx, repo)
+	if err != nil {
+		c.logger.Debug("dependencies not found", "ecosystem", "cargo", "error", err)
+	}
+	allDependencies = append(allDependencies, cargoDeps...)
+
+	// Ruby (Gemfile.lock / Gemfile)
+	rubyDeps, err := c.getRubyDependencies(ctx, repo)
+	if err != nil {
+		c.logger.Debug("dependencies not found", "ecosystem", "ruby", "error", err)
+	}
+	allDependencies = append(allDependencies, rubyDeps...)
+
+	// Java (pom.xml)
+	mavenDeps, err := c.getMavenDependencies(ctx, repo)
+	if err != nil {
+		c.logger.Debug("dependencies not found", "ecosystem", "maven", "error", err)
+	}
+	allDependencies = append(allDependencies, mavenDeps...)
+
+	// Java / Kotlin (build.gradle / libs.versions.toml)
+	gradleDeps, err := c.getGradleDependencies(ctx, repo)
+	if err != nil {
+		c.logger.Debug("dependencies not found", "ecosystem", "gradle", "error", err)
+	}
+	allDependencies = append(allDependencies, gradleDeps...)
+
+	// PHP (composer.json)
+	composerDeps, err := c.getComposerDependencies(ctx, repo)
+	if err != nil {
+		c.logger.Debug("dependencies not found", "ecosystem", "composer", "error", err)
+	}
+	allDependencies = append(allDependencies, composerDeps...)
+
 	return allDependencies, nil
 }
 
@@ -321,19 +825,9 @@ func (c *Client) GetIssues(ctx context.Context, repo domain.Repository, state st
 		url += "&since=" + since.Format(time.RFC3339)
 	}
 
-	resp, err := c.doRequest(ctx, "GET", url)
+	apiIssues, err := fetchAllPages[apiIssue](ctx, c, url, "issues")
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch issues: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GitHub API error: %s", resp.Status)
-	}
-
-	var apiIssues []apiIssue
-	if err := json.NewDecoder(resp.Body).Decode(&apiIssues); err != nil {
-		return nil, fmt.Errorf("failed to decode issues: %w", err)
+		return nil, err
 	}
 
 	// PRを除外（GitHub APIではPRもIssueとして返される）
@@ -359,6 +853,45 @@ func (c *Client) GetIssues(ctx context.Context, repo domain.Repository, state st
 	return issues, nil
 }
 
+// GetIssueEvents は Issue のイベント（ラベル付け・アサインなど）を取得する。
+// 復旧作業の開始（最初のトリアージ）を求めるのが目的のため、古い順の先頭ページ（100件）だけを取得する。
+func (c *Client) GetIssueEvents(ctx context.Context, repo domain.Repository, issueNumber int) ([]analyze.IssueEvent, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/events?per_page=100",
+		c.baseURL,
+		repo.Owner,
+		repo.Name,
+		issueNumber,
+	)
+
+	resp, err := c.doRequest(ctx, "GET", url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issue events: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API error: %s", resp.Status)
+	}
+
+	var apiEvents []apiIssueEvent
+	if err := json.NewDecoder(resp.Body).Decode(&apiEvents); err != nil {
+		return nil, fmt.Errorf("failed to decode issue events: %w", err)
+	}
+
+	events := make([]analyze.IssueEvent, len(apiEvents))
+	for i, ae := range apiEvents {
+		events[i] = analyze.IssueEvent{
+			Event:     ae.Event,
+			CreatedAt: ae.CreatedAt,
+		}
+		if ae.Label != nil {
+			events[i].Label = ae.Label.Name
+		}
+	}
+
+	return events, nil
+}
+
 // GetPRReviews はPRのレビュー一覧を取得する。
 func (c *Client) GetPRReviews(ctx context.Context, repo domain.Repository, prNumber int) ([]analyze.Review, error) {
 	url := fmt.Sprintf("%s/repos/%s/%s/pulls/%d/reviews?per_page=100",
@@ -396,6 +929,44 @@ func (c *Client) GetPRReviews(ctx context.Context, repo domain.Repository, prNum
 	return reviews, nil
 }
 
+// GetPRComments はPRの会話欄のコメント（Issue コメント API）を取得する。
+// 最初の反応を求めるのが目的のため、古い順の先頭ページ（100件）だけを取得する。
+func (c *Client) GetPRComments(ctx context.Context, repo domain.Repository, prNumber int) ([]analyze.Comment, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments?per_page=100",
+		c.baseURL,
+		repo.Owner,
+		repo.Name,
+		prNumber,
+	)
+
+	resp, err := c.doRequest(ctx, "GET", url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch comments: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API error: %s", resp.Status)
+	}
+
+	var apiComments []apiComment
+	if err := json.NewDecoder(resp.Body).Decode(&apiComments); err != nil {
+		return nil, fmt.Errorf("failed to decode comments: %w", err)
+	}
+
+	comments := make([]analyze.Comment, len(apiComments))
+	for i, ac := range apiComments {
+		comments[i] = analyze.Comment{
+			ID:        ac.ID,
+			Author:    ac.User.Login,
+			Bot:       ac.User.Type == "Bot",
+			CreatedAt: ac.CreatedAt,
+		}
+	}
+
+	return comments, nil
+}
+
 // GetReleases はリリース一覧を取得する。
 func (c *Client) GetReleases(ctx context.Context, repo domain.Repository) ([]analyze.Release, error) {
 	url := fmt.Sprintf("%s/repos/%s/%s/releases?per_page=100",
@@ -432,6 +1003,349 @@ func (c *Client) GetReleases(ctx context.Context, repo domain.Repository) ([]ana
 	return releases, nil
 }
 
+// GetTags はタグ一覧を、タグが指すコミットの日時付きで取得する。
+//
+// タグ一覧APIには日時が含まれないため、タグごとにコミット詳細を取得する。
+// APIコール節約のため、対象は一覧の先頭1ページ分（最大100件、新しいタグから並ぶ）に限る。
+func (c *Client) GetTags(ctx context.Context, repo domain.Repository) ([]analyze.Tag, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/tags?per_page=100",
+		c.baseURL,
+		repo.Owner,
+		repo.Name,
+	)
+
+	apiTags, _, err := fetchPage[apiTag](ctx, c, endpoint, "tags")
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]analyze.Tag, 0, len(apiTags))
+	for _, at := range apiTags {
+		commit, err := c.GetCommitDetail(ctx, repo, at.Commit.SHA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve tag %s: %w", at.Name, err)
+		}
+		tags = append(tags, analyze.Tag{
+			Name: at.Name,
+			SHA:  at.Commit.SHA,
+			Date: commit.Date,
+		})
+	}
+
+	return tags, nil
+}
+
+// GetWorkflowRuns は指定期間に成功したワークフロー実行のうち、
+// ワークフロー名かワークフローファイル名（deploy.yml など）が workflow に一致するものを取得する。
+// ワークフロー名は大文字小文字を区別しない。
+func (c *Client) GetWorkflowRuns(ctx context.Context, repo domain.Repository, workflow string, period domain.DateRange) ([]analyze.WorkflowRun, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/actions/runs?status=success&created=%s&per_page=100",
+		c.baseURL,
+		repo.Owner,
+		repo.Name,
+		url.QueryEscape(period.From.Format(time.RFC3339)+".."+period.To.Format(time.RFC3339)),
+	)
+
+	// 実行一覧APIは配列ではなくオブジェクトで返るため、fetchAllPages は使えない
+	var runs []analyze.WorkflowRun
+	for page := 1; endpoint != ""; page++ {
+		if page > c.maxPages {
+			c.logger.Warn("list truncated", "what", "workflow runs", "pages", c.maxPages, "items", len(runs))
+			analyze.MarkTruncated(ctx)
+			break
+		}
+
+		apiRuns, next, err := c.fetchWorkflowRunsPage(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+		for _, ar := range apiRuns {
+			if !matchWorkflow(ar, workflow) {
+				continue
+			}
+			runs = append(runs, analyze.WorkflowRun{
+				ID:        ar.ID,
+				Name:      ar.Name,
+				Path:      ar.Path,
+				CreatedAt: ar.CreatedAt,
+			})
+		}
+		endpoint = next
+	}
+
+	return runs, nil
+}
+
+// fetchWorkflowRunsPage はワークフロー実行一覧の1ページ分を取得し、次ページの URL（なければ空）を返す。
+func (c *Client) fetchWorkflowRunsPage(ctx context.Context, endpoint string) ([]apiWorkflowRun, string, error) {
+	resp, err := c.doRequest(ctx, "GET", endpoint)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch workflow runs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("GitHub API error: %s", resp.Status)
+	}
+
+	var body apiWorkflowRuns
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, "", fmt.Errorf("failed to decode workflow runs: %w", err)
+	}
+
+	return body.WorkflowRuns, httpapi.NextPageURL(resp.Header.Get("Link")), nil
+}
+
+// branchNotProtectedMessage は保護ルールのないブランチで protection API が 404 とともに返すメッセージ。
+// 権限不足でも 404 になるため、このメッセージのときだけ「保護なし」と判断する。
+const branchNotProtectedMessage = "Branch not protected"
+
+// GetBranchProtection はデフォルトブランチの保護設定を取得する。
+//
+// protection API は管理者権限（またはそれに相当するスコープ）がないと 404 / 403 を返す。
+// その場合は保護の有無を判断できないため、エラーにせず nil を返す。
+// Rulesets による保護はこの API に現れないため、保護なしと判定されることがある。
+func (c *Client) GetBranchProtection(ctx context.Context, repo domain.Repository) (*domain.BranchProtection, error) {
+	branch, err := c.getDefaultBranch(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/branches/%s/protection",
+		c.baseURL,
+		repo.Owner,
+		repo.Name,
+		url.PathEscape(branch),
+	)
+
+	resp, err := c.doRequest(ctx, "GET", endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch branch protection: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		var body apiError
+		if err := json.NewDecoder(resp.Body).Decode(&body); err == nil && body.Message == branchNotProtectedMessage {
+			return &domain.BranchProtection{Branch: branch}, nil
+		}
+		return nil, nil
+	case http.StatusForbidden:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("GitHub API error: %s", resp.Status)
+	}
+
+	var ap apiBranchProtection
+	if err := json.NewDecoder(resp.Body).Decode(&ap); err != nil {
+		return nil, fmt.Errorf("failed to decode branch protection: %w", err)
+	}
+
+	bp := &domain.BranchProtection{
+		Branch:               branch,
+		Protected:            true,
+		RequiredStatusChecks: ap.RequiredStatusChecks != nil,
+	}
+	if ap.RequiredPullRequestReviews != nil {
+		bp.RequiredApprovals = ap.RequiredPullRequestReviews.RequiredApprovingReviewCount
+	}
+	return bp, nil
+}
+
+// errActivityUnavailable はアクティビティ API を使えないことを表す。
+var errActivityUnavailable = errors.New("repository activity API is unavailable")
+
+// GetForcePushes は指定期間にデフォルトブランチへ行われた force push を取得する。
+//
+// コミット一覧の API には force push が現れないため、アクティビティ API
+// （/repos/{owner}/{repo}/activity）を使う。この API がない GitHub Enterprise Server や、
+// 権限不足で 403 / 404 / 422 が返る場合はエラーにせず nil を返す。
+// アクティビティは新しい順に並ぶため、期間の開始より前に達したらページングを打ち切る。
+func (c *Client) GetForcePushes(ctx context.Context, repo domain.Repository, period domain.DateRange) ([]analyze.ForcePush, error) {
+	branch, err := c.getDefaultBranch(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/activity?activity_type=force_push&ref=%s&per_page=100",
+		c.baseURL,
+		repo.Owner,
+		repo.Name,
+		url.QueryEscape("refs/heads/"+branch),
+	)
+
+	var pushes []analyze.ForcePush
+	for page := 1; endpoint != ""; page++ {
+		if page > c.maxPages {
+			c.logger.Warn("list truncated", "what", "force pushes", "pages", c.maxPages, "items", len(pushes))
+			analyze.MarkTruncated(ctx)
+			break
+		}
+
+		activities, next, err := c.fetchActivityPage(ctx, endpoint)
+		if errors.Is(err, errActivityUnavailable) {
+			c.logger.Debug("skipping force push detection", "reason", err)
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		reachedStart := false
+		for _, a := range activities {
+			if a.Timestamp.Before(period.From) {
+				reachedStart = true
+				continue
+			}
+			if a.Timestamp.After(period.To) {
+				continue
+			}
+			pushes = append(pushes, analyze.ForcePush{
+				Branch:    branch,
+				Actor:     a.Actor.Login,
+				Timestamp: a.Timestamp,
+			})
+		}
+		if reachedStart {
+			break
+		}
+		endpoint = next
+	}
+
+	return pushes, nil
+}
+
+// fetchActivityPage はアクティビティ一覧の1ページ分を取得し、次ページの URL（なければ空）を返す。
+// API を使えない場合は errActivityUnavailable を返す。
+func (c *Client) fetchActivityPage(ctx context.Context, endpoint string) ([]apiActivity, string, error) {
+	resp, err := c.doRequest(ctx, "GET", endpoint)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch activity: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusForbidden, http.StatusNotFound, http.StatusUnprocessableEntity:
+		return nil, "", fmt.Errorf("%w: %s", errActivityUnavailable, resp.Status)
+	default:
+		return nil, "", fmt.Errorf("GitHub API error: %s", resp.Status)
+	}
+
+	var activities []apiActivity
+	if err := json.NewDecoder(resp.Body).Decode(&activities); err != nil {
+		return nil, "", fmt.Errorf("failed to decode activity: %w", err)
+	}
+
+	return activities, httpapi.NextPageURL(resp.Header.Get("Link")), nil
+}
+
+// RepositoryAccess はリポジトリへのアクセスを確認した結果。
+type RepositoryAccess struct {
+	StatusCode    int    // HTTP ステータスコード（200 なら到達できた）
+	Status        string // "404 Not Found" などのステータス行
+	DefaultBranch string // デフォルトブランチ名（到達できた場合のみ）
+	Private       bool   // プライベートリポジトリか（到達できた場合のみ）
+}
+
+// Reachable はリポジトリを読み取れたかを返す。
+func (a *RepositoryAccess) Reachable() bool {
+	return a.StatusCode == http.StatusOK
+}
+
+// GetRepository はリポジトリ情報を1回のリクエストで取得し、アクセスできるかを確認する。
+// 分析前にトークンとリポジトリへの到達性を確かめる用途のため、キャッシュは使わない。
+// 404 や 401 などの応答はエラーにせず、ステータスを結果に入れて返す（通信・レート制限のエラーのみ返す）。
+func (c *Client) GetRepository(ctx context.Context, repo domain.Repository) (*RepositoryAccess, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s", c.baseURL, repo.Owner, repo.Name)
+
+	resp, err := c.doRequestUncached(ctx, "GET", endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch repository: %w", err)
+	}
+	defer resp.Body.Close()
+
+	access := &RepositoryAccess{StatusCode: resp.StatusCode, Status: resp.Status}
+	if resp.StatusCode != http.StatusOK {
+		return access, nil
+	}
+
+	var ar apiRepository
+	if err := json.NewDecoder(resp.Body).Decode(&ar); err != nil {
+		return nil, fmt.Errorf("failed to decode repository: %w", err)
+	}
+	access.DefaultBranch = ar.DefaultBranch
+	access.Private = ar.Private
+	return access, nil
+}
+
+// BranchExists はブランチが存在するかを返す。
+func (c *Client) BranchExists(ctx context.Context, repo domain.Repository, branch string) (bool, error) {
+	sha, err := c.getBranchHead(ctx, repo, branch)
+	if err != nil {
+		return false, err
+	}
+	return sha != "", nil
+}
+
+// getBranchHead はブランチの先頭コミットの SHA を返す。ブランチがなければ空文字を返す（エラーではない）。
+func (c *Client) getBranchHead(ctx context.Context, repo domain.Repository, branch string) (string, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/branches/%s", c.baseURL, repo.Owner, repo.Name, branch)
+
+	resp, err := c.doRequest(ctx, "GET", endpoint)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch branch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GitHub API error: %s", resp.Status)
+	}
+
+	var ab apiBranch
+	if err := json.NewDecoder(resp.Body).Decode(&ab); err != nil {
+		return "", fmt.Errorf("failed to decode branch: %w", err)
+	}
+	return ab.Commit.SHA, nil
+}
+
+// getDefaultBranch はリポジトリのデフォルトブランチ名を取得する。
+func (c *Client) getDefaultBranch(ctx context.Context, repo domain.Repository) (string, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s", c.baseURL, repo.Owner, repo.Name)
+
+	resp, err := c.doRequest(ctx, "GET", endpoint)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch repository: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GitHub API error: %s", resp.Status)
+	}
+
+	var ar apiRepository
+	if err := json.NewDecoder(resp.Body).Decode(&ar); err != nil {
+		return "", fmt.Errorf("failed to decode repository: %w", err)
+	}
+	if ar.DefaultBranch == "" {
+		return "", fmt.Errorf("repository %s has no default branch", repo.FullName())
+	}
+	return ar.DefaultBranch, nil
+}
+
+// matchWorkflow はワークフロー実行が workflow（ワークフロー名かファイル名）に一致するかを返す。
+func matchWorkflow(run apiWorkflowRun, workflow string) bool {
+	if strings.EqualFold(run.Name, workflow) {
+		return true
+	}
+	file := run.Path[strings.LastIndex(run.Path, "/")+1:]
+	return file == workflow
+}
+
 // getNpmDependencies はpackage.jsonから依存を取得する。
 func (c *Client) getNpmDependencies(ctx context.Context, repo domain.Repository) ([]analyze.Dependency, error) {
 	content, err := c.GetFileContent(ctx, repo, "package.json")
@@ -439,196 +1353,838 @@ func (c *Client) getNpmDependencies(ctx context.Context, repo domain.Repository)
 		return nil, err
 	}
 
-	var pkg packageJSON
-	if err := json.Unmarshal(content, &pkg); err != nil {
-		return nil, err
+	var pkg packageJSON
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return nil, err
+	}
+
+	allDeps := make(map[string]string)
+	for name, version := range pkg.Dependencies {
+		allDeps[name] = version
+	}
+	for name, version := range pkg.DevDependencies {
+		allDeps[name] = version
+	}
+
+	var lookups []releaseLookup
+	for _, name := range slices.Sorted(maps.Keys(allDeps)) {
+		constraint := allDeps[name]
+		lookups = append(lookups, releaseLookup{
+			dep: analyze.Dependency{Name: name, Version: constraint, PackageType: "npm"},
+			// バージョンは範囲を満たす公開済みの最新版に置き換える
+			fetch: func(ctx context.Context, dep *analyze.Dependency) (time.Time, error) {
+				version, releasedAt, err := c.getNpmRelease(ctx, name, constraint)
+				dep.Version = version
+				return releasedAt, err
+			},
+		})
+	}
+
+	return c.resolveReleaseDates(ctx, lookups), nil
+}
+
+// getGoDependencies はgo.modから依存を取得する。
+func (c *Client) getGoDependencies(ctx context.Context, repo domain.Repository) ([]analyze.Dependency, error) {
+	content, err := c.GetFileContent(ctx, repo, "go.mod")
+	if err != nil {
+		return nil, err
+	}
+
+	var lookups []releaseLookup
+
+	lines := strings.Split(string(content), "\n")
+	inRequire := false
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+
+		if strings.HasPrefix(line, "require (") {
+			inRequire = true
+			continue
+		}
+		if line == ")" {
+			inRequire = false
+			continue
+		}
+
+		// require行をパース
+		var moduleLine string
+		if inRequire {
+			moduleLine = line
+		} else if strings.HasPrefix(line, "require ") {
+			moduleLine = strings.TrimPrefix(line, "require ")
+		} else {
+			continue
+		}
+
+		parts := strings.Fields(moduleLine)
+		if len(parts) < 2 {
+			continue
+		}
+
+		modulePath, rawVersion := parts[0], parts[1]
+		lookups = append(lookups, releaseLookup{
+			dep: analyze.Dependency{Name: modulePath, Version: strings.TrimPrefix(rawVersion, "v"), PackageType: "go"},
+			fetch: func(ctx context.Context, _ *analyze.Dependency) (time.Time, error) {
+				return c.getGoReleaseDate(ctx, modulePath, rawVersion)
+			},
+		})
+	}
+
+	return c.resolveReleaseDates(ctx, lookups), nil
+}
+
+// getPythonDependencies はrequirements.txtから依存を取得する。
+func (c *Client) getPythonDependencies(ctx context.Context, repo domain.Repository) ([]analyze.Dependency, error) {
+	content, err := c.GetFileContent(ctx, repo, "requirements.txt")
+	if err != nil {
+		return nil, err
+	}
+
+	var lookups []releaseLookup
+
+	lines := strings.Split(string(content), "\n")
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// package==version 形式をパース
+		var name, version string
+		if strings.Contains(line, "==") {
+			parts := strings.Split(line, "==")
+			name = parts[0]
+			version = parts[1]
+		} else if strings.Contains(line, ">=") {
+			// 下限だけの指定はインストールされるバージョンが決まらないため、PyPI の最新版に解決する。
+			// 上限などを併記した指定（">=1.0,<2.0"）は最新版が範囲外になりうるため扱わない
+			parts := strings.Split(line, ">=")
+			if strings.Contains(parts[1], ",") {
+				continue
+			}
+			name = parts[0]
+		} else {
+			continue
+		}
+
+		lookups = append(lookups, releaseLookup{
+			dep: analyze.Dependency{Name: name, Version: version, PackageType: "python"},
+			fetch: func(ctx context.Context, dep *analyze.Dependency) (time.Time, error) {
+				resolved, releasedAt, err := c.getPyPIRelease(ctx, name, version)
+				dep.Version = resolved
+				return releasedAt, err
+			},
+		})
+	}
+
+	return c.resolveReleaseDates(ctx, lookups), nil
+}
+
+// getDotNetDependencies は.csprojから依存を取得する。
+func (c *Client) getDotNetDependencies(ctx context.Context, repo domain.Repository) ([]analyze.Dependency, error) {
+	// ファイル一覧から.csprojを探す
+	files, _, err := c.GetFiles(ctx, repo, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var lookups []releaseLookup
+
+	for _, f := range files {
+		if !strings.HasSuffix(f.Path, ".csproj") {
+			continue
+		}
+
+		content, err := c.GetFileContent(ctx, repo, f.Path)
+		if err != nil {
+			continue
+		}
+
+		// 簡易的なXMLパース（PackageReferenceを抽出）
+		lines := strings.Split(string(content), "\n")
+		for _, line := range lines {
+			line = strings.TrimSpace(line)
+			if !strings.Contains(line, "PackageReference") {
+				continue
+			}
+
+			// Include="..." と Version="..." を抽出
+			name := extractAttribute(line, "Include")
+			version := extractAttribute(line, "Version")
+			if name == "" || version == "" {
+				continue
+			}
+
+			lookups = append(lookups, releaseLookup{
+				dep: analyze.Dependency{Name: name, Version: version, PackageType: "nuget"},
+				fetch: func(ctx context.Context, _ *analyze.Dependency) (time.Time, error) {
+					return c.getNuGetReleaseDate(ctx, name, version)
+				},
+			})
+		}
+	}
+
+	return c.resolveReleaseDates(ctx, lookups), nil
+}
+
+// getCargoDependencies はCargo.tomlから依存を取得する。
+func (c *Client) getCargoDependencies(ctx context.Context, repo domain.Repository) ([]analyze.Dependency, error) {
+	content, err := c.GetFileContent(ctx, repo, "Cargo.toml")
+	if err != nil {
+		return nil, err
+	}
+
+	var lookups []releaseLookup
+
+	for _, dep := range parseCargoToml(string(content)) {
+		version := normalizeCargoVersion(dep.version)
+		if version == "" {
+			continue
+		}
+
+		lookups = append(lookups, releaseLookup{
+			dep: analyze.Dependency{Name: dep.name, Version: version, PackageType: "cargo"},
+			fetch: func(ctx context.Context, _ *analyze.Dependency) (time.Time, error) {
+				return c.getCratesIOReleaseDate(ctx, dep.name, version)
+			},
+		})
+	}
+
+	return c.resolveReleaseDates(ctx, lookups), nil
+}
+
+// cargoDependency はCargo.tomlの依存1件。
+type cargoDependency struct {
+	name    string // crates.io 上のクレート名
+	version string // バージョン要求（例: "1.2", "^0.4"）
+}
+
+// parseCargoToml はCargo.tomlの [dependencies] と [dev-dependencies] を抽出する。
+//
+// 完全なTOMLパーサではなく、Cargo.tomlで使われる以下の形式のみ対応する:
+//   - foo = "1.2"
+//   - foo = { version = "1.2", features = [...] }
+//   - [dependencies.foo] テーブル内の version = "1.2"
+//
+// path / git 指定でバージョンのない依存は crates.io で引けないため除外する。
+func parseCargoToml(content string) []cargoDependency {
+	var deps []cargoDependency
+
+	inDeps := false // [dependencies] / [dev-dependencies] の中
+	tableName := "" // [dependencies.foo] の中なら "foo"
+	tableVersion := ""
+	tablePackage := ""
+
+	flushTable := func() {
+		if tableName != "" && tableVersion != "" {
+			name := tableName
+			if tablePackage != "" {
+				name = tablePackage
+			}
+			deps = append(deps, cargoDependency{name: name, version: tableVersion})
+		}
+		tableName, tableVersion, tablePackage = "", "", ""
+	}
+
+	lines := strings.Split(content, "\n")
+	for _, line := range lines {
+		line = strings.TrimSpace(stripTomlComment(line))
+		if line == "" {
+			continue
+		}
+
+		// セクションヘッダ
+		if strings.HasPrefix(line, "[") {
+			flushTable()
+			section := strings.Trim(line, "[] ")
+			inDeps = false
+			switch {
+			case section == "dependencies" || section == "dev-dependencies":
+				inDeps = true
+			case strings.HasPrefix(section, "dependencies."):
+				tableName = strings.TrimPrefix(section, "dependencies.")
+			case strings.HasPrefix(section, "dev-dependencies."):
+				tableName = strings.TrimPrefix(section, "dev-dependencies.")
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		// [dependencies.foo] テーブル内
+		if tableName != "" {
+			switch key {
+			case "version":
+				tableVersion = unquoteToml(value)
+			case "package":
+				tablePackage = unquoteToml(value)
+			}
+			continue
+		}
+
+		if !inDeps {
+			continue
+		}
+
+		name := strings.Trim(key, `"`)
+		var version string
+		if strings.HasPrefix(value, "{") {
+			// インラインテーブル: foo = { version = "1.2", package = "bar" }
+			fields := parseTomlInlineTable(value)
+			version = fields["version"]
+			if pkg := fields["package"]; pkg != "" {
+				name = pkg
+			}
+		} else {
+			version = unquoteToml(value)
+		}
+
+		if version == "" {
+			continue
+		}
+		deps = append(deps, cargoDependency{name: name, version: version})
+	}
+	flushTable()
+
+	return deps
+}
+
+// parseTomlInlineTable は { key = "value", ... } 形式の文字列値だけを取り出す。
+// 配列やネストしたテーブルの値は無視する。
+func parseTomlInlineTable(value string) map[string]string {
+	fields := make(map[string]string)
+	body := strings.TrimSuffix(strings.TrimPrefix(value, "{"), "}")
+	for _, part := range strings.Split(body, ",") {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		v = strings.TrimSpace(v)
+		if !strings.HasPrefix(v, `"`) {
+			continue
+		}
+		fields[strings.TrimSpace(k)] = unquoteToml(v)
+	}
+	return fields
+}
+
+// stripTomlComment は行末の # コメントを除去する（文字列中の # は残す）。
+func stripTomlComment(line string) string {
+	inString := false
+	for i, r := range line {
+		switch r {
+		case '"':
+			inString = !inString
+		case '#':
+			if !inString {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
+
+// unquoteToml は "..." で囲まれた文字列値を取り出す。
+func unquoteToml(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return ""
+	}
+	return value[1 : len(value)-1]
+}
+
+// normalizeCargoVersion はバージョン要求を crates.io で引ける具体的なバージョンに変換する。
+// 演算子（^ ~ = >=）を除き、"1.2" のような省略形は "1.2.0" に補完する。
+// 範囲指定（"1.2, <2"）やワイルドカード（"*"）は解決できないため空文字を返す。
+func normalizeCargoVersion(req string) string {
+	req = strings.TrimSpace(req)
+	if strings.Contains(req, ",") || strings.Contains(req, "*") {
+		return ""
+	}
+	req = strings.TrimLeft(req, "^~=>< ")
+	if req == "" {
+		return ""
+	}
+	return padVersion(req)
+}
+
+// padVersion は "1" / "1.2" のような省略形のバージョンを "1.0.0" / "1.2.0" に補完する。
+// プレリリースやビルドメタデータ（"-beta" "+build"）はそのまま残す。
+func padVersion(version string) string {
+	core, suffix := version, ""
+	if i := strings.IndexAny(version, "-+"); i != -1 {
+		core, suffix = version[:i], version[i:]
+	}
+	switch strings.Count(core, ".") {
+	case 0:
+		core += ".0.0"
+	case 1:
+		core += ".0"
+	}
+	return core + suffix
+}
+
+// getComposerDependencies はcomposer.jsonから依存を取得する。
+// require と require-dev をまとめ、プラットフォーム要件（php, ext-* など）は除く。
+func (c *Client) getComposerDependencies(ctx context.Context, repo domain.Repository) ([]analyze.Dependency, error) {
+	content, err := c.GetFileContent(ctx, repo, "composer.json")
+	if err != nil {
+		return nil, err
+	}
+
+	var composer composerJSON
+	if err := json.Unmarshal(content, &composer); err != nil {
+		return nil, err
+	}
+
+	allDeps := make(map[string]string)
+	for name, constraint := range composer.Require {
+		allDeps[name] = constraint
+	}
+	for name, constraint := range composer.RequireDev {
+		allDeps[name] = constraint
+	}
+
+	var lookups []releaseLookup
+
+	for _, name := range slices.Sorted(maps.Keys(allDeps)) {
+		if isComposerPlatformPackage(name) {
+			continue
+		}
+		version := normalizeComposerVersion(allDeps[name])
+		if version == "" {
+			continue
+		}
+
+		lookups = append(lookups, releaseLookup{
+			dep: analyze.Dependency{Name: name, Version: version, PackageType: "composer"},
+			fetch: func(ctx context.Context, _ *analyze.Dependency) (time.Time, error) {
+				return c.getPackagistReleaseDate(ctx, name, version)
+			},
+		})
+	}
+
+	return c.resolveReleaseDates(ctx, lookups), nil
+}
+
+// isComposerPlatformPackage はプラットフォーム要件（php, ext-json, lib-curl, composer-plugin-api 等）かを返す。
+// Packagist のパッケージ名は必ず "vendor/package" の形のため、"/" を含まないものは Packagist にない。
+func isComposerPlatformPackage(name string) bool {
+	return !strings.Contains(name, "/")
+}
+
+// normalizeComposerVersion はバージョン制約を Packagist で引ける具体的なバージョンに変換する。
+// OR 条件（"^7.0 || ^8.0"）は先頭、範囲（">=1.2 <2.0"）は下限を使い、
+// 演算子（^ ~ = >= v）と安定性フラグ（@dev）を除いて "1.2" のような省略形は "1.2.0" に補完する。
+// ワイルドカード（"*", "1.*"）やブランチ指定（"dev-main"）は解決できないため空文字を返す。
+func normalizeComposerVersion(constraint string) string {
+	first, _, _ := strings.Cut(constraint, "|")
+	fields := strings.FieldsFunc(first, func(r rune) bool { return r == ' ' || r == ',' })
+	if len(fields) == 0 {
+		return ""
+	}
+	version, _, _ := strings.Cut(fields[0], "@")
+	version = strings.TrimLeft(version, "^~=><v")
+	if version == "" || strings.HasPrefix(version, "dev-") || strings.HasSuffix(version, "-dev") ||
+		strings.Contains(version, "*") || strings.HasSuffix(version, ".x") {
+		return ""
+	}
+	return padVersion(version)
+}
+
+// getRubyDependencies はGemfile.lock（なければGemfile）から依存を取得する。
+// Gemfile.lock は確定バージョンが記録されているため優先する。
+func (c *Client) getRubyDependencies(ctx context.Context, repo domain.Repository) ([]analyze.Dependency, error) {
+	var gems []rubyGem
+	if content, err := c.GetFileContent(ctx, repo, "Gemfile.lock"); err == nil {
+		gems = parseGemfileLock(string(content))
+	} else {
+		content, gemfileErr := c.GetFileContent(ctx, repo, "Gemfile")
+		if gemfileErr != nil {
+			return nil, err
+		}
+		gems = parseGemfile(string(content))
 	}
 
-	allDeps := make(map[string]string)
-	for name, version := range pkg.Dependencies {
-		allDeps[name] = version
-	}
-	for name, version := range pkg.DevDependencies {
-		allDeps[name] = version
+	lookups := make([]releaseLookup, len(gems))
+	for i, gem := range gems {
+		lookups[i] = releaseLookup{
+			dep: analyze.Dependency{Name: gem.name, Version: gem.version, PackageType: "rubygems"},
+			fetch: func(ctx context.Context, _ *analyze.Dependency) (time.Time, error) {
+				return c.getRubyGemsReleaseDate(ctx, gem.name, gem.version)
+			},
+		}
 	}
 
-	var dependencies []analyze.Dependency
+	return c.resolveReleaseDates(ctx, lookups), nil
+}
 
-	for name, version := range allDeps {
-		cleanVersion := strings.TrimLeft(version, "^~>=<")
-		releasedAt, err := c.getNpmReleaseDate(ctx, name, cleanVersion)
-		if err != nil {
+// rubyGem はGemfile / Gemfile.lockの依存1件。
+type rubyGem struct {
+	name    string
+	version string
+}
+
+// parseGemfileLock はGemfile.lockのGEMセクションのspecsから依存を抽出する。
+//
+// specs には推移的な依存も含まれるため、DEPENDENCIES セクションがあれば
+// そこに列挙された直接依存だけに絞る（他のエコシステムと粒度を揃えるため）。
+//
+//	GEM
+//	  remote: https://rubygems.org/
+//	  specs:
+//	    rails (7.0.4)        ← 4スペース: gem 本体
+//	      actionpack (= 7.0.4) ← 6スペース: その gem の依存（無視）
+//	DEPENDENCIES
+//	  rails (~> 7.0)
+func parseGemfileLock(content string) []rubyGem {
+	var specs []rubyGem
+	direct := make(map[string]bool)
+
+	section := ""
+	inSpecs := false
+	lines := strings.Split(content, "\n")
+	for _, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
 			continue
 		}
-		dependencies = append(dependencies, analyze.Dependency{
-			Name:        name,
-			Version:     cleanVersion,
-			ReleasedAt:  releasedAt,
-			AgeMonths:   ageMonths(releasedAt),
-			PackageType: "npm",
-		})
-	}
 
-	return dependencies, nil
-}
+		// インデントなしの行はセクションヘッダ
+		if !strings.HasPrefix(line, " ") {
+			section = strings.TrimSpace(line)
+			inSpecs = false
+			continue
+		}
 
-// getGoDependencies はgo.modから依存を取得する。
-func (c *Client) getGoDependencies(ctx context.Context, repo domain.Repository) ([]analyze.Dependency, error) {
-	content, err := c.GetFileContent(ctx, repo, "go.mod")
-	if err != nil {
-		return nil, err
+		switch section {
+		case "GEM":
+			if strings.TrimSpace(line) == "specs:" {
+				inSpecs = true
+				continue
+			}
+			if !inSpecs || !strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "     ") {
+				continue
+			}
+			name, version, ok := strings.Cut(strings.TrimSpace(line), " ")
+			if !ok {
+				continue
+			}
+			version = strings.Trim(version, "()")
+			// プラットフォーム付き（1.14.2-x86_64-linux）はバージョン部分だけ使う
+			version, _, _ = strings.Cut(version, "-")
+			specs = append(specs, rubyGem{name: name, version: version})
+
+		case "DEPENDENCIES":
+			if strings.HasPrefix(line, "   ") {
+				continue
+			}
+			name, _, _ := strings.Cut(strings.TrimSpace(line), " ")
+			direct[strings.TrimSuffix(name, "!")] = true
+		}
 	}
 
-	var dependencies []analyze.Dependency
+	if len(direct) == 0 {
+		return specs
+	}
 
-	lines := strings.Split(string(content), "\n")
-	inRequire := false
+	seen := make(map[string]bool)
+	var gems []rubyGem
+	for _, g := range specs {
+		// プラットフォーム違いで同じ gem が複数回出ることがある
+		if !direct[g.name] || seen[g.name] {
+			continue
+		}
+		seen[g.name] = true
+		gems = append(gems, g)
+	}
+	return gems
+}
+
+// parseGemfile はGemfileの gem 'name', 'version' 行から依存を抽出する。
+// バージョン指定のない gem はリリース日を引けないため除外する。
+func parseGemfile(content string) []rubyGem {
+	var gems []rubyGem
 
+	lines := strings.Split(content, "\n")
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "gem ") {
+			continue
+		}
 
-		if strings.HasPrefix(line, "require (") {
-			inRequire = true
+		args := strings.Split(strings.TrimPrefix(line, "gem "), ",")
+		if len(args) < 2 {
 			continue
 		}
-		if line == ")" {
-			inRequire = false
+		name := strings.Trim(strings.TrimSpace(args[0]), `'"`)
+		version := strings.Trim(strings.TrimSpace(args[1]), `'"`)
+		// オプション引数（require: false など）はバージョンではない
+		if strings.Contains(version, ":") {
+			continue
+		}
+		version = strings.TrimSpace(strings.TrimLeft(version, "~>=< "))
+		if name == "" || version == "" {
 			continue
 		}
 
-		// require行をパース
-		var moduleLine string
-		if inRequire {
-			moduleLine = line
-		} else if strings.HasPrefix(line, "require ") {
-			moduleLine = strings.TrimPrefix(line, "require ")
-		} else {
+		gems = append(gems, rubyGem{name: name, version: version})
+	}
+
+	return gems
+}
+
+// getMavenDependencies はpom.xmlから依存を取得する。
+func (c *Client) getMavenDependencies(ctx context.Context, repo domain.Repository) ([]analyze.Dependency, error) {
+	content, err := c.GetFileContent(ctx, repo, "pom.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	deps, err := parsePOM(content)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.resolveMavenDependencies(ctx, deps, "maven"), nil
+}
+
+// resolveMavenDependencies はMaven Centralでリリース日を引き、packageType の依存として返す。
+// Maven と Gradle で共通。
+func (c *Client) resolveMavenDependencies(ctx context.Context, deps []mavenDependency, packageType string) []analyze.Dependency {
+	var lookups []releaseLookup
+	for _, dep := range deps {
+		// ${spring.version}（pom.xml）や $kotlinVersion（build.gradle）のような
+		// プロパティ参照は未解決のため対象外
+		if strings.Contains(dep.Version, "$") {
+			c.logger.Debug("skipping dependency with property version",
+				"ecosystem", packageType, "dependency", dep.name(), "version", dep.Version)
 			continue
 		}
 
-		parts := strings.Fields(moduleLine)
-		if len(parts) < 2 {
+		lookups = append(lookups, releaseLookup{
+			dep: analyze.Dependency{Name: dep.name(), Version: dep.Version, PackageType: packageType},
+			fetch: func(ctx context.Context, _ *analyze.Dependency) (time.Time, error) {
+				return c.getMavenReleaseDate(ctx, dep.GroupID, dep.ArtifactID, dep.Version)
+			},
+		})
+	}
+	return c.resolveReleaseDates(ctx, lookups)
+}
+
+// mavenPOM はpom.xmlのうち依存の抽出に必要な部分。
+type mavenPOM struct {
+	Dependencies         []mavenDependency `xml:"dependencies>dependency"`
+	DependencyManagement struct {
+		Dependencies []mavenDependency `xml:"dependencies>dependency"`
+	} `xml:"dependencyManagement"`
+}
+
+// mavenDependency はMaven座標（groupId:artifactId:version）で表される依存1件。
+// pom.xmlの <dependency> のほか、Gradleの依存宣言もこの形に揃える。
+type mavenDependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+}
+
+// name は "groupId:artifactId" 形式の名前を返す。
+func (d mavenDependency) name() string {
+	return d.GroupID + ":" + d.ArtifactID
+}
+
+// parsePOM はpom.xmlの <dependencies> と <dependencyManagement> から依存を抽出する。
+//
+// バージョン指定のない依存（親POMや dependencyManagement で管理）は引けないため除外し、
+// 同じ groupId:artifactId:version は1件にまとめる。
+func parsePOM(content []byte) ([]mavenDependency, error) {
+	var pom mavenPOM
+	if err := xml.Unmarshal(content, &pom); err != nil {
+		return nil, fmt.Errorf("failed to parse pom.xml: %w", err)
+	}
+
+	all := append(pom.DependencyManagement.Dependencies, pom.Dependencies...)
+	return uniqueMavenDependencies(all), nil
+}
+
+// uniqueMavenDependencies は座標の前後の空白を除き、座標が欠けたものと重複を除く。
+func uniqueMavenDependencies(deps []mavenDependency) []mavenDependency {
+	var unique []mavenDependency
+	seen := make(map[mavenDependency]bool)
+	for _, d := range deps {
+		d.GroupID = strings.TrimSpace(d.GroupID)
+		d.ArtifactID = strings.TrimSpace(d.ArtifactID)
+		d.Version = strings.TrimSpace(d.Version)
+		if d.GroupID == "" || d.ArtifactID == "" || d.Version == "" || seen[d] {
 			continue
 		}
+		seen[d] = true
+		unique = append(unique, d)
+	}
+	return unique
+}
 
-		modulePath := parts[0]
-		version := strings.TrimPrefix(parts[1], "v")
+// getGradleDependencies はbuild.gradle（.kts）とバージョンカタログ
+// gradle/libs.versions.toml から依存を取得する。
+func (c *Client) getGradleDependencies(ctx context.Context, repo domain.Repository) ([]analyze.Dependency, error) {
+	var deps []mavenDependency
+	found := false
+	var lastErr error
 
-		releasedAt, err := c.getGoReleaseDate(ctx, modulePath, parts[1])
+	for _, path := range []string{"build.gradle", "build.gradle.kts"} {
+		content, err := c.GetFileContent(ctx, repo, path)
 		if err != nil {
+			lastErr = err
 			continue
 		}
+		found = true
+		deps = append(deps, parseBuildGradle(string(content))...)
+	}
 
-		dependencies = append(dependencies, analyze.Dependency{
-			Name:        modulePath,
-			Version:     version,
-			ReleasedAt:  releasedAt,
-			AgeMonths:   ageMonths(releasedAt),
-			PackageType: "go",
-		})
+	if content, err := c.GetFileContent(ctx, repo, "gradle/libs.versions.toml"); err == nil {
+		found = true
+		deps = append(deps, parseVersionCatalog(string(content))...)
 	}
 
-	return dependencies, nil
+	if !found {
+		return nil, lastErr
+	}
+	return c.resolveMavenDependencies(ctx, uniqueMavenDependencies(deps), "gradle"), nil
 }
 
-// getPythonDependencies はrequirements.txtから依存を取得する。
-func (c *Client) getPythonDependencies(ctx context.Context, repo domain.Repository) ([]analyze.Dependency, error) {
-	content, err := c.GetFileContent(ctx, repo, "requirements.txt")
-	if err != nil {
-		return nil, err
-	}
+// gradleConfigurations は依存の抽出対象とする Gradle の configuration 名。
+var gradleConfigurations = map[string]bool{
+	"implementation":            true,
+	"api":                       true,
+	"compileOnly":               true,
+	"runtimeOnly":               true,
+	"testImplementation":        true,
+	"testCompileOnly":           true,
+	"testRuntimeOnly":           true,
+	"annotationProcessor":       true,
+	"kapt":                      true,
+	"ksp":                       true,
+	"classpath":                 true,
+	"compile":                   true, // Gradle 7 で削除された旧 configuration
+	"testCompile":               true,
+	"androidTestImplementation": true,
+}
 
-	var dependencies []analyze.Dependency
+// parseBuildGradle はbuild.gradle / build.gradle.kts の文字列記法の依存宣言を抽出する。
+//
+//	implementation "com.google.guava:guava:32.1.2-jre"
+//	testImplementation("org.junit.jupiter:junit-jupiter:5.10.0")
+//
+// group: / name: を分けて書くマップ記法や、libs.xxx によるカタログ参照は対象外
+// （カタログ側は parseVersionCatalog で拾う）。
+func parseBuildGradle(content string) []mavenDependency {
+	var deps []mavenDependency
 
-	lines := strings.Split(string(content), "\n")
+	lines := strings.Split(content, "\n")
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
-		if line == "" || strings.HasPrefix(line, "#") {
+		if strings.HasPrefix(line, "//") {
 			continue
 		}
 
-		// package==version 形式をパース
-		var name, version string
-		if strings.Contains(line, "==") {
-			parts := strings.Split(line, "==")
-			name = parts[0]
-			version = parts[1]
-		} else if strings.Contains(line, ">=") {
-			parts := strings.Split(line, ">=")
-			name = parts[0]
-			version = parts[1]
-		} else {
+		quote := strings.IndexAny(line, `"'`)
+		if quote == -1 {
 			continue
 		}
-
-		releasedAt, err := c.getPyPIReleaseDate(ctx, name, version)
-		if err != nil {
+		config := strings.TrimSpace(strings.TrimRight(line[:quote], "( "))
+		if !gradleConfigurations[config] {
 			continue
 		}
 
-		dependencies = append(dependencies, analyze.Dependency{
-			Name:        name,
-			Version:     version,
-			ReleasedAt:  releasedAt,
-			AgeMonths:   ageMonths(releasedAt),
-			PackageType: "python",
-		})
+		rest := line[quote+1:]
+		end := strings.IndexAny(rest, `"'`)
+		if end == -1 {
+			continue
+		}
+		if dep, ok := parseGradleNotation(rest[:end]); ok {
+			deps = append(deps, dep)
+		}
 	}
 
-	return dependencies, nil
+	return deps
 }
 
-// getDotNetDependencies は.csprojから依存を取得する。
-func (c *Client) getDotNetDependencies(ctx context.Context, repo domain.Repository) ([]analyze.Dependency, error) {
-	// ファイル一覧から.csprojを探す
-	files, err := c.GetFiles(ctx, repo)
-	if err != nil {
-		return nil, err
+// parseGradleNotation は "group:artifact:version[:classifier][@ext]" を分解する。
+func parseGradleNotation(notation string) (mavenDependency, bool) {
+	notation, _, _ = strings.Cut(notation, "@")
+	parts := strings.Split(notation, ":")
+	if len(parts) < 3 {
+		return mavenDependency{}, false
 	}
+	return mavenDependency{GroupID: parts[0], ArtifactID: parts[1], Version: parts[2]}, true
+}
 
-	var dependencies []analyze.Dependency
+// parseVersionCatalog はバージョンカタログ（libs.versions.toml）の [libraries] を抽出する。
+//
+// 対応する形式:
+//   - guava = "com.google.guava:guava:32.1.2-jre"
+//   - guava = { module = "com.google.guava:guava", version = "32.1.2-jre" }
+//   - guava = { group = "com.google.guava", name = "guava", version.ref = "guava" }
+//
+// version.ref は [versions] の値で解決する。strictly / prefer などのリッチバージョンは対象外。
+func parseVersionCatalog(content string) []mavenDependency {
+	versions := make(map[string]string)
+	var libraries []map[string]string
 
-	for _, f := range files {
-		if !strings.HasSuffix(f.Path, ".csproj") {
+	section := ""
+	lines := strings.Split(content, "\n")
+	for _, line := range lines {
+		line = strings.TrimSpace(stripTomlComment(line))
+		if line == "" {
 			continue
 		}
-
-		content, err := c.GetFileContent(ctx, repo, f.Path)
-		if err != nil {
+		if strings.HasPrefix(line, "[") {
+			section = strings.Trim(line, "[] ")
 			continue
 		}
 
-		// 簡易的なXMLパース（PackageReferenceを抽出）
-		lines := strings.Split(string(content), "\n")
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			if !strings.Contains(line, "PackageReference") {
-				continue
-			}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.Trim(strings.TrimSpace(key), `"`)
+		value = strings.TrimSpace(value)
 
-			// Include="..." と Version="..." を抽出
-			name := extractAttribute(line, "Include")
-			version := extractAttribute(line, "Version")
-			if name == "" || version == "" {
-				continue
+		switch section {
+		case "versions":
+			if v := unquoteToml(value); v != "" {
+				versions[key] = v
 			}
-
-			releasedAt, err := c.getNuGetReleaseDate(ctx, name, version)
-			if err != nil {
-				continue
+		case "libraries":
+			if strings.HasPrefix(value, "{") {
+				libraries = append(libraries, parseTomlInlineTable(value))
+			} else if dep, ok := parseGradleNotation(unquoteToml(value)); ok {
+				libraries = append(libraries, map[string]string{
+					"group": dep.GroupID, "name": dep.ArtifactID, "version": dep.Version,
+				})
 			}
-
-			dependencies = append(dependencies, analyze.Dependency{
-				Name:        name,
-				Version:     version,
-				ReleasedAt:  releasedAt,
-				AgeMonths:   ageMonths(releasedAt),
-				PackageType: "nuget",
-			})
 		}
 	}
 
-	return dependencies, nil
+	var deps []mavenDependency
+	for _, lib := range libraries {
+		group, name := lib["group"], lib["name"]
+		if module := lib["module"]; module != "" {
+			group, name, _ = strings.Cut(module, ":")
+		}
+		version := lib["version"]
+		if ref := lib["version.ref"]; ref != "" {
+			version = versions[ref]
+		}
+		deps = append(deps, mavenDependency{GroupID: group, ArtifactID: name, Version: version})
+	}
+	return deps
 }
 
 // extractAttribute はXML属性値を抽出する。
@@ -647,14 +2203,19 @@ func extractAttribute(line, attr string) string {
 }
 
 // fetchJSON は外部APIにGETリクエストを送り、レスポンスをJSONデコードする。
+// レスポンスは GitHub API と同じディスクキャッシュに保存し、一時的なエラーはリトライする（sendRegistry 参照）。
 func (c *Client) fetchJSON(ctx context.Context, url string, dest interface{}) error {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Accept", "application/json")
+	// crates.io は User-Agent のないリクエストを拒否する
+	req.Header.Set("User-Agent", "lokup")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.cachedGet(url, func() (*http.Response, error) {
+		return c.sendRegistry(ctx, req)
+	})
 	if err != nil {
 		return err
 	}
@@ -668,32 +2229,36 @@ func (c *Client) fetchJSON(ctx context.Context, url string, dest interface{}) er
 }
 
 // ageMonths はリリース日から現在までの月数を計算する。
-func ageMonths(releasedAt time.Time) int {
-	return int(time.Since(releasedAt).Hours() / 24 / 30)
+// リリース日がゼロ値のときや、レジストリの時計のずれで未来になっているときは 0 を返す。
+func (c *Client) ageMonths(releasedAt time.Time) int {
+	if releasedAt.IsZero() {
+		return 0
+	}
+	return max(0, int(c.now().Sub(releasedAt).Hours()/24/30))
 }
 
-// getNpmReleaseDate はnpmレジストリから特定バージョンのリリース日を取得する。
-func (c *Client) getNpmReleaseDate(ctx context.Context, packageName, version string) (time.Time, error) {
+// getNpmRelease はnpmレジストリからバージョン範囲（"^1.2.0" など）を満たす公開済みの最新版と
+// そのリリース日を取得する。範囲を解釈できないとき（タグ名・Git・ファイル指定など）や
+// 満たす版がないときはエラーを返す。
+func (c *Client) getNpmRelease(ctx context.Context, packageName, constraint string) (string, time.Time, error) {
+	r, ok := parseNpmRange(constraint)
+	if !ok {
+		return "", time.Time{}, fmt.Errorf("unsupported version range %q", constraint)
+	}
+
 	url := fmt.Sprintf("https://registry.npmjs.org/%s", packageName)
 
 	var npmResp npmRegistryResponse
 	if err := c.fetchJSON(ctx, url, &npmResp); err != nil {
-		return time.Time{}, err
-	}
-
-	// 指定バージョンのリリース日を探す
-	if releaseTime, ok := npmResp.Time[version]; ok {
-		return releaseTime, nil
+		return "", time.Time{}, err
 	}
 
-	// 完全一致がない場合、部分一致を試す（1.0.0 が 1.0.0-beta などにマッチ）
-	for v, t := range npmResp.Time {
-		if strings.HasPrefix(v, version) {
-			return t, nil
-		}
+	// time には公開済みの全バージョンのほか "created" / "modified" が入っている（maxSatisfying が無視する）
+	version, ok := r.maxSatisfying(slices.Collect(maps.Keys(npmResp.Time)))
+	if !ok {
+		return "", time.Time{}, fmt.Errorf("no version satisfies %s", constraint)
 	}
-
-	return time.Time{}, fmt.Errorf("version %s not found", version)
+	return version, npmResp.Time[version], nil
 }
 
 // getGoReleaseDate はGo Proxyから特定バージョンのリリース日を取得する。
@@ -719,20 +2284,24 @@ func (c *Client) getGoReleaseDate(ctx context.Context, modulePath, version strin
 	return goResp.Time, nil
 }
 
-// getPyPIReleaseDate はPyPIから特定バージョンのリリース日を取得する。
-func (c *Client) getPyPIReleaseDate(ctx context.Context, packageName, version string) (time.Time, error) {
+// getPyPIRelease はPyPIから特定バージョンのリリース日を取得する。
+// version が空なら最新の安定版に解決し、そのバージョンとリリース日を返す。
+func (c *Client) getPyPIRelease(ctx context.Context, packageName, version string) (string, time.Time, error) {
 	url := fmt.Sprintf("https://pypi.org/pypi/%s/json", packageName)
 
 	var pypiResp pypiResponse
 	if err := c.fetchJSON(ctx, url, &pypiResp); err != nil {
-		return time.Time{}, err
+		return "", time.Time{}, err
 	}
 
+	if version == "" {
+		version = pypiResp.Info.Version
+	}
 	if releases, ok := pypiResp.Releases[version]; ok && len(releases) > 0 {
-		return releases[0].UploadTime, nil
+		return version, releases[0].UploadTime, nil
 	}
 
-	return time.Time{}, fmt.Errorf("version %s not found", version)
+	return "", time.Time{}, fmt.Errorf("version %s not found", version)
 }
 
 // getNuGetReleaseDate はNuGetから特定バージョンのリリース日を取得する。
@@ -748,6 +2317,80 @@ func (c *Client) getNuGetReleaseDate(ctx context.Context, packageName, version s
 	return nugetResp.Published, nil
 }
 
+// getCratesIOReleaseDate はcrates.ioから特定バージョンのリリース日を取得する。
+func (c *Client) getCratesIOReleaseDate(ctx context.Context, crateName, version string) (time.Time, error) {
+	url := fmt.Sprintf("https://crates.io/api/v1/crates/%s/%s", crateName, version)
+
+	var cratesResp cratesIOResponse
+	if err := c.fetchJSON(ctx, url, &cratesResp); err != nil {
+		return time.Time{}, err
+	}
+
+	return cratesResp.Version.CreatedAt, nil
+}
+
+// getMavenReleaseDate はMaven Centralから特定バージョンのリリース日を取得する。
+func (c *Client) getMavenReleaseDate(ctx context.Context, groupID, artifactID, version string) (time.Time, error) {
+	query := fmt.Sprintf(`g:"%s" AND a:"%s" AND v:"%s"`, groupID, artifactID, version)
+	endpoint := "https://search.maven.org/solrsearch/select?rows=1&wt=json&q=" + url.QueryEscape(query)
+
+	var searchResp mavenSearchResponse
+	if err := c.fetchJSON(ctx, endpoint, &searchResp); err != nil {
+		return time.Time{}, err
+	}
+	if len(searchResp.Response.Docs) == 0 {
+		return time.Time{}, fmt.Errorf("maven artifact not found: %s:%s:%s", groupID, artifactID, version)
+	}
+
+	// timestamp はミリ秒単位の Unix 時刻
+	return time.UnixMilli(searchResp.Response.Docs[0].Timestamp), nil
+}
+
+// getPackagistReleaseDate はPackagistから特定バージョンのリリース日を取得する。
+func (c *Client) getPackagistReleaseDate(ctx context.Context, packageName, version string) (time.Time, error) {
+	url := fmt.Sprintf("https://repo.packagist.org/p2/%s.json", packageName)
+
+	var packagistResp packagistResponse
+	if err := c.fetchJSON(ctx, url, &packagistResp); err != nil {
+		return time.Time{}, err
+	}
+
+	// タグ名に "v" が付くパッケージもあるため、付けずに比較する
+	for _, v := range packagistResp.Packages[packageName] {
+		if strings.TrimPrefix(v.Version, "v") == version {
+			return v.Time, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("version %s not found", version)
+}
+
+// getRubyGemsReleaseDate はRubyGemsから特定バージョンのリリース日を取得する。
+func (c *Client) getRubyGemsReleaseDate(ctx context.Context, gemName, version string) (time.Time, error) {
+	url := fmt.Sprintf("https://rubygems.org/api/v1/versions/%s.json", gemName)
+
+	var versions []rubyGemsVersion
+	if err := c.fetchJSON(ctx, url, &versions); err != nil {
+		return time.Time{}, err
+	}
+
+	// 同じバージョンでもプラットフォーム別に複数あるため、素の ruby 版を優先する
+	var found *rubyGemsVersion
+	for i, v := range versions {
+		if v.Number != version {
+			continue
+		}
+		if found == nil || v.Platform == "ruby" {
+			found = &versions[i]
+		}
+	}
+	if found == nil {
+		return time.Time{}, fmt.Errorf("version %s not found", version)
+	}
+
+	return found.CreatedAt, nil
+}
+
 // API レスポンスの型定義
 
 type apiCommit struct {
@@ -760,6 +2403,26 @@ type apiCommit struct {
 		} `json:"author"`
 		Message string `json:"message"`
 	} `json:"commit"`
+	Parents []struct {
+		SHA string `json:"sha"`
+	} `json:"parents"`
+	// 以下はコミット詳細APIのみ
+	Stats struct {
+		Additions int `json:"additions"`
+		Deletions int `json:"deletions"`
+	} `json:"stats"`
+	Files []struct {
+		Filename string `json:"filename"`
+	} `json:"files"`
+}
+
+// parentSHAs は親コミットのハッシュを返す（先頭が first parent）。
+func (ac apiCommit) parentSHAs() []string {
+	shas := make([]string, len(ac.Parents))
+	for i, p := range ac.Parents {
+		shas[i] = p.SHA
+	}
+	return shas
 }
 
 type apiContributor struct {
@@ -779,21 +2442,36 @@ type apiPullRequest struct {
 	MergedAt  *time.Time `json:"merged_at"`
 	Additions int        `json:"additions"`
 	Deletions int        `json:"deletions"`
+	Draft     bool       `json:"draft"`
 	User      struct {
 		Login string `json:"login"`
 	} `json:"user"`
 	Head struct {
 		Ref string `json:"ref"` // ブランチ名
+		SHA string `json:"sha"`
 	} `json:"head"`
+	MergeCommitSHA string `json:"merge_commit_sha"`
+}
+
+// mergeSHA はマージで作られたコミットのハッシュを返す。
+// 未マージのPRの merge_commit_sha はテストマージ用のコミットを指すため空にする。
+func (ap apiPullRequest) mergeSHA() string {
+	if ap.MergedAt == nil {
+		return ""
+	}
+	return ap.MergeCommitSHA
 }
 
 type apiTree struct {
-	Tree []apiTreeItem `json:"tree"`
+	SHA       string        `json:"sha"`
+	Tree      []apiTreeItem `json:"tree"`
+	Truncated bool          `json:"truncated"` // 項目数・サイズの上限で一覧が切り詰められた
 }
 
 type apiTreeItem struct {
 	Path string `json:"path"`
 	Type string `json:"type"` // "blob" or "tree"
+	SHA  string `json:"sha"`
 	Size int    `json:"size"` // ファイルサイズ（blobのみ）
 }
 
@@ -812,6 +2490,9 @@ type goProxyResponse struct {
 }
 
 type pypiResponse struct {
+	Info struct {
+		Version string `json:"version"` // 最新の安定版
+	} `json:"info"`
 	Releases map[string][]pypiRelease `json:"releases"`
 }
 
@@ -823,6 +2504,41 @@ type nugetResponse struct {
 	Published time.Time `json:"published"`
 }
 
+type composerJSON struct {
+	Require    map[string]string `json:"require"`
+	RequireDev map[string]string `json:"require-dev"`
+}
+
+// packagistResponse は Packagist の p2 メタデータ。
+// 2件目以降のバージョンは前のバージョンとの差分だけを持つ（minified 形式）が、
+// version と time は毎回変わるため常に含まれる。
+type packagistResponse struct {
+	Packages map[string][]struct {
+		Version string    `json:"version"`
+		Time    time.Time `json:"time"`
+	} `json:"packages"`
+}
+
+type rubyGemsVersion struct {
+	Number    string    `json:"number"`
+	Platform  string    `json:"platform"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type mavenSearchResponse struct {
+	Response struct {
+		Docs []struct {
+			Timestamp int64 `json:"timestamp"`
+		} `json:"docs"`
+	} `json:"response"`
+}
+
+type cratesIOResponse struct {
+	Version struct {
+		CreatedAt time.Time `json:"created_at"`
+	} `json:"version"`
+}
+
 type apiIssue struct {
 	Number      int        `json:"number"`
 	Title       string     `json:"title"`
@@ -835,6 +2551,15 @@ type apiIssue struct {
 	} `json:"labels"`
 }
 
+// apiIssueEvent は Issue イベント API のレスポンス。
+type apiIssueEvent struct {
+	Event     string    `json:"event"` // "labeled" / "assigned" / "closed" など
+	CreatedAt time.Time `json:"created_at"`
+	Label     *struct {
+		Name string `json:"name"`
+	} `json:"label"` // labeled / unlabeled のみ
+}
+
 type apiRelease struct {
 	ID          int       `json:"id"`
 	TagName     string    `json:"tag_name"`
@@ -842,6 +2567,54 @@ type apiRelease struct {
 	PublishedAt time.Time `json:"published_at"`
 }
 
+type apiTag struct {
+	Name   string `json:"name"`
+	Commit struct {
+		SHA string `json:"sha"`
+	} `json:"commit"`
+}
+
+type apiWorkflowRuns struct {
+	WorkflowRuns []apiWorkflowRun `json:"workflow_runs"`
+}
+
+type apiWorkflowRun struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Path      string    `json:"path"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type apiBranch struct {
+	Commit struct {
+		SHA string `json:"sha"`
+	} `json:"commit"`
+}
+
+type apiRepository struct {
+	DefaultBranch string `json:"default_branch"`
+	Private       bool   `json:"private"`
+}
+
+type apiActivity struct {
+	Timestamp time.Time `json:"timestamp"`
+	Actor     struct {
+		Login string `json:"login"`
+	} `json:"actor"`
+}
+
+type apiBranchProtection struct {
+	RequiredPullRequestReviews *struct {
+		RequiredApprovingReviewCount int `json:"required_approving_review_count"`
+	} `json:"required_pull_request_reviews"`
+	RequiredStatusChecks *struct{} `json:"required_status_checks"`
+}
+
+// apiError は GitHub API のエラーレスポンス。
+type apiError struct {
+	Message string `json:"message"`
+}
+
 type apiReview struct {
 	ID          int       `json:"id"`
 	State       string    `json:"state"`
@@ -850,3 +2623,13 @@ type apiReview struct {
 		Login string `json:"login"`
 	} `json:"user"`
 }
+
+// apiComment は Issue コメント API のレスポンス（PRの会話欄のコメント）。
+type apiComment struct {
+	ID        int       `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	User      struct {
+		Login string `json:"login"`
+		Type  string `json:"type"` // "User" / "Bot"
+	} `json:"user"`
+}
@@ -0,0 +1,1370 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/features/analyze"
+	"github.com/ryuka-games/lokup/infrastructure/internal/apitest"
+)
+
+func TestAgeMonths(t *testing.T) {
+	now := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	c := NewClient("", WithClock(func() time.Time { return now }))
+
+	tests := []struct {
+		name       string
+		releasedAt time.Time
+		want       int
+	}{
+		{"a year ago", now.AddDate(0, 0, -365), 12},
+		{"other time zone", now.AddDate(0, 0, -90).In(time.FixedZone("JST", 9*60*60)), 3},
+		{"zero value", time.Time{}, 0},
+		{"future (registry clock skew)", now.Add(48 * time.Hour), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.ageMonths(tt.releasedAt); got != tt.want {
+				t.Errorf("ageMonths(%v) = %d, want %d", tt.releasedAt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetBranchProtection(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    *domain.BranchProtection
+		wantErr bool
+	}{
+		{
+			name:   "required reviews and status checks",
+			status: http.StatusOK,
+			body:   `{"required_pull_request_reviews":{"required_approving_review_count":2},"required_status_checks":{"strict":true,"contexts":["ci"]}}`,
+			want:   &domain.BranchProtection{Branch: "main", Protected: true, RequiredApprovals: 2, RequiredStatusChecks: true},
+		},
+		{
+			name:   "protected without reviews",
+			status: http.StatusOK,
+			body:   `{"enforce_admins":{"enabled":true}}`,
+			want:   &domain.BranchProtection{Branch: "main", Protected: true},
+		},
+		{
+			name:   "not protected",
+			status: http.StatusNotFound,
+			body:   `{"message":"Branch not protected"}`,
+			want:   &domain.BranchProtection{Branch: "main"},
+		},
+		{
+			name:   "no admin access is unknown",
+			status: http.StatusNotFound,
+			body:   `{"message":"Not Found"}`,
+			want:   nil,
+		},
+		{
+			name:   "forbidden is unknown",
+			status: http.StatusForbidden,
+			body:   `{"message":"Resource not accessible by integration"}`,
+			want:   nil,
+		},
+		{
+			name:    "server error",
+			status:  http.StatusInternalServerError,
+			body:    `{}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/repos/o/r":
+					fmt.Fprint(w, `{"default_branch":"main"}`)
+				case "/repos/o/r/branches/main/protection":
+					w.WriteHeader(tt.status)
+					fmt.Fprint(w, tt.body)
+				default:
+					t.Errorf("unexpected request %s", r.URL.Path)
+					http.NotFound(w, r)
+				}
+			}))
+			defer srv.Close()
+
+			c := NewClient("", WithMaxRetries(0), WithBaseURL(srv.URL))
+			got, err := c.GetBranchProtection(context.Background(), domain.NewRepository("o", "r"))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetBranchProtection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetBranchProtection() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetForcePushes(t *testing.T) {
+	period := domain.NewDateRange(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC))
+
+	t.Run("filters by period and stops paging", func(t *testing.T) {
+		var srvURL string
+		var secondPage bool
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/repos/o/r":
+				fmt.Fprint(w, `{"default_branch":"main"}`)
+			case r.URL.Path == "/repos/o/r/activity" && r.URL.Query().Get("page") == "":
+				if got := r.URL.Query(); got.Get("activity_type") != "force_push" || got.Get("ref") != "refs/heads/main" {
+					t.Errorf("query = %v, want force_push on refs/heads/main", got)
+				}
+				w.Header().Set("Link", fmt.Sprintf(`<%s/repos/o/r/activity?page=2>; rel="next"`, srvURL))
+				fmt.Fprint(w, `[
+					{"timestamp":"2025-02-03T00:00:00Z","actor":{"login":"carol"}},
+					{"timestamp":"2025-01-20T00:00:00Z","actor":{"login":"alice"}},
+					{"timestamp":"2024-12-30T00:00:00Z","actor":{"login":"bob"}}
+				]`)
+			case r.URL.Path == "/repos/o/r/activity":
+				secondPage = true
+				fmt.Fprint(w, `[]`)
+			default:
+				t.Errorf("unexpected request %s", r.URL.Path)
+				http.NotFound(w, r)
+			}
+		}))
+		defer srv.Close()
+		srvURL = srv.URL
+
+		c := NewClient("", WithMaxRetries(0), WithBaseURL(srv.URL))
+		got, err := c.GetForcePushes(context.Background(), domain.NewRepository("o", "r"), period)
+		if err != nil {
+			t.Fatalf("GetForcePushes() error = %v", err)
+		}
+		want := []analyze.ForcePush{{Branch: "main", Actor: "alice", Timestamp: time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("GetForcePushes() = %+v, want %+v", got, want)
+		}
+		if secondPage {
+			t.Error("fetched the next page after reaching the period start")
+		}
+	})
+
+	for _, status := range []int{http.StatusNotFound, http.StatusForbidden, http.StatusUnprocessableEntity} {
+		t.Run(fmt.Sprintf("unavailable %d", status), func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/repos/o/r" {
+					fmt.Fprint(w, `{"default_branch":"main"}`)
+					return
+				}
+				w.WriteHeader(status)
+				fmt.Fprint(w, `{"message":"Not Found"}`)
+			}))
+			defer srv.Close()
+
+			c := NewClient("", WithMaxRetries(0), WithBaseURL(srv.URL))
+			got, err := c.GetForcePushes(context.Background(), domain.NewRepository("o", "r"), period)
+			if err != nil || got != nil {
+				t.Errorf("GetForcePushes() = %+v, %v, want nil, nil", got, err)
+			}
+		})
+	}
+}
+
+func TestClient_ConcurrentUse(t *testing.T) {
+	const repos = 20
+	period := domain.NewDateRange(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC))
+
+	var srvURL string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 2ページ目まで辿らせて、ページ送りも並行に走らせる
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=2>; rel="next"`, srvURL, r.URL.Path))
+		}
+		switch path.Base(r.URL.Path) {
+		case "commits":
+			fmt.Fprint(w, `[{"sha":"a","commit":{"author":{"name":"alice","date":"2025-01-10T00:00:00Z"}}}]`)
+		case "issues":
+			fmt.Fprint(w, `[{"number":1,"state":"open","created_at":"2025-01-10T00:00:00Z"}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	srvURL = srv.URL
+
+	c := NewClient("", WithMaxRetries(0), WithCache(t.TempDir(), time.Hour), WithBaseURL(srv.URL))
+
+	// 2周目はキャッシュからの読み出しと書き込みが並行する
+	for round := 0; round < 2; round++ {
+		var wg sync.WaitGroup
+		errs := make(chan error, repos*2)
+		for i := 0; i < repos; i++ {
+			repo := domain.NewRepository("o", fmt.Sprintf("r%d", i%5))
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				commits, err := c.GetCommits(context.Background(), repo, period, "", "")
+				if err == nil && len(commits) != 2 {
+					err = fmt.Errorf("GetCommits(%s) returned %d commits, want 2", repo.FullName(), len(commits))
+				}
+				errs <- err
+			}()
+			go func() {
+				defer wg.Done()
+				issues, err := c.GetIssues(context.Background(), repo, "all", nil)
+				if err == nil && len(issues) != 2 {
+					err = fmt.Errorf("GetIssues(%s) returned %d issues, want 2", repo.FullName(), len(issues))
+				}
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			if err != nil {
+				t.Errorf("round %d: %v", round, err)
+			}
+		}
+	}
+}
+
+func TestClient_SharedRateLimit(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message":"API rate limit exceeded"}`)
+	}))
+	defer srv.Close()
+
+	c := NewClient("", WithMaxRetries(0), WithBaseURL(srv.URL))
+	repo := domain.NewRepository("o", "r")
+
+	if _, err := c.GetIssues(context.Background(), repo, "all", nil); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("GetIssues() error = %v, want ErrRateLimited", err)
+	}
+
+	// 解除まで待てないことが分かっているので、後続は API に出ずに失敗する
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.GetIssues(context.Background(), repo, "open", nil); !errors.Is(err, ErrRateLimited) {
+				t.Errorf("GetIssues() error = %v, want ErrRateLimited", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := requests.Load(); got != 1 {
+		t.Errorf("server received %d requests, want 1", got)
+	}
+}
+
+// fixtureTime は日時フィクスチャ（UTC）を返す。
+func fixtureTime(year int, month time.Month, day, hour, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+}
+
+func TestGetCommits_Fixture(t *testing.T) {
+	period := domain.NewDateRange(fixtureTime(2025, 1, 1, 0, 0), fixtureTime(2025, 1, 31, 0, 0))
+	pages := apitest.ServeFixturePages(t, "commits_page1.json", "commits_page2.json")
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/repos/o/r/commits": func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			if q.Get("since") != "2025-01-01T00:00:00Z" || q.Get("until") != "2025-01-31T00:00:00Z" || q.Get("path") != "src/app" || q.Get("sha") != "develop" {
+				t.Errorf("query = %v, want since/until of the period, path=src/app and sha=develop", q)
+			}
+			pages(w, r)
+		},
+	})
+
+	got, err := c.GetCommits(context.Background(), domain.NewRepository("o", "r"), period, "src/app", "develop")
+	if err != nil {
+		t.Fatalf("GetCommits() error = %v", err)
+	}
+	want := []analyze.Commit{
+		{SHA: "c3", Author: "Alice", Email: "alice@example.com", Date: fixtureTime(2025, 1, 20, 10, 0),
+			Message: "Merge pull request #12 from o/feature/login", Parents: []string{"c2", "b1"}},
+		{SHA: "c2", Author: "Bob", Email: "bob@example.com", Date: fixtureTime(2025, 1, 15, 9, 30),
+			Message: "fix: handle empty input", Parents: []string{"c1"}},
+		{SHA: "c1", Author: "Alice", Email: "alice@example.com", Date: fixtureTime(2025, 1, 5, 8, 0),
+			Message: "Initial commit", Parents: []string{}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetCommits() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetCommitDetail_Fixture(t *testing.T) {
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/repos/o/r/commits/c2": apitest.ServeFixture(t, "commit_detail.json"),
+	})
+
+	got, err := c.GetCommitDetail(context.Background(), domain.NewRepository("o", "r"), "c2")
+	if err != nil {
+		t.Fatalf("GetCommitDetail() error = %v", err)
+	}
+	want := &analyze.Commit{
+		SHA: "c2", Author: "Bob", Email: "bob@example.com", Date: fixtureTime(2025, 1, 15, 9, 30),
+		Message: "fix: handle empty input", Parents: []string{"c1"},
+		Files:     []string{"src/input.go", "src/input_test.go"},
+		Additions: 12, Deletions: 3,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetCommitDetail() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetPullRequests_Fixture(t *testing.T) {
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/repos/o/r/pulls":    apitest.ServeFixture(t, "pulls.json"),
+		"/repos/o/r/pulls/12": apitest.ServeFixture(t, "pull_detail.json"),
+	})
+	repo := domain.NewRepository("o", "r")
+	merged := fixtureTime(2025, 1, 20, 10, 0)
+
+	got, err := c.GetPullRequests(context.Background(), repo, "all")
+	if err != nil {
+		t.Fatalf("GetPullRequests() error = %v", err)
+	}
+	// 未マージのPRの merge_commit_sha（テストマージ）は MergeSHA に入れない
+	want := []analyze.PullRequest{
+		{Number: 12, Title: "Add login", Author: "alice", HeadBranch: "feature/login", HeadSHA: "b1",
+			MergeSHA: "c3", CreatedAt: fixtureTime(2025, 1, 10, 0, 0), MergedAt: &merged},
+		{Number: 13, Title: "WIP: refactor", Author: "bob", HeadBranch: "refactor", HeadSHA: "d1",
+			CreatedAt: fixtureTime(2025, 1, 25, 0, 0), Draft: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetPullRequests() = %+v, want %+v", got, want)
+	}
+
+	detail, err := c.GetPRDetail(context.Background(), repo, 12)
+	if err != nil {
+		t.Fatalf("GetPRDetail() error = %v", err)
+	}
+	wantDetail := want[0]
+	wantDetail.Additions, wantDetail.Deletions = 120, 30
+	if !reflect.DeepEqual(*detail, wantDetail) {
+		t.Errorf("GetPRDetail() = %+v, want %+v", *detail, wantDetail)
+	}
+}
+
+func TestGetIssues_Fixture(t *testing.T) {
+	since := fixtureTime(2025, 1, 1, 0, 0)
+	issues := apitest.ServeFixture(t, "issues.json")
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/repos/o/r/issues": func(w http.ResponseWriter, r *http.Request) {
+			if q := r.URL.Query(); q.Get("state") != "all" || q.Get("since") != "2025-01-01T00:00:00Z" {
+				t.Errorf("query = %v, want state=all and since", q)
+			}
+			issues(w, r)
+		},
+	})
+
+	got, err := c.GetIssues(context.Background(), domain.NewRepository("o", "r"), "all", &since)
+	if err != nil {
+		t.Fatalf("GetIssues() error = %v", err)
+	}
+	// PR（pull_request を持つ要素）は除外される
+	closed := fixtureTime(2025, 1, 4, 12, 0)
+	want := []analyze.Issue{
+		{Number: 21, Title: "Crash on startup", State: "closed", Labels: []string{"bug", "incident"},
+			CreatedAt: fixtureTime(2025, 1, 2, 0, 0), ClosedAt: &closed},
+		{Number: 22, Title: "Document setup", State: "open", Labels: []string{},
+			CreatedAt: fixtureTime(2025, 1, 18, 0, 0)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetIssues() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetRepository(t *testing.T) {
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/repos/o/r": func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `{"full_name": "o/r", "default_branch": "main", "private": true}`)
+		},
+	})
+
+	got, err := c.GetRepository(context.Background(), domain.NewRepository("o", "r"))
+	if err != nil {
+		t.Fatalf("GetRepository() error = %v", err)
+	}
+	want := &RepositoryAccess{StatusCode: http.StatusOK, Status: "200 OK", DefaultBranch: "main", Private: true}
+	if !reflect.DeepEqual(got, want) || !got.Reachable() {
+		t.Errorf("GetRepository() = %+v, want %+v", got, want)
+	}
+
+	// 存在しない（またはトークンで見えない）リポジトリはエラーにせずステータスを返す
+	got, err = c.GetRepository(context.Background(), domain.NewRepository("o", "missing"))
+	if err != nil {
+		t.Fatalf("GetRepository() error = %v", err)
+	}
+	if got.Reachable() || got.StatusCode != http.StatusNotFound || got.Status != "404 Not Found" {
+		t.Errorf("GetRepository() = %+v, want unreachable 404", got)
+	}
+}
+
+func TestGetLastCommit(t *testing.T) {
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/repos/o/r/commits": func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			if q.Get("until") != "2025-01-31T00:00:00Z" || q.Get("per_page") != "1" || q.Get("path") != "src" {
+				t.Errorf("query = %v", q)
+			}
+			fmt.Fprint(w, `[{"sha": "abc", "commit": {"message": "fix", "author": {"name": "alice", "email": "alice@example.com", "date": "2024-06-01T09:00:00Z"}}, "parents": []}]`)
+		},
+		"/repos/o/empty/commits": func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, `{"message": "Git Repository is empty."}`, http.StatusConflict)
+		},
+	})
+
+	before := fixtureTime(2025, 1, 31, 0, 0)
+	got, err := c.GetLastCommit(context.Background(), domain.NewRepository("o", "r"), before, "src", "")
+	if err != nil {
+		t.Fatalf("GetLastCommit() error = %v", err)
+	}
+	if got == nil || got.SHA != "abc" || !got.Date.Equal(fixtureTime(2024, 6, 1, 9, 0)) {
+		t.Errorf("GetLastCommit() = %+v, want abc at 2024-06-01", got)
+	}
+
+	// 空のリポジトリ（409）はコミットなしとして扱う
+	got, err = c.GetLastCommit(context.Background(), domain.NewRepository("o", "empty"), before, "", "")
+	if err != nil || got != nil {
+		t.Errorf("GetLastCommit(empty) = %+v, %v, want nil, nil", got, err)
+	}
+}
+
+func TestGetPRComments_Fixture(t *testing.T) {
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/repos/o/r/issues/12/comments": apitest.ServeFixture(t, "issue_comments.json"),
+	})
+
+	got, err := c.GetPRComments(context.Background(), domain.NewRepository("o", "r"), 12)
+	if err != nil {
+		t.Fatalf("GetPRComments() error = %v", err)
+	}
+	want := []analyze.Comment{
+		{ID: 501, Author: "netlify[bot]", Bot: true, CreatedAt: fixtureTime(2025, 1, 10, 3, 0)},
+		{ID: 502, Author: "carol", CreatedAt: fixtureTime(2025, 1, 10, 5, 30)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetPRComments() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetIssueEvents_Fixture(t *testing.T) {
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/repos/o/r/issues/21/events": apitest.ServeFixture(t, "issue_events.json"),
+	})
+
+	got, err := c.GetIssueEvents(context.Background(), domain.NewRepository("o", "r"), 21)
+	if err != nil {
+		t.Fatalf("GetIssueEvents() error = %v", err)
+	}
+	want := []analyze.IssueEvent{
+		{Event: "labeled", Label: "bug", CreatedAt: fixtureTime(2025, 1, 2, 1, 0)},
+		{Event: "assigned", CreatedAt: fixtureTime(2025, 1, 3, 9, 0)},
+		{Event: "closed", CreatedAt: fixtureTime(2025, 1, 4, 12, 0)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetIssueEvents() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetReleases_Fixture(t *testing.T) {
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/repos/o/r/releases": apitest.ServeFixture(t, "releases.json"),
+	})
+
+	got, err := c.GetReleases(context.Background(), domain.NewRepository("o", "r"))
+	if err != nil {
+		t.Fatalf("GetReleases() error = %v", err)
+	}
+	want := []analyze.Release{
+		{ID: 2, TagName: "v1.1.0", Name: "v1.1.0", PublishedAt: fixtureTime(2025, 1, 28, 0, 0)},
+		{ID: 1, TagName: "v1.0.0", Name: "First release", PublishedAt: fixtureTime(2025, 1, 3, 0, 0)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetReleases() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetFiles_Fixture(t *testing.T) {
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/repos/o/r/git/trees/HEAD": apitest.ServeFixture(t, "tree.json"),
+	})
+
+	got, truncated, err := c.GetFiles(context.Background(), domain.NewRepository("o", "r"), "")
+	if err != nil {
+		t.Fatalf("GetFiles() error = %v", err)
+	}
+	// ディレクトリ（tree）とサブモジュール（commit）は含めない
+	want := []analyze.File{
+		{Path: "README.md", Size: 1200},
+		{Path: "src/main.go", Size: 3400},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetFiles() = %+v, want %+v", got, want)
+	}
+	if truncated {
+		t.Error("GetFiles() truncated = true, want false")
+	}
+}
+
+func TestGetFiles_Branch(t *testing.T) {
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/repos/o/r/branches/feature/x": func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `{"name": "feature/x", "commit": {"sha": "abc123"}}`)
+		},
+		// "/" を含むブランチ名は先頭コミットの SHA に解決してからツリーを取得する
+		"/repos/o/r/git/trees/abc123": apitest.ServeFixture(t, "tree.json"),
+	})
+	repo := domain.NewRepository("o", "r")
+
+	got, _, err := c.GetFiles(context.Background(), repo, "feature/x")
+	if err != nil {
+		t.Fatalf("GetFiles() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("GetFiles() = %+v, want 2 files", got)
+	}
+
+	if _, _, err := c.GetFiles(context.Background(), repo, "missing"); err == nil || !strings.Contains(err.Error(), `branch "missing" not found`) {
+		t.Errorf("GetFiles(missing) error = %v, want branch not found", err)
+	}
+}
+
+func TestBranchExists(t *testing.T) {
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/repos/o/r/branches/develop": func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `{"name": "develop", "commit": {"sha": "abc123"}}`)
+		},
+	})
+	repo := domain.NewRepository("o", "r")
+
+	for branch, want := range map[string]bool{"develop": true, "missing": false} {
+		got, err := c.BranchExists(context.Background(), repo, branch)
+		if err != nil {
+			t.Fatalf("BranchExists(%s) error = %v", branch, err)
+		}
+		if got != want {
+			t.Errorf("BranchExists(%s) = %v, want %v", branch, got, want)
+		}
+	}
+}
+
+// serveTree は recursive 指定の有無で別々のツリーを返す（nil なら 404）。
+func serveTree(recursive, direct string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body := direct
+		if r.URL.Query().Get("recursive") != "" {
+			body = recursive
+		}
+		if body == "" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	}
+}
+
+func TestGetFiles_TruncatedTree(t *testing.T) {
+	// ルートの recursive が切り詰められたら、直下 → 子ディレクトリの recursive → さらに切り詰められたら直下、と辿る
+	routes := map[string]http.HandlerFunc{
+		"/repos/o/r/git/trees/HEAD": serveTree(`{"sha": "root", "truncated": true, "tree": [
+			{"path": "README.md", "type": "blob", "size": 1200}
+		]}`, ""),
+		"/repos/o/r/git/trees/root": serveTree("", `{"sha": "root", "truncated": false, "tree": [
+			{"path": "README.md", "type": "blob", "size": 1200},
+			{"path": "src", "type": "tree", "sha": "src"},
+			{"path": "web", "type": "tree", "sha": "web"}
+		]}`),
+		"/repos/o/r/git/trees/src": serveTree(`{"sha": "src", "truncated": false, "tree": [
+			{"path": "pkg", "type": "tree", "sha": "pkg"},
+			{"path": "pkg/util.go", "type": "blob", "size": 300},
+			{"path": "main.go", "type": "blob", "size": 3400}
+		]}`, ""),
+		"/repos/o/r/git/trees/web": serveTree(`{"sha": "web", "truncated": true, "tree": []}`, `{"sha": "web", "truncated": false, "tree": [
+			{"path": "app.ts", "type": "blob", "size": 900}
+		]}`),
+	}
+	c := newFixtureClient(t, routes)
+
+	got, truncated, err := c.GetFiles(context.Background(), domain.NewRepository("o", "r"), "")
+	if err != nil {
+		t.Fatalf("GetFiles() error = %v", err)
+	}
+	want := []analyze.File{
+		{Path: "README.md", Size: 1200},
+		{Path: "src/pkg/util.go", Size: 300},
+		{Path: "src/main.go", Size: 3400},
+		{Path: "web/app.ts", Size: 900},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetFiles() = %+v, want %+v", got, want)
+	}
+	if truncated {
+		t.Error("GetFiles() truncated = true, want false after walking directories")
+	}
+
+	// 辿り直しに失敗したら、取れた分のうち多い方と truncated を返す
+	routes["/repos/o/r/git/trees/web"] = serveTree("", "")
+	c = newFixtureClient(t, routes)
+	got, truncated, err = c.GetFiles(context.Background(), domain.NewRepository("o", "r"), "")
+	if err != nil {
+		t.Fatalf("GetFiles() error = %v", err)
+	}
+	if !truncated || len(got) != 3 {
+		t.Errorf("GetFiles() = %d files, truncated %v, want 3 files, truncated", len(got), truncated)
+	}
+}
+
+func TestGetDependencies_Fixture(t *testing.T) {
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/repos/o/r/contents/package.json":     serveContent(t, "package.json"),
+		"/repos/o/r/contents/go.mod":           serveContent(t, "go.mod.txt"),
+		"/repos/o/r/contents/requirements.txt": serveContent(t, "requirements.txt"),
+		"/repos/o/r/contents/Cargo.toml":       serveContent(t, "Cargo.toml"),
+
+		"/registry.npmjs.org/react":      apitest.ServeFixture(t, "registry/npm_react.json"),
+		"/registry.npmjs.org/typescript": apitest.ServeFixture(t, "registry/npm_typescript.json"),
+		// モジュールパスの大文字は "!" + 小文字にエスケープされる
+		"/proxy.golang.org/github.com/!burnt!sushi/toml/@v/v1.3.2.info": apitest.ServeFixture(t, "registry/goproxy_toml.json"),
+		"/proxy.golang.org/golang.org/x/text/@v/v0.14.0.info":           apitest.ServeFixture(t, "registry/goproxy_text.json"),
+		"/pypi.org/pypi/requests/json":                                  apitest.ServeFixture(t, "registry/pypi_requests.json"),
+		"/pypi.org/pypi/flask/json":                                     apitest.ServeFixture(t, "registry/pypi_flask.json"),
+		"/crates.io/api/v1/crates/serde/1.0.0":                          apitest.ServeFixture(t, "registry/crates_serde.json"),
+	})
+
+	got, err := c.GetDependencies(context.Background(), domain.NewRepository("o", "r"))
+	if err != nil {
+		t.Fatalf("GetDependencies() error = %v", err)
+	}
+	// エコシステムごとに並ぶため名前順にそろえる
+	sort.Slice(got, func(i, j int) bool { return got[i].Name < got[j].Name })
+
+	// リリース日が取れない依存（golang.org/x/sync・バージョン指定のない numpy）は含めない
+	want := []analyze.Dependency{
+		// 下限だけの "flask>=2.3.0" は PyPI の最新の安定版に解決する
+		{Name: "flask", Version: "2.3.3", ReleasedAt: fixtureTime(2023, 8, 21, 0, 0), AgeMonths: 22, PackageType: "python"},
+		{Name: "github.com/BurntSushi/toml", Version: "1.3.2", ReleasedAt: fixtureTime(2023, 6, 8, 0, 0), AgeMonths: 25, PackageType: "go"},
+		{Name: "golang.org/x/text", Version: "0.14.0", ReleasedAt: fixtureTime(2023, 10, 26, 0, 0), AgeMonths: 20, PackageType: "go"},
+		// "^18.2.0" は範囲を満たす公開済みの最新版に解決する
+		{Name: "react", Version: "18.3.0", ReleasedAt: fixtureTime(2024, 4, 25, 0, 0), AgeMonths: 14, PackageType: "npm"},
+		{Name: "requests", Version: "2.31.0", ReleasedAt: fixtureTime(2023, 5, 22, 0, 0), AgeMonths: 25, PackageType: "python"},
+		// Cargo の要件 "1.0" は具体的なバージョンにそろえる
+		{Name: "serde", Version: "1.0.0", ReleasedAt: fixtureTime(2024, 1, 10, 0, 0), AgeMonths: 17, PackageType: "cargo"},
+		{Name: "typescript", Version: "5.1.6", ReleasedAt: fixtureTime(2023, 6, 28, 0, 0), AgeMonths: 24, PackageType: "npm"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetDependencies() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseCargoToml(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []cargoDependency
+	}{
+		{
+			name: "plain and inline table",
+			content: `[package]
+name = "app"
+version = "0.1.0"
+
+[dependencies]
+serde = "1.0"
+tokio = { version = "1.28", features = ["full", "macros"] }
+`,
+			want: []cargoDependency{{"serde", "1.0"}, {"tokio", "1.28"}},
+		},
+		{
+			name: "dev-dependencies and renamed package",
+			content: `[dev-dependencies]
+rand = "^0.8"
+json = { package = "serde_json", version = "~1.0.100" }
+`,
+			want: []cargoDependency{{"rand", "^0.8"}, {"serde_json", "~1.0.100"}},
+		},
+		{
+			name: "comments and quoted keys",
+			content: `[dependencies] # runtime
+# serde = "1.0"
+"log" = "=0.4.20" # pinned
+anyhow = "1.0" # "quoted" in a comment
+`,
+			want: []cargoDependency{{"log", "=0.4.20"}, {"anyhow", "1.0"}},
+		},
+		{
+			name: "dependency tables",
+			content: `[dependencies.clap]
+version = "4.3"
+features = ["derive"]
+
+[dev-dependencies.pretty]
+package = "pretty_assertions"
+version = "1"
+`,
+			want: []cargoDependency{{"clap", "4.3"}, {"pretty_assertions", "1"}},
+		},
+		{
+			name: "workspace, path and git deps have no version",
+			content: `[dependencies]
+core = { path = "../core" }
+shared = { workspace = true }
+forked = { git = "https://github.com/o/forked" }
+local = { path = "../local", version = "0.2" }
+
+[dependencies.tbl]
+path = "../tbl"
+`,
+			want: []cargoDependency{{"local", "0.2"}},
+		},
+		{
+			name: "other sections are ignored",
+			content: `[package]
+version = "0.1.0"
+
+[build-dependencies]
+cc = "1.0"
+
+[features]
+default = "std"
+`,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseCargoToml(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCargoToml() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTomlInlineTable(t *testing.T) {
+	tests := []struct {
+		value string
+		want  map[string]string
+	}{
+		{`{ version = "1.2" }`, map[string]string{"version": "1.2"}},
+		{`{ version = "1.2", package = "bar" }`, map[string]string{"version": "1.2", "package": "bar"}},
+		{`{ version = "1", features = ["a", "b"], default-features = false }`, map[string]string{"version": "1"}},
+		{`{ path = "../core" }`, map[string]string{"path": "../core"}},
+		{`{ workspace = true }`, map[string]string{}},
+		{`{}`, map[string]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := parseTomlInlineTable(tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTomlInlineTable(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripTomlComment(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{`serde = "1.0"`, `serde = "1.0"`},
+		{`serde = "1.0" # latest`, `serde = "1.0" `},
+		{`# serde = "1.0"`, ``},
+		{`url = "https://example.com/#anchor"`, `url = "https://example.com/#anchor"`},
+		{`url = "a#b" # c`, `url = "a#b" `},
+		{`[dependencies] # runtime`, `[dependencies] `},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if got := stripTomlComment(tt.line); got != tt.want {
+				t.Errorf("stripTomlComment(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnquoteToml(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{`"1.0"`, "1.0"},
+		{`  "^0.4"  `, "^0.4"},
+		{`""`, ""},
+		{`"`, ""},
+		{`1.0`, ""},
+		{`true`, ""},
+		{`'1.0'`, ""}, // リテラル文字列には対応しない
+		{`["a"]`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := unquoteToml(tt.value); got != tt.want {
+				t.Errorf("unquoteToml(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeCargoVersion(t *testing.T) {
+	tests := []struct {
+		req  string
+		want string
+	}{
+		{"1.2.3", "1.2.3"},
+		{"1.2", "1.2.0"},
+		{"1", "1.0.0"},
+		{"^0.4", "0.4.0"},
+		{"^1.2.3", "1.2.3"},
+		{"~1.2", "1.2.0"},
+		{"=0.4.20", "0.4.20"},
+		{">=1.5", "1.5.0"},
+		{" 1.0 ", "1.0.0"},
+		{"1.0.0-beta.1", "1.0.0-beta.1"},
+		{">=1.2, <2", ""},
+		{"*", ""},
+		{"1.*", ""},
+		{"^", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.req, func(t *testing.T) {
+			if got := normalizeCargoVersion(tt.req); got != tt.want {
+				t.Errorf("normalizeCargoVersion(%q) = %q, want %q", tt.req, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPadVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"1", "1.0.0"},
+		{"1.2", "1.2.0"},
+		{"1.2.3", "1.2.3"},
+		{"1.2-beta", "1.2.0-beta"},
+		{"1+build.5", "1.0.0+build.5"},
+		{"1.2.3-rc.1+build", "1.2.3-rc.1+build"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := padVersion(tt.version); got != tt.want {
+				t.Errorf("padVersion(%q) = %q, want %q", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePOM(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []mavenDependency
+		wantErr bool
+	}{
+		{
+			name: "dependencies",
+			content: `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <dependencies>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+      <version>32.1.2-jre</version>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <version>4.13.2</version>
+      <scope>test</scope>
+    </dependency>
+  </dependencies>
+</project>`,
+			want: []mavenDependency{
+				{"com.google.guava", "guava", "32.1.2-jre"},
+				{"junit", "junit", "4.13.2"},
+			},
+		},
+		{
+			name: "dependencyManagement first, managed versions and duplicates",
+			content: `<project>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>org.slf4j</groupId>
+        <artifactId>slf4j-api</artifactId>
+        <version>2.0.9</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+      <version> 2.0.9 </version>
+    </dependency>
+    <dependency>
+      <groupId>org.projectlombok</groupId>
+      <artifactId>lombok</artifactId>
+    </dependency>
+  </dependencies>
+</project>`,
+			want: []mavenDependency{{"org.slf4j", "slf4j-api", "2.0.9"}},
+		},
+		{
+			// プロパティ参照は解決せずそのまま返す（resolveMavenDependencies で除く）
+			name: "property version",
+			content: `<project>
+  <properties><spring.version>6.0.11</spring.version></properties>
+  <dependencies>
+    <dependency>
+      <groupId>org.springframework</groupId>
+      <artifactId>spring-core</artifactId>
+      <version>${spring.version}</version>
+    </dependency>
+  </dependencies>
+</project>`,
+			want: []mavenDependency{{"org.springframework", "spring-core", "${spring.version}"}},
+		},
+		{
+			name:    "no dependencies",
+			content: `<project><artifactId>app</artifactId></project>`,
+			want:    nil,
+		},
+		{
+			name:    "invalid xml",
+			content: `<project><dependencies>`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePOM([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePOM() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePOM() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUniqueMavenDependencies(t *testing.T) {
+	tests := []struct {
+		name string
+		deps []mavenDependency
+		want []mavenDependency
+	}{
+		{
+			name: "keeps order and drops duplicates",
+			deps: []mavenDependency{{"a", "x", "1.0"}, {"b", "y", "2.0"}, {"a", "x", "1.0"}},
+			want: []mavenDependency{{"a", "x", "1.0"}, {"b", "y", "2.0"}},
+		},
+		{
+			name: "different versions are kept",
+			deps: []mavenDependency{{"a", "x", "1.0"}, {"a", "x", "1.1"}},
+			want: []mavenDependency{{"a", "x", "1.0"}, {"a", "x", "1.1"}},
+		},
+		{
+			name: "trims whitespace before comparing",
+			deps: []mavenDependency{{" a ", "x\n", " 1.0"}, {"a", "x", "1.0"}},
+			want: []mavenDependency{{"a", "x", "1.0"}},
+		},
+		{
+			name: "drops incomplete coordinates",
+			deps: []mavenDependency{{"", "x", "1.0"}, {"a", "", "1.0"}, {"a", "x", ""}, {"a", "x", "  "}},
+			want: nil,
+		},
+		{
+			name: "empty",
+			deps: nil,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := uniqueMavenDependencies(tt.deps); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("uniqueMavenDependencies() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBuildGradle(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []mavenDependency
+	}{
+		{
+			name: "groovy DSL",
+			content: `plugins {
+    id 'java'
+}
+
+dependencies {
+    implementation 'com.google.guava:guava:32.1.2-jre'
+    testImplementation "junit:junit:4.13.2"
+    compileOnly 'org.projectlombok:lombok:1.18.30'
+    annotationProcessor 'org.projectlombok:lombok:1.18.30'
+}`,
+			want: []mavenDependency{
+				{"com.google.guava", "guava", "32.1.2-jre"},
+				{"junit", "junit", "4.13.2"},
+				{"org.projectlombok", "lombok", "1.18.30"},
+				{"org.projectlombok", "lombok", "1.18.30"},
+			},
+		},
+		{
+			name: "kotlin DSL",
+			content: `plugins {
+    kotlin("jvm") version "1.9.10"
+}
+
+dependencies {
+    implementation(kotlin("stdlib"))
+    implementation("com.squareup.okhttp3:okhttp:4.11.0")
+    testImplementation ("org.junit.jupiter:junit-jupiter:5.10.0")
+    androidTestImplementation("androidx.test:runner:1.5.2@aar")
+}`,
+			want: []mavenDependency{
+				{"com.squareup.okhttp3", "okhttp", "4.11.0"},
+				{"org.junit.jupiter", "junit-jupiter", "5.10.0"},
+				{"androidx.test", "runner", "1.5.2"},
+			},
+		},
+		{
+			// プロパティ参照は解決せずそのまま返す（resolveMavenDependencies で除く）
+			name: "property versions",
+			content: `dependencies {
+    implementation "org.jetbrains.kotlin:kotlin-reflect:$kotlinVersion"
+    implementation("io.ktor:ktor-server-core:${Versions.ktor}")
+}`,
+			want: []mavenDependency{
+				{"org.jetbrains.kotlin", "kotlin-reflect", "$kotlinVersion"},
+				{"io.ktor", "ktor-server-core", "${Versions.ktor}"},
+			},
+		},
+		{
+			name: "ignored declarations",
+			content: `dependencies {
+    // implementation 'com.google.guava:guava:32.1.2-jre'
+    implementation libs.guava
+    implementation(libs.okhttp)
+    implementation platform('org.springframework.boot:spring-boot-dependencies:3.1.0')
+    implementation group: 'commons-io', name: 'commons-io', version: '2.13.0'
+    implementation project(':core')
+    implementation 'org.apache.commons:commons-lang3'
+}`,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseBuildGradle(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseBuildGradle() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGradleNotation(t *testing.T) {
+	tests := []struct {
+		notation string
+		want     mavenDependency
+		wantOK   bool
+	}{
+		{"com.google.guava:guava:32.1.2-jre", mavenDependency{"com.google.guava", "guava", "32.1.2-jre"}, true},
+		{"org.lwjgl:lwjgl:3.3.2:natives-linux", mavenDependency{"org.lwjgl", "lwjgl", "3.3.2"}, true},
+		{"androidx.test:runner:1.5.2@aar", mavenDependency{"androidx.test", "runner", "1.5.2"}, true},
+		{"org.jetbrains.kotlin:kotlin-reflect:$kotlinVersion", mavenDependency{"org.jetbrains.kotlin", "kotlin-reflect", "$kotlinVersion"}, true},
+		{"org.apache.commons:commons-lang3", mavenDependency{}, false},
+		{"guava", mavenDependency{}, false},
+		{"", mavenDependency{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			got, ok := parseGradleNotation(tt.notation)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("parseGradleNotation(%q) = %+v, %v, want %+v, %v", tt.notation, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseVersionCatalog(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []mavenDependency
+	}{
+		{
+			name: "all library forms",
+			content: `[versions]
+guava = "32.1.2-jre"
+okhttp = "4.11.0" # http client
+
+[libraries]
+junit = "junit:junit:4.13.2"
+guava = { module = "com.google.guava:guava", version.ref = "guava" }
+okhttp = { group = "com.squareup.okhttp3", name = "okhttp", version.ref = "okhttp" }
+slf4j = { module = "org.slf4j:slf4j-api", version = "2.0.9" }
+"quoted-key" = { group = "org.example", name = "quoted", version = "1.0.0" }
+
+[plugins]
+kotlin = { id = "org.jetbrains.kotlin.jvm", version = "1.9.10" }
+`,
+			want: []mavenDependency{
+				{"junit", "junit", "4.13.2"},
+				{"com.google.guava", "guava", "32.1.2-jre"},
+				{"com.squareup.okhttp3", "okhttp", "4.11.0"},
+				{"org.slf4j", "slf4j-api", "2.0.9"},
+				{"org.example", "quoted", "1.0.0"},
+			},
+		},
+		{
+			// 解決できないバージョンは空のまま返す（uniqueMavenDependencies で除く）
+			name: "unresolved versions",
+			content: `[libraries]
+missing-ref = { module = "org.example:missing", version.ref = "nope" }
+bom-managed = { module = "org.example:managed" }
+rich = { module = "org.example:rich", version = { strictly = "1.0" } }
+`,
+			want: []mavenDependency{
+				{"org.example", "missing", ""},
+				{"org.example", "managed", ""},
+				{"org.example", "rich", ""},
+			},
+		},
+		{
+			name: "comments and other sections",
+			content: `# [libraries]
+[bundles]
+network = ["okhttp"]
+`,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseVersionCatalog(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseVersionCatalog() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGemfileLock(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []rubyGem
+	}{
+		{
+			name: "direct dependencies only",
+			content: `GEM
+  remote: https://rubygems.org/
+  specs:
+    actionpack (7.0.4)
+      rack (~> 2.0, >= 2.2.0)
+    rack (2.2.8)
+    rails (7.0.4)
+      actionpack (= 7.0.4)
+    rspec (3.12.0)
+
+PLATFORMS
+  ruby
+
+DEPENDENCIES
+  rails (~> 7.0)
+  rspec
+
+BUNDLED WITH
+   2.4.10
+`,
+			want: []rubyGem{{"rails", "7.0.4"}, {"rspec", "3.12.0"}},
+		},
+		{
+			name: "platform variants are merged",
+			content: `GEM
+  specs:
+    nokogiri (1.14.2-arm64-darwin)
+    nokogiri (1.14.2-x86_64-linux)
+
+DEPENDENCIES
+  nokogiri
+`,
+			want: []rubyGem{{"nokogiri", "1.14.2"}},
+		},
+		{
+			// GIT / PATH の specs は rubygems.org にないため数えない
+			name: "git and path sources",
+			content: `GIT
+  remote: https://github.com/o/forked.git
+  specs:
+    forked (0.1.0)
+
+PATH
+  remote: .
+  specs:
+    mygem (1.0.0)
+
+GEM
+  specs:
+    puma (6.4.0)
+
+DEPENDENCIES
+  forked!
+  mygem!
+  puma (>= 6.0)
+`,
+			want: []rubyGem{{"puma", "6.4.0"}},
+		},
+		{
+			name:    "without DEPENDENCIES keeps all specs",
+			content: "GEM\r\n  specs:\r\n    rake (13.0.6)\r\n    json (2.6.3)\r\n",
+			want:    []rubyGem{{"rake", "13.0.6"}, {"json", "2.6.3"}},
+		},
+		{
+			name:    "empty",
+			content: "",
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseGemfileLock(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseGemfileLock() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGemfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []rubyGem
+	}{
+		{
+			name: "version constraints",
+			content: `source "https://rubygems.org"
+
+gem 'rails', '~> 7.0.4'
+gem "puma", ">= 6.0"
+gem 'pg', '1.5.4'
+gem 'sidekiq', '~> 7.1', '>= 7.1.2'
+`,
+			want: []rubyGem{{"rails", "7.0.4"}, {"puma", "6.0"}, {"pg", "1.5.4"}, {"sidekiq", "7.1"}},
+		},
+		{
+			name: "gems without a version",
+			content: `gem 'bootsnap', require: false
+gem 'tzinfo-data', platforms: %i[windows jruby]
+gem 'rake'
+gem "forked", git: "https://github.com/o/forked.git"
+`,
+			want: nil,
+		},
+		{
+			name: "groups and comments",
+			content: `group :development, :test do
+  gem 'rspec-rails', '~> 6.0'
+  # gem 'byebug', '11.1.3'
+end
+gemspec
+`,
+			want: []rubyGem{{"rspec-rails", "6.0"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseGemfile(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseGemfile() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeComposerVersion(t *testing.T) {
+	tests := []struct {
+		constraint string
+		want       string
+	}{
+		{"1.2.3", "1.2.3"},
+		{"^7.0", "7.0.0"},
+		{"^1.2.3", "1.2.3"},
+		{"~1.2", "1.2.0"},
+		{"~1.2.3", "1.2.3"},
+		{"=2.0.1", "2.0.1"},
+		{"v2.1", "2.1.0"},
+		{"  ^1.0  ", "1.0.0"},
+		{"^7.0 || ^8.0", "7.0.0"},
+		{"^7.0|^8.0", "7.0.0"},
+		{">=1.2 <2.0", "1.2.0"},
+		{">=1.2,<2.0", "1.2.0"},
+		{"1.0.0@beta", "1.0.0"},
+		{"^2.0@dev", "2.0.0"},
+		{"1.0.0-RC1", "1.0.0-RC1"},
+		{"dev-main", ""},
+		{"dev-feature/foo as 1.0.x-dev", ""},
+		{"2.x-dev", ""},
+		{"*", ""},
+		{"1.*", ""},
+		{"2.x", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			if got := normalizeComposerVersion(tt.constraint); got != tt.want {
+				t.Errorf("normalizeComposerVersion(%q) = %q, want %q", tt.constraint, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsComposerPlatformPackage(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"php", true},
+		{"php-64bit", true},
+		{"hhvm", true},
+		{"ext-json", true},
+		{"ext-mbstring", true},
+		{"lib-curl", true},
+		{"composer-plugin-api", true},
+		{"monolog/monolog", false},
+		{"symfony/console", false},
+		{"ext-vendor/ext-package", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isComposerPlatformPackage(tt.name); got != tt.want {
+				t.Errorf("isComposerPlatformPackage(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package github
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/infrastructure/internal/apitest"
+)
+
+// fixtureNow はフィクスチャを使うテストの現在時刻（依存の経過月数の基準）。
+var fixtureNow = time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+// newFixtureClient は routes の応答を返す httptest サーバーに向けた Client を返す。
+//
+// routes のキーはリクエストのパス。GitHub API は WithBaseURL でサーバーに向け、
+// パッケージレジストリ（registry.npmjs.org など）は URL を書き換えて
+// "/<ホスト名>/<パス>" としてサーバーに届ける。登録のないパスは 404 を返す。
+func newFixtureClient(t *testing.T, routes map[string]http.HandlerFunc) *Client {
+	t.Helper()
+	srv := apitest.NewServer(t, routes, `{"message":"Not Found"}`)
+	c := NewClient("", WithBaseURL(srv.URL), WithMaxRetries(0), WithClock(func() time.Time { return fixtureNow }))
+	c.httpClient.Transport = rewriteHostTransport{srv: srv}
+	return c
+}
+
+// rewriteHostTransport は GitHub API 以外へのリクエストをテストサーバーに振り向ける。
+type rewriteHostTransport struct {
+	srv *httptest.Server
+}
+
+func (rt rewriteHostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	target := rt.srv.Listener.Addr().String()
+	if req.URL.Host != target {
+		req = req.Clone(req.Context())
+		req.URL.Path = "/" + req.URL.Host + req.URL.Path
+		req.URL.Scheme = "http"
+		req.URL.Host = target
+		req.Host = ""
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// serveContent は testdata 配下のファイルを contents API の形式（base64）で返す。
+func serveContent(t *testing.T, name string) http.HandlerFunc {
+	body, err := json.Marshal(apiContent{
+		Content:  base64.StdEncoding.EncodeToString(apitest.ReadFixture(t, name)),
+		Encoding: "base64",
+	})
+	if err != nil {
+		t.Fatalf("failed to encode content fixture: %v", err)
+	}
+	return apitest.ServeJSON(body)
+}
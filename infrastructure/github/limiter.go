@@ -0,0 +1,29 @@
+package github
+
+import (
+	"net/http"
+
+	"github.com/ryuka-games/lokup/infrastructure/internal/httpapi"
+)
+
+// DefaultConcurrency は同時に送る HTTP リクエスト数のデフォルト。
+// GitHub のセカンダリレート制限（同時リクエスト数の制限）にかからない控えめな値にする。
+const DefaultConcurrency = 5
+
+// WithConcurrency は同時に送る HTTP リクエスト数の上限を設定する。
+//
+// コミット詳細・PR詳細・依存のリリース日などの並列取得は、それぞれのワーカー数に
+// 関係なくこの上限で頭打ちになる。GitHub API 以外（パッケージレジストリ）への
+// リクエストも含む。
+func WithConcurrency(n int) ClientOption {
+	return func(c *Client) {
+		if n > 0 {
+			c.limiter = httpapi.NewLimiter(n)
+		}
+	}
+}
+
+// do はリクエストを1回送る。同時実行数の枠を確保してから送り、結果をログに出す（httpapi.Do 参照）。
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	return httpapi.Do(c.httpClient, c.limiter, c.logger, req)
+}
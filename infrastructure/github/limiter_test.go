@@ -0,0 +1,57 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithConcurrency_LimitsInFlightRequests(t *testing.T) {
+	const limit = 3
+	var inFlight, maxInFlight atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient("", WithConcurrency(limit))
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// GitHub API 向け（doRequest）とレジストリ向け（fetchJSON）の両方が同じ枠を使う
+			if i%2 == 0 {
+				resp, err := c.doRequest(context.Background(), http.MethodGet, srv.URL)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				resp.Body.Close()
+				return
+			}
+			var v struct{}
+			if err := c.fetchJSON(context.Background(), srv.URL, &v); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := maxInFlight.Load(); got > limit {
+		t.Errorf("max in-flight requests = %d, want <= %d", got, limit)
+	}
+}
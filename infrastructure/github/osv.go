@@ -0,0 +1,142 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ryuka-games/lokup/features/analyze"
+)
+
+// osvBatchSize は OSV の querybatch に1回で送るクエリ数の上限（API の上限は1000）。
+const osvBatchSize = 1000
+
+// osvEcosystems は Dependency.PackageType から OSV のエコシステム名への対応。
+// 対応のないものは問い合わせない。
+var osvEcosystems = map[string]string{
+	"npm":      "npm",
+	"go":       "Go",
+	"python":   "PyPI",
+	"nuget":    "NuGet",
+	"cargo":    "crates.io",
+	"rubygems": "RubyGems",
+	"maven":    "Maven",
+	"gradle":   "Maven",
+	"composer": "Packagist",
+}
+
+// osvQuery は querybatch の1件分のクエリ。
+type osvQuery struct {
+	Package osvPackage `json:"package"`
+	Version string     `json:"version"`
+}
+
+type osvPackage struct {
+	Name      string `json:"name"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+type osvBatchRequest struct {
+	Queries []osvQuery `json:"queries"`
+}
+
+// osvBatchResponse は querybatch のレスポンス。results はクエリと同じ順に並ぶ。
+type osvBatchResponse struct {
+	Results []struct {
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+	} `json:"results"`
+}
+
+// CheckVulnerabilities は OSV（https://osv.dev）で各依存の既知の脆弱性を調べる。
+//
+// 依存ごとに /v1/query を呼ぶと依存の数だけリクエストが出るため、/v1/querybatch で
+// まとめて問い合わせる。返すスライスは deps と同じ順で、各要素は影響する脆弱性の ID
+// （GHSA-xxxx や PYSEC-xxxx。OSV がエイリアスとして持つ CVE は含まない）。
+// OSV が扱わないエコシステムの依存と、バージョンが範囲のまま（"^1.2" ">=2.0" など）の依存は
+// 問い合わせず、空のままにする（OSV はバージョンを完全一致で照合するため）。
+func (c *Client) CheckVulnerabilities(ctx context.Context, deps []analyze.Dependency) ([][]string, error) {
+	ids := make([][]string, len(deps))
+
+	var queries []osvQuery
+	var index []int // queries[i] に対応する deps の位置
+	for i, dep := range deps {
+		ecosystem, ok := osvEcosystems[dep.PackageType]
+		if !ok || dep.Name == "" || !isPinnedVersion(dep.Version) {
+			continue
+		}
+		queries = append(queries, osvQuery{
+			Package: osvPackage{Name: dep.Name, Ecosystem: ecosystem},
+			Version: dep.Version,
+		})
+		index = append(index, i)
+	}
+
+	for start := 0; start < len(queries); start += osvBatchSize {
+		end := min(start+osvBatchSize, len(queries))
+		results, err := c.queryOSVBatch(ctx, queries[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for j, vulns := range results {
+			ids[index[start+j]] = vulns
+		}
+	}
+	return ids, nil
+}
+
+// isPinnedVersion は version が範囲やワイルドカードでない、1つに決まったバージョンかを返す。
+func isPinnedVersion(version string) bool {
+	if version == "" || strings.ContainsAny(version, "^~<>=!*|, ") {
+		return false
+	}
+	return !strings.HasSuffix(version, ".x") && !strings.HasPrefix(version, "dev-")
+}
+
+// queryOSVBatch は querybatch を1回呼び、クエリと同じ順で脆弱性の ID（昇順）を返す。
+func (c *Client) queryOSVBatch(ctx context.Context, queries []osvQuery) ([][]string, error) {
+	body, err := json.Marshal(osvBatchRequest{Queries: queries})
+	if err != nil {
+		return nil, err
+	}
+
+	url := c.osvBaseURL + "/v1/querybatch"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "lokup")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query OSV: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to query OSV: HTTP %s", resp.Status)
+	}
+	var batch osvBatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		return nil, fmt.Errorf("failed to decode OSV response: %w", err)
+	}
+	if len(batch.Results) != len(queries) {
+		return nil, fmt.Errorf("failed to query OSV: got %d results for %d queries", len(batch.Results), len(queries))
+	}
+
+	results := make([][]string, len(queries))
+	for i, r := range batch.Results {
+		for _, v := range r.Vulns {
+			results[i] = append(results[i], v.ID)
+		}
+		sort.Strings(results[i])
+	}
+	return results, nil
+}
@@ -0,0 +1,188 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ryuka-games/lokup/features/analyze"
+)
+
+// newOSVServer は querybatch を受け、vulns（"ecosystem/name@version" → ID）で答える OSV のモック。
+func newOSVServer(t *testing.T, vulns map[string][]string, requests *atomic.Int32, got *[]osvQuery) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/querybatch" {
+			t.Errorf("request = %s %s, want POST /v1/querybatch", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Error("GitHub token must not be sent to OSV")
+		}
+		requests.Add(1)
+		var req osvBatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("invalid request body: %v", err)
+		}
+		if got != nil {
+			*got = append(*got, req.Queries...)
+		}
+
+		type vuln struct {
+			ID string `json:"id"`
+		}
+		type result struct {
+			Vulns []vuln `json:"vulns,omitempty"`
+		}
+		resp := struct {
+			Results []result `json:"results"`
+		}{Results: make([]result, len(req.Queries))}
+		for i, q := range req.Queries {
+			for _, id := range vulns[q.Package.Ecosystem+"/"+q.Package.Name+"@"+q.Version] {
+				resp.Results[i].Vulns = append(resp.Results[i].Vulns, vuln{ID: id})
+			}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func TestCheckVulnerabilities(t *testing.T) {
+	var requests atomic.Int32
+	var queries []osvQuery
+	srv := newOSVServer(t, map[string][]string{
+		"npm/lodash@4.17.20":            {"GHSA-35jh-r3h4-6jhm", "GHSA-29mw-wpgm-hmr9"},
+		"Go/golang.org/x/net@0.1.0":     {"GO-2023-1571"},
+		"Maven/org.yaml:snakeyaml@1.30": {"GHSA-mjmj-j48q-9wg2"},
+	}, &requests, &queries)
+	defer srv.Close()
+
+	c := NewClient("ghp_secret")
+	c.osvBaseURL = srv.URL
+	deps := []analyze.Dependency{
+		{Name: "react", Version: "18.2.0", PackageType: "npm"},
+		{Name: "lodash", Version: "4.17.20", PackageType: "npm"},
+		{Name: "golang.org/x/net", Version: "0.1.0", PackageType: "go"},
+		{Name: "left-pad", Version: "1.0.0", PackageType: "unknown"}, // OSV の対象外は問い合わせない
+		{Name: "org.yaml:snakeyaml", Version: "1.30", PackageType: "gradle"},
+	}
+
+	got, err := c.CheckVulnerabilities(context.Background(), deps)
+	if err != nil {
+		t.Fatalf("CheckVulnerabilities() error = %v", err)
+	}
+	want := [][]string{
+		nil,
+		{"GHSA-29mw-wpgm-hmr9", "GHSA-35jh-r3h4-6jhm"}, // 昇順
+		{"GO-2023-1571"},
+		nil,
+		{"GHSA-mjmj-j48q-9wg2"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckVulnerabilities() = %v, want %v", got, want)
+	}
+	if requests.Load() != 1 || len(queries) != 4 {
+		t.Errorf("requests = %d, queries = %d, want 1 batch of 4", requests.Load(), len(queries))
+	}
+}
+
+func TestCheckVulnerabilities_SkipsConstraints(t *testing.T) {
+	var requests atomic.Int32
+	var queries []osvQuery
+	srv := newOSVServer(t, map[string][]string{
+		"crates.io/serde@1.0.0": {"RUSTSEC-0000-0001"},
+		"PyPI/flask@2.3.3":      {"PYSEC-0000-0001"},
+	}, &requests, &queries)
+	defer srv.Close()
+
+	c := NewClient("")
+	c.osvBaseURL = srv.URL
+	// 範囲のままのバージョンは OSV が完全一致で照合できないため問い合わせない
+	deps := []analyze.Dependency{
+		{Name: "serde", Version: "^1.0", PackageType: "cargo"},
+		{Name: "serde", Version: "1.0.0", PackageType: "cargo"},
+		{Name: "flask", Version: ">=2.3.0", PackageType: "python"},
+		{Name: "flask", Version: "2.3.3", PackageType: "python"},
+		{Name: "tokio", Version: "~1.2", PackageType: "cargo"},
+		{Name: "rand", Version: "*", PackageType: "cargo"},
+		{Name: "django", Version: ">=4.0,<5.0", PackageType: "python"},
+		{Name: "monolog/monolog", Version: "1.x", PackageType: "composer"},
+		{Name: "laravel/framework", Version: "dev-main", PackageType: "composer"},
+		{Name: "rails", Version: "~> 7.0", PackageType: "rubygems"},
+		{Name: "left-pad", Version: "", PackageType: "npm"},
+	}
+
+	got, err := c.CheckVulnerabilities(context.Background(), deps)
+	if err != nil {
+		t.Fatalf("CheckVulnerabilities() error = %v", err)
+	}
+	want := make([][]string, len(deps))
+	want[1] = []string{"RUSTSEC-0000-0001"}
+	want[3] = []string{"PYSEC-0000-0001"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckVulnerabilities() = %v, want %v", got, want)
+	}
+	var versions []string
+	for _, q := range queries {
+		versions = append(versions, q.Version)
+	}
+	if !reflect.DeepEqual(versions, []string{"1.0.0", "2.3.3"}) {
+		t.Errorf("queried versions = %v, want only the pinned versions", versions)
+	}
+}
+
+func TestCheckVulnerabilities_Batches(t *testing.T) {
+	var requests atomic.Int32
+	srv := newOSVServer(t, map[string][]string{"npm/pkg1000@1.0.0": {"GHSA-last"}}, &requests, nil)
+	defer srv.Close()
+
+	c := NewClient("")
+	c.osvBaseURL = srv.URL
+	deps := make([]analyze.Dependency, osvBatchSize+1)
+	for i := range deps {
+		deps[i] = analyze.Dependency{Name: fmt.Sprintf("pkg%d", i), Version: "1.0.0", PackageType: "npm"}
+	}
+
+	got, err := c.CheckVulnerabilities(context.Background(), deps)
+	if err != nil {
+		t.Fatalf("CheckVulnerabilities() error = %v", err)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("requests = %d, want 2", requests.Load())
+	}
+	if len(got) != len(deps) || !reflect.DeepEqual(got[osvBatchSize], []string{"GHSA-last"}) {
+		t.Errorf("last result = %v, want [GHSA-last] from the second batch", got[len(got)-1])
+	}
+}
+
+func TestCheckVulnerabilities_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"server error", func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "boom", http.StatusBadRequest)
+		}},
+		{"invalid JSON", func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, "not json")
+		}},
+		{"result count mismatch", func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `{"results":[]}`)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.handler)
+			defer srv.Close()
+			c := NewClient("", WithMaxRetries(0))
+			c.osvBaseURL = srv.URL
+			deps := []analyze.Dependency{{Name: "lodash", Version: "4.17.20", PackageType: "npm"}}
+			if _, err := c.CheckVulnerabilities(context.Background(), deps); err == nil {
+				t.Error("CheckVulnerabilities() error = nil, want error")
+			}
+		})
+	}
+}
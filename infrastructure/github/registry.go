@@ -0,0 +1,75 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ryuka-games/lokup/features/analyze"
+	"github.com/ryuka-games/lokup/infrastructure/internal/httpapi"
+	"github.com/ryuka-games/lokup/shared/parallel"
+)
+
+// releaseLookup は依存1件分のリリース日の問い合わせ。
+type releaseLookup struct {
+	dep analyze.Dependency // Name・Version・PackageType を埋めておく（リリース日と経過月数は解決時に埋める）
+	// fetch はリリース日を引く。バージョン範囲から具体的なバージョンを決めた場合は dep.Version を書き換える
+	fetch func(ctx context.Context, dep *analyze.Dependency) (time.Time, error)
+}
+
+// resolveReleaseDates は依存ごとのリリース日をパッケージレジストリに並行して問い合わせ、
+// 取得できた依存を返す。
+//
+// ワーカー数は同時リクエスト数の上限（WithConcurrency）にそろえる。実際に送る数は
+// limiter が全エンドポイント共通で抑えるため、GitHub API への取得と並行しても上限は超えない。
+// 結果は lookups の順のまま。取得に失敗した依存（レジストリにないプライベートパッケージなど）は除く。
+// ctx がキャンセルされたら未着手の依存は問い合わせない。
+func (c *Client) resolveReleaseDates(ctx context.Context, lookups []releaseLookup) []analyze.Dependency {
+	resolved := make([]bool, len(lookups))
+	parallel.ForEach(ctx, cap(c.limiter), len(lookups), func(i int) {
+		releasedAt, err := lookups[i].fetch(ctx, &lookups[i].dep)
+		if err != nil {
+			return
+		}
+		lookups[i].dep.ReleasedAt = releasedAt
+		lookups[i].dep.AgeMonths = c.ageMonths(releasedAt)
+		resolved[i] = true
+	})
+
+	var dependencies []analyze.Dependency
+	for i, l := range lookups {
+		if resolved[i] {
+			dependencies = append(dependencies, l.dep)
+		}
+	}
+	return dependencies
+}
+
+// sendRegistry はパッケージレジストリへのリクエストを送る。
+//
+// 一時的なエラー（502/503/504・タイムアウト）は GitHub API と同じく指数バックオフでリトライし、
+// 429 は Retry-After が rateLimitMaxWait 以内なら待って1度だけ再送する。
+// レジストリのレート制限は GitHub のものとは別のため、rateLimit には記録せず他のリクエストを止めない。
+func (c *Client) sendRegistry(ctx context.Context, req *http.Request) (*http.Response, error) {
+	for waited := false; ; waited = true {
+		resp, err := c.retry(ctx, req.Method, func() (*http.Response, error) {
+			return c.do(req)
+		})
+		if err != nil || waited || resp.StatusCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+
+		now := c.now()
+		reset, limited := rateLimitReset(resp, now)
+		wait := reset.Sub(now)
+		if !limited || wait > c.rateLimitMaxWait {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		c.logger.Debug("registry rate limited, waiting", "url", req.URL.String(), "wait", wait.Round(time.Second))
+		if err := httpapi.SleepContext(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/features/analyze"
+)
+
+func TestResolveReleaseDates(t *testing.T) {
+	const limit = 4
+	c := NewClient("", WithConcurrency(limit), WithClock(func() time.Time { return fixtureNow }))
+
+	var inFlight, maxInFlight atomic.Int32
+	released := fixtureTime(2024, 7, 1, 0, 0)
+	var lookups []releaseLookup
+	for i := range 12 {
+		name := fmt.Sprintf("pkg%02d", i)
+		lookups = append(lookups, releaseLookup{
+			dep: analyze.Dependency{Name: name, Version: "1.0.0", PackageType: "npm"},
+			fetch: func(ctx context.Context, _ *analyze.Dependency) (time.Time, error) {
+				n := inFlight.Add(1)
+				defer inFlight.Add(-1)
+				for {
+					m := maxInFlight.Load()
+					if n <= m || maxInFlight.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				// 後ろの依存ほど早く終わらせ、完了順と結果の順がずれるようにする
+				time.Sleep(time.Duration(12-i) * time.Millisecond)
+				if i%3 == 0 {
+					return time.Time{}, errors.New("not found")
+				}
+				return released, nil
+			},
+		})
+	}
+
+	got := c.resolveReleaseDates(context.Background(), lookups)
+
+	var names []string
+	for _, d := range got {
+		names = append(names, d.Name)
+		if !d.ReleasedAt.Equal(released) || d.AgeMonths != 12 {
+			t.Errorf("%s: ReleasedAt = %v, AgeMonths = %d, want %v, 12", d.Name, d.ReleasedAt, d.AgeMonths, released)
+		}
+	}
+	want := []string{"pkg01", "pkg02", "pkg04", "pkg05", "pkg07", "pkg08", "pkg10", "pkg11"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("resolved = %v, want %v", names, want)
+	}
+	if m := maxInFlight.Load(); m < 2 || m > limit {
+		t.Errorf("max concurrent lookups = %d, want 2..%d", m, limit)
+	}
+}
+
+func TestResolveReleaseDates_Canceled(t *testing.T) {
+	c := NewClient("")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls atomic.Int32
+	lookups := make([]releaseLookup, 10)
+	for i := range lookups {
+		lookups[i].fetch = func(ctx context.Context, _ *analyze.Dependency) (time.Time, error) {
+			calls.Add(1)
+			return time.Now(), nil
+		}
+	}
+
+	if got := c.resolveReleaseDates(ctx, lookups); len(got) != 0 {
+		t.Errorf("resolveReleaseDates() = %d deps, want 0", len(got))
+	}
+	if n := calls.Load(); n != 0 {
+		t.Errorf("fetch called %d times after cancel, want 0", n)
+	}
+}
+
+func TestFetchJSON_Retry(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int    // 成功を返すまでに失敗させる回数
+		status       int    // 失敗時のステータス
+		retryAfter   string // 失敗時の Retry-After
+		wantErr      bool
+		wantRequests int32
+	}{
+		{name: "transient error is retried", failures: 2, status: http.StatusServiceUnavailable, wantRequests: 3},
+		{name: "too many transient errors", failures: 5, status: http.StatusBadGateway, wantErr: true, wantRequests: 3},
+		{name: "rate limited waits for Retry-After", failures: 1, status: http.StatusTooManyRequests, retryAfter: "0", wantRequests: 2},
+		{name: "rate limited twice", failures: 2, status: http.StatusTooManyRequests, retryAfter: "0", wantErr: true, wantRequests: 2},
+		{name: "rate limit beyond max wait", failures: 1, status: http.StatusTooManyRequests, retryAfter: "3600", wantErr: true, wantRequests: 1},
+		{name: "not found is not retried", failures: 1, status: http.StatusNotFound, wantErr: true, wantRequests: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if int(requests.Add(1)) <= tt.failures {
+					if tt.retryAfter != "" {
+						w.Header().Set("Retry-After", tt.retryAfter)
+					}
+					w.WriteHeader(tt.status)
+					return
+				}
+				fmt.Fprint(w, `{"version":"1.0.0"}`)
+			}))
+			defer srv.Close()
+
+			c := NewClient("", WithMaxRetries(2), WithRateLimitMaxWait(time.Minute))
+			c.retryBaseDelay = time.Millisecond
+
+			var v struct{ Version string }
+			err := c.fetchJSON(context.Background(), srv.URL, &v)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("fetchJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && v.Version != "1.0.0" {
+				t.Errorf("Version = %q, want 1.0.0", v.Version)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("server received %d requests, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestFetchJSON_RateLimitDoesNotBlockGitHub(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/registry" {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `[]`)
+	}))
+	defer srv.Close()
+
+	c := NewClient("", WithMaxRetries(0), WithBaseURL(srv.URL))
+	var v struct{}
+	if err := c.fetchJSON(context.Background(), srv.URL+"/registry", &v); err == nil {
+		t.Fatal("fetchJSON() error = nil, want rate limit error")
+	}
+	// レジストリのレート制限は GitHub API のリクエストを止めない
+	resp, err := c.doRequest(context.Background(), http.MethodGet, srv.URL+"/repos/o/r/issues")
+	if err != nil {
+		t.Fatalf("doRequest() error = %v", err)
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,345 @@
+package github
+
+import (
+	"strconv"
+	"strings"
+)
+
+// semver はセマンティックバージョン（major.minor.patch[-prerelease]）。ビルドメタデータは比較に使わないため持たない。
+type semver struct {
+	major, minor, patch int
+	pre                 []string // プレリリースの識別子（"beta.2" なら ["beta", "2"]、なければ nil）
+}
+
+// parseSemver は "1.2.3" / "v1.2.3-beta.1" / "1.2.3+build" のような完全なバージョンを解釈する。
+// "1.2" のような省略形やタグ名（"latest"）は false を返す。
+func parseSemver(s string) (semver, bool) {
+	p, ok := parsePartialVersion(s)
+	if !ok || p.parts < 3 {
+		return semver{}, false
+	}
+	return p.version, true
+}
+
+// isPrerelease はプレリリース版かを返す。
+func (v semver) isPrerelease() bool {
+	return len(v.pre) > 0
+}
+
+// sameCore は major.minor.patch が同じかを返す。
+func (v semver) sameCore(o semver) bool {
+	return v.major == o.major && v.minor == o.minor && v.patch == o.patch
+}
+
+// compare は v と o を比較し、v が小さければ負、等しければ 0、大きければ正を返す。
+// SemVer 2.0.0 の優先順位に従い、同じ major.minor.patch ならプレリリースのほうが小さい。
+func (v semver) compare(o semver) int {
+	for _, d := range []int{v.major - o.major, v.minor - o.minor, v.patch - o.patch} {
+		if d != 0 {
+			return d
+		}
+	}
+	switch {
+	case len(v.pre) == 0 && len(o.pre) == 0:
+		return 0
+	case len(v.pre) == 0:
+		return 1
+	case len(o.pre) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.pre) && i < len(o.pre); i++ {
+		if d := comparePrereleaseIdent(v.pre[i], o.pre[i]); d != 0 {
+			return d
+		}
+	}
+	return len(v.pre) - len(o.pre)
+}
+
+// comparePrereleaseIdent はプレリリースの識別子1つを比較する。
+// 数字だけの識別子は数値で比べ、英数字の識別子より小さい。
+func comparePrereleaseIdent(a, b string) int {
+	an, aErr := strconv.Atoi(a)
+	bn, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return an - bn
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// partialVersion は "1" / "1.2" / "1.x" のような省略・ワイルドカードを含むバージョン。
+type partialVersion struct {
+	version semver
+	parts   int // 指定のある部分の数（"1.2.x" なら 2、"*" なら 0）
+}
+
+// parsePartialVersion はバージョンの先頭から数字で指定された部分を読む。
+// "x" / "X" / "*" や省略された部分はワイルドカードとして扱い、それ以降は無視する。
+func parsePartialVersion(s string) (partialVersion, bool) {
+	s = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "="), "v")
+	s, _, _ = strings.Cut(s, "+")
+	core, pre, hasPre := strings.Cut(s, "-")
+
+	var p partialVersion
+	if core == "" || core == "*" || core == "x" || core == "X" {
+		return p, !hasPre
+	}
+	fields := strings.Split(core, ".")
+	if len(fields) > 3 {
+		return p, false
+	}
+	nums := [3]*int{&p.version.major, &p.version.minor, &p.version.patch}
+	for i, f := range fields {
+		if f == "x" || f == "X" || f == "*" {
+			break
+		}
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return p, false
+		}
+		*nums[i] = n
+		p.parts++
+	}
+	if hasPre {
+		// プレリリースは完全なバージョンにしか付けられない
+		if p.parts < 3 || pre == "" {
+			return p, false
+		}
+		p.version.pre = strings.Split(pre, ".")
+	}
+	return p, true
+}
+
+// next は指定のある最後の部分を1つ上げた、範囲の上限（未満）を返す。
+// "1.2" なら "1.3.0"、"1" なら "2.0.0"。
+func (p partialVersion) next() semver {
+	v := semver{major: p.version.major, minor: p.version.minor}
+	switch p.parts {
+	case 1:
+		return semver{major: v.major + 1}
+	case 2:
+		return semver{major: v.major, minor: v.minor + 1}
+	default:
+		return semver{major: v.major, minor: v.minor, patch: p.version.patch + 1}
+	}
+}
+
+// comparator はバージョン範囲を構成する比較1つ（">=1.2.3" など）。
+type comparator struct {
+	op string // "<" "<=" ">" ">=" "="
+	v  semver
+}
+
+// matches は v が比較を満たすかを返す。
+func (c comparator) matches(v semver) bool {
+	d := v.compare(c.v)
+	switch c.op {
+	case "<":
+		return d < 0
+	case "<=":
+		return d <= 0
+	case ">":
+		return d > 0
+	case ">=":
+		return d >= 0
+	default:
+		return d == 0
+	}
+}
+
+// npmRange は npm のバージョン範囲。"||" で区切った範囲のどれかを満たせばよく、
+// 各範囲は空白で区切った比較をすべて満たす必要がある。
+type npmRange [][]comparator
+
+// parseNpmRange は package.json の依存に書かれたバージョン範囲を解釈する。
+//
+// 対応する書式は完全・省略形のバージョン（"1.2.3" "1.2" "1.x" "*"）、比較（">=1.2.3 <2"）、
+// キャレット（"^1.2.3"）、チルダ（"~1.2.3"）、ハイフン範囲（"1.2.3 - 2.3.4"）とその "||" 結合。
+// タグ名（"latest"）や URL・Git・ファイル・ワークスペース指定はレジストリのバージョンに
+// 対応付けられないため false を返す。
+func parseNpmRange(s string) (npmRange, bool) {
+	var r npmRange
+	for _, part := range strings.Split(s, "||") {
+		set, ok := parseComparatorSet(part)
+		if !ok {
+			return nil, false
+		}
+		r = append(r, set)
+	}
+	return r, true
+}
+
+// parseComparatorSet は "||" で区切られた範囲1つを比較の並びに変換する。
+func parseComparatorSet(s string) ([]comparator, bool) {
+	fields := strings.Fields(s)
+	// ハイフン範囲 "1.2.3 - 2.3.4"
+	if len(fields) == 3 && fields[1] == "-" {
+		from, ok1 := parsePartialVersion(fields[0])
+		to, ok2 := parsePartialVersion(fields[2])
+		if !ok1 || !ok2 {
+			return nil, false
+		}
+		set := []comparator{{op: ">=", v: from.version}}
+		switch {
+		case to.parts == 3:
+			set = append(set, comparator{op: "<=", v: to.version})
+		case to.parts > 0:
+			set = append(set, comparator{op: "<", v: to.next()})
+		}
+		return set, true
+	}
+
+	// 演算子と数字の間に空白がある書き方（">= 1.2.3"）をまとめる
+	var tokens []string
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if strings.Trim(f, "<>=^~") == "" && i+1 < len(fields) {
+			f += fields[i+1]
+			i++
+		}
+		tokens = append(tokens, f)
+	}
+	if len(tokens) == 0 {
+		// 空の範囲は "*" と同じ
+		return []comparator{}, true
+	}
+
+	var set []comparator
+	for _, t := range tokens {
+		cs, ok := desugarComparator(t)
+		if !ok {
+			return nil, false
+		}
+		set = append(set, cs...)
+	}
+	return set, true
+}
+
+// desugarComparator は演算子付きの省略形（"^1.2" "~1" ">1.x" など）を基本の比較に展開する。
+func desugarComparator(t string) ([]comparator, bool) {
+	op := t[:len(t)-len(strings.TrimLeft(t, "<>=^~"))]
+	p, ok := parsePartialVersion(t[len(op):])
+	if !ok {
+		return nil, false
+	}
+	v := p.version
+
+	switch op {
+	case "^":
+		if p.parts == 0 {
+			return []comparator{}, true
+		}
+		// 左端の 0 でない部分（省略形なら指定のある最後の部分）を上げたものが上限
+		var upper semver
+		switch {
+		case v.major > 0 || p.parts == 1:
+			upper = semver{major: v.major + 1}
+		case v.minor > 0 || p.parts == 2:
+			upper = semver{minor: v.minor + 1}
+		default:
+			upper = semver{patch: v.patch + 1}
+		}
+		return []comparator{{op: ">=", v: v}, {op: "<", v: upper}}, true
+	case "~", "~>":
+		if p.parts == 0 {
+			return []comparator{}, true
+		}
+		upper := semver{major: v.major, minor: v.minor + 1}
+		if p.parts == 1 {
+			upper = semver{major: v.major + 1}
+		}
+		return []comparator{{op: ">=", v: v}, {op: "<", v: upper}}, true
+	case "", "=":
+		switch p.parts {
+		case 0:
+			return []comparator{}, true
+		case 3:
+			return []comparator{{op: "=", v: v}}, true
+		default:
+			return []comparator{{op: ">=", v: v}, {op: "<", v: p.next()}}, true
+		}
+	case ">=":
+		return []comparator{{op: ">=", v: v}}, true
+	case "<":
+		if p.parts == 0 {
+			// "<*" は何も満たさない
+			return []comparator{{op: "<", v: semver{}}}, true
+		}
+		return []comparator{{op: "<", v: v}}, true
+	case ">":
+		switch p.parts {
+		case 0:
+			return []comparator{{op: "<", v: semver{}}}, true
+		case 3:
+			return []comparator{{op: ">", v: v}}, true
+		default:
+			return []comparator{{op: ">=", v: p.next()}}, true
+		}
+	case "<=":
+		switch p.parts {
+		case 0:
+			return []comparator{}, true
+		case 3:
+			return []comparator{{op: "<=", v: v}}, true
+		default:
+			return []comparator{{op: "<", v: p.next()}}, true
+		}
+	default:
+		return nil, false
+	}
+}
+
+// satisfies は v が範囲を満たすかを返す。
+//
+// npm と同じく、プレリリース版は同じ major.minor.patch のプレリリースを比較に含む範囲
+// （"^1.2.3-beta.1" など）でしか満たさない。"^1.2.3" で 1.3.0-beta が選ばれることはない。
+func (r npmRange) satisfies(v semver) bool {
+	for _, set := range r {
+		if setSatisfies(set, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// setSatisfies は v が比較の並びをすべて満たすかを返す（satisfies 参照）。
+func setSatisfies(set []comparator, v semver) bool {
+	for _, c := range set {
+		if !c.matches(v) {
+			return false
+		}
+	}
+	if !v.isPrerelease() {
+		return true
+	}
+	for _, c := range set {
+		if c.v.isPrerelease() && c.v.sameCore(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// maxSatisfying は versions のうち範囲を満たす最も新しいバージョンを返す（なければ false）。
+// バージョンとして解釈できない要素（npm の time にある "created" / "modified" など）は無視する。
+func (r npmRange) maxSatisfying(versions []string) (string, bool) {
+	var best string
+	var bestV semver
+	found := false
+	for _, s := range versions {
+		v, ok := parseSemver(s)
+		if !ok || !r.satisfies(v) {
+			continue
+		}
+		// 同じ優先順位（ビルドメタデータ違い）は文字列の小さいほうにそろえて順序によらず同じ結果にする
+		if d := v.compare(bestV); !found || d > 0 || (d == 0 && s < best) {
+			best, bestV, found = s, v, true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,117 @@
+package github
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSemverCompare(t *testing.T) {
+	// SemVer 2.0.0 の仕様にある優先順位の例（昇順）
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.10", "1.2.0", "2.0.0",
+	}
+	for i := 0; i+1 < len(ordered); i++ {
+		a, okA := parseSemver(ordered[i])
+		b, okB := parseSemver(ordered[i+1])
+		if !okA || !okB {
+			t.Fatalf("parseSemver(%q / %q) failed", ordered[i], ordered[i+1])
+		}
+		if a.compare(b) >= 0 || b.compare(a) <= 0 {
+			t.Errorf("%s should be lower than %s", ordered[i], ordered[i+1])
+		}
+	}
+	a, _ := parseSemver("v1.2.3+build.5")
+	b, _ := parseSemver("1.2.3")
+	if a.compare(b) != 0 {
+		t.Error("build metadata should not affect precedence")
+	}
+	for _, s := range []string{"1.2", "latest", "created", "1.2.3.4", "1.2.x"} {
+		if _, ok := parseSemver(s); ok {
+			t.Errorf("parseSemver(%q) ok = true, want false", s)
+		}
+	}
+}
+
+// reactVersions は npm の time マップのキー（"created" / "modified" とプレリリースを含む）。
+var reactVersions = []string{
+	"created", "modified",
+	"0.14.0", "0.14.8", "15.0.0", "16.0.0", "16.14.0",
+	"17.0.0-rc.3", "17.0.0", "17.0.2",
+	"18.0.0-alpha-f6abf4b40-20211020", "18.0.0-rc.0", "18.0.0", "18.2.0", "18.3.0", "18.3.1",
+	"19.0.0-beta-26f2496093-20240514", "19.0.0-rc.1", "19.0.0", "19.1.0",
+	"0.0.0-experimental-2b036d3f1-20240327",
+}
+
+func TestNpmRange_MaxSatisfying(t *testing.T) {
+	tests := []struct {
+		constraint string
+		versions   []string
+		want       string // 空なら満たす版がない
+	}{
+		{"18.2.0", reactVersions, "18.2.0"},
+		{"=18.2.0", reactVersions, "18.2.0"},
+		{"v18.2.0", reactVersions, "18.2.0"},
+		{"^18.2.0", reactVersions, "18.3.1"},
+		{"^18", reactVersions, "18.3.1"},
+		{"~18.2.0", reactVersions, "18.2.0"},
+		{"~18", reactVersions, "18.3.1"},
+		{"18.x", reactVersions, "18.3.1"},
+		{"18", reactVersions, "18.3.1"},
+		{"17.0", reactVersions, "17.0.2"},
+		{"*", reactVersions, "19.1.0"},
+		{"", reactVersions, "19.1.0"},
+		{">=16.0.0 <18.0.0", reactVersions, "17.0.2"},
+		{">= 16 < 18", reactVersions, "17.0.2"},
+		{">17", reactVersions, "19.1.0"},
+		{"<=17", reactVersions, "17.0.2"},
+		{"<17.0.0", reactVersions, "16.14.0"},
+		{"16.0.0 - 17", reactVersions, "17.0.2"},
+		{"16.0.0 - 17.0.0", reactVersions, "17.0.0"},
+		{"^16.0.0 || ^17.0.0", reactVersions, "17.0.2"},
+		{"^0.14.0", reactVersions, "0.14.8"},
+		// 同じ major.minor.patch のプレリリースを指定したときだけプレリリースを選ぶ
+		{"^18.0.0-rc.0", reactVersions, "18.3.1"},
+		{"~19.0.0-rc.1", reactVersions, "19.0.0"},
+		{"19.0.0-rc.1", reactVersions, "19.0.0-rc.1"},
+		{">=19.0.0-beta-26f2496093-20240514 <19.0.0", reactVersions, "19.0.0-rc.1"},
+		{">=18.3.2 <19.0.0", reactVersions, ""},
+		// ^0.x はマイナー・パッチまでを固定する
+		{"^0.2.3", []string{"0.2.3", "0.2.9", "0.3.0"}, "0.2.9"},
+		{"^0.0.3", []string{"0.0.3", "0.0.4"}, "0.0.3"},
+		{"^0.0", []string{"0.0.1", "0.0.9", "0.1.0"}, "0.0.9"},
+		{"~>1.2", []string{"1.2.0", "1.2.7", "1.3.0"}, "1.2.7"},
+		// 前方一致の "1.0" / "1.0.1" で取り違えていた 1.0.0-beta・1.0.10・1.0.1-beta を正しく扱う
+		{"1.0", []string{"1.0.0-beta", "1.0.0", "1.0.10", "1.1.0"}, "1.0.10"},
+		{"1.0.1", []string{"1.0.1-beta", "1.0.10", "1.0.1"}, "1.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			r, ok := parseNpmRange(tt.constraint)
+			if !ok {
+				t.Fatalf("parseNpmRange(%q) ok = false", tt.constraint)
+			}
+			// 入力の順によらず同じ結果になる
+			reversed := slices.Clone(tt.versions)
+			slices.Reverse(reversed)
+			for _, versions := range [][]string{tt.versions, reversed} {
+				got, found := r.maxSatisfying(versions)
+				if got != tt.want || found != (tt.want != "") {
+					t.Errorf("maxSatisfying() = %q, %v, want %q", got, found, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseNpmRange_Unsupported(t *testing.T) {
+	for _, s := range []string{
+		"latest", "next", "file:../shared", "workspace:*", "npm:react@^18",
+		"git+https://github.com/o/r.git", "github:o/r", "o/r#main",
+		"https://example.com/pkg.tgz", "^1.2.3.4", ">=abc",
+	} {
+		if _, ok := parseNpmRange(s); ok {
+			t.Errorf("parseNpmRange(%q) ok = true, want false", s)
+		}
+	}
+}
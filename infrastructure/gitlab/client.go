@@ -0,0 +1,1024 @@
+// Package gitlab は GitLab REST API（v4）クライアントを提供する。
+//
+// このパッケージは infrastructure 層に属し、infrastructure/github と同じく
+// features/analyze の Repository インターフェースを実装する。
+// マージリクエストはPR、MR のノート（承認・差し戻しのシステムノートと差分へのコメント）はレビュー、
+// デプロイ（Deployments API）はワークフロー実行に読み替えるため、分析・レポートはそのまま使える。
+//
+// GitHub クライアントと比べて、以下は取得しない:
+//   - ファイルサイズ（ツリー API に含まれないため、巨大ファイルのリスクは出ない）
+//   - 依存（依存ファイルの解析とパッケージレジストリへの問い合わせは GitHub クライアントにしかない）
+//   - force push（GitLab の API では通常の push と区別できない）
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/features/analyze"
+	"github.com/ryuka-games/lokup/infrastructure/internal/httpapi"
+)
+
+// DefaultBaseURL は GitLab.com の API のベース URL。
+const DefaultBaseURL = "https://gitlab.com/api/v4"
+
+// DefaultConcurrency は同時に送る HTTP リクエスト数のデフォルト。
+const DefaultConcurrency = 5
+
+// デフォルトのリトライ設定
+const (
+	defaultMaxRetries     = 3
+	defaultRetryBaseDelay = 1 * time.Second
+	// レート制限の解除をこの時間まで待つ（それ以上ならエラーにする）
+	defaultRateLimitMaxWait = 60 * time.Second
+	// 一覧取得で辿るページ数の上限（per_page=100 なので 1000 件）
+	defaultMaxPages = 10
+	// ファイルツリーで辿るページ数の上限（ツリーはファイル数がそのまま件数になるため多めにとる）
+	defaultMaxTreePages = 100
+)
+
+// ErrRateLimited は GitLab API のレート制限に達したことを示す。
+// 解除まで待てない場合に doRequest が返す（errors.Is で判定できる）。
+var ErrRateLimited = errors.New("GitLab API rate limit exceeded")
+
+// Client は GitLab API クライアント。
+//
+// 複数の goroutine から同時に使ってよい。設定は NewClient の時点で固定される。
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+
+	// 一時的なエラー（502/503/504・タイムアウト）時のリトライ回数
+	maxRetries int
+	// リトライ間隔の初期値（指数バックオフで倍々に伸ばす）
+	retryBaseDelay time.Duration
+	// レート制限の解除を待つ上限
+	rateLimitMaxWait time.Duration
+	// 一覧取得で辿るページ数の上限（巨大リポジトリでの際限ない取得を防ぐ）
+	maxPages int
+	// 同時に送る HTTP リクエスト数の上限（全エンドポイント共通）
+	limiter httpapi.Limiter
+	// リクエスト・ページ送りなどの経過を出すロガー
+	logger *slog.Logger
+	// 現在時刻を返す関数（レート制限の待ち時間に使う）
+	clock func() time.Time
+}
+
+// ClientOption は Client の設定を変更する。
+type ClientOption func(*Client)
+
+// WithBaseURL は GitLab API のベース URL を設定する。
+// セルフマネージドの GitLab（https://gitlab.example.com/api/v4 など）やテスト用のサーバーを指すときに使う。
+func WithBaseURL(u string) ClientOption {
+	return func(c *Client) {
+		if u != "" {
+			c.baseURL = strings.TrimRight(u, "/")
+		}
+	}
+}
+
+// WithMaxRetries は一時的なエラー時のリトライ回数を設定する（0 でリトライしない）。
+func WithMaxRetries(n int) ClientOption {
+	return func(c *Client) {
+		if n >= 0 {
+			c.maxRetries = n
+		}
+	}
+}
+
+// WithRateLimitMaxWait はレート制限の解除を待つ上限を設定する（0 で待たない）。
+func WithRateLimitMaxWait(d time.Duration) ClientOption {
+	return func(c *Client) {
+		if d >= 0 {
+			c.rateLimitMaxWait = d
+		}
+	}
+}
+
+// WithMaxPages は一覧取得で辿るページ数の上限を設定する。
+func WithMaxPages(n int) ClientOption {
+	return func(c *Client) {
+		if n > 0 {
+			c.maxPages = n
+		}
+	}
+}
+
+// WithConcurrency は同時に送る HTTP リクエスト数の上限を設定する。
+func WithConcurrency(n int) ClientOption {
+	return func(c *Client) {
+		if n > 0 {
+			c.limiter = httpapi.NewLimiter(n)
+		}
+	}
+}
+
+// WithLogger はロガーを設定する。
+// リクエストごとの所要時間やページ送りは Debug レベルで出す。
+func WithLogger(l *slog.Logger) ClientOption {
+	return func(c *Client) {
+		if l != nil {
+			c.logger = l
+		}
+	}
+}
+
+// WithClock は現在時刻の取得元を設定する。
+// テストやレポートの再現のために時刻を固定したい場合に使う。所要時間の計測には使わない。
+func WithClock(now func() time.Time) ClientOption {
+	return func(c *Client) {
+		if now != nil {
+			c.clock = now
+		}
+	}
+}
+
+// NewClient は Client を生成する。token はパーソナル・プロジェクト・グループのアクセストークン（空なら認証なし）。
+func NewClient(token string, opts ...ClientOption) *Client {
+	c := &Client{
+		baseURL:          DefaultBaseURL,
+		token:            token,
+		httpClient:       &http.Client{Timeout: 30 * time.Second},
+		maxRetries:       defaultMaxRetries,
+		retryBaseDelay:   defaultRetryBaseDelay,
+		rateLimitMaxWait: defaultRateLimitMaxWait,
+		maxPages:         defaultMaxPages,
+		limiter:          httpapi.NewLimiter(DefaultConcurrency),
+		logger:           slog.Default(),
+		clock:            time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// now は現在時刻を返す。
+func (c *Client) now() time.Time {
+	if c.clock != nil {
+		return c.clock()
+	}
+	return time.Now()
+}
+
+// projectURL はプロジェクト配下のエンドポイントの URL を返す。
+// GitLab はプロジェクトを "group/project" を URL エンコードした ID で指す。
+func (c *Client) projectURL(repo domain.Repository, format string, args ...any) string {
+	return c.baseURL + "/projects/" + url.PathEscape(repo.FullName()) + fmt.Sprintf(format, args...)
+}
+
+// doRequest は GET リクエストを送る。
+//
+// 一時的なエラー（502/503/504・タイムアウト）は指数バックオフでリトライする。
+// レート制限（429）に達した場合、解除が rateLimitMaxWait 以内なら待って1度だけ再送し、
+// それ以上かかるなら ErrRateLimited を返す。
+func (c *Client) doRequest(ctx context.Context, endpoint string) (*http.Response, error) {
+	for waited := false; ; waited = true {
+		resp, err := c.sendWithRetry(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusTooManyRequests {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		wait := retryAfter(resp, c.now())
+		if waited || wait > c.rateLimitMaxWait {
+			return nil, fmt.Errorf("%w (retry after %s)", ErrRateLimited, wait.Round(time.Second))
+		}
+		c.logger.Warn("GitLab API rate limited, waiting until reset", "wait", wait.Round(time.Second))
+		if err := httpapi.SleepContext(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sendWithRetry はリクエストを send で送り、一時的なエラーなら指数バックオフでリトライする（httpapi.Retry 参照）。
+func (c *Client) sendWithRetry(ctx context.Context, endpoint string) (*http.Response, error) {
+	return httpapi.Retry(ctx, c.maxRetries, c.retryBaseDelay, func() (*http.Response, error) {
+		return c.send(ctx, endpoint)
+	})
+}
+
+// send は GET リクエストを1回だけ送る。同時実行数の枠を確保してから送り、結果をログに出す（httpapi.Do 参照）。
+func (c *Client) send(ctx context.Context, endpoint string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "lokup")
+	if c.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	}
+
+	return httpapi.Do(c.httpClient, c.limiter, c.logger, req)
+}
+
+// getJSON は endpoint を取得して dest にデコードする。200 以外はエラーにする。
+func (c *Client) getJSON(ctx context.Context, endpoint, what string, dest any) error {
+	resp, err := c.doRequest(ctx, endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return apiError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(dest); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", what, err)
+	}
+	return nil
+}
+
+// fetchAllPages は Link ヘッダの rel="next" を辿って全ページを取得する。
+// maxPages に達したら打ち切り、truncated = true を返す（httpapi.FetchAllPages 参照）。
+func fetchAllPages[T any](ctx context.Context, c *Client, endpoint, what string, maxPages int) (all []T, truncated bool, err error) {
+	return httpapi.FetchAllPages[T](ctx, c.pager(), endpoint, what, maxPages)
+}
+
+// fetchPage は1ページ分を取得し、次ページの URL（なければ空）を返す。
+func fetchPage[T any](ctx context.Context, c *Client, endpoint, what string) ([]T, string, error) {
+	return httpapi.FetchPage[T](ctx, c.pager(), endpoint, what)
+}
+
+// pager は GitLab API の一覧を doRequest（リトライ・レート制限の待ちを含む）で取得する設定を返す。
+// 200 以外は GitLab が返したメッセージ付きの statusError にする。
+func (c *Client) pager() httpapi.Pager {
+	return httpapi.Pager{Get: c.doRequest, StatusError: apiError, Logger: c.logger}
+}
+
+// statusError は GitLab API が 200 以外を返したことを表す。
+type statusError struct {
+	StatusCode int
+	Status     string
+	Message    string // GitLab が返したエラーメッセージ（なければ空）
+}
+
+func (e *statusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("GitLab API error: %s: %s", e.Status, e.Message)
+	}
+	return fmt.Sprintf("GitLab API error: %s", e.Status)
+}
+
+// hasStatus は err が codes のいずれかのステータスによる statusError かを返す。
+func hasStatus(err error, codes ...int) bool {
+	var se *statusError
+	return errors.As(err, &se) && slices.Contains(codes, se.StatusCode)
+}
+
+// apiError は 200 以外の応答を、GitLab が返したメッセージ付きの statusError にする。
+func apiError(resp *http.Response) error {
+	se := &statusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	var body struct {
+		Message json.RawMessage `json:"message"`
+		Error   string          `json:"error"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err := json.Unmarshal(data, &body); err == nil {
+		se.Message = body.Error
+		if len(body.Message) > 0 {
+			var s string
+			if json.Unmarshal(body.Message, &s) == nil {
+				se.Message = s
+			} else {
+				se.Message = string(body.Message) // 検証エラーはフィールドごとのオブジェクトで返る
+			}
+		}
+	}
+	return se
+}
+
+// retryAfter はレート制限の応答から待つべき時間を返す。
+//
+// GitLab は Retry-After（秒）と RateLimit-Reset（解除時刻の UNIX 秒）を返す。
+// どちらもなければ待てないので、rateLimitMaxWait を超える長さにして即エラーにさせる。
+func retryAfter(resp *http.Response, now time.Time) time.Duration {
+	if sec, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Duration(sec) * time.Second
+	}
+	if sec, err := strconv.ParseInt(resp.Header.Get("RateLimit-Reset"), 10, 64); err == nil {
+		return max(time.Unix(sec, 0).Sub(now), 0)
+	}
+	return time.Hour
+}
+
+// commitsURL はコミット一覧のエンドポイントに path・branch の絞り込みを付けた URL を返す。
+func (c *Client) commitsURL(repo domain.Repository, query, path, branch string) string {
+	endpoint := c.projectURL(repo, "/repository/commits?%s", query)
+	// path を渡すと、そのパス配下を変更したコミットだけを API 側で絞り込める
+	if path != "" {
+		endpoint += "&path=" + url.QueryEscape(path)
+	}
+	// ref_name にブランチ名を渡すと、デフォルトブランチの代わりにそのブランチの履歴を辿る
+	if branch != "" {
+		endpoint += "&ref_name=" + url.QueryEscape(branch)
+	}
+	return endpoint
+}
+
+// GetCommits は指定期間のコミット履歴を取得する。
+func (c *Client) GetCommits(ctx context.Context, repo domain.Repository, period domain.DateRange, path, branch string) ([]analyze.Commit, error) {
+	query := fmt.Sprintf("since=%s&until=%s&per_page=100",
+		url.QueryEscape(period.From.Format(time.RFC3339)),
+		url.QueryEscape(period.To.Format(time.RFC3339)),
+	)
+
+	apiCommits, _, err := fetchAllPages[apiCommit](ctx, c, c.commitsURL(repo, query, path, branch), "commits", c.maxPages)
+	if err != nil {
+		return nil, err
+	}
+
+	// 変更ファイルは一覧APIに含まれないため、
+	// 必要なコミットのみ GetCommitDetail で個別取得する（fillCommitFiles参照）
+	commits := make([]analyze.Commit, len(apiCommits))
+	for i, ac := range apiCommits {
+		commits[i] = ac.toCommit()
+	}
+	return commits, nil
+}
+
+// GetLastCommit は before 以前で最新のコミットを1件だけ取得する。
+func (c *Client) GetLastCommit(ctx context.Context, repo domain.Repository, before time.Time, path, branch string) (*analyze.Commit, error) {
+	query := fmt.Sprintf("until=%s&per_page=1", url.QueryEscape(before.Format(time.RFC3339)))
+
+	var apiCommits []apiCommit
+	if err := c.getJSON(ctx, c.commitsURL(repo, query, path, branch), "last commit", &apiCommits); err != nil {
+		return nil, err
+	}
+	if len(apiCommits) == 0 {
+		return nil, nil
+	}
+	commit := apiCommits[0].toCommit()
+	return &commit, nil
+}
+
+// BranchExists はブランチが存在するかを返す。
+func (c *Client) BranchExists(ctx context.Context, repo domain.Repository, branch string) (bool, error) {
+	resp, err := c.doRequest(ctx, c.projectURL(repo, "/repository/branches/%s", url.PathEscape(branch)))
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch branch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, apiError(resp)
+	}
+}
+
+// GetCommitDetail はコミットの詳細（変更ファイル・行数含む）を取得する。
+// コミット API は行数（stats）だけを返すため、変更ファイルは diff API から取得する。
+func (c *Client) GetCommitDetail(ctx context.Context, repo domain.Repository, sha string) (*analyze.Commit, error) {
+	var ac apiCommit
+	if err := c.getJSON(ctx, c.projectURL(repo, "/repository/commits/%s?stats=true", url.PathEscape(sha)), "commit detail", &ac); err != nil {
+		return nil, err
+	}
+
+	diffs, _, err := fetchAllPages[apiDiff](ctx, c, c.projectURL(repo, "/repository/commits/%s/diff?per_page=100", url.PathEscape(sha)), "commit diff", c.maxPages)
+	if err != nil {
+		return nil, err
+	}
+
+	commit := ac.toCommit()
+	commit.Files = make([]string, len(diffs))
+	for i, d := range diffs {
+		commit.Files[i] = d.NewPath
+	}
+	commit.Additions = ac.Stats.Additions
+	commit.Deletions = ac.Stats.Deletions
+	return &commit, nil
+}
+
+// GetContributors はコントリビューター一覧を取得する。
+//
+// GitLab のコントリビューターはメールアドレスごとに集計されるため、同じ名前の行は1人にまとめる
+// （アカウントとの対応は返らないため、Login にはコミットの作成者名が入る）。
+func (c *Client) GetContributors(ctx context.Context, repo domain.Repository) ([]analyze.Contributor, error) {
+	apiContributors, _, err := fetchAllPages[apiContributor](ctx, c, c.projectURL(repo, "/repository/contributors?per_page=100"), "contributors", c.maxPages)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]int) // 名前 → contributors の添字
+	var contributors []analyze.Contributor
+	for _, ac := range apiContributors {
+		if i, ok := index[ac.Name]; ok {
+			contributors[i].Contributions += ac.Commits
+			continue
+		}
+		index[ac.Name] = len(contributors)
+		contributors = append(contributors, analyze.Contributor{Login: ac.Name, Contributions: ac.Commits})
+	}
+	sort.SliceStable(contributors, func(i, j int) bool {
+		return contributors[i].Contributions > contributors[j].Contributions
+	})
+	return contributors, nil
+}
+
+// GetFileContent はデフォルトブランチにあるファイルの内容を取得する。
+func (c *Client) GetFileContent(ctx context.Context, repo domain.Repository, path string) ([]byte, error) {
+	resp, err := c.doRequest(ctx, c.projectURL(repo, "/repository/files/%s/raw?ref=HEAD", url.PathEscape(path)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, apiError(resp)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// GetPullRequests はマージリクエスト一覧をPRとして取得する。
+//
+// state は GitHub の PR と同じ "open" / "closed" / "all"。GitLab の merged と closed は
+// どちらも "closed" として返す（マージされたものは MergedAt が入る）。作成日の新しい順に並ぶ。
+func (c *Client) GetPullRequests(ctx context.Context, repo domain.Repository, state string) ([]analyze.PullRequest, error) {
+	// closed は merged と closed を別々に API 側で絞り込んで取得し、1つの一覧にまとめる。
+	// all から opened を除くと、オープンの MR がページ数の上限を使い切ってしまうため
+	var apiStates []string
+	switch state {
+	case "open":
+		apiStates = []string{"opened"}
+	case "closed":
+		apiStates = []string{"merged", "closed"}
+	default:
+		apiStates = []string{"all"}
+	}
+
+	var apiMRs []apiMergeRequest
+	for _, apiState := range apiStates {
+		endpoint := c.projectURL(repo, "/merge_requests?state=%s&order_by=created_at&sort=desc&per_page=100", apiState)
+		items, _, err := fetchAllPages[apiMergeRequest](ctx, c, endpoint, "merge requests", c.maxPages)
+		if err != nil {
+			return nil, err
+		}
+		apiMRs = append(apiMRs, items...)
+	}
+	if len(apiStates) > 1 {
+		sort.SliceStable(apiMRs, func(i, j int) bool { return apiMRs[i].CreatedAt.After(apiMRs[j].CreatedAt) })
+	}
+
+	var prs []analyze.PullRequest
+	for _, am := range apiMRs {
+		// Note: additions/deletions は一覧APIに含まれないため、
+		// 必要なPRのみ GetPRDetail で個別取得する（buildPRDetails参照）
+		prs = append(prs, am.toPullRequest())
+	}
+	return prs, nil
+}
+
+// GetPRDetail はマージリクエストの詳細（additions/deletions含む）を取得する。
+// GitLab は行数を返さないため、MR の差分（diffs API、GitLab 15.7 以降）から追加・削除行を数える。
+// 大きすぎて GitLab が省略した差分の行は数えられない。
+func (c *Client) GetPRDetail(ctx context.Context, repo domain.Repository, prNumber int) (*analyze.PullRequest, error) {
+	var am apiMergeRequest
+	if err := c.getJSON(ctx, c.projectURL(repo, "/merge_requests/%d", prNumber), "merge request detail", &am); err != nil {
+		return nil, err
+	}
+
+	diffs, _, err := fetchAllPages[apiDiff](ctx, c, c.projectURL(repo, "/merge_requests/%d/diffs?per_page=100", prNumber), "merge request diffs", c.maxPages)
+	if err != nil {
+		return nil, err
+	}
+
+	pr := am.toPullRequest()
+	for _, d := range diffs {
+		additions, deletions := countDiffLines(d.Diff)
+		pr.Additions += additions
+		pr.Deletions += deletions
+	}
+	return &pr, nil
+}
+
+// countDiffLines は unified diff の本文（ハンク）から追加行と削除行を数える。
+// GitLab の diff はファイルヘッダ（--- / +++）を含まず "@@" から始まる。
+func countDiffLines(diff string) (additions, deletions int) {
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			additions++
+		case strings.HasPrefix(line, "-"):
+			deletions++
+		}
+	}
+	return additions, deletions
+}
+
+// GetFiles はリポジトリ内のファイル一覧を取得する。
+//
+// ツリー API を recursive・キーセット方式のページングで辿る。ツリー API はサイズを返さないため Size は 0。
+// ページ数の上限（10,000 ファイル）に達したら、取れた分と truncated = true を返す。
+func (c *Client) GetFiles(ctx context.Context, repo domain.Repository, branch string) ([]analyze.File, bool, error) {
+	endpoint := c.projectURL(repo, "/repository/tree?recursive=true&pagination=keyset&per_page=100")
+	if branch != "" {
+		endpoint += "&ref=" + url.QueryEscape(branch)
+	}
+
+	items, truncated, err := fetchAllPages[apiTreeItem](ctx, c, endpoint, "tree", defaultMaxTreePages)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// ディレクトリ（tree）とサブモジュール（commit）は含めない
+	var files []analyze.File
+	for _, item := range items {
+		if item.Type == "blob" {
+			files = append(files, analyze.File{Path: item.Path})
+		}
+	}
+	return files, truncated, nil
+}
+
+// GetDependencies は依存情報を取得する。
+// 依存ファイルの解析とパッケージレジストリへの問い合わせは GitHub クライアントにしかないため、常に空を返す。
+func (c *Client) GetDependencies(ctx context.Context, repo domain.Repository) ([]analyze.Dependency, error) {
+	c.logger.Debug("dependencies are not analyzed for GitLab projects", "repo", repo.FullName())
+	return nil, nil
+}
+
+// GetIssues はIssue一覧を取得する。
+// state は GitHub と同じ "open" / "closed" / "all" で、返す Issue の State も "open" / "closed" にそろえる。
+// since は更新日時で絞り込む（GitHub の since と同じ）。
+func (c *Client) GetIssues(ctx context.Context, repo domain.Repository, state string, since *time.Time) ([]analyze.Issue, error) {
+	apiState := state
+	if state == "open" {
+		apiState = "opened"
+	}
+	endpoint := c.projectURL(repo, "/issues?state=%s&per_page=100", apiState)
+	if since != nil {
+		endpoint += "&updated_after=" + url.QueryEscape(since.Format(time.RFC3339))
+	}
+
+	apiIssues, _, err := fetchAllPages[apiIssue](ctx, c, endpoint, "issues", c.maxPages)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := make([]analyze.Issue, len(apiIssues))
+	for i, ai := range apiIssues {
+		issueState := ai.State
+		if issueState == "opened" {
+			issueState = "open"
+		}
+		issues[i] = analyze.Issue{
+			Number:    ai.IID,
+			Title:     ai.Title,
+			State:     issueState,
+			Labels:    ai.Labels,
+			CreatedAt: ai.CreatedAt,
+			ClosedAt:  ai.ClosedAt,
+		}
+	}
+	return issues, nil
+}
+
+// assignedNotePrefix は担当者をアサインしたときにGitLabが残すシステムノートの本文の先頭。
+const assignedNotePrefix = "assigned to "
+
+// GetIssueEvents は Issue のイベント（ラベル付け・アサイン）を古い順に取得する。
+//
+// ラベルの付け外しはラベルイベント API から、アサインはシステムノート（"assigned to @user"）から読み取る。
+// 復旧作業の開始（最初のトリアージ）を求めるのが目的のため、それぞれ古い順の先頭ページ（100件）だけを取得する。
+func (c *Client) GetIssueEvents(ctx context.Context, repo domain.Repository, issueNumber int) ([]analyze.IssueEvent, error) {
+	labelEvents, _, err := fetchPage[apiLabelEvent](ctx, c, c.projectURL(repo, "/issues/%d/resource_label_events?per_page=100", issueNumber), "issue label events")
+	if err != nil {
+		return nil, err
+	}
+	notes, err := c.getNotes(ctx, repo, "issues", issueNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []analyze.IssueEvent
+	for _, le := range labelEvents {
+		e := analyze.IssueEvent{Event: "labeled", CreatedAt: le.CreatedAt}
+		if le.Action == "remove" {
+			e.Event = "unlabeled"
+		}
+		if le.Label != nil {
+			e.Label = le.Label.Name
+		}
+		events = append(events, e)
+	}
+	for _, n := range notes {
+		if n.System && strings.HasPrefix(n.Body, assignedNotePrefix) {
+			events = append(events, analyze.IssueEvent{Event: "assigned", CreatedAt: n.CreatedAt})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].CreatedAt.Before(events[j].CreatedAt)
+	})
+	return events, nil
+}
+
+// MR のレビューとみなすシステムノートの本文
+const (
+	approvedNote         = "approved this merge request"
+	requestedChangesNote = "requested changes"
+)
+
+// GetPRReviews はマージリクエストのレビューを取得する。
+//
+// 承認 API は承認者だけを返して日時を持たないため、MR のノートから読み取る:
+// 承認のシステムノートを APPROVED、差し戻し（requested changes）を CHANGES_REQUESTED、
+// 差分へのコメント（DiffNote）を COMMENTED とする。古い順の先頭ページ（100件）だけを取得する。
+func (c *Client) GetPRReviews(ctx context.Context, repo domain.Repository, prNumber int) ([]analyze.Review, error) {
+	notes, err := c.getNotes(ctx, repo, "merge_requests", prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	var reviews []analyze.Review
+	for _, n := range notes {
+		var state string
+		switch {
+		case n.System && n.Body == approvedNote:
+			state = "APPROVED"
+		case n.System && strings.HasPrefix(n.Body, requestedChangesNote):
+			state = "CHANGES_REQUESTED"
+		case !n.System && n.Type == "DiffNote":
+			state = "COMMENTED"
+		default:
+			continue
+		}
+		reviews = append(reviews, analyze.Review{
+			ID:          n.ID,
+			Author:      n.Author.Username,
+			State:       state,
+			SubmittedAt: n.CreatedAt,
+		})
+	}
+	return reviews, nil
+}
+
+// GetPRComments はマージリクエストの会話欄のコメントを取得する。
+// システムノートと差分へのコメント（レビューとして GetPRReviews で取得する）は含まない。
+// 最初の反応を求めるのが目的のため、古い順の先頭ページ（100件）だけを取得する。
+func (c *Client) GetPRComments(ctx context.Context, repo domain.Repository, prNumber int) ([]analyze.Comment, error) {
+	notes, err := c.getNotes(ctx, repo, "merge_requests", prNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []analyze.Comment
+	for _, n := range notes {
+		if n.System || n.Type == "DiffNote" {
+			continue
+		}
+		comments = append(comments, analyze.Comment{
+			ID:        n.ID,
+			Author:    n.Author.Username,
+			Bot:       isBotUsername(n.Author.Username),
+			CreatedAt: n.CreatedAt,
+		})
+	}
+	return comments, nil
+}
+
+// getNotes は Issue か MR（kind は "issues" / "merge_requests"）のノートを古い順に先頭ページ分だけ取得する。
+func (c *Client) getNotes(ctx context.Context, repo domain.Repository, kind string, iid int) ([]apiNote, error) {
+	endpoint := c.projectURL(repo, "/%s/%d/notes?sort=asc&order_by=created_at&per_page=100", kind, iid)
+	notes, _, err := fetchPage[apiNote](ctx, c, endpoint, "notes")
+	return notes, err
+}
+
+// isBotUsername は Bot アカウントのユーザー名かを返す。
+// GitLab の API はノートの投稿者が Bot かを返さないため、プロジェクト・グループのアクセストークンの
+// Bot（project_123_bot_xxx）や "renovate-bot" のような名前から判断する。
+func isBotUsername(username string) bool {
+	u := strings.ToLower(username)
+	return strings.Contains(u, "_bot") || strings.HasSuffix(u, "-bot") || strings.HasSuffix(u, "[bot]")
+}
+
+// GetReleases はリリース一覧を取得する（先頭1ページ分、新しいリリースから並ぶ）。
+// 公開日時にはリリース日（released_at）を使う。
+func (c *Client) GetReleases(ctx context.Context, repo domain.Repository) ([]analyze.Release, error) {
+	apiReleases, _, err := fetchPage[apiRelease](ctx, c, c.projectURL(repo, "/releases?per_page=100"), "releases")
+	if err != nil {
+		return nil, err
+	}
+
+	releases := make([]analyze.Release, len(apiReleases))
+	for i, ar := range apiReleases {
+		releases[i] = analyze.Release{
+			TagName:     ar.TagName,
+			Name:        ar.Name,
+			PublishedAt: ar.ReleasedAt,
+		}
+	}
+	return releases, nil
+}
+
+// GetTags はタグ一覧を、タグが指すコミットの日時付きで取得する。
+// GitLab のタグ一覧にはコミットが含まれるため、GitHub と違ってタグごとの追加の取得はいらない。
+// 対象は一覧の先頭1ページ分（最大100件、新しいタグから並ぶ）に限る。
+func (c *Client) GetTags(ctx context.Context, repo domain.Repository) ([]analyze.Tag, error) {
+	apiTags, _, err := fetchPage[apiTag](ctx, c, c.projectURL(repo, "/repository/tags?per_page=100"), "tags")
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]analyze.Tag, len(apiTags))
+	for i, at := range apiTags {
+		tags[i] = analyze.Tag{
+			Name: at.Name,
+			SHA:  at.Commit.ID,
+			Date: at.Commit.AuthoredDate,
+		}
+	}
+	return tags, nil
+}
+
+// GetWorkflowRuns は指定期間に成功したデプロイのうち、環境名かデプロイしたジョブ名が workflow に一致するものを
+// ワークフロー実行として取得する（大文字小文字を区別しない）。
+// GitLab には GitHub Actions のワークフローに相当する単位がないため、Deployments API で代える。
+func (c *Client) GetWorkflowRuns(ctx context.Context, repo domain.Repository, workflow string, period domain.DateRange) ([]analyze.WorkflowRun, error) {
+	// updated_after は order_by=updated_at と組み合わせる必要がある。作成日時での絞り込みは取得後に行う
+	endpoint := c.projectURL(repo, "/deployments?status=success&order_by=updated_at&updated_after=%s&per_page=100",
+		url.QueryEscape(period.From.Format(time.RFC3339)))
+
+	deployments, _, err := fetchAllPages[apiDeployment](ctx, c, endpoint, "deployments", c.maxPages)
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []analyze.WorkflowRun
+	for _, d := range deployments {
+		if !matchDeployment(d, workflow) || d.CreatedAt.Before(period.From) || d.CreatedAt.After(period.To) {
+			continue
+		}
+		run := analyze.WorkflowRun{ID: d.ID, Name: d.Environment.Name, CreatedAt: d.CreatedAt}
+		if d.Deployable != nil {
+			run.Path = d.Deployable.Name
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// matchDeployment はデプロイの環境名かジョブ名が workflow に一致するかを返す。
+func matchDeployment(d apiDeployment, workflow string) bool {
+	if strings.EqualFold(d.Environment.Name, workflow) {
+		return true
+	}
+	return d.Deployable != nil && strings.EqualFold(d.Deployable.Name, workflow)
+}
+
+// GetBranchProtection はデフォルトブランチの保護設定を取得する。
+//
+// 保護ブランチはワイルドカード（release/*）でも指定できるため、一覧を取得して名前を照合する。
+// 必要な承認数は承認ルールの最大値、ステータスチェックの必須は「パイプラインが成功した場合のみマージを許可」で判断する。
+// 権限不足で保護ブランチを読めない場合（403 / 404）はエラーにせず nil を返す。
+// 承認ルールを読めない場合（Free プランや権限不足）は承認数を 0 とする。
+func (c *Client) GetBranchProtection(ctx context.Context, repo domain.Repository) (*domain.BranchProtection, error) {
+	var project apiProject
+	if err := c.getJSON(ctx, c.projectURL(repo, ""), "project", &project); err != nil {
+		return nil, err
+	}
+	if project.DefaultBranch == "" {
+		return nil, fmt.Errorf("project %s has no default branch", repo.FullName())
+	}
+
+	protected, _, err := fetchPage[apiProtectedBranch](ctx, c, c.projectURL(repo, "/protected_branches?per_page=100"), "protected branches")
+	if hasStatus(err, http.StatusForbidden, http.StatusNotFound) {
+		c.logger.Debug("protected branches unavailable", "repo", repo.FullName(), "error", err)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	bp := &domain.BranchProtection{Branch: project.DefaultBranch}
+	for _, p := range protected {
+		if matchBranchPattern(p.Name, project.DefaultBranch) {
+			bp.Protected = true
+			break
+		}
+	}
+	if !bp.Protected {
+		return bp, nil
+	}
+
+	bp.RequiredStatusChecks = project.OnlyAllowMergeIfPipelineSucceeds
+	rules, _, err := fetchPage[apiApprovalRule](ctx, c, c.projectURL(repo, "/approval_rules?per_page=100"), "approval rules")
+	if hasStatus(err, http.StatusForbidden, http.StatusNotFound) {
+		c.logger.Debug("approval rules unavailable", "repo", repo.FullName(), "error", err)
+		return bp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rules {
+		bp.RequiredApprovals = max(bp.RequiredApprovals, r.ApprovalsRequired)
+	}
+	return bp, nil
+}
+
+// matchBranchPattern は保護ブランチの名前（"*" をワイルドカードに使える）が branch に一致するかを返す。
+// GitLab の "*" は "/" を含む任意の文字列に一致する。
+func matchBranchPattern(pattern, branch string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == branch
+	}
+	if !strings.HasPrefix(branch, parts[0]) {
+		return false
+	}
+	rest := branch[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(rest, part)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(part):]
+	}
+	last := parts[len(parts)-1]
+	return len(rest) >= len(last) && strings.HasSuffix(rest, last)
+}
+
+// GetForcePushes は force push を取得する。
+// GitLab の API（プッシュイベント）では force push を通常の push と区別できないため、常に nil を返す。
+func (c *Client) GetForcePushes(ctx context.Context, repo domain.Repository, period domain.DateRange) ([]analyze.ForcePush, error) {
+	return nil, nil
+}
+
+// API レスポンスの型定義
+
+type apiCommit struct {
+	ID           string    `json:"id"`
+	ParentIDs    []string  `json:"parent_ids"` // 先頭が first parent
+	AuthorName   string    `json:"author_name"`
+	AuthorEmail  string    `json:"author_email"`
+	AuthoredDate time.Time `json:"authored_date"`
+	Message      string    `json:"message"`
+	// 以下はコミット詳細API（stats=true）のみ
+	Stats struct {
+		Additions int `json:"additions"`
+		Deletions int `json:"deletions"`
+	} `json:"stats"`
+}
+
+// toCommit は一覧・詳細に共通の項目を analyze.Commit に変換する。
+func (ac apiCommit) toCommit() analyze.Commit {
+	return analyze.Commit{
+		SHA:     ac.ID,
+		Author:  ac.AuthorName,
+		Email:   ac.AuthorEmail,
+		Date:    ac.AuthoredDate,
+		Message: ac.Message,
+		Parents: ac.ParentIDs,
+	}
+}
+
+// apiDiff はコミット・MR の差分1ファイル分。
+type apiDiff struct {
+	NewPath string `json:"new_path"`
+	Diff    string `json:"diff"`
+}
+
+type apiContributor struct {
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	Commits int    `json:"commits"`
+}
+
+type apiMergeRequest struct {
+	IID             int        `json:"iid"`
+	Title           string     `json:"title"`
+	State           string     `json:"state"` // "opened" / "closed" / "merged" / "locked"
+	CreatedAt       time.Time  `json:"created_at"`
+	MergedAt        *time.Time `json:"merged_at"`
+	Draft           bool       `json:"draft"`
+	SourceBranch    string     `json:"source_branch"`
+	SHA             string     `json:"sha"` // ソースブランチ先頭のコミット
+	MergeCommitSHA  string     `json:"merge_commit_sha"`
+	SquashCommitSHA string     `json:"squash_commit_sha"`
+	Author          struct {
+		Username string `json:"username"`
+	} `json:"author"`
+}
+
+// toPullRequest は MR を analyze.PullRequest に変換する（行数は含まない）。
+func (am apiMergeRequest) toPullRequest() analyze.PullRequest {
+	return analyze.PullRequest{
+		Number:     am.IID,
+		Title:      am.Title,
+		Author:     am.Author.Username,
+		HeadBranch: am.SourceBranch,
+		HeadSHA:    am.SHA,
+		MergeSHA:   am.mergeSHA(),
+		CreatedAt:  am.CreatedAt,
+		MergedAt:   am.MergedAt,
+		Draft:      am.Draft,
+	}
+}
+
+// mergeSHA はマージでターゲットブランチに入ったコミットのハッシュを返す（未マージなら空）。
+// マージコミットを作らない方式では、スカッシュしたコミットか、fast-forward されたソースブランチの先頭になる。
+func (am apiMergeRequest) mergeSHA() string {
+	switch {
+	case am.MergedAt == nil:
+		return ""
+	case am.MergeCommitSHA != "":
+		return am.MergeCommitSHA
+	case am.SquashCommitSHA != "":
+		return am.SquashCommitSHA
+	default:
+		return am.SHA
+	}
+}
+
+type apiTreeItem struct {
+	Path string `json:"path"`
+	Type string `json:"type"` // "blob" / "tree" / "commit"
+}
+
+type apiIssue struct {
+	IID       int        `json:"iid"`
+	Title     string     `json:"title"`
+	State     string     `json:"state"` // "opened" / "closed"
+	Labels    []string   `json:"labels"`
+	CreatedAt time.Time  `json:"created_at"`
+	ClosedAt  *time.Time `json:"closed_at"`
+}
+
+// apiLabelEvent はラベルイベント API のレスポンス。
+type apiLabelEvent struct {
+	Action    string    `json:"action"` // "add" / "remove"
+	CreatedAt time.Time `json:"created_at"`
+	Label     *struct {
+		Name string `json:"name"`
+	} `json:"label"` // 削除済みのラベルでは null
+}
+
+// apiNote は Issue・MR のノート（コメントとシステムノート）。
+type apiNote struct {
+	ID        int       `json:"id"`
+	Body      string    `json:"body"`
+	System    bool      `json:"system"` // 操作の記録（アサイン・承認など）
+	Type      string    `json:"type"`   // "DiffNote"（差分へのコメント）/ "DiscussionNote" / 空
+	CreatedAt time.Time `json:"created_at"`
+	Author    struct {
+		Username string `json:"username"`
+	} `json:"author"`
+}
+
+type apiRelease struct {
+	TagName    string    `json:"tag_name"`
+	Name       string    `json:"name"`
+	ReleasedAt time.Time `json:"released_at"`
+}
+
+type apiTag struct {
+	Name   string `json:"name"`
+	Commit struct {
+		ID           string    `json:"id"`
+		AuthoredDate time.Time `json:"authored_date"`
+	} `json:"commit"`
+}
+
+type apiDeployment struct {
+	ID          int       `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	Environment struct {
+		Name string `json:"name"`
+	} `json:"environment"`
+	Deployable *struct {
+		Name string `json:"name"` // デプロイしたジョブ名
+	} `json:"deployable"`
+}
+
+type apiProject struct {
+	DefaultBranch                    string `json:"default_branch"`
+	OnlyAllowMergeIfPipelineSucceeds bool   `json:"only_allow_merge_if_pipeline_succeeds"`
+}
+
+type apiProtectedBranch struct {
+	Name string `json:"name"` // ブランチ名かワイルドカード（release/*）
+}
+
+type apiApprovalRule struct {
+	ApprovalsRequired int `json:"approvals_required"`
+}
@@ -0,0 +1,478 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/features/analyze"
+	"github.com/ryuka-games/lokup/infrastructure/internal/apitest"
+)
+
+func TestGetCommits_Fixture(t *testing.T) {
+	period := domain.NewDateRange(fixtureTime(2025, 1, 1, 0, 0), fixtureTime(2025, 1, 31, 0, 0))
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/projects/group/app/repository/commits": func(w http.ResponseWriter, r *http.Request) {
+			// プロジェクトは "group/app" を1つのパスセグメントにエンコードした ID で指す
+			if !strings.Contains(r.URL.EscapedPath(), "/projects/group%2Fapp/") {
+				t.Errorf("path = %s, want the URL-encoded project path", r.URL.EscapedPath())
+			}
+			q := r.URL.Query()
+			if q.Get("since") != "2025-01-01T00:00:00Z" || q.Get("until") != "2025-01-31T00:00:00Z" || q.Get("path") != "src/app" || q.Get("ref_name") != "develop" {
+				t.Errorf("query = %v, want since/until of the period, path=src/app and ref_name=develop", q)
+			}
+			apitest.ServeFixture(t, "commits.json")(w, r)
+		},
+	})
+
+	got, err := c.GetCommits(context.Background(), domain.NewRepository("group", "app"), period, "src/app", "develop")
+	if err != nil {
+		t.Fatalf("GetCommits() error = %v", err)
+	}
+	want := []analyze.Commit{
+		{SHA: "c2", Author: "Alice", Email: "alice@example.com", Date: fixtureTime(2025, 1, 20, 10, 0),
+			Message: "Merge branch 'feature/login' into 'main'", Parents: []string{"c1", "b1"}},
+		{SHA: "c1", Author: "Bob", Email: "bob@example.com", Date: fixtureTime(2025, 1, 5, 8, 0),
+			Message: "Initial commit", Parents: []string{}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetCommits() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetCommitDetail(t *testing.T) {
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/projects/o/r/repository/commits/c2": func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("stats") != "true" {
+				t.Errorf("query = %v, want stats=true", r.URL.Query())
+			}
+			fmt.Fprint(w, `{"id":"c2","parent_ids":["c1"],"author_name":"Bob","author_email":"bob@example.com",
+				"authored_date":"2025-01-15T09:30:00Z","message":"fix: handle empty input","stats":{"additions":12,"deletions":3}}`)
+		},
+		"/projects/o/r/repository/commits/c2/diff": apitest.ServePages(
+			`[{"new_path":"src/input.go","diff":"@@ -1 +1 @@"}]`,
+			`[{"new_path":"src/input_test.go","diff":"@@ -0,0 +1 @@"}]`,
+		),
+	})
+
+	got, err := c.GetCommitDetail(context.Background(), domain.NewRepository("o", "r"), "c2")
+	if err != nil {
+		t.Fatalf("GetCommitDetail() error = %v", err)
+	}
+	want := &analyze.Commit{
+		SHA: "c2", Author: "Bob", Email: "bob@example.com", Date: fixtureTime(2025, 1, 15, 9, 30),
+		Message: "fix: handle empty input", Parents: []string{"c1"},
+		Files:     []string{"src/input.go", "src/input_test.go"},
+		Additions: 12, Deletions: 3,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetCommitDetail() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetContributors_MergesSameName(t *testing.T) {
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/projects/o/r/repository/contributors": apitest.ServePages(`[
+			{"name":"Alice","email":"alice@work.example.com","commits":30},
+			{"name":"Bob","email":"bob@example.com","commits":40},
+			{"name":"Alice","email":"alice@home.example.com","commits":25}
+		]`),
+	})
+
+	got, err := c.GetContributors(context.Background(), domain.NewRepository("o", "r"))
+	if err != nil {
+		t.Fatalf("GetContributors() error = %v", err)
+	}
+	want := []analyze.Contributor{{Login: "Alice", Contributions: 55}, {Login: "Bob", Contributions: 40}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetContributors() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetPullRequests_Fixture(t *testing.T) {
+	var all []json.RawMessage
+	if err := json.Unmarshal(apitest.ReadFixture(t, "merge_requests.json"), &all); err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	var gotStates []string
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		// API と同じく state で絞り込んだ MR だけを返す
+		"/projects/o/r/merge_requests": func(w http.ResponseWriter, r *http.Request) {
+			state := r.URL.Query().Get("state")
+			mu.Lock()
+			gotStates = append(gotStates, state)
+			mu.Unlock()
+			var items []json.RawMessage
+			for _, raw := range all {
+				var mr struct{ State string }
+				if err := json.Unmarshal(raw, &mr); err != nil {
+					t.Error(err)
+				}
+				if state == "all" || mr.State == state {
+					items = append(items, raw)
+				}
+			}
+			json.NewEncoder(w).Encode(items)
+		},
+	})
+	repo := domain.NewRepository("o", "r")
+	merged12, merged13 := fixtureTime(2025, 1, 20, 10, 0), fixtureTime(2025, 1, 16, 0, 0)
+
+	got, err := c.GetPullRequests(context.Background(), repo, "closed")
+	if err != nil {
+		t.Fatalf("GetPullRequests() error = %v", err)
+	}
+	if !slices.Equal(gotStates, []string{"merged", "closed"}) {
+		t.Errorf("states = %v, want merged and closed filtered by the API", gotStates)
+	}
+	// マージ済みと未マージのクローズを作成日の新しい順にまとめ、オープン（opened）は含まない。
+	// スカッシュのみのマージはスカッシュしたコミットを MergeSHA にする
+	want := []analyze.PullRequest{
+		{Number: 13, Title: "Tidy docs", Author: "carol", HeadBranch: "docs/tidy", HeadSHA: "e1",
+			MergeSHA: "e2", CreatedAt: fixtureTime(2025, 1, 15, 0, 0), MergedAt: &merged13},
+		{Number: 12, Title: "Add login", Author: "alice", HeadBranch: "feature/login", HeadSHA: "b1",
+			MergeSHA: "c2", CreatedAt: fixtureTime(2025, 1, 10, 0, 0), MergedAt: &merged12},
+		{Number: 11, Title: "Abandoned idea", Author: "bob", HeadBranch: "idea", HeadSHA: "f1",
+			CreatedAt: fixtureTime(2025, 1, 2, 0, 0)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetPullRequests(closed) = %+v, want %+v", got, want)
+	}
+
+	gotStates = nil
+	open, err := c.GetPullRequests(context.Background(), repo, "open")
+	if err != nil {
+		t.Fatalf("GetPullRequests(open) error = %v", err)
+	}
+	if !slices.Equal(gotStates, []string{"opened"}) || len(open) != 1 || open[0].Number != 14 {
+		t.Errorf("GetPullRequests(open) = %+v with states %v, want only !14 with opened", open, gotStates)
+	}
+}
+
+func TestGetPRDetail_CountsDiffLines(t *testing.T) {
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/projects/o/r/merge_requests/12": func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"iid":12,"title":"Add login","state":"merged","created_at":"2025-01-10T00:00:00Z",
+				"merged_at":"2025-01-20T10:00:00Z","source_branch":"feature/login","sha":"b1","merge_commit_sha":"c2",
+				"author":{"username":"alice"}}`)
+		},
+		"/projects/o/r/merge_requests/12/diffs": apitest.ServePages(
+			`[{"new_path":"login.go","diff":"@@ -1,2 +1,3 @@\n context\n-old\n+new\n+added\n"}]`,
+			`[{"new_path":"README.md","diff":"@@ -1 +0,0 @@\n---- a removed markdown rule\n"}]`,
+		),
+	})
+
+	got, err := c.GetPRDetail(context.Background(), domain.NewRepository("o", "r"), 12)
+	if err != nil {
+		t.Fatalf("GetPRDetail() error = %v", err)
+	}
+	if got.Number != 12 || got.MergeSHA != "c2" || got.Additions != 2 || got.Deletions != 2 {
+		t.Errorf("GetPRDetail() = %+v, want #12 merged as c2 with +2/-2", got)
+	}
+}
+
+func TestGetPRReviewsAndComments_Fixture(t *testing.T) {
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/projects/o/r/merge_requests/12/notes": apitest.ServeFixture(t, "merge_request_notes.json"),
+	})
+	repo := domain.NewRepository("o", "r")
+
+	reviews, err := c.GetPRReviews(context.Background(), repo, 12)
+	if err != nil {
+		t.Fatalf("GetPRReviews() error = %v", err)
+	}
+	wantReviews := []analyze.Review{
+		{ID: 3, Author: "bob", State: "COMMENTED", SubmittedAt: fixtureTime(2025, 1, 12, 9, 0)},
+		{ID: 4, Author: "bob", State: "CHANGES_REQUESTED", SubmittedAt: fixtureTime(2025, 1, 12, 9, 5)},
+		{ID: 6, Author: "carol", State: "APPROVED", SubmittedAt: fixtureTime(2025, 1, 14, 9, 0)},
+	}
+	if !reflect.DeepEqual(reviews, wantReviews) {
+		t.Errorf("GetPRReviews() = %+v, want %+v", reviews, wantReviews)
+	}
+
+	comments, err := c.GetPRComments(context.Background(), repo, 12)
+	if err != nil {
+		t.Fatalf("GetPRComments() error = %v", err)
+	}
+	wantComments := []analyze.Comment{
+		{ID: 1, Author: "project_42_bot_3f2a", Bot: true, CreatedAt: fixtureTime(2025, 1, 10, 9, 0)},
+		{ID: 2, Author: "bob", CreatedAt: fixtureTime(2025, 1, 11, 9, 0)},
+	}
+	if !reflect.DeepEqual(comments, wantComments) {
+		t.Errorf("GetPRComments() = %+v, want %+v", comments, wantComments)
+	}
+}
+
+func TestGetIssues_Fixture(t *testing.T) {
+	since := fixtureTime(2025, 1, 1, 0, 0)
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/projects/o/r/issues": func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			if q.Get("state") != "all" || q.Get("updated_after") != "2025-01-01T00:00:00Z" {
+				t.Errorf("query = %v, want state=all and updated_after", q)
+			}
+			apitest.ServeFixture(t, "issues.json")(w, r)
+		},
+	})
+
+	got, err := c.GetIssues(context.Background(), domain.NewRepository("o", "r"), "all", &since)
+	if err != nil {
+		t.Fatalf("GetIssues() error = %v", err)
+	}
+	closed := fixtureTime(2025, 1, 12, 0, 0)
+	want := []analyze.Issue{
+		{Number: 8, Title: "Login fails on Safari", State: "closed", Labels: []string{"bug", "incident"},
+			CreatedAt: fixtureTime(2025, 1, 10, 0, 0), ClosedAt: &closed},
+		{Number: 9, Title: "Add dark mode", State: "open", Labels: []string{}, CreatedAt: fixtureTime(2025, 1, 15, 0, 0)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetIssues() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetIssueEvents(t *testing.T) {
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/projects/o/r/issues/8/resource_label_events": apitest.ServePages(`[
+			{"action":"add","created_at":"2025-01-10T03:00:00Z","label":{"name":"incident"}},
+			{"action":"remove","created_at":"2025-01-12T00:00:00Z","label":null}
+		]`),
+		"/projects/o/r/issues/8/notes": apitest.ServePages(`[
+			{"id":1,"body":"assigned to @alice","system":true,"created_at":"2025-01-10T01:00:00Z"},
+			{"id":2,"body":"assigned to me?","system":false,"created_at":"2025-01-10T02:00:00Z"}
+		]`),
+	})
+
+	got, err := c.GetIssueEvents(context.Background(), domain.NewRepository("o", "r"), 8)
+	if err != nil {
+		t.Fatalf("GetIssueEvents() error = %v", err)
+	}
+	want := []analyze.IssueEvent{
+		{Event: "assigned", CreatedAt: fixtureTime(2025, 1, 10, 1, 0)},
+		{Event: "labeled", Label: "incident", CreatedAt: fixtureTime(2025, 1, 10, 3, 0)},
+		{Event: "unlabeled", CreatedAt: fixtureTime(2025, 1, 12, 0, 0)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetIssueEvents() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetFiles_Truncated(t *testing.T) {
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/projects/o/r/repository/tree": func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			if q.Get("recursive") != "true" || q.Get("ref") != "develop" {
+				t.Errorf("query = %v, want recursive=true and ref=develop", q)
+			}
+			// ページの上限に達するまで続きを返し続ける
+			q.Set("page_token", "next")
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s%s?%s>; rel="next"`, r.Host, r.URL.EscapedPath(), q.Encode()))
+			fmt.Fprint(w, `[{"path":"src","type":"tree"},{"path":"src/main.go","type":"blob"},{"path":"vendor/lib","type":"commit"}]`)
+		},
+	})
+
+	files, truncated, err := c.GetFiles(context.Background(), domain.NewRepository("o", "r"), "develop")
+	if err != nil {
+		t.Fatalf("GetFiles() error = %v", err)
+	}
+	if !truncated || len(files) != defaultMaxTreePages || files[0] != (analyze.File{Path: "src/main.go"}) {
+		t.Errorf("GetFiles() = %d files (first %+v), truncated = %v, want %d blobs and truncated", len(files), files[0], truncated, defaultMaxTreePages)
+	}
+}
+
+func TestGetWorkflowRuns_Deployments(t *testing.T) {
+	period := domain.NewDateRange(fixtureTime(2025, 1, 1, 0, 0), fixtureTime(2025, 1, 31, 0, 0))
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/projects/o/r/deployments": apitest.ServePages(`[
+			{"id":1,"created_at":"2025-01-05T00:00:00Z","environment":{"name":"production"},"deployable":{"name":"deploy:prod"}},
+			{"id":2,"created_at":"2025-01-06T00:00:00Z","environment":{"name":"staging"},"deployable":{"name":"deploy:staging"}},
+			{"id":3,"created_at":"2024-12-31T00:00:00Z","environment":{"name":"production"},"deployable":null},
+			{"id":4,"created_at":"2025-01-07T00:00:00Z","environment":{"name":"review/feature"},"deployable":{"name":"Deploy:Prod"}}
+		]`),
+	})
+
+	got, err := c.GetWorkflowRuns(context.Background(), domain.NewRepository("o", "r"), "deploy:prod", period)
+	if err != nil {
+		t.Fatalf("GetWorkflowRuns() error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID != 1 || got[1].ID != 4 {
+		t.Errorf("GetWorkflowRuns(job name) = %+v, want deployments 1 and 4", got)
+	}
+
+	got, err = c.GetWorkflowRuns(context.Background(), domain.NewRepository("o", "r"), "Production", period)
+	if err != nil {
+		t.Fatalf("GetWorkflowRuns() error = %v", err)
+	}
+	// 期間より前に作成されたデプロイは除く
+	if len(got) != 1 || got[0].ID != 1 || got[0].Name != "production" || got[0].Path != "deploy:prod" {
+		t.Errorf("GetWorkflowRuns(environment) = %+v, want deployment 1 only", got)
+	}
+}
+
+func TestGetBranchProtection(t *testing.T) {
+	tests := []struct {
+		name          string
+		project       string
+		protected     http.HandlerFunc
+		approvalRules http.HandlerFunc
+		want          *domain.BranchProtection
+	}{
+		{
+			name:          "protected with approvals and pipeline required",
+			project:       `{"default_branch":"main","only_allow_merge_if_pipeline_succeeds":true}`,
+			protected:     apitest.ServePages(`[{"name":"main"}]`),
+			approvalRules: apitest.ServePages(`[{"approvals_required":1},{"approvals_required":2}]`),
+			want:          &domain.BranchProtection{Branch: "main", Protected: true, RequiredApprovals: 2, RequiredStatusChecks: true},
+		},
+		{
+			name:      "protected by wildcard without approval rules (free plan)",
+			project:   `{"default_branch":"release/2025","only_allow_merge_if_pipeline_succeeds":false}`,
+			protected: apitest.ServePages(`[{"name":"release/*"}]`),
+			want:      &domain.BranchProtection{Branch: "release/2025", Protected: true},
+		},
+		{
+			name:      "not protected",
+			project:   `{"default_branch":"main"}`,
+			protected: apitest.ServePages(`[{"name":"stable"}]`),
+			want:      &domain.BranchProtection{Branch: "main"},
+		},
+		{
+			name:    "forbidden is unknown",
+			project: `{"default_branch":"main"}`,
+			protected: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, `{"message":"403 Forbidden"}`, http.StatusForbidden)
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			routes := map[string]http.HandlerFunc{
+				"/projects/o/r":                    apitest.ServePages(tt.project),
+				"/projects/o/r/protected_branches": tt.protected,
+			}
+			if tt.approvalRules != nil {
+				routes["/projects/o/r/approval_rules"] = tt.approvalRules
+			}
+			c := newFixtureClient(t, routes)
+
+			got, err := c.GetBranchProtection(context.Background(), domain.NewRepository("o", "r"))
+			if err != nil {
+				t.Fatalf("GetBranchProtection() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetBranchProtection() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBranchExists(t *testing.T) {
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/projects/o/r/repository/branches/feature/login": apitest.ServePages(`{"name":"feature/login"}`),
+	})
+	repo := domain.NewRepository("o", "r")
+
+	for branch, want := range map[string]bool{"feature/login": true, "missing": false} {
+		got, err := c.BranchExists(context.Background(), repo, branch)
+		if err != nil {
+			t.Fatalf("BranchExists(%q) error = %v", branch, err)
+		}
+		if got != want {
+			t.Errorf("BranchExists(%q) = %v, want %v", branch, got, want)
+		}
+	}
+}
+
+func TestDoRequest_RateLimited(t *testing.T) {
+	var calls atomic.Int32
+	c := newFixtureClient(t, map[string]http.HandlerFunc{
+		"/projects/o/r/releases": func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			fmt.Fprint(w, `[{"tag_name":"v1.0.0","name":"1.0","released_at":"2025-01-20T00:00:00Z"}]`)
+		},
+		"/projects/o/r/repository/tags": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+		},
+	})
+	repo := domain.NewRepository("o", "r")
+
+	// 待てる長さなら待って再送する
+	releases, err := c.GetReleases(context.Background(), repo)
+	if err != nil {
+		t.Fatalf("GetReleases() error = %v", err)
+	}
+	want := []analyze.Release{{TagName: "v1.0.0", Name: "1.0", PublishedAt: fixtureTime(2025, 1, 20, 0, 0)}}
+	if !reflect.DeepEqual(releases, want) || calls.Load() != 2 {
+		t.Errorf("GetReleases() = %+v after %d calls, want %+v after a retry", releases, calls.Load(), want)
+	}
+
+	// 解除まで長すぎれば待たずにエラーにする
+	if _, err := c.GetTags(context.Background(), repo); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("GetTags() error = %v, want ErrRateLimited", err)
+	}
+}
+
+func TestDoRequest_RateLimitResetUsesClock(t *testing.T) {
+	now := fixtureTime(2025, 1, 31, 12, 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 注入した時刻の1時間後に解除される（実時間ではすでに過ぎている）
+		w.Header().Set("RateLimit-Reset", strconv.FormatInt(now.Add(time.Hour).Unix(), 10))
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+	c := NewClient("", WithBaseURL(srv.URL), WithMaxRetries(0), WithClock(func() time.Time { return now }))
+
+	_, err := c.GetTags(context.Background(), domain.NewRepository("o", "r"))
+	if !errors.Is(err, ErrRateLimited) || !strings.Contains(err.Error(), "retry after 1h0m0s") {
+		t.Errorf("GetTags() error = %v, want ErrRateLimited with the wait measured from the clock", err)
+	}
+}
+
+func TestAPIError_IncludesMessage(t *testing.T) {
+	c := newFixtureClient(t, nil)
+
+	_, err := c.GetFileContent(context.Background(), domain.NewRepository("o", "missing"), "README.md")
+	if err == nil || !strings.Contains(err.Error(), "404 Not Found") || !hasStatus(err, http.StatusNotFound) {
+		t.Errorf("GetFileContent() error = %v, want a 404 statusError with the message", err)
+	}
+}
+
+func TestMatchBranchPattern(t *testing.T) {
+	tests := []struct {
+		pattern, branch string
+		want            bool
+	}{
+		{"main", "main", true},
+		{"main", "main2", false},
+		{"*", "main", true},
+		{"release/*", "release/2025/q1", true},
+		{"release/*", "hotfix/1", false},
+		{"*-stable", "1.0-stable", true},
+		{"*-stable", "stable", false},
+		{"v*.*", "v1.2", true},
+		{"v*.*", "v12", false},
+	}
+	for _, tt := range tests {
+		if got := matchBranchPattern(tt.pattern, tt.branch); got != tt.want {
+			t.Errorf("matchBranchPattern(%q, %q) = %v, want %v", tt.pattern, tt.branch, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package gitlab
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/infrastructure/internal/apitest"
+)
+
+// newFixtureClient は routes の応答を返す httptest サーバーに向けた Client を返す。
+//
+// routes のキーはデコード済みのリクエストのパス（プロジェクト ID の %2F は "/" に戻る）。
+// 登録のないパスは 404 を返す。
+func newFixtureClient(t *testing.T, routes map[string]http.HandlerFunc) *Client {
+	t.Helper()
+	srv := apitest.NewServer(t, routes, `{"message":"404 Not Found"}`)
+	return NewClient("", WithBaseURL(srv.URL), WithMaxRetries(0))
+}
+
+// fixtureTime は UTC の日時を返す。
+func fixtureTime(year int, month time.Month, day, hour, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+}
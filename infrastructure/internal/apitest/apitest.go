@@ -0,0 +1,86 @@
+// Package apitest は GitHub・GitLab クライアントのテストが共通に使うフィクスチャのヘルパーを提供する。
+//
+// テスト専用（_test.go からだけ使う）。応答は呼び出し側のパッケージの testdata 配下に置く。
+package apitest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// NewServer は routes の応答を返す httptest サーバーを起動する（テストの終了時に閉じる）。
+//
+// routes のキーはデコード済みのリクエストのパス。登録のないパスは 404 と notFoundBody を返す。
+func NewServer(t *testing.T, routes map[string]http.HandlerFunc, notFoundBody string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h, ok := routes[r.URL.Path]; ok {
+			h(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, notFoundBody)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// ReadFixture は testdata 配下のファイルを読む。
+func ReadFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	return b
+}
+
+// ServeFixture は testdata 配下のファイルをそのまま JSON として返す。
+func ServeFixture(t *testing.T, name string) http.HandlerFunc {
+	return ServeJSON(ReadFixture(t, name))
+}
+
+// ServeJSON は body をそのまま JSON として返す。
+func ServeJSON(body []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}
+}
+
+// ServeFixturePages は ?page=N に応じて testdata 配下の names[N-1] を返す（ServePages 参照）。
+func ServeFixturePages(t *testing.T, names ...string) http.HandlerFunc {
+	pages := make([]string, len(names))
+	for i, name := range names {
+		pages[i] = string(ReadFixture(t, name))
+	}
+	return ServePages(pages...)
+}
+
+// ServePages は ?page=N に応じて pages[N-1] を JSON として返し、続きがあれば Link ヘッダで次ページを示す。
+// 次ページの URL はエスケープしたままのパスを使う（GitLab のプロジェクト ID の %2F を残すため）。
+func ServePages(pages ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page := 1
+		if p := r.URL.Query().Get("page"); p != "" {
+			page, _ = strconv.Atoi(p)
+		}
+		if page < 1 || page > len(pages) {
+			http.NotFound(w, r)
+			return
+		}
+		if page < len(pages) {
+			q := r.URL.Query()
+			q.Set("page", strconv.Itoa(page+1))
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s%s?%s>; rel="next"`, r.Host, r.URL.EscapedPath(), q.Encode()))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, pages[page-1])
+	}
+}
@@ -0,0 +1,190 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLimiter_AcquireCanceled(t *testing.T) {
+	l := NewLimiter(1)
+	if err := l.Acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Acquire(ctx); err != context.Canceled {
+		t.Errorf("Acquire() error = %v, want context.Canceled", err)
+	}
+	l.Release()
+	if err := l.Acquire(context.Background()); err != nil {
+		t.Errorf("Acquire() after release error = %v", err)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		statuses  []int // 送るたびに返すステータス（足りなければ最後のものを繰り返す）
+		retries   int
+		want      int
+		wantCalls int
+	}{
+		{"success", []int{200}, 3, 200, 1},
+		{"retry on 503", []int{503, 502, 200}, 3, 200, 3},
+		{"give up after retries", []int{504}, 2, 504, 3},
+		{"no retries", []int{503, 200}, 0, 503, 1},
+		{"not retryable", []int{404, 200}, 3, 404, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			resp, err := Retry(context.Background(), tt.retries, time.Millisecond, func() (*http.Response, error) {
+				status := tt.statuses[min(calls, len(tt.statuses)-1)]
+				calls++
+				rec := httptest.NewRecorder()
+				rec.WriteHeader(status)
+				return rec.Result(), nil
+			})
+			if err != nil {
+				t.Fatalf("Retry() error = %v", err)
+			}
+			if resp.StatusCode != tt.want || calls != tt.wantCalls {
+				t.Errorf("Retry() = %d after %d calls, want %d after %d", resp.StatusCode, calls, tt.want, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetry_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	_, err := Retry(ctx, 3, time.Hour, func() (*http.Response, error) {
+		calls++
+		cancel()
+		rec := httptest.NewRecorder()
+		rec.WriteHeader(http.StatusServiceUnavailable)
+		return rec.Result(), nil
+	})
+	if err != nil || calls != 1 {
+		t.Errorf("Retry() error = %v after %d calls, want the response without waiting", err, calls)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		err    error
+		want   bool
+	}{
+		{"bad gateway", http.StatusBadGateway, nil, true},
+		{"service unavailable", http.StatusServiceUnavailable, nil, true},
+		{"gateway timeout", http.StatusGatewayTimeout, nil, true},
+		{"internal server error", http.StatusInternalServerError, nil, false},
+		{"ok", http.StatusOK, nil, false},
+		{"timeout", 0, timeoutError{}, true},
+		{"other error", 0, errors.New("connection refused"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp *http.Response
+			if tt.err == nil {
+				resp = &http.Response{StatusCode: tt.status}
+			}
+			if got := IsRetryable(resp, tt.err); got != tt.want {
+				t.Errorf("IsRetryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// timeoutError はタイムアウトを表す net.Error。
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestNextPageURL(t *testing.T) {
+	tests := []struct {
+		name string
+		link string
+		want string
+	}{
+		{"empty", "", ""},
+		{"next and last", `<https://api.example.com/items?page=2>; rel="next", <https://api.example.com/items?page=5>; rel="last"`, "https://api.example.com/items?page=2"},
+		{"last page", `<https://api.example.com/items?page=1>; rel="first", <https://api.example.com/items?page=4>; rel="prev"`, ""},
+		{"keyset", `<https://gitlab.example.com/api/v4/projects/1/repository/tree?page_token=abc&pagination=keyset>; rel="next"`, "https://gitlab.example.com/api/v4/projects/1/repository/tree?page_token=abc&pagination=keyset"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextPageURL(tt.link); got != tt.want {
+				t.Errorf("NextPageURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchAllPages(t *testing.T) {
+	const pages = 3
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/items" {
+			http.NotFound(w, r)
+			return
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < pages {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/items?page=%d>; rel="next"`, r.Host, page+1))
+		}
+		fmt.Fprintf(w, `[%d, %d]`, page*10, page*10+1)
+	}))
+	defer srv.Close()
+
+	var requests atomic.Int32
+	p := Pager{
+		Get: func(ctx context.Context, url string) (*http.Response, error) {
+			requests.Add(1)
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return nil, err
+			}
+			return http.DefaultClient.Do(req)
+		},
+		StatusError: func(resp *http.Response) error { return errors.New(resp.Status) },
+		Logger:      slog.New(slog.DiscardHandler),
+	}
+
+	t.Run("all pages", func(t *testing.T) {
+		requests.Store(0)
+		got, truncated, err := FetchAllPages[int](context.Background(), p, srv.URL+"/items?page=1", "items", 10)
+		if err != nil || truncated {
+			t.Fatalf("FetchAllPages() truncated = %v, error = %v", truncated, err)
+		}
+		if len(got) != 6 || got[0] != 10 || got[5] != 31 || requests.Load() != pages {
+			t.Errorf("FetchAllPages() = %v after %d requests, want 6 items from %d pages", got, requests.Load(), pages)
+		}
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		requests.Store(0)
+		got, truncated, err := FetchAllPages[int](context.Background(), p, srv.URL+"/items?page=1", "items", 2)
+		if err != nil || !truncated || len(got) != 4 || requests.Load() != 2 {
+			t.Errorf("FetchAllPages() = %v, truncated = %v, error = %v after %d requests, want 4 items truncated after 2", got, truncated, err, requests.Load())
+		}
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		_, _, err := FetchAllPages[int](context.Background(), p, srv.URL+"/missing", "items", 2)
+		if err == nil || err.Error() != "404 Not Found" {
+			t.Errorf("FetchAllPages() error = %v, want the StatusError result", err)
+		}
+	})
+}
@@ -0,0 +1,26 @@
+package httpapi
+
+import "context"
+
+// Limiter は同時に送る HTTP リクエスト数を制限するセマフォ。
+type Limiter chan struct{}
+
+// NewLimiter は同時に n 件まで通す Limiter を生成する。
+func NewLimiter(n int) Limiter {
+	return make(Limiter, n)
+}
+
+// Acquire は枠が空くまで待つ。待っている間に ctx がキャンセルされたらそのエラーを返す。
+func (l Limiter) Acquire(ctx context.Context) error {
+	select {
+	case l <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release は Acquire で確保した枠を返す。
+func (l Limiter) Release() {
+	<-l
+}
@@ -0,0 +1,81 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/ryuka-games/lokup/features/analyze"
+)
+
+// Pager は Link ヘッダの rel="next" を辿って一覧を取得するための、クライアントごとの設定。
+type Pager struct {
+	// Get は GET リクエストを送る（リトライ・レート制限の待ちはクライアント側で行う）
+	Get func(ctx context.Context, url string) (*http.Response, error)
+	// StatusError は 200 以外の応答をエラーにする（ボディは呼び出し側で閉じる）
+	StatusError func(resp *http.Response) error
+	// Logger はページ送りと打ち切りを出すロガー
+	Logger *slog.Logger
+}
+
+// FetchAllPages は Link ヘッダの rel="next" を辿って全ページを取得する。
+// maxPages に達したら打ち切り、その旨をログに出して truncated = true を返す（analyze.MarkTruncated でも記録する）。
+func FetchAllPages[T any](ctx context.Context, p Pager, url, what string, maxPages int) (all []T, truncated bool, err error) {
+	for page := 1; url != ""; page++ {
+		if page > maxPages {
+			p.Logger.Warn("list truncated", "what", what, "pages", maxPages, "items", len(all))
+			analyze.MarkTruncated(ctx)
+			return all, true, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, false, err
+		}
+
+		items, next, err := FetchPage[T](ctx, p, url, what)
+		if err != nil {
+			return nil, false, err
+		}
+		all = append(all, items...)
+		p.Logger.Debug("fetched page", "what", what, "page", page, "items", len(items), "hasNext", next != "")
+		url = next
+	}
+	return all, false, nil
+}
+
+// FetchPage は1ページ分を取得し、次ページの URL（なければ空）を返す。
+func FetchPage[T any](ctx context.Context, p Pager, url, what string) ([]T, string, error) {
+	resp, err := p.Get(ctx, url)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch %s: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", p.StatusError(resp)
+	}
+
+	var items []T
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		return nil, "", fmt.Errorf("failed to decode %s: %w", what, err)
+	}
+
+	return items, NextPageURL(resp.Header.Get("Link")), nil
+}
+
+// NextPageURL は Link ヘッダから rel="next" の URL を取り出す。
+// GitHub も GitLab（オフセット方式の page= でもキーセット方式の page_token= でも）同じ形で返す。
+//
+//	<https://api.github.com/...&page=2>; rel="next", <https://api.github.com/...&page=5>; rel="last"
+func NextPageURL(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		target, params, ok := strings.Cut(part, ";")
+		if !ok || !strings.Contains(params, `rel="next"`) {
+			continue
+		}
+		return strings.Trim(strings.TrimSpace(target), "<>")
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+// Package httpapi は GitHub・GitLab クライアントが共通に使う HTTP の送受信を提供する。
+//
+// 同時実行数の制限（Limiter）、一時的なエラーの指数バックオフ（Retry）、
+// Link ヘッダの rel="next" を辿るページ送り（FetchAllPages）を扱う。
+// 認証ヘッダ・レート制限の判定・エラー応答の解釈はサービスごとに異なるため、
+// 各クライアントのパッケージに置く。
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Do はリクエストを1回送る。l の枠を確保してから送り、結果を logger に Debug レベルで出す。
+//
+// 枠はレスポンスヘッダを受け取るまで持つ。ボディの読み出しやリトライ・レート制限の
+// 待ち時間には持たないため、キャッシュヒットや待機中のリクエストが他を塞がない。
+func Do(client *http.Client, l Limiter, logger *slog.Logger, req *http.Request) (*http.Response, error) {
+	if err := l.Acquire(req.Context()); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	l.Release()
+
+	if err != nil {
+		logger.Debug("request failed", "method", req.Method, "url", req.URL.String(),
+			"duration", time.Since(start).Round(time.Millisecond), "error", err)
+		return nil, err
+	}
+	logger.Debug("request", "method", req.Method, "url", req.URL.String(), "status", resp.StatusCode,
+		"duration", time.Since(start).Round(time.Millisecond))
+	return resp, nil
+}
+
+// Retry は send でリクエストを送り、一時的なエラー（IsRetryable）なら最大 retries 回まで
+// baseDelay から倍々に伸ばす指数バックオフでリトライする。
+// リトライ待ちの間も ctx のキャンセルを優先する。
+func Retry(ctx context.Context, retries int, baseDelay time.Duration, send func() (*http.Response, error)) (*http.Response, error) {
+	delay := baseDelay
+	for attempt := 0; ; attempt++ {
+		resp, err := send()
+		if attempt >= retries || !IsRetryable(resp, err) || ctx.Err() != nil {
+			return resp, err
+		}
+
+		// リトライ前にボディを捨てて接続を再利用できるようにする
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if err := SleepContext(ctx, delay); err != nil {
+			return nil, err
+		}
+		delay *= 2
+	}
+}
+
+// IsRetryable は一時的なエラー（502/503/504・タイムアウト）でリトライする価値があるかを返す。
+func IsRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		var netErr net.Error
+		return errors.As(err, &netErr) && netErr.Timeout()
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// SleepContext は d だけ待つ。ctx がキャンセルされたらすぐに戻る。
+func SleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,385 @@
+// Package lokup は lokup を Go のライブラリとして組み込むためのエントリポイント。
+//
+// CLI（cmd/lokup）と同じ組み立て（GitHub / GitLab クライアント → 分析サービス → レポート）を
+// 関数呼び出しで行う。CLI はこのパッケージの薄いラッパーで、引数の解析・トークンの解決・
+// 進捗と結果の表示・終了コードだけを受け持つ。
+//
+//	result, err := lokup.Run(ctx, lokup.Options{
+//		Token:      os.Getenv("GITHUB_TOKEN"),
+//		Repository: domain.NewRepository("facebook", "react"),
+//		Days:       90,
+//	})
+package lokup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+	"github.com/ryuka-games/lokup/features/analyze"
+	"github.com/ryuka-games/lokup/features/report"
+	"github.com/ryuka-games/lokup/infrastructure/github"
+	"github.com/ryuka-games/lokup/infrastructure/gitlab"
+	"github.com/ryuka-games/lokup/shared/i18n"
+)
+
+// DefaultDays は Period も Days も指定しないときの分析期間（日数）。
+const DefaultDays = 30
+
+// Provider はリポジトリのデータの取得元。
+type Provider string
+
+const (
+	ProviderGitHub Provider = "github" // GitHub（デフォルト）
+	ProviderGitLab Provider = "gitlab" // GitLab（GitLab.com かセルフマネージド）
+)
+
+// ParseProvider は文字列をデータの取得元に変換する。空ならデフォルト（github）。
+func ParseProvider(s string) (Provider, error) {
+	switch p := Provider(strings.ToLower(s)); p {
+	case "":
+		return ProviderGitHub, nil
+	case ProviderGitHub, ProviderGitLab:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unsupported provider: %q (use github or gitlab)", s)
+	}
+}
+
+// Options は分析の設定。ゼロ値の項目はそれぞれのデフォルトになる。
+type Options struct {
+	// Token は GitHub（Provider が gitlab なら GitLab）のトークン。空なら認証なしで呼ぶ（レート制限が厳しい）。
+	Token string
+	// Repository は分析対象のリポジトリ（Run で使う）。
+	Repository domain.Repository
+
+	// Period は分析期間。nil なら現在から Days 日さかのぼる。
+	Period *domain.DateRange
+	// Days は Period が nil のときの分析期間の日数（0 なら DefaultDays）。
+	Days int
+
+	// 閾値・取得範囲（0 ならデフォルト）
+	StalePRDays      int // オープンのままこの日数を超えたPRを滞留とみなす
+	MaxCommitDetails int // 変更ファイルを取得するコミット数の上限
+	TopFiles         int // 巨大ファイル・変更集中ファイルの一覧に残す件数
+	TodoScanMaxFiles int // TODO コメントを数えるために内容を取得するファイル数の上限（0 なら走査しない）
+
+	// CheckVulns が true なら依存の既知の脆弱性を OSV（api.osv.dev）で調べる。
+	CheckVulns bool
+	// Quick が true ならPR詳細・レビューと依存を取得しないクイックモードで分析する（analyze.WithQuickMode 参照）。
+	// 依存を取得しないため CheckVulns は効かない。
+	Quick bool
+	// IncludeDrafts が true ならドラフトPRもPR系のメトリクス・リスクに含める（analyze.WithIncludeDrafts 参照）。
+	IncludeDrafts bool
+
+	// スコア・判定の設定
+	CategoryWeights map[domain.Category]float64 // 総合スコアのカテゴリ別の重み（nil なら均等、analyze.NormalizeCategoryWeights で検証した値）
+	FailureLabels   []string                    // 障害とみなす Issue ラベル（nil ならデフォルト）
+	Penalties       *analyze.SeverityPenalties  // カテゴリスコアの重大度別の減点（nil ならデフォルト、SeverityPenalties.Validate で検証した値）
+	DORABands       *domain.DORARatingBands     // DORA レーティングの境界（nil ならデフォルト、DORARatingBands.Validate で検証した値）
+	BranchPrefixes  analyze.BranchPrefixes      // PRの種類を判定するブランチ名の接頭辞（nil の種類はデフォルト）
+	NonSourceExts   []string                    // 巨大ファイルリスクの対象外にするファイル名の末尾（nil ならデフォルト）
+	Location        *time.Location              // 深夜判定・時間帯別集計のタイムゾーン（nil ならコミット自身のオフセット）
+	// CoAuthorshipMitigation が true なら共同作成のコミットが多い期間の属人化リスクの重大度を下げる
+	// （analyze.WithCoAuthorshipMitigation 参照）。
+	CoAuthorshipMitigation bool
+	// TestFilePatterns はテストファイルとみなすパスのパターン（nil ならデフォルト、
+	// analyze.ValidateTestFilePatterns で検証した値。analyze.WithTestFilePatterns 参照）。
+	TestFilePatterns []string
+
+	// 分析対象の絞り込み
+	Path       string  // リポジトリ内のこのパス配下に絞る（空ならリポジトリ全体）
+	Branch     string  // デフォルトブランチの代わりに分析するブランチ（空ならデフォルトブランチ）
+	IgnoreFile *string // .lokupignore 形式の除外パターン（nil ならリポジトリの .lokupignore を使う）
+
+	// DORA メトリクスでデプロイとみなすイベントの取得元（空なら releases）と、workflows のときのワークフロー名
+	DeploySource   analyze.DeploySource
+	DeployWorkflow string
+
+	// MTTR で復旧作業の開始とみなす時点（空なら Issue の作成）
+	MTTRSource analyze.MTTRSource
+
+	// 属人化リスクでコミットの偏りを見るときの集計元（空なら累計のコントリビューター）
+	OwnershipSource analyze.OwnershipSource
+
+	// HTTP クライアントの設定
+	Concurrency int           // 同時に送る HTTP リクエスト数の上限（0 なら github.DefaultConcurrency）
+	CacheTTL    time.Duration // API レスポンスをディスクにキャッシュする期間（0 ならキャッシュしない。GitHub のみ）
+
+	// Provider はデータの取得元（空なら GitHub）。GitLab では依存・force push・ファイルサイズを取得しないため、
+	// CheckVulns は使えず、巨大ファイルのリスクも出ない（infrastructure/gitlab 参照）。
+	Provider Provider
+	// GitLabURL は GitLab API のベース URL（空なら gitlab.DefaultBaseURL。セルフマネージドの GitLab 用）。
+	GitLabURL string
+
+	// Lang はリスクの説明・診断・レポートの言語（ゼロ値なら日本語）。
+	Lang i18n.Lang
+	// Baseline は比較の基準にする過去の JSON レポート（nil なら比較しない）。
+	Baseline *report.Baseline
+
+	// History を 2 以上にすると、分析期間の終わりからさかのぼって HistoryWindow 日ずつの
+	// 期間を History 個分析し、総合スコアの推移を AnalysisResult.History に付ける（0 なら推移を出さない）。
+	// HistoryWindow が 0 なら各期間の長さは分析期間と同じ。
+	History       int
+	HistoryWindow int
+
+	// Clock は現在時刻の取得元（nil なら time.Now）。分析期間の計算・分析・API クライアントで共通に使う。
+	// テストやレポートの再現のために時刻を固定したい場合に差し替える。所要時間の計測には使わない。
+	Clock func() time.Time
+
+	// Logger は取得ごとの所要時間などを出すロガー（nil なら slog.Default）。
+	Logger *slog.Logger
+	// Progress は分析の進捗の通知先（nil なら通知しない）。
+	// 「PR詳細を取得中 12/20」のような表示をアプリケーション側で出すために使う。
+	Progress analyze.ProgressFunc
+
+	// Output を指定すると Run がレポートも書き出す（空なら書かない）。
+	// {repo} の展開や "-"（標準出力）は CLI の --output と同じ。
+	Output string
+	// OutputDir を指定すると Run が index.html と CSV（Format が HTML 以外ならその形式のレポートも）を
+	// このディレクトリにまとめて書き出す（report.Service.GenerateBundle 参照）。Output とは併用できない。
+	OutputDir string
+	// Format はレポートの形式（空なら HTML）。
+	Format report.Format
+}
+
+// Analyzer は組み立て済みのクライアントと分析サービス。
+// 同じ設定で複数のリポジトリを分析する場合に使う（期間もすべてのリポジトリで揃う）。
+type Analyzer struct {
+	service  *analyze.Service
+	period   domain.DateRange
+	baseline *report.Baseline
+	logger   *slog.Logger
+
+	historyPeriods []domain.DateRange // スコア推移を出す期間（古い順、最後は period と同じ終わり）
+}
+
+// NewAnalyzer は opts から GitHub（または GitLab）クライアントと分析サービスを組み立てる。
+func NewAnalyzer(opts Options) (*Analyzer, error) {
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	clock := opts.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+
+	var client analyze.Repository
+	var vulnChecker analyze.VulnerabilityChecker
+	switch opts.Provider {
+	case ProviderGitLab:
+		if opts.CheckVulns {
+			return nil, errors.New("vulnerability check is not supported for GitLab (dependencies are not analyzed)")
+		}
+		if opts.CacheTTL > 0 {
+			logger.Debug("response cache is not supported for GitLab, fetching without cache")
+		}
+		client = gitlab.NewClient(opts.Token,
+			gitlab.WithBaseURL(opts.GitLabURL),
+			gitlab.WithLogger(logger),
+			gitlab.WithConcurrency(opts.Concurrency),
+			gitlab.WithClock(clock),
+		)
+	case ProviderGitHub, "":
+		clientOpts := []github.ClientOption{github.WithLogger(logger), github.WithConcurrency(opts.Concurrency), github.WithClock(clock)}
+		if opts.CacheTTL > 0 {
+			dir, err := github.DefaultCacheDir()
+			if err != nil {
+				return nil, err
+			}
+			clientOpts = append(clientOpts, github.WithCache(dir, opts.CacheTTL))
+		}
+		gh := github.NewClient(opts.Token, clientOpts...)
+		client, vulnChecker = gh, gh
+	default:
+		return nil, fmt.Errorf("unsupported provider: %q", opts.Provider)
+	}
+
+	serviceOpts := []analyze.Option{
+		analyze.WithMaxCommitDetails(opts.MaxCommitDetails),
+		analyze.WithLocation(opts.Location),
+		analyze.WithLogger(logger),
+		analyze.WithClock(clock),
+		analyze.WithStalePRDays(opts.StalePRDays),
+		analyze.WithTopFiles(opts.TopFiles),
+		analyze.WithTodoScan(opts.TodoScanMaxFiles),
+		analyze.WithCategoryWeights(opts.CategoryWeights),
+		analyze.WithFailureLabels(opts.FailureLabels),
+		analyze.WithBranchPrefixes(opts.BranchPrefixes),
+		analyze.WithNonSourceExtensions(opts.NonSourceExts),
+		analyze.WithTestFilePatterns(opts.TestFilePatterns),
+		analyze.WithLang(opts.Lang),
+		analyze.WithPath(opts.Path),
+		analyze.WithBranch(opts.Branch),
+		analyze.WithDeploySource(opts.DeploySource, opts.DeployWorkflow),
+		analyze.WithMTTRSource(opts.MTTRSource),
+		analyze.WithOwnershipSource(opts.OwnershipSource),
+		analyze.WithProgress(opts.Progress),
+		analyze.WithQuickMode(opts.Quick),
+		analyze.WithIncludeDrafts(opts.IncludeDrafts),
+		analyze.WithCoAuthorshipMitigation(opts.CoAuthorshipMitigation),
+	}
+	if opts.Penalties != nil {
+		serviceOpts = append(serviceOpts, analyze.WithSeverityPenalties(*opts.Penalties))
+	}
+	if opts.DORABands != nil {
+		serviceOpts = append(serviceOpts, analyze.WithDORARatingBands(*opts.DORABands))
+	}
+	if opts.IgnoreFile != nil {
+		serviceOpts = append(serviceOpts, analyze.WithIgnoreFile(*opts.IgnoreFile))
+	}
+	if opts.CheckVulns {
+		serviceOpts = append(serviceOpts, analyze.WithVulnerabilityCheck(vulnChecker))
+	}
+
+	a := &Analyzer{
+		service:  analyze.NewService(client, serviceOpts...),
+		period:   resolvePeriod(opts, clock()),
+		baseline: opts.Baseline,
+		logger:   logger,
+	}
+	a.historyPeriods = historyPeriods(a.period, opts.History, opts.HistoryWindow)
+	return a, nil
+}
+
+// resolvePeriod は分析期間を決める（Period の指定があればそのまま使う）。
+// キャッシュ有効時は期間の終わりを TTL 単位に丸め、TTL 内の再実行で同じ URL になるようにする。
+func resolvePeriod(opts Options, now time.Time) domain.DateRange {
+	if opts.Period != nil {
+		return *opts.Period
+	}
+	days := opts.Days
+	if days <= 0 {
+		days = DefaultDays
+	}
+	if opts.CacheTTL > 0 {
+		now = now.Truncate(opts.CacheTTL)
+	}
+	return domain.NewDateRange(now.AddDate(0, 0, -days), now)
+}
+
+// historyPeriods は period の終わりからさかのぼって連続する n 個の期間を古い順に返す。
+// 各期間の長さは windowDays 日（0 なら period と同じ長さ）。n が 2 未満なら推移を出さないため nil。
+func historyPeriods(period domain.DateRange, n, windowDays int) []domain.DateRange {
+	if n < 2 {
+		return nil
+	}
+	periods := make([]domain.DateRange, n)
+	to := period.To
+	for i := n - 1; i >= 0; i-- {
+		from := to.Add(-period.To.Sub(period.From))
+		if windowDays > 0 {
+			from = to.AddDate(0, 0, -windowDays)
+		}
+		periods[i] = domain.NewDateRange(from, to)
+		to = from
+	}
+	return periods
+}
+
+// Period は分析期間を返す。
+func (a *Analyzer) Period() domain.DateRange {
+	return a.period
+}
+
+// Analyze は1リポジトリを分析する。Baseline の指定があれば比較結果も付け、
+// History の指定があれば期間をずらして分析したスコアの推移も付ける。
+func (a *Analyzer) Analyze(ctx context.Context, repo domain.Repository) (*domain.AnalysisResult, error) {
+	result, err := a.service.Analyze(ctx, analyze.ServiceInput{
+		Repository: repo,
+		Period:     a.period,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if a.baseline != nil {
+		result.Baseline = a.baseline.Compare(result)
+		if result.Baseline == nil {
+			a.logger.Warn("repository not found in baseline", "repo", repo.FullName())
+		}
+	}
+
+	if len(a.historyPeriods) > 0 {
+		history, err := a.analyzeHistory(ctx, repo, result)
+		if err != nil {
+			return nil, err
+		}
+		result.History = history
+	}
+	return result, nil
+}
+
+// analyzeHistory は historyPeriods の各期間を分析してスコアの推移を返す。
+//
+// 分析期間と同じ期間は result をそのまま使う。期間ごとの分析はリポジトリ全体の取得
+// （ファイルツリー・依存など）を繰り返すため、キャッシュ（CacheTTL）と組み合わせて使う想定。
+// ファイルツリー・依存などは過去の時点を指定して取れないため、それ以外の期間は
+// 分析時点の状態で採点したものとして HistoryPoint.CurrentSnapshot を立てる。
+// 一部の期間の分析に失敗しても警告を出してその期間を飛ばす（キャンセルされた場合はエラーを返す）。
+func (a *Analyzer) analyzeHistory(ctx context.Context, repo domain.Repository, result *domain.AnalysisResult) ([]domain.HistoryPoint, error) {
+	var history []domain.HistoryPoint
+	for _, period := range a.historyPeriods {
+		r := result
+		past := !period.From.Equal(result.Period.From) || !period.To.Equal(result.Period.To)
+		if past {
+			var err error
+			r, err = a.service.Analyze(ctx, analyze.ServiceInput{Repository: repo, Period: period})
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, err
+				}
+				a.logger.Warn("history window analysis failed", "repo", repo.FullName(),
+					"from", period.From.Format(time.DateOnly), "to", period.To.Format(time.DateOnly), "error", err)
+				continue
+			}
+		}
+		point := domain.NewHistoryPoint(r)
+		point.CurrentSnapshot = past
+		history = append(history, point)
+	}
+	return history, nil
+}
+
+// Run は opts.Repository を分析して結果を返す。
+// opts.Output / opts.OutputDir を指定した場合はレポートも書き出す（書き出しに失敗したら結果とエラーを両方返す）。
+func Run(ctx context.Context, opts Options) (*domain.AnalysisResult, error) {
+	if opts.Repository.Owner == "" || opts.Repository.Name == "" {
+		return nil, errors.New("repository is required")
+	}
+	if opts.Output != "" && opts.OutputDir != "" {
+		return nil, errors.New("output and output directory cannot both be set")
+	}
+
+	a, err := NewAnalyzer(opts)
+	if err != nil {
+		return nil, err
+	}
+	result, err := a.Analyze(ctx, opts.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	format := opts.Format
+	if format == "" {
+		format = report.FormatHTML
+	}
+	reportService := report.NewService(report.WithLang(opts.Lang))
+	results := []*domain.AnalysisResult{result}
+	switch {
+	case opts.Output != "":
+		if _, err := reportService.GenerateAll(results, opts.Output, format); err != nil {
+			return result, fmt.Errorf("report generation failed: %w", err)
+		}
+	case opts.OutputDir != "":
+		if _, err := reportService.GenerateBundle(results, opts.OutputDir, format); err != nil {
+			return result, fmt.Errorf("report generation failed: %w", err)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,125 @@
+package lokup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ryuka-games/lokup/domain"
+)
+
+func TestResolvePeriod(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 35, 0, 0, time.UTC)
+	fixed := domain.NewDateRange(
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+	)
+
+	tests := []struct {
+		name string
+		opts Options
+		want domain.DateRange
+	}{
+		{"default days", Options{}, domain.NewDateRange(now.AddDate(0, 0, -DefaultDays), now)},
+		{"days", Options{Days: 7}, domain.NewDateRange(now.AddDate(0, 0, -7), now)},
+		{"explicit period wins", Options{Period: &fixed, Days: 7, CacheTTL: time.Hour}, fixed},
+		{
+			"cache truncates the end",
+			Options{Days: 7, CacheTTL: time.Hour},
+			domain.NewDateRange(now.Truncate(time.Hour).AddDate(0, 0, -7), now.Truncate(time.Hour)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolvePeriod(tt.opts, now)
+			if !got.From.Equal(tt.want.From) || !got.To.Equal(tt.want.To) {
+				t.Errorf("resolvePeriod() = %v ~ %v, want %v ~ %v", got.From, got.To, tt.want.From, tt.want.To)
+			}
+		})
+	}
+}
+
+func TestNewAnalyzer_Clock(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 35, 0, 0, time.UTC)
+	a, err := NewAnalyzer(Options{Days: 7, Clock: func() time.Time { return now }})
+	if err != nil {
+		t.Fatalf("NewAnalyzer() error = %v", err)
+	}
+	if got := a.Period(); !got.From.Equal(now.AddDate(0, 0, -7)) || !got.To.Equal(now) {
+		t.Errorf("Period() = %v ~ %v, want 7 days up to the injected clock %v", got.From, got.To, now)
+	}
+}
+
+func TestHistoryPeriods(t *testing.T) {
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC) }
+	// 3/1〜3/31 は30日間
+	period := domain.NewDateRange(day(3, 1), day(3, 31))
+
+	tests := []struct {
+		name       string
+		n          int
+		windowDays int
+		want       [][2]time.Time
+	}{
+		{"disabled", 0, 0, nil},
+		{"single window is not a trend", 1, 0, nil},
+		{"same length as period", 3, 0, [][2]time.Time{
+			{time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), day(1, 30)}, {day(1, 30), day(3, 1)}, {day(3, 1), day(3, 31)},
+		}},
+		{"custom window", 2, 7, [][2]time.Time{
+			{day(3, 17), day(3, 24)}, {day(3, 24), day(3, 31)},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := historyPeriods(period, tt.n, tt.windowDays)
+			if len(got) != len(tt.want) {
+				t.Fatalf("historyPeriods() = %d periods, want %d", len(got), len(tt.want))
+			}
+			for i, w := range tt.want {
+				if !got[i].From.Equal(w[0]) || !got[i].To.Equal(w[1]) {
+					t.Errorf("period[%d] = %v ~ %v, want %v ~ %v", i, got[i].From, got[i].To, w[0], w[1])
+				}
+			}
+		})
+	}
+}
+
+func TestRun_RequiresRepository(t *testing.T) {
+	if _, err := Run(context.Background(), Options{}); err == nil {
+		t.Error("Run() without repository: want error")
+	}
+}
+
+func TestRun_OutputAndOutputDir(t *testing.T) {
+	_, err := Run(context.Background(), Options{
+		Repository: domain.NewRepository("facebook", "react"),
+		Output:     "report.html",
+		OutputDir:  "reports",
+	})
+	if err == nil {
+		t.Error("Run() with both Output and OutputDir: want error")
+	}
+}
+
+func TestParseProvider(t *testing.T) {
+	for in, want := range map[string]Provider{"": ProviderGitHub, "github": ProviderGitHub, "GitLab": ProviderGitLab} {
+		got, err := ParseProvider(in)
+		if err != nil || got != want {
+			t.Errorf("ParseProvider(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseProvider("bitbucket"); err == nil {
+		t.Error("ParseProvider(bitbucket): want error")
+	}
+}
+
+func TestNewAnalyzer_GitLab(t *testing.T) {
+	if _, err := NewAnalyzer(Options{Provider: ProviderGitLab}); err != nil {
+		t.Errorf("NewAnalyzer(gitlab) error = %v", err)
+	}
+	// GitLab では依存を取得しないため、脆弱性の確認は組み立ての時点で断る
+	if _, err := NewAnalyzer(Options{Provider: ProviderGitLab, CheckVulns: true}); err == nil {
+		t.Error("NewAnalyzer(gitlab, CheckVulns) error = nil, want error")
+	}
+}
@@ -0,0 +1,683 @@
+package i18n
+
+// english は英語のメッセージカタログ。
+// 書式付きのメッセージは原文と同じ順序・同じ種類の verb を使う。
+var english = map[string]string{
+	// ── リスク種別（domain.RiskType.DisplayName）──────────────────
+	"変更集中リスク":       "Change concentration",
+	"巨大ファイル":        "Large files",
+	"属人化":           "Knowledge silo",
+	"バス係数リスク":       "Bus factor",
+	"依存の古さ":         "Outdated dependencies",
+	"脆弱性のある依存":      "Vulnerable dependencies",
+	"深夜労働":          "Late-night work",
+	"週末労働":          "Weekend work",
+	"PRリードタイム超過":    "Slow PR lead time",
+	"滞留PR":          "Stale PRs",
+	"PR放棄率過多":       "High PR abandonment",
+	"レビュー待ち超過":      "Slow reviews",
+	"PRサイズ超過":       "Large PRs",
+	"PR未経由の直接プッシュ":  "Direct pushes",
+	"レビューカバレッジ不足":   "Low review coverage",
+	"セルフマージ":        "Self-merges",
+	"Issueクローズ率低下":  "Low issue close rate",
+	"バグ修正割合過多":      "High bug-fix ratio",
+	"デプロイ頻度不足":      "Low deploy frequency",
+	"変更失敗率過多":       "High change failure rate",
+	"復旧時間超過":        "Slow recovery",
+	"機能投資不足":        "Low feature investment",
+	"ライセンス未設定":      "Missing license",
+	"TODOコメント過多":    "Too many TODO comments",
+	"コミットメッセージ品質低下": "Low commit message quality",
+	"コミュニティファイル不足":  "Missing community files",
+
+	// ── 重大度・グレード ──────────────────────────────────
+	"低":   "Low",
+	"中":   "Medium",
+	"高":   "High",
+	"不明":  "Unknown",
+	"良好":  "Good",
+	"普通":  "Fair",
+	"要改善": "Needs improvement",
+	"危険":  "Critical",
+
+	// ── カテゴリ ──────────────────────────────────────
+	"開発速度":   "Velocity",
+	"コード品質":  "Code quality",
+	"技術的負債":  "Technical debt",
+	"チーム健全性": "Team health",
+
+	// ── 単位・経過期間 ────────────────────────────────────
+	"日":                "d",
+	"時間":               "h",
+	"行":                " lines",
+	"回/月":              "/mo",
+	"時":                "h",
+	"%dヶ月":             "%d mo",
+	"%d年":              "%d yr",
+	"%d年%dヶ月":          "%d yr %d mo",
+	"%d件":              "%d items",
+	"%v件":              "%v",
+	"%v人":              "%v",
+	"%v行":              "%v lines",
+	"%.1f日":            "%.1f d",
+	"%.2f/日":           "%.2f/day",
+	"%.1f/月":           "%.1f/mo",
+	"%v件 (%.0f%%)":     "%v (%.0f%%)",
+	"%vファイル / %v人":     "%v files / %v people",
+	"%.1f日（中央値 %.1f日）": "%.1f d (median %.1f d)",
+	"%.1f/月 (%v)":      "%.1f/mo (%v)",
+
+	// ── リスクの説明・対象（analyze）─────────────────────────
+	"リポジトリ全体":   "Entire repository",
+	"デフォルトブランチ": "Default branch",
+	"1人のコントリビューターがコミットの大部分を占めています":                    "A single contributor accounts for most of the commits",
+	"%s だけがオーナーのディレクトリがリポジトリの%d%%を占めています":             "Directories owned solely by %s make up %d%% of the repository",
+	"ライセンスファイル（LICENSE / COPYING）が見つかりません":            "No license file (LICENSE / COPYING) found",
+	"TODO / FIXME / HACK コメントが%d件（1000行あたり%.1f件）あります": "%d TODO / FIXME / HACK comments (%.1f per 1,000 lines)",
+	"コミュニティヘルスファイルが不足しています: %s":                       "Missing community health files: %s",
+	"深夜のコミットが多いです":                                    "Many commits are made late at night",
+	"週末のコミットが多いです":                                    "Many commits are made on weekends",
+	"%dKB以上の巨大ファイルがあります":                              "There are files of %dKB or more",
+	"%dKB以上の大きいファイルがあります":                             "There are files of %dKB or more",
+	"%d年以上前の古い依存があります":                                "There are dependencies more than %d years old",
+	"%d日以上オープンのままのPRが%d件あります":                         "%d+ days open: %d pull requests",
+	"PRを経由しないコミットが%d件中%d件あります":                        "Of %d commits, %d did not go through a pull request",
+	"PRリードタイムが平均%.1f日です":                              "Average PR lead time is %.1f days",
+	"レビュー待ち時間が平均%.1f時間です":                             "Average review wait time is %.1f hours",
+	"PRの平均サイズが%d行です":                                  "Average PR size is %d lines",
+	"レビュー済みでマージされたPRが%.1f%%です":                        "%.1f%% of merged PRs were reviewed",
+	"作成者以外の承認なしでマージされたPRが%d件（%.1f%%）です":               "%d PRs (%.1f%%) were merged without approval from someone other than the author",
+	"マージされずにクローズされたPRが%d件（%.1f%%）です":                  "%d PRs (%.1f%%) were closed without being merged",
+	"Issueクローズ率が%.1f%%です":                             "Issue close rate is %.1f%%",
+	"バグ修正PRの割合が%.1f%%です":                              "Bug-fix PRs make up %.1f%%",
+	"デプロイ頻度が月%.1f回です":                                 "Deploy frequency is %.1f per month",
+	"変更失敗率が%.1f%%です":                                  "Change failure rate is %.1f%%",
+	"平均復旧時間が%.1f時間です":                                 "Mean time to recovery is %.1f hours",
+	"機能追加PRの割合が%.1f%%です":                              "Feature PRs make up %.1f%%",
+
+	// ── スコア内訳（analyze）─────────────────────────────
+	"基本スコア": "Base score",
+	"22-5時のコミットが%d%%、基準%d%%以下":      "%d%% of commits between 22:00 and 5:00, target %d%% or less",
+	"土日のコミットが%d%%、基準%d%%未満":         "%d%% of commits on weekends, target below %d%%",
+	"1人で%d%%のコミット、基準%d%%以下":         "%d%% of commits by one person, target %d%% or less",
+	"単独オーナーの範囲がリポジトリの%d%%、基準%d%%未満": "Single-owner area is %d%% of the repository, target below %d%%",
+	"%d種類が不足、全%d種類":                 "%d missing out of %d",
+	"%d回変更、基準%d回以下":                 "%d changes, target %d or fewer",
+	"%d件、%dKB以上":                    "%d files of %dKB or more",
+	"%d件、%d年以上前":                    "%d packages older than %d years",
+	"%d件、既知の脆弱性あり":                  "%d packages with known vulnerabilities",
+	"既知の脆弱性がある依存があります: %s":          "Dependencies with known vulnerabilities: %s",
+	" 他%d件": " and %d more",
+	"1000行あたり%.1f件、基準%d件以下": "%.1f per 1,000 lines, target %d or fewer",
+	"平均%.1f日、基準%d日以下":       "Average %.1f days, target %d days or less",
+	"平均%.1f時間、基準%d時間以下":     "Average %.1f hours, target %d hours or less",
+	"滞留PR%d件、基準%d件以下":       "%d stale PRs, target %d or fewer",
+	"放棄%d%%、基準%d%%以下":       "%d%% abandoned, target %d%% or less",
+	"平均%d行、基準%d行以下":         "Average %d lines, target %d lines or less",
+	"PR未経由%d%%、基準%d%%以下":    "%d%% without a PR, target %d%% or less",
+	"レビュー済み%d%%、基準%d%%以上":   "%d%% reviewed, target %d%% or more",
+	"セルフマージ%d%%、基準%d%%以下":   "%d%% self-merged, target %d%% or less",
+	"低品質メッセージ%d%%、基準%d%%以下": "%d%% low-quality messages, target %d%% or less",
+	"クローズ率%d%%、基準%d%%以上":    "Close rate %d%%, target %d%% or more",
+	"バグ修正%d%%、基準%d%%以下":     "%d%% bug fixes, target %d%% or less",
+	"月%.1f回、基準月%.1f回以上":     "%.1f per month, target %.1f per month or more",
+	"失敗率%d%%、基準%d%%以下":      "Failure rate %d%%, target %d%% or less",
+	"平均%.1f時間、基準%.1f時間以下":   "Average %.1f hours, target %.1f hours or less",
+	"機能追加%d%%、基準%d%%以上":     "%d%% features, target %d%% or more",
+	"%d / 基準%d":             "%d / target %d",
+
+	// ── カテゴリ診断（analyze）───────────────────────────
+	"良好な状態です": "In good shape",
+	"PRリードタイムが長く、開発速度が低下しています":             "Long PR lead times are slowing development",
+	"レビュー待ち時間が長く、フィードバックが遅延しています":          "Long review waits are delaying feedback",
+	"長期間オープンのままのPRが滞留し、作業が止まっています":         "Long-open PRs are piling up and work is stalled",
+	"特定ファイルへの変更が集中しており、品質リスクがあります":         "Changes concentrate on a few files, which is a quality risk",
+	"PRサイズが大きく、レビューの質が低下する可能性があります":        "Large PRs may lower review quality",
+	"PRを経由しない変更が多く、レビューされずに取り込まれています":      "Many changes bypass pull requests and land without review",
+	"レビューを受けずにマージされるPRが多く、品質チェックが抜けています":   "Many PRs are merged without review, skipping quality checks",
+	"作成者以外の承認なしにマージされるPRが多く、統制が効いていません":    "Many PRs are merged without approval from someone other than the author",
+	"Issueの消化が追いつかず、負債が蓄積しています":            "Issues are not being closed fast enough and debt is accumulating",
+	"バグ修正の割合が高く、品質に課題があります":                "A high share of bug fixes points to quality problems",
+	"巨大ファイルが多数あり、保守性に課題があります":              "Many large files hurt maintainability",
+	"古い依存パッケージがあり、セキュリティリスクがあります":          "Outdated dependencies pose a security risk",
+	"既知の脆弱性がある依存パッケージを使っています":              "Dependencies with known vulnerabilities are in use",
+	"ライセンスが明示されておらず、利用・配布の条件が不明確です":        "No license is declared, so the terms of use and distribution are unclear",
+	"TODO コメントが多く、先送りした作業が溜まっています":         "Many TODO comments point to a growing pile of deferred work",
+	"貢献方法や脆弱性の報告窓口が示されておらず、外部から参加しにくい状態です": "There is no guidance on contributing or reporting vulnerabilities, which makes it hard for outsiders to take part",
+	"深夜作業が多く、チームの持続可能性に懸念があります":            "Frequent late-night work raises sustainability concerns",
+	"週末の作業が常態化しており、十分に休めていない可能性があります":      "Weekend work has become routine; the team may not be getting enough rest",
+	"知識が特定の人に偏っており、属人化リスクがあります":            "Knowledge is concentrated in a few people",
+	"副担当のいないディレクトリが大きく、担当者の不在に弱い状態です":      "Large areas have no backup owner and are vulnerable to absences",
+	"デプロイ頻度が低く、価値提供のスピードが遅れています":           "Infrequent deploys slow down value delivery",
+	"変更失敗率が高く、リリース品質に課題があります":              "A high change failure rate points to release quality problems",
+	"障害からの復旧時間が長く、運用に課題があります":              "Slow recovery from incidents points to operational problems",
+	"機能追加への投資比率が低く、負債対応に追われています":           "Low investment in features; the team is busy paying down debt",
+	"マージされずに閉じられるPRが多く、作業が無駄になっています":       "Many PRs are closed without being merged, wasting effort",
+	"改善の余地があります": "There is room for improvement",
+
+	// ── トレンド・ベースライン ─────────────────────────────
+	"コミット数":      "Commits",
+	"コミット頻度":     "Commit frequency",
+	"Issueクローズ率": "Issue close rate",
+	"総合スコア":      "Overall score",
+	"平均リードタイム":   "Avg lead time",
+	"平均レビュー待ち時間": "Avg review wait",
+	"平均PRサイズ":    "Avg PR size",
+	"バグ修正率":      "Bug-fix ratio",
+	"レビューカバレッジ":  "Review coverage",
+	"デプロイ頻度":     "Deploy frequency",
+	"変更失敗率":      "Change failure rate",
+	"平均復旧時間":     "Mean time to recovery",
+	"深夜コミット率":    "Late-night commit rate",
+	"▲ 改善":       "▲ Improved",
+	"▼ 悪化":       "▼ Regressed",
+	"→ 変化なし":     "→ No change",
+
+	// ── 改善提案（report）─────────────────────────────────
+	"このファイルの責務を分割することを検討してください。頻繁な変更はバグの温床になります。":                   "Consider splitting this file's responsibilities. Frequent changes breed bugs.",
+	"ファイルを機能ごとに分割してください。大きなファイルは可読性と保守性を下げます。":                      "Split the file by feature. Large files hurt readability and maintainability.",
+	"コードレビューやペアプログラミングで知識を共有してください。担当者が離脱するとリスクになります。":              "Share knowledge through code review and pair programming. Losing the owner is a risk.",
+	"CODEOWNERS に副担当を追加し、レビューを通じて担当範囲の知識を共有してください。":                 "Add backup owners to CODEOWNERS and share knowledge of their areas through reviews.",
+	"依存パッケージを更新してください。古いバージョンにはセキュリティ脆弱性がある可能性があります。":               "Update dependencies. Old versions may contain security vulnerabilities.",
+	"脆弱性の修正済みバージョンに更新してください。更新できない場合は、各アドバイザリで影響範囲と回避策を確認してください。":   "Update to a version that fixes the vulnerability. If you cannot, check each advisory for impact and workarounds.",
+	"リポジトリのルートに LICENSE を追加し、利用条件を明示してください。":                        "Add a LICENSE to the repository root to state the terms of use.",
+	"TODO コメントを棚卸しし、対応するものは Issue に起こして期限を決め、不要なものは削除してください。":       "Review the TODO comments: turn the ones worth doing into issues with a due date and delete the rest.",
+	"CONTRIBUTING.md や SECURITY.md などを整備し、貢献の手順と脆弱性の報告窓口を明示してください。": "Add CONTRIBUTING.md, SECURITY.md and the like to document how to contribute and where to report vulnerabilities.",
+	"開発環境の準備・ブランチ運用・PRの出し方をまとめた CONTRIBUTING.md を追加してください。":         "Add a CONTRIBUTING.md covering development setup, branching and how to open a PR.",
+	"脆弱性の非公開の報告先と対応方針を SECURITY.md に書いてください。":                       "Document a private channel for reporting vulnerabilities and your response policy in SECURITY.md.",
+	"バグ報告・機能要望の Issue テンプレートを .github/ISSUE_TEMPLATE/ に追加してください。":   "Add bug report and feature request templates under .github/ISSUE_TEMPLATE/.",
+	"変更内容・動作確認・関連 Issue を書く欄のある PR テンプレートを追加してください。":                "Add a PR template with sections for the change, how it was tested and related issues.",
+	"深夜作業が多い原因を調査してください。締め切り圧力やリソース不足の兆候かもしれません。":                   "Investigate why late-night work is common. It may signal deadline pressure or understaffing.",
+	"週末作業が続く原因を調査し、リリース日程や障害対応の当番体制を見直してください。":                      "Investigate why weekend work keeps happening, and revisit release schedules and on-call rotations.",
+	"PRを小さく分割し、レビュー担当をローテーションで明確化してください。":                           "Split PRs into smaller pieces and assign reviewers on a clear rotation.",
+	"長期間動きのないPRを棚卸しし、マージ・クローズ・担当の再割り当てを決めてください。":                    "Review long-idle PRs and decide whether to merge, close, or reassign them.",
+	"着手前にIssueで方針を合意し、不要になったPRは早めに閉じて理由を残してください。":                   "Agree on the approach in an issue before starting, and close PRs that are no longer needed early with a reason.",
+	"レビュー時間をカレンダーで確保し、Slackへの通知など見逃さない仕組みを導入してください。":                "Block time for reviews on the calendar and add notifications (e.g. Slack) so none are missed.",
+	"1つのPRで1つの機能/修正に絞り、リファクタリングと機能追加を分けてください。":                      "Keep each PR to one feature or fix, and separate refactoring from feature work.",
+	"ブランチ保護ルールでデフォルトブランチへの直接プッシュを禁止し、PRとレビューを必須にしてください。":            "Use branch protection to block direct pushes to the default branch and require PRs and reviews.",
+	"ブランチ保護ルールで承認レビューを必須にし、セルフマージの運用を見直してください。":                     "Require approving reviews with branch protection and revisit how self-merges are handled.",
+	"ブランチ保護ルールで作成者以外の承認を1件以上必須にし、管理者によるバイパスも制限してください。":              "Require at least one approval from someone other than the author, and restrict admin bypasses.",
+	"定期的なトリアージミーティングで優先度を整理し、対応しないものは wontfix でクローズしてください。":         "Hold regular triage meetings to set priorities, and close issues you won't address as wontfix.",
+	"テストを充実させてバグを事前に防ぎ、コードレビューの品質を上げてください。":                         "Strengthen tests to catch bugs early and raise the quality of code reviews.",
+	"CI/CDパイプラインを整備し、小さなリリースを頻繁に行う文化を構築してください。":                     "Build a CI/CD pipeline and a culture of small, frequent releases.",
+	"リリース前のテスト自動化とステージング環境での検証を強化してください。":                           "Strengthen pre-release test automation and verification in staging.",
+	"インシデント対応プロセスを整備し、ロールバック手順を自動化してください。":                          "Establish an incident response process and automate rollbacks.",
+	"技術的負債の計画的な返済とともに、機能開発への投資バランスを見直してください。":                       "Pay down technical debt on a plan and rebalance investment toward feature work.",
+	"詳細を確認し、改善策を検討してください。":                                          "Review the details and consider how to improve.",
+
+	// ── 総合診断（report）─────────────────────────────────
+	"全体的に良好な状態です。":               "Overall in good shape.",
+	"概ね良好ですが、%sに改善の余地があります。":     "Mostly good, but %s has room for improvement.",
+	"%sを中心に改善が必要です。":             "Improvement is needed, especially in %s.",
+	"%sに重大な課題があります。早急な対応を推奨します。": "%s has serious problems. Prompt action is recommended.",
+	"診断データがありません。":               "No diagnosis data.",
+
+	// ── Markdown ────────────────────────────────────
+	"**総合グレード: %s**（%d / 100・%s）\n\n": "**Overall grade: %s** (%d / 100, %s)\n\n",
+	"分析期間: %s 〜 %s（%d日間）\n\n":         "Period: %s to %s (%d days)\n\n",
+	"| カテゴリ | スコア | グレード | 診断 |\n":    "| Category | Score | Grade | Diagnosis |\n",
+	"### DORA メトリクス\n\n":              "### DORA metrics\n\n",
+	"| 指標 | 値 | 評価 |\n":               "| Metric | Value | Rating |\n",
+	"| デプロイ頻度 | %.1f 回/月 | %s |\n":    "| Deploy frequency | %.1f /mo | %s |\n",
+	"| 変更失敗率 | %.1f%% | %s |\n":       "| Change failure rate | %.1f%% | %s |\n",
+	"| 平均復旧時間 | %.1f 時間 | %s |\n":     "| Mean time to recovery | %.1f h | %s |\n",
+	"### ベースライン比較（%s 生成）\n\n":         "### Baseline comparison (generated %s)\n\n",
+	"| 項目 | ベースライン | 今回 | 差 | 判定 |\n": "| Item | Baseline | Current | Delta | Status |\n",
+	"### スコアの推移\n\n":                  "### Score history\n\n",
+	"| 期間 | 総合スコア | グレード |\n":         "| Period | Overall score | Grade |\n",
+	"### 検出されたリスク（%d件）\n\n":           "### Detected risks (%d)\n\n",
+	"重大なリスクは検出されませんでした。\n":            "No significant risks detected.\n",
+	"- ほか %d 件\n":                     "- and %d more\n",
+
+	// スコアの推移の注記（Markdown / HTML）
+	"※ の期間は、ファイルツリー・依存・ブランチ保護・ライセンス・コミュニティファイルを期間当時ではなく現在の状態で採点しています（期間ごとに変わるのはコミット・PR・Issue などの活動の指標だけです）。\n\n": "Windows marked ※ are scored with the current file tree, dependencies, branch protection, license and community files rather than their state at the time (only activity metrics such as commits, PRs and issues change per window).\n\n",
+	"※ の期間は、ファイルツリー・依存・ブランチ保護・ライセンス・コミュニティファイルを期間当時ではなく現在の状態で採点しています（期間ごとに変わるのはコミット・PR・Issue などの活動の指標だけです）。":     "Windows marked ※ are scored with the current file tree, dependencies, branch protection, license and community files rather than their state at the time (only activity metrics such as commits, PRs and issues change per window).",
+
+	// ── HTML レポート ─────────────────────────────────
+	"Lokup レポート - %v":               "Lokup report - %v",
+	"Lokup 統合レポート（%dリポジトリ）":         "Lokup combined report (%d repositories)",
+	"統合レポート":                        "Combined report",
+	"GitHub リポジトリ健康診断レポート":          "GitHub repository health check report",
+	"GitHub リポジトリ健康診断レポート（%dリポジトリ）": "GitHub repository health check report (%d repositories)",
+	"Lokup - GitHub リポジトリ健康診断ツール":   "Lokup - GitHub repository health check tool",
+
+	// ── ロールアップ（組織全体の概要）─────────────────────────
+	"Lokup ロールアップ（%dリポジトリ）": "Lokup rollup (%d repositories)",
+	"ロールアップ":                "Rollup",
+	"組織全体の概要（%dリポジトリ）":      "Organization overview (%d repositories)",
+	"平均スコア":                 "Average score",
+	"%dリポジトリ":               "%d repos",
+	"どのリポジトリも分析期間内の活動がなく、スコアを算出できませんでした。":      "No repository had any activity in the period, so no score was calculated.",
+	"データ不足でスコアを算出していない%dリポジトリは、平均と分布に含めていません。": "%d repositories with insufficient data are not included in the average and distribution.",
+	"スコアの低いリポジトリ":           "Lowest-scoring repositories",
+	"高リスク":                  "High risks",
+	"DORA メトリクス（組織全体）":      "DORA metrics (organization-wide)",
+	"レーティングの付いたリポジトリがありません": "No repository has a rating",
+	"値はレーティングの付いたリポジトリの平均です（データがない・期間が短いリポジトリは除く）。": "Values are averages over rated repositories (repositories without data or with too short a period are excluded).",
+	"多くのリポジトリで検出されたリスク": "Most common risks across repositories",
+	"リポジトリ数":               "Repositories",
+	"（高 %d件）":              "(%d high)",
+	"分析期間: %v ~ %v (%v日間)": "Period: %v ~ %v (%v days)",
+	"生成日時: %v":             "Generated: %v",
+	"総合スコア: %v / 100":      "Overall score: %v / 100",
+	"総合スコア<br>%v / 100":    "Overall score<br>%v / 100",
+	"グレード %v":              "Grade %v",
+	"リポジトリ一覧":              "Repositories",
+	"リポジトリ":                "Repository",
+	"総合":                   "Overall",
+	"リスク":                  "Risk",
+	"主要メトリクス":              "Key metrics",
+	"検出されたリスク（%d件）":        "Detected risks (%d)",
+	"重大なリスクは検出されませんでした。": "No significant risks detected.",
+	"（対象: %s）":  " (target: %s)",
+	"対象:":       "Target:",
+	"スコア内訳":     "Score breakdown",
+	"診断":        "Diagnosis",
+	"放置すると？":    "If left alone?",
+	"改善提案":      "Suggestions",
+	"PRリードタイム":  "PR lead time",
+	"レビュー待ち時間":  "Review wait time",
+	"コントリビューター": "Contributors",
+	"深夜労働率":     "Late-night work rate",
+	"PR作成からマージまでの平均日数は <strong>%.1f日</strong> です。基準: 3日以下が良好 / 7日以上で警告。":                                    "The average time from PR creation to merge is <strong>%.1f days</strong>. Target: 3 days or less is good / 7 days or more is a warning.",
+	"中央値 <strong>%.1f日</strong>（75%%のPRが %.1f日以内、90%%のPRが %.1f日以内）。平均と中央値の差が大きい場合は、一部の長期化したPRが平均を押し上げています。": "Median <strong>%.1f days</strong> (75%% of PRs within %.1f days, 90%% within %.1f days). A large gap between mean and median means a few long-running PRs are pulling the average up.",
+	"PR別リードタイム":            "Lead time by PR",
+	"コンフリクトが頻発する":          "Merge conflicts become frequent",
+	"開発者のモチベーションが低下":       "Developer motivation drops",
+	"コンテキストスイッチが増える":       "Context switching increases",
+	"PRを小さく分割する（200行以下目標）": "Split PRs into smaller pieces (aim for 200 lines or less)",
+	"レビュー担当をローテーションで明確化":   "Assign reviewers on a clear rotation",
+	"レビュー時間をカレンダーで確保":      "Block time for reviews on the calendar",
+	"1日あたり平均 <strong>%.2f</strong> コミットです。基準: 2以上が活発 / 0.5未満で低調。期間中の総コミット数: %v件。": "<strong>%.2f</strong> commits per day on average. Target: 2 or more is active / below 0.5 is slow. Total commits in the period: %v.",
+	"日別コミット推移":               "Daily commits",
+	"極端に少ない場合: 開発が停滞している可能性": "Very few: development may be stalled",
+	"急激な増加: リリース前の駆け込みかも":    "Sudden spike: possibly a pre-release rush",
+	"週末に多い: 過負荷の兆候":          "Many on weekends: a sign of overload",
+	"PR作成から最初のレビューまでの平均時間は <strong>%.1f時間</strong> です。基準: 24h以内が良好 / 48h以上で警告。": "The average time from PR creation to first review is <strong>%.1f hours</strong>. Target: within 24h is good / 48h or more is a warning.",
+	"コメントを含めた最初の反応（作成者以外のレビューかコメント）までの平均時間は <strong>%.1f時間</strong> です。":        "Including comments, the average time to the first response (a review or comment by someone other than the author) is <strong>%.1f hours</strong>.",
+	"PR別レビュー待ち時間":          "Review wait by PR",
+	"レビュー担当者をPR作成時に指定":     "Assign reviewers when opening the PR",
+	"Slackへの通知で見逃し防止":      "Send Slack notifications so nothing is missed",
+	"毎日のレビュータイムを確保（朝一番など）": "Set aside daily review time (e.g. first thing in the morning)",
+	"オープン PR/Issue":        "Open PRs/issues",
+	"現在オープン中: PR <strong>%v件</strong> / Issue <strong>%v件</strong>。滞留タスクの量を示します。": "Currently open: <strong>%v</strong> PRs / <strong>%v</strong> issues. This shows the amount of pending work.",
+	"週次でオープン数をモニタリング":      "Monitor open counts weekly",
+	"古いPR/Issueは定期的にトリアージ": "Triage old PRs/issues regularly",
+	"優先度ラベルを活用して整理":        "Use priority labels to organize",
+	"デプロイ頻度 (DORA)":        "Deploy frequency (DORA)",
+	"期間中のデプロイ頻度は <strong>月%.1f回</strong> です。DORAレーティング: <strong>%v</strong>（Elite: 毎日 / High: 週1回 / Medium: 月1回 / Low: 月1回未満）": "Deploy frequency in the period is <strong>%.1f per month</strong>. DORA rating: <strong>%v</strong> (Elite: daily / High: weekly / Medium: monthly / Low: less than monthly)",
+	"CI/CDパイプラインを整備して自動デプロイ": "Set up a CI/CD pipeline for automated deploys",
+	"小さなリリースを頻繁に行う文化を構築":     "Build a culture of small, frequent releases",
+	"フィーチャーフラグでリスクを軽減":       "Reduce risk with feature flags",
+	"平均復旧時間 (DORA)":          "Mean time to recovery (DORA)",
+	"障害からの平均復旧時間は <strong>%.1f時間</strong> です。DORAレーティング: <strong>%v</strong>（Elite: 1h未満 / High: 24h未満 / Medium: 1週間未満 / Low: 1週間以上）": "Mean time to recovery from incidents is <strong>%.1f hours</strong>. DORA rating: <strong>%v</strong> (Elite: under 1h / High: under 24h / Medium: under a week / Low: a week or more)",
+	"ロールバック手順を自動化する":    "Automate rollbacks",
+	"インシデント対応のランブックを整備": "Write incident response runbooks",
+	"障害検知の自動アラートを設定":    "Set up automatic alerts for failures",
+	"投資比率（PR分類）":        "Investment ratio (PR types)",
+	"マージ済みPRの投資比率: Feature <strong>%v件 (%.1f%%)</strong> / BugFix <strong>%v件 (%.1f%%)</strong> / Refactor <strong>%v件 (%.1f%%)</strong> / Other <strong>%v件</strong>。機能追加30%%以上が目安です。": "Investment ratio of merged PRs: Feature <strong>%v (%.1f%%)</strong> / BugFix <strong>%v (%.1f%%)</strong> / Refactor <strong>%v (%.1f%%)</strong> / Other <strong>%v</strong>. Aim for 30%% or more features.",
+	"PR種別内訳": "PRs by type",
+	"テストを充実させてバグを事前に防ぐ":      "Strengthen tests to catch bugs early",
+	"技術的負債の返済と機能開発のバランスを見直す": "Rebalance debt repayment and feature work",
+	"リファクタリングの時間を計画的に確保する":   "Plan dedicated time for refactoring",
+	"このほか、マージされずにクローズされたPRが <strong>%v件（クローズ済みPRの%.1f%%）</strong> あります。基準: 30%%以下が良好 / 50%%超は要対応。": "In addition, <strong>%v PRs (%.1f%% of closed PRs)</strong> were closed without being merged. Target: 30%% or less is healthy / over 50%% needs action.",
+	"大きな変更は着手前にIssueで方針を合意し、放棄されるPRを減らす":                                                           "Agree on large changes in an issue before starting to reduce abandoned PRs",
+	"変更失敗率 (DORA)": "Change failure rate (DORA)",
+	"変更失敗率は <strong>%.1f%%</strong> です。DORAレーティング: <strong>%v</strong>（Elite: 15%%以下 / High: 30%%以下 / Medium: 45%%以下 / Low: 45%%超）": "Change failure rate is <strong>%.1f%%</strong>. DORA rating: <strong>%v</strong> (Elite: 15%% or less / High: 30%% or less / Medium: 45%% or less / Low: over 45%%)",
+	"リリース前のテスト自動化を強化":  "Strengthen pre-release test automation",
+	"ステージング環境での検証を徹底":  "Verify thoroughly in staging",
+	"カナリアリリースでリスクを軽減":  "Reduce risk with canary releases",
+	"コードチャーン（Revert率）": "Code churn (revert rate)",
+	"Revertコミットが <strong>%v件</strong>（全体の%.1f%%）です。手戻りの多さを示します。": "<strong>%v</strong> revert commits (%.1f%% of all). This shows how much work is redone.",
+	"PRレビューの品質を向上させる":                                  "Improve the quality of PR reviews",
+	"自動テストのカバレッジを上げる":                                  "Increase automated test coverage",
+	"小さな変更を頻繁にリリースする":                                  "Release small changes frequently",
+	"変更集中（ホットスポット）":                                    "Change concentration (hotspots)",
+	"短期間に集中して変更されたファイルが <strong>%d件</strong> 検出されました。": "<strong>%d</strong> files were changed intensively over a short period.",
+	"ホットスポット一覧":                                        "Hotspots",
+	"ファイル":                                             "File",
+	"説明":                                               "Description",
+	"頻繁に変更されるファイルの責務を分割する":                             "Split responsibilities of frequently changed files",
+	"変更の原因を調査し、設計を見直す":                                 "Investigate why they change and revisit the design",
+	"PRあたりの平均変更行数は <strong>%v行</strong> です。基準: 200行以下が良好 / 500行以上で警告。": "The average PR changes <strong>%v lines</strong>. Target: 200 lines or less is good / 500 lines or more is a warning.",
+	"PR別変更行数": "Lines changed by PR",
+	"対象パス: %v（コントリビューター・Issue・リリース・オープンPR・依存はリポジトリ全体）":       "Path: %v (contributors, issues, releases, open PRs and dependencies are repository-wide)",
+	"対象パス: `%s`（コントリビューター・Issue・リリース・オープンPR・依存はリポジトリ全体）\n\n": "Path: `%s` (contributors, issues, releases, open PRs and dependencies are repository-wide)\n\n",
+	"対象ブランチ: %v（PR・Issue・リリース・依存はリポジトリ全体）":                   "Branch: %v (pull requests, issues, releases and dependencies are repository-wide)",
+	"対象ブランチ: `%s`（PR・Issue・リリース・依存はリポジトリ全体）\n\n":             "Branch: `%s` (pull requests, issues, releases and dependencies are repository-wide)\n\n",
+	"クイックモード: PRサイズ・レビュー・依存は計算していません":                        "Quick mode: PR size, review and dependency metrics were not computed",
+	"クイックモード: PRサイズ・レビュー・依存は計算していません\n\n":                    "Quick mode: PR size, review and dependency metrics were not computed\n\n",
+	"クイックモード（--quick）では計算していません。--quick を外して再実行すると取得します。":    "Not computed in quick mode (--quick). Run again without --quick to fetch it.",
+	"分析できるだけの活動がありません":                                       "Not enough activity to analyze",
+	"分析期間内にコミットもマージされたPRもないため、スコアは算出していません。リスクが検出されないのは健全だからではありません。期間を広げて（--days / --since）再実行してください。": "There were no commits or merged PRs in the analysis period, so no scores were calculated. No risks were detected, but that does not mean the repository is healthy. Widen the period (--days / --since) and run again.",
+	"データ不足": "Insufficient data",
+	"**データ不足**: 分析期間内にコミットもマージされたPRもないため、スコアは算出していません。\n\n":                    "**Insufficient data**: there were no commits or merged PRs in the analysis period, so no scores were calculated.\n\n",
+	"分析期間が%d日未満のため、月換算の頻度は参考値です。レーティングを出すには期間を広げて（--days / --since）再実行してください。": "The analysis period is shorter than %d days, so the monthly rate is only indicative. Widen the period (--days / --since) and run again to get a rating.",
+	"CI のステータスチェックの成功を必須にする":   "Require CI status checks to pass",
+	"ステータスチェックの成功は必須になっていません。": "Passing status checks are not required.",
+	"ステータスチェックの成功も必須です。":       "Passing status checks are also required.",
+	"デフォルトブランチ <strong>%s</strong> に保護ルールがありません。レビューなしの直接プッシュや force push ができる状態です。": "The default branch <strong>%s</strong> has no protection rule. Anyone with write access can push directly or force-push without review.",
+	"デフォルトブランチ <strong>%s</strong> は保護されています。マージに必要な承認: <strong>%v件</strong>":        "The default branch <strong>%s</strong> is protected. Approvals required to merge: <strong>%v</strong>.",
+	"デフォルトブランチにレビューを経ない変更が入り得る状態です":                                                  "Changes can reach the default branch without review",
+	"デフォルトブランチに保護ルール（または Ruleset）を設定":                                                "Add a protection rule (or ruleset) to the default branch",
+	"デフォルトブランチに保護ルール（または Ruleset）を設定し、マージ前の承認レビューとステータスチェックを必須にしてください。":              "Add a protection rule (or ruleset) to the default branch and require approving reviews and status checks before merging.",
+	"デフォルトブランチ（%s）にブランチ保護がありません":                                                     "The default branch (%s) has no branch protection",
+	"デフォルトブランチ（%s）の保護ルールでレビューが必須になっていません":                                            "The protection rule on the default branch (%s) does not require reviews",
+	"ブランチ保護":   "Branch protection",
+	"ブランチ保護なし": "No branch protection",
+	"ブランチ保護の設定を読めませんでした（リポジトリの管理者権限のないトークンでは取得できません）。": "Could not read the branch protection settings (they require a token with admin access to the repository).",
+	"マージ前に1件以上の承認レビューを必須にする":                           "Require at least one approving review before merging",
+	"承認%v件必須": "%v approvals required",
+	"承認不要":    "No approvals required",
+	"デフォルトブランチ（%s）への force push が%d回ありました": "Force pushes to the default branch (%s): %d",
+	"（実行者: %s）":      " (by %s)",
+	"force push %d回": "%d force pushes",
+	"履歴の書き換え":        "History rewrite",
+	"共有ブランチの履歴が書き換えられ、各自の作業と食い違いやすい状態です":                          "The shared branch history is being rewritten, so local work easily diverges from it",
+	"デフォルトブランチの保護ルールで force push を禁止し、取り消しは revert コミットで行ってください。": "Block force pushes with a protection rule on the default branch, and undo changes with revert commits instead.",
+	"入れてから24時間以内に Revert された変更が%d件あります":                           "%d changes were reverted within 24 hours of landing",
+	"24時間以内のRevert %d件": "%d reverted within 24h",
+	"直後の Revert":        "Fast revert",
+	"入れた直後に取り消される変更があり、レビューやテストで問題を防げていません":                                                                                          "Changes are reverted right after landing, so reviews and tests are not catching the problems",
+	"Revert された変更の原因を振り返り、同じ問題をレビューや CI のテストで止められるようにしてください。":                                                                        "Look back at why the reverted changes failed, and make sure reviews or CI tests would catch the same problem next time.",
+	"元のコミットから Revert までは平均 <strong>%.1f時間</strong>で、24時間以内に取り消された変更が <strong>%v件</strong> あります。入れた直後の取り消しは、リリース後にロールバックした失敗に近い兆候です。": "Reverts come on average <strong>%.1f hours</strong> after the original commit, and <strong>%v</strong> changes were reverted within 24 hours. Undoing a change right after it lands is close to a failed release rolled back.",
+	"最優先の改善（上位%d件）":         "Top %d recommended actions",
+	"変更回数の多い上位%d件を表示しています。": "Showing the top %d files by number of changes.",
+	"サイズの大きい上位%d件を表示しています。": "Showing the top %d files by size.",
+	"バイナリ・生成物（対象外）":         "Binary and generated files (excluded)",
+	"画像・ロックファイル・minify 済みのファイルなど %v件 はソースコードではないため巨大ファイルに数えていません。": "%v non-source files such as images, lock files and minified assets are not counted as large files.",
+	"変更回数":     "Changes",
+	"%d回":      "%d times",
+	"PRサイズの分布": "PR size distribution",
+	"平均だけでは、小さなPRと巨大なPRが混在していても見分けられません。": "The average alone cannot tell you when tiny and huge PRs are mixed together.",
+	"PR数": "PRs",
+	"1つのPRで1つの機能/修正に絞る":        "Keep each PR to one feature or fix",
+	"リファクタリングと機能追加を分ける":        "Separate refactoring from feature work",
+	"フィーチャーフラグで大きな機能を小分けにリリース": "Release large features in slices behind feature flags",
+	"直近のマージ済みPR %v件のうち、承認または変更要求のレビューを受けたものは <strong>%.1f%%</strong> です。基準: 80%%以上が良好 / 50%%未満は要対応。コメントだけのレビューは含みません。": "Of the %v most recently merged PRs, <strong>%.1f%%</strong> received an approving or changes-requested review. Target: 80%% or more is good / below 50%% needs action. Comment-only reviews are not counted.",
+	"期間中にレビュー情報を取得できたマージ済みPRがありません。": "No merged PRs with review data in the period.",
+	"ブランチ保護ルールで承認レビューを必須にする":         "Require approving reviews with branch protection",
+	"CODEOWNERS でレビュー担当を自動アサイン":      "Auto-assign reviewers with CODEOWNERS",
+	"緊急対応でのセルフマージは事後レビューをルール化":       "Require after-the-fact reviews for emergency self-merges",
+	"直近のマージ済みPR %v件のうち、作成者以外の承認がないままマージされたものは <strong>%v件（%.1f%%）</strong> です。基準: 20%%以下が良好 / 50%%超は要対応。": "Of the %v most recently merged PRs, <strong>%v (%.1f%%)</strong> were merged without approval from someone other than the author. Target: 20%% or less is good / over 50%% needs action.",
+	"ブランチ保護ルールで作成者以外の承認を必須にする": "Require approval from someone other than the author",
+	"管理者によるルールのバイパスを制限":        "Restrict admin bypasses of the rules",
+	"1人チームの場合は自動テストと事後レビューで補う": "For one-person teams, compensate with automated tests and later reviews",
+	"期間中の作成: <strong>%v件</strong> / クローズ: <strong>%v件</strong>（クローズ率 %.1f%%）。基準: 100%%以上が良好 / 50%%以下で警告。": "Created in the period: <strong>%v</strong> / closed: <strong>%v</strong> (close rate %.1f%%). Target: 100%% or more is good / 50%% or less is a warning.",
+	"作成 vs クローズ": "Created vs closed",
+	"定期的なトリアージミーティングで優先度を整理":                    "Set priorities in regular triage meetings",
+	"対応しないものは「wontfix」でクローズ":                    "Close issues you won't address as \"wontfix\"",
+	"100%以上を維持（消化が追いつく状態）":                      "Stay at 100% or more (keeping up with incoming issues)",
+	"50KB以上のファイルが <strong>%v件</strong> あります。":   "There are <strong>%v</strong> files of 50KB or more.",
+	"該当ファイル一覧":                                  "Files",
+	"サイズ":                                       "Size",
+	"責務ごとにファイルを分割する":                            "Split files by responsibility",
+	"共通処理を別モジュールに抽出":                            "Extract shared logic into separate modules",
+	"100KB以上は優先的に対応":                            "Prioritize files of 100KB or more",
+	"古い依存":                                      "Outdated dependencies",
+	"2年以上前の依存パッケージが <strong>%v件</strong> あります。": "There are <strong>%v</strong> dependencies more than 2 years old.",
+	"分析した依存 <strong>%v件</strong> のうち %.1f%% が古い依存です（リリース日を取得できた依存だけを数えています）。": "Of the <strong>%v</strong> analyzed dependencies, %.1f%% are outdated (only dependencies whose release date could be resolved are counted).",
+	"エコシステム別の依存数": "Dependencies by ecosystem",
+	"エコシステム":      "Ecosystem",
+	"依存数":         "Dependencies",
+	"割合":          "Share",
+	"該当パッケージ一覧":   "Packages",
+	"パッケージ":       "Package",
+	"バージョン":       "Version",
+	"脆弱性":         "Vulnerabilities",
+	"OSV で調べた依存 %v件 のうち、既知の脆弱性があるものが <strong>%v件</strong> あります。": "Of the %v dependencies checked against OSV, <strong>%v</strong> have known vulnerabilities.",
+	"修正済みのバージョンに更新する":                         "Update to a fixed version",
+	"更新できない場合はアドバイザリで影響範囲と回避策を確認する":           "If you cannot update, check the advisory for impact and workarounds",
+	"Dependabot security updates などで継続的に検知する": "Detect them continuously with Dependabot security updates or similar",
+	"経過": "Age",
+	"Dependabot や Renovate を導入して自動更新": "Automate updates with Dependabot or Renovate",
+	"3年以上のものは優先的に対応":                  "Prioritize those older than 3 years",
+	"セキュリティ脆弱性のスキャンを定期実行":             "Run security vulnerability scans regularly",
+	"ライセンス": "License",
+	"なし":    "None",
+	"ライセンスファイル <strong>%s</strong> があります。":                                             "License file <strong>%s</strong> is present.",
+	"リポジトリのルートに LICENSE / LICENSE.md / LICENSE.txt / COPYING が見つかりません。利用・配布の条件が不明確です。": "No LICENSE / LICENSE.md / LICENSE.txt / COPYING found at the repository root. The terms of use and distribution are unclear.",
+	"choosealicense.com などを参考にライセンスを選び、ルートに LICENSE を追加":                               "Pick a license (e.g. via choosealicense.com) and add a LICENSE at the root",
+	"社内専用なら、その旨を LICENSE や README に明記":                                                 "If the code is internal only, say so in a LICENSE or the README",
+	"TODO コメント":          "TODO comments",
+	"%v件（1000行あたり%.1f件）": "%v (%.1f per 1,000 lines)",
+	"%v ファイル・%v 行を走査し、TODO / FIXME / HACK を含む行が <strong>%v件</strong> ありました。基準: 1000行あたり5件以下が良好 / 10件超で要注意。": "Scanned %v files / %v lines and found <strong>%v</strong> lines containing TODO / FIXME / HACK. Target: 5 or fewer per 1,000 lines is good / more than 10 needs attention.",
+	"API コール節約のため、走査するのはサイズの大きいソースファイルから上限件数までです。":                                                          "To save API calls, only the largest source files are scanned, up to the configured limit.",
+	"件数": "Count",
+	"TODO を棚卸しし、残すものは Issue 番号を添える":                                             "Review TODOs and link an issue number to each one you keep",
+	"リファクタリングの時間をスプリントに確保して計画的に減らす":                                             "Reserve refactoring time in each sprint to pay them down steadily",
+	"22:00〜翌5:00のコミット割合は <strong>%.1f%%</strong> です。基準: 10%%以下が良好 / 30%%以上で警告。": "<strong>%.1f%%</strong> of commits are made between 22:00 and 5:00. Target: 10%% or less is good / 30%% or more is a warning.",
+	"時間帯別コミット分布":    "Commits by hour",
+	"曜日×時間帯のコミット分布": "Commits by weekday and hour",
+	"色が濃いほどコミットが多い時間帯です。深夜・週末の塊は長時間労働の兆候です。": "Darker cells mean more commits. Clusters late at night or on weekends are a sign of overwork.",
+	"スプリントの作業量を見直す":      "Revisit sprint workloads",
+	"人員を増やす or 作業を分散する":  "Add people or spread the work",
+	"日中の会議を減らし、集中タイムを確保": "Cut daytime meetings and protect focus time",
+	"週末労働率": "Weekend work rate",
+	"土日のコミット割合は <strong>%.1f%%</strong> です。基準: 10%%以下が良好 / 20%%以上で警告。曜日は深夜労働率と同じタイムゾーンで判定します。": "<strong>%.1f%%</strong> of commits are made on Saturday or Sunday. Target: 10%% or less is healthy / 20%% or more is a warning. Days are judged in the same time zone as the late-night rate.",
+	"週末にずれ込むリリース日程を見直す":         "Revisit release schedules that spill into weekends",
+	"障害対応の当番をローテーションし、代休を確保する":  "Rotate on-call duty and make sure people get compensatory days off",
+	"週末の作業が個人の習慣か、業務量の問題かを確認する": "Check whether weekend work is a personal habit or a workload problem",
+	"コントリビューターの定着":              "Contributor retention",
+	"%v人 (+%v / -%v)":           "%v people (+%v / -%v)",
+	"期間中にコミットした作成者は <strong>%v人</strong> です。期間を前半・後半に分けると、後半に新しく加わった人が <strong>%v人</strong>、前半のみで後半にコミットのない人が <strong>%v人</strong> です。": "<strong>%v</strong> authors committed in the period. Splitting it into halves, <strong>%v</strong> joined in the second half and <strong>%v</strong> committed only in the first half.",
+	"離れた人が加わった人より多い場合、チームが縮小している可能性があります。作成者はコミットのメールアドレスで識別します。":                                                                       "If more people left than joined, the team may be shrinking. Authors are identified by commit email address.",
+	"新メンバー向けのオンボーディング資料と good first issue を用意する":                                                                                        "Prepare onboarding docs and good first issues for newcomers",
+	"離れたメンバーの担当範囲を棚卸しし、引き継ぎ漏れを防ぐ":                                                                                                       "Inventory what departed members owned so nothing falls through the cracks",
+	"コミュニティヘルス": "Community health",
+	"貢献の手順・脆弱性の報告窓口・Issue / PR テンプレートが揃っているかを示します。外部の開発者が参加しやすいかの目安です。": "Shows whether contribution guidelines, a vulnerability reporting channel and issue / PR templates are in place. A rough guide to how easy it is for outside developers to take part.",
+	"ファイル一覧":  "Files",
+	"状態":      "Status",
+	"リポジトリ規模": "Repository size",
+	"リポジトリの総ファイル数は <strong>%v件</strong>、コントリビューター数は <strong>%v人</strong> です。1人あたりの負担量の目安になります。": "The repository has <strong>%v</strong> files and <strong>%v</strong> contributors. This gives a rough idea of the load per person.",
+	"コントリビューター分布": "Contributor distribution",
+	"コントリビューター数: <strong>%v人</strong>。多いほど属人化リスクが低く、知識が分散されています。": "Contributors: <strong>%v</strong>. More contributors means knowledge is spread out and silo risk is lower.",
+	"コントリビューター別コミット数":             "Commits by contributor",
+	"ペアプログラミングやコードレビューで知識を分散":     "Spread knowledge through pair programming and code review",
+	"ドキュメントを整備して参入障壁を下げる":         "Improve documentation to lower the barrier to entry",
+	"1人が80%以上のコミット → 属人化リスク":      "One person with 80% or more of commits → silo risk",
+	"前期比較トレンド":                    "Trends vs previous period",
+	"ベースライン比較":                    "Baseline comparison",
+	"ベースライン: %v 生成（分析期間 %v 〜 %v）": "Baseline: generated %v (period %v to %v)",
+	"項目":        "Item",
+	"ベースライン":    "Baseline",
+	"今回":        "Current",
+	"差":         "Delta",
+	"判定":        "Status",
+	"メトリクス":     "Metric",
+	"スコアの推移":    "Score history",
+	"期間":        "Period",
+	"グレード":      "Grade",
+	"%v 〜 %v":   "%v to %v",
+	"AI 分析コメント": "AI analysis comments",
+	"まだAI分析は実行されていません。":                      "AI analysis has not been run yet.",
+	"このセクションはAIによる自動分析です。内容は参考情報としてご利用ください。": "This section is generated by AI analysis. Use it for reference only.",
+	"リードタイム (日)":  "Lead time (days)",
+	"日数":          "Days",
+	"レビュー待ち (時間)": "Review wait (hours)",
+	"変更行数":        "Lines changed",
+	"行数":          "Lines",
+	"作成":          "Created",
+	"クローズ":        "Closed",
+	"前期データがありません": "No data for the previous period",
+
+	// コミットメッセージの品質
+	"変更内容を説明していないコミットメッセージが%d件（%.1f%%）あります":                 "%d commit messages (%.1f%%) do not describe the change",
+	"コミットメッセージから変更の意図が読み取れず、履歴を追いにくくなっています":                 "Commit messages do not explain the intent of changes, making history hard to follow",
+	"件名に「何を・なぜ」変えたかを書くルールを決め、commitlint などでメッセージを検査してください。": "Agree on subjects that state what changed and why, and check messages with a tool such as commitlint.",
+	"コミットメッセージの品質": "Commit Message Quality",
+	"変更内容を説明していないコミットメッセージが <strong>%v件</strong>（全体の%.1f%%）です。空・1単語（wip / fix / update など）・WIP で始まるもの・本文のないマージコミットを数えています。": "<strong>%v commit messages</strong> (%.1f%% of all) do not describe the change. Counted: empty, single word (wip / fix / update, etc.), starting with WIP, and merge commits without a body.",
+	"件名に「何を・なぜ」変えたかを1行で書く":             "Write what changed and why in a one-line subject",
+	"作業途中のコミットはマージ前に squash して整理する":    "Squash work-in-progress commits before merging",
+	"commitlint などでメッセージの形式を CI で検査する": "Check the message format in CI with a tool such as commitlint",
+
+	// PRスループット
+	"PRスループット": "PR throughput",
+	"%.1f件/週":  "%.1f/week",
+	"1週間あたり平均 <strong>%.1f件</strong> のPRがマージされています。期間中にマージされたPR: %v件。コミット数と違い、レビューを通って取り込まれた変更の量を表します。": "On average <strong>%.1f PRs</strong> are merged per week. PRs merged in the period: %v. Unlike commit counts, this is the amount of change that made it through review.",
+	"前期比で下がっている場合: レビュー待ちや大きすぎるPRがないか確認":                                                                "If it dropped from the previous period: look for review bottlenecks or oversized PRs",
+	"PRを小さく分割するとスループットが上がりやすい":                                                                          "Splitting PRs into smaller ones tends to raise throughput",
+	"件数だけで比較せず、リードタイム・PRサイズと合わせて見る":                                                                     "Read it together with lead time and PR size rather than comparing counts alone",
+
+	// リリース負債
+	"リリース負債": "Release debt",
+	"最後のリリース（%s）から%d日間、%dコミットが未リリースです":              "Since the last release (%s), %d days have passed and %d commits are unreleased",
+	"マージした変更がリリースされないまま溜まっています":                     "Merged changes are piling up without being released",
+	"未リリース%dコミット、基準%dコミット以下":                        "%d unreleased commits, target ≤%d",
+	"最後のリリース: %s（%s）から %d 日・%d コミット未リリース\n\n":       "Last release: %s (%s); %d days and %d commits unreleased since\n\n",
+	"リリースの単位を小さくして定期的にリリースし、リリース作業を CI で自動化してください。": "Release smaller changes on a regular schedule and automate the release process in CI.",
+	"最後のリリースから":    "Since last release",
+	"%d日 / %dコミット": "%d days / %d commits",
+	"最後のリリースは <strong>%v</strong>（%v）で、それから <strong>%d日</strong>・<strong>%dコミット</strong> が未リリースです。30日超かつ50コミット超で注意、90日超かつ200コミット超で危険と判定します。": "The last release was <strong>%v</strong> (%v); <strong>%d days</strong> and <strong>%d commits</strong> have gone unreleased since. More than 30 days and 50 commits is a warning; more than 90 days and 200 commits is critical.",
+	"分析期間の終わりまでにリリース（デプロイ）が見つかりませんでした。--deploy-source でデプロイの取得元を確認してください。":                                                                   "No release (deploy) was found up to the end of the analysis period. Check where deploys are read from with --deploy-source.",
+	"リリースの間隔を決めて定期的にリリースする":    "Release on a fixed cadence",
+	"リリース作業（タグ付け・リリースノート）を自動化": "Automate release tasks (tagging, release notes)",
+	"未完成の機能はフィーチャーフラグで隠してリリース": "Ship unfinished features behind feature flags",
+
+	// ファイル単位の属人化
+	"ファイルの属人化": "File ownership",
+	"1人の作成者が変更の%d%%以上を占めるファイルがあります":   "Some files have %d%% or more of their changes from a single author",
+	"%d件、1人で変更の%d%%以上":                "%d files, one author made ≥%d%% of changes",
+	"1人しか変更していないファイルがあり、知識がサイロ化しています": "Some files are changed by only one person, so knowledge is siloed",
+	"1人しか変更していないファイルは別のメンバーにも変更やレビューを担当してもらい、知識を共有してください。":                       "Have other members change and review the files only one person touches, so the knowledge is shared.",
+	"1人の作成者が変更の90%%以上を占めるファイルが <strong>%d件</strong> あります（期間内に5回以上変更されたファイルが対象）。": "<strong>%d files</strong> have 90%% or more of their changes from a single author (files changed 5 or more times in the period).",
+	"サイロ化したファイル一覧": "Siloed files",
+	"作成者":          "Author",
+	"サイロ化したファイルの変更を別のメンバーにも担当してもらう":  "Have another member take on changes to siloed files",
+	"レビュアーを固定せず、担当外のメンバーにもレビューを依頼する": "Rotate reviewers and ask members outside the area to review",
+
+	// SARIF
+	"期間内に%d回変更されています":          "Changed %d times in the period",
+	"%dKBの巨大ファイルです":            "Large file (%dKB)",
+	"%s が変更の%d%%を占めています（%d回中）": "%s made %d%% of the changes (%d in total)",
+
+	// ファイル一覧の切り詰め
+	"ファイル一覧の一部しか取得できなかったため、ファイル数・巨大ファイルなどは実際より少ない可能性があります":     "Only part of the file list could be fetched, so file counts, large files and similar may be undercounted",
+	"ファイル一覧の一部しか取得できなかったため、ファイル数・巨大ファイルなどは実際より少ない可能性があります\n\n": "Only part of the file list could be fetched, so file counts, large files and similar may be undercounted\n\n",
+
+	// 休眠リポジトリ
+	"休眠リポジトリ": "Inactive repository",
+	"期間内にコミット・マージされたPR・リリースがありません（最終活動: %s、%d日前）": "No commits, merged PRs or releases in the period (last activity: %s, %d days ago)",
+	"活動が止まっており、メンテナンスされていない可能性があります":              "Activity has stopped and the repository may be unmaintained",
+	"最終活動から%d日、期間%d日": "%d days since last activity, %d-day period",
+	"今後使う予定がなければアーカイブし、使い続けるならメンテナンスの担当者を決めてください。": "Archive the repository if it is no longer needed; if it stays in use, assign someone to maintain it.",
+
+	// 共同作成（Co-authored-by）
+	"共同作成のコミット": "Co-authored commits",
+	"コミットメッセージに <code>Co-authored-by:</code> のあるコミットが <strong>%v件</strong>（全体の%.1f%%）です。": "<strong>%v commits</strong> (%.1f%% of all) have a <code>Co-authored-by:</code> trailer in their message.",
+	"ペアプログラミング・モブプログラミングで知識が共有されている目安です。多いほど、コミットの作成者が偏っていても担当者の離脱による影響は小さくなります。":         "This indicates knowledge shared through pair or mob programming. The higher it is, the less a departure hurts even when commit authorship is concentrated.",
+	"難しい変更や担当者が1人の領域はペアで作業する":               "Pair on difficult changes and on areas only one person owns",
+	"ペアで作業したコミットには Co-authored-by を付けて記録する": "Record paired work with a Co-authored-by trailer on the commit",
+	"（共同作成のコミットが%.0f%%あるため重大度を下げています）":      " (severity lowered because %.0f%% of commits are co-authored)",
+	"変更集中（Medium）": "Change concentration (Medium)",
+	"回":            " changes",
+	"変更集中（High）":   "Change concentration (High)",
+	"属人化（1人のコミット割合）":           "Ownership (one person's share of commits)",
+	"ファイル単位の属人化（1人の変更割合）":      "File ownership (one author's share of changes)",
+	"週末コミット率":                  "Weekend commit rate",
+	"巨大ファイル（Medium）":           "Large file (Medium)",
+	"巨大ファイル（High）":             "Large file (High)",
+	"古い依存（Medium）":             "Outdated dependency (Medium)",
+	"か月":                       " mo",
+	"古い依存（High）":               "Outdated dependency (High)",
+	"TODO コメントの密度（Medium）":     "TODO comment density (Medium)",
+	"件/1000行":                  "/1000 lines",
+	"TODO コメントの密度（High）":       "TODO comment density (High)",
+	"直接プッシュの割合（Medium）":        "Direct push rate (Medium)",
+	"直接プッシュの割合（High）":          "Direct push rate (High)",
+	"滞留PRとみなす経過日数":             "Days open before a PR counts as stale",
+	"滞留PRの件数（Medium）":          "Stale PRs (Medium)",
+	"件":                        " PRs",
+	"滞留PRの件数（High）":            "Stale PRs (High)",
+	"放棄PRの割合（Medium）":          "PR abandonment rate (Medium)",
+	"放棄PRの割合（High）":            "PR abandonment rate (High)",
+	"PRサイズ":                    "PR size",
+	"バグ修正割合":                   "Bug fix ratio",
+	"レビューカバレッジ（Medium）":        "Review coverage (Medium)",
+	"レビューカバレッジ（High）":          "Review coverage (High)",
+	"セルフマージの割合（Medium）":        "Self-merge rate (Medium)",
+	"セルフマージの割合（High）":          "Self-merge rate (High)",
+	"低品質なコミットメッセージの割合（Medium）": "Low-quality commit message rate (Medium)",
+	"低品質なコミットメッセージの割合（High）":   "Low-quality commit message rate (High)",
+	"平均復旧時間（MTTR）":             "Mean time to recovery (MTTR)",
+	"機能追加の割合":                  "Feature work ratio",
+	"リリース負債の経過日数（Medium）":      "Days since last release (Medium)",
+	"リリース負債の経過日数（High）":        "Days since last release (High)",
+	"休眠リポジトリ（High）":            "Inactive repository (High)",
+	"リスク1件の減点（High）":           "Penalty per risk (High)",
+	"点":                        " pts",
+	"リスク1件の減点（Medium）":         "Penalty per risk (Medium)",
+	"リスク1件の減点（Low）":            "Penalty per risk (Low)",
+	"属人化を緩和する共同作成のコミット率":       "Co-authored commit rate that mitigates ownership risk",
+	"判定基準（使用した閾値）":             "Methodology (thresholds used)",
+	"設定で変更: %d件":               "Changed in config: %d",
+	"閾値":                       "Threshold",
+	"デフォルト":                    "Default",
+	"テストファイル不足（推定）":            "Few test files (estimated)",
+	"ソースコードに対してテストが少なく、変更による不具合を検出しにくい状態です":   "There are few tests for the amount of source code, so regressions are hard to catch",
+	"ソース1件あたりテスト%.2f件、基準%.2f件以上":              "%.2f test files per source file, target %.2f or more",
+	"ソースファイル%d件に対してテストファイルが%d件です（ファイル名からの推定）": "%d source files but only %d test files (estimated from file names)",
+	"ソースファイルに対するテストファイルの比率（Medium）":           "Test files per source file (Medium)",
+	"ソースファイルに対するテストファイルの比率（High）":             "Test files per source file (High)",
+	"変更の多い箇所からテストを書き足し、新しいコードにはテストを付けるルールを決めてください。実際のカバレッジは CI のカバレッジ計測で確認してください。": "Add tests starting with frequently changed code, and make tests a requirement for new code. Measure actual coverage in CI.",
+	"テストファイルの比率": "Test file ratio",
+	"ソースファイル <strong>%v件</strong> に対してテストファイルが <strong>%v件</strong>（ソースファイル1件あたり%.2f件）です。基準: 0.1件以上（ソースファイルが20件未満なら判定しない）。": "<strong>%v</strong> source files and <strong>%v</strong> test files (%.2f test files per source file). Target: 0.1 or more (not evaluated with fewer than 20 source files).",
+	"ファイル名の慣習（_test.go・.spec.ts・test_*.py・src/test/ など）で分類した推定値で、実際のカバレッジではありません。":                                          "This is an estimate based on file name conventions (_test.go, .spec.ts, test_*.py, src/test/, etc.), not actual code coverage.",
+	"変更の多いファイルからテストを書き足す":              "Add tests to frequently changed files first",
+	"新しいコードにはテストを付けることをレビューで確認する":      "Check in review that new code comes with tests",
+	"CI でカバレッジを計測し、実際の網羅率を確認する":        "Measure code coverage in CI to see the actual numbers",
+	"1人のコントリビューターが期間内のコミットの大部分を占めています": "A single contributor accounts for most of the commits in the period",
+	"コミットサイズ超過": "Large commits",
+	"1つのコミットで多くのファイルを変更しており、レビューや不具合の原因の切り分けが難しくなっています": "Commits touch many files, making them hard to review and to bisect when tracking down bugs",
+	"1コミットあたり平均%.1fファイルを変更しています":                        "Commits change %.1f files on average",
+	"平均%.1fファイル/コミット、基準%.1fファイル以下":                      "%.1f files per commit on average, target %.1f or fewer",
+	"コミットあたりの平均変更ファイル数（Medium）":                         "Average files changed per commit (Medium)",
+	"コミットあたりの平均変更ファイル数（High）":                           "Average files changed per commit (High)",
+	"ファイル/コミット": " files/commit",
+	"1つのコミットを1つの論理的な変更に絞り、フォーマットやリネームなどの機械的な一括変更は別のコミットに分けてください。": "Keep each commit to one logical change, and put mechanical bulk changes such as formatting or renames in separate commits.",
+	"コミットあたりの変更ファイル数": "Files changed per commit",
+	"変更ファイルを取得した <strong>%v件</strong> のコミットで、1コミットあたり平均 <strong>%.1fファイル</strong> を変更しています。基準: 10ファイル以下（集計したコミットが20件未満なら判定しない）。": "Across <strong>%v</strong> commits with file details, each commit changes <strong>%.1f files</strong> on average. Target: 10 or fewer (not evaluated with fewer than 20 commits).",
+	"多くのファイルにまたがるコミットはレビューしにくく、git bisect で不具合の原因を絞り込みにくくなります。マージコミットは除いています。":                                                   "Commits spanning many files are hard to review and make it harder to narrow down a bug with git bisect. Merge commits are excluded.",
+	"1つのコミットには1つの論理的な変更だけを含める":           "Include only one logical change per commit",
+	"フォーマット・リネームなどの一括変更は機能の変更と別のコミットにする": "Commit bulk changes such as formatting or renames separately from functional changes",
+	"git add -p で変更を分けてコミットする":           "Use git add -p to split changes into separate commits",
+	"コミット":           "Commits",
+	"コミットの変更ファイル":    "Commit file changes",
+	"クローズ済みPR":       "Closed PRs",
+	"オープンPR":         "Open PRs",
+	"PR詳細（サイズ・レビュー）": "PR details (size, reviews)",
+	"Issue":          "Issues",
+	"障害Issueのイベント":   "Incident issue events",
+	"オープンIssue":      "Open issues",
+	"TODO コメントの走査":   "TODO comment scan",
+	"依存":             "Dependencies",
+	"依存の脆弱性":         "Dependency vulnerabilities",
+	"デプロイ":           "Deployments",
+	"前期のコミット":        "Previous period commits",
+	"前期のIssue":       "Previous period issues",
+	"権限不足などで読み取れませんでした":              "Could not be read (e.g. insufficient permissions)",
+	"クイックモードのため取得していません":             "Not fetched in quick mode",
+	"取得に失敗しました: %v":                  "Fetch failed: %v",
+	"件数の上限のため新しいものから一部だけ取得しています":     "Only the most recent items were fetched because of the item limit",
+	"一覧がページ数の上限で打ち切られ、一部しか取得できていません": "The list hit the page limit, so only part of it was fetched",
+	"%d件は取得に失敗しました":                  "%d failed to fetch",
+	"データの取得状況":                       "Data completeness",
+	"欠けているデータ: %d種類":                 "Incomplete: %d data sources",
+	"すべて取得済み":                        "All data fetched",
+	"データ":                            "Data",
+	"状況":                             "Status",
+	"**欠けているデータ（%d種類）**: これらを使うスコアは割り引いて読んでください\n\n": "**Incomplete data (%d sources)**: read the scores that depend on it with caution\n\n",
+	"デプロイ頻度のDORAレーティング（Elite）":                       "DORA rating for deploy frequency (Elite)",
+	"デプロイ頻度のDORAレーティング（High）":                        "DORA rating for deploy frequency (High)",
+	"デプロイ頻度のDORAレーティング（Medium）":                      "DORA rating for deploy frequency (Medium)",
+	"変更失敗率のDORAレーティング（Elite）":                        "DORA rating for change failure rate (Elite)",
+	"変更失敗率のDORAレーティング（High）":                         "DORA rating for change failure rate (High)",
+	"変更失敗率のDORAレーティング（Medium）":                       "DORA rating for change failure rate (Medium)",
+	"MTTRのDORAレーティング（Elite）":                         "DORA rating for MTTR (Elite)",
+	"MTTRのDORAレーティング（High）":                          "DORA rating for MTTR (High)",
+	"MTTRのDORAレーティング（Medium）":                        "DORA rating for MTTR (Medium)",
+	"期間中のデプロイ頻度は <strong>月%.1f回</strong> です。DORAレーティング: <strong>%v</strong>（設定した境界: Elite: 月%v回以上 / High: 月%v回以上 / Medium: 月%v回以上 / Low: それ未満）": "Deploy frequency in the period is <strong>%.1f per month</strong>. DORA rating: <strong>%v</strong> (configured bands: Elite: %v or more per month / High: %v or more / Medium: %v or more / Low: less)",
+	"障害からの平均復旧時間は <strong>%.1f時間</strong> です。DORAレーティング: <strong>%v</strong>（設定した境界: Elite: %vh未満 / High: %vh未満 / Medium: %vh未満 / Low: それ以上）":   "Mean time to recovery from incidents is <strong>%.1f hours</strong>. DORA rating: <strong>%v</strong> (configured bands: Elite: under %vh / High: under %vh / Medium: under %vh / Low: longer)",
+	"変更失敗率は <strong>%.1f%%</strong> です。DORAレーティング: <strong>%v</strong>（設定した境界: Elite: %v%%以下 / High: %v%%以下 / Medium: %v%%以下 / Low: それを超える）":    "Change failure rate is <strong>%.1f%%</strong>. DORA rating: <strong>%v</strong> (configured bands: Elite: %v%% or less / High: %v%% or less / Medium: %v%% or less / Low: higher)",
+}
@@ -0,0 +1,68 @@
+// Package i18n はレポートなどユーザー向け文字列の多言語化を提供する。
+//
+// メッセージのキーは日本語の原文そのもの（gettext 方式）。
+// 日本語はキーをそのまま使い、それ以外の言語はカタログで訳を引く。
+// 原文がコード上に残るので読みやすく、既定の日本語出力は訳の有無に左右されない。
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Lang はレポートの出力言語。
+type Lang string
+
+const (
+	// Japanese は日本語（既定）。
+	Japanese Lang = "ja"
+	// English は英語。
+	English Lang = "en"
+)
+
+// Default は既定の出力言語。
+const Default = Japanese
+
+// catalogs は言語ごとのメッセージカタログ（日本語の原文 → 訳）。
+// 日本語は原文をそのまま使うため持たない。
+var catalogs = map[Lang]map[string]string{
+	English: english,
+}
+
+// weekdays は言語ごとの曜日の短縮名（日曜始まり）。
+var weekdays = map[Lang][7]string{
+	Japanese: {"日", "月", "火", "水", "木", "金", "土"},
+	English:  {"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+}
+
+// Parse は --lang の値を Lang に変換する。大文字小文字は区別しない。
+func Parse(s string) (Lang, error) {
+	switch l := Lang(strings.ToLower(strings.TrimSpace(s))); l {
+	case Japanese, English:
+		return l, nil
+	default:
+		return "", fmt.Errorf("unsupported language: %q (want ja or en)", s)
+	}
+}
+
+// T はメッセージを翻訳する。args があれば訳を書式として fmt.Sprintf で埋め込む。
+// 訳がなければ原文（日本語）を使う。ゼロ値の Lang は日本語として扱う。
+func (l Lang) T(msg string, args ...any) string {
+	if tr, ok := catalogs[l][msg]; ok {
+		msg = tr
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Weekday は曜日の短縮名を返す（例: "土", "Sat"）。
+func (l Lang) Weekday(d time.Weekday) string {
+	names, ok := weekdays[l]
+	if !ok {
+		names = weekdays[Default]
+	}
+	return names[d]
+}
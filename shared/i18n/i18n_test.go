@@ -0,0 +1,177 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Lang
+		wantErr bool
+	}{
+		{"ja", Japanese, false},
+		{"en", English, false},
+		{"EN", English, false},
+		{" en ", English, false},
+		{"fr", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestT(t *testing.T) {
+	tests := []struct {
+		name string
+		lang Lang
+		msg  string
+		args []any
+		want string
+	}{
+		{"japanese returns source", Japanese, "開発速度", nil, "開発速度"},
+		{"zero value is japanese", "", "開発速度", nil, "開発速度"},
+		{"english translation", English, "開発速度", nil, "Velocity"},
+		{"english with args", English, "PRリードタイムが平均%.1f日です", []any{3.5}, "Average PR lead time is 3.5 days"},
+		{"japanese with args", Japanese, "PRリードタイムが平均%.1f日です", []any{3.5}, "PRリードタイムが平均3.5日です"},
+		{"missing translation falls back", English, "未登録のメッセージ", nil, "未登録のメッセージ"},
+		{"no args keeps percent", English, "100%以上を維持（消化が追いつく状態）", nil, "Stay at 100% or more (keeping up with incoming issues)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.lang.T(tt.msg, tt.args...); got != tt.want {
+				t.Errorf("T(%q) = %q, want %q", tt.msg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeekday(t *testing.T) {
+	tests := []struct {
+		lang Lang
+		day  time.Weekday
+		want string
+	}{
+		{Japanese, time.Saturday, "土"},
+		{English, time.Saturday, "Sat"},
+		{English, time.Sunday, "Sun"},
+		{"", time.Monday, "月"},
+	}
+	for _, tt := range tests {
+		if got := tt.lang.Weekday(tt.day); got != tt.want {
+			t.Errorf("%q.Weekday(%v) = %q, want %q", tt.lang, tt.day, got, tt.want)
+		}
+	}
+}
+
+// japanese は日本語（ひらがな・カタカナ・漢字）を含むかを判定する。
+var japanese = regexp.MustCompile(`[\p{Hiragana}\p{Katakana}\p{Han}]`)
+
+// formatVerb は書式の verb。%% は verbs で取り除いてから探す。
+var formatVerb = regexp.MustCompile(`%[-+# 0]*[0-9]*(?:\.[0-9]+)?[a-z]`)
+
+// verbs は書式の verb を順に返す。
+func verbs(format string) []string {
+	return formatVerb.FindAllString(strings.ReplaceAll(format, "%%", ""), -1)
+}
+
+// templateMessage はテンプレート中の {{t "..."}} / {{th "..."}} のメッセージ。
+var templateMessage = regexp.MustCompile(`\{\{th? ("(?:[^"\\]|\\.)*")`)
+
+// TestEnglishCatalog は日本語のメッセージがすべて英語カタログにあることを確認する。
+// 対象は domain / features の Go コード中の文字列リテラル（コメント・テストを除く）と、
+// レポートテンプレートの t / th に渡す文言。
+func TestEnglishCatalog(t *testing.T) {
+	root := filepath.Join("..", "..")
+	var messages []string
+	add := func(msg string) {
+		if japanese.MatchString(msg) && !slices.Contains(messages, msg) {
+			messages = append(messages, msg)
+		}
+	}
+
+	for _, dir := range []string{"domain", "features"} {
+		err := filepath.WalkDir(filepath.Join(root, dir), func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+				return err
+			}
+			f, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+			if err != nil {
+				return err
+			}
+			ast.Inspect(f, func(n ast.Node) bool {
+				if lit, ok := n.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					if s, err := strconv.Unquote(lit.Value); err == nil {
+						add(s)
+					}
+				}
+				return true
+			})
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	templates, err := filepath.Glob(filepath.Join(root, "features", "report", "*.html"))
+	if err != nil || len(templates) == 0 {
+		t.Fatalf("no templates found: %v", err)
+	}
+	comment := regexp.MustCompile(`(?s)<!--.*?-->`)
+	action := regexp.MustCompile(`(?s)\{\{.*?\}\}`)
+	for _, path := range templates {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range templateMessage.FindAllStringSubmatch(string(b), -1) {
+			s, err := strconv.Unquote(m[1])
+			if err != nil {
+				t.Fatalf("%s: invalid message %s", path, m[1])
+			}
+			add(s)
+		}
+		// t / th を通していない日本語が残っていないこと
+		rest := action.ReplaceAllString(comment.ReplaceAllString(string(b), ""), "")
+		for i, line := range strings.Split(rest, "\n") {
+			if japanese.MatchString(line) {
+				t.Errorf("%s: untranslated text: %s (line %d after stripping actions)", filepath.Base(path), strings.TrimSpace(line), i+1)
+			}
+		}
+	}
+
+	if len(messages) == 0 {
+		t.Fatal("no messages found")
+	}
+	for _, msg := range messages {
+		tr, ok := english[msg]
+		if !ok {
+			t.Errorf("missing English translation: %q", msg)
+			continue
+		}
+		if want, got := verbs(msg), verbs(tr); len(want) > 0 && !slices.Equal(want, got) {
+			t.Errorf("format verbs differ for %q: %v vs %v", msg, want, got)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+// Package parallel は同時に動かす数に上限を設けた並列処理を提供する。
+package parallel
+
+import (
+	"context"
+	"sync"
+)
+
+// ForEach は fn(0) から fn(count-1) を最大 n 個の goroutine で並行に呼び、すべて終わるまで待つ。
+//
+// fn は添字ごとに1回だけ呼ばれる。各呼び出しが添字に対応する別々の要素にしか書き込まなければ、
+// 結果を受け取るスライスにロックはいらない。
+// ctx がキャンセルされたら未着手の添字では fn を呼ばない（実行中の fn は ctx で打ち切らせる）。
+func ForEach(ctx context.Context, n, count int, fn func(i int)) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(count, max(n, 1)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := range count {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
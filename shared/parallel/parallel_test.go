@@ -0,0 +1,63 @@
+package parallel
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestForEach(t *testing.T) {
+	tests := []struct {
+		name  string
+		n     int
+		count int
+	}{
+		{"more items than workers", 3, 20},
+		{"more workers than items", 10, 2},
+		{"no items", 4, 0},
+		{"zero workers runs one at a time", 0, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inFlight, maxInFlight atomic.Int32
+			calls := make([]int, tt.count)
+			ForEach(context.Background(), tt.n, tt.count, func(i int) {
+				cur := inFlight.Add(1)
+				defer inFlight.Add(-1)
+				for {
+					m := maxInFlight.Load()
+					if cur <= m || maxInFlight.CompareAndSwap(m, cur) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				calls[i]++
+			})
+
+			for i, c := range calls {
+				if c != 1 {
+					t.Errorf("fn(%d) called %d times, want 1", i, c)
+				}
+			}
+			if got, limit := maxInFlight.Load(), int32(max(tt.n, 1)); got > limit {
+				t.Errorf("max in-flight = %d, want <= %d", got, limit)
+			}
+		})
+	}
+}
+
+func TestForEach_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	ForEach(ctx, 1, 100, func(i int) {
+		calls.Add(1)
+		if i == 2 {
+			cancel()
+		}
+	})
+	// キャンセル時点で渡し済みの添字までで止まる
+	if got := calls.Load(); got < 3 || got > 4 {
+		t.Errorf("fn called %d times, want to stop shortly after cancel", got)
+	}
+}